@@ -17,7 +17,11 @@ import (
 )
 
 // NewestSupportedRevision is the newest tosca.Revision currently supported by the CT specification.
-const NewestSupportedRevision = tosca.R15_Osaka
+// The experimental revision follows the newest fully supported revision.
+const NewestSupportedRevision = tosca.R98_Experimental
+
+// NewestFullySupportedRevision is the newest regular tosca.Revision supported
+// by the CT specification.
 const NewestFullySupportedRevision = tosca.R15_Osaka
 
 const R99_UnknownNextRevision = tosca.Revision(99)
@@ -46,8 +50,10 @@ func GetForkBlock(revision tosca.Revision) uint64 {
 		return 6000
 	case tosca.R15_Osaka:
 		return 7000
-	default: // R99_UnknownNextRevision:
+	case tosca.R98_Experimental:
 		return 8000
+	default: // R99_UnknownNextRevision:
+		return 9000
 	}
 }
 
@@ -74,22 +80,51 @@ func GetForkTime(revision tosca.Revision) uint64 {
 		return 6000
 	case tosca.R15_Osaka:
 		return 7000
-	default:
+	case tosca.R98_Experimental:
 		return 8000
+	default:
+		return 9000
 	}
 }
 
 // GetRevisionForBlock returns the revision that is considered to be enabled for a given block number.
 func GetRevisionForBlock(block uint64) tosca.Revision {
-	for rev := MinRevision; rev <= NewestSupportedRevision+1; rev++ {
-		forkBlock := GetForkBlock(rev)
+	for rev := MinRevision; rev <= NewestSupportedRevision; rev = GetNextRevision(rev) {
+		forkBlock := GetForkBlock(GetNextRevision(rev))
 		if block < forkBlock {
-			return rev - 1
+			return rev
 		}
 	}
 	return R99_UnknownNextRevision
 }
 
+// GetNextRevision returns the revision following the given revision in the
+// sequence of revisions covered by the CT specification. The experimental
+// revision follows the newest fully supported revision and is followed by
+// R99_UnknownNextRevision, which is its own successor.
+func GetNextRevision(revision tosca.Revision) tosca.Revision {
+	switch {
+	case revision == NewestFullySupportedRevision:
+		return tosca.R98_Experimental
+	case revision >= NewestSupportedRevision:
+		return R99_UnknownNextRevision
+	}
+	return revision + 1
+}
+
+// GetPreviousRevision returns the revision preceding the given revision in
+// the sequence of revisions covered by the CT specification. It is the
+// inverse of GetNextRevision for all revisions following MinRevision.
+func GetPreviousRevision(revision tosca.Revision) tosca.Revision {
+	switch {
+	case revision == R99_UnknownNextRevision:
+		return NewestSupportedRevision
+	case revision == tosca.R98_Experimental:
+		return NewestFullySupportedRevision
+	}
+	return revision - 1
+}
+
 // GetBlockRangeLengthFor returns the number of block numbers between the given revision and the following
 // in case of an Unknown revision, math.MaxUint64 is returned.
 func GetBlockRangeLengthFor(revision tosca.Revision) (uint64, error) {
@@ -99,7 +134,7 @@ func GetBlockRangeLengthFor(revision tosca.Revision) (uint64, error) {
 	// if it's the last supported revision, the blockNumber range has no limit.
 	// if it's not, we want to limit this range to the first block number of next revision.
 	if revision <= NewestSupportedRevision {
-		nextRevisionNumber := GetForkBlock(revision + 1)
+		nextRevisionNumber := GetForkBlock(GetNextRevision(revision))
 
 		// since we know both numbers are positive, and nextRevisionNumber is bigger,
 		// we can safely convert them to uint64
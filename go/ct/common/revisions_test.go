@@ -12,6 +12,7 @@ package common
 
 import (
 	"math"
+	"slices"
 	"testing"
 
 	"github.com/0xsoniclabs/tosca/go/tosca"
@@ -22,15 +23,16 @@ func TestRevisions_RangeLength(t *testing.T) {
 		revision    tosca.Revision
 		rangeLength uint64
 	}{
-		"Istanbul":    {tosca.R07_Istanbul, 1000},
-		"Berlin":      {tosca.R09_Berlin, 1000},
-		"London":      {tosca.R10_London, 1000},
-		"Paris":       {tosca.R11_Paris, 1000},
-		"Shanghai":    {tosca.R12_Shanghai, 1000},
-		"Cancun":      {tosca.R13_Cancun, 1000},
-		"Prague":      {tosca.R14_Prague, 1000},
-		"Osaka":       {tosca.R15_Osaka, 1000},
-		"UnknownNext": {R99_UnknownNextRevision, math.MaxUint64},
+		"Istanbul":     {tosca.R07_Istanbul, 1000},
+		"Berlin":       {tosca.R09_Berlin, 1000},
+		"London":       {tosca.R10_London, 1000},
+		"Paris":        {tosca.R11_Paris, 1000},
+		"Shanghai":     {tosca.R12_Shanghai, 1000},
+		"Cancun":       {tosca.R13_Cancun, 1000},
+		"Prague":       {tosca.R14_Prague, 1000},
+		"Osaka":        {tosca.R15_Osaka, 1000},
+		"Experimental": {tosca.R98_Experimental, 1000},
+		"UnknownNext":  {R99_UnknownNextRevision, math.MaxUint64},
 	}
 
 	for name, test := range tests {
@@ -51,15 +53,16 @@ func TestRevisions_GetForkBlock(t *testing.T) {
 		revision  tosca.Revision
 		forkBlock uint64
 	}{
-		"Istanbul":    {tosca.R07_Istanbul, 0},
-		"Berlin":      {tosca.R09_Berlin, 1000},
-		"London":      {tosca.R10_London, 2000},
-		"Paris":       {tosca.R11_Paris, 3000},
-		"Shanghai":    {tosca.R12_Shanghai, 4000},
-		"Cancun":      {tosca.R13_Cancun, 5000},
-		"Prague":      {tosca.R14_Prague, 6000},
-		"Osaka":       {tosca.R15_Osaka, 7000},
-		"UnknownNext": {R99_UnknownNextRevision, 8000},
+		"Istanbul":     {tosca.R07_Istanbul, 0},
+		"Berlin":       {tosca.R09_Berlin, 1000},
+		"London":       {tosca.R10_London, 2000},
+		"Paris":        {tosca.R11_Paris, 3000},
+		"Shanghai":     {tosca.R12_Shanghai, 4000},
+		"Cancun":       {tosca.R13_Cancun, 5000},
+		"Prague":       {tosca.R14_Prague, 6000},
+		"Osaka":        {tosca.R15_Osaka, 7000},
+		"Experimental": {tosca.R98_Experimental, 8000},
+		"UnknownNext":  {R99_UnknownNextRevision, 9000},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -75,10 +78,10 @@ func TestRevision_GetRevisionForBlock(t *testing.T) {
 
 	revisions := map[tosca.Revision]uint64{}
 
-	for i := tosca.R07_Istanbul; i <= NewestSupportedRevision; i++ {
+	for i := tosca.R07_Istanbul; i <= NewestSupportedRevision; i = GetNextRevision(i) {
 		revisions[i] = GetForkBlock(i)
 	}
-	revisions[R99_UnknownNextRevision] = 8000
+	revisions[R99_UnknownNextRevision] = 9000
 
 	for revision, revisionBlockNumber := range revisions {
 		t.Run(revision.String(), func(t *testing.T) {
@@ -101,7 +104,8 @@ func TestRevisions_GetForkTime(t *testing.T) {
 		tosca.R13_Cancun:        5000,
 		tosca.R14_Prague:        6000,
 		tosca.R15_Osaka:         7000,
-		R99_UnknownNextRevision: 8000,
+		tosca.R98_Experimental:  8000,
+		R99_UnknownNextRevision: 9000,
 	}
 
 	for revision, forkTime := range tests {
@@ -113,3 +117,26 @@ func TestRevisions_GetForkTime(t *testing.T) {
 		})
 	}
 }
+
+func TestRevisions_NextAndPreviousRevisionsAreInverse(t *testing.T) {
+	want := []tosca.Revision{}
+	for i := tosca.R07_Istanbul; i <= NewestFullySupportedRevision; i++ {
+		want = append(want, i)
+	}
+	want = append(want, tosca.R98_Experimental, R99_UnknownNextRevision)
+
+	got := []tosca.Revision{MinRevision}
+	for cur := MinRevision; cur != R99_UnknownNextRevision; {
+		cur = GetNextRevision(cur)
+		got = append(got, cur)
+	}
+	if !slices.Equal(want, got) {
+		t.Fatalf("unexpected revision sequence, wanted %v, got %v", want, got)
+	}
+
+	for i := 1; i < len(want); i++ {
+		if got := GetPreviousRevision(want[i]); got != want[i-1] {
+			t.Errorf("unexpected predecessor of %v, wanted %v, got %v", want[i], want[i-1], got)
+		}
+	}
+}
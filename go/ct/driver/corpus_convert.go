@@ -0,0 +1,158 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/0xsoniclabs/tosca/go/ct/st"
+	"github.com/urfave/cli/v2"
+)
+
+var CorpusConvertCmd = cli.Command{
+	Action: doCorpusConvert,
+	Name:   "corpus-convert",
+	Usage:  "Convert a corpus of CT states between JSON files and a binary stream",
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "input",
+			Usage: "convert given state file or stream, or all files in the given directory (recursively)",
+			Value: cli.NewStringSlice("./regression_inputs"),
+		},
+		&cli.StringFlag{
+			Name:     "output",
+			Usage:    "the stream file to write for the binary format, the directory to write to for the JSON format",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "the format to convert to, one of binary or json",
+			Value: "binary",
+		},
+	},
+}
+
+func doCorpusConvert(context *cli.Context) error {
+	inputs, err := enumerateInputs(context.StringSlice("input"))
+	if err != nil {
+		return err
+	}
+
+	output := context.String("output")
+	switch format := context.String("format"); format {
+	case "binary":
+		return convertToStream(inputs, output)
+	case "json":
+		return convertToJSON(inputs, output)
+	default:
+		return fmt.Errorf("invalid format %q, use one of: binary, json", format)
+	}
+}
+
+// convertToStream writes all states of the given input files into a single
+// binary stream.
+func convertToStream(inputs []string, output string) error {
+	stream, err := createStateStream(output)
+	if err != nil {
+		return err
+	}
+	for _, input := range inputs {
+		if err := st.ImportStates(input, stream.Write); err != nil {
+			stream.Close()
+			return fmt.Errorf("failed to convert %v: %w", input, err)
+		}
+	}
+	if err := stream.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d states of %d files to %v\n", stream.count, len(inputs), output)
+	return nil
+}
+
+// convertToJSON writes each state of the given input files to its own JSON
+// file in the output directory. Files are named after their input; states
+// of streams holding more than one state are numbered.
+func convertToJSON(inputs []string, output string) error {
+	if err := os.MkdirAll(output, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	count := 0
+	for _, input := range inputs {
+		var states []*st.State
+		err := st.ImportStates(input, func(state *st.State) error {
+			states = append(states, state)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to convert %v: %w", input, err)
+		}
+
+		name := strings.TrimSuffix(filepath.Base(input), filepath.Ext(input))
+		for i, state := range states {
+			fileName := name + ".json"
+			if len(states) > 1 {
+				fileName = fmt.Sprintf("%s_%06d.json", name, i)
+			}
+			if err := st.ExportStateJSON(state, filepath.Join(output, fileName)); err != nil {
+				return fmt.Errorf("failed to write state: %w", err)
+			}
+		}
+		count += len(states)
+	}
+	fmt.Printf("Wrote %d states of %d files to %v\n", count, len(inputs), output)
+	return nil
+}
+
+// stateStream is a binary stream of states written to a file.
+type stateStream struct {
+	file   *os.File
+	writer *st.StateWriter
+	count  int // < the number of states written so far
+}
+
+// createStateStream creates the given file, including missing parent
+// directories, and starts a stream of states in it.
+func createStateStream(path string) (*stateStream, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	writer, err := st.NewStateWriter(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &stateStream{file: file, writer: writer}, nil
+}
+
+// Write appends the given state to the stream.
+func (s *stateStream) Write(state *st.State) error {
+	if err := s.writer.Write(state); err != nil {
+		return err
+	}
+	s.count++
+	return nil
+}
+
+// Close flushes the stream and closes the underlying file.
+func (s *stateStream) Close() error {
+	if err := s.writer.Flush(); err != nil {
+		s.file.Close()
+		return fmt.Errorf("failed to write stream: %w", err)
+	}
+	return s.file.Close()
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	cliUtils "github.com/0xsoniclabs/tosca/go/ct/driver/cli"
+	"github.com/0xsoniclabs/tosca/go/ct/spc"
+	"github.com/urfave/cli/v2"
+)
+
+var DocCmd = cli.Command{
+	Action: doDoc,
+	Name:   "doc",
+	Usage:  "Render a human-readable reference of the rules of the specification",
+	Flags: []cli.Flag{
+		cliUtils.FilterFlag,
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "the format of the reference, either markdown or html",
+			Value: "markdown",
+		},
+		&cli.StringFlag{
+			Name:  "output",
+			Usage: "the file to write the reference to, stdout if not set",
+		},
+	},
+}
+
+func doDoc(context *cli.Context) error {
+	filter, err := cliUtils.FilterFlag.Fetch(context)
+	if err != nil {
+		return err
+	}
+
+	reference := spc.NewReference(spc.FilterRules(spc.Spec.GetRules(), filter))
+	var write func(io.Writer) error
+	switch format := context.String("format"); format {
+	case "markdown":
+		write = reference.WriteMarkdown
+	case "html":
+		write = reference.WriteHTML
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+
+	var writer io.Writer = os.Stdout
+	if path := context.String("output"); path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		writer = file
+	}
+	return write(writer)
+}
@@ -24,13 +24,24 @@ func main() {
 		Copyright: "(c) 2023 Fantom Foundation",
 		Flags:     []cli.Flag{},
 		Commands: []*cli.Command{
+			&CorpusConvertCmd,
+			&DocCmd,
 			&GeneratorInfoCmd,
 			&ListCmd,
+			&PrecompilesCmd,
+			&ProcessorsCmd,
 			&ProbeCmd,
 			&RegressionsCmd,
+			&RevisionDiffCmd,
+			&RuleCoverageCmd,
 			&RunCmd,
+			&SpecCheckCmd,
+			&SpecDiffCmd,
+			&SpecExportCmd,
+			&StateTestExportCmd,
 			&StatsCmd,
 			&TestCmd,
+			&TraceImportCmd,
 		},
 	}
 
@@ -0,0 +1,107 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package main
+
+import (
+	"fmt"
+	"math"
+
+	cliUtils "github.com/0xsoniclabs/tosca/go/ct/driver/cli"
+	"github.com/0xsoniclabs/tosca/go/ct/precompiles"
+	"github.com/0xsoniclabs/tosca/go/ct/rlz"
+	"github.com/0xsoniclabs/tosca/go/processor/precompile"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/exp/maps"
+)
+
+var PrecompilesCmd = cliUtils.AddCommonFlags(cli.Command{
+	Action:    doPrecompiles,
+	Name:      "precompiles",
+	Usage:     "Run Conformance Tests on an implementation of precompiled contracts",
+	ArgsUsage: "<implementation>",
+	Flags: []cli.Flag{
+		cliUtils.FilterFlag,
+		cliUtils.SeedFlag,
+		&cli.IntFlag{
+			Name:  "max-errors",
+			Usage: "aborts testing after the given number of issues",
+			Value: 100,
+		},
+	},
+})
+
+var precompileImplementations = map[string]precompiles.Implementation{
+	"tosca": precompiles.NewRegistryImplementation(precompile.NewStandardRegistry()),
+	"geth":  precompiles.NewGethImplementation(),
+}
+
+func doPrecompiles(context *cli.Context) error {
+	seed := cliUtils.SeedFlag.Fetch(context)
+	filter, err := cliUtils.FilterFlag.Fetch(context)
+	if err != nil {
+		return err
+	}
+
+	maxErrors := context.Int("max-errors")
+	if maxErrors <= 0 {
+		maxErrors = math.MaxInt
+	}
+
+	var identifier string
+	if context.Args().Len() >= 1 {
+		identifier = context.Args().Get(0)
+	}
+	implementation, ok := precompileImplementations[identifier]
+	if !ok {
+		return fmt.Errorf("invalid implementation identifier, use one of: %v", maps.Keys(precompileImplementations))
+	}
+
+	defer fmt.Printf("Seed Used: %d\n", seed)
+
+	issuesCollector := cliUtils.IssuesCollector{}
+	for _, revision := range tosca.GetAllKnownRevisions() {
+		if err := precompiles.Spec.CheckAddresses(implementation, revision); err != nil {
+			issuesCollector.AddIssue(nil, err)
+		}
+	}
+
+	numTests := 0
+	rules := precompiles.FilterRules(precompiles.Spec.GetRules(), filter)
+	precompiles.ForEachTestCase(rules, seed, func(testCase precompiles.TestCase) (result rlz.ConsumerResult) {
+		defer func() {
+			if r := recover(); r != nil {
+				result = rlz.ConsumeAbort
+				issuesCollector.AddIssue(nil, fmt.Errorf("implementation panicked for %v: %v", testCase, r))
+			}
+		}()
+		if issuesCollector.NumIssues() >= maxErrors {
+			return rlz.ConsumeAbort
+		}
+		numTests++
+		if err := testCase.Check(implementation); err != nil {
+			issuesCollector.AddIssue(nil, fmt.Errorf("failed test case:\n %w", err))
+		}
+		return rlz.ConsumeContinue
+	})
+	fmt.Printf("Number of executed tests: %d\n", numTests)
+
+	issues := issuesCollector.GetIssues()
+	if len(issues) == 0 {
+		fmt.Printf("All tests passed successfully!\n")
+		return nil
+	}
+
+	if err := issuesCollector.ExportIssues(); err != nil {
+		return err
+	}
+	return fmt.Errorf("failed to pass %d test cases", len(issues))
+}
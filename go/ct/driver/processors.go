@@ -0,0 +1,126 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package main
+
+import (
+	"fmt"
+	"math"
+
+	cliUtils "github.com/0xsoniclabs/tosca/go/ct/driver/cli"
+	"github.com/0xsoniclabs/tosca/go/ct/processors"
+	"github.com/0xsoniclabs/tosca/go/ct/rlz"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/exp/maps"
+
+	_ "github.com/0xsoniclabs/tosca/go/processor/floria"
+	_ "github.com/0xsoniclabs/tosca/go/processor/floria_eth"
+	_ "github.com/0xsoniclabs/tosca/go/processor/geth"
+	_ "github.com/0xsoniclabs/tosca/go/processor/geth_eth"
+	_ "github.com/0xsoniclabs/tosca/go/processor/opera"
+)
+
+var ProcessorsCmd = cliUtils.AddCommonFlags(cli.Command{
+	Action:    doProcessors,
+	Name:      "processors",
+	Usage:     "Run Conformance Tests on a transaction processor",
+	ArgsUsage: "<processor>",
+	Flags: []cli.Flag{
+		cliUtils.FilterFlag,
+		cliUtils.SeedFlag,
+		&cli.StringFlag{
+			Name:  "interpreter",
+			Usage: "the interpreter used by the processor",
+			Value: "lfvm",
+		},
+		&cli.StringFlag{
+			Name:  "chain",
+			Usage: "the chain whose transaction rules are checked (sonic or ethereum)",
+			Value: "sonic",
+		},
+		&cli.IntFlag{
+			Name:  "max-errors",
+			Usage: "aborts testing after the given number of issues",
+			Value: 100,
+		},
+	},
+})
+
+var chains = map[string]processors.Chain{
+	"sonic":    processors.Sonic,
+	"ethereum": processors.Ethereum,
+}
+
+func doProcessors(context *cli.Context) error {
+	seed := cliUtils.SeedFlag.Fetch(context)
+	filter, err := cliUtils.FilterFlag.Fetch(context)
+	if err != nil {
+		return err
+	}
+
+	maxErrors := context.Int("max-errors")
+	if maxErrors <= 0 {
+		maxErrors = math.MaxInt
+	}
+
+	chain, ok := chains[context.String("chain")]
+	if !ok {
+		return fmt.Errorf("invalid chain, use one of: %v", maps.Keys(chains))
+	}
+
+	interpreter := tosca.GetInterpreter(context.String("interpreter"))
+	if interpreter == nil {
+		return fmt.Errorf("invalid interpreter, use one of: %v", maps.Keys(tosca.GetAllRegisteredInterpreters()))
+	}
+
+	var name string
+	if context.Args().Len() >= 1 {
+		name = context.Args().Get(0)
+	}
+	processor := tosca.GetProcessor(name, interpreter)
+	if processor == nil {
+		return fmt.Errorf("invalid processor identifier, use one of: %v", maps.Keys(tosca.GetAllRegisteredProcessorFactories()))
+	}
+
+	defer fmt.Printf("Seed Used: %d\n", seed)
+
+	issuesCollector := cliUtils.IssuesCollector{}
+	numTests := 0
+	rules := processors.FilterRules(processors.GetSpecification(chain).GetRules(), filter)
+	processors.ForEachTestCase(rules, seed, func(testCase processors.TestCase) (result rlz.ConsumerResult) {
+		defer func() {
+			if r := recover(); r != nil {
+				result = rlz.ConsumeAbort
+				issuesCollector.AddIssue(nil, fmt.Errorf("processor panicked for %v: %v", testCase, r))
+			}
+		}()
+		if issuesCollector.NumIssues() >= maxErrors {
+			return rlz.ConsumeAbort
+		}
+		numTests++
+		if err := testCase.Check(processor); err != nil {
+			issuesCollector.AddIssue(nil, fmt.Errorf("failed test case:\n %w", err))
+		}
+		return rlz.ConsumeContinue
+	})
+	fmt.Printf("Number of executed tests: %d\n", numTests)
+
+	issues := issuesCollector.GetIssues()
+	if len(issues) == 0 {
+		fmt.Printf("All tests passed successfully!\n")
+		return nil
+	}
+
+	if err := issuesCollector.ExportIssues(); err != nil {
+		return err
+	}
+	return fmt.Errorf("failed to pass %d test cases", len(issues))
+}
@@ -16,6 +16,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/0xsoniclabs/tosca/go/ct"
 	cliUtils "github.com/0xsoniclabs/tosca/go/ct/driver/cli"
 	"github.com/0xsoniclabs/tosca/go/ct/spc"
 	"github.com/0xsoniclabs/tosca/go/ct/st"
@@ -31,9 +32,13 @@ var RegressionsCmd = cliUtils.AddCommonFlags(cli.Command{
 	Flags: []cli.Flag{
 		&cli.StringSliceFlag{
 			Name:  "input",
-			Usage: "run given input file, or all files in the given directory (recursively)",
+			Usage: "run given input file or stream, or all files in the given directory (recursively)",
 			Value: cli.NewStringSlice("./regression_inputs"),
 		},
+		&cli.StringFlag{
+			Name:  "failures",
+			Usage: "write the input states failing any rule to the given stream file",
+		},
 	},
 })
 
@@ -94,50 +99,81 @@ func doRegressionTests(context *cli.Context) error {
 		return err
 	}
 
+	var failures *stateStream
+	if path := context.String("failures"); path != "" {
+		failures, err = createStateStream(path)
+		if err != nil {
+			return err
+		}
+	}
+
 	var issues []error
 	for _, input := range inputs {
 		fmt.Printf("Running regression tests for %v\n", input)
-		state, err := st.ImportStateJSON(input)
+		index := 0
+		err := st.ImportStates(input, func(state *st.State) error {
+			label := fmt.Sprintf("%v#%d", input, index)
+			index++
+			stateIssues := runRegressionTest(evm, state, label)
+			if len(stateIssues) > 0 && failures != nil {
+				if err := failures.Write(state); err != nil {
+					return err
+				}
+			}
+			issues = append(issues, stateIssues...)
+			return nil
+		})
 		if err != nil {
-			issues = append(issues, fmt.Errorf("failed to import state from %v: %w", input, err))
-			continue
+			issues = append(issues, fmt.Errorf("failed to import states from %v: %w", input, err))
 		}
+	}
 
-		rules := spc.Spec.GetRulesFor(state)
-
-		if len(rules) == 0 {
-			issues = append(issues, fmt.Errorf("no rules apply for input %v", input))
-			continue
+	if failures != nil {
+		if err := failures.Close(); err != nil {
+			issues = append(issues, err)
+		} else {
+			fmt.Printf("Wrote %d failing states to %v\n", failures.count, context.String("failures"))
 		}
+	}
 
-		evaluationCount := 0
-
-		for _, rule := range rules {
-			input := state.Clone()
-			expected := state.Clone()
-			rule.Effect.Apply(expected)
+	return errors.Join(issues...)
+}
 
-			// TODO: do not only skip state but change 'pc_on_data_is_ignored' rule to anyEffect, see #954
-			// Pc on data is not supported
-			if !state.Code.IsCode(int(state.Pc)) {
-				continue
-			}
+// runRegressionTest checks the given state against all rules applying to it
+// and returns the detected issues. The label identifies the state in the
+// reported issues.
+func runRegressionTest(evm ct.Evm, state *st.State, label string) []error {
+	rules := spc.Spec.GetRulesFor(state)
+	if len(rules) == 0 {
+		return []error{fmt.Errorf("no rules apply for input %v", label)}
+	}
 
-			result, err := evm.StepN(input.Clone(), 1)
-			if err != nil {
-				issues = append(issues, fmt.Errorf("failed to evaluate rule %v, %w", rule.Name, err))
-				continue
-			}
+	var issues []error
+	evaluationCount := 0
+	for _, rule := range rules {
+		input := state.Clone()
+		expected := state.Clone()
+		rule.Effect.Apply(expected)
+
+		// TODO: do not only skip state but change 'pc_on_data_is_ignored' rule to anyEffect, see #954
+		// Pc on data is not supported
+		if !state.Code.IsCode(int(state.Pc)) {
+			continue
+		}
 
-			if !result.Eq(expected) {
-				issues = append(issues, fmt.Errorf("unexpected result for rule %v, diff %v", rule.Name, formatDiffForUser(input, result, expected, rule.Name)))
-				continue
-			}
+		result, err := evm.StepN(input.Clone(), 1)
+		if err != nil {
+			issues = append(issues, fmt.Errorf("failed to evaluate rule %v on %v, %w", rule.Name, label, err))
+			continue
+		}
 
-			evaluationCount++
+		if !result.Eq(expected) {
+			issues = append(issues, fmt.Errorf("unexpected result for rule %v on %v, diff %v", rule.Name, label, formatDiffForUser(input, result, expected, rule.Name)))
+			continue
 		}
-		fmt.Printf("OK: (rules evaluated: %d)\n", evaluationCount)
-	}
 
-	return errors.Join(issues...)
+		evaluationCount++
+	}
+	fmt.Printf("OK: %v (rules evaluated: %d)\n", label, evaluationCount)
+	return issues
 }
@@ -0,0 +1,107 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"pgregory.net/rand"
+
+	"github.com/0xsoniclabs/tosca/go/ct/common"
+	cliUtils "github.com/0xsoniclabs/tosca/go/ct/driver/cli"
+	"github.com/0xsoniclabs/tosca/go/ct/spc"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/urfave/cli/v2"
+)
+
+var RevisionDiffCmd = cli.Command{
+	Action:    doRevisionDiff,
+	Name:      "revision-diff",
+	Usage:     "List the rules and gas prices of operations differing between two revisions",
+	ArgsUsage: "<old revision> <new revision>",
+	Flags: []cli.Flag{
+		cliUtils.FilterFlag,
+		cliUtils.SeedFlag,
+		&cli.IntFlag{
+			Name:  "samples",
+			Usage: "number of states sampled to compare the effects of rules",
+			Value: 10,
+		},
+	},
+}
+
+func doRevisionDiff(context *cli.Context) error {
+	if context.Args().Len() != 2 {
+		return fmt.Errorf("expected exactly two revisions to compare")
+	}
+	old, err := parseRevision(context.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	new, err := parseRevision(context.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	filter, err := cliUtils.FilterFlag.Fetch(context)
+	if err != nil {
+		return err
+	}
+	samples := context.Int("samples")
+	if samples <= 0 {
+		return fmt.Errorf("samples must be positive")
+	}
+	seed := cliUtils.SeedFlag.Fetch(context)
+	defer fmt.Printf("Seed Used: %d\n", seed)
+
+	rules := spc.FilterRules(spc.Spec.GetRules(), filter)
+	diff, err := spc.DiffRevisions(rules, old, new, rand.New(seed), samples)
+	if err != nil {
+		return err
+	}
+
+	for _, operation := range diff.Operations {
+		fmt.Printf("%s\n", operation.Name)
+		if gas := operation.StaticGas; gas != nil {
+			fmt.Printf("  static gas: %d -> %d\n", gas.Old, gas.New)
+		}
+		if gas := operation.DynamicGas; gas != nil {
+			fmt.Printf("  dynamic gas: %v -> %v\n", gas.Old, gas.New)
+		}
+		for _, name := range operation.Added {
+			fmt.Printf("  + %s\n", name)
+		}
+		for _, name := range operation.Removed {
+			fmt.Printf("  - %s\n", name)
+		}
+		for _, change := range operation.Modified {
+			aspects := []string{}
+			if change.Condition {
+				aspects = append(aspects, "condition")
+			}
+			if change.Effect {
+				aspects = append(aspects, "effect")
+			}
+			fmt.Printf("  ~ %s -> %s (%s)\n", change.Old, change.New, strings.Join(aspects, ", "))
+		}
+	}
+	fmt.Printf("Number of operations differing between %v and %v: %d\n", old, new, len(diff.Operations))
+	return nil
+}
+
+func parseRevision(name string) (tosca.Revision, error) {
+	for revision := common.MinRevision; revision <= common.NewestSupportedRevision; revision = common.GetNextRevision(revision) {
+		if strings.EqualFold(revision.String(), name) {
+			return revision, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown revision %q", name)
+}
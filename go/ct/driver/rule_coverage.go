@@ -0,0 +1,342 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime/coverage"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0xsoniclabs/tosca/go/ct"
+	cliUtils "github.com/0xsoniclabs/tosca/go/ct/driver/cli"
+	"github.com/0xsoniclabs/tosca/go/ct/rlz"
+	"github.com/0xsoniclabs/tosca/go/ct/spc"
+	"github.com/0xsoniclabs/tosca/go/ct/st"
+	"github.com/urfave/cli/v2"
+)
+
+var RuleCoverageCmd = cliUtils.AddCommonFlags(cli.Command{
+	Action:    doRuleCoverage,
+	Name:      "rule-coverage",
+	Usage:     "Relate rules to the interpreter code exercised by their test states",
+	ArgsUsage: "<EVM>",
+	Description: "Requires a driver built with Go coverage instrumentation in atomic mode, e.g.\n" +
+		"   go build -cover -covermode=atomic -coverpkg=./go/ct/driver,./go/interpreter/lfvm ./go/ct/driver\n" +
+		"The coverage counters of each rule are written to a directory of the output\n" +
+		"directory and summarized using `go tool covdata`.",
+	Flags: []cli.Flag{
+		cliUtils.FilterFlag,
+		cliUtils.JobsFlag,
+		cliUtils.SeedFlag,
+		cliUtils.FullModeFlag,
+		&cli.StringFlag{
+			Name:  "packages",
+			Usage: "comma-separated import paths of the packages to report on, all instrumented packages if empty",
+			Value: "github.com/0xsoniclabs/tosca/go/interpreter/lfvm,github.com/0xsoniclabs/tosca/go/interpreter/sfvm",
+		},
+		&cli.StringFlag{
+			Name:  "output",
+			Usage: "the directory to write coverage data and reports to",
+			Value: "rule-coverage",
+		},
+	},
+})
+
+func doRuleCoverage(context *cli.Context) error {
+	jobCount := cliUtils.JobsFlag.Fetch(context)
+	seed := cliUtils.SeedFlag.Fetch(context)
+	fullMode := cliUtils.FullModeFlag.Fetch(context)
+	filter, err := cliUtils.FilterFlag.Fetch(context)
+	if err != nil {
+		return err
+	}
+
+	var evmIdentifier string
+	if context.Args().Len() >= 1 {
+		evmIdentifier = context.Args().Get(0)
+	}
+	evm, ok := evms[evmIdentifier]
+	if !ok {
+		return fmt.Errorf("invalid EVM identifier, use one of: %v", slices.Sorted(maps.Keys(evms)))
+	}
+
+	// Counters can only be reset between rules in atomic mode.
+	if err := coverage.ClearCounters(); err != nil {
+		return fmt.Errorf("driver is not built with -cover -covermode=atomic: %w", err)
+	}
+
+	output := context.String("output")
+	packages := context.String("packages")
+	rules := spc.FilterRules(spc.Spec.GetRules(), filter)
+	defer fmt.Printf("Seed Used: %d\n", seed)
+
+	report := ruleCoverageReport{
+		functions: map[string]map[string]float64{},
+		blocks:    map[string]map[string]int64{},
+	}
+	for i, rule := range rules {
+		fmt.Printf("[%d/%d] Running tests of rule %s ...\n", i+1, len(rules), rule.Name)
+		dir := filepath.Join(output, "rules", rule.Name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create coverage directory: %w", err)
+		}
+
+		if err := coverage.ClearCounters(); err != nil {
+			return err
+		}
+		err := spc.ForEachState(
+			[]rlz.Rule{rule},
+			func(state *st.State) rlz.ConsumerResult {
+				runForCoverage(state, evm)
+				return rlz.ConsumeContinue
+			},
+			func(time.Duration, float64, int64) {},
+			jobCount,
+			seed,
+			fullMode,
+		)
+		if err != nil {
+			return fmt.Errorf("error generating states of %s: %w", rule.Name, err)
+		}
+		if err := coverage.WriteMetaDir(dir); err != nil {
+			return err
+		}
+		if err := coverage.WriteCountersDir(dir); err != nil {
+			return err
+		}
+
+		functions, blocks, err := readCoverage(dir, packages)
+		if err != nil {
+			return fmt.Errorf("failed to read coverage of %s: %w", rule.Name, err)
+		}
+		report.functions[rule.Name] = functions
+		report.blocks[rule.Name] = blocks
+	}
+
+	if err := report.write(output); err != nil {
+		return err
+	}
+	fmt.Print(report.getSummary())
+	fmt.Printf("Reports written to %s\n", output)
+	return nil
+}
+
+// runForCoverage executes a single step of the given state on the EVM. The
+// result is of no interest, only the code exercised by the step.
+func runForCoverage(state *st.State, evm ct.Evm) {
+	defer func() {
+		_ = recover() // < panics are reported by the run command
+	}()
+	// Pc on data is not supported, see run command.
+	if !state.Code.IsCode(int(state.Pc)) {
+		return
+	}
+	result, err := evm.StepN(state.Clone(), 1)
+	if err == nil {
+		result.Release()
+	}
+}
+
+// readCoverage summarizes the coverage data of the given packages in the given
+// directory using the covdata tool of the Go toolchain, listing the coverage
+// of functions in percent and the execution count of code blocks.
+func readCoverage(dir string, packages string) (map[string]float64, map[string]int64, error) {
+	args := []string{"-i", dir}
+	if packages != "" {
+		args = append(args, "-pkg", packages)
+	}
+	functionData, err := exec.Command("go", slices.Concat([]string{"tool", "covdata", "func"}, args)...).Output()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to run covdata: %w", err)
+	}
+	functions, err := parseFunctionCoverage(bytes.NewReader(functionData))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	profile := filepath.Join(dir, "cover.out")
+	if err := exec.Command("go", slices.Concat([]string{"tool", "covdata", "textfmt", "-o", profile}, args)...).Run(); err != nil {
+		return nil, nil, fmt.Errorf("failed to run covdata: %w", err)
+	}
+	file, err := os.Open(profile)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	blocks, err := parseCoverageProfile(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	return functions, blocks, nil
+}
+
+// parseFunctionCoverage parses the output of `go tool covdata func`, where
+// each line lists the position and name of a function followed by the share
+// of its statements covered. Functions are identified by position and name.
+func parseFunctionCoverage(reader io.Reader) (map[string]float64, error) {
+	res := map[string]float64{}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] == "total" {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid function coverage line %q", scanner.Text())
+		}
+		percentage, err := strconv.ParseFloat(strings.TrimSuffix(fields[2], "%"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid function coverage line %q: %w", scanner.Text(), err)
+		}
+		res[fields[0]+" "+fields[1]] = percentage
+	}
+	return res, scanner.Err()
+}
+
+// parseCoverageProfile parses a coverage profile in the text format of the
+// Go toolchain, where each line lists the position of a code block followed
+// by its number of statements and its execution count.
+func parseCoverageProfile(reader io.Reader) (map[string]int64, error) {
+	res := map[string]int64{}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid coverage profile line %q", line)
+		}
+		count, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid coverage profile line %q: %w", line, err)
+		}
+		res[fields[0]] += count
+	}
+	return res, scanner.Err()
+}
+
+// ruleCoverageReport collects the coverage of functions and code blocks per
+// rule.
+type ruleCoverageReport struct {
+	functions map[string]map[string]float64
+	blocks    map[string]map[string]int64
+}
+
+// getUnreached lists the functions and code blocks not exercised by any rule.
+func (r *ruleCoverageReport) getUnreached() (functions []string, blocks []string) {
+	reachedFunctions := map[string]bool{}
+	for _, covered := range r.functions {
+		for function, percentage := range covered {
+			reachedFunctions[function] = reachedFunctions[function] || percentage > 0
+		}
+	}
+	reachedBlocks := map[string]bool{}
+	for _, counts := range r.blocks {
+		for block, count := range counts {
+			reachedBlocks[block] = reachedBlocks[block] || count > 0
+		}
+	}
+	for function, reached := range reachedFunctions {
+		if !reached {
+			functions = append(functions, function)
+		}
+	}
+	for block, reached := range reachedBlocks {
+		if !reached {
+			blocks = append(blocks, block)
+		}
+	}
+	slices.Sort(functions)
+	slices.Sort(blocks)
+	return functions, blocks
+}
+
+// write produces the following files in the given directory:
+//   - functions.csv listing the functions exercised per rule
+//   - blocks.csv listing the code blocks exercised per rule
+//   - unreached.txt listing functions and blocks no rule exercises
+func (r *ruleCoverageReport) write(dir string) error {
+	var functions strings.Builder
+	functions.WriteString("rule,function,coverage\n")
+	for _, rule := range slices.Sorted(maps.Keys(r.functions)) {
+		covered := r.functions[rule]
+		for _, function := range slices.Sorted(maps.Keys(covered)) {
+			if percentage := covered[function]; percentage > 0 {
+				functions.WriteString(fmt.Sprintf("%s,%s,%.1f\n", rule, function, percentage))
+			}
+		}
+	}
+
+	var blocks strings.Builder
+	blocks.WriteString("rule,block,count\n")
+	for _, rule := range slices.Sorted(maps.Keys(r.blocks)) {
+		counts := r.blocks[rule]
+		for _, block := range slices.Sorted(maps.Keys(counts)) {
+			if count := counts[block]; count > 0 {
+				blocks.WriteString(fmt.Sprintf("%s,%s,%d\n", rule, block, count))
+			}
+		}
+	}
+
+	var unreached strings.Builder
+	unreachedFunctions, unreachedBlocks := r.getUnreached()
+	for _, function := range unreachedFunctions {
+		unreached.WriteString(fmt.Sprintf("function %s\n", function))
+	}
+	for _, block := range unreachedBlocks {
+		unreached.WriteString(fmt.Sprintf("block %s\n", block))
+	}
+
+	for name, content := range map[string]string{
+		"functions.csv": functions.String(),
+		"blocks.csv":    blocks.String(),
+		"unreached.txt": unreached.String(),
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *ruleCoverageReport) getSummary() string {
+	functions := map[string]bool{}
+	for _, covered := range r.functions {
+		for function := range covered {
+			functions[function] = true
+		}
+	}
+	blocks := map[string]bool{}
+	for _, counts := range r.blocks {
+		for block := range counts {
+			blocks[block] = true
+		}
+	}
+	unreachedFunctions, unreachedBlocks := r.getUnreached()
+	return fmt.Sprintf(
+		"Rules: %d, reached functions: %d/%d, reached blocks: %d/%d\n",
+		len(r.functions),
+		len(functions)-len(unreachedFunctions), len(functions),
+		len(blocks)-len(unreachedBlocks), len(blocks),
+	)
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package main
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRuleCoverage_FunctionCoverageCanBeParsed(t *testing.T) {
+	input := "example.com/lfvm/instructions.go:12:\topAdd\t\t100.0%\n" +
+		"example.com/lfvm/instructions.go:20:\topSub\t\t0.0%\n" +
+		"total\t\t\t\t(statements)\t50.0%\n"
+	functions, err := parseFunctionCoverage(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("failed to parse function coverage: %v", err)
+	}
+	want := map[string]float64{
+		"example.com/lfvm/instructions.go:12: opAdd": 100,
+		"example.com/lfvm/instructions.go:20: opSub": 0,
+	}
+	if !maps.Equal(want, functions) {
+		t.Errorf("unexpected functions, wanted %v, got %v", want, functions)
+	}
+
+	if _, err := parseFunctionCoverage(strings.NewReader("file.go:1: f 1.0% extra\n")); err == nil {
+		t.Errorf("expected parsing of invalid line to fail")
+	}
+}
+
+func TestRuleCoverage_ProfileCanBeParsed(t *testing.T) {
+	input := "mode: atomic\n" +
+		"example.com/lfvm/instructions.go:12.2,14.3 2 5\n" +
+		"example.com/lfvm/instructions.go:15.2,15.10 1 0\n"
+	blocks, err := parseCoverageProfile(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("failed to parse profile: %v", err)
+	}
+	want := map[string]int64{
+		"example.com/lfvm/instructions.go:12.2,14.3":  5,
+		"example.com/lfvm/instructions.go:15.2,15.10": 0,
+	}
+	if !maps.Equal(want, blocks) {
+		t.Errorf("unexpected blocks, wanted %v, got %v", want, blocks)
+	}
+
+	if _, err := parseCoverageProfile(strings.NewReader("file.go:1.1,2.2 1 many\n")); err == nil {
+		t.Errorf("expected parsing of invalid count to fail")
+	}
+}
+
+func TestRuleCoverage_UnreachedCodeIsReported(t *testing.T) {
+	report := ruleCoverageReport{
+		functions: map[string]map[string]float64{
+			"add": {"f": 100, "g": 0, "h": 0},
+			"sub": {"f": 50, "g": 20, "h": 0},
+		},
+		blocks: map[string]map[string]int64{
+			"add": {"a": 1, "b": 0, "c": 0},
+			"sub": {"a": 0, "b": 3, "c": 0},
+		},
+	}
+	functions, blocks := report.getUnreached()
+	if want := []string{"h"}; !slices.Equal(want, functions) {
+		t.Errorf("unexpected unreached functions, wanted %v, got %v", want, functions)
+	}
+	if want := []string{"c"}; !slices.Equal(want, blocks) {
+		t.Errorf("unexpected unreached blocks, wanted %v, got %v", want, blocks)
+	}
+	if want, got := "Rules: 2, reached functions: 2/3, reached blocks: 2/3\n", report.getSummary(); want != got {
+		t.Errorf("unexpected summary, wanted %q, got %q", want, got)
+	}
+}
+
+func TestRuleCoverage_ReportsListExercisedCodePerRule(t *testing.T) {
+	report := ruleCoverageReport{
+		functions: map[string]map[string]float64{
+			"sub": {"f": 50, "g": 0},
+			"add": {"f": 100, "g": 0},
+		},
+		blocks: map[string]map[string]int64{
+			"sub": {"a": 2, "b": 0},
+			"add": {"a": 1, "b": 0},
+		},
+	}
+	dir := t.TempDir()
+	if err := report.write(dir); err != nil {
+		t.Fatalf("failed to write report: %v", err)
+	}
+
+	want := map[string]string{
+		"functions.csv": "rule,function,coverage\nadd,f,100.0\nsub,f,50.0\n",
+		"blocks.csv":    "rule,block,count\nadd,a,1\nsub,a,2\n",
+		"unreached.txt": "function g\nblock b\n",
+	}
+	for name, content := range want {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+		if content != string(got) {
+			t.Errorf("unexpected content of %s, wanted %q, got %q", name, content, got)
+		}
+	}
+}
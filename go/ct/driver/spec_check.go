@@ -0,0 +1,145 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package main
+
+import (
+	"fmt"
+	"regexp"
+
+	"pgregory.net/rand"
+
+	"github.com/0xsoniclabs/tosca/go/ct/common"
+	cliUtils "github.com/0xsoniclabs/tosca/go/ct/driver/cli"
+	"github.com/0xsoniclabs/tosca/go/ct/rlz"
+	"github.com/0xsoniclabs/tosca/go/ct/spc"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+	"github.com/urfave/cli/v2"
+)
+
+var SpecCheckCmd = cliUtils.AddCommonFlags(cli.Command{
+	Action: doSpecCheck,
+	Name:   "spec-check",
+	Usage:  "Statically check the specification for conflicting rules and uncovered states",
+	Flags: []cli.Flag{
+		cliUtils.FilterFlag,
+		cliUtils.SeedFlag,
+		&cli.IntFlag{
+			Name:  "samples",
+			Usage: "number of states sampled per solver invocation",
+			Value: 10,
+		},
+		&cli.IntFlag{
+			Name:  "budget",
+			Usage: "maximum number of solver invocations per operation and revision",
+			Value: 1000,
+		},
+		&cli.BoolFlag{
+			Name:  "skip-overlaps",
+			Usage: "skip the pairwise analysis of rules",
+		},
+		&cli.BoolFlag{
+			Name:  "skip-completeness",
+			Usage: "skip the coverage analysis of operations",
+		},
+	},
+})
+
+func doSpecCheck(context *cli.Context) error {
+	filter, err := cliUtils.FilterFlag.Fetch(context)
+	if err != nil {
+		return err
+	}
+	seed := cliUtils.SeedFlag.Fetch(context)
+	samples := context.Int("samples")
+	budget := context.Int("budget")
+	if samples <= 0 || budget <= 0 {
+		return fmt.Errorf("samples and budget must be positive")
+	}
+
+	defer fmt.Printf("Seed Used: %d\n", seed)
+	rnd := rand.New(seed)
+	issuesCollector := cliUtils.IssuesCollector{}
+
+	if !context.Bool("skip-overlaps") {
+		fmt.Printf("Checking rules for conflicting overlaps ...\n")
+		rules := spc.FilterRules(spc.Spec.GetRules(), filter)
+		report, err := spc.CheckOverlaps(rules, rnd, samples)
+		if err != nil {
+			return fmt.Errorf("error checking overlaps: %w", err)
+		}
+		fmt.Printf(
+			"Analyzed %d pairs of rules: %d disjoint, %d overlapping consistently, %d unknown, %d conflicting\n",
+			report.Pairs, report.Disjoint, report.Consistent, report.Unknown, len(report.Conflicts),
+		)
+		for _, conflict := range report.Conflicts {
+			issuesCollector.AddIssue(conflict.Witness, fmt.Errorf(
+				"rules %s and %s overlap with different effects",
+				conflict.Rules[0].Name, conflict.Rules[1].Name,
+			))
+			conflict.Witness.Release()
+		}
+	}
+
+	if !context.Bool("skip-completeness") {
+		fmt.Printf("Checking operations for uncovered states ...\n")
+		ops := getCoveredOperations(spc.Spec.GetRules(), filter)
+		revisions := []tosca.Revision{}
+		for revision := common.MinRevision; revision <= common.NewestSupportedRevision; revision = common.GetNextRevision(revision) {
+			revisions = append(revisions, revision)
+		}
+		report, err := spc.CheckCompleteness(spc.Spec.GetRules(), ops, revisions, rnd, samples, budget)
+		if err != nil {
+			return fmt.Errorf("error checking completeness: %w", err)
+		}
+		fmt.Printf(
+			"Analyzed %d operations in %d revisions: %d covered, %d unknown, %d with gaps\n",
+			len(ops), len(revisions), report.Covered, len(report.Unknown), len(report.Gaps),
+		)
+		for _, cell := range report.Unknown {
+			fmt.Printf("Coverage of %v in %v is unknown\n", cell.Op, cell.Revision)
+		}
+		for _, gap := range report.Gaps {
+			issuesCollector.AddIssue(gap.Witness, fmt.Errorf("no rule for %v in %v", gap.Op, gap.Revision))
+			gap.Witness.Release()
+		}
+	}
+
+	issues := issuesCollector.GetIssues()
+	if len(issues) == 0 {
+		fmt.Printf("No issues found!\n")
+		return nil
+	}
+	if err := issuesCollector.ExportIssues(); err != nil {
+		return err
+	}
+	return fmt.Errorf("found %d issues in the specification", len(issues))
+}
+
+// getCoveredOperations lists all operations with at least one rule selected
+// by the given filter. The coverage of those operations is analyzed using all
+// rules of the specification.
+func getCoveredOperations(rules []rlz.Rule, filter *regexp.Regexp) []vm.OpCode {
+	opPattern := regexp.MustCompile(`code\[PC\] = ([^\s]+)`)
+	names := map[string]bool{}
+	for _, rule := range spc.FilterRules(rules, filter) {
+		if match := opPattern.FindStringSubmatch(rule.Condition.String()); match != nil {
+			names[match[1]] = true
+		}
+	}
+	res := []vm.OpCode{}
+	for i := range 256 {
+		if op := vm.OpCode(i); names[op.String()] {
+			res = append(res, op)
+		}
+	}
+	return res
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	cliUtils "github.com/0xsoniclabs/tosca/go/ct/driver/cli"
+	"github.com/0xsoniclabs/tosca/go/ct/spc"
+	"github.com/urfave/cli/v2"
+)
+
+var SpecExportCmd = cli.Command{
+	Action: doSpecExport,
+	Name:   "spec-export",
+	Usage:  "Export the rules of the specification in a declarative JSON format",
+	Flags: []cli.Flag{
+		cliUtils.FilterFlag,
+		&cli.StringFlag{
+			Name:  "output",
+			Usage: "the file to write the document to, stdout if not set",
+		},
+	},
+}
+
+var SpecDiffCmd = cli.Command{
+	Action:    doSpecDiff,
+	Name:      "spec-diff",
+	Usage:     "List the rules differing between two exported specifications",
+	ArgsUsage: "<old> <new>",
+}
+
+func doSpecExport(context *cli.Context) error {
+	filter, err := cliUtils.FilterFlag.Fetch(context)
+	if err != nil {
+		return err
+	}
+
+	document, err := spc.NewDocument(spc.FilterRules(spc.Spec.GetRules(), filter))
+	if err != nil {
+		return err
+	}
+
+	var writer io.Writer = os.Stdout
+	if path := context.String("output"); path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		writer = file
+	}
+	return document.Write(writer)
+}
+
+func doSpecDiff(context *cli.Context) error {
+	if context.Args().Len() != 2 {
+		return fmt.Errorf("expected exactly two documents to compare")
+	}
+	old, err := readDocument(context.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	new, err := readDocument(context.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	diffs, err := spc.Diff(old, new)
+	if err != nil {
+		return err
+	}
+	for _, diff := range diffs {
+		switch {
+		case diff.Old == nil:
+			fmt.Printf("+ %s\n", diff.Name)
+		case diff.New == nil:
+			fmt.Printf("- %s\n", diff.Name)
+		default:
+			fmt.Printf("~ %s\n", diff.Name)
+			for _, description := range []struct {
+				prefix string
+				rule   *spc.RuleDescription
+			}{{"-", diff.Old}, {"+", diff.New}} {
+				encoded, err := json.Marshal(description.rule)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("  %s %s\n", description.prefix, encoded)
+			}
+		}
+	}
+	fmt.Printf("Number of differing rules: %d\n", len(diffs))
+	return nil
+}
+
+func readDocument(path string) (spc.Document, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return spc.Document{}, fmt.Errorf("failed to open document: %w", err)
+	}
+	defer file.Close()
+	return spc.ReadDocument(file)
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/0xsoniclabs/tosca/go/ct/st"
+	"github.com/0xsoniclabs/tosca/go/ct/statetest"
+	"github.com/urfave/cli/v2"
+)
+
+var StateTestExportCmd = cli.Command{
+	Action: doStateTestExport,
+	Name:   "state-test-export",
+	Usage:  "Convert CT states into Ethereum state test fixtures replayable by other clients",
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "input",
+			Usage: "convert given state file or stream, or all files in the given directory (recursively)",
+			Value: cli.NewStringSlice("./regression_inputs"),
+		},
+		&cli.StringFlag{
+			Name:  "output",
+			Usage: "the directory to write the fixtures to",
+			Value: "./state_tests",
+		},
+		&cli.StringFlag{
+			Name:  "skipped",
+			Usage: "write the states that cannot be exported to the given stream file",
+		},
+	},
+}
+
+func doStateTestExport(context *cli.Context) error {
+	inputs, err := enumerateInputs(context.StringSlice("input"))
+	if err != nil {
+		return err
+	}
+
+	output := context.String("output")
+	if err := os.MkdirAll(output, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	var skipped *stateStream
+	if path := context.String("skipped"); path != "" {
+		skipped, err = createStateStream(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	exported, total := 0, 0
+	for _, input := range inputs {
+		var states []*st.State
+		err := st.ImportStates(input, func(state *st.State) error {
+			states = append(states, state)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to import states from %v: %w", input, err)
+		}
+
+		base := strings.TrimSuffix(filepath.Base(input), filepath.Ext(input))
+		for i, state := range states {
+			total++
+			name := base
+			if len(states) > 1 {
+				name = fmt.Sprintf("%s_%06d", base, i)
+			}
+
+			fixture, err := statetest.Export(state)
+			if err != nil {
+				fmt.Printf("Skipping %v: %v\n", name, err)
+				if skipped != nil {
+					if err := skipped.Write(state); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+
+			data, err := json.MarshalIndent(map[string]*statetest.Fixture{name: fixture}, "", "  ")
+			if err != nil {
+				return err
+			}
+			path := filepath.Join(output, name+".json")
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				return fmt.Errorf("failed to write fixture: %w", err)
+			}
+			exported++
+		}
+	}
+
+	if skipped != nil {
+		if err := skipped.Close(); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %d skipped states to %v\n", skipped.count, context.String("skipped"))
+	}
+	fmt.Printf("Exported %d of %d states to %v\n", exported, total, output)
+	return nil
+}
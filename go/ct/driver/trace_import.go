@@ -0,0 +1,96 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/0xsoniclabs/tosca/go/ct/st"
+	"github.com/0xsoniclabs/tosca/go/ct/trace"
+	"github.com/urfave/cli/v2"
+)
+
+var TraceImportCmd = cli.Command{
+	Action:    doTraceImport,
+	Name:      "trace-import",
+	Usage:     "Convert a step of an EIP-3155 trace into a CT state usable as regression input",
+	ArgsUsage: "<trace file>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "frame",
+			Usage:    "JSON file describing the code and context of the traced call frame",
+			Required: true,
+		},
+		&cli.IntFlag{
+			Name:  "step",
+			Usage: "index of the step to convert, counted from 0",
+		},
+		&cli.StringFlag{
+			Name:  "output",
+			Usage: "the file to write the state to, defaults to a file in ./regression_inputs named after the trace",
+		},
+	},
+}
+
+func doTraceImport(context *cli.Context) error {
+	if context.Args().Len() < 1 {
+		return fmt.Errorf("missing trace file")
+	}
+	tracePath := context.Args().Get(0)
+
+	file, err := os.Open(tracePath)
+	if err != nil {
+		return fmt.Errorf("failed to open trace: %w", err)
+	}
+	defer file.Close()
+	steps, err := trace.ReadTrace(file)
+	if err != nil {
+		return fmt.Errorf("failed to read trace: %w", err)
+	}
+
+	index := context.Int("step")
+	if index < 0 || index >= len(steps) {
+		return fmt.Errorf("step %d out of range, trace has %d steps", index, len(steps))
+	}
+
+	data, err := os.ReadFile(context.String("frame"))
+	if err != nil {
+		return fmt.Errorf("failed to read frame: %w", err)
+	}
+	var frame trace.Frame
+	if err := json.Unmarshal(data, &frame); err != nil {
+		return fmt.Errorf("failed to parse frame: %w", err)
+	}
+
+	state, err := trace.NewState(&frame, &steps[index])
+	if err != nil {
+		return fmt.Errorf("failed to convert step %d: %w", index, err)
+	}
+
+	output := context.String("output")
+	if output == "" {
+		name := strings.TrimSuffix(filepath.Base(tracePath), filepath.Ext(tracePath))
+		output = filepath.Join("./regression_inputs", fmt.Sprintf("%s_step_%d.json", name, index))
+	}
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := st.ExportStateJSON(state, output); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+
+	fmt.Printf("Wrote state of step %d (%v at pc %d) to %v\n", index, steps[index].OpName, state.Pc, output)
+	return nil
+}
@@ -208,7 +208,7 @@ func (b *BlockContextGenerator) Generate(assignment Assignment, rnd *rand.Rand)
 
 	revision := GetRevisionForBlock(blockNumber)
 	time := GetForkTime(revision)
-	nextTime := GetForkTime(revision + 1)
+	nextTime := GetForkTime(GetNextRevision(revision))
 	timestamp := rnd.Uint64n(nextTime-time) + time
 
 	return st.BlockContext{
@@ -30,6 +30,7 @@ type CodeGenerator struct {
 	varOps               []varOpConstraint
 	varIsCodeConstraints []varIsCodeConstraint
 	varIsDataConstraints []varIsDataConstraint
+	eofConstraints       []bool
 
 	// testing only
 	codeSize *int
@@ -79,10 +80,36 @@ func (g *CodeGenerator) AddIsData(v Variable) {
 	g.varIsDataConstraints = append(g.varIsDataConstraints, varIsDataConstraint{v})
 }
 
+// SetEof constrains the generated code to be an EOF container or legacy code.
+func (g *CodeGenerator) SetEof(isEof bool) {
+	if !slices.Contains(g.eofConstraints, isEof) {
+		g.eofConstraints = append(g.eofConstraints, isEof)
+	}
+}
+
 // Generate produces a Code instance satisfying the constraints set on this
 // generator or returns ErrUnsatisfiable on conflicting constraints. Updates the
 // given assignment along the way.
 func (g *CodeGenerator) Generate(assignment Assignment, rnd *rand.Rand) (*st.Code, error) {
+	if len(g.eofConstraints) > 1 {
+		return nil, fmt.Errorf("%w, code can not be EOF and legacy code", ErrUnsatisfiable)
+	}
+	if len(g.eofConstraints) == 1 && g.eofConstraints[0] {
+		return g.generateEof(assignment, rnd)
+	}
+	code, err := g.generateLegacy(assignment, rnd)
+	if err != nil {
+		return nil, err
+	}
+	if len(g.eofConstraints) == 1 && code.IsEof() {
+		return nil, fmt.Errorf("%w, generated code is an EOF container", ErrUnsatisfiable)
+	}
+	return code, nil
+}
+
+// generateLegacy produces legacy code satisfying the operation, isCode, and
+// isData constraints of the generator.
+func (g *CodeGenerator) generateLegacy(assignment Assignment, rnd *rand.Rand) (*st.Code, error) {
 	var err error
 
 	// Convert operation constraints referencing bound variables to constant constraints.
@@ -243,6 +270,7 @@ func (g *CodeGenerator) Clone() *CodeGenerator {
 		varOps:               slices.Clone(g.varOps),
 		varIsCodeConstraints: slices.Clone(g.varIsCodeConstraints),
 		varIsDataConstraints: slices.Clone(g.varIsDataConstraints),
+		eofConstraints:       slices.Clone(g.eofConstraints),
 	}
 }
 
@@ -255,6 +283,7 @@ func (g *CodeGenerator) Restore(other *CodeGenerator) {
 	g.varOps = slices.Clone(other.varOps)
 	g.varIsCodeConstraints = slices.Clone(other.varIsCodeConstraints)
 	g.varIsDataConstraints = slices.Clone(other.varIsDataConstraints)
+	g.eofConstraints = slices.Clone(other.eofConstraints)
 }
 
 func (g *CodeGenerator) String() string {
@@ -280,6 +309,14 @@ func (g *CodeGenerator) String() string {
 		parts = append(parts, fmt.Sprintf("isData[%v]", con.variable))
 	}
 
+	for _, isEof := range g.eofConstraints {
+		if isEof {
+			parts = append(parts, "eof")
+		} else {
+			parts = append(parts, "legacy")
+		}
+	}
+
 	return "{" + strings.Join(parts, ",") + "}"
 }
 
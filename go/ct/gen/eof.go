@@ -0,0 +1,511 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package gen
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+
+	"pgregory.net/rand"
+
+	. "github.com/0xsoniclabs/tosca/go/ct/common"
+	"github.com/0xsoniclabs/tosca/go/ct/st"
+	"github.com/0xsoniclabs/tosca/go/tosca/eof"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+)
+
+// generateEof produces a valid EOF container satisfying the operation, isCode,
+// and isData constraints of the generator. Each operation constrained to a
+// variable position is placed in one of the code sections of the container,
+// preceded by the instructions required to keep the container valid. Since
+// the positions of instructions are determined by the layout of the
+// container, operations at constant positions are not supported.
+func (g *CodeGenerator) generateEof(assignment Assignment, rnd *rand.Rand) (*st.Code, error) {
+	if len(g.constOps) > 0 {
+		return nil, fmt.Errorf("%w, operations at fixed positions are not supported in EOF code", ErrUnsatisfiable)
+	}
+
+	// Collect the operations to be placed, one for each variable.
+	variables := []Variable{}
+	ops := []vm.OpCode{}
+	for _, cur := range g.varOps {
+		if _, found := assignment[cur.variable]; found {
+			return nil, fmt.Errorf("%w, operations at fixed positions are not supported in EOF code", ErrUnsatisfiable)
+		}
+		if i := slices.Index(variables, cur.variable); i >= 0 {
+			if ops[i] != cur.op {
+				return nil, fmt.Errorf("%w, unable to satisfy conflicting constraint for op[%v]=%v and op[%v]=%v", ErrUnsatisfiable, cur.variable, ops[i], cur.variable, cur.op)
+			}
+			continue
+		}
+		if !vm.IsValidInEof(cur.op) {
+			return nil, fmt.Errorf("%w, %v is not valid in EOF code", ErrUnsatisfiable, cur.op)
+		}
+		variables = append(variables, cur.variable)
+		ops = append(ops, cur.op)
+	}
+
+	container, kind, positions, err := newEofBuilder(ops, rnd).build()
+	if err != nil {
+		return nil, err
+	}
+	bytes := container.Bytes()
+	if _, err := eof.ParseAndValidate(bytes, kind); err != nil {
+		return nil, fmt.Errorf("generated invalid EOF container: %w", err)
+	}
+	code := st.NewCode(bytes)
+
+	for i, v := range variables {
+		pos := code.GetSectionStart(positions[i].section) + positions[i].offset
+		assignment[v] = NewU256(uint64(pos))
+	}
+
+	var instructions, data []int
+	for pos := range code.Length() {
+		if code.IsCode(pos) {
+			instructions = append(instructions, pos)
+		} else {
+			data = append(data, pos)
+		}
+	}
+
+	for _, cur := range g.varIsCodeConstraints {
+		if pos, found := assignment[cur.variable]; found {
+			if !pos.IsUint64() || pos.Uint64() >= uint64(code.Length()) || !code.IsCode(int(pos.Uint64())) {
+				return nil, fmt.Errorf("%w, unable to satisfy isCode[%v]", ErrUnsatisfiable, cur.variable)
+			}
+			continue
+		}
+		assignment[cur.variable] = NewU256(uint64(instructions[rnd.Intn(len(instructions))]))
+	}
+
+	for _, cur := range g.varIsDataConstraints {
+		if pos, found := assignment[cur.variable]; found {
+			if pos.IsUint64() && pos.Uint64() < uint64(code.Length()) && code.IsCode(int(pos.Uint64())) {
+				return nil, fmt.Errorf("%w, unable to satisfy isData[%v]", ErrUnsatisfiable, cur.variable)
+			}
+			continue
+		}
+		// The header of the container is never code.
+		assignment[cur.variable] = NewU256(uint64(data[rnd.Intn(len(data))]))
+	}
+
+	return code, nil
+}
+
+// eofPosition is the position of an instruction within a code section.
+type eofPosition struct {
+	section int
+	offset  int
+}
+
+// eofBuilder assembles a valid EOF container hosting a list of operations.
+// The code sections are built as straight-line code, keeping track of the
+// exact stack height at each instruction. Terminating instructions within a
+// section are skipped by a preceding RJUMPI to keep the remaining code
+// reachable, and relative jumps only target instructions of the same stack
+// height, which results in valid sections.
+type eofBuilder struct {
+	rnd        *rand.Rand
+	ops        []vm.OpCode
+	initcode   bool                // < whether an initcode or a runtime container is built
+	types      []eof.FunctionType  // < the types of the code sections
+	sections   []*eofSection       // < the code sections under construction
+	containers [][]byte            // < the sub-containers
+	kinds      []eof.ContainerKind // < the usage of each sub-container
+	data       []byte
+}
+
+// eofSection is a code section under construction.
+type eofSection struct {
+	code      []byte
+	heights   []int // < the stack height at each position, -1 for immediates
+	height    int   // < the stack height at the end of the code
+	maxHeight int
+	jumps     []eofJump
+	placed    []eofPlacement // < the remaining operations to be placed in this section
+}
+
+// eofJump is a relative jump whose targets are to be resolved once the code
+// of its section is complete.
+type eofJump struct {
+	pos    int // < the position of the jump instruction
+	height int // < the stack height at all targets
+}
+
+// eofPlacement is an operation to be placed in a code section.
+type eofPlacement struct {
+	op     vm.OpCode
+	index  int // < the index of the operation in the list of required operations, -1 for others
+	target int // < the targeted code section of CALLF and JUMPF, -1 for a random one
+}
+
+// maxEofFillerHeight is the stack height above which filler instructions are
+// preceded by POPs.
+const maxEofFillerHeight = 24
+
+func newEofBuilder(ops []vm.OpCode, rnd *rand.Rand) *eofBuilder {
+	return &eofBuilder{rnd: rnd, ops: ops}
+}
+
+func (b *eofBuilder) build() (*eof.Container, eof.ContainerKind, []eofPosition, error) {
+	rnd := b.rnd
+
+	// STOP and RETURN are only valid in runtime containers, RETURNCONTRACT
+	// only in initcode containers.
+	needsRuntime := slices.ContainsFunc(b.ops, func(op vm.OpCode) bool { return op == vm.STOP || op == vm.RETURN })
+	needsInitcode := slices.Contains(b.ops, vm.RETURNCONTRACT)
+	if needsRuntime && needsInitcode {
+		return nil, 0, nil, fmt.Errorf("%w, operations require runtime and initcode container", ErrUnsatisfiable)
+	}
+	b.initcode = needsInitcode || (!needsRuntime && rnd.Intn(2) == 0)
+
+	// DATALOADN requires at least 32 bytes of data.
+	dataSize := rnd.Intn(65)
+	if slices.Contains(b.ops, vm.DATALOADN) {
+		dataSize = 32 + rnd.Intn(33)
+	}
+	b.data = RandomBytesOfSize(rnd, dataSize).ToBytes()
+
+	// Pick the types of the code sections, the first one is non-returning
+	// without inputs.
+	b.types = []eof.FunctionType{{Inputs: 0, Outputs: eof.NonReturning}}
+	for range rnd.Intn(4) {
+		b.types = append(b.types, b.randomType(rnd.Intn(2) == 0))
+	}
+	returning := func() []int {
+		res := []int{}
+		for i, t := range b.types {
+			if t.IsReturning() {
+				res = append(res, i)
+			}
+		}
+		return res
+	}
+	if len(returning()) == 0 && (slices.Contains(b.ops, vm.CALLF) || slices.Contains(b.ops, vm.RETF)) {
+		b.types = append(b.types, b.randomType(true))
+	}
+
+	b.sections = make([]*eofSection, len(b.types))
+	for i, t := range b.types {
+		b.sections[i] = &eofSection{height: int(t.Inputs), maxHeight: int(t.Inputs)}
+	}
+
+	// Distribute the required operations among the sections. RETF may only
+	// be placed in returning sections.
+	for i, op := range b.ops {
+		section := rnd.Intn(len(b.types))
+		if op == vm.RETF {
+			candidates := returning()
+			section = candidates[rnd.Intn(len(candidates))]
+		}
+		b.sections[section].placed = append(b.sections[section].placed, eofPlacement{op: op, index: i, target: -1})
+	}
+
+	// Every section needs to be referenced by a preceding section to be
+	// reachable from the first section.
+	for i := 1; i < len(b.types); i++ {
+		op := vm.JUMPF
+		if b.types[i].IsReturning() {
+			op = vm.CALLF
+		}
+		section := rnd.Intn(i)
+		b.sections[section].placed = append(b.sections[section].placed, eofPlacement{op: op, index: -1, target: i})
+	}
+
+	positions := make([]eofPosition, len(b.ops))
+	for i, section := range b.sections {
+		rnd.Shuffle(len(section.placed), func(x, y int) {
+			section.placed[x], section.placed[y] = section.placed[y], section.placed[x]
+		})
+		b.appendFillers(i)
+		for _, placement := range section.placed {
+			offset := b.appendOperation(i, placement.op, placement.target, true)
+			if placement.index >= 0 {
+				positions[placement.index] = eofPosition{section: i, offset: offset}
+			}
+			b.appendFillers(i)
+		}
+		b.appendTerminator(i)
+		b.resolveJumps(section)
+	}
+
+	container := &eof.Container{
+		Containers: b.containers,
+		Data:       b.data,
+		DataSize:   uint16(len(b.data)),
+	}
+	for i, section := range b.sections {
+		t := b.types[i]
+		t.MaxStackIncrease = uint16(section.maxHeight - int(t.Inputs))
+		container.Types = append(container.Types, t)
+		container.Code = append(container.Code, section.code)
+	}
+
+	kind := eof.RuntimeContainer
+	if b.initcode {
+		kind = eof.InitcodeContainer
+	}
+	return container, kind, positions, nil
+}
+
+func (b *eofBuilder) randomType(returning bool) eof.FunctionType {
+	res := eof.FunctionType{Inputs: uint8(b.rnd.Intn(4)), Outputs: eof.NonReturning}
+	if returning {
+		res.Outputs = uint8(b.rnd.Intn(4))
+	}
+	return res
+}
+
+// appendFillers appends a few random instructions to the given section.
+func (b *eofBuilder) appendFillers(section int) {
+	for range b.rnd.Intn(4) {
+		s := b.sections[section]
+		for s.height > maxEofFillerHeight {
+			b.appendOperation(section, vm.POP, -1, false)
+		}
+		b.appendOperation(section, b.randomFillerOp(), -1, false)
+	}
+}
+
+// randomFillerOp picks a random non-terminating instruction not requiring any
+// references to other sections or sub-containers.
+func (b *eofBuilder) randomFillerOp() vm.OpCode {
+	for {
+		op := vm.OpCode(b.rnd.Intn(256))
+		if !vm.IsValidInEof(op) || eof.IsTerminal(op) {
+			continue
+		}
+		switch op {
+		case vm.CALLF, vm.JUMPF, vm.EOFCREATE, vm.RETURNCONTRACT:
+			continue
+		case vm.DATALOADN:
+			if len(b.data) < 32 {
+				continue
+			}
+		}
+		return op
+	}
+}
+
+// appendTerminator ends the given section with a terminating instruction
+// valid for the type of the section.
+func (b *eofBuilder) appendTerminator(section int) {
+	rnd := b.rnd
+	if b.types[section].IsReturning() {
+		// Returning sections end by returning or by jumping to a returning
+		// section whose outputs can be provided.
+		op, target := vm.RETF, -1
+		if rnd.Intn(4) == 0 {
+			candidates := b.getJumpfTargets(section)
+			target = candidates[rnd.Intn(len(candidates))]
+			if b.types[target].IsReturning() {
+				op = vm.JUMPF
+			}
+		}
+		b.appendOperation(section, op, target, false)
+		return
+	}
+
+	candidates := []vm.OpCode{vm.REVERT, vm.INVALID, vm.RJUMP, vm.JUMPF}
+	if b.initcode {
+		candidates = append(candidates, vm.RETURNCONTRACT)
+	} else {
+		candidates = append(candidates, vm.STOP, vm.RETURN)
+	}
+	b.appendOperation(section, candidates[rnd.Intn(len(candidates))], -1, false)
+}
+
+// getJumpfTargets lists the sections which may be targeted by a JUMPF in the
+// given section. Returning sections may only be targeted by returning
+// sections with at least as many outputs.
+func (b *eofBuilder) getJumpfTargets(section int) []int {
+	res := []int{}
+	for i, t := range b.types {
+		if !t.IsReturning() {
+			res = append(res, i)
+			continue
+		}
+		cur := b.types[section]
+		if cur.IsReturning() && cur.Outputs >= t.Outputs {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// appendOperation appends the given operation to the given section, preceded
+// by the instructions needed to provide the required stack elements. If
+// guarded is set, terminating operations are preceded by an RJUMPI skipping
+// them. Otherwise, the operation is expected to end the section. For CALLF
+// and JUMPF, a target of -1 selects a random target section. The offset of
+// the appended operation in the section is returned.
+func (b *eofBuilder) appendOperation(section int, op vm.OpCode, target int, guarded bool) int {
+	rnd := b.rnd
+	s := b.sections[section]
+	cur := b.types[section]
+
+	// Pick targets of calls and determine the stack effect.
+	pops, pushes := eof.GetStackEffect(op)
+	exact := -1 // < the exact stack height required by the operation, if any
+	switch op {
+	case vm.CALLF:
+		if target < 0 {
+			candidates := []int{}
+			for i, t := range b.types {
+				if t.IsReturning() {
+					candidates = append(candidates, i)
+				}
+			}
+			target = candidates[rnd.Intn(len(candidates))]
+		}
+		pops, pushes = int(b.types[target].Inputs), int(b.types[target].Outputs)
+	case vm.JUMPF:
+		if target < 0 {
+			candidates := b.getJumpfTargets(section)
+			target = candidates[rnd.Intn(len(candidates))]
+		}
+		t := b.types[target]
+		pops, pushes = int(t.Inputs), 0
+		if t.IsReturning() {
+			exact = int(cur.Outputs) + int(t.Inputs) - int(t.Outputs)
+		}
+	case vm.RETF:
+		pops, pushes = int(cur.Outputs), 0
+		exact = pops
+	}
+
+	// Provide the required stack elements.
+	for s.height < pops || (exact >= 0 && s.height < exact) {
+		b.appendPush(s)
+	}
+	for exact >= 0 && s.height > exact {
+		b.appendRaw(s, []byte{byte(vm.POP)}, 1, 0)
+	}
+
+	// Assemble the instruction.
+	instruction := []byte{byte(op)}
+	switch {
+	case vm.PUSH1 <= op && op <= vm.PUSH32:
+		instruction = append(instruction, RandomBytesOfSize(rnd, int(op-vm.PUSH1)+1).ToBytes()...)
+	case op == vm.RJUMP || op == vm.RJUMPI:
+		instruction = append(instruction, 0, 0)
+	case op == vm.RJUMPV:
+		count := 1 + rnd.Intn(4)
+		instruction = append(instruction, byte(count-1))
+		instruction = append(instruction, make([]byte, 2*count)...)
+	case op == vm.CALLF || op == vm.JUMPF:
+		instruction = binary.BigEndian.AppendUint16(instruction, uint16(target))
+	case op == vm.DATALOADN:
+		instruction = binary.BigEndian.AppendUint16(instruction, uint16(rnd.Intn(len(b.data)-31)))
+	case op == vm.EOFCREATE:
+		instruction = append(instruction, byte(b.getSubContainer(eof.InitcodeContainer)))
+	case op == vm.RETURNCONTRACT:
+		instruction = append(instruction, byte(b.getSubContainer(eof.RuntimeContainer)))
+	}
+
+	// Terminating operations within the section are skipped by a jump.
+	terminal := eof.IsTerminal(op)
+	if terminal && guarded {
+		b.appendPush(s)
+		b.appendRaw(s, binary.BigEndian.AppendUint16([]byte{byte(vm.RJUMPI)}, uint16(len(instruction))), 1, 0)
+	}
+
+	height := s.height
+	offset := len(s.code)
+	switch op {
+	case vm.RJUMP, vm.RJUMPI, vm.RJUMPV:
+		s.jumps = append(s.jumps, eofJump{pos: offset, height: height - pops})
+	}
+	b.appendRaw(s, instruction, pops, pushes)
+
+	// The code following a skipped operation continues with the stack height
+	// before the operation.
+	if terminal && guarded {
+		s.height = height
+	}
+	return offset
+}
+
+// appendPush appends a random push instruction to the given section.
+func (b *eofBuilder) appendPush(s *eofSection) {
+	op := vm.PUSH0 + vm.OpCode(b.rnd.Intn(33))
+	instruction := append([]byte{byte(op)}, RandomBytesOfSize(b.rnd, int(op-vm.PUSH0)).ToBytes()...)
+	b.appendRaw(s, instruction, 0, 1)
+}
+
+// appendRaw appends the given instruction with the given stack effect to the
+// given section.
+func (b *eofBuilder) appendRaw(s *eofSection, instruction []byte, pops, pushes int) {
+	s.code = append(s.code, instruction...)
+	s.heights = append(s.heights, s.height)
+	for range len(instruction) - 1 {
+		s.heights = append(s.heights, -1)
+	}
+	s.height = s.height - pops + pushes
+	s.maxHeight = max(s.maxHeight, s.height)
+}
+
+// resolveJumps sets the targets of the relative jumps of the given section to
+// random instructions with the stack height expected after the jump. Since
+// every jump is followed by an instruction of this height, unless it ends the
+// section, and a jump may target itself, there is always a valid target.
+func (b *eofBuilder) resolveJumps(s *eofSection) {
+	for _, jump := range s.jumps {
+		candidates := []int{}
+		for pos, height := range s.heights {
+			if height == jump.height {
+				candidates = append(candidates, pos)
+			}
+		}
+		count := 1
+		immediates := jump.pos + 1
+		if vm.OpCode(s.code[jump.pos]) == vm.RJUMPV {
+			count = int(s.code[jump.pos+1]) + 1
+			immediates++
+		}
+		next := immediates + 2*count
+		for i := range count {
+			target := candidates[b.rnd.Intn(len(candidates))]
+			binary.BigEndian.PutUint16(s.code[immediates+2*i:], uint16(int16(target-next)))
+		}
+	}
+}
+
+// getSubContainer returns the index of a sub-container of the given kind,
+// which is created if needed.
+func (b *eofBuilder) getSubContainer(kind eof.ContainerKind) int {
+	for i, cur := range b.kinds {
+		if cur == kind && b.rnd.Intn(2) == 0 {
+			return i
+		}
+	}
+	if len(b.containers) == eof.MaxContainerSections {
+		return slices.Index(b.kinds, kind)
+	}
+
+	// Sub-containers end their execution right away. Only the data of
+	// containers deployed by RETURNCONTRACT may be truncated.
+	data := RandomBytes(b.rnd, 16).ToBytes()
+	sub := &eof.Container{
+		Types:    []eof.FunctionType{{Inputs: 0, Outputs: eof.NonReturning}},
+		Code:     [][]byte{{byte(vm.INVALID)}},
+		Data:     data,
+		DataSize: uint16(len(data)),
+	}
+	if kind == eof.RuntimeContainer {
+		sub.DataSize += uint16(b.rnd.Intn(4))
+	}
+	b.containers = append(b.containers, sub.Bytes())
+	b.kinds = append(b.kinds, kind)
+	return len(b.containers) - 1
+}
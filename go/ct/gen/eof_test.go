@@ -0,0 +1,131 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package gen
+
+import (
+	"errors"
+	"testing"
+
+	"pgregory.net/rand"
+
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+)
+
+func TestCodeGenerator_EofConstraintProducesEofCode(t *testing.T) {
+	rnd := rand.New(0)
+	for range 100 {
+		generator := NewCodeGenerator()
+		generator.SetEof(true)
+		code, err := generator.Generate(Assignment{}, rnd)
+		if err != nil {
+			t.Fatalf("unexpected error during build: %v", err)
+		}
+		if !code.IsEof() {
+			t.Fatalf("expected EOF code, got %v", code)
+		}
+	}
+}
+
+func TestCodeGenerator_LegacyConstraintProducesLegacyCode(t *testing.T) {
+	rnd := rand.New(0)
+	generator := NewCodeGenerator()
+	generator.SetEof(false)
+	code, err := generator.Generate(Assignment{}, rnd)
+	if err != nil {
+		t.Fatalf("unexpected error during build: %v", err)
+	}
+	if code.IsEof() {
+		t.Fatalf("expected legacy code, got %v", code)
+	}
+}
+
+func TestCodeGenerator_ConflictingEofConstraintsAreDetected(t *testing.T) {
+	generator := NewCodeGenerator()
+	generator.SetEof(true)
+	generator.SetEof(false)
+	if _, err := generator.Generate(Assignment{}, rand.New(0)); !errors.Is(err, ErrUnsatisfiable) {
+		t.Errorf("unsatisfiable constraint not detected, got %v", err)
+	}
+}
+
+func TestCodeGenerator_EofCodeContainsEveryEofOperation(t *testing.T) {
+	rnd := rand.New(0)
+	for i := range 256 {
+		op := vm.OpCode(i)
+		if !vm.IsValidInEof(op) {
+			continue
+		}
+		for range 10 {
+			generator := NewCodeGenerator()
+			generator.SetEof(true)
+			generator.AddOperation("X", op)
+			generator.AddIsCode("Y")
+			generator.AddIsData("Z")
+			assignment := Assignment{}
+			code, err := generator.Generate(assignment, rnd)
+			if err != nil {
+				t.Fatalf("unexpected error for %v: %v", op, err)
+			}
+			pos := int(assignment["X"].Uint64())
+			if got, err := code.GetOperation(pos); err != nil || got != op {
+				t.Fatalf("expected %v at position %d, got %v, %v", op, pos, got, err)
+			}
+			if !code.IsCode(int(assignment["Y"].Uint64())) {
+				t.Errorf("isCode constraint not satisfied for %v", op)
+			}
+			if !code.IsData(int(assignment["Z"].Uint64())) {
+				t.Errorf("isData constraint not satisfied for %v", op)
+			}
+		}
+	}
+}
+
+func TestCodeGenerator_EofCodeCanContainMultipleOperations(t *testing.T) {
+	rnd := rand.New(0)
+	ops := []vm.OpCode{vm.RETF, vm.CALLF, vm.RJUMPV, vm.DATALOADN, vm.EOFCREATE, vm.RETURNCONTRACT}
+	for range 100 {
+		generator := NewCodeGenerator()
+		generator.SetEof(true)
+		for i, op := range ops {
+			generator.AddOperation(Variable(rune('A'+i)), op)
+		}
+		assignment := Assignment{}
+		code, err := generator.Generate(assignment, rnd)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i, op := range ops {
+			pos := int(assignment[Variable(rune('A'+i))].Uint64())
+			if got, err := code.GetOperation(pos); err != nil || got != op {
+				t.Fatalf("expected %v at position %d, got %v, %v", op, pos, got, err)
+			}
+		}
+	}
+}
+
+func TestCodeGenerator_EofCodeRejectsConflictingContainerKinds(t *testing.T) {
+	generator := NewCodeGenerator()
+	generator.SetEof(true)
+	generator.AddOperation("A", vm.STOP)
+	generator.AddOperation("B", vm.RETURNCONTRACT)
+	if _, err := generator.Generate(Assignment{}, rand.New(0)); !errors.Is(err, ErrUnsatisfiable) {
+		t.Errorf("unsatisfiable constraint not detected, got %v", err)
+	}
+}
+
+func TestCodeGenerator_EofCodeRejectsLegacyOnlyOperations(t *testing.T) {
+	generator := NewCodeGenerator()
+	generator.SetEof(true)
+	generator.AddOperation("A", vm.JUMP)
+	if _, err := generator.Generate(Assignment{}, rand.New(0)); !errors.Is(err, ErrUnsatisfiable) {
+		t.Errorf("unsatisfiable constraint not detected, got %v", err)
+	}
+}
@@ -49,6 +49,7 @@ type StateGenerator struct {
 	pcVariableConstraints  []Variable
 	gasConstraints         *RangeSolver[tosca.Gas]
 	gasRefundConstraints   *RangeSolver[tosca.Gas]
+	returnStackConstraints *RangeSolver[int]
 	variableBindings       []variableBinding
 	selfAddressConstraints []tosca.Address
 	selfAddressBindings    []Variable
@@ -70,19 +71,20 @@ type StateGenerator struct {
 // NewStateGenerator creates a generator without any initial constraints.
 func NewStateGenerator() *StateGenerator {
 	return &StateGenerator{
-		codeGen:               NewCodeGenerator(),
-		stackGen:              NewStackGenerator(),
-		memoryGen:             NewMemoryGenerator(),
-		storageGen:            NewStorageGenerator(),
-		transientStorageGen:   NewTransientStorageGenerator(),
-		accountsGen:           NewAccountGenerator(),
-		callContextGen:        NewCallContextGenerator(),
-		callJournalGen:        NewCallJournalGenerator(),
-		blockContextGen:       NewBlockContextGenerator(),
-		gasConstraints:        NewRangeSolver[tosca.Gas](0, st.MaxGasUsedByCt),
-		gasRefundConstraints:  NewRangeSolver[tosca.Gas](-st.MaxGasUsedByCt, st.MaxGasUsedByCt),
-		hasSelfDestructedGen:  NewSelfDestructedGenerator(),
-		transactionContextGen: NewTransactionContextGenerator(),
+		codeGen:                NewCodeGenerator(),
+		stackGen:               NewStackGenerator(),
+		memoryGen:              NewMemoryGenerator(),
+		storageGen:             NewStorageGenerator(),
+		transientStorageGen:    NewTransientStorageGenerator(),
+		accountsGen:            NewAccountGenerator(),
+		callContextGen:         NewCallContextGenerator(),
+		callJournalGen:         NewCallJournalGenerator(),
+		blockContextGen:        NewBlockContextGenerator(),
+		gasConstraints:         NewRangeSolver[tosca.Gas](0, st.MaxGasUsedByCt),
+		gasRefundConstraints:   NewRangeSolver[tosca.Gas](-st.MaxGasUsedByCt, st.MaxGasUsedByCt),
+		returnStackConstraints: NewRangeSolver(0, st.MaxReturnStackSize),
+		hasSelfDestructedGen:   NewSelfDestructedGenerator(),
+		transactionContextGen:  NewTransactionContextGenerator(),
 	}
 }
 
@@ -293,6 +295,34 @@ func (g *StateGenerator) BindToColdAddress(key Variable) {
 	g.accountsGen.BindCold(key)
 }
 
+// MustBeEofCode wraps CodeGenerator.SetEof.
+func (g *StateGenerator) MustBeEofCode() {
+	g.codeGen.SetEof(true)
+}
+
+// MustBeLegacyCode wraps CodeGenerator.SetEof.
+func (g *StateGenerator) MustBeLegacyCode() {
+	g.codeGen.SetEof(false)
+}
+
+// AddReturnStackSizeLowerBound adds a constraint on the minimum number of
+// entries of the return stack. Only EOF code may have a non-empty return stack.
+func (g *StateGenerator) AddReturnStackSizeLowerBound(size int) {
+	g.returnStackConstraints.AddLowerBoundary(size)
+}
+
+// AddReturnStackSizeUpperBound adds a constraint on the maximum number of
+// entries of the return stack.
+func (g *StateGenerator) AddReturnStackSizeUpperBound(size int) {
+	g.returnStackConstraints.AddUpperBoundary(size)
+}
+
+// SetReturnStackSize adds a constraint on the number of entries of the return
+// stack.
+func (g *StateGenerator) SetReturnStackSize(size int) {
+	g.returnStackConstraints.AddEqualityConstraint(size)
+}
+
 func (g *StateGenerator) MustBeNewContract() {
 	g.hasSelfDestructedGen.MarkAsNewContract()
 }
@@ -427,6 +457,12 @@ func (g *StateGenerator) generateWith(rnd *rand.Rand, assignment Assignment) (*s
 		return nil, fmt.Errorf("failed to resolve gas refund constraints: %w", err)
 	}
 
+	// Pick a return stack, which may only be non-empty for EOF code.
+	resultReturnStack, err := g.generateReturnStack(resultCode, rnd)
+	if err != nil {
+		return nil, err
+	}
+
 	// --- Self Address ---
 
 	// Pick the address of the account executing the code.
@@ -556,6 +592,7 @@ func (g *StateGenerator) generateWith(rnd *rand.Rand, assignment Assignment) (*s
 	result.Pc = resultPc
 	result.Gas = resultGas
 	result.GasRefund = resultGasRefund
+	result.ReturnStack = resultReturnStack
 	result.Stack = resultStack
 	result.Memory = resultMemory
 	result.Storage = resultStorage
@@ -574,6 +611,42 @@ func (g *StateGenerator) generateWith(rnd *rand.Rand, assignment Assignment) (*s
 	return result, nil
 }
 
+// generateReturnStack produces a return stack satisfying the size constraints.
+// The entries are random instruction positions within the code sections of
+// the given code.
+func (g *StateGenerator) generateReturnStack(code *st.Code, rnd *rand.Rand) ([]uint16, error) {
+	if !code.IsEof() {
+		if g.returnStackConstraints.GetMin() > 0 {
+			return nil, fmt.Errorf("%w, legacy code has no return stack", ErrUnsatisfiable)
+		}
+		return nil, nil
+	}
+
+	size, err := g.returnStackConstraints.Generate(rnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve return stack constraints: %w", err)
+	}
+	// Favor small return stacks, as they are most common.
+	if rnd.Intn(2) == 0 {
+		size = min(size, g.returnStackConstraints.GetMin()+rnd.Intn(4))
+	}
+	if size == 0 {
+		return nil, nil
+	}
+
+	var instructions []uint16
+	for pos := range code.Length() {
+		if _, inSection := code.GetSection(pos); inSection && code.IsCode(pos) {
+			instructions = append(instructions, uint16(pos))
+		}
+	}
+	res := make([]uint16, size)
+	for i := range res {
+		res[i] = instructions[rnd.Intn(len(instructions))]
+	}
+	return res, nil
+}
+
 // Clone creates an independent copy of the generator in its current state.
 // Future modifications are isolated from each other.
 func (g *StateGenerator) Clone() *StateGenerator {
@@ -584,6 +657,7 @@ func (g *StateGenerator) Clone() *StateGenerator {
 		pcVariableConstraints:  slices.Clone(g.pcVariableConstraints),
 		gasConstraints:         g.gasConstraints.Clone(),
 		gasRefundConstraints:   g.gasRefundConstraints.Clone(),
+		returnStackConstraints: g.returnStackConstraints.Clone(),
 		variableBindings:       slices.Clone(g.variableBindings),
 		selfAddressConstraints: slices.Clone(g.selfAddressConstraints),
 		selfAddressBindings:    slices.Clone(g.selfAddressBindings),
@@ -610,6 +684,7 @@ func (g *StateGenerator) Restore(other *StateGenerator) {
 		g.pcVariableConstraints = slices.Clone(other.pcVariableConstraints)
 		g.gasConstraints.Restore(other.gasConstraints)
 		g.gasRefundConstraints.Restore(other.gasRefundConstraints)
+		g.returnStackConstraints.Restore(other.returnStackConstraints)
 		g.variableBindings = slices.Clone(other.variableBindings)
 		g.selfAddressConstraints = slices.Clone(other.selfAddressConstraints)
 		g.selfAddressBindings = slices.Clone(other.selfAddressBindings)
@@ -668,6 +743,9 @@ func (g *StateGenerator) String() string {
 
 	parts = append(parts, g.gasConstraints.Print("gas"))
 	parts = append(parts, g.gasRefundConstraints.Print("gasRefund"))
+	if g.returnStackConstraints.GetMin() > 0 || g.returnStackConstraints.GetMax() < st.MaxReturnStackSize {
+		parts = append(parts, g.returnStackConstraints.Print("returnStackSize"))
+	}
 
 	parts = append(parts, fmt.Sprintf("code=%v", g.codeGen))
 	parts = append(parts, fmt.Sprintf("stack=%v", g.stackGen))
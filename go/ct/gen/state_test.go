@@ -248,6 +248,73 @@ func TestStateGenerator_AddGasLowerUpperBoundIsEnforced(t *testing.T) {
 	}
 }
 
+////////////////////////////////////////////////////////////
+// Return Stack
+
+func TestStateGenerator_SetReturnStackSizeIsEnforced(t *testing.T) {
+	sizes := []int{0, 1, 42, st.MaxReturnStackSize}
+
+	rnd := rand.New(0)
+	for _, size := range sizes {
+		generator := NewStateGenerator()
+		generator.MustBeEofCode()
+		generator.SetReturnStackSize(size)
+		state, err := generator.Generate(rnd)
+		if err != nil {
+			t.Fatalf("unexpected error during build: %v", err)
+		}
+		if want, got := size, len(state.ReturnStack); want != got {
+			t.Errorf("unexpected return stack size, wanted %d, got %d", want, got)
+		}
+		for _, pos := range state.ReturnStack {
+			if _, inSection := state.Code.GetSection(int(pos)); !inSection || !state.Code.IsCode(int(pos)) {
+				t.Errorf("return stack entry %d is not an instruction of a code section", pos)
+			}
+		}
+	}
+}
+
+func TestStateGenerator_AddReturnStackSizeLowerUpperBoundIsEnforced(t *testing.T) {
+	generator := NewStateGenerator()
+	generator.MustBeEofCode()
+	generator.AddReturnStackSizeLowerBound(42)
+	generator.AddReturnStackSizeUpperBound(44)
+
+	state, err := generator.Generate(rand.New(0))
+	if err != nil {
+		t.Fatalf("unexpected error during build: %v", err)
+	}
+	if size := len(state.ReturnStack); size < 42 || size > 44 {
+		t.Fatalf("return stack bounds not working, got %v", size)
+	}
+}
+
+func TestStateGenerator_LegacyCodeHasNoReturnStack(t *testing.T) {
+	generator := NewStateGenerator()
+	generator.MustBeLegacyCode()
+	state, err := generator.Generate(rand.New(0))
+	if err != nil {
+		t.Fatalf("unexpected error during build: %v", err)
+	}
+	if len(state.ReturnStack) != 0 {
+		t.Errorf("unexpected return stack for legacy code: %v", state.ReturnStack)
+	}
+
+	generator.AddReturnStackSizeLowerBound(1)
+	if _, err := generator.Generate(rand.New(0)); !errors.Is(err, ErrUnsatisfiable) {
+		t.Errorf("unsatisfiable constraint not detected, got %v", err)
+	}
+}
+
+func TestStateGenerator_ConflictingCodeKindsAreDetected(t *testing.T) {
+	generator := NewStateGenerator()
+	generator.MustBeEofCode()
+	generator.MustBeLegacyCode()
+	if _, err := generator.Generate(rand.New(0)); !errors.Is(err, ErrUnsatisfiable) {
+		t.Errorf("unsatisfiable constraint not detected, got %v", err)
+	}
+}
+
 ////////////////////////////////////////////////////////////
 // Gas Refund Counter
 
@@ -0,0 +1,118 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package precompiles
+
+import (
+	"bytes"
+	"math"
+	"slices"
+
+	"github.com/0xsoniclabs/tosca/go/processor/precompile"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/ethereum/go-ethereum/common"
+	geth "github.com/ethereum/go-ethereum/core/vm"
+)
+
+// Implementation is the interface through which the conformance tests check
+// an implementation of precompiled contracts.
+type Implementation interface {
+	// Addresses returns the sorted addresses of all contracts available in
+	// the given revision.
+	Addresses(revision tosca.Revision) []tosca.Address
+
+	// Run calls the contract at the given address. A failing execution is
+	// reported by an unsuccessful result without output and gas.
+	Run(revision tosca.Revision, address tosca.Address, input tosca.Data, gas tosca.Gas) tosca.CallResult
+}
+
+// NewRegistryImplementation creates an implementation running the contracts
+// of the given registry.
+func NewRegistryImplementation(registry *precompile.Registry) Implementation {
+	return registryImplementation{registry: registry}
+}
+
+type registryImplementation struct {
+	registry *precompile.Registry
+}
+
+func (i registryImplementation) Addresses(revision tosca.Revision) []tosca.Address {
+	return i.registry.Addresses(revision)
+}
+
+func (i registryImplementation) Run(revision tosca.Revision, address tosca.Address, input tosca.Data, gas tosca.Gas) tosca.CallResult {
+	contract, found := i.registry.Get(address, revision)
+	if !found {
+		return tosca.CallResult{}
+	}
+	cost := contract.RequiredGas(input)
+	if cost > math.MaxInt64 || tosca.Gas(cost) > gas {
+		return tosca.CallResult{}
+	}
+	output, err := contract.Run(input)
+	if err != nil {
+		return tosca.CallResult{}
+	}
+	return tosca.CallResult{
+		Success: true,
+		Output:  output,
+		GasLeft: gas - tosca.Gas(cost),
+	}
+}
+
+// NewGethImplementation creates an implementation running the precompiled
+// contracts of geth, serving as a reference for the specification.
+func NewGethImplementation() Implementation {
+	return gethImplementation{}
+}
+
+type gethImplementation struct{}
+
+func (gethImplementation) Addresses(revision tosca.Revision) []tosca.Address {
+	res := []tosca.Address{}
+	for address := range getGethPrecompiles(revision) {
+		res = append(res, tosca.Address(address))
+	}
+	slices.SortFunc(res, func(a, b tosca.Address) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	return res
+}
+
+func (gethImplementation) Run(revision tosca.Revision, address tosca.Address, input tosca.Data, gas tosca.Gas) tosca.CallResult {
+	contract, found := getGethPrecompiles(revision)[common.Address(address)]
+	if !found || gas < 0 {
+		return tosca.CallResult{}
+	}
+	output, gasLeft, err := geth.RunPrecompiledContract(nil, contract, common.Address(address), input, uint64(gas), nil)
+	if err != nil {
+		return tosca.CallResult{}
+	}
+	return tosca.CallResult{
+		Success: true,
+		Output:  output,
+		GasLeft: tosca.Gas(gasLeft),
+	}
+}
+
+func getGethPrecompiles(revision tosca.Revision) geth.PrecompiledContracts {
+	switch revision {
+	case tosca.R07_Istanbul:
+		return geth.PrecompiledContractsIstanbul
+	case tosca.R09_Berlin, tosca.R10_London, tosca.R11_Paris, tosca.R12_Shanghai:
+		return geth.PrecompiledContractsBerlin
+	case tosca.R13_Cancun:
+		return geth.PrecompiledContractsCancun
+	case tosca.R14_Prague:
+		return geth.PrecompiledContractsPrague
+	default:
+		return geth.PrecompiledContractsOsaka
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package precompiles
+
+import (
+	"testing"
+
+	"github.com/0xsoniclabs/tosca/go/ct/rlz"
+	"github.com/0xsoniclabs/tosca/go/processor/precompile"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+)
+
+func TestImplementation_ConformsToSpecification(t *testing.T) {
+	implementations := map[string]Implementation{
+		"tosca": NewRegistryImplementation(precompile.NewStandardRegistry()),
+		"geth":  NewGethImplementation(),
+	}
+	for name, implementation := range implementations {
+		t.Run(name, func(t *testing.T) {
+			for _, revision := range tosca.GetAllKnownRevisions() {
+				if err := Spec.CheckAddresses(implementation, revision); err != nil {
+					t.Error(err)
+				}
+			}
+			errors := 0
+			ForEachTestCase(Spec.GetRules(), 0, func(testCase TestCase) rlz.ConsumerResult {
+				if err := testCase.Check(implementation); err != nil {
+					t.Error(err)
+					errors++
+				}
+				if errors >= 10 {
+					return rlz.ConsumeAbort
+				}
+				return rlz.ConsumeContinue
+			})
+		})
+	}
+}
+
+func TestRegistryImplementation_UnknownAddressFails(t *testing.T) {
+	implementation := NewRegistryImplementation(precompile.NewStandardRegistry())
+	result := implementation.Run(tosca.R07_Istanbul, precompileAddress(0x0a), nil, 100000)
+	if result.Success {
+		t.Errorf("calling a contract not available in the revision should fail")
+	}
+}
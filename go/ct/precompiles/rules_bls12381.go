@@ -0,0 +1,495 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package precompiles
+
+import (
+	"math/big"
+	"slices"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"pgregory.net/rand"
+)
+
+// getBls12381Rules specifies the operations on the BLS12-381 curve defined by
+// EIP-2537 at addresses 0x0b to 0x11, introduced in Prague. Field elements
+// are encoded in 64 bytes, the leading 16 of which must be zero.
+func getBls12381Rules() []Rule {
+	return []Rule{
+		{
+			Name:      "bls12_g1add",
+			Address:   precompileAddress(0x0b),
+			Revisions: since(tosca.R14_Prague),
+			Gas:       constantGas(375),
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				a := randomBls12381G1(rnd)
+				b := randomBls12381G1(rnd)
+				negA := new(bls12381.G1Affine).Neg(&a)
+				infinity := bls12381.G1Affine{}
+				outside := bls12381G1OutsideOfSubgroup()
+				res := []Sample{}
+				// Points outside of the subgroup are accepted for additions.
+				for _, pair := range [][2]*bls12381.G1Affine{
+					{&a, &b}, {&a, &a}, {&a, negA}, {&a, &infinity},
+					{&infinity, &infinity}, {&outside, &a},
+				} {
+					res = append(res, Sample{
+						Input:  concat(encodeBls12381G1(pair[0]), encodeBls12381G1(pair[1])),
+						Output: encodeBls12381G1(new(bls12381.G1Affine).Add(pair[0], pair[1])),
+					})
+				}
+				return res
+			},
+		},
+		{
+			Name:      "bls12_g1add_invalid_input",
+			Address:   precompileAddress(0x0b),
+			Revisions: since(tosca.R14_Prague),
+			Gas:       constantGas(375),
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				valid := randomBls12381G1(rnd)
+				input := concat(encodeBls12381G1(&valid), encodeBls12381G1(&valid))
+				return slices.Concat(
+					invalidLengthSamples(input),
+					invalidPointSamples(encodeBls12381G1(&valid), func(point tosca.Data) tosca.Data {
+						return concat(encodeBls12381G1(&valid), point)
+					}),
+				)
+			},
+		},
+		{
+			Name:      "bls12_g1msm",
+			Address:   precompileAddress(0x0c),
+			Revisions: since(tosca.R14_Prague),
+			Gas:       bls12381MsmGas(bls12381G1MsmPairLength, 12000, &bls12381G1MsmDiscounts),
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				res := []Sample{}
+				for _, k := range []int{1, 2, 3, 10, 129} {
+					input := tosca.Data{}
+					sum := bls12381.G1Affine{}
+					for i := range k {
+						point := randomBls12381G1(rnd)
+						scalar := randomBls12381Scalar(rnd, i)
+						if i == 1 {
+							point = bls12381.G1Affine{}
+						}
+						input = append(input, concat(encodeBls12381G1(&point), scalar.FillBytes(make([]byte, 32)))...)
+						sum.Add(&sum, new(bls12381.G1Affine).ScalarMultiplication(&point, scalar))
+					}
+					res = append(res, Sample{Input: input, Output: encodeBls12381G1(&sum)})
+				}
+				return res
+			},
+		},
+		{
+			Name:      "bls12_g1msm_invalid_input",
+			Address:   precompileAddress(0x0c),
+			Revisions: since(tosca.R14_Prague),
+			Gas:       bls12381MsmGas(bls12381G1MsmPairLength, 12000, &bls12381G1MsmDiscounts),
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				valid := randomBls12381G1(rnd)
+				outside := bls12381G1OutsideOfSubgroup()
+				scalar := randomData(rnd, 32)
+				input := concat(encodeBls12381G1(&valid), scalar)
+				return slices.Concat(
+					invalidLengthSamples(input),
+					invalidPointSamples(encodeBls12381G1(&valid), func(point tosca.Data) tosca.Data {
+						return concat(point, scalar)
+					}),
+					[]Sample{
+						{Input: nil, Fails: true},
+						{Input: concat(encodeBls12381G1(&outside), scalar), Fails: true},
+					},
+				)
+			},
+		},
+		{
+			Name:      "bls12_g2add",
+			Address:   precompileAddress(0x0d),
+			Revisions: since(tosca.R14_Prague),
+			Gas:       constantGas(600),
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				a := randomBls12381G2(rnd)
+				b := randomBls12381G2(rnd)
+				negA := new(bls12381.G2Affine).Neg(&a)
+				infinity := bls12381.G2Affine{}
+				outside := bls12381G2OutsideOfSubgroup()
+				res := []Sample{}
+				for _, pair := range [][2]*bls12381.G2Affine{
+					{&a, &b}, {&a, &a}, {&a, negA}, {&a, &infinity},
+					{&infinity, &infinity}, {&outside, &a},
+				} {
+					res = append(res, Sample{
+						Input:  concat(encodeBls12381G2(pair[0]), encodeBls12381G2(pair[1])),
+						Output: encodeBls12381G2(new(bls12381.G2Affine).Add(pair[0], pair[1])),
+					})
+				}
+				return res
+			},
+		},
+		{
+			Name:      "bls12_g2add_invalid_input",
+			Address:   precompileAddress(0x0d),
+			Revisions: since(tosca.R14_Prague),
+			Gas:       constantGas(600),
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				valid := randomBls12381G2(rnd)
+				input := concat(encodeBls12381G2(&valid), encodeBls12381G2(&valid))
+				return slices.Concat(
+					invalidLengthSamples(input),
+					invalidPointSamples(encodeBls12381G2(&valid), func(point tosca.Data) tosca.Data {
+						return concat(point, encodeBls12381G2(&valid))
+					}),
+				)
+			},
+		},
+		{
+			Name:      "bls12_g2msm",
+			Address:   precompileAddress(0x0e),
+			Revisions: since(tosca.R14_Prague),
+			Gas:       bls12381MsmGas(bls12381G2MsmPairLength, 22500, &bls12381G2MsmDiscounts),
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				res := []Sample{}
+				for _, k := range []int{1, 2, 3, 10, 129} {
+					input := tosca.Data{}
+					sum := bls12381.G2Affine{}
+					for i := range k {
+						point := randomBls12381G2(rnd)
+						scalar := randomBls12381Scalar(rnd, i)
+						if i == 1 {
+							point = bls12381.G2Affine{}
+						}
+						input = append(input, concat(encodeBls12381G2(&point), scalar.FillBytes(make([]byte, 32)))...)
+						sum.Add(&sum, new(bls12381.G2Affine).ScalarMultiplication(&point, scalar))
+					}
+					res = append(res, Sample{Input: input, Output: encodeBls12381G2(&sum)})
+				}
+				return res
+			},
+		},
+		{
+			Name:      "bls12_g2msm_invalid_input",
+			Address:   precompileAddress(0x0e),
+			Revisions: since(tosca.R14_Prague),
+			Gas:       bls12381MsmGas(bls12381G2MsmPairLength, 22500, &bls12381G2MsmDiscounts),
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				valid := randomBls12381G2(rnd)
+				outside := bls12381G2OutsideOfSubgroup()
+				scalar := randomData(rnd, 32)
+				input := concat(encodeBls12381G2(&valid), scalar)
+				return slices.Concat(
+					invalidLengthSamples(input),
+					invalidPointSamples(encodeBls12381G2(&valid), func(point tosca.Data) tosca.Data {
+						return concat(point, scalar)
+					}),
+					[]Sample{
+						{Input: nil, Fails: true},
+						{Input: concat(encodeBls12381G2(&outside), scalar), Fails: true},
+					},
+				)
+			},
+		},
+		{
+			Name:      "bls12_pairing",
+			Address:   precompileAddress(0x0f),
+			Revisions: since(tosca.R14_Prague),
+			Gas:       bls12381PairingGas,
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				_, _, g1, g2 := bls12381.Generators()
+				a := randomBls12381Scalar(rnd, 2)
+				b := randomBls12381Scalar(rnd, 2)
+				ab := new(big.Int).Mul(a, b)
+
+				// e(a*G1, b*G2) * e(-ab*G1, G2) = 1
+				aG1 := new(bls12381.G1Affine).ScalarMultiplication(&g1, a)
+				bG2 := new(bls12381.G2Affine).ScalarMultiplication(&g2, b)
+				abG1 := new(bls12381.G1Affine).ScalarMultiplication(&g1, ab)
+				negAbG1 := new(bls12381.G1Affine).Neg(abG1)
+				valid := concat(
+					encodeBls12381G1(aG1), encodeBls12381G2(bG2),
+					encodeBls12381G1(negAbG1), encodeBls12381G2(&g2),
+				)
+				infinity := concat(encodeBls12381G1(&bls12381.G1Affine{}), encodeBls12381G2(&g2))
+				return []Sample{
+					{Input: valid, Output: word(1)},
+					{Input: valid[:bls12381PairingPairLength], Output: word(0)},
+					{Input: concat(valid, valid[:bls12381PairingPairLength]), Output: word(0)},
+					{Input: infinity, Output: word(1)},
+					{Input: concat(valid, infinity), Output: word(1)},
+				}
+			},
+		},
+		{
+			Name:      "bls12_pairing_invalid_input",
+			Address:   precompileAddress(0x0f),
+			Revisions: since(tosca.R14_Prague),
+			Gas:       bls12381PairingGas,
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				g1 := randomBls12381G1(rnd)
+				g2 := randomBls12381G2(rnd)
+				outsideG1 := bls12381G1OutsideOfSubgroup()
+				outsideG2 := bls12381G2OutsideOfSubgroup()
+				input := concat(encodeBls12381G1(&g1), encodeBls12381G2(&g2))
+				return slices.Concat(
+					invalidLengthSamples(input),
+					invalidPointSamples(encodeBls12381G1(&g1), func(point tosca.Data) tosca.Data {
+						return concat(point, encodeBls12381G2(&g2))
+					}),
+					invalidPointSamples(encodeBls12381G2(&g2), func(point tosca.Data) tosca.Data {
+						return concat(encodeBls12381G1(&g1), point)
+					}),
+					[]Sample{
+						{Input: nil, Fails: true},
+						{Input: concat(encodeBls12381G1(&outsideG1), encodeBls12381G2(&g2)), Fails: true},
+						{Input: concat(encodeBls12381G1(&g1), encodeBls12381G2(&outsideG2)), Fails: true},
+					},
+				)
+			},
+		},
+		{
+			Name:      "bls12_map_fp_to_g1",
+			Address:   precompileAddress(0x10),
+			Revisions: since(tosca.R14_Prague),
+			Gas:       constantGas(5500),
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				res := []Sample{}
+				for _, element := range []fp.Element{{}, randomBls12381Fp(rnd), maxBls12381Fp()} {
+					point := bls12381.MapToG1(element)
+					res = append(res, Sample{
+						Input:  encodeBls12381Fp(element),
+						Output: encodeBls12381G1(&point),
+					})
+				}
+				return res
+			},
+		},
+		{
+			Name:      "bls12_map_fp_to_g1_invalid_input",
+			Address:   precompileAddress(0x10),
+			Revisions: since(tosca.R14_Prague),
+			Gas:       constantGas(5500),
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				element := encodeBls12381Fp(randomBls12381Fp(rnd))
+				return slices.Concat(
+					invalidLengthSamples(element),
+					invalidFieldElementSamples(element, 0),
+				)
+			},
+		},
+		{
+			Name:      "bls12_map_fp2_to_g2",
+			Address:   precompileAddress(0x11),
+			Revisions: since(tosca.R14_Prague),
+			Gas:       constantGas(23800),
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				res := []Sample{}
+				for _, element := range []bls12381.E2{
+					{},
+					{A0: randomBls12381Fp(rnd), A1: randomBls12381Fp(rnd)},
+					{A0: maxBls12381Fp(), A1: maxBls12381Fp()},
+				} {
+					point := bls12381.MapToG2(element)
+					res = append(res, Sample{
+						Input:  concat(encodeBls12381Fp(element.A0), encodeBls12381Fp(element.A1)),
+						Output: encodeBls12381G2(&point),
+					})
+				}
+				return res
+			},
+		},
+		{
+			Name:      "bls12_map_fp2_to_g2_invalid_input",
+			Address:   precompileAddress(0x11),
+			Revisions: since(tosca.R14_Prague),
+			Gas:       constantGas(23800),
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				element := concat(encodeBls12381Fp(randomBls12381Fp(rnd)), encodeBls12381Fp(randomBls12381Fp(rnd)))
+				return slices.Concat(
+					invalidLengthSamples(element),
+					invalidFieldElementSamples(element, 0),
+					invalidFieldElementSamples(element, 1),
+				)
+			},
+		},
+	}
+}
+
+const (
+	bls12381FieldElementLength = 64
+	bls12381G1MsmPairLength    = 2*bls12381FieldElementLength + 32
+	bls12381G2MsmPairLength    = 4*bls12381FieldElementLength + 32
+	bls12381PairingPairLength  = 6 * bls12381FieldElementLength
+)
+
+// bls12381G1MsmDiscounts are the discounts in per mille applied to the price
+// of a G1 multi-scalar multiplication depending on the number of pairs.
+var bls12381G1MsmDiscounts = [128]uint64{1000, 949, 848, 797, 764, 750, 738, 728, 719, 712, 705, 698, 692, 687, 682, 677, 673, 669, 665, 661, 658, 654, 651, 648, 645, 642, 640, 637, 635, 632, 630, 627, 625, 623, 621, 619, 617, 615, 613, 611, 609, 608, 606, 604, 603, 601, 599, 598, 596, 595, 593, 592, 591, 589, 588, 586, 585, 584, 582, 581, 580, 579, 577, 576, 575, 574, 573, 572, 570, 569, 568, 567, 566, 565, 564, 563, 562, 561, 560, 559, 558, 557, 556, 555, 554, 553, 552, 551, 550, 549, 548, 547, 547, 546, 545, 544, 543, 542, 541, 540, 540, 539, 538, 537, 536, 536, 535, 534, 533, 532, 532, 531, 530, 529, 528, 528, 527, 526, 525, 525, 524, 523, 522, 522, 521, 520, 520, 519}
+
+// bls12381G2MsmDiscounts are the discounts in per mille applied to the price
+// of a G2 multi-scalar multiplication depending on the number of pairs.
+var bls12381G2MsmDiscounts = [128]uint64{1000, 1000, 923, 884, 855, 832, 812, 796, 782, 770, 759, 749, 740, 732, 724, 717, 711, 704, 699, 693, 688, 683, 679, 674, 670, 666, 663, 659, 655, 652, 649, 646, 643, 640, 637, 634, 632, 629, 627, 624, 622, 620, 618, 615, 613, 611, 609, 607, 606, 604, 602, 600, 598, 597, 595, 593, 592, 590, 589, 587, 586, 584, 583, 582, 580, 579, 578, 576, 575, 574, 573, 571, 570, 569, 568, 567, 566, 565, 563, 562, 561, 560, 559, 558, 557, 556, 555, 554, 553, 552, 552, 551, 550, 549, 548, 547, 546, 545, 545, 544, 543, 542, 541, 541, 540, 539, 538, 537, 537, 536, 535, 535, 534, 533, 532, 532, 531, 530, 530, 529, 528, 528, 527, 526, 526, 525, 524, 524}
+
+// bls12381MsmGas returns a gas function charging a multi-scalar
+// multiplication for the number of complete pairs in the input, discounted
+// according to EIP-2537.
+func bls12381MsmGas(pairLength int, mulGas uint64, discounts *[128]uint64) func(tosca.Revision, tosca.Data) uint64 {
+	return func(_ tosca.Revision, input tosca.Data) uint64 {
+		k := len(input) / pairLength
+		if k == 0 {
+			return 0
+		}
+		discount := discounts[min(k, len(discounts))-1]
+		return uint64(k) * mulGas * discount / 1000
+	}
+}
+
+func bls12381PairingGas(_ tosca.Revision, input tosca.Data) uint64 {
+	return 37700 + 32600*uint64(len(input)/bls12381PairingPairLength)
+}
+
+// randomBls12381Scalar produces scalars covering zero, values exceeding the
+// group order, and random values, depending on the given index.
+func randomBls12381Scalar(rnd *rand.Rand, index int) *big.Int {
+	switch index {
+	case 0:
+		return big.NewInt(0)
+	case 3:
+		return new(big.Int).SetBytes(concat([]byte{0xff}, randomData(rnd, 31)))
+	default:
+		return new(big.Int).SetBytes(randomData(rnd, 32))
+	}
+}
+
+func randomBls12381G1(rnd *rand.Rand) bls12381.G1Affine {
+	var res bls12381.G1Affine
+	res.ScalarMultiplicationBase(new(big.Int).SetBytes(randomData(rnd, 32)))
+	return res
+}
+
+func randomBls12381G2(rnd *rand.Rand) bls12381.G2Affine {
+	var res bls12381.G2Affine
+	res.ScalarMultiplicationBase(new(big.Int).SetBytes(randomData(rnd, 32)))
+	return res
+}
+
+func randomBls12381Fp(rnd *rand.Rand) fp.Element {
+	var res fp.Element
+	res.SetBigInt(new(big.Int).SetBytes(randomData(rnd, 64)))
+	return res
+}
+
+func maxBls12381Fp() fp.Element {
+	var res fp.Element
+	res.SetBigInt(new(big.Int).Sub(fp.Modulus(), big.NewInt(1)))
+	return res
+}
+
+// bls12381G1OutsideOfSubgroup returns a point on the G1 curve which is not in
+// the subgroup of prime order.
+func bls12381G1OutsideOfSubgroup() bls12381.G1Affine {
+	for x := uint64(1); ; x++ {
+		var point bls12381.G1Affine
+		point.X.SetUint64(x)
+		var rhs fp.Element
+		rhs.Square(&point.X).Mul(&rhs, &point.X).Add(&rhs, new(fp.Element).SetUint64(4))
+		if rhs.Legendre() != 1 {
+			continue
+		}
+		point.Y.Sqrt(&rhs)
+		if point.IsOnCurve() && !point.IsInSubGroup() {
+			return point
+		}
+	}
+}
+
+// bls12381G2OutsideOfSubgroup returns a point on the G2 curve which is not in
+// the subgroup of prime order.
+func bls12381G2OutsideOfSubgroup() bls12381.G2Affine {
+	var b bls12381.E2
+	b.A0.SetUint64(4)
+	b.A1.SetUint64(4)
+	for x := uint64(1); ; x++ {
+		var point bls12381.G2Affine
+		point.X.A0.SetUint64(x)
+		var rhs bls12381.E2
+		rhs.Square(&point.X).Mul(&rhs, &point.X).Add(&rhs, &b)
+		if rhs.Legendre() != 1 {
+			continue
+		}
+		point.Y.Sqrt(&rhs)
+		if point.IsOnCurve() && !point.IsInSubGroup() {
+			return point
+		}
+	}
+}
+
+// invalidLengthSamples produces failing samples by truncating and extending
+// the given valid input.
+func invalidLengthSamples(valid tosca.Data) []Sample {
+	return []Sample{
+		{Input: valid[:len(valid)-1], Fails: true},
+		{Input: concat(valid, []byte{0}), Fails: true},
+	}
+}
+
+// invalidPointSamples produces failing samples by replacing an encoded point
+// of a valid input with invalid encodings. The input is created by the given
+// function from the modified point.
+func invalidPointSamples(point tosca.Data, input func(point tosca.Data) tosca.Data) []Sample {
+	res := []Sample{}
+	for i := range len(point) / bls12381FieldElementLength {
+		res = append(res, invalidFieldElementSamples(point, i)...)
+	}
+	notOnCurve := concat(point)
+	notOnCurve[len(notOnCurve)-1] ^= 1
+	res = append(res, Sample{Input: notOnCurve, Fails: true})
+	for i := range res {
+		res[i].Input = input(res[i].Input)
+	}
+	return res
+}
+
+// invalidFieldElementSamples produces failing samples by replacing the field
+// element with the given index in the data by invalid encodings.
+func invalidFieldElementSamples(data tosca.Data, index int) []Sample {
+	offset := index * bls12381FieldElementLength
+	nonZeroPadding := concat(data)
+	nonZeroPadding[offset+15] = 1
+	exceedingModulus := concat(data)
+	copy(exceedingModulus[offset+16:offset+bls12381FieldElementLength], fp.Modulus().FillBytes(make([]byte, 48)))
+	return []Sample{
+		{Input: nonZeroPadding, Fails: true},
+		{Input: exceedingModulus, Fails: true},
+	}
+}
+
+// encodeBls12381Fp encodes a field element in 64 bytes, the leading 16 of
+// which are zero.
+func encodeBls12381Fp(element fp.Element) tosca.Data {
+	bytes := element.Bytes()
+	return concat(make([]byte, 16), bytes[:])
+}
+
+// encodeBls12381G1 encodes a G1 point as its X and Y coordinates, where the
+// point at infinity is encoded as (0, 0).
+func encodeBls12381G1(point *bls12381.G1Affine) tosca.Data {
+	return concat(encodeBls12381Fp(point.X), encodeBls12381Fp(point.Y))
+}
+
+// encodeBls12381G2 encodes a G2 point as its X and Y coordinates, each of
+// which lists the real part first.
+func encodeBls12381G2(point *bls12381.G2Affine) tosca.Data {
+	return concat(
+		encodeBls12381Fp(point.X.A0), encodeBls12381Fp(point.X.A1),
+		encodeBls12381Fp(point.Y.A0), encodeBls12381Fp(point.Y.A1),
+	)
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package precompiles
+
+import (
+	"math/big"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"pgregory.net/rand"
+)
+
+// getBn254Rules specifies the point addition (0x06), scalar multiplication
+// (0x07), and pairing check (0x08) of the alt_bn128 curve defined by EIP-196
+// and EIP-197, priced according to EIP-1108.
+func getBn254Rules() []Rule {
+	return []Rule{
+		{
+			Name:      "bn256_add",
+			Address:   precompileAddress(0x06),
+			Revisions: since(tosca.R07_Istanbul),
+			Gas:       constantGas(150),
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				a := randomBn254G1(rnd)
+				b := randomBn254G1(rnd)
+				infinity := bn254.G1Affine{}
+				negA := new(bn254.G1Affine).Neg(&a)
+				sample := func(input tosca.Data, x, y *bn254.G1Affine) Sample {
+					return Sample{Input: input, Output: encodeBn254G1(new(bn254.G1Affine).Add(x, y))}
+				}
+				input := concat(encodeBn254G1(&a), encodeBn254G1(&b))
+				return []Sample{
+					sample(input, &a, &b),
+					sample(concat(encodeBn254G1(&a), encodeBn254G1(&a)), &a, &a),
+					sample(concat(encodeBn254G1(&a), encodeBn254G1(negA)), &a, negA),
+					sample(concat(encodeBn254G1(&a), encodeBn254G1(&infinity)), &a, &infinity),
+					sample(nil, &infinity, &infinity),
+					sample(input[:64], &a, &infinity),                 // < truncated second point
+					sample(concat(input, randomData(rnd, 7)), &a, &b), // < excess input is ignored
+				}
+			},
+		},
+		{
+			Name:      "bn256_add_invalid_point",
+			Address:   precompileAddress(0x06),
+			Revisions: since(tosca.R07_Istanbul),
+			Gas:       constantGas(150),
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				valid := randomBn254G1(rnd)
+				res := []Sample{}
+				for _, invalid := range invalidBn254G1Encodings() {
+					res = append(res,
+						Sample{Input: concat(invalid, encodeBn254G1(&valid)), Fails: true},
+						Sample{Input: concat(encodeBn254G1(&valid), invalid), Fails: true},
+					)
+				}
+				return res
+			},
+		},
+		{
+			Name:      "bn256_mul",
+			Address:   precompileAddress(0x07),
+			Revisions: since(tosca.R07_Istanbul),
+			Gas:       constantGas(6000),
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				point := randomBn254G1(rnd)
+				infinity := bn254.G1Affine{}
+				order := fr.Modulus()
+				maxScalar := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+				res := []Sample{}
+				for _, scalar := range []*big.Int{
+					big.NewInt(0),
+					big.NewInt(1),
+					big.NewInt(2),
+					new(big.Int).SetBytes(randomData(rnd, 32)),
+					new(big.Int).Sub(order, big.NewInt(1)),
+					order,
+					maxScalar,
+				} {
+					for _, p := range []*bn254.G1Affine{&point, &infinity} {
+						product := new(bn254.G1Affine).ScalarMultiplication(p, scalar)
+						res = append(res, Sample{
+							Input:  concat(encodeBn254G1(p), scalar.FillBytes(make([]byte, 32))),
+							Output: encodeBn254G1(product),
+						})
+					}
+				}
+
+				// Missing bytes of the scalar are treated as zeros.
+				input := concat(encodeBn254G1(&point), []byte{1})
+				scalar := new(big.Int).Lsh(big.NewInt(1), 248)
+				res = append(res,
+					Sample{Input: input, Output: encodeBn254G1(new(bn254.G1Affine).ScalarMultiplication(&point, scalar))},
+					Sample{Input: nil, Output: encodeBn254G1(&infinity)},
+				)
+				return res
+			},
+		},
+		{
+			Name:      "bn256_mul_invalid_point",
+			Address:   precompileAddress(0x07),
+			Revisions: since(tosca.R07_Istanbul),
+			Gas:       constantGas(6000),
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				res := []Sample{}
+				for _, invalid := range invalidBn254G1Encodings() {
+					res = append(res, Sample{Input: concat(invalid, randomData(rnd, 32)), Fails: true})
+				}
+				return res
+			},
+		},
+		{
+			Name:      "bn256_pairing",
+			Address:   precompileAddress(0x08),
+			Revisions: since(tosca.R07_Istanbul),
+			Gas:       bn254PairingGas,
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				_, _, g1, g2 := bn254.Generators()
+				a := randomBn254Scalar(rnd)
+				b := randomBn254Scalar(rnd)
+				ab := new(big.Int).Mul(a, b)
+
+				// e(a*G1, b*G2) * e(-ab*G1, G2) = 1
+				aG1 := new(bn254.G1Affine).ScalarMultiplication(&g1, a)
+				bG2 := new(bn254.G2Affine).ScalarMultiplication(&g2, b)
+				abG1 := new(bn254.G1Affine).ScalarMultiplication(&g1, ab)
+				negAbG1 := new(bn254.G1Affine).Neg(abG1)
+				valid := concat(
+					encodeBn254G1(aG1), encodeBn254G2(bG2),
+					encodeBn254G1(negAbG1), encodeBn254G2(&g2),
+				)
+				infinity := concat(encodeBn254G1(&bn254.G1Affine{}), encodeBn254G2(&g2))
+				return []Sample{
+					{Input: nil, Output: word(1)},
+					{Input: valid, Output: word(1)},
+					{Input: valid[:192], Output: word(0)},
+					{Input: concat(valid, valid[:192]), Output: word(0)},
+					{Input: infinity, Output: word(1)},
+					{Input: concat(valid, infinity), Output: word(1)},
+				}
+			},
+		},
+		{
+			Name:      "bn256_pairing_invalid_input",
+			Address:   precompileAddress(0x08),
+			Revisions: since(tosca.R07_Istanbul),
+			Gas:       bn254PairingGas,
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				_, _, _, g2 := bn254.Generators()
+				validG1 := randomBn254G1(rnd)
+				valid := concat(encodeBn254G1(&validG1), encodeBn254G2(&g2))
+				res := []Sample{
+					{Input: valid[:191], Fails: true},
+					{Input: concat(valid, []byte{0}), Fails: true},
+				}
+				for _, invalid := range invalidBn254G1Encodings() {
+					res = append(res, Sample{Input: concat(invalid, encodeBn254G2(&g2)), Fails: true})
+				}
+
+				// A G2 point which is not on the curve and a coordinate
+				// exceeding the field modulus.
+				notOnCurve := encodeBn254G2(&g2)
+				notOnCurve[127] ^= 1
+				outOfField := encodeBn254G2(&g2)
+				copy(outOfField[:32], fp.Modulus().FillBytes(make([]byte, 32)))
+				res = append(res,
+					Sample{Input: concat(encodeBn254G1(&validG1), notOnCurve), Fails: true},
+					Sample{Input: concat(encodeBn254G1(&validG1), outOfField), Fails: true},
+				)
+				return res
+			},
+		},
+	}
+}
+
+func bn254PairingGas(_ tosca.Revision, input tosca.Data) uint64 {
+	return 45000 + 34000*uint64(len(input)/192)
+}
+
+func randomBn254Scalar(rnd *rand.Rand) *big.Int {
+	return new(big.Int).SetBytes(randomData(rnd, 32))
+}
+
+func randomBn254G1(rnd *rand.Rand) bn254.G1Affine {
+	var res bn254.G1Affine
+	res.ScalarMultiplicationBase(randomBn254Scalar(rnd))
+	return res
+}
+
+// invalidBn254G1Encodings produces encodings of G1 points which are not on
+// the curve or have coordinates exceeding the field modulus.
+func invalidBn254G1Encodings() []tosca.Data {
+	modulus := fp.Modulus()
+	return []tosca.Data{
+		concat(word(1), word(3)),
+		concat(word(0), word(1)),
+		// (p+1, 2) is the generator (1, 2) if coordinates are not reduced.
+		concat(new(big.Int).Add(modulus, big.NewInt(1)).FillBytes(make([]byte, 32)), word(2)),
+		concat(word(1), new(big.Int).Add(modulus, big.NewInt(2)).FillBytes(make([]byte, 32))),
+	}
+}
+
+// encodeBn254G1 encodes a G1 point as its X and Y coordinates, where the
+// point at infinity is encoded as (0, 0).
+func encodeBn254G1(point *bn254.G1Affine) tosca.Data {
+	x := point.X.Bytes()
+	y := point.Y.Bytes()
+	return concat(x[:], y[:])
+}
+
+// encodeBn254G2 encodes a G2 point as its X and Y coordinates, each of which
+// lists the imaginary part first.
+func encodeBn254G2(point *bn254.G2Affine) tosca.Data {
+	xi := point.X.A1.Bytes()
+	xr := point.X.A0.Bytes()
+	yi := point.Y.A1.Bytes()
+	yr := point.Y.A0.Bytes()
+	return concat(xi[:], xr[:], yi[:], yr[:])
+}
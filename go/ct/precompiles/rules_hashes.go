@@ -0,0 +1,151 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package precompiles
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"golang.org/x/crypto/ripemd160"
+	"pgregory.net/rand"
+)
+
+// getHashRules specifies SHA2-256 (0x02), RIPEMD-160 (0x03), the identity
+// (0x04), and the BLAKE2b compression function F (0x09, EIP-152).
+func getHashRules() []Rule {
+	return []Rule{
+		{
+			Name:      "sha256",
+			Address:   precompileAddress(0x02),
+			Revisions: since(tosca.R07_Istanbul),
+			Gas:       wordGas(60, 12),
+			Samples: hashSamples(func(input tosca.Data) tosca.Data {
+				hash := sha256.Sum256(input)
+				return hash[:]
+			}),
+		},
+		{
+			Name:      "ripemd160",
+			Address:   precompileAddress(0x03),
+			Revisions: since(tosca.R07_Istanbul),
+			Gas:       wordGas(600, 120),
+			Samples: hashSamples(func(input tosca.Data) tosca.Data {
+				hasher := ripemd160.New()
+				hasher.Write(input)
+				return concat(make([]byte, 12), hasher.Sum(nil))
+			}),
+		},
+		{
+			Name:      "identity",
+			Address:   precompileAddress(0x04),
+			Revisions: since(tosca.R07_Istanbul),
+			Gas:       wordGas(15, 3),
+			Samples: hashSamples(func(input tosca.Data) tosca.Data {
+				return concat(input)
+			}),
+		},
+		{
+			Name:      "blake2f",
+			Address:   precompileAddress(0x09),
+			Revisions: since(tosca.R07_Istanbul),
+			Gas:       blake2FGas,
+			Samples: func(tosca.Revision, *rand.Rand) []Sample {
+				res := []Sample{}
+				for _, vector := range blake2FVectors {
+					res = append(res, Sample{
+						Input:  newBlake2FInput(vector.rounds, vector.final),
+						Output: mustDecodeHex(vector.output),
+					})
+				}
+				return res
+			},
+		},
+		{
+			Name:      "blake2f_invalid_input",
+			Address:   precompileAddress(0x09),
+			Revisions: since(tosca.R07_Istanbul),
+			Gas:       blake2FGas,
+			Samples: func(tosca.Revision, *rand.Rand) []Sample {
+				valid := newBlake2FInput(12, true)
+				invalidFlag := newBlake2FInput(12, true)
+				invalidFlag[212] = 2
+				return []Sample{
+					{Input: nil, Fails: true},
+					{Input: valid[:212], Fails: true},
+					{Input: concat(valid, []byte{0}), Fails: true},
+					{Input: invalidFlag, Fails: true},
+				}
+			},
+		},
+	}
+}
+
+// hashSamples produces samples of various lengths for a contract computing
+// the given function of its input.
+func hashSamples(hash func(tosca.Data) tosca.Data) func(tosca.Revision, *rand.Rand) []Sample {
+	return func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+		res := []Sample{}
+		for _, length := range []int{0, 1, 31, 32, 33, 64, 1000, rnd.Intn(1024)} {
+			input := randomData(rnd, length)
+			res = append(res, Sample{Input: input, Output: hash(input)})
+		}
+		return res
+	}
+}
+
+const blake2FInputLength = 213
+
+// blake2FGas charges the number of rounds encoded in well-formed inputs.
+// Malformed inputs are free, since their execution fails anyway.
+func blake2FGas(_ tosca.Revision, input tosca.Data) uint64 {
+	if len(input) != blake2FInputLength {
+		return 0
+	}
+	return uint64(binary.BigEndian.Uint32(input[:4]))
+}
+
+// blake2FVectors are the test vectors 4 to 7 of EIP-152, all of which share
+// the same state, message, and offset counters.
+var blake2FVectors = []struct {
+	rounds uint32
+	final  bool
+	output string
+}{
+	{0, true, "08c9bcf367e6096a3ba7ca8485ae67bb2bf894fe72f36e3cf1361d5f3af54fa5d282e6ad7f520e511f6c3e2b8c68059b9442be0454267ce079217e1319cde05b"},
+	{12, true, "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923"},
+	{12, false, "75ab69d3190a562c51aef8d88f1c2775876944407270c42c9844252c26d2875298743e7f6d5ea2f2d3e8d226039cd31b4e426ac4f2d3d666a610c2116fde4735"},
+	{1, true, "b63a380cb2897d521994a85234ee2c181b5f844d2c624c002677e9703449d2fba551b3a8333bcdf5f2f7e08993d53923de3d64fcc68c034e717b9293fed7a421"},
+}
+
+// newBlake2FInput creates the input of the EIP-152 test vectors with the
+// given number of rounds and final block flag.
+func newBlake2FInput(rounds uint32, final bool) tosca.Data {
+	const state = "48c9bdf267e6096a3ba7ca8485ae67bb2bf894fe72f36e3cf1361d5f3af54fa5d182e6ad7f520e511f6c3e2b8c68059b6bbd41fbabd9831f79217e1319cde05b"
+	message := make([]byte, 128)
+	copy(message, "abc")
+	offsets := make([]byte, 16)
+	offsets[0] = 3
+	flag := byte(0)
+	if final {
+		flag = 1
+	}
+	return concat(binary.BigEndian.AppendUint32(nil, rounds), mustDecodeHex(state), message, offsets, []byte{flag})
+}
+
+func mustDecodeHex(data string) tosca.Data {
+	res, err := hex.DecodeString(data)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package precompiles
+
+import (
+	test_utils "github.com/0xsoniclabs/tosca/go/processor"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"pgregory.net/rand"
+)
+
+// getKzgRules specifies the KZG point evaluation contract (0x0a) introduced
+// by EIP-4844 in Cancun.
+func getKzgRules() []Rule {
+	return []Rule{
+		{
+			Name:      "point_evaluation",
+			Address:   precompileAddress(0x0a),
+			Revisions: since(tosca.R13_Cancun),
+			Gas:       constantGas(50000),
+			Samples: func(tosca.Revision, *rand.Rand) []Sample {
+				// A successful evaluation returns the number of field elements
+				// per blob and the modulus of the BLS12-381 scalar field.
+				return []Sample{{
+					Input:  concat(test_utils.ValidPointEvaluationInput),
+					Output: concat(word(4096), fr.Modulus().FillBytes(make([]byte, 32))),
+				}}
+			},
+		},
+		{
+			Name:      "point_evaluation_invalid_input",
+			Address:   precompileAddress(0x0a),
+			Revisions: since(tosca.R13_Cancun),
+			Gas:       constantGas(50000),
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				valid := test_utils.ValidPointEvaluationInput
+
+				// The input is composed of the versioned hash (32 bytes), the
+				// point z (32 bytes), the claimed value y (32 bytes), the
+				// commitment (48 bytes), and the proof (48 bytes).
+				tamper := func(position int) tosca.Data {
+					res := concat(valid)
+					res[position] ^= 1
+					return res
+				}
+				return []Sample{
+					{Input: nil, Fails: true},
+					{Input: valid[:len(valid)-1], Fails: true},
+					{Input: concat(valid, []byte{0}), Fails: true},
+					{Input: randomData(rnd, len(valid)), Fails: true},
+					{Input: tamper(0), Fails: true},   // < unsupported version
+					{Input: tamper(31), Fails: true},  // < hash not matching the commitment
+					{Input: tamper(95), Fails: true},  // < wrong claimed value
+					{Input: tamper(140), Fails: true}, // < commitment not matching the hash
+					{Input: tamper(190), Fails: true}, // < invalid proof
+				}
+			},
+		},
+	}
+}
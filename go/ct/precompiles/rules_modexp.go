@@ -0,0 +1,239 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package precompiles
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"pgregory.net/rand"
+)
+
+// getModExpRules specifies the modular exponentiation contract (0x05) of
+// EIP-198, priced according to EIP-2565 since Berlin and EIP-7883 since
+// Osaka, with operands limited to 1024 bytes since Osaka (EIP-7823).
+func getModExpRules() []Rule {
+	return []Rule{
+		{
+			Name:      "modexp",
+			Address:   precompileAddress(0x05),
+			Revisions: since(tosca.R07_Istanbul),
+			Gas:       modExpGas,
+			Samples:   modExpSamples,
+		},
+		{
+			Name:      "modexp_oversized_operands",
+			Address:   precompileAddress(0x05),
+			Revisions: since(tosca.R07_Istanbul),
+			Gas:       modExpGas,
+			Samples:   modExpOversizedSamples,
+		},
+	}
+}
+
+const modExpMaxOperandLength = 1024
+
+func modExpSamples(_ tosca.Revision, rnd *rand.Rand) []Sample {
+	res := []Sample{}
+	for _, lengths := range [][3]int{
+		{0, 0, 0}, {1, 1, 1}, {0, 1, 1}, {1, 0, 1}, {1, 1, 0},
+		{32, 32, 32}, {33, 1, 33}, {64, 40, 64}, {100, 200, 300},
+		{modExpMaxOperandLength, 2, modExpMaxOperandLength},
+	} {
+		base := randomData(rnd, lengths[0])
+		exp := randomData(rnd, lengths[1])
+		mod := randomData(rnd, lengths[2])
+		input := newModExpInput(base, exp, mod)
+		res = append(res,
+			modExpSample(input),
+			modExpSample(input[:len(input)-len(mod)/2]), // < truncated modulus
+		)
+	}
+
+	// Special operands.
+	one := []byte{1}
+	zero := make([]byte, 32)
+	for _, operands := range [][3][]byte{
+		{one, randomData(rnd, 32), randomData(rnd, 32)},  // < base one
+		{randomData(rnd, 32), zero, randomData(rnd, 32)}, // < exponent zero
+		{randomData(rnd, 32), randomData(rnd, 32), zero}, // < modulus zero
+		{randomData(rnd, 32), randomData(rnd, 32), one},  // < modulus one
+		{zero, zero, randomData(rnd, 32)},                // < 0^0
+	} {
+		res = append(res, modExpSample(newModExpInput(operands[0], operands[1], operands[2])))
+	}
+
+	// Exponents with leading zeros have a reduced price.
+	exp := concat(make([]byte, 40), randomData(rnd, 24))
+	res = append(res, modExpSample(newModExpInput(randomData(rnd, 16), exp, randomData(rnd, 16))))
+	return res
+}
+
+func modExpOversizedSamples(revision tosca.Revision, rnd *rand.Rand) []Sample {
+	res := []Sample{}
+	for _, lengths := range [][3]uint64{
+		{modExpMaxOperandLength + 1, 1, 1},
+		{1, modExpMaxOperandLength + 1, 1},
+		{1, 1, modExpMaxOperandLength + 1},
+	} {
+		input := concat(word(lengths[0]), word(lengths[1]), word(lengths[2]))
+		input = append(input, randomData(rnd, int(lengths[0]+lengths[1]+lengths[2]))...)
+		sample := modExpSample(input)
+		if revision >= tosca.R15_Osaka {
+			sample = Sample{Input: input, Fails: true}
+		}
+		res = append(res, sample)
+	}
+
+	// Lengths exceeding 64 bits are too expensive before Osaka and fail since.
+	huge := concat([]byte{1}, make([]byte, 31))
+	for _, input := range []tosca.Data{
+		concat(huge, word(1), word(1)),
+		concat(word(1), huge, word(1)),
+		concat(word(1), word(1), huge),
+	} {
+		res = append(res, Sample{Input: input, Fails: true})
+	}
+	return res
+}
+
+func newModExpInput(base, exp, mod []byte) tosca.Data {
+	return concat(
+		word(uint64(len(base))), word(uint64(len(exp))), word(uint64(len(mod))),
+		base, exp, mod,
+	)
+}
+
+// modExpOperands decodes the lengths of the operands of an input. Missing
+// input bytes are treated as zeros.
+func modExpOperands(input tosca.Data) (baseLen, expLen, modLen *big.Int, data tosca.Data) {
+	padded := concat(input, make([]byte, max(0, 96-len(input))))
+	return new(big.Int).SetBytes(padded[0:32]),
+		new(big.Int).SetBytes(padded[32:64]),
+		new(big.Int).SetBytes(padded[64:96]),
+		padded[96:]
+}
+
+// modExpSample computes the output for an input with operands of feasible
+// lengths.
+func modExpSample(input tosca.Data) Sample {
+	baseLenBig, expLenBig, modLenBig, data := modExpOperands(input)
+	baseLen := int(baseLenBig.Int64())
+	expLen := int(expLenBig.Int64())
+	modLen := int(modLenBig.Int64())
+	if baseLen == 0 && modLen == 0 {
+		return Sample{Input: input, Output: tosca.Data{}}
+	}
+	base := new(big.Int).SetBytes(readPadded(data, 0, baseLen))
+	exp := new(big.Int).SetBytes(readPadded(data, baseLen, expLen))
+	mod := new(big.Int).SetBytes(readPadded(data, baseLen+expLen, modLen))
+	output := make(tosca.Data, modLen)
+	if mod.Sign() != 0 {
+		new(big.Int).Exp(base, exp, mod).FillBytes(output)
+	}
+	return Sample{Input: input, Output: output}
+}
+
+// modExpGas computes the costs of a modular exponentiation according to
+// EIP-198, EIP-2565 since Berlin, and EIP-7883 since Osaka.
+func modExpGas(revision tosca.Revision, input tosca.Data) uint64 {
+	baseLen, expLen, modLen, data := modExpOperands(input)
+	maxLen := baseLen
+	if modLen.Cmp(maxLen) > 0 {
+		maxLen = modLen
+	}
+
+	// The adjusted exponent length is derived from the length of the
+	// exponent and the position of the highest bit in its first 32 bytes.
+	head := big.NewInt(0)
+	if baseLen.IsInt64() && baseLen.Int64() < int64(len(data)) {
+		headLen := int64(32)
+		if expLen.IsInt64() {
+			headLen = min(expLen.Int64(), 32)
+		}
+		head.SetBytes(readPadded(data, int(baseLen.Int64()), int(headLen)))
+	}
+	wordIterations := int64(8)
+	if revision >= tosca.R15_Osaka {
+		wordIterations = 16
+	}
+	iterations := big.NewInt(0)
+	if expLen.Cmp(big.NewInt(32)) > 0 {
+		iterations.Sub(expLen, big.NewInt(32))
+		iterations.Mul(iterations, big.NewInt(wordIterations))
+	}
+	if head.BitLen() > 0 {
+		iterations.Add(iterations, big.NewInt(int64(head.BitLen()-1)))
+	}
+	if iterations.Sign() == 0 {
+		iterations.SetInt64(1)
+	}
+
+	words := new(big.Int).Add(maxLen, big.NewInt(7))
+	words.Div(words, big.NewInt(8))
+	gas := new(big.Int)
+	switch {
+	case revision >= tosca.R15_Osaka:
+		complexity := big.NewInt(16)
+		if maxLen.Cmp(big.NewInt(32)) > 0 {
+			complexity.Mul(words, words)
+			complexity.Mul(complexity, big.NewInt(2))
+		}
+		gas.Mul(complexity, iterations)
+		gas = bigMax(gas, big.NewInt(500))
+	case revision >= tosca.R09_Berlin:
+		gas.Mul(words, words)
+		gas.Mul(gas, iterations)
+		gas.Div(gas, big.NewInt(3))
+		gas = bigMax(gas, big.NewInt(200))
+	default:
+		gas.Mul(modExpComplexityEip198(maxLen), iterations)
+		gas.Div(gas, big.NewInt(20))
+	}
+	if !gas.IsUint64() {
+		return math.MaxUint64
+	}
+	return gas.Uint64()
+}
+
+func modExpComplexityEip198(x *big.Int) *big.Int {
+	square := new(big.Int).Mul(x, x)
+	switch {
+	case x.Cmp(big.NewInt(64)) <= 0:
+		return square
+	case x.Cmp(big.NewInt(1024)) <= 0:
+		res := square.Div(square, big.NewInt(4))
+		res.Add(res, new(big.Int).Mul(x, big.NewInt(96)))
+		return res.Sub(res, big.NewInt(3072))
+	default:
+		res := square.Div(square, big.NewInt(16))
+		res.Add(res, new(big.Int).Mul(x, big.NewInt(480)))
+		return res.Sub(res, big.NewInt(199680))
+	}
+}
+
+func bigMax(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
+
+// readPadded reads length bytes at the given offset of the data, filling
+// missing bytes with zeros.
+func readPadded(data []byte, offset, length int) []byte {
+	res := make([]byte, length)
+	if offset < len(data) {
+		copy(res, data[offset:])
+	}
+	return res
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package precompiles
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	cryptorand "crypto/rand"
+	"math/big"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/ethereum/go-ethereum/crypto"
+	"pgregory.net/rand"
+)
+
+// getSignatureRules specifies the recovery of secp256k1 signers (0x01) and
+// the verification of secp256r1 signatures (0x0100) introduced by EIP-7951
+// in Osaka. Invalid signatures do not cause failures, but produce an empty
+// output.
+func getSignatureRules() []Rule {
+	return []Rule{
+		{
+			Name:      "ecrecover",
+			Address:   precompileAddress(0x01),
+			Revisions: since(tosca.R07_Istanbul),
+			Gas:       constantGas(3000),
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				hash := randomData(rnd, 32)
+				v, r, s, address := signSecp256k1(rnd, hash)
+				output := concat(make([]byte, 12), address[:])
+
+				// Signatures with s in the upper half of the order are
+				// accepted, recovering the same signer with flipped parity.
+				highS := new(big.Int).Sub(crypto.S256().Params().N, new(big.Int).SetBytes(s))
+				return []Sample{
+					{Input: concat(hash, word(v), r, s), Output: output},
+					{Input: concat(hash, word(55-v), r, highS.FillBytes(make([]byte, 32))), Output: output},
+					{Input: concat(hash, word(v), r, s, randomData(rnd, 10)), Output: output},
+				}
+			},
+		},
+		{
+			Name:      "ecrecover_invalid_signature",
+			Address:   precompileAddress(0x01),
+			Revisions: since(tosca.R07_Istanbul),
+			Gas:       constantGas(3000),
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				hash := randomData(rnd, 32)
+				v, r, s, _ := signSecp256k1(rnd, hash)
+				n := crypto.S256().Params().N.FillBytes(make([]byte, 32))
+				highV := word(v)
+				highV[0] = 1
+				res := []Sample{}
+				for _, input := range []tosca.Data{
+					nil,
+					concat(hash, word(v), r, s)[:96], // < missing s is zero
+					concat(hash, word(0), r, s),
+					concat(hash, word(1), r, s),
+					concat(hash, word(29), r, s),
+					concat(hash, highV, r, s),
+					concat(hash, word(v), word(0), s),
+					concat(hash, word(v), r, word(0)),
+					concat(hash, word(v), n, s),
+					concat(hash, word(v), r, n),
+				} {
+					res = append(res, Sample{Input: input, Output: tosca.Data{}})
+				}
+				return res
+			},
+		},
+		{
+			Name:      "p256verify",
+			Address:   precompileAddress(0x0100),
+			Revisions: since(tosca.R15_Osaka),
+			Gas:       constantGas(6900),
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				hash := randomData(rnd, 32)
+				r, s, x, y := signSecp256r1(hash)
+
+				// Signatures with s in the upper half of the order are valid.
+				highS := new(big.Int).Sub(elliptic.P256().Params().N, new(big.Int).SetBytes(s))
+				return []Sample{
+					{Input: concat(hash, r, s, x, y), Output: word(1)},
+					{Input: concat(hash, r, highS.FillBytes(make([]byte, 32)), x, y), Output: word(1)},
+				}
+			},
+		},
+		{
+			Name:      "p256verify_invalid_signature",
+			Address:   precompileAddress(0x0100),
+			Revisions: since(tosca.R15_Osaka),
+			Gas:       constantGas(6900),
+			Samples: func(_ tosca.Revision, rnd *rand.Rand) []Sample {
+				hash := randomData(rnd, 32)
+				r, s, x, y := signSecp256r1(hash)
+				params := elliptic.P256().Params()
+				n := params.N.FillBytes(make([]byte, 32))
+				p := params.P.FillBytes(make([]byte, 32))
+				otherHash := concat(hash)
+				otherHash[0] ^= 1
+				notOnCurve := concat(y)
+				notOnCurve[31] ^= 1
+				res := []Sample{}
+				for _, input := range []tosca.Data{
+					nil,
+					concat(hash, r, s, x, y)[:159],
+					concat(hash, r, s, x, y, []byte{0}),
+					concat(otherHash, r, s, x, y),
+					concat(hash, word(0), s, x, y),
+					concat(hash, r, word(0), x, y),
+					concat(hash, n, s, x, y),
+					concat(hash, r, n, x, y),
+					concat(hash, r, s, x, notOnCurve),
+					concat(hash, r, s, p, y),
+					concat(hash, r, s, word(0), word(0)),
+				} {
+					res = append(res, Sample{Input: input, Output: tosca.Data{}})
+				}
+				return res
+			},
+		},
+	}
+}
+
+// signSecp256k1 signs the given hash with a random key and returns the
+// recovery identifier v (27 or 28), the signature values r and s, and the
+// address of the signer.
+func signSecp256k1(rnd *rand.Rand, hash []byte) (uint64, []byte, []byte, tosca.Address) {
+	for {
+		key, err := crypto.ToECDSA(randomData(rnd, 32))
+		if err != nil {
+			continue // < invalid keys are unlikely, but possible
+		}
+		signature, err := crypto.Sign(hash, key)
+		if err != nil {
+			panic(err)
+		}
+		address := crypto.PubkeyToAddress(key.PublicKey)
+		return uint64(signature[64]) + 27, signature[0:32], signature[32:64], tosca.Address(address)
+	}
+}
+
+// signSecp256r1 signs the given hash with a random key and returns the
+// signature values r and s and the coordinates of the public key. The
+// standard library does not support deterministic keys and signatures, so
+// these values are not reproducible by a seed.
+func signSecp256r1(hash []byte) (r, s, x, y []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), cryptorand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	rInt, sInt, err := ecdsa.Sign(cryptorand.Reader, key, hash)
+	if err != nil {
+		panic(err)
+	}
+	public, err := key.PublicKey.Bytes()
+	if err != nil {
+		panic(err)
+	}
+	return rInt.FillBytes(make([]byte, 32)), sInt.FillBytes(make([]byte, 32)), public[1:33], public[33:65]
+}
@@ -0,0 +1,259 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+// Package precompiles provides a conformance test specification for the
+// precompiled contracts defined by Ethereum. The specification is a list of
+// rules, each covering a class of inputs of a single contract. Rules define
+// the gas costs of their inputs and generate sample inputs together with the
+// outcome required for them. Test cases derived from these samples can be
+// checked against any implementation of the precompiled contracts.
+package precompiles
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/0xsoniclabs/tosca/go/ct/rlz"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"pgregory.net/rand"
+)
+
+// Rule specifies the behavior of a precompiled contract for a class of inputs.
+type Rule struct {
+	Name      string
+	Address   tosca.Address
+	Revisions []tosca.Revision
+
+	// Gas computes the costs of running the contract on the given input. If
+	// the costs exceed the range of a uint64, math.MaxUint64 is returned.
+	Gas func(revision tosca.Revision, input tosca.Data) uint64
+
+	// Samples produces inputs covered by this rule for the given revision,
+	// together with the outcome required for them.
+	Samples func(revision tosca.Revision, rnd *rand.Rand) []Sample
+}
+
+// Sample is an input of a precompiled contract and its required outcome,
+// assuming that sufficient gas is provided.
+type Sample struct {
+	Input  tosca.Data
+	Fails  bool       // < if set, the execution fails consuming all gas
+	Output tosca.Data // < the output of a successful execution
+}
+
+// TestCase is a single call of a precompiled contract and its required
+// result.
+type TestCase struct {
+	Rule     string
+	Revision tosca.Revision
+	Address  tosca.Address
+	Input    tosca.Data
+	Gas      tosca.Gas
+	Expected tosca.CallResult
+}
+
+func (c TestCase) String() string {
+	return fmt.Sprintf(
+		"rule %s, revision %v, address %v, gas %d, input 0x%x",
+		c.Rule, c.Revision, c.Address, c.Gas, []byte(c.Input),
+	)
+}
+
+// Check runs the test case on the given implementation and returns an error
+// if the result differs from the expected one.
+func (c TestCase) Check(implementation Implementation) error {
+	got := implementation.Run(c.Revision, c.Address, c.Input, c.Gas)
+	want := c.Expected
+	if got.Success != want.Success || got.GasLeft != want.GasLeft || !bytes.Equal(got.Output, want.Output) {
+		return fmt.Errorf(
+			"%v\n\twant: success %t, gas left %d, output 0x%x\n\tgot:  success %t, gas left %d, output 0x%x",
+			c, want.Success, want.GasLeft, []byte(want.Output), got.Success, got.GasLeft, []byte(got.Output),
+		)
+	}
+	return nil
+}
+
+// Specification is a list of rules defining the behavior of precompiled
+// contracts.
+type Specification struct {
+	rules []Rule
+}
+
+// Spec is the specification of the precompiled contracts defined by Ethereum
+// for all revisions supported by Tosca.
+var Spec = Specification{rules: slices.Concat(
+	getHashRules(),
+	getModExpRules(),
+	getBn254Rules(),
+	getKzgRules(),
+	getBls12381Rules(),
+	getSignatureRules(),
+)}
+
+// GetRules returns all rules of the specification.
+func (s Specification) GetRules() []Rule {
+	return slices.Clone(s.rules)
+}
+
+// Addresses returns the sorted addresses of all contracts available in the
+// given revision.
+func (s Specification) Addresses(revision tosca.Revision) []tosca.Address {
+	res := []tosca.Address{}
+	for _, rule := range s.rules {
+		if slices.Contains(rule.Revisions, revision) && !slices.Contains(res, rule.Address) {
+			res = append(res, rule.Address)
+		}
+	}
+	slices.SortFunc(res, func(a, b tosca.Address) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	return res
+}
+
+// CheckAddresses returns an error if the given implementation does not
+// provide exactly the contracts of the specification in the given revision.
+func (s Specification) CheckAddresses(implementation Implementation, revision tosca.Revision) error {
+	want := s.Addresses(revision)
+	got := implementation.Addresses(revision)
+	if !slices.Equal(want, got) {
+		return fmt.Errorf("unexpected precompiled contracts in revision %v, want %v, got %v", revision, want, got)
+	}
+	return nil
+}
+
+// FilterRules returns the rules which names match the given filter.
+func FilterRules(rules []Rule, filter *regexp.Regexp) []Rule {
+	if filter == nil {
+		return rules
+	}
+	res := make([]Rule, 0, len(rules))
+	for _, rule := range rules {
+		if filter.MatchString(rule.Name) {
+			res = append(res, rule)
+		}
+	}
+	return res
+}
+
+// ForEachTestCase generates the test cases of the given rules and passes
+// them to the given consumer until all cases are consumed or the consumer
+// aborts. For each sample, the contract is called with insufficient gas,
+// with the exact costs, and with excess gas.
+func ForEachTestCase(rules []Rule, seed uint64, consume func(TestCase) rlz.ConsumerResult) {
+	rnd := rand.New(seed)
+	for _, rule := range rules {
+		for _, revision := range rule.Revisions {
+			for _, sample := range rule.Samples(revision, rnd) {
+				for _, testCase := range getTestCases(rule, revision, sample, rnd) {
+					if consume(testCase) == rlz.ConsumeAbort {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// maxTestGas is the maximum gas provided to contracts by test cases, which
+// exceeds any realistic gas limit of a transaction by orders of magnitude.
+const maxTestGas = 1 << 50
+
+func getTestCases(rule Rule, revision tosca.Revision, sample Sample, rnd *rand.Rand) []TestCase {
+	testCase := TestCase{
+		Rule:     rule.Name,
+		Revision: revision,
+		Address:  rule.Address,
+		Input:    sample.Input,
+	}
+	// Costs beyond any realistic gas limit are only tested for failing.
+	cost := rule.Gas(revision, sample.Input)
+	if cost > maxTestGas {
+		testCase.Gas = maxTestGas
+		return []TestCase{testCase}
+	}
+
+	res := []TestCase{}
+	if cost > 0 {
+		testCase.Gas = tosca.Gas(cost - 1)
+		res = append(res, testCase)
+	}
+	for _, excess := range []tosca.Gas{0, tosca.Gas(rnd.Uint64n(10_000) + 1)} {
+		testCase.Gas = tosca.Gas(cost) + excess
+		testCase.Expected = tosca.CallResult{}
+		if !sample.Fails {
+			testCase.Expected = tosca.CallResult{
+				Success: true,
+				Output:  sample.Output,
+				GasLeft: excess,
+			}
+		}
+		res = append(res, testCase)
+	}
+	return res
+}
+
+// since returns all revisions known to Tosca starting with the given one.
+func since(first tosca.Revision) []tosca.Revision {
+	res := []tosca.Revision{}
+	for _, revision := range tosca.GetAllKnownRevisions() {
+		if revision >= first {
+			res = append(res, revision)
+		}
+	}
+	return res
+}
+
+// constantGas returns a gas function charging the given price for all
+// inputs.
+func constantGas(price uint64) func(tosca.Revision, tosca.Data) uint64 {
+	return func(tosca.Revision, tosca.Data) uint64 {
+		return price
+	}
+}
+
+// wordGas returns a gas function charging a base price and a price per
+// started 32-byte word of the input.
+func wordGas(base, perWord uint64) func(tosca.Revision, tosca.Data) uint64 {
+	return func(_ tosca.Revision, input tosca.Data) uint64 {
+		return base + perWord*uint64((len(input)+31)/32)
+	}
+}
+
+// precompileAddress returns the address of the precompiled contract with the
+// given number.
+func precompileAddress(number uint16) tosca.Address {
+	return tosca.Address{18: byte(number >> 8), 19: byte(number)}
+}
+
+// randomData produces random data of the given length.
+func randomData(rnd *rand.Rand, length int) tosca.Data {
+	res := make(tosca.Data, length)
+	rnd.Read(res)
+	return res
+}
+
+// word encodes the given value as a 32-byte big-endian word.
+func word(value uint64) []byte {
+	res := make([]byte, 32)
+	for i := range 8 {
+		res[31-i] = byte(value >> (8 * i))
+	}
+	return res
+}
+
+func concat(parts ...[]byte) tosca.Data {
+	res := tosca.Data{}
+	for _, part := range parts {
+		res = append(res, part...)
+	}
+	return res
+}
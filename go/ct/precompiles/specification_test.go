@@ -0,0 +1,104 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package precompiles
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/0xsoniclabs/tosca/go/ct/rlz"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"pgregory.net/rand"
+)
+
+func TestSpecification_RuleNamesAreUnique(t *testing.T) {
+	seen := map[string]bool{}
+	for _, rule := range Spec.GetRules() {
+		if seen[rule.Name] {
+			t.Errorf("duplicate rule name %q", rule.Name)
+		}
+		seen[rule.Name] = true
+	}
+}
+
+func TestSpecification_AllRulesProduceSamples(t *testing.T) {
+	rnd := rand.New(0)
+	for _, rule := range Spec.GetRules() {
+		if len(rule.Revisions) == 0 {
+			t.Errorf("rule %s covers no revision", rule.Name)
+		}
+		for _, revision := range rule.Revisions {
+			if len(rule.Samples(revision, rnd)) == 0 {
+				t.Errorf("rule %s produces no samples for %v", rule.Name, revision)
+			}
+		}
+	}
+}
+
+func TestSpecification_AddressesDependOnRevision(t *testing.T) {
+	tests := map[tosca.Revision]int{
+		tosca.R07_Istanbul: 9,
+		tosca.R12_Shanghai: 9,
+		tosca.R13_Cancun:   10,
+		tosca.R14_Prague:   17,
+		tosca.R15_Osaka:    18,
+	}
+	for revision, want := range tests {
+		if got := len(Spec.Addresses(revision)); got != want {
+			t.Errorf("unexpected number of contracts in %v, want %d, got %d", revision, want, got)
+		}
+	}
+}
+
+func TestFilterRules_SelectsRulesByName(t *testing.T) {
+	rules := FilterRules(Spec.GetRules(), regexp.MustCompile("^bn256_"))
+	if len(rules) != 6 {
+		t.Fatalf("unexpected number of rules, want 6, got %d", len(rules))
+	}
+	if got := FilterRules(Spec.GetRules(), nil); len(got) != len(Spec.GetRules()) {
+		t.Errorf("nil filter should keep all rules")
+	}
+}
+
+func TestForEachTestCase_CoversGasLevels(t *testing.T) {
+	rules := FilterRules(Spec.GetRules(), regexp.MustCompile("^identity$"))
+	cases := []TestCase{}
+	ForEachTestCase(rules, 0, func(testCase TestCase) rlz.ConsumerResult {
+		cases = append(cases, testCase)
+		return rlz.ConsumeContinue
+	})
+	if len(cases) == 0 || len(cases)%3 != 0 {
+		t.Fatalf("expected three cases per sample, got %d", len(cases))
+	}
+	for i := 0; i < len(cases); i += 3 {
+		short, exact, excess := cases[i], cases[i+1], cases[i+2]
+		if short.Expected.Success {
+			t.Errorf("insufficient gas should fail: %v", short)
+		}
+		if !exact.Expected.Success || exact.Expected.GasLeft != 0 {
+			t.Errorf("exact gas should succeed without gas left: %v", exact)
+		}
+		if !excess.Expected.Success || excess.Expected.GasLeft != excess.Gas-exact.Gas {
+			t.Errorf("excess gas should be returned: %v", excess)
+		}
+	}
+}
+
+func TestForEachTestCase_StopsOnAbort(t *testing.T) {
+	count := 0
+	ForEachTestCase(Spec.GetRules(), 0, func(TestCase) rlz.ConsumerResult {
+		count++
+		return rlz.ConsumeAbort
+	})
+	if count != 1 {
+		t.Errorf("expected a single case before aborting, got %d", count)
+	}
+}
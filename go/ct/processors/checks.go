@@ -0,0 +1,254 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package processors
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"pgregory.net/rand"
+)
+
+// check is a validity condition of transactions. Transactions violating any
+// check are rejected.
+type check struct {
+	name      string
+	revisions []tosca.Revision
+	violated  func(Input) bool
+
+	// violate modifies a valid input such that it violates this check, but
+	// no other.
+	violate func(input *Input, rnd *rand.Rand)
+}
+
+// getChecks lists the validity checks of transactions on the given chain.
+// Processors may evaluate them in any order.
+func getChecks(chain Chain) []check {
+	return []check{
+		{
+			name:      "nonce_too_low",
+			revisions: allRevisions,
+			violated: func(input Input) bool {
+				return input.Transaction.Nonce < input.State[input.Transaction.Sender].Nonce
+			},
+			violate: func(input *Input, rnd *rand.Rand) {
+				sender := input.State[input.Transaction.Sender]
+				sender.Nonce = input.Transaction.Nonce + 1 + rnd.Uint64n(10)
+				input.State[input.Transaction.Sender] = sender
+			},
+		},
+		{
+			name:      "nonce_too_high",
+			revisions: allRevisions,
+			violated: func(input Input) bool {
+				return input.Transaction.Nonce > input.State[input.Transaction.Sender].Nonce
+			},
+			violate: func(input *Input, rnd *rand.Rand) {
+				input.Transaction.Nonce += 1 + rnd.Uint64n(10)
+			},
+		},
+		{
+			name:      "sender_not_eoa",
+			revisions: allRevisions,
+			violated: func(input Input) bool {
+				return len(input.State[input.Transaction.Sender].Code) > 0
+			},
+			violate: func(input *Input, rnd *rand.Rand) {
+				sender := input.State[input.Transaction.Sender]
+				sender.Code = randomCode(rnd)
+				input.State[input.Transaction.Sender] = sender
+			},
+		},
+		{
+			name:      "blob_transaction_creating_contract",
+			revisions: revisionsSince(tosca.R13_Cancun),
+			violated: func(input Input) bool {
+				return input.Transaction.BlobHashes != nil && input.Transaction.Recipient == nil
+			},
+			violate: func(input *Input, rnd *rand.Rand) {
+				input.Transaction.Recipient = nil
+				input.Transaction.Input = bytes.Clone(deployCode)
+				input.Transaction.GasLimit = intrinsicGas(input.Block.Revision, input.Transaction) + 10_000
+				setBlobs(input, rnd, 1+rnd.Intn(maxBlobsPerTransaction))
+				fundSender(input, rnd)
+			},
+		},
+		{
+			name:      "blob_transaction_without_blobs",
+			revisions: revisionsSince(tosca.R13_Cancun),
+			violated: func(input Input) bool {
+				return input.Transaction.BlobHashes != nil && len(input.Transaction.BlobHashes) == 0
+			},
+			violate: func(input *Input, rnd *rand.Rand) {
+				input.Transaction.BlobHashes = []tosca.Hash{}
+				input.Transaction.BlobGasFeeCap = input.Block.BlobBaseFee
+			},
+		},
+		{
+			name:      "blob_with_invalid_version",
+			revisions: revisionsSince(tosca.R13_Cancun),
+			violated: func(input Input) bool {
+				for _, hash := range input.Transaction.BlobHashes {
+					if hash[0] != blobHashVersion {
+						return true
+					}
+				}
+				return false
+			},
+			violate: func(input *Input, rnd *rand.Rand) {
+				setBlobs(input, rnd, 1+rnd.Intn(maxBlobsPerTransaction))
+				hash := &input.Transaction.BlobHashes[rnd.Intn(len(input.Transaction.BlobHashes))]
+				hash[0] = byte(2 + rnd.Intn(254))
+				fundSender(input, rnd)
+			},
+		},
+		{
+			name:      "blob_fee_cap_below_blob_base_fee",
+			revisions: revisionsSince(tosca.R13_Cancun),
+			violated: func(input Input) bool {
+				return len(input.Transaction.BlobHashes) > 0 &&
+					input.Transaction.BlobGasFeeCap.Cmp(input.Block.BlobBaseFee) < 0
+			},
+			violate: func(input *Input, rnd *rand.Rand) {
+				setBlobs(input, rnd, 1+rnd.Intn(maxBlobsPerTransaction))
+				input.Transaction.BlobGasFeeCap = tosca.Sub(input.Block.BlobBaseFee, tosca.NewValue(1+rnd.Uint64n(input.Block.BlobBaseFee.ToUint256().Uint64())))
+				fundSender(input, rnd)
+			},
+		},
+		{
+			name:      "init_code_too_large",
+			revisions: revisionsSince(tosca.R12_Shanghai),
+			violated: func(input Input) bool {
+				return input.Block.Revision >= tosca.R12_Shanghai &&
+					input.Transaction.Recipient == nil && len(input.Transaction.Input) > maxInitCodeSize
+			},
+			violate: func(input *Input, rnd *rand.Rand) {
+				input.Transaction.Recipient = nil
+				padding := maxInitCodeSize + 1 - len(deployCode) + rnd.Intn(100)
+				input.Transaction.Input = append(bytes.Clone(deployCode), make([]byte, padding)...)
+				input.Transaction.GasLimit = intrinsicGas(input.Block.Revision, input.Transaction) + 10_000
+				fundSender(input, rnd)
+			},
+		},
+		{
+			name:      "fee_cap_below_base_fee",
+			revisions: revisionsSince(tosca.R10_London),
+			violated: func(input Input) bool {
+				return input.Transaction.GasFeeCap.Cmp(input.Block.BaseFee) < 0
+			},
+			violate: func(input *Input, rnd *rand.Rand) {
+				baseFee := input.Block.BaseFee.ToUint256().Uint64()
+				input.Transaction.GasFeeCap = tosca.NewValue(rnd.Uint64n(baseFee))
+				input.Transaction.GasTipCap = tosca.NewValue(rnd.Uint64n(input.Transaction.GasFeeCap.ToUint256().Uint64() + 1))
+			},
+		},
+		{
+			name:      "tip_cap_above_fee_cap",
+			revisions: revisionsSince(tosca.R10_London),
+			violated: func(input Input) bool {
+				return input.Transaction.GasFeeCap.Cmp(input.Transaction.GasTipCap) < 0
+			},
+			violate: func(input *Input, rnd *rand.Rand) {
+				input.Transaction.GasTipCap = tosca.Add(input.Transaction.GasFeeCap, tosca.NewValue(1+rnd.Uint64n(100)))
+			},
+		},
+		{
+			name:      "insufficient_balance",
+			revisions: allRevisions,
+			violated: func(input Input) bool {
+				return input.State[input.Transaction.Sender].Balance.ToBig().Cmp(requiredBalance(chain, input)) < 0
+			},
+			violate: func(input *Input, rnd *rand.Rand) {
+				required := requiredBalance(chain, *input)
+				deficit := new(big.Int).SetUint64(1 + rnd.Uint64n(required.Uint64()))
+				sender := input.State[input.Transaction.Sender]
+				sender.Balance = bigToValue(new(big.Int).Sub(required, deficit))
+				input.State[input.Transaction.Sender] = sender
+			},
+		},
+		{
+			name:      "intrinsic_gas_too_low",
+			revisions: allRevisions,
+			violated: func(input Input) bool {
+				return input.Transaction.GasLimit < intrinsicGas(input.Block.Revision, input.Transaction)
+			},
+			violate: func(input *Input, rnd *rand.Rand) {
+				intrinsic := intrinsicGas(input.Block.Revision, input.Transaction)
+				input.Transaction.GasLimit = intrinsic - 1 - tosca.Gas(rnd.Uint64n(uint64(intrinsic)))
+			},
+		},
+	}
+}
+
+// getRejectionRules specifies the rejection of transactions violating any of
+// the validity checks of the given chain.
+func getRejectionRules(chain Chain) []Rule {
+	checks := getChecks(chain)
+	res := []Rule{}
+	for _, current := range checks {
+		res = append(res, Rule{
+			Name:      current.name,
+			Revisions: current.revisions,
+			Condition: func(input Input) bool {
+				return current.violated(input)
+			},
+			Effect: func(Input) Outcome {
+				return Outcome{Rejected: true}
+			},
+			Inputs: func(revision tosca.Revision, rnd *rand.Rand) []Input {
+				res := []Input{}
+				for _, kind := range []transactionKind{transferTransaction, contractCallTransaction, createTransaction} {
+					input := newValidInput(chain, revision, kind, rnd)
+					current.violate(&input, rnd)
+					if !current.violated(input) || countViolations(checks, input) != 1 {
+						continue // < the modification is not applicable to this kind
+					}
+					res = append(res, input)
+				}
+				return res
+			},
+		})
+	}
+	return res
+}
+
+// isValid returns true if the input satisfies all checks of the given chain.
+func isValid(chain Chain, input Input) bool {
+	return countViolations(getChecks(chain), input) == 0
+}
+
+func countViolations(checks []check, input Input) int {
+	count := 0
+	for _, check := range checks {
+		if check.violated(input) {
+			count++
+		}
+	}
+	return count
+}
+
+// requiredBalance computes the balance the sender of a transaction must at
+// least have. On Ethereum, the gas fee cap and the transferred value are
+// covered, while Sonic only requires the effective gas price.
+func requiredBalance(chain Chain, input Input) *big.Int {
+	tx := input.Transaction
+	price := tx.GasFeeCap
+	if chain == Sonic {
+		price = gasPrice(input.Block, tx)
+	}
+	res := new(big.Int).Mul(price.ToBig(), big.NewInt(int64(tx.GasLimit)))
+	if chain == Ethereum {
+		res.Add(res, tx.Value.ToBig())
+	}
+	blobFee := new(big.Int).Mul(tx.BlobGasFeeCap.ToBig(), new(big.Int).SetUint64(blobGas(tx)))
+	return res.Add(res, blobFee)
+}
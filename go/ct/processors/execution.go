@@ -0,0 +1,647 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package processors
+
+import (
+	"bytes"
+	"math/big"
+	"slices"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"pgregory.net/rand"
+)
+
+// execution is the result of the top-level call or create of a transaction.
+type execution struct {
+	success bool
+	gasLeft tosca.Gas
+	refund  tosca.Gas
+	output  tosca.Data
+	logs    []tosca.Log
+	state   WorldState
+}
+
+// process models the processing of a valid transaction. The given function
+// models the top-level call or create, starting on the state after buying
+// gas with the gas remaining after the intrinsic costs.
+func process(chain Chain, input Input, execute func(state WorldState, gas tosca.Gas) execution) Outcome {
+	tx := input.Transaction
+	block := input.Block
+	state := input.State.Clone()
+	price := gasPrice(block, tx)
+
+	sender := state[tx.Sender]
+	cost := tosca.Add(price.Scale(uint64(tx.GasLimit)), block.BlobBaseFee.Scale(blobGas(tx)))
+	sender.Balance = tosca.Sub(sender.Balance, cost)
+	if tx.Recipient != nil {
+		sender.Nonce++
+	}
+	state[tx.Sender] = sender
+
+	result := execute(state, tx.GasLimit-intrinsicGas(block.Revision, tx))
+	state = result.state
+
+	// Sonic charges 10% of the unused gas to discourage over-estimation.
+	gasLeft := result.gasLeft
+	if chain == Sonic {
+		gasLeft -= gasLeft / 10
+	}
+
+	// EIP-3529 reduced the refund cap from a half to a fifth of the used gas.
+	if result.success {
+		maxRefund := (tx.GasLimit - gasLeft) / 2
+		if block.Revision >= tosca.R10_London {
+			maxRefund = (tx.GasLimit - gasLeft) / 5
+		}
+		gasLeft += min(result.refund, maxRefund)
+	}
+	gasUsed := tx.GasLimit - gasLeft
+
+	sender = state[tx.Sender]
+	sender.Balance = tosca.Add(sender.Balance, price.Scale(uint64(gasLeft)))
+	state[tx.Sender] = sender
+
+	// Since London, the base fee is burned and only the tip is paid to the
+	// coinbase. Sonic does not pay fees to the coinbase at all.
+	if chain == Ethereum {
+		tip := price
+		if block.Revision >= tosca.R10_London {
+			tip = tosca.Sub(price, block.BaseFee)
+		}
+		coinbase := state[block.Coinbase]
+		coinbase.Balance = tosca.Add(coinbase.Balance, tip.Scale(uint64(gasUsed)))
+		state[block.Coinbase] = coinbase
+	}
+
+	receipt := tosca.Receipt{
+		Success: result.success,
+		GasUsed: gasUsed,
+		Output:  result.output,
+		Logs:    result.logs,
+	}
+	if tx.Recipient == nil && result.success {
+		created := createdAddress(tx)
+		receipt.ContractAddress = &created
+	}
+	return Outcome{Receipt: receipt, State: state}
+}
+
+// getExecutionRules specifies the processing of valid transactions on the
+// given chain.
+func getExecutionRules(chain Chain) []Rule {
+	isValidCall := func(input Input) bool {
+		return input.Transaction.Recipient != nil && isValid(chain, input)
+	}
+	isValidCreate := func(input Input) bool {
+		return input.Transaction.Recipient == nil && isValid(chain, input)
+	}
+
+	res := []Rule{}
+	if chain == Sonic {
+		// Sonic does not require the sender to cover the transferred value
+		// in the balance check. Thus, the top-level call or create may fail.
+		res = append(res,
+			Rule{
+				Name:      "call_with_insufficient_value",
+				Revisions: allRevisions,
+				Condition: func(input Input) bool {
+					return isValidCall(input) && !canTransferValue(input)
+				},
+				Effect: func(input Input) Outcome {
+					return process(chain, input, failedTransfer)
+				},
+				Inputs: func(revision tosca.Revision, rnd *rand.Rand) []Input {
+					return []Input{
+						withInsufficientValue(newValidInput(chain, revision, transferTransaction, rnd), rnd),
+						withInsufficientValue(newValidInput(chain, revision, contractCallTransaction, rnd), rnd),
+					}
+				},
+			},
+			Rule{
+				Name:      "create_with_insufficient_value",
+				Revisions: allRevisions,
+				Condition: func(input Input) bool {
+					return isValidCreate(input) && !canTransferValue(input)
+				},
+				Effect: func(input Input) Outcome {
+					return process(chain, input, failedTransfer)
+				},
+				Inputs: func(revision tosca.Revision, rnd *rand.Rand) []Input {
+					return []Input{
+						withInsufficientValue(newValidInput(chain, revision, createTransaction, rnd), rnd),
+					}
+				},
+			},
+		)
+	}
+
+	res = append(res, Rule{
+		Name:      "value_transfer",
+		Revisions: allRevisions,
+		Condition: func(input Input) bool {
+			return isValidCall(input) && canTransferValue(input) &&
+				findCallTemplate(input) == nil && findCaller(input) == nil
+		},
+		Effect: func(input Input) Outcome {
+			return process(chain, input, call(input, nil))
+		},
+		Inputs: func(revision tosca.Revision, rnd *rand.Rand) []Input {
+			return transferInputs(chain, revision, rnd)
+		},
+	})
+
+	for _, template := range append(slices.Clone(callTemplates), identityContract) {
+		res = append(res, Rule{
+			Name:      "call_" + template.name,
+			Revisions: allRevisions,
+			Condition: func(input Input) bool {
+				if !isValidCall(input) || !canTransferValue(input) {
+					return false
+				}
+				target := findCallTemplate(input)
+				return target != nil && target.name == template.name
+			},
+			Effect: func(input Input) Outcome {
+				return process(chain, input, call(input, findCallTemplate(input)))
+			},
+			Inputs: func(revision tosca.Revision, rnd *rand.Rand) []Input {
+				return callInputs(chain, revision, template, rnd)
+			},
+		})
+	}
+
+	for _, scenario := range nestedScenarios {
+		res = append(res, Rule{
+			Name:      "nested_" + scenario,
+			Revisions: allRevisions,
+			Condition: func(input Input) bool {
+				return isValidCall(input) && canTransferValue(input) && getNestedScenario(input) == scenario
+			},
+			Effect: func(input Input) Outcome {
+				return process(chain, input, callFrames(input))
+			},
+			Inputs: func(revision tosca.Revision, rnd *rand.Rand) []Input {
+				return nestedInputs(chain, revision, scenario, rnd)
+			},
+		})
+	}
+
+	res = append(res, Rule{
+		Name:      "create_collision",
+		Revisions: allRevisions,
+		Condition: func(input Input) bool {
+			return isValidCreate(input) && canTransferValue(input) &&
+				!input.State[createdAddress(input.Transaction)].isEmpty()
+		},
+		Effect: func(input Input) Outcome {
+			return process(chain, input, create(input, nil))
+		},
+		Inputs: func(revision tosca.Revision, rnd *rand.Rand) []Input {
+			return collisionInputs(chain, revision, rnd)
+		},
+	})
+
+	for _, template := range initCodeTemplates {
+		res = append(res, Rule{
+			Name:      "create_" + template.name,
+			Revisions: allRevisions,
+			Condition: func(input Input) bool {
+				if !isValidCreate(input) || !canTransferValue(input) ||
+					!input.State[createdAddress(input.Transaction)].isEmpty() {
+					return false
+				}
+				initCode := findInitCodeTemplate(input)
+				return initCode != nil && initCode.name == template.name
+			},
+			Effect: func(input Input) Outcome {
+				return process(chain, input, create(input, findInitCodeTemplate(input)))
+			},
+			Inputs: func(revision tosca.Revision, rnd *rand.Rand) []Input {
+				return createInputs(chain, revision, template, rnd)
+			},
+		})
+	}
+	return res
+}
+
+// ----------------------------------------------------------------------------
+
+// status is the way a contract execution ends.
+type status int
+
+const (
+	succeeded status = iota
+	reverted
+	aborted // < consumes all gas
+)
+
+// contract is a code template used by generated inputs along with a model
+// of its execution.
+type contract struct {
+	name string
+	code tosca.Code
+
+	// cost is the gas consumed by running the contract to completion. With
+	// less gas, the execution is aborted.
+	cost func(input Input) tosca.Gas
+
+	// required is the gas needed to start the execution if it exceeds the
+	// cost, or nil otherwise.
+	required func(input Input) tosca.Gas
+
+	// effect models a completed execution at the given address by modifying
+	// the given state.
+	effect func(input Input, state WorldState, address tosca.Address) (status, tosca.Data, tosca.Gas)
+
+	// logs are the logs emitted by a successful execution at the given
+	// address, or nil if the contract emits no logs.
+	logs func(address tosca.Address) []tosca.Log
+}
+
+var (
+	returnWordCode = code(vm.PUSH1, 42, vm.PUSH1, 0, vm.MSTORE, vm.PUSH1, 32, vm.PUSH1, 0, vm.RETURN)
+	revertCode     = code(vm.PUSH1, 42, vm.PUSH1, 0, vm.MSTORE, vm.PUSH1, 32, vm.PUSH1, 0, vm.REVERT)
+	deployCode     = code(vm.PUSH1, 32, vm.PUSH1, 0, vm.RETURN)
+	logCode        = code(vm.PUSH1, 42, vm.PUSH1, 0, vm.MSTORE, vm.PUSH1, 7, vm.PUSH1, 32, vm.PUSH1, 0, vm.LOG1, vm.STOP)
+)
+
+var (
+	returnWordContract = contract{
+		name: "return_word",
+		code: returnWordCode,
+		cost: func(Input) tosca.Gas { return 18 },
+		effect: func(Input, WorldState, tosca.Address) (status, tosca.Data, tosca.Gas) {
+			return succeeded, word(42), 0
+		},
+	}
+
+	revertContract = contract{
+		name: "revert",
+		code: revertCode,
+		cost: func(Input) tosca.Gas { return 18 },
+		effect: func(Input, WorldState, tosca.Address) (status, tosca.Data, tosca.Gas) {
+			return reverted, word(42), 0
+		},
+	}
+
+	// logContract emits a log with a single topic and a word of data.
+	logContract = contract{
+		name: "log",
+		code: logCode,
+		cost: func(Input) tosca.Gas { return 21 + 375 + 375 + 8*32 },
+		effect: func(Input, WorldState, tosca.Address) (status, tosca.Data, tosca.Gas) {
+			return succeeded, nil, 0
+		},
+		logs: func(address tosca.Address) []tosca.Log {
+			return []tosca.Log{{
+				Address: address,
+				Topics:  []tosca.Hash{{31: 7}},
+				Data:    word(42),
+			}}
+		},
+	}
+
+	invalidContract = contract{
+		name: "invalid",
+		code: code(vm.INVALID),
+		cost: func(Input) tosca.Gas { return 0 },
+		effect: func(Input, WorldState, tosca.Address) (status, tosca.Data, tosca.Gas) {
+			return aborted, nil, 0
+		},
+	}
+
+	// clearSlotContract resets storage slot 1, which is the only template
+	// covering gas refunds and the EIP-2200 call stipend sentry.
+	clearSlotContract = contract{
+		name: "clear_slot",
+		code: code(vm.PUSH1, 0, vm.PUSH1, 1, vm.SSTORE, vm.STOP),
+		cost: func(input Input) tosca.Gas {
+			cost, _ := clearSlotCosts(input)
+			return 6 + cost
+		},
+		required: func(input Input) tosca.Gas {
+			cost, _ := clearSlotCosts(input)
+			return 6 + max(cost, sstoreSentryGas+1)
+		},
+		effect: func(input Input, state WorldState, address tosca.Address) (status, tosca.Data, tosca.Gas) {
+			_, refund := clearSlotCosts(input)
+			account := state[address]
+			delete(account.Storage, clearedSlot)
+			state[address] = account
+			return succeeded, nil, refund
+		},
+	}
+
+	// identityContract models calls to the identity precompiled contract.
+	identityContract = contract{
+		name: "identity",
+		cost: func(input Input) tosca.Gas {
+			return tosca.Gas(15 + 3*tosca.SizeInWords(uint64(len(input.Transaction.Input))))
+		},
+		effect: func(input Input, _ WorldState, _ tosca.Address) (status, tosca.Data, tosca.Gas) {
+			return succeeded, bytes.Clone(input.Transaction.Input), 0
+		},
+	}
+
+	// deployContract is init code deploying 32 zero bytes.
+	deployContract = contract{
+		name: "deploy",
+		code: deployCode,
+		cost: func(Input) tosca.Gas { return 9 },
+		effect: func(Input, WorldState, tosca.Address) (status, tosca.Data, tosca.Gas) {
+			return succeeded, make(tosca.Data, 32), 0
+		},
+	}
+)
+
+// callTemplates are the contracts installed at the recipients of calls.
+var callTemplates = []contract{
+	returnWordContract,
+	revertContract,
+	logContract,
+	invalidContract,
+	clearSlotContract,
+}
+
+// initCodeTemplates are the init codes of contract creations.
+var initCodeTemplates = []contract{
+	deployContract,
+	revertContract,
+}
+
+const (
+	sstoreSentryGas      = 2300
+	createGasCostPerByte = 200
+	coldSloadCost        = 2100
+)
+
+var (
+	clearedSlot     = tosca.Key{31: 1}
+	identityAddress = tosca.Address{19: 0x04}
+)
+
+// clearSlotCosts computes the gas costs and the refund of clearing storage
+// slot 1 of the recipient of the given input.
+func clearSlotCosts(input Input) (tosca.Gas, tosca.Gas) {
+	revision := input.Block.Revision
+	recipient := *input.Transaction.Recipient
+	isSet := input.State[recipient].Storage[clearedSlot] != (tosca.Word{})
+
+	if revision < tosca.R09_Berlin {
+		if isSet {
+			return 5000, 15000
+		}
+		return 800, 0
+	}
+
+	cost := tosca.Gas(100)
+	if isSet {
+		cost = 5000 - coldSloadCost
+	}
+	if !isInAccessList(input.Transaction.AccessList, recipient, clearedSlot) {
+		cost += coldSloadCost
+	}
+	refund := tosca.Gas(0)
+	if isSet {
+		refund = 15000
+		if revision >= tosca.R10_London {
+			refund = 4800
+		}
+	}
+	return cost, refund
+}
+
+func isInAccessList(list []tosca.AccessTuple, address tosca.Address, key tosca.Key) bool {
+	for _, tuple := range list {
+		if tuple.Address == address {
+			for _, cur := range tuple.Keys {
+				if cur == key {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// emittedLogs returns the logs emitted by a successful execution of the
+// contract at the given address.
+func (c *contract) emittedLogs(address tosca.Address) []tosca.Log {
+	if c.logs == nil {
+		return nil
+	}
+	return c.logs(address)
+}
+
+func (c *contract) requiredGas(input Input) tosca.Gas {
+	if c.required != nil {
+		return c.required(input)
+	}
+	return c.cost(input)
+}
+
+// findCallTemplate returns the contract called by the given input, or nil if
+// the recipient has no code.
+func findCallTemplate(input Input) *contract {
+	recipient := *input.Transaction.Recipient
+	if recipient == identityAddress {
+		return &identityContract
+	}
+	code := input.State[recipient].Code
+	for i := range callTemplates {
+		if bytes.Equal(callTemplates[i].code, code) {
+			return &callTemplates[i]
+		}
+	}
+	return nil
+}
+
+// findInitCodeTemplate returns the template the init code of the given input
+// starts with, or nil if there is none.
+func findInitCodeTemplate(input Input) *contract {
+	for i := range initCodeTemplates {
+		if bytes.HasPrefix(input.Transaction.Input, initCodeTemplates[i].code) {
+			return &initCodeTemplates[i]
+		}
+	}
+	return nil
+}
+
+// ----------------------------------------------------------------------------
+
+// call models the top-level call of a transaction with a sufficient balance
+// for the transferred value.
+func call(input Input, target *contract) func(WorldState, tosca.Gas) execution {
+	return func(state WorldState, gas tosca.Gas) execution {
+		tx := input.Transaction
+		backup := state.Clone()
+		transfer(state, tx.Sender, *tx.Recipient, tx.Value)
+		if target == nil {
+			return execution{success: true, gasLeft: gas, state: state}
+		}
+		return run(input, target, state, backup, *tx.Recipient, gas)
+	}
+}
+
+// create models the top-level create of a transaction with a sufficient
+// balance for the transferred value.
+func create(input Input, initCode *contract) func(WorldState, tosca.Gas) execution {
+	return func(state WorldState, gas tosca.Gas) execution {
+		tx := input.Transaction
+		address := createdAddress(tx)
+
+		sender := state[tx.Sender]
+		sender.Nonce++
+		state[tx.Sender] = sender
+		if !state[address].isEmpty() {
+			return execution{state: state}
+		}
+
+		backup := state.Clone()
+		state[address] = Account{Balance: state[address].Balance, Nonce: 1}
+		transfer(state, tx.Sender, address, tx.Value)
+		res := run(input, initCode, state, backup, address, gas)
+		if !res.success {
+			return res
+		}
+
+		deploymentCost := tosca.Gas(len(res.output) * createGasCostPerByte)
+		if res.gasLeft < deploymentCost {
+			return execution{state: backup}
+		}
+		account := res.state[address]
+		account.Code = tosca.Code(bytes.Clone(res.output))
+		res.state[address] = account
+		res.gasLeft -= deploymentCost
+		return res
+	}
+}
+
+// failedTransfer models a top-level call or create which can not transfer
+// the value of the transaction. This fails without consuming gas.
+func failedTransfer(state WorldState, gas tosca.Gas) execution {
+	return execution{gasLeft: gas, state: state}
+}
+
+// run models the execution of a contract at the given address. The backup is
+// restored on failures.
+func run(input Input, target *contract, state, backup WorldState, address tosca.Address, gas tosca.Gas) execution {
+	cost := target.cost(input)
+	if gas < target.requiredGas(input) {
+		return execution{state: backup}
+	}
+	status, output, refund := target.effect(input, state, address)
+	switch status {
+	case succeeded:
+		return execution{success: true, gasLeft: gas - cost, refund: refund, output: output, logs: target.emittedLogs(address), state: state}
+	case reverted:
+		return execution{gasLeft: gas - cost, output: output, state: backup}
+	}
+	return execution{state: backup}
+}
+
+func transfer(state WorldState, from, to tosca.Address, value tosca.Value) {
+	sender := state[from]
+	sender.Balance = tosca.Sub(sender.Balance, value)
+	state[from] = sender
+	recipient := state[to]
+	recipient.Balance = tosca.Add(recipient.Balance, value)
+	state[to] = recipient
+}
+
+// ----------------------------------------------------------------------------
+
+const (
+	maxInitCodeSize        = 2 * 24576
+	maxBlobsPerTransaction = 6
+	blobGasPerBlob         = 1 << 17
+	blobHashVersion        = 0x01
+)
+
+// intrinsicGas computes the gas charged before the execution of a
+// transaction, covering its type, input data and access list.
+func intrinsicGas(revision tosca.Revision, tx tosca.Transaction) tosca.Gas {
+	gas := tosca.Gas(21_000)
+	if tx.Recipient == nil {
+		gas = 53_000
+		if revision >= tosca.R12_Shanghai {
+			gas += tosca.Gas(2 * tosca.SizeInWords(uint64(len(tx.Input))))
+		}
+	}
+	for _, cur := range tx.Input {
+		if cur == 0 {
+			gas += 4
+		} else {
+			gas += 16
+		}
+	}
+	for _, tuple := range tx.AccessList {
+		gas += 2400 + tosca.Gas(len(tuple.Keys))*1900
+	}
+	return gas
+}
+
+// gasPrice computes the effective gas price of a transaction, which is the
+// base fee plus the tip limited by the fee cap.
+func gasPrice(block tosca.BlockParameters, tx tosca.Transaction) tosca.Value {
+	if tx.GasFeeCap.Cmp(block.BaseFee) < 0 {
+		return tx.GasFeeCap // < rejected transaction
+	}
+	return tosca.Add(block.BaseFee, tosca.Min(tx.GasTipCap, tosca.Sub(tx.GasFeeCap, block.BaseFee)))
+}
+
+func blobGas(tx tosca.Transaction) uint64 {
+	return uint64(len(tx.BlobHashes)) * blobGasPerBlob
+}
+
+// balanceAfterBuyingGas computes the balance of the sender available for the
+// transfer of the transaction value.
+func balanceAfterBuyingGas(input Input) *big.Int {
+	tx := input.Transaction
+	cost := new(big.Int).Mul(gasPrice(input.Block, tx).ToBig(), new(big.Int).SetUint64(uint64(tx.GasLimit)))
+	cost.Add(cost, new(big.Int).Mul(input.Block.BlobBaseFee.ToBig(), new(big.Int).SetUint64(blobGas(tx))))
+	return cost.Sub(input.State[tx.Sender].Balance.ToBig(), cost)
+}
+
+func canTransferValue(input Input) bool {
+	return balanceAfterBuyingGas(input).Cmp(input.Transaction.Value.ToBig()) >= 0
+}
+
+func createdAddress(tx tosca.Transaction) tosca.Address {
+	return tosca.Address(crypto.CreateAddress(common.Address(tx.Sender), tx.Nonce))
+}
+
+func bigToValue(value *big.Int) tosca.Value {
+	return tosca.ValueFromUint256(uint256.MustFromBig(value))
+}
+
+func code(ops ...any) tosca.Code {
+	res := tosca.Code{}
+	for _, op := range ops {
+		switch op := op.(type) {
+		case vm.OpCode:
+			res = append(res, byte(op))
+		case int:
+			res = append(res, byte(op))
+		}
+	}
+	return res
+}
+
+func word(value byte) tosca.Data {
+	res := make(tosca.Data, 32)
+	res[31] = value
+	return res
+}
@@ -0,0 +1,472 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package processors
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// The execution of a transaction is a stack of call frames operating on a
+// shared world state. The model below covers the transitions between frames
+// for transactions calling the caller templates defined at the end of this
+// file: the gas handed to and returned by nested frames, value transfers,
+// contract creations, and the propagation of reverts. Nested frames may only
+// run caller templates or the contract templates of this package; any other
+// code is modeled as an abort.
+//
+// This is not a multi-frame model of the interpreter state. st.State still
+// models a single frame, with nested calls abstracted by st.CallJournal, so
+// the interpreter specification can not verify the execution inside a
+// callee. A stack of frames in st.State, generators producing multi-frame
+// states, and rules for CALL, CREATE, RETURN, and REVERT transitions are
+// not implemented.
+
+// frame is a single call or create on the stack of frames.
+type frame struct {
+	kind    tosca.CallKind
+	caller  tosca.Address
+	address tosca.Address // < the created address for creates
+	value   tosca.Value
+	gas     tosca.Gas
+}
+
+// frameResult is the result of a frame returned to its parent.
+type frameResult struct {
+	status  status
+	gasLeft tosca.Gas
+	output  tosca.Data
+	address tosca.Address // < the created address of successful creates
+}
+
+// maxCallDepth is the maximum number of nested frames below the top-level
+// call or create of a transaction.
+const maxCallDepth = 1024
+
+// frameContext models the execution of nested frames. All frames share the
+// world state, the gas refund counter, the set of warm accounts and the
+// emitted logs, which are restored when a frame fails.
+type frameContext struct {
+	input   Input
+	state   WorldState
+	stack   []frame
+	refund  tosca.Gas
+	warm    map[tosca.Address]bool
+	logs    []tosca.Log
+	aborted int // < number of frames ending in an abort
+}
+
+// frameSnapshot is the shared state of frames restored on failures.
+type frameSnapshot struct {
+	state  WorldState
+	refund tosca.Gas
+	warm   map[tosca.Address]bool
+	logs   []tosca.Log
+}
+
+func newFrameContext(input Input, state WorldState) *frameContext {
+	tx := input.Transaction
+	warm := map[tosca.Address]bool{tx.Sender: true}
+	if tx.Recipient != nil {
+		warm[*tx.Recipient] = true
+	}
+	if input.Block.Revision >= tosca.R12_Shanghai {
+		warm[input.Block.Coinbase] = true
+	}
+	for _, tuple := range tx.AccessList {
+		warm[tuple.Address] = true
+	}
+	return &frameContext{input: input, state: state, warm: warm}
+}
+
+func (c *frameContext) snapshot() frameSnapshot {
+	return frameSnapshot{state: c.state.Clone(), refund: c.refund, warm: maps.Clone(c.warm), logs: slices.Clone(c.logs)}
+}
+
+func (c *frameContext) restore(snapshot frameSnapshot) {
+	c.state = snapshot.state
+	c.refund = snapshot.refund
+	c.warm = snapshot.warm
+	c.logs = snapshot.logs
+}
+
+// call runs the code at the address of the given frame after transferring
+// its value. If the caller can not cover the value or the maximum depth is
+// reached, the call fails without consuming gas.
+func (c *frameContext) call(f frame) frameResult {
+	if len(c.stack) > maxCallDepth || c.state[f.caller].Balance.Cmp(f.value) < 0 {
+		return frameResult{status: reverted, gasLeft: f.gas}
+	}
+	backup := c.snapshot()
+	transfer(c.state, f.caller, f.address, f.value)
+	res := c.run(f, c.state[f.address].Code)
+	if res.status != succeeded {
+		c.restore(backup)
+	}
+	return res
+}
+
+// create runs the given init code in a frame creating a contract at the
+// address derived from the caller's nonce, and deploys the output of the
+// init code on success.
+func (c *frameContext) create(f frame, initCode tosca.Code) frameResult {
+	if len(c.stack) > maxCallDepth || c.state[f.caller].Balance.Cmp(f.value) < 0 {
+		return frameResult{status: reverted, gasLeft: f.gas}
+	}
+
+	// The nonce increment of the caller and the access of the created
+	// address are not undone by failures of the create.
+	account := c.state[f.caller]
+	f.address = tosca.Address(crypto.CreateAddress(common.Address(f.caller), account.Nonce))
+	account.Nonce++
+	c.state[f.caller] = account
+	c.warm[f.address] = true
+	if !c.state[f.address].isEmpty() {
+		c.aborted++
+		return frameResult{status: aborted}
+	}
+
+	backup := c.snapshot()
+	c.state[f.address] = Account{Balance: c.state[f.address].Balance, Nonce: 1}
+	transfer(c.state, f.caller, f.address, f.value)
+	res := c.run(f, initCode)
+	if res.status == succeeded {
+		deploymentCost := tosca.Gas(len(res.output) * createGasCostPerByte)
+		if res.gasLeft < deploymentCost {
+			c.aborted++
+			res = frameResult{status: aborted}
+		} else {
+			account := c.state[f.address]
+			account.Code = tosca.Code(bytes.Clone(res.output))
+			c.state[f.address] = account
+			res = frameResult{status: succeeded, gasLeft: res.gasLeft - deploymentCost, address: f.address}
+		}
+	}
+	if res.status != succeeded {
+		c.restore(backup)
+	}
+	return res
+}
+
+// run executes the given code in the given frame, which is pushed on the
+// stack of frames for the duration of the execution.
+func (c *frameContext) run(f frame, code tosca.Code) frameResult {
+	c.stack = append(c.stack, f)
+	defer func() { c.stack = c.stack[:len(c.stack)-1] }()
+
+	res := frameResult{status: aborted}
+	if len(code) == 0 {
+		res = frameResult{status: succeeded, gasLeft: f.gas}
+	} else if proxy, ok := parseCaller(code); ok {
+		res = proxy.run(c, f)
+	} else if template := findTemplate(code); template != nil {
+		res = c.runTemplate(f, template)
+	}
+	// Other code is not used by generated inputs and modeled as an abort.
+
+	if res.status == aborted {
+		c.aborted++
+		return frameResult{status: aborted}
+	}
+	return res
+}
+
+// runTemplate executes a contract template, which is modeled in the context
+// of a transaction calling the address of the frame.
+func (c *frameContext) runTemplate(f frame, template *contract) frameResult {
+	input := c.input
+	input.Transaction.Sender = f.caller
+	input.Transaction.Recipient = &f.address
+	input.Transaction.Value = f.value
+	input.Transaction.Input = nil
+
+	if f.gas < template.requiredGas(input) {
+		return frameResult{status: aborted}
+	}
+	status, output, refund := template.effect(input, c.state, f.address)
+	if status == succeeded {
+		c.refund += refund
+		c.logs = append(c.logs, template.emittedLogs(f.address)...)
+	}
+	return frameResult{status: status, gasLeft: f.gas - template.cost(input), output: output}
+}
+
+// findTemplate returns the call or init code template with the given code,
+// or nil if there is none.
+func findTemplate(code tosca.Code) *contract {
+	for _, templates := range [][]contract{callTemplates, initCodeTemplates} {
+		for i := range templates {
+			if bytes.Equal(templates[i].code, code) {
+				return &templates[i]
+			}
+		}
+	}
+	return nil
+}
+
+// callFrames models the top-level call of a transaction to a contract
+// spawning nested frames.
+func callFrames(input Input) func(WorldState, tosca.Gas) execution {
+	return func(state WorldState, gas tosca.Gas) execution {
+		context := newFrameContext(input, state)
+		res := context.execute(gas)
+		return execution{
+			success: res.status == succeeded,
+			gasLeft: res.gasLeft,
+			refund:  context.refund,
+			output:  res.output,
+			logs:    context.logs,
+			state:   context.state,
+		}
+	}
+}
+
+// execute runs the top-level call of the transaction with the given gas.
+func (c *frameContext) execute(gas tosca.Gas) frameResult {
+	tx := c.input.Transaction
+	return c.call(frame{
+		kind:    tosca.Call,
+		caller:  tx.Sender,
+		address: *tx.Recipient,
+		value:   tx.Value,
+		gas:     gas,
+	})
+}
+
+// ----------------------------------------------------------------------------
+
+// caller is a code template spawning a nested frame. A calling contract
+// calls the target with the given value, forwarding all available gas, and
+// ends with the first word of the return data of the call followed by the
+// success flag. A creating contract creates a contract with the given init
+// code and value, and ends with the created address. The contract returns
+// this output, or reverts with it if revert is set.
+type caller struct {
+	kind     tosca.CallKind // < tosca.Call or tosca.Create
+	target   tosca.Address
+	initCode tosca.Code
+	value    byte
+	revert   bool
+}
+
+const (
+	callStipend       = 2300
+	callValueTransfer = 9000
+	callNewAccount    = 25000
+	createGas         = 32000
+	initCodeWordGas   = 2
+	coldAccountAccess = 2600
+	warmAccess        = 100
+)
+
+func (c caller) code() tosca.Code {
+	end := vm.RETURN
+	if c.revert {
+		end = vm.REVERT
+	}
+	if c.kind == tosca.Create {
+		size := len(c.initCode)
+		res := code(vm.PUSH1 - 1 + vm.OpCode(size))
+		res = append(res, c.initCode...)
+		return append(res, code(
+			vm.PUSH1, 0, vm.MSTORE,
+			vm.PUSH1, size, vm.PUSH1, 32-size, vm.PUSH1, int(c.value), vm.CREATE,
+			vm.PUSH1, 0, vm.MSTORE,
+			vm.PUSH1, 32, vm.PUSH1, 0, end,
+		)...)
+	}
+	res := code(vm.PUSH1, 32, vm.PUSH1, 0, vm.PUSH1, 0, vm.PUSH1, 0, vm.PUSH1, int(c.value), vm.PUSH20)
+	res = append(res, c.target[:]...)
+	return append(res, code(
+		vm.GAS, vm.CALL,
+		vm.PUSH1, 32, vm.MSTORE,
+		vm.PUSH1, 64, vm.PUSH1, 0, end,
+	)...)
+}
+
+// parseCaller returns the caller template of the given code, if there is one.
+func parseCaller(code tosca.Code) (caller, bool) {
+	if len(code) < 2 {
+		return caller{}, false
+	}
+	res := caller{revert: code[len(code)-1] == byte(vm.REVERT)}
+	if op := vm.OpCode(code[0]); op == vm.PUSH1 {
+		res.kind = tosca.Call
+		if len(code) > 30 {
+			res.value = code[9]
+			res.target = tosca.Address(code[11:31])
+		}
+	} else if op > vm.PUSH1 && op <= vm.PUSH32 {
+		size := int(op - vm.PUSH1 + 1)
+		if len(code) < 1+size+9 {
+			return caller{}, false
+		}
+		res.kind = tosca.Create
+		res.initCode = bytes.Clone(code[1 : 1+size])
+		res.value = code[1+size+8]
+	} else {
+		return caller{}, false
+	}
+	return res, bytes.Equal(res.code(), code)
+}
+
+// run models the execution of the caller in the given frame.
+func (c caller) run(context *frameContext, f frame) frameResult {
+	revision := context.input.Block.Revision
+	value := tosca.NewValue(uint64(c.value))
+
+	// Pushing the arguments of the call or storing and pushing the init
+	// code, including the expansion of the memory to one word.
+	before, after := tosca.Gas(20), tosca.Gas(15)
+	cost := tosca.Gas(3) // < memory expansion of the return data area
+	if c.kind == tosca.Create {
+		before, after = 21, 12
+		cost = createGas
+		if revision >= tosca.R12_Shanghai {
+			cost += tosca.Gas(initCodeWordGas * tosca.SizeInWords(uint64(len(c.initCode))))
+		}
+	} else {
+		if revision < tosca.R09_Berlin {
+			cost += 700
+		} else if context.warm[c.target] {
+			cost += warmAccess
+		} else {
+			cost += coldAccountAccess
+		}
+		if c.value != 0 {
+			cost += callValueTransfer
+			if target := context.state[c.target]; target.Nonce == 0 && target.Balance == (tosca.Value{}) && len(target.Code) == 0 {
+				cost += callNewAccount
+			}
+		}
+	}
+	gas := f.gas
+	if gas < before+cost {
+		return frameResult{status: aborted}
+	}
+	gas -= before + cost
+
+	// All but one 64th of the remaining gas is handed to the nested frame.
+	nested := frame{kind: c.kind, caller: f.address, value: value, gas: gas - gas/64}
+	gas -= nested.gas
+
+	output := make(tosca.Data, 64)
+	if c.kind == tosca.Create {
+		res := context.create(nested, c.initCode)
+		gas += res.gasLeft
+		if res.status == succeeded {
+			copy(output[12:32], res.address[:])
+		}
+		output = output[:32]
+	} else {
+		context.warm[c.target] = true
+		nested.address = c.target
+		if c.value != 0 {
+			nested.gas += callStipend
+		}
+		res := context.call(nested)
+		gas += res.gasLeft
+		copy(output[:32], res.output)
+		if res.status == succeeded {
+			output[63] = 1
+		}
+	}
+
+	if gas < after {
+		return frameResult{status: aborted}
+	}
+	gas -= after
+	if c.revert {
+		return frameResult{status: reverted, gasLeft: gas, output: output}
+	}
+	return frameResult{status: succeeded, gasLeft: gas, output: output}
+}
+
+// findCaller returns the caller template installed at the recipient of the
+// given input, or nil if there is none.
+func findCaller(input Input) *caller {
+	if input.Transaction.Recipient == nil {
+		return nil
+	}
+	res, ok := parseCaller(input.State[*input.Transaction.Recipient].Code)
+	if !ok {
+		return nil
+	}
+	return &res
+}
+
+// nestedScenarios lists the frame transitions covered by rules, as
+// classified by getNestedScenario.
+var nestedScenarios = func() []string {
+	res := []string{}
+	for _, template := range callTemplates {
+		res = append(res, "call_"+template.name)
+	}
+	res = append(res,
+		"call_account_without_code",
+		"call_caller",
+		"call_with_insufficient_value",
+		"call_reverted_by_caller",
+	)
+	for _, template := range initCodeTemplates {
+		res = append(res, "create_"+template.name)
+	}
+	return append(res, "create_reverted_by_caller")
+}()
+
+// getNestedScenario classifies inputs calling a caller template by the frame
+// transition they cover. The result is empty for other inputs.
+func getNestedScenario(input Input) string {
+	proxy := findCaller(input)
+	if proxy == nil {
+		return ""
+	}
+	if proxy.revert {
+		if proxy.kind == tosca.Create {
+			return "create_reverted_by_caller"
+		}
+		return "call_reverted_by_caller"
+	}
+	if proxy.kind == tosca.Create {
+		for _, template := range initCodeTemplates {
+			if bytes.Equal(template.code, proxy.initCode) {
+				return "create_" + template.name
+			}
+		}
+		return ""
+	}
+
+	// The caller holds its balance and the value of the transaction.
+	recipient := *input.Transaction.Recipient
+	balance := tosca.Add(input.State[recipient].Balance, input.Transaction.Value)
+	if balance.Cmp(tosca.NewValue(uint64(proxy.value))) < 0 {
+		return "call_with_insufficient_value"
+	}
+	code := input.State[proxy.target].Code
+	if len(code) == 0 {
+		return "call_account_without_code"
+	}
+	if _, ok := parseCaller(code); ok {
+		return "call_caller"
+	}
+	for _, template := range callTemplates {
+		if bytes.Equal(template.code, code) {
+			return "call_" + template.name
+		}
+	}
+	return ""
+}
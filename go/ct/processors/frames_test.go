@@ -0,0 +1,76 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package processors
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"pgregory.net/rand"
+)
+
+func TestFrames_CallerCodeCanBeParsed(t *testing.T) {
+	callers := []caller{
+		{kind: tosca.Call, target: tosca.Address{1, 2, 3}, value: 7},
+		{kind: tosca.Call, target: tosca.Address{19: 1}, revert: true},
+		{kind: tosca.Create, initCode: deployCode, value: 1},
+		{kind: tosca.Create, initCode: revertCode, revert: true},
+	}
+	for _, want := range callers {
+		got, ok := parseCaller(want.code())
+		if !ok {
+			t.Fatalf("failed to parse code of %+v", want)
+		}
+		if got.kind != want.kind || got.target != want.target || got.value != want.value ||
+			got.revert != want.revert || !bytes.Equal(got.initCode, want.initCode) {
+			t.Errorf("unexpected caller, wanted %+v, got %+v", want, got)
+		}
+	}
+	for _, code := range []tosca.Code{nil, returnWordCode, deployCode, clearSlotContract.code} {
+		if _, ok := parseCaller(code); ok {
+			t.Errorf("code 0x%x should not be parsed as a caller", []byte(code))
+		}
+	}
+}
+
+func TestFrames_RevertsOfNestedFramesAreNotPropagated(t *testing.T) {
+	rnd := rand.New(0)
+	for _, scenario := range []string{"call_revert", "call_invalid", "create_revert"} {
+		input := nestedInputs(Sonic, tosca.R13_Cancun, scenario, rnd)[0]
+		context := newFrameContext(input, input.State.Clone())
+		res := context.execute(1_000_000)
+		if res.status != succeeded {
+			t.Errorf("caller of %s should succeed, got status %v", scenario, res.status)
+		}
+		if len(res.output) == 64 && res.output[63] != 0 {
+			t.Errorf("nested frame of %s should fail, got output 0x%x", scenario, []byte(res.output))
+		}
+	}
+}
+
+func TestFrames_RevertOfCallerDiscardsChangesOfNestedFrames(t *testing.T) {
+	rnd := rand.New(0)
+	for _, scenario := range []string{"call_reverted_by_caller", "create_reverted_by_caller"} {
+		input := nestedInputs(Sonic, tosca.R13_Cancun, scenario, rnd)[0]
+		context := newFrameContext(input, input.State.Clone())
+		res := context.execute(1_000_000)
+		if res.status != reverted {
+			t.Errorf("caller of %s should revert, got status %v", scenario, res.status)
+		}
+		if diff := input.State.Diff(context.state); len(diff) != 0 {
+			t.Errorf("state of %s should not be modified, got %v", scenario, diff)
+		}
+		if context.refund != 0 {
+			t.Errorf("refund of %s should be discarded, got %d", scenario, context.refund)
+		}
+	}
+}
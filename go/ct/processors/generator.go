@@ -0,0 +1,462 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package processors
+
+import (
+	"bytes"
+	"math/big"
+	"slices"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"pgregory.net/rand"
+)
+
+// allRevisions lists the revisions covered by the specification.
+var allRevisions = revisionsSince(tosca.R07_Istanbul)
+
+// revisionsSince returns the covered revisions starting with the given one.
+func revisionsSince(first tosca.Revision) []tosca.Revision {
+	res := []tosca.Revision{}
+	for revision := first; revision <= tosca.R13_Cancun; revision++ {
+		res = append(res, revision)
+	}
+	return res
+}
+
+// Generated addresses start with distinct prefixes to avoid accidental
+// collisions. Precompiled contracts and Sonic's built-in contracts at 0xd1..
+// are not used as prefixes.
+const (
+	senderPrefix     = 0x5e
+	coinbasePrefix   = 0xcb
+	accountPrefix    = 0xe0
+	contractPrefix   = 0xc0
+	accessListPrefix = 0xa1
+)
+
+type transactionKind int
+
+const (
+	transferTransaction transactionKind = iota
+	contractCallTransaction
+	createTransaction
+)
+
+// newValidInput produces an input of the given kind satisfying all validity
+// checks of the given chain.
+func newValidInput(chain Chain, revision tosca.Revision, kind transactionKind, rnd *rand.Rand) Input {
+	input := newBaseInput(revision, rnd)
+	switch kind {
+	case transferTransaction:
+		recipient := randomAddress(rnd, accountPrefix)
+		input.Transaction.Recipient = &recipient
+	case contractCallTransaction:
+		recipient := randomAddress(rnd, contractPrefix)
+		input.State[recipient] = Account{Balance: randomValue(rnd, 1000), Code: bytes.Clone(returnWordCode)}
+		input.Transaction.Recipient = &recipient
+	case createTransaction:
+		input.Transaction.Input = bytes.Clone(deployCode)
+	}
+	input.Transaction.GasLimit = intrinsicGas(revision, input.Transaction) + 10_000 + tosca.Gas(rnd.Int63n(10_000))
+	fundSender(&input, rnd)
+	return input
+}
+
+// newBaseInput produces a block and a transaction of a sender without a
+// recipient, input data, and gas limit.
+func newBaseInput(revision tosca.Revision, rnd *rand.Rand) Input {
+	block := tosca.BlockParameters{
+		ChainID:     tosca.Word{31: 1},
+		BlockNumber: int64(rnd.Uint32()),
+		Timestamp:   int64(rnd.Uint32()),
+		Coinbase:    randomAddress(rnd, coinbasePrefix),
+		GasLimit:    30_000_000,
+		Revision:    revision,
+	}
+
+	// Before London, transactions have a single gas price which is modeled
+	// by equal fee and tip caps without a base fee.
+	feeCap := 1 + rnd.Uint64n(1000)
+	tipCap := feeCap
+	if revision >= tosca.R10_London {
+		baseFee := 1 + rnd.Uint64n(1000)
+		block.BaseFee = tosca.NewValue(baseFee)
+		feeCap += baseFee
+		tipCap = rnd.Uint64n(feeCap + 1)
+	}
+	if revision >= tosca.R13_Cancun {
+		block.BlobBaseFee = tosca.NewValue(1 + rnd.Uint64n(100))
+	}
+
+	sender := randomAddress(rnd, senderPrefix)
+	nonce := rnd.Uint64n(100)
+	return Input{
+		State: WorldState{
+			sender: Account{Nonce: nonce},
+		},
+		Transaction: tosca.Transaction{
+			Sender:    sender,
+			Nonce:     nonce,
+			Value:     randomValue(rnd, 1000),
+			GasFeeCap: tosca.NewValue(feeCap),
+			GasTipCap: tosca.NewValue(tipCap),
+		},
+		Block: block,
+	}
+}
+
+// fundSender sets the balance of the sender to cover the transaction on any
+// chain.
+func fundSender(input *Input, rnd *rand.Rand) {
+	required := requiredBalance(Ethereum, *input)
+	sender := input.State[input.Transaction.Sender]
+	sender.Balance = bigToValue(required.Add(required, big.NewInt(rnd.Int63n(1_000_000))))
+	input.State[input.Transaction.Sender] = sender
+}
+
+// withInsufficientValue reduces the balance of the sender to cover the gas,
+// but not the value of the transaction, which is only valid on Sonic.
+func withInsufficientValue(input Input, rnd *rand.Rand) Input {
+	input.Transaction.Value = tosca.NewValue(1 + rnd.Uint64n(1000))
+	required := requiredBalance(Sonic, input)
+	shortfall := rnd.Int63n(int64(input.Transaction.Value.ToUint256().Uint64()))
+	sender := input.State[input.Transaction.Sender]
+	sender.Balance = bigToValue(required.Add(required, big.NewInt(shortfall)))
+	input.State[input.Transaction.Sender] = sender
+	return input
+}
+
+// setBlobs attaches the given number of valid blob hashes to a transaction.
+func setBlobs(input *Input, rnd *rand.Rand, count int) {
+	hashes := make([]tosca.Hash, count)
+	for i := range hashes {
+		rnd.Read(hashes[i][:])
+		hashes[i][0] = blobHashVersion
+	}
+	input.Transaction.BlobHashes = hashes
+	input.Transaction.BlobGasFeeCap = tosca.Add(input.Block.BlobBaseFee, randomValue(rnd, 100))
+}
+
+// decorate randomly adds an access list and blobs to a transaction where
+// supported by the revision.
+func decorate(input *Input, rnd *rand.Rand) {
+	tx := &input.Transaction
+	if input.Block.Revision >= tosca.R09_Berlin && rnd.Intn(2) == 0 {
+		tx.AccessList = []tosca.AccessTuple{}
+		for range rnd.Intn(3) {
+			tuple := tosca.AccessTuple{Address: randomAddress(rnd, accessListPrefix)}
+			for range rnd.Intn(3) {
+				tuple.Keys = append(tuple.Keys, randomKey(rnd))
+			}
+			tx.AccessList = append(tx.AccessList, tuple)
+		}
+		if tx.Recipient != nil && rnd.Intn(2) == 0 {
+			tx.AccessList = append(tx.AccessList, tosca.AccessTuple{
+				Address: *tx.Recipient,
+				Keys:    []tosca.Key{clearedSlot},
+			})
+		}
+	}
+	if input.Block.Revision >= tosca.R13_Cancun && tx.Recipient != nil && rnd.Intn(2) == 0 {
+		setBlobs(input, rnd, 1+rnd.Intn(maxBlobsPerTransaction))
+	}
+}
+
+// withGasVariants produces copies of the given input with gas limits around
+// the given gas required for the execution: exactly sufficient, slightly
+// and randomly insufficient, and more than sufficient.
+func withGasVariants(input Input, gas tosca.Gas, rnd *rand.Rand) []Input {
+	variants := []tosca.Gas{gas, gas + 1 + tosca.Gas(rnd.Int63n(100_000))}
+	if gas > 0 {
+		variants = append(variants, gas-1, tosca.Gas(rnd.Int63n(int64(gas))))
+	}
+	res := make([]Input, 0, len(variants))
+	for _, cur := range variants {
+		variant := input
+		variant.State = input.State.Clone()
+		variant.Transaction.GasLimit = intrinsicGas(input.Block.Revision, input.Transaction) + cur
+		fundSender(&variant, rnd)
+		res = append(res, variant)
+	}
+	return res
+}
+
+func transferInputs(chain Chain, revision tosca.Revision, rnd *rand.Rand) []Input {
+	res := []Input{}
+	for _, existing := range []bool{false, true} {
+		input := newValidInput(chain, revision, transferTransaction, rnd)
+		if existing {
+			input.State[*input.Transaction.Recipient] = Account{
+				Balance: randomValue(rnd, 1000),
+				Nonce:   rnd.Uint64n(10),
+			}
+		} else {
+			input.Transaction.Value = tosca.Value{}
+		}
+		input.Transaction.Input = randomData(rnd, 64)
+		decorate(&input, rnd)
+		res = append(res, withGasVariants(input, 0, rnd)...)
+	}
+	return res
+}
+
+func callInputs(chain Chain, revision tosca.Revision, target contract, rnd *rand.Rand) []Input {
+	input := newValidInput(chain, revision, contractCallTransaction, rnd)
+	recipient := *input.Transaction.Recipient
+	if target.code == nil {
+		delete(input.State, recipient)
+		recipient = identityAddress
+		input.Transaction.Recipient = &recipient
+		input.Transaction.Input = randomData(rnd, 100)
+	} else {
+		account := input.State[recipient]
+		account.Code = bytes.Clone(target.code)
+		input.State[recipient] = account
+	}
+
+	// Only the clear-slot contract depends on the storage of the recipient.
+	settings := []bool{false}
+	if target.name == clearSlotContract.name {
+		settings = append(settings, true)
+	}
+
+	res := []Input{}
+	for _, isSet := range settings {
+		cur := input
+		cur.State = input.State.Clone()
+		cur.Transaction.AccessList = nil
+		if isSet {
+			account := cur.State[recipient]
+			account.Storage = map[tosca.Key]tosca.Word{clearedSlot: randomWord(rnd)}
+			cur.State[recipient] = account
+		}
+		decorate(&cur, rnd)
+		res = append(res, withGasVariants(cur, target.requiredGas(cur), rnd)...)
+	}
+	return res
+}
+
+func collisionInputs(chain Chain, revision tosca.Revision, rnd *rand.Rand) []Input {
+	res := []Input{}
+	for _, collision := range []Account{
+		{Nonce: 1 + rnd.Uint64n(10)},
+		{Code: randomCode(rnd)},
+		{Storage: map[tosca.Key]tosca.Word{randomKey(rnd): randomWord(rnd)}},
+	} {
+		input := newValidInput(chain, revision, createTransaction, rnd)
+		collision.Balance = randomValue(rnd, 1000)
+		input.State[createdAddress(input.Transaction)] = collision
+		decorate(&input, rnd)
+		res = append(res, withGasVariants(input, deployContract.cost(input), rnd)...)
+	}
+	return res
+}
+
+func createInputs(chain Chain, revision tosca.Revision, initCode contract, rnd *rand.Rand) []Input {
+	res := []Input{}
+	for _, existing := range []bool{false, true} {
+		input := newValidInput(chain, revision, createTransaction, rnd)
+		input.Transaction.Input = append(bytes.Clone(initCode.code), randomData(rnd, 32)...)
+		if existing {
+			input.State[createdAddress(input.Transaction)] = Account{Balance: randomValue(rnd, 1000)}
+		}
+		decorate(&input, rnd)
+
+		gas := initCode.cost(input)
+		status, output, _ := initCode.effect(input, input.State.Clone(), createdAddress(input.Transaction))
+		if status == succeeded {
+			gas += tosca.Gas(len(output) * createGasCostPerByte)
+		}
+		res = append(res, withGasVariants(input, gas, rnd)...)
+	}
+	return res
+}
+
+// nestedInputs produces calls of a caller template spawning nested frames
+// covering the given scenario of getNestedScenario.
+func nestedInputs(chain Chain, revision tosca.Revision, scenario string, rnd *rand.Rand) []Input {
+	input := newValidInput(chain, revision, contractCallTransaction, rnd)
+	recipient := *input.Transaction.Recipient
+	account := input.State[recipient]
+	account.Balance = tosca.Add(randomValue(rnd, 1000), tosca.NewValue(255))
+
+	target := randomAddress(rnd, contractPrefix)
+	targetAccount := Account{Balance: randomValue(rnd, 1000)}
+	proxy := caller{kind: tosca.Call, target: target, value: randomCallValue(rnd)}
+	switch scenario {
+	case "call_account_without_code":
+		if rnd.Intn(2) == 0 {
+			targetAccount = Account{}
+		}
+	case "call_caller":
+		inner := randomAddress(rnd, contractPrefix)
+		targetAccount.Balance = tosca.Add(targetAccount.Balance, tosca.NewValue(255))
+		targetAccount.Code = newRandomCaller(inner, rnd).code()
+		input.State[inner] = newClearSlotAccount(rnd)
+	case "call_with_insufficient_value":
+		proxy.value = byte(1 + rnd.Intn(255))
+		account.Balance = tosca.NewValue(rnd.Uint64n(uint64(proxy.value)))
+		input.Transaction.Value = tosca.Value{}
+		targetAccount.Code = bytes.Clone(returnWordCode)
+	case "call_reverted_by_caller":
+		proxy.revert = true
+		targetAccount = newClearSlotAccount(rnd)
+	case "create_reverted_by_caller":
+		proxy = proxy.withInitCode(deployCode)
+		proxy.revert = true
+	default:
+		for _, template := range callTemplates {
+			if scenario == "call_"+template.name {
+				targetAccount.Code = bytes.Clone(template.code)
+				if template.name == clearSlotContract.name {
+					targetAccount = newClearSlotAccount(rnd)
+				}
+			}
+		}
+		for _, template := range initCodeTemplates {
+			if scenario == "create_"+template.name {
+				proxy = proxy.withInitCode(template.code)
+			}
+		}
+	}
+
+	if proxy.kind == tosca.Create {
+		// The created address may hold a balance.
+		target = createdAddress(tosca.Transaction{Sender: recipient, Nonce: account.Nonce})
+		targetAccount = Account{}
+		if rnd.Intn(2) == 0 {
+			targetAccount.Balance = randomValue(rnd, 1000)
+		}
+	}
+	account.Code = proxy.code()
+	input.State[recipient] = account
+	input.State[target] = targetAccount
+
+	decorate(&input, rnd)
+	if revision >= tosca.R09_Berlin && rnd.Intn(2) == 0 {
+		input.Transaction.AccessList = append(input.Transaction.AccessList, tosca.AccessTuple{
+			Address: target,
+			Keys:    []tosca.Key{clearedSlot},
+		})
+	}
+
+	res := []Input{}
+	for _, gas := range getNestedGasThresholds(input) {
+		res = append(res, withGasVariants(input, gas, rnd)...)
+	}
+	return res
+}
+
+func randomCallValue(rnd *rand.Rand) byte {
+	if rnd.Intn(2) == 0 {
+		return 0
+	}
+	return byte(1 + rnd.Intn(255))
+}
+
+// newRandomCaller produces a caller calling the given target with a random
+// value, which may revert.
+func newRandomCaller(target tosca.Address, rnd *rand.Rand) caller {
+	return caller{kind: tosca.Call, target: target, value: randomCallValue(rnd), revert: rnd.Intn(2) == 0}
+}
+
+// withInitCode returns a copy of the caller creating a contract with the
+// given init code.
+func (c caller) withInitCode(initCode tosca.Code) caller {
+	return caller{kind: tosca.Create, initCode: bytes.Clone(initCode), value: c.value}
+}
+
+// newClearSlotAccount produces an account with the clear-slot contract where
+// the cleared slot may be set.
+func newClearSlotAccount(rnd *rand.Rand) Account {
+	res := Account{Balance: randomValue(rnd, 1000), Code: bytes.Clone(clearSlotContract.code)}
+	if rnd.Intn(2) == 0 {
+		res.Storage = map[tosca.Key]tosca.Word{clearedSlot: randomWord(rnd)}
+	}
+	return res
+}
+
+// getNestedGasThresholds computes the minimal gas required to complete the
+// top-level frame of the given input and to complete all frames. Since the
+// gas handed to nested frames depends on the available gas, the thresholds
+// are determined by a search on the model.
+func getNestedGasThresholds(input Input) []tosca.Gas {
+	const limit = tosca.Gas(1 << 22)
+	completes := func(gas tosca.Gas, all bool) bool {
+		context := newFrameContext(input, input.State.Clone())
+		res := context.execute(gas)
+		if all {
+			return context.aborted == 0
+		}
+		return res.status != aborted
+	}
+
+	res := []tosca.Gas{}
+	for _, all := range []bool{false, true} {
+		if !completes(limit, all) {
+			continue
+		}
+		low, high := tosca.Gas(0), limit
+		for low < high {
+			mid := (low + high) / 2
+			if completes(mid, all) {
+				high = mid
+			} else {
+				low = mid + 1
+			}
+		}
+		if !slices.Contains(res, low) {
+			res = append(res, low)
+		}
+	}
+	return res
+}
+
+// ----------------------------------------------------------------------------
+
+func randomAddress(rnd *rand.Rand, prefix byte) tosca.Address {
+	res := tosca.Address{}
+	rnd.Read(res[:])
+	res[0] = prefix
+	return res
+}
+
+func randomKey(rnd *rand.Rand) tosca.Key {
+	res := tosca.Key{}
+	rnd.Read(res[:])
+	return res
+}
+
+// randomWord produces a non-zero word.
+func randomWord(rnd *rand.Rand) tosca.Word {
+	res := tosca.Word{}
+	rnd.Read(res[:])
+	res[31] |= 1
+	return res
+}
+
+func randomValue(rnd *rand.Rand, limit uint64) tosca.Value {
+	return tosca.NewValue(rnd.Uint64n(limit + 1))
+}
+
+func randomData(rnd *rand.Rand, maxSize int) tosca.Data {
+	res := make(tosca.Data, rnd.Intn(maxSize+1))
+	rnd.Read(res)
+	return res
+}
+
+// randomCode produces non-empty code which is not one of the templates.
+func randomCode(rnd *rand.Rand) tosca.Code {
+	res := make(tosca.Code, 1+rnd.Intn(32))
+	rnd.Read(res)
+	res[0] = 0x5b // < JUMPDEST, not starting any template
+	return res
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package processors
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/0xsoniclabs/tosca/go/ct/rlz"
+	_ "github.com/0xsoniclabs/tosca/go/interpreter/lfvm"
+	_ "github.com/0xsoniclabs/tosca/go/processor/floria"
+	_ "github.com/0xsoniclabs/tosca/go/processor/floria_eth"
+	_ "github.com/0xsoniclabs/tosca/go/processor/geth"
+	_ "github.com/0xsoniclabs/tosca/go/processor/geth_eth"
+	_ "github.com/0xsoniclabs/tosca/go/processor/opera"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+)
+
+func TestProcessors_ConformToSpecification(t *testing.T) {
+	processors := map[string]Chain{
+		"floria":     Sonic,
+		"floria-eth": Ethereum,
+		"geth-sonic": Sonic,
+		"geth-eth":   Ethereum,
+	}
+	for name, chain := range processors {
+		t.Run(name, func(t *testing.T) {
+			processor := tosca.GetProcessor(name, tosca.GetInterpreter("lfvm"))
+			if processor == nil {
+				t.Fatalf("processor %s is not registered", name)
+			}
+			errors := 0
+			ForEachTestCase(GetSpecification(chain).GetRules(), 0, func(testCase TestCase) rlz.ConsumerResult {
+				if err := testCase.Check(processor); err != nil {
+					t.Error(err)
+					errors++
+				}
+				if errors >= 10 {
+					return rlz.ConsumeAbort
+				}
+				return rlz.ConsumeContinue
+			})
+		})
+	}
+}
+
+// knownOperaIssues lists the rules violated by the opera processor, which is
+// a rough copy of a legacy implementation lacking several validity checks.
+var knownOperaIssues = map[string]string{
+	"blob_fee_cap_below_blob_base_fee":   "blob transactions are not validated",
+	"blob_transaction_creating_contract": "blob transactions are not validated",
+	"blob_transaction_without_blobs":     "blob transactions are not validated",
+	"blob_with_invalid_version":          "blob transactions are not validated",
+	"tip_cap_above_fee_cap":              "the tip cap is not checked against the fee cap",
+	"init_code_too_large":                "the init code size is not limited (EIP-3860)",
+	"create_deploy":                      "init code words are not charged (EIP-3860)",
+	"create_revert":                      "init code words are not charged (EIP-3860)",
+	"create_with_insufficient_value":     "init code words are not charged (EIP-3860)",
+	"nested_create_deploy":               "nested creates deviate in gas, nonces and deployed code",
+	"nested_create_revert":               "nested creates deviate in gas, nonces and deployed code",
+	"nested_create_reverted_by_caller":   "nested creates deviate in gas, nonces and deployed code",
+	"call_identity":                      "blob gas is not charged",
+	"call_invalid":                       "blob gas is not charged",
+	"nested_call_account_without_code":   "blob gas is not charged",
+	"nested_call_invalid":                "blob gas is not charged",
+	"nested_call_log":                    "blob gas is not charged",
+	"nested_call_return_word":            "blob gas is not charged",
+	"nested_call_reverted_by_caller":     "blob gas is not charged",
+	"value_transfer":                     "blob gas is not charged",
+}
+
+// The opera processor is checked against all rules. Violations of the rules
+// listed in knownOperaIssues are reported as known issues, while violations
+// of any other rule fail the test. Known issues no longer observed fail the
+// test as well, to keep the list up to date.
+func TestProcessors_OperaViolatesOnlyKnownIssues(t *testing.T) {
+	processor := tosca.GetProcessor("opera", tosca.GetInterpreter("lfvm"))
+	if processor == nil {
+		t.Fatalf("processor opera is not registered")
+	}
+	violated := map[string]bool{}
+	errors := 0
+	ForEachTestCase(GetSpecification(Sonic).GetRules(), 0, func(testCase TestCase) rlz.ConsumerResult {
+		err := testCase.Check(processor)
+		if err == nil {
+			return rlz.ConsumeContinue
+		}
+		if _, known := knownOperaIssues[testCase.Rule]; known {
+			violated[testCase.Rule] = true
+			return rlz.ConsumeContinue
+		}
+		t.Error(err)
+		errors++
+		if errors >= 10 {
+			return rlz.ConsumeAbort
+		}
+		return rlz.ConsumeContinue
+	})
+	for _, rule := range slices.Sorted(maps.Keys(violated)) {
+		t.Logf("known issue: opera violates rule %s, %s", rule, knownOperaIssues[rule])
+	}
+	if errors >= 10 {
+		return // < not all test cases have been checked
+	}
+	for _, rule := range slices.Sorted(maps.Keys(knownOperaIssues)) {
+		if !violated[rule] {
+			t.Errorf("rule %s is listed as a known issue but not violated by opera, remove it from the list", rule)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+// Package processors provides a conformance test specification for
+// transaction processors. Similar to the interpreter specification, the
+// specification is a list of rules. Each rule has a condition selecting the
+// inputs it covers, an effect computing the required outcome, and a generator
+// producing inputs satisfying its condition. An input is composed of a world
+// state, a transaction, and block parameters.
+//
+// Besides the top-level call or create, the specification covers transitions
+// between nested call frames, modeled as a stack of frames operating on a
+// shared world state.
+//
+// The specification covers the revisions Istanbul to Cancun. Since Sonic
+// deviates from Ethereum in the handling of transactions, the rules are
+// defined for each of the supported chains.
+package processors
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/0xsoniclabs/tosca/go/ct/rlz"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"pgregory.net/rand"
+)
+
+// Chain selects the transaction processing rules of a network.
+type Chain int
+
+const (
+	// Sonic ignores gas fee caps and transferred values in balance checks,
+	// charges 10% of the unused gas, and does not pay fees to the coinbase.
+	Sonic Chain = iota
+	// Ethereum processes transactions as defined by the Ethereum protocol.
+	Ethereum
+)
+
+func (c Chain) String() string {
+	switch c {
+	case Sonic:
+		return "Sonic"
+	case Ethereum:
+		return "Ethereum"
+	}
+	return fmt.Sprintf("Chain(%d)", int(c))
+}
+
+// Input is the starting point of a transaction processor test.
+type Input struct {
+	State       WorldState
+	Transaction tosca.Transaction
+	Block       tosca.BlockParameters
+}
+
+func (i Input) String() string {
+	tx := i.Transaction
+	recipient := "<create>"
+	if tx.Recipient != nil {
+		recipient = tx.Recipient.String()
+	}
+	builder := strings.Builder{}
+	fmt.Fprintf(&builder, "revision %v, base fee %v, blob base fee %v, coinbase %v\n",
+		i.Block.Revision, i.Block.BaseFee, i.Block.BlobBaseFee, i.Block.Coinbase)
+	fmt.Fprintf(&builder, "\ttransaction: sender %v, recipient %s, nonce %d, value %v, gas limit %d, fee cap %v, tip cap %v\n",
+		tx.Sender, recipient, tx.Nonce, tx.Value, tx.GasLimit, tx.GasFeeCap, tx.GasTipCap)
+	fmt.Fprintf(&builder, "\t             input 0x%x, access list %v, blob hashes %v, blob fee cap %v\n",
+		[]byte(tx.Input), tx.AccessList, tx.BlobHashes, tx.BlobGasFeeCap)
+	addresses := make([]tosca.Address, 0, len(i.State))
+	for address := range i.State {
+		addresses = append(addresses, address)
+	}
+	slices.SortFunc(addresses, func(a, b tosca.Address) int { return bytes.Compare(a[:], b[:]) })
+	for _, address := range addresses {
+		account := i.State[address]
+		fmt.Fprintf(&builder, "\taccount %v: balance %v, nonce %d, code 0x%x, storage %v\n",
+			address, account.Balance, account.Nonce, []byte(account.Code), account.Storage)
+	}
+	return builder.String()
+}
+
+// Outcome is the required result of processing a transaction.
+type Outcome struct {
+	// Rejected is set if the transaction is invalid. Rejected transactions
+	// are not part of a block and the processor's effects on the state are
+	// discarded, thus the receipt and state are not specified.
+	Rejected bool
+	// Receipt is the receipt of an accepted transaction. The contract
+	// address is only specified for successful contract creations, and the
+	// output of failed executions is only specified for reverts.
+	Receipt tosca.Receipt
+	// State is the world state after processing an accepted transaction.
+	State WorldState
+}
+
+// Rule specifies the processing of a class of inputs.
+type Rule struct {
+	Name      string
+	Revisions []tosca.Revision
+	Condition func(Input) bool
+	Effect    func(Input) Outcome
+
+	// Inputs produces inputs satisfying the condition of this rule for the
+	// given revision.
+	Inputs func(revision tosca.Revision, rnd *rand.Rand) []Input
+}
+
+// TestCase is a single transaction to be processed and its required outcome.
+type TestCase struct {
+	Rule     string
+	Input    Input
+	Expected Outcome
+}
+
+func (c TestCase) String() string {
+	return fmt.Sprintf("rule %s, %v", c.Rule, c.Input)
+}
+
+// Check runs the test case on the given processor and returns an error if
+// the result differs from the expected outcome.
+func (c TestCase) Check(processor tosca.Processor) error {
+	context := newTransactionContext(c.Input.State.Clone())
+	receipt, err := processor.Run(c.Input.Block, c.Input.Transaction, context)
+
+	want := c.Expected
+	if want.Rejected {
+		if err == nil {
+			return fmt.Errorf("%v\ttransaction should be rejected, got receipt %+v", c, receipt)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%v\ttransaction should be accepted, got error: %w", c, err)
+	}
+
+	issues := []string{}
+	if want, got := want.Receipt.Success, receipt.Success; want != got {
+		issues = append(issues, fmt.Sprintf("unexpected success, want %t, got %t", want, got))
+	}
+	if want, got := want.Receipt.GasUsed, receipt.GasUsed; want != got {
+		issues = append(issues, fmt.Sprintf("unexpected gas used, want %d, got %d", want, got))
+	}
+	if want, got := want.Receipt.Output, receipt.Output; isOutputSpecified(c.Expected) && !bytes.Equal(want, got) {
+		issues = append(issues, fmt.Sprintf("unexpected output, want 0x%x, got 0x%x", []byte(want), []byte(got)))
+	}
+	if want, got := want.Receipt.ContractAddress, receipt.ContractAddress; c.Input.Transaction.Recipient != nil && got != nil {
+		issues = append(issues, fmt.Sprintf("unexpected contract address for a call: %v", *got))
+	} else if want != nil && (got == nil || *want != *got) {
+		issues = append(issues, fmt.Sprintf("unexpected contract address, want %v, got %v", *want, got))
+	}
+	if want, got := want.Receipt.Logs, receipt.Logs; !slices.EqualFunc(want, got, equalLogs) {
+		issues = append(issues, fmt.Sprintf("unexpected logs, want %v, got %v", want, got))
+	}
+	issues = append(issues, want.State.Diff(context.current)...)
+	if len(issues) > 0 {
+		return fmt.Errorf("%v\t%s", c, strings.Join(issues, "\n\t"))
+	}
+	return nil
+}
+
+// equalLogs compares the address, the topics, and the data of the given logs.
+func equalLogs(a, b tosca.Log) bool {
+	return a.Address == b.Address &&
+		slices.Equal(a.Topics, b.Topics) &&
+		bytes.Equal(a.Data, b.Data)
+}
+
+// isOutputSpecified returns false for failed executions not ending with a
+// revert, since implementations differ in the output of failed contract
+// deployments, which is not part of the consensus.
+func isOutputSpecified(outcome Outcome) bool {
+	return outcome.Receipt.Success || outcome.Receipt.Output != nil
+}
+
+// Specification is a list of rules defining the processing of transactions
+// on a chain.
+type Specification struct {
+	chain Chain
+	rules []Rule
+}
+
+// GetSpecification returns the specification of transaction processing on
+// the given chain.
+func GetSpecification(chain Chain) Specification {
+	return Specification{
+		chain: chain,
+		rules: slices.Concat(getRejectionRules(chain), getExecutionRules(chain)),
+	}
+}
+
+// GetRules returns all rules of the specification.
+func (s Specification) GetRules() []Rule {
+	return slices.Clone(s.rules)
+}
+
+// GetRulesFor returns the rules which conditions are satisfied by the given
+// input. For a consistent specification, this is exactly one rule for every
+// generated input.
+func (s Specification) GetRulesFor(input Input) []Rule {
+	res := []Rule{}
+	for _, rule := range s.rules {
+		if slices.Contains(rule.Revisions, input.Block.Revision) && rule.Condition(input) {
+			res = append(res, rule)
+		}
+	}
+	return res
+}
+
+// FilterRules returns the rules which names match the given filter.
+func FilterRules(rules []Rule, filter *regexp.Regexp) []Rule {
+	if filter == nil {
+		return rules
+	}
+	res := make([]Rule, 0, len(rules))
+	for _, rule := range rules {
+		if filter.MatchString(rule.Name) {
+			res = append(res, rule)
+		}
+	}
+	return res
+}
+
+// ForEachTestCase generates the test cases of the given rules and passes
+// them to the given consumer until all cases are consumed or the consumer
+// aborts.
+func ForEachTestCase(rules []Rule, seed uint64, consume func(TestCase) rlz.ConsumerResult) {
+	rnd := rand.New(seed)
+	for _, rule := range rules {
+		for _, revision := range rule.Revisions {
+			for _, input := range rule.Inputs(revision, rnd) {
+				testCase := TestCase{
+					Rule:     rule.Name,
+					Input:    input,
+					Expected: rule.Effect(input),
+				}
+				if consume(testCase) == rlz.ConsumeAbort {
+					return
+				}
+			}
+		}
+	}
+}
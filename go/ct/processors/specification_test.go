@@ -0,0 +1,89 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package processors
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/0xsoniclabs/tosca/go/ct/rlz"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"pgregory.net/rand"
+)
+
+func TestSpecification_RuleNamesAreUnique(t *testing.T) {
+	for _, chain := range []Chain{Sonic, Ethereum} {
+		seen := map[string]bool{}
+		for _, rule := range GetSpecification(chain).GetRules() {
+			if seen[rule.Name] {
+				t.Errorf("duplicate rule name %q in %v", rule.Name, chain)
+			}
+			seen[rule.Name] = true
+		}
+	}
+}
+
+func TestSpecification_AllRulesProduceInputs(t *testing.T) {
+	rnd := rand.New(0)
+	for _, chain := range []Chain{Sonic, Ethereum} {
+		for _, rule := range GetSpecification(chain).GetRules() {
+			for _, revision := range rule.Revisions {
+				if len(rule.Inputs(revision, rnd)) == 0 {
+					t.Errorf("rule %s produces no inputs for %v on %v", rule.Name, revision, chain)
+				}
+			}
+		}
+	}
+}
+
+func TestSpecification_EachInputIsCoveredByExactlyItsRule(t *testing.T) {
+	for _, chain := range []Chain{Sonic, Ethereum} {
+		spec := GetSpecification(chain)
+		ForEachTestCase(spec.GetRules(), 0, func(testCase TestCase) rlz.ConsumerResult {
+			rules := spec.GetRulesFor(testCase.Input)
+			if len(rules) != 1 || rules[0].Name != testCase.Rule {
+				names := []string{}
+				for _, rule := range rules {
+					names = append(names, rule.Name)
+				}
+				t.Errorf("input of rule %s on %v is covered by %v", testCase.Rule, chain, names)
+			}
+			return rlz.ConsumeContinue
+		})
+	}
+}
+
+func TestSpecification_ChainsDifferInInsufficientValueHandling(t *testing.T) {
+	rnd := rand.New(0)
+	input := withInsufficientValue(newValidInput(Sonic, tosca.R13_Cancun, transferTransaction, rnd), rnd)
+	if got := GetSpecification(Sonic).GetRulesFor(input); len(got) != 1 || got[0].Name != "call_with_insufficient_value" {
+		t.Errorf("unexpected rules on Sonic: %v", got)
+	}
+	if got := GetSpecification(Ethereum).GetRulesFor(input); len(got) != 1 || got[0].Name != "insufficient_balance" {
+		t.Errorf("unexpected rules on Ethereum: %v", got)
+	}
+}
+
+func TestFilterRules_SelectsMatchingRules(t *testing.T) {
+	rules := GetSpecification(Sonic).GetRules()
+	filtered := FilterRules(rules, regexp.MustCompile("^create_"))
+	if len(filtered) == 0 {
+		t.Fatalf("no rules selected")
+	}
+	for _, rule := range filtered {
+		if !regexp.MustCompile("^create_").MatchString(rule.Name) {
+			t.Errorf("unexpected rule %s", rule.Name)
+		}
+	}
+	if got := FilterRules(rules, nil); len(got) != len(rules) {
+		t.Errorf("nil filter should select all rules, got %d of %d", len(got), len(rules))
+	}
+}
@@ -0,0 +1,316 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package processors
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// WorldState models the accounts of a chain before or after a transaction.
+// Accounts with default values are equivalent to missing accounts.
+type WorldState map[tosca.Address]Account
+
+// Account is the state of a single account.
+type Account struct {
+	Balance tosca.Value
+	Nonce   uint64
+	Code    tosca.Code
+	Storage map[tosca.Key]tosca.Word
+}
+
+func (s WorldState) Clone() WorldState {
+	res := make(WorldState, len(s))
+	for address, account := range s {
+		res[address] = account.Clone()
+	}
+	return res
+}
+
+// Equal returns true if both states contain the same non-empty accounts.
+func (s WorldState) Equal(other WorldState) bool {
+	return len(s.Diff(other)) == 0
+}
+
+// Diff lists the differences between the given states.
+func (s WorldState) Diff(other WorldState) []string {
+	addresses := slices.Collect(maps.Keys(s))
+	for address := range other {
+		if _, found := s[address]; !found {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i][:], addresses[j][:]) < 0
+	})
+
+	res := []string{}
+	for _, address := range addresses {
+		a, b := s[address], other[address]
+		if a.Balance != b.Balance {
+			res = append(res, fmt.Sprintf("%v: different balance: %v != %v", address, a.Balance, b.Balance))
+		}
+		if a.Nonce != b.Nonce {
+			res = append(res, fmt.Sprintf("%v: different nonce: %d != %d", address, a.Nonce, b.Nonce))
+		}
+		if !bytes.Equal(a.Code, b.Code) {
+			res = append(res, fmt.Sprintf("%v: different code: 0x%x != 0x%x", address, []byte(a.Code), []byte(b.Code)))
+		}
+		for key := range a.Storage {
+			if a.Storage[key] != b.Storage[key] {
+				res = append(res, fmt.Sprintf("%v: different value for key %v: %v != %v", address, key, a.Storage[key], b.Storage[key]))
+			}
+		}
+		for key := range b.Storage {
+			if _, found := a.Storage[key]; !found && b.Storage[key] != (tosca.Word{}) {
+				res = append(res, fmt.Sprintf("%v: different value for key %v: %v != %v", address, key, tosca.Word{}, b.Storage[key]))
+			}
+		}
+	}
+	return res
+}
+
+func (a Account) Clone() Account {
+	return Account{
+		Balance: a.Balance,
+		Nonce:   a.Nonce,
+		Code:    bytes.Clone(a.Code),
+		Storage: maps.Clone(a.Storage),
+	}
+}
+
+// isEmpty returns true if the account can not collide with a created
+// contract, which requires a zero nonce, no code, and an empty storage.
+func (a Account) isEmpty() bool {
+	if a.Nonce != 0 || len(a.Code) != 0 {
+		return false
+	}
+	for _, value := range a.Storage {
+		if value != (tosca.Word{}) {
+			return false
+		}
+	}
+	return true
+}
+
+// ----------------------------------------------------------------------------
+
+// transactionContext implements the tosca.TransactionContext interface on
+// top of a WorldState to run transactions of test cases.
+type transactionContext struct {
+	original  WorldState
+	current   WorldState
+	transient map[tosca.Address]map[tosca.Key]tosca.Word
+	accounts  map[tosca.Address]bool
+	slots     map[tosca.Address]map[tosca.Key]bool
+	created   map[tosca.Address]bool
+	destroyed map[tosca.Address]bool
+	logs      []tosca.Log
+	undo      []func()
+}
+
+func newTransactionContext(state WorldState) *transactionContext {
+	return &transactionContext{
+		original:  state,
+		current:   state.Clone(),
+		transient: map[tosca.Address]map[tosca.Key]tosca.Word{},
+		accounts:  map[tosca.Address]bool{},
+		slots:     map[tosca.Address]map[tosca.Key]bool{},
+		created:   map[tosca.Address]bool{},
+		destroyed: map[tosca.Address]bool{},
+	}
+}
+
+// update applies the given modification to an account, recording the
+// previous state to be restored on snapshot reverts.
+func (c *transactionContext) update(address tosca.Address, modify func(*Account)) {
+	original, found := c.current[address]
+	c.undo = append(c.undo, func() {
+		if found {
+			c.current[address] = original
+		} else {
+			delete(c.current, address)
+		}
+	})
+	modified := original.Clone()
+	modify(&modified)
+	c.current[address] = modified
+}
+
+func (c *transactionContext) AccountExists(address tosca.Address) bool {
+	account := c.current[address]
+	return account.Balance != (tosca.Value{}) || account.Nonce != 0 || len(account.Code) != 0
+}
+
+func (c *transactionContext) CreateContract(address tosca.Address) {
+	c.update(address, func(account *Account) {
+		*account = Account{Balance: account.Balance}
+	})
+	c.created[address] = true
+	c.undo = append(c.undo, func() { delete(c.created, address) })
+}
+
+func (c *transactionContext) IsNewContract(address tosca.Address) bool {
+	return c.created[address]
+}
+
+func (c *transactionContext) GetBalance(address tosca.Address) tosca.Value {
+	return c.current[address].Balance
+}
+
+func (c *transactionContext) SetBalance(address tosca.Address, value tosca.Value) {
+	c.update(address, func(account *Account) { account.Balance = value })
+}
+
+func (c *transactionContext) GetNonce(address tosca.Address) uint64 {
+	return c.current[address].Nonce
+}
+
+func (c *transactionContext) SetNonce(address tosca.Address, nonce uint64) {
+	c.update(address, func(account *Account) { account.Nonce = nonce })
+}
+
+func (c *transactionContext) GetCode(address tosca.Address) tosca.Code {
+	return bytes.Clone(c.current[address].Code)
+}
+
+func (c *transactionContext) GetCodeHash(address tosca.Address) tosca.Hash {
+	if !c.AccountExists(address) {
+		return tosca.Hash{}
+	}
+	return tosca.Hash(crypto.Keccak256Hash(c.current[address].Code))
+}
+
+func (c *transactionContext) GetCodeSize(address tosca.Address) int {
+	return len(c.current[address].Code)
+}
+
+func (c *transactionContext) SetCode(address tosca.Address, code tosca.Code) {
+	c.update(address, func(account *Account) { account.Code = bytes.Clone(code) })
+}
+
+func (c *transactionContext) HasEmptyStorage(address tosca.Address) bool {
+	for _, value := range c.current[address].Storage {
+		if value != (tosca.Word{}) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *transactionContext) GetStorage(address tosca.Address, key tosca.Key) tosca.Word {
+	return c.current[address].Storage[key]
+}
+
+func (c *transactionContext) SetStorage(address tosca.Address, key tosca.Key, value tosca.Word) tosca.StorageStatus {
+	original := c.original[address].Storage[key]
+	current := c.current[address].Storage[key]
+	c.update(address, func(account *Account) {
+		if account.Storage == nil {
+			account.Storage = map[tosca.Key]tosca.Word{}
+		}
+		account.Storage[key] = value
+	})
+	return tosca.GetStorageStatus(original, current, value)
+}
+
+func (c *transactionContext) SelfDestruct(address tosca.Address, beneficiary tosca.Address) bool {
+	balance := c.GetBalance(address)
+	c.SetBalance(address, tosca.Value{})
+	c.SetBalance(beneficiary, tosca.Add(c.GetBalance(beneficiary), balance))
+	if c.destroyed[address] {
+		return false
+	}
+	c.destroyed[address] = true
+	c.undo = append(c.undo, func() { delete(c.destroyed, address) })
+	return true
+}
+
+func (c *transactionContext) HasSelfDestructed(address tosca.Address) bool {
+	return c.destroyed[address]
+}
+
+func (c *transactionContext) CreateSnapshot() tosca.Snapshot {
+	return tosca.Snapshot(len(c.undo))
+}
+
+func (c *transactionContext) RestoreSnapshot(snapshot tosca.Snapshot) {
+	for len(c.undo) > int(snapshot) {
+		c.undo[len(c.undo)-1]()
+		c.undo = c.undo[:len(c.undo)-1]
+	}
+}
+
+func (c *transactionContext) GetTransientStorage(address tosca.Address, key tosca.Key) tosca.Word {
+	return c.transient[address][key]
+}
+
+func (c *transactionContext) SetTransientStorage(address tosca.Address, key tosca.Key, value tosca.Word) {
+	if c.transient[address] == nil {
+		c.transient[address] = map[tosca.Key]tosca.Word{}
+	}
+	previous := c.transient[address][key]
+	c.transient[address][key] = value
+	c.undo = append(c.undo, func() { c.transient[address][key] = previous })
+}
+
+func (c *transactionContext) AccessAccount(address tosca.Address) tosca.AccessStatus {
+	if c.accounts[address] {
+		return tosca.WarmAccess
+	}
+	c.accounts[address] = true
+	c.undo = append(c.undo, func() { delete(c.accounts, address) })
+	return tosca.ColdAccess
+}
+
+func (c *transactionContext) AccessStorage(address tosca.Address, key tosca.Key) tosca.AccessStatus {
+	if c.slots[address][key] {
+		return tosca.WarmAccess
+	}
+	if c.slots[address] == nil {
+		c.slots[address] = map[tosca.Key]bool{}
+	}
+	c.slots[address][key] = true
+	c.undo = append(c.undo, func() { delete(c.slots[address], key) })
+	return tosca.ColdAccess
+}
+
+func (c *transactionContext) IsAddressInAccessList(address tosca.Address) bool {
+	return c.accounts[address]
+}
+
+func (c *transactionContext) IsSlotInAccessList(address tosca.Address, key tosca.Key) (addressPresent, slotPresent bool) {
+	return c.accounts[address], c.slots[address][key]
+}
+
+func (c *transactionContext) EmitLog(log tosca.Log) {
+	length := len(c.logs)
+	c.logs = append(c.logs, log)
+	c.undo = append(c.undo, func() { c.logs = c.logs[:length] })
+}
+
+func (c *transactionContext) GetLogs() []tosca.Log {
+	return slices.Clone(c.logs)
+}
+
+func (c *transactionContext) GetBlockHash(int64) tosca.Hash {
+	return tosca.Hash{}
+}
+
+func (c *transactionContext) GetCommittedStorage(address tosca.Address, key tosca.Key) tosca.Word {
+	return c.original[address].Storage[key]
+}
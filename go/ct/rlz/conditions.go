@@ -11,6 +11,7 @@
 package rlz
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"strings"
@@ -101,6 +102,251 @@ func (c *conjunction) String() string {
 	return builder.String()
 }
 
+////////////////////////////////////////////////////////////
+// False
+
+type falseCondition struct{}
+
+// False creates a condition that is never satisfied. It is the complement of
+// conditions covering all states, e.g. bounds including all revisions.
+func False() Condition {
+	return &falseCondition{}
+}
+
+func (c *falseCondition) Check(*st.State) (bool, error) {
+	return false, nil
+}
+
+func (c *falseCondition) Restrict(generator *gen.StateGenerator) {
+	// Conflicting constraints make the generator report the condition as
+	// unsatisfiable.
+	generator.SetStatus(st.Running)
+	generator.SetStatus(st.Stopped)
+}
+
+func (c *falseCondition) GetTestValues() []TestValue {
+	return nil
+}
+
+func (c *falseCondition) String() string {
+	return "false"
+}
+
+////////////////////////////////////////////////////////////
+// Disjunction
+
+type disjunction struct {
+	conditions []Condition
+}
+
+// Or creates a condition satisfied if any of the given conditions holds. When
+// restricting generators, the first alternative is selected. To cover all
+// alternatives, test values include a choice of the alternative to restrict
+// generators to, and Rule.GenerateSatisfyingState tries every alternative.
+func Or(conditions ...Condition) Condition {
+	if len(conditions) == 0 {
+		panic("disjunction without alternatives is unsatisfiable")
+	}
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+	// Merge nested disjunctions into a single disjunction.
+	res := []Condition{}
+	for _, cur := range conditions {
+		if c, ok := cur.(*disjunction); ok {
+			res = append(res, c.conditions...)
+		} else {
+			res = append(res, cur)
+		}
+	}
+	return &disjunction{conditions: res}
+}
+
+func (c *disjunction) Check(s *st.State) (bool, error) {
+	var errs error
+	for _, cur := range c.conditions {
+		r, err := cur.Check(s)
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		if r {
+			return true, nil
+		}
+	}
+	return false, errs
+}
+
+func (c *disjunction) Restrict(generator *gen.StateGenerator) {
+	c.conditions[0].Restrict(generator)
+}
+
+func (c *disjunction) GetTestValues() []TestValue {
+	property := Property(fmt.Sprintf("alternative(%v)", c))
+	domain := uint16Domain{}
+	restrict := func(generator *gen.StateGenerator, alternative uint16) {
+		c.conditions[alternative].Restrict(generator)
+	}
+	res := []TestValue{}
+	for i, cur := range c.conditions {
+		res = append(res, NewTestValue(property, domain, uint16(i), restrict))
+		res = append(res, cur.GetTestValues()...)
+	}
+	return res
+}
+
+func (c *disjunction) String() string {
+	parts := make([]string, 0, len(c.conditions))
+	for _, cur := range c.conditions {
+		if _, ok := cur.(*conjunction); ok {
+			parts = append(parts, "("+cur.String()+")")
+		} else {
+			parts = append(parts, cur.String())
+		}
+	}
+	return strings.Join(parts, " ∨ ")
+}
+
+// getAlternatives expands the disjunctions in the given condition, resulting
+// in a list of disjunction-free conditions which are satisfied if and only if
+// any of them is satisfied.
+func getAlternatives(condition Condition) []Condition {
+	switch c := condition.(type) {
+	case *disjunction:
+		res := []Condition{}
+		for _, cur := range c.conditions {
+			res = append(res, getAlternatives(cur)...)
+		}
+		return res
+	case *conjunction:
+		res := []Condition{And()}
+		for _, cur := range c.conditions {
+			next := []Condition{}
+			for _, prefix := range res {
+				for _, alternative := range getAlternatives(cur) {
+					next = append(next, And(prefix, alternative))
+				}
+			}
+			res = next
+		}
+		return res
+	}
+	return []Condition{condition}
+}
+
+////////////////////////////////////////////////////////////
+// Negation
+
+// negatable is implemented by conditions with a complementary condition.
+type negatable interface {
+	negate() Condition
+}
+
+type negation struct {
+	condition Condition
+}
+
+// Not creates a condition satisfied if the given condition is not. Negations
+// of conjunctions and disjunctions are resolved using De Morgan's laws, and
+// conditions with a complementary condition are replaced by it. Other
+// conditions can not restrict generators when negated.
+func Not(condition Condition) Condition {
+	if c, ok := condition.(negatable); ok {
+		return c.negate()
+	}
+	return &negation{condition}
+}
+
+func (c *negation) Check(s *st.State) (bool, error) {
+	r, err := c.condition.Check(s)
+	if err != nil {
+		return false, err
+	}
+	return !r, nil
+}
+
+func (c *negation) Restrict(*gen.StateGenerator) {
+	// This is an specification error, and should not be silently ignored
+	panic(fmt.Sprintf("negation of %v can not restrict generators", c.condition))
+}
+
+func (c *negation) GetTestValues() []TestValue {
+	return c.condition.GetTestValues()
+}
+
+func (c *negation) String() string {
+	return fmt.Sprintf("¬(%v)", c.condition)
+}
+
+func (c *negation) negate() Condition { return c.condition }
+
+func (c *conjunction) negate() Condition {
+	if len(c.conditions) == 0 {
+		panic("negation of true is unsatisfiable")
+	}
+	res := make([]Condition, 0, len(c.conditions))
+	for _, cur := range c.conditions {
+		res = append(res, Not(cur))
+	}
+	return Or(res...)
+}
+
+func (c *falseCondition) negate() Condition { return And() }
+
+func (c *disjunction) negate() Condition {
+	res := make([]Condition, 0, len(c.conditions))
+	for _, cur := range c.conditions {
+		res = append(res, Not(cur))
+	}
+	return And(res...)
+}
+
+func (c *eq[T]) negate() Condition { return Ne(c.lhs, c.rhs) }
+func (c *ne[T]) negate() Condition { return Eq(c.lhs, c.rhs) }
+func (c *lt[T]) negate() Condition { return Ge(c.lhs, c.rhs) }
+func (c *le[T]) negate() Condition { return Gt(c.lhs, c.rhs) }
+func (c *gt[T]) negate() Condition { return Le(c.lhs, c.rhs) }
+func (c *ge[T]) negate() Condition { return Lt(c.lhs, c.rhs) }
+
+func (c *revisionBounds) negate() Condition {
+	res := []Condition{}
+	if c.min > MinRevision {
+		res = append(res, RevisionBounds(MinRevision, GetPreviousRevision(c.min)))
+	}
+	if c.max < R99_UnknownNextRevision {
+		res = append(res, RevisionBounds(GetNextRevision(c.max), R99_UnknownNextRevision))
+	}
+	if len(res) == 0 {
+		return False()
+	}
+	return Or(res...)
+}
+
+func (c *isCode) negate() Condition                        { return IsData(c.position) }
+func (c *isData) negate() Condition                        { return IsCode(c.position) }
+func (c *isStorageWarm) negate() Condition                 { return IsStorageCold(c.key) }
+func (c *isStorageCold) negate() Condition                 { return IsStorageWarm(c.key) }
+func (c *bindTransientStorageToNonZero) negate() Condition { return BindTransientStorageToZero(c.key) }
+func (c *bindTransientStorageToZero) negate() Condition    { return BindTransientStorageToNonZero(c.key) }
+func (c *accountIsEmpty) negate() Condition                { return AccountIsNotEmpty(c.address) }
+func (c *accountIsNotEmpty) negate() Condition             { return AccountIsEmpty(c.address) }
+func (c *isAddressWarm) negate() Condition                 { return IsAddressCold(c.key) }
+func (c *isAddressCold) negate() Condition                 { return IsAddressWarm(c.key) }
+func (c *isEofCode) negate() Condition                     { return IsLegacyCode() }
+func (c *isLegacyCode) negate() Condition                  { return IsEofCode() }
+func (c *isNewContract) negate() Condition                 { return IsNotNewContract() }
+func (c *isNotNewContract) negate() Condition              { return IsNewContract() }
+func (c *hasSelfDestructed) negate() Condition             { return HasNotSelfDestructed() }
+func (c *hasNotSelfDestructed) negate() Condition          { return HasSelfDestructed() }
+func (c *inRange256FromCurrentBlock) negate() Condition {
+	return OutOfRange256FromCurrentBlock(c.blockNumber)
+}
+func (c *outOfRange256FromCurrentBlock) negate() Condition {
+	return InRange256FromCurrentBlock(c.blockNumber)
+}
+func (c *hasBlobHash) negate() Condition   { return HasNoBlobHash(c.index) }
+func (c *hasNoBlobHash) negate() Condition { return HasBlobHash(c.index) }
+
 ////////////////////////////////////////////////////////////
 // Equal
 
@@ -169,6 +415,8 @@ func (e *ne[T]) Restrict(generator *gen.StateGenerator) {
 	e.lhs.Restrict(RestrictEqual, domain.SomethingNotEqual(e.rhs), generator)
 }
 
+func (e *ne[T]) isInequality() {}
+
 func (e *ne[T]) GetTestValues() []TestValue {
 	return Eq(e.lhs, e.rhs).GetTestValues()
 }
@@ -333,6 +581,63 @@ func IsRevisionCondition(condition Condition) bool {
 	return ok
 }
 
+// GetRevisions lists the revisions, including R99_UnknownNextRevision, for
+// which the given condition may hold. Only revision bounds are considered,
+// other constraints of the condition are assumed to be satisfiable.
+func GetRevisions(condition Condition) []tosca.Revision {
+	candidates := []tosca.Revision{}
+	for revision := MinRevision; revision <= NewestSupportedRevision; revision = GetNextRevision(revision) {
+		candidates = append(candidates, revision)
+	}
+	candidates = append(candidates, R99_UnknownNextRevision)
+
+	res := []tosca.Revision{}
+	for _, revision := range candidates {
+		for _, alternative := range getAlternatives(condition) {
+			if holdsForRevision(alternative, revision) {
+				res = append(res, revision)
+				break
+			}
+		}
+	}
+	return res
+}
+
+// WithoutRevisionBounds removes all revision bounds from the given condition,
+// yielding the constraints of the condition shared by all its revisions.
+func WithoutRevisionBounds(condition Condition) Condition {
+	switch c := condition.(type) {
+	case *revisionBounds:
+		return And()
+	case *conjunction:
+		res := make([]Condition, 0, len(c.conditions))
+		for _, cur := range c.conditions {
+			if !IsRevisionCondition(cur) {
+				res = append(res, WithoutRevisionBounds(cur))
+			}
+		}
+		return And(res...)
+	case *disjunction:
+		res := make([]Condition, 0, len(c.conditions))
+		for _, cur := range c.conditions {
+			res = append(res, WithoutRevisionBounds(cur))
+		}
+		return Or(res...)
+	}
+	return condition
+}
+
+func holdsForRevision(condition Condition, revision tosca.Revision) bool {
+	for _, cur := range getConditions(condition) {
+		if bounds, ok := cur.(*revisionBounds); ok {
+			if revision < bounds.min || bounds.max < revision {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // AnyKnownRevision restricts the revision to any revision covered by the CT specification.
 func AnyKnownRevision() Condition {
 	return RevisionBounds(MinRevision, NewestSupportedRevision)
@@ -361,7 +666,7 @@ func (c *revisionBounds) GetTestValues() []TestValue {
 	}
 
 	res = append(res, NewTestValue(property, domain, R99_UnknownNextRevision, restrict))
-	for r := tosca.Revision(0); r <= NewestSupportedRevision; r++ {
+	for r := tosca.Revision(0); r <= NewestSupportedRevision; r = GetNextRevision(r) {
 		res = append(res, NewTestValue(property, domain, r, restrict))
 	}
 
@@ -823,6 +1128,76 @@ func restrictAccountWarmCold(bindKey BindableExpression[U256]) func(generator *g
 	}
 }
 
+////////////////////////////////////////////////////////////
+// Is EOF code
+
+type isEofCode struct {
+}
+
+// IsEofCode holds for states executing an EOF container, which is only
+// interpreted as such in the experimental revision. In other revisions, EOF
+// containers are legacy code starting with an invalid instruction. Since those
+// states are covered by legacy code rules, they are not generated.
+func IsEofCode() Condition {
+	return &isEofCode{}
+}
+
+func (c *isEofCode) Check(s *st.State) (bool, error) {
+	return s.Revision == tosca.R98_Experimental && s.Code.IsEof(), nil
+}
+
+func (c *isEofCode) Restrict(generator *gen.StateGenerator) {
+	generator.SetRevision(tosca.R98_Experimental)
+	generator.MustBeEofCode()
+}
+
+func (c *isEofCode) GetTestValues() []TestValue {
+	property := Property(c.String())
+	domain := boolDomain{}
+	restrict := func(generator *gen.StateGenerator, isEof bool) {
+		if isEof {
+			IsEofCode().Restrict(generator)
+		} else {
+			IsLegacyCode().Restrict(generator)
+		}
+	}
+	return []TestValue{
+		NewTestValue(property, domain, true, restrict),
+		NewTestValue(property, domain, false, restrict),
+	}
+}
+
+func (c *isEofCode) String() string {
+	return "isEofCode()"
+}
+
+////////////////////////////////////////////////////////////
+// Is legacy code
+
+type isLegacyCode struct {
+}
+
+func IsLegacyCode() Condition {
+	return &isLegacyCode{}
+}
+
+func (c *isLegacyCode) Check(s *st.State) (bool, error) {
+	res, err := IsEofCode().Check(s)
+	return !res, err
+}
+
+func (c *isLegacyCode) Restrict(generator *gen.StateGenerator) {
+	generator.MustBeLegacyCode()
+}
+
+func (c *isLegacyCode) GetTestValues() []TestValue {
+	return IsEofCode().GetTestValues()
+}
+
+func (c *isLegacyCode) String() string {
+	return "isLegacyCode()"
+}
+
 ////////////////////////////////////////////////////////////
 // Is new contract
 
@@ -12,7 +12,9 @@ package rlz
 
 import (
 	"errors"
+	"fmt"
 	"math"
+	"slices"
 	"testing"
 
 	. "github.com/0xsoniclabs/tosca/go/ct/common"
@@ -74,6 +76,13 @@ func TestCondition_Check(t *testing.T) {
 		{IsData(Pc()), newStateWithPcAndCode(1, byte(vm.PUSH1), byte(0)), newStateWithPcAndCode(1, byte(vm.ADD), byte(vm.ADD))},
 		{IsData(Pc()), newStateWithPcAndCode(1, byte(vm.PUSH1), byte(0)), newStateWithPcAndCode(2, byte(vm.ADD), byte(vm.ADD))},
 		{IsData(Param(0)), newStateWithStack(st.NewStack(NewU256(1))), newStateWithStack(st.NewStack(NewU256(1, 1)))},
+		{Or(Eq(Status(), st.Reverted), Eq(Pc(), NewU256(42))), newStateWithStatusAndPc(st.Reverted, 41), newStateWithStatusAndPc(st.Stopped, 41)},
+		{Or(Eq(Status(), st.Reverted), Eq(Pc(), NewU256(42))), newStateWithStatusAndPc(st.Stopped, 42), newStateWithStatusAndPc(st.Stopped, 43)},
+		{Not(Eq(Pc(), NewU256(42))), newStateWithPc(41), newStateWithPc(42)},
+		{Not(Lt(Pc(), NewU256(42))), newStateWithPc(42), newStateWithPc(41)},
+		{Not(And(Eq(Status(), st.Reverted), Eq(Pc(), NewU256(42)))), newStateWithStatusAndPc(st.Reverted, 41), newStateWithStatusAndPc(st.Reverted, 42)},
+		{Not(Or(Eq(Status(), st.Reverted), Eq(Pc(), NewU256(42)))), newStateWithStatusAndPc(st.Stopped, 41), newStateWithStatusAndPc(st.Stopped, 42)},
+		{Not(IsCode(Pc())), newStateWithPcAndCode(1, byte(vm.PUSH1), byte(0)), newStateWithPcAndCode(1, byte(vm.ADD), byte(vm.ADD))},
 	}
 
 	for _, test := range tests {
@@ -617,6 +626,56 @@ func TestCondition_IsRevisionCondition(t *testing.T) {
 	}
 }
 
+func TestCondition_GetRevisions(t *testing.T) {
+	known := []tosca.Revision{}
+	for revision := MinRevision; revision <= NewestSupportedRevision; revision = GetNextRevision(revision) {
+		known = append(known, revision)
+	}
+	all := append(slices.Clone(known), R99_UnknownNextRevision)
+
+	tests := map[string]struct {
+		condition Condition
+		want      []tosca.Revision
+	}{
+		"unconstrained":    {IsCode(Pc()), all},
+		"single revision":  {And(IsCode(Pc()), IsRevision(tosca.R10_London)), []tosca.Revision{tosca.R10_London}},
+		"bounds":           {RevisionBounds(tosca.R13_Cancun, R99_UnknownNextRevision), []tosca.Revision{tosca.R13_Cancun, tosca.R14_Prague, tosca.R15_Osaka, tosca.R98_Experimental, R99_UnknownNextRevision}},
+		"any known":        {AnyKnownRevision(), known},
+		"intersection":     {And(RevisionBounds(tosca.R09_Berlin, tosca.R11_Paris), RevisionBounds(tosca.R10_London, tosca.R13_Cancun)), []tosca.Revision{tosca.R10_London, tosca.R11_Paris}},
+		"empty":            {And(IsRevision(tosca.R09_Berlin), IsRevision(tosca.R10_London)), []tosca.Revision{}},
+		"disjunction":      {Or(IsRevision(tosca.R07_Istanbul), IsRevision(tosca.R15_Osaka)), []tosca.Revision{tosca.R07_Istanbul, tosca.R15_Osaka}},
+		"negated revision": {Not(RevisionBounds(MinRevision, tosca.R14_Prague)), []tosca.Revision{tosca.R15_Osaka, tosca.R98_Experimental, R99_UnknownNextRevision}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := GetRevisions(test.condition); !slices.Equal(got, test.want) {
+				t.Errorf("unexpected revisions of %v, wanted %v, got %v", test.condition, test.want, got)
+			}
+		})
+	}
+}
+
+func TestCondition_WithoutRevisionBounds(t *testing.T) {
+	tests := []struct {
+		condition Condition
+		want      Condition
+	}{
+		{IsRevision(tosca.R10_London), And()},
+		{IsCode(Pc()), IsCode(Pc())},
+		{And(IsRevision(tosca.R10_London), IsCode(Pc()), AnyKnownRevision()), And(IsCode(Pc()))},
+		{
+			Or(And(IsRevision(tosca.R10_London), Lt(Gas(), 5)), And(IsRevision(tosca.R09_Berlin), Lt(Gas(), 3))),
+			Or(Lt(Gas(), 5), Lt(Gas(), 3)),
+		},
+	}
+	for _, test := range tests {
+		if want, got := test.want.String(), WithoutRevisionBounds(test.condition).String(); want != got {
+			t.Errorf("unexpected condition for %v, wanted %v, got %v", test.condition, want, got)
+		}
+	}
+}
+
 func TestCondition_GetTestValues(t *testing.T) {
 
 	inOutofRangeTestValues := []any{math.MinInt64, -1, 0, 1, 255, 256, 257, math.MaxInt64}
@@ -635,6 +694,7 @@ func TestCondition_GetTestValues(t *testing.T) {
 			tosca.R13_Cancun,
 			tosca.R14_Prague,
 			tosca.R15_Osaka,
+			tosca.R98_Experimental,
 			R99_UnknownNextRevision,
 		}},
 		{InRange256FromCurrentBlock(Param(0)), inOutofRangeTestValues},
@@ -780,3 +840,216 @@ func BenchmarkCondition_IsAddressWarmCheckCold(b *testing.B) {
 		}
 	}
 }
+
+func TestCondition_NotIsComplementOfCondition(t *testing.T) {
+	conditions := []Condition{
+		Eq(Gas(), tosca.Gas(42)),
+		Ne(Gas(), tosca.Gas(42)),
+		Lt(Gas(), tosca.Gas(42)),
+		Le(Gas(), tosca.Gas(42)),
+		Gt(Gas(), tosca.Gas(42)),
+		Ge(Gas(), tosca.Gas(42)),
+		IsRevision(tosca.R10_London),
+		RevisionBounds(tosca.R09_Berlin, tosca.R12_Shanghai),
+		RevisionBounds(MinRevision, tosca.R12_Shanghai),
+		IsCode(Pc()),
+		IsData(Pc()),
+		IsStorageWarm(Param(0)),
+		IsStorageCold(Param(0)),
+		IsAddressWarm(Param(0)),
+		IsAddressCold(Param(0)),
+		AccountIsEmpty(Param(0)),
+		AccountIsNotEmpty(Param(0)),
+		IsEofCode(),
+		IsLegacyCode(),
+		IsNewContract(),
+		IsNotNewContract(),
+		HasSelfDestructed(),
+		HasNotSelfDestructed(),
+		HasBlobHash(Param(0)),
+		HasNoBlobHash(Param(0)),
+		StorageConfiguration(tosca.StorageAdded, Param(0), Param(1)),
+		And(Lt(Gas(), tosca.Gas(42)), IsRevision(tosca.R10_London)),
+		Or(Lt(Gas(), tosca.Gas(42)), IsRevision(tosca.R09_Berlin)),
+	}
+
+	rnd := rand.New(0)
+	for _, condition := range conditions {
+		negated := Not(condition)
+		for _, cur := range []Condition{condition, negated} {
+			if _, isNegation := cur.(*negation); isNegation {
+				continue // < can not be used to restrict generators
+			}
+			generator := gen.NewStateGenerator()
+			cur.Restrict(generator)
+			state, err := generator.Generate(rnd)
+			if err != nil {
+				t.Fatalf("failed to generate state for %v: %v", cur, err)
+			}
+			want, err := condition.Check(state)
+			if err != nil {
+				t.Fatalf("failed to check %v: %v", condition, err)
+			}
+			got, err := negated.Check(state)
+			if err != nil {
+				t.Fatalf("failed to check %v: %v", negated, err)
+			}
+			if want == got {
+				t.Errorf("%v and %v both evaluate to %t", condition, negated, got)
+			}
+			state.Release()
+		}
+	}
+}
+
+func TestCondition_NotOfAllRevisionsIsUnsatisfiable(t *testing.T) {
+	condition := Not(RevisionBounds(MinRevision, R99_UnknownNextRevision))
+	if want, got := "false", condition.String(); want != got {
+		t.Errorf("unexpected negation, wanted %v, got %v", want, got)
+	}
+
+	rnd := rand.New(0)
+	for _, revision := range []tosca.Revision{MinRevision, tosca.R98_Experimental, R99_UnknownNextRevision} {
+		generator := gen.NewStateGenerator()
+		generator.SetRevision(revision)
+		state, err := generator.Generate(rnd)
+		if err != nil {
+			t.Fatalf("failed to generate state: %v", err)
+		}
+		if got, err := condition.Check(state); err != nil || got {
+			t.Errorf("negation is satisfied in %v, got %t, %v", revision, got, err)
+		}
+		state.Release()
+	}
+
+	generator := gen.NewStateGenerator()
+	condition.Restrict(generator)
+	if _, err := generator.Generate(rnd); !errors.Is(err, gen.ErrUnsatisfiable) {
+		t.Errorf("restricted generator should be unsatisfiable, got %v", err)
+	}
+}
+
+func TestCondition_NotOfNotIsOriginalCondition(t *testing.T) {
+	conditions := []Condition{
+		Eq(Pc(), NewU256(42)),
+		IsCode(Pc()),
+		StorageConfiguration(tosca.StorageAdded, Param(0), Param(1)),
+	}
+	for _, condition := range conditions {
+		if got := Not(Not(condition)); got.String() != condition.String() {
+			t.Errorf("unexpected double negation of %v, got %v", condition, got)
+		}
+	}
+}
+
+func TestCondition_NegationWithoutComplementCanNotRestrictGenerators(t *testing.T) {
+	condition := Not(StorageConfiguration(tosca.StorageAdded, Param(0), Param(1)))
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("restricting a generator by %v should panic", condition)
+		}
+	}()
+	condition.Restrict(gen.NewStateGenerator())
+}
+
+func TestCondition_OrWithoutAlternativesPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("empty disjunction should panic")
+		}
+	}()
+	Or()
+}
+
+func TestCondition_OrMergesNestedDisjunctions(t *testing.T) {
+	a := Eq(Pc(), NewU256(1))
+	b := Eq(Pc(), NewU256(2))
+	c := Eq(Pc(), NewU256(3))
+	condition := Or(a, Or(b, c))
+	if want, got := fmt.Sprintf("%v ∨ %v ∨ %v", a, b, c), condition.String(); want != got {
+		t.Errorf("unexpected condition, wanted %s, got %s", want, got)
+	}
+	if Or(a) != a {
+		t.Errorf("disjunction of a single condition should be the condition itself")
+	}
+}
+
+func TestCondition_OrCheckIgnoresErrorsOfSatisfiedAlternatives(t *testing.T) {
+	state := st.NewState(st.NewCode([]byte{}))
+	state.Pc = 42
+	condition := Or(Eq(Param(0), NewU256(1)), Eq(Pc(), NewU256(42)))
+	if ok, err := condition.Check(state); !ok || err != nil {
+		t.Errorf("unexpected check result, got %t, %v", ok, err)
+	}
+	condition = Or(Eq(Param(0), NewU256(1)), Eq(Pc(), NewU256(41)))
+	if ok, err := condition.Check(state); ok || err == nil {
+		t.Errorf("unexpected check result, got %t, %v", ok, err)
+	}
+}
+
+func TestCondition_OrTestValuesCoverAllAlternatives(t *testing.T) {
+	condition := Or(Eq(Pc(), NewU256(1)), IsRevision(tosca.R10_London))
+	dimensions := getPropertyTestValues(condition)
+
+	alternatives := dimensions[Property(fmt.Sprintf("alternative(%v)", condition))]
+	if len(alternatives) != 2 {
+		t.Fatalf("unexpected number of alternatives, got %v", alternatives)
+	}
+	rnd := rand.New(0)
+	for i, alternative := range alternatives {
+		generator := gen.NewStateGenerator()
+		alternative.Restrict(generator)
+		state, err := generator.Generate(rnd)
+		if err != nil {
+			t.Fatalf("failed to generate state: %v", err)
+		}
+		var satisfied bool
+		if i == 0 {
+			satisfied = state.Pc == 1
+		} else {
+			satisfied = state.Revision == tosca.R10_London
+		}
+		if !satisfied {
+			t.Errorf("alternative %d is not enforced by its test value", i)
+		}
+	}
+	if len(dimensions[Pc().Property()]) == 0 || len(dimensions[Property("revision")]) == 0 {
+		t.Errorf("test values of alternatives are missing, got %v", dimensions)
+	}
+}
+
+func TestCondition_GetAlternativesExpandsDisjunctions(t *testing.T) {
+	a := Eq(Pc(), NewU256(1))
+	b := Eq(Pc(), NewU256(2))
+	c := Eq(Status(), st.Failed)
+	d := Eq(Status(), st.Reverted)
+
+	tests := map[string]struct {
+		condition Condition
+		want      []Condition
+	}{
+		"plain":       {a, []Condition{a}},
+		"disjunction": {Or(a, b), []Condition{a, b}},
+		"conjunction": {And(Or(a, b), Or(c, d)), []Condition{
+			And(a, c), And(a, d), And(b, c), And(b, d),
+		}},
+		"nested": {Or(And(a, Or(c, d)), b), []Condition{
+			And(a, c), And(a, d), b,
+		}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			want := []string{}
+			for _, cur := range test.want {
+				want = append(want, cur.String())
+			}
+			got := []string{}
+			for _, cur := range getAlternatives(test.condition) {
+				got = append(got, cur.String())
+			}
+			if !slices.Equal(want, got) {
+				t.Errorf("unexpected alternatives, wanted %v, got %v", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package rlz
+
+import (
+	"errors"
+	"slices"
+
+	"pgregory.net/rand"
+
+	"github.com/0xsoniclabs/tosca/go/ct/gen"
+	"github.com/0xsoniclabs/tosca/go/ct/st"
+)
+
+// Coverage is the result of a search for states not covered by a list of
+// conditions.
+type Coverage int
+
+const (
+	// Covered indicates that the constraint solver proved that every state
+	// of the searched domain satisfies at least one of the conditions.
+	Covered Coverage = iota
+	// Uncovered indicates that a witness state satisfying none of the
+	// conditions was found.
+	Uncovered
+	// Unknown indicates that the search was inconclusive, either due to an
+	// exhausted budget or conditions which could not restrict generators.
+	Unknown
+)
+
+func (c Coverage) String() string {
+	switch c {
+	case Covered:
+		return "covered"
+	case Uncovered:
+		return "uncovered"
+	case Unknown:
+		return "unknown"
+	}
+	return "invalid"
+}
+
+// FindUncoveredState searches for a state satisfying the given domain but
+// none of the given conditions. The search alternates between sampling states
+// and excluding the conditions satisfied by those samples from the searched
+// space, leaving it to the constraint solvers of the state generator to prove
+// remaining regions empty. Each sampling step consumes one unit of the budget
+// and draws at most the given number of samples. The returned witness is only
+// set if the result is Uncovered and is owned by the caller.
+func FindUncoveredState(
+	domain Condition,
+	conditions []Condition,
+	rnd *rand.Rand,
+	samples int,
+	budget int,
+) (Coverage, *st.State, error) {
+	search := coverageSearch{
+		conditions: conditions,
+		rnd:        rnd,
+		samples:    samples,
+		budget:     budget,
+	}
+	res := Covered
+	for _, alternative := range getAlternatives(domain) {
+		restricted, checked := splitRestrictable(getConditions(alternative))
+		coverage, witness, err := search.run(restricted, checked)
+		if err != nil || coverage == Uncovered {
+			return coverage, witness, err
+		}
+		if coverage == Unknown {
+			res = Unknown
+		}
+	}
+	return res, nil, nil
+}
+
+// FindSatisfyingState searches for a state satisfying the given condition.
+// Parts of the condition which can not restrict generators are checked on up
+// to the given number of samples per alternative of the condition. The error
+// gen.ErrUnsatisfiable is returned if the constraint solver proved that no
+// such state exists, while a nil state without an error indicates that no
+// sample satisfied the condition.
+func FindSatisfyingState(condition Condition, rnd *rand.Rand, samples int) (*st.State, error) {
+	satisfiable := false
+	for _, alternative := range getAlternatives(condition) {
+		restricted, checked := splitRestrictable(getConditions(alternative))
+		generator := gen.NewStateGenerator()
+		for _, condition := range restricted {
+			condition.Restrict(generator)
+		}
+		for range samples {
+			state, err := generator.Generate(rnd)
+			if errors.Is(err, gen.ErrUnsatisfiable) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			satisfiable = true
+			if holdsAll(restricted, state) && holdsAll(checked, state) {
+				return state, nil
+			}
+			state.Release()
+		}
+	}
+	if !satisfiable {
+		return nil, gen.ErrUnsatisfiable
+	}
+	return nil, nil
+}
+
+type coverageSearch struct {
+	conditions []Condition
+	rnd        *rand.Rand
+	samples    int
+	budget     int
+}
+
+// run searches the region of states described by the conjunction of the
+// restricted and checked conditions. Restricted conditions are enforced by
+// the generator, while checked conditions are only used to filter samples.
+func (s *coverageSearch) run(restricted, checked []Condition) (Coverage, *st.State, error) {
+	if s.budget <= 0 {
+		return Unknown, nil, nil
+	}
+	s.budget--
+
+	generator := gen.NewStateGenerator()
+	for _, condition := range restricted {
+		condition.Restrict(generator)
+	}
+
+	// Find a condition covering a sample of the current region.
+	var covering Condition
+	for range s.samples {
+		state, err := generator.Generate(s.rnd)
+		if errors.Is(err, gen.ErrUnsatisfiable) {
+			return Covered, nil, nil
+		}
+		if err != nil {
+			return Unknown, nil, err
+		}
+		if !holdsAll(restricted, state) || !holdsAll(checked, state) {
+			state.Release()
+			continue
+		}
+		covering = firstSatisfied(s.conditions, state)
+		if covering == nil {
+			return Uncovered, state, nil
+		}
+		state.Release()
+		break
+	}
+	if covering == nil {
+		return Unknown, nil, nil
+	}
+	if len(getConditions(covering)) == 0 {
+		return Covered, nil, nil // < covering every state
+	}
+
+	// Exclude the covering condition from the region and search the rest.
+	res := Covered
+	for _, alternative := range getAlternatives(Not(covering)) {
+		literals := getConditions(alternative)
+		if slices.ContainsFunc(literals, func(literal Condition) bool {
+			return contradicts(literal, restricted)
+		}) {
+			continue
+		}
+		newRestricted, newChecked := splitRestrictable(literals)
+		coverage, witness, err := s.run(
+			append(slices.Clone(restricted), newRestricted...),
+			append(slices.Clone(checked), newChecked...),
+		)
+		if err != nil || coverage == Uncovered {
+			return coverage, witness, err
+		}
+		if coverage == Unknown {
+			res = Unknown
+		}
+	}
+	return res, nil, nil
+}
+
+// splitRestrictable separates the given conditions into conditions able to
+// restrict generators and conditions which can only be checked.
+func splitRestrictable(conditions []Condition) (restricted, checked []Condition) {
+	for _, condition := range conditions {
+		if canRestrict(condition) {
+			restricted = append(restricted, condition)
+		} else {
+			checked = append(checked, condition)
+		}
+	}
+	return restricted, checked
+}
+
+// canRestrict tests whether the given condition can restrict generators to
+// exactly the states satisfying it. Negations and comparisons unsupported by
+// the compared expression can only be checked. So can inequalities, since they
+// restrict generators to a single value different from the excluded one.
+func canRestrict(condition Condition) (res bool) {
+	if _, ok := condition.(interface{ isInequality() }); ok {
+		return false
+	}
+	defer func() {
+		if recover() != nil {
+			res = false
+		}
+	}()
+	condition.Restrict(gen.NewStateGenerator())
+	return true
+}
+
+// contradicts returns true if the complement of the given literal is among
+// the given conditions. This resolves contradictions of literals which can
+// not be restricted, e.g. inequalities of operation codes, syntactically.
+func contradicts(literal Condition, conditions []Condition) bool {
+	complement := Not(literal).String()
+	return slices.ContainsFunc(conditions, func(condition Condition) bool {
+		return condition.String() == complement
+	})
+}
+
+func holdsAll(conditions []Condition, state *st.State) bool {
+	for _, condition := range conditions {
+		if ok, err := condition.Check(state); !ok || err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func firstSatisfied(conditions []Condition, state *st.State) Condition {
+	for _, condition := range conditions {
+		if ok, err := condition.Check(state); ok && err == nil {
+			return condition
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package rlz
+
+import (
+	"errors"
+	"testing"
+
+	"pgregory.net/rand"
+
+	"github.com/0xsoniclabs/tosca/go/ct/gen"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+)
+
+func TestFindUncoveredState_ProvesCoverage(t *testing.T) {
+	tests := map[string]struct {
+		domain     Condition
+		conditions []Condition
+	}{
+		"split": {
+			domain:     IsRevision(tosca.R10_London),
+			conditions: []Condition{Lt(Gas(), 10), Ge(Gas(), 10)},
+		},
+		"overlapping": {
+			domain:     IsRevision(tosca.R10_London),
+			conditions: []Condition{Lt(Gas(), 10), Gt(Gas(), 5)},
+		},
+		"unrestrictable_literals": {
+			domain: And(Eq(Op(Pc()), vm.ADD), IsCode(Pc())),
+			conditions: []Condition{
+				And(Eq(Op(Pc()), vm.ADD), Lt(Gas(), 5)),
+				And(Eq(Op(Pc()), vm.ADD), Ge(Gas(), 5)),
+			},
+		},
+		"disjunction": {
+			domain:     Or(IsRevision(tosca.R09_Berlin), IsRevision(tosca.R10_London)),
+			conditions: []Condition{Or(Lt(Gas(), 10), Ge(Gas(), 10))},
+		},
+		"anything": {
+			domain:     IsRevision(tosca.R10_London),
+			conditions: []Condition{And()},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			coverage, witness, err := FindUncoveredState(test.domain, test.conditions, rand.New(0), 10, 100)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if coverage != Covered || witness != nil {
+				t.Errorf("unexpected result, wanted %v, got %v with witness %v", Covered, coverage, witness)
+			}
+		})
+	}
+}
+
+func TestFindUncoveredState_FindsWitness(t *testing.T) {
+	domain := And(IsRevision(tosca.R10_London), Eq(Op(Pc()), vm.ADD), IsCode(Pc()))
+	conditions := []Condition{Lt(Gas(), 10), Gt(Gas(), 10)}
+	coverage, witness, err := FindUncoveredState(domain, conditions, rand.New(0), 10, 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if coverage != Uncovered || witness == nil {
+		t.Fatalf("unexpected result, wanted %v, got %v", Uncovered, coverage)
+	}
+	defer witness.Release()
+	if witness.Gas != 10 {
+		t.Errorf("unexpected gas of witness, wanted 10, got %d", witness.Gas)
+	}
+	if ok, err := domain.Check(witness); !ok || err != nil {
+		t.Errorf("witness is not in domain %v", domain)
+	}
+}
+
+func TestFindUncoveredState_ExhaustedBudgetIsInconclusive(t *testing.T) {
+	conditions := []Condition{Lt(Gas(), 10), Ge(Gas(), 10)}
+	coverage, _, err := FindUncoveredState(IsRevision(tosca.R10_London), conditions, rand.New(0), 10, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if coverage != Unknown {
+		t.Errorf("unexpected result, wanted %v, got %v", Unknown, coverage)
+	}
+}
+
+func TestFindSatisfyingState_ChecksUnrestrictableConditions(t *testing.T) {
+	condition := And(Lt(Gas(), 5), Ne(Op(Pc()), vm.ADD))
+	state, err := FindSatisfyingState(condition, rand.New(0), 10)
+	if err != nil || state == nil {
+		t.Fatalf("failed to find state: %v", err)
+	}
+	defer state.Release()
+	if ok, err := condition.Check(state); !ok || err != nil {
+		t.Errorf("state does not satisfy %v", condition)
+	}
+}
+
+func TestFindSatisfyingState_ReportsUnsatisfiableConditions(t *testing.T) {
+	condition := And(Lt(Gas(), 5), Ge(Gas(), 5))
+	if _, err := FindSatisfyingState(condition, rand.New(0), 10); !errors.Is(err, gen.ErrUnsatisfiable) {
+		t.Errorf("expected unsatisfiable error, got %v", err)
+	}
+}
+
+func TestCanRestrict_IdentifiesConditionsOnlySupportingChecks(t *testing.T) {
+	tests := map[Condition]bool{
+		Eq(Op(Pc()), vm.ADD): true,
+		Ne(Op(Pc()), vm.ADD): false,
+		Ne(Gas(), 5):         false,
+		Lt(Gas(), 5):         true,
+		Not(IsCode(Pc())):    true,
+		Eq(Status(), 0):      true,
+		Not(StorageConfiguration(tosca.StorageAdded, Param(0), Param(1))): false,
+	}
+	for condition, want := range tests {
+		if got := canRestrict(condition); want != got {
+			t.Errorf("unexpected result for %v, wanted %t, got %t", condition, want, got)
+		}
+	}
+}
+
+func TestCoverage_String(t *testing.T) {
+	tests := map[Coverage]string{
+		Covered:     "covered",
+		Uncovered:   "uncovered",
+		Unknown:     "unknown",
+		Coverage(7): "invalid",
+	}
+	for coverage, want := range tests {
+		if got := coverage.String(); want != got {
+			t.Errorf("unexpected string, wanted %s, got %s", want, got)
+		}
+	}
+}
@@ -197,20 +197,14 @@ func (revisionDomain) Predecessor(a tosca.Revision) tosca.Revision {
 	if a == tosca.R07_Istanbul {
 		return R99_UnknownNextRevision
 	}
-	if a == R99_UnknownNextRevision {
-		return NewestSupportedRevision
-	}
-	return a - 1
+	return GetPreviousRevision(a)
 }
 
 func (revisionDomain) Successor(a tosca.Revision) tosca.Revision {
 	if a == R99_UnknownNextRevision {
 		return tosca.R07_Istanbul
 	}
-	if a == NewestSupportedRevision {
-		return R99_UnknownNextRevision
-	}
-	return a + 1
+	return GetNextRevision(a)
 }
 
 func (d revisionDomain) SomethingNotEqual(a tosca.Revision) tosca.Revision {
@@ -223,7 +217,7 @@ func (d revisionDomain) Samples(a tosca.Revision) []tosca.Revision {
 
 func (revisionDomain) SamplesForAll(a []tosca.Revision) []tosca.Revision {
 	res := []tosca.Revision{R99_UnknownNextRevision}
-	for r := tosca.R07_Istanbul; r <= NewestSupportedRevision; r++ {
+	for r := tosca.R07_Istanbul; r <= NewestSupportedRevision; r = GetNextRevision(r) {
 		res = append(res, r)
 	}
 
@@ -12,8 +12,11 @@ package rlz
 
 import (
 	"fmt"
+	"strings"
 
+	. "github.com/0xsoniclabs/tosca/go/ct/common"
 	"github.com/0xsoniclabs/tosca/go/ct/st"
+	"github.com/0xsoniclabs/tosca/go/tosca"
 )
 
 type Effect interface {
@@ -27,11 +30,12 @@ type Effect interface {
 // Change
 
 type change struct {
-	fun func(*st.State)
+	fun  func(*st.State)
+	kind string // < the declarative kind of common effects, empty otherwise
 }
 
 func Change(fun func(*st.State)) Effect {
-	return &change{fun}
+	return &change{fun: fun}
 }
 
 func (c *change) Apply(state *st.State) {
@@ -39,18 +43,393 @@ func (c *change) Apply(state *st.State) {
 }
 
 func (c *change) String() string {
+	switch c.kind {
+	case "none":
+		return "no effect"
+	case "fail":
+		return "fail, consuming all gas"
+	}
 	return "change"
 }
 
 ////////////////////////////////////////////////////////////
 
 func NoEffect() Effect {
-	return Change(func(*st.State) {})
+	return &change{fun: func(*st.State) {}, kind: "none"}
 }
 
 func FailEffect() Effect {
-	return Change(func(s *st.State) {
-		s.Status = st.Failed
-		s.Gas = 0
-	})
+	return &change{
+		fun: func(s *st.State) {
+			s.Status = st.Failed
+			s.Gas = 0
+		},
+		kind: "fail",
+	}
+}
+
+////////////////////////////////////////////////////////////
+// Sequence
+
+type sequence struct {
+	effects []Effect
+}
+
+// Sequence combines the given effects into a single effect applying them in
+// order. Once an effect ends the execution, for instance by failing due to a
+// lack of gas, the remaining effects are skipped.
+func Sequence(effects ...Effect) Effect {
+	return &sequence{effects: effects}
+}
+
+func (s *sequence) Apply(state *st.State) {
+	for _, effect := range s.effects {
+		if state.Status != st.Running {
+			return
+		}
+		effect.Apply(state)
+	}
+}
+
+func (s *sequence) String() string {
+	parts := make([]string, 0, len(s.effects))
+	for _, effect := range s.effects {
+		parts = append(parts, effect.String())
+	}
+	return strings.Join(parts, "; ")
+}
+
+////////////////////////////////////////////////////////////
+// Gas
+
+type consumeGas struct {
+	amount tosca.Gas
+}
+
+// ConsumeGas reduces the gas of the state by the given amount. The rule's
+// condition is expected to ensure that sufficient gas is available.
+func ConsumeGas(amount tosca.Gas) Effect {
+	return &consumeGas{amount}
+}
+
+func (c *consumeGas) Apply(state *st.State) {
+	state.Gas -= c.amount
+}
+
+func (c *consumeGas) String() string {
+	return fmt.Sprintf("consume %d gas", c.amount)
+}
+
+type consumeStaticGas struct {
+	amount tosca.Gas
+}
+
+// ConsumeStaticGas reduces the gas of the state by the static gas price of the
+// executed operation. Other than ConsumeGas, it marks the amount as the price
+// of the operation, which may be changed by a gas schedule.
+func ConsumeStaticGas(amount tosca.Gas) Effect {
+	return &consumeStaticGas{amount}
+}
+
+func (c *consumeStaticGas) Apply(state *st.State) {
+	state.Gas -= c.amount
+}
+
+func (c *consumeStaticGas) String() string {
+	return fmt.Sprintf("consume %d static gas", c.amount)
+}
+
+type consumeDynamicGas struct {
+	kind tosca.DynamicGasKind
+}
+
+// ConsumeDynamicGas reduces the gas of the state by the Ethereum price of the
+// given kind of dynamic gas. The rule's condition is expected to ensure that
+// sufficient gas is available.
+func ConsumeDynamicGas(kind tosca.DynamicGasKind) Effect {
+	return &consumeDynamicGas{kind}
+}
+
+func (c *consumeDynamicGas) Apply(state *st.State) {
+	state.Gas -= c.kind.EthereumPrice()
+}
+
+func (c *consumeDynamicGas) String() string {
+	return fmt.Sprintf("consume %v (%d gas)", c.kind, c.kind.EthereumPrice())
+}
+
+// GetGasPrices returns the static gas and the kinds of dynamic gas consumed by
+// the given effect.
+func GetGasPrices(effect Effect) (static tosca.Gas, dynamic []tosca.DynamicGasKind) {
+	var visit func(Effect)
+	visit = func(effect Effect) {
+		switch e := effect.(type) {
+		case *sequence:
+			for _, part := range e.effects {
+				visit(part)
+			}
+		case *consumeStaticGas:
+			static += e.amount
+		case *consumeDynamicGas:
+			dynamic = append(dynamic, e.kind)
+		}
+	}
+	visit(effect)
+	return static, dynamic
+}
+
+type addGasRefund struct {
+	amount tosca.Gas
+}
+
+// AddGasRefund adds the given, potentially negative, amount to the gas refund.
+func AddGasRefund(amount tosca.Gas) Effect {
+	return &addGasRefund{amount}
+}
+
+func (a *addGasRefund) Apply(state *st.State) {
+	state.GasRefund += a.amount
+}
+
+func (a *addGasRefund) String() string {
+	return fmt.Sprintf("add %d to gas refund", a.amount)
+}
+
+////////////////////////////////////////////////////////////
+// Program Counter
+
+type incrementPc struct {
+	amount uint16
+}
+
+// IncrementPc advances the program counter by the given amount.
+func IncrementPc(amount uint16) Effect {
+	return &incrementPc{amount}
+}
+
+func (i *incrementPc) Apply(state *st.State) {
+	state.Pc += i.amount
+}
+
+func (i *incrementPc) String() string {
+	return fmt.Sprintf("pc += %d", i.amount)
+}
+
+////////////////////////////////////////////////////////////
+// Stack
+
+type pop struct {
+	count int
+}
+
+// Pop removes the given number of elements from the stack.
+func Pop(count int) Effect {
+	return &pop{count}
+}
+
+func (p *pop) Apply(state *st.State) {
+	for range p.count {
+		state.Stack.Pop()
+	}
+}
+
+func (p *pop) String() string {
+	return fmt.Sprintf("pop %d", p.count)
+}
+
+type dup struct {
+	position int
+}
+
+// Dup pushes a copy of the stack element at the given 1-based position.
+func Dup(position int) Effect {
+	return &dup{position}
+}
+
+func (d *dup) Apply(state *st.State) {
+	state.Stack.Push(state.Stack.Get(d.position - 1))
+}
+
+func (d *dup) String() string {
+	return fmt.Sprintf("duplicate stack element %d", d.position)
+}
+
+type swap struct {
+	position int
+}
+
+// Swap exchanges the top of the stack with the element at the given position.
+func Swap(position int) Effect {
+	return &swap{position}
+}
+
+func (s *swap) Apply(state *st.State) {
+	a := state.Stack.Get(0)
+	b := state.Stack.Get(s.position)
+	state.Stack.Set(0, b)
+	state.Stack.Set(s.position, a)
+}
+
+func (s *swap) String() string {
+	return fmt.Sprintf("swap top of stack with element %d", s.position)
+}
+
+type push struct {
+	value BindableExpression[U256]
+}
+
+// Push pushes the value of the given expression, evaluated before the push,
+// on the stack.
+func Push(value BindableExpression[U256]) Effect {
+	return &push{value}
+}
+
+func (p *push) Apply(state *st.State) {
+	value, err := p.value.Eval(state)
+	if err != nil {
+		state.Status = st.Failed
+		return
+	}
+	state.Stack.Push(value)
+}
+
+func (p *push) String() string {
+	return fmt.Sprintf("push %v", p.value)
+}
+
+type pushData struct {
+	size int
+}
+
+// PushData pushes the given number of immediate data bytes following the
+// current position in the code on the stack and skips them.
+func PushData(size int) Effect {
+	return &pushData{size}
+}
+
+func (p *pushData) Apply(state *st.State) {
+	data := make([]byte, p.size)
+	for i := range p.size {
+		b, err := state.Code.GetData(int(state.Pc) + i)
+		// This panic will never be triggered because the code generator always ensures that
+		// after a PUSHX op there are X data bytes. This should be fixed by #592
+		if err != nil {
+			panic(err)
+		}
+		data[i] = b
+	}
+	state.Stack.Push(NewU256FromBytes(data...))
+	state.Pc += uint16(p.size)
+}
+
+func (p *pushData) String() string {
+	return fmt.Sprintf("push %d bytes of code data and skip them", p.size)
+}
+
+////////////////////////////////////////////////////////////
+// Memory
+
+type expandMemory struct {
+	offset BindableExpression[U256]
+	size   BindableExpression[U256]
+}
+
+// ExpandMemory grows the memory to cover the given range, consuming the gas
+// for the expansion. If the gas is insufficient, the execution fails.
+func ExpandMemory(offset, size BindableExpression[U256]) Effect {
+	return &expandMemory{offset, size}
+}
+
+func (e *expandMemory) Apply(state *st.State) {
+	offset, err1 := e.offset.Eval(state)
+	size, err2 := e.size.Eval(state)
+	if err1 != nil || err2 != nil {
+		state.Status = st.Failed
+		return
+	}
+	cost, offset64, size64 := state.Memory.ExpansionCosts(offset, size)
+	if state.Gas < cost {
+		state.Status = st.Failed
+		return
+	}
+	state.Gas -= cost
+	state.Memory.Grow(offset64, size64)
+}
+
+func (e *expandMemory) String() string {
+	return fmt.Sprintf("expand memory to cover %v bytes at %v", e.size, e.offset)
+}
+
+////////////////////////////////////////////////////////////
+// Storage
+
+type setStorage struct {
+	key   BindableExpression[U256]
+	value BindableExpression[U256]
+}
+
+// SetStorage updates the current value of a storage slot.
+func SetStorage(key, value BindableExpression[U256]) Effect {
+	return &setStorage{key, value}
+}
+
+func (s *setStorage) Apply(state *st.State) {
+	key, err1 := s.key.Eval(state)
+	value, err2 := s.value.Eval(state)
+	if err1 != nil || err2 != nil {
+		state.Status = st.Failed
+		return
+	}
+	state.Storage.SetCurrent(key, value)
+}
+
+func (s *setStorage) String() string {
+	return fmt.Sprintf("storage[%v] = %v", s.key, s.value)
+}
+
+type markStorageWarm struct {
+	key BindableExpression[U256]
+}
+
+// MarkStorageWarm adds a storage slot to the set of accessed slots.
+func MarkStorageWarm(key BindableExpression[U256]) Effect {
+	return &markStorageWarm{key}
+}
+
+func (m *markStorageWarm) Apply(state *st.State) {
+	key, err := m.key.Eval(state)
+	if err != nil {
+		state.Status = st.Failed
+		return
+	}
+	state.Storage.MarkWarm(key)
+}
+
+func (m *markStorageWarm) String() string {
+	return fmt.Sprintf("mark storage[%v] warm", m.key)
+}
+
+////////////////////////////////////////////////////////////
+// Accounts
+
+type markAddressWarm struct {
+	address BindableExpression[U256]
+}
+
+// MarkAddressWarm adds an account to the set of accessed accounts.
+func MarkAddressWarm(address BindableExpression[U256]) Effect {
+	return &markAddressWarm{address}
+}
+
+func (m *markAddressWarm) Apply(state *st.State) {
+	address, err := m.address.Eval(state)
+	if err != nil {
+		state.Status = st.Failed
+		return
+	}
+	state.Accounts.MarkWarm(NewAddress(address))
+}
+
+func (m *markAddressWarm) String() string {
+	return fmt.Sprintf("mark account %v warm", m.address)
 }
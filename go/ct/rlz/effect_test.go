@@ -11,9 +11,13 @@
 package rlz
 
 import (
+	"slices"
 	"testing"
 
+	. "github.com/0xsoniclabs/tosca/go/ct/common"
 	"github.com/0xsoniclabs/tosca/go/ct/st"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
 )
 
 func TestEffect_Change(t *testing.T) {
@@ -66,3 +70,164 @@ func TestEffect_FailEffect(t *testing.T) {
 		t.Errorf("effect should have set gas to 0")
 	}
 }
+
+func TestEffect_SequenceAppliesEffectsInOrder(t *testing.T) {
+	state := st.NewState(st.NewCode([]byte{}))
+	state.Gas = 10
+
+	Sequence(ConsumeGas(3), IncrementPc(2), Push(Constant(NewU256(7))), Pop(1), Push(Constant(NewU256(8)))).Apply(state)
+
+	if state.Gas != 7 || state.Pc != 2 {
+		t.Errorf("unexpected gas and pc, got %d and %d", state.Gas, state.Pc)
+	}
+	if state.Stack.Size() != 1 || state.Stack.Get(0) != NewU256(8) {
+		t.Errorf("unexpected stack, got %v", state.Stack)
+	}
+}
+
+func TestEffect_GasEffectsConsumeStaticAndDynamicGas(t *testing.T) {
+	state := st.NewState(st.NewCode([]byte{}))
+	state.Gas = 5000
+
+	Sequence(
+		ConsumeStaticGas(5),
+		ConsumeDynamicGas(tosca.ColdAccountAccessGas),
+		ConsumeGas(20),
+	).Apply(state)
+
+	if want, got := tosca.Gas(5000-5-2600-20), state.Gas; want != got {
+		t.Errorf("unexpected gas, wanted %d, got %d", want, got)
+	}
+}
+
+func TestEffect_GetGasPricesListsStaticAndDynamicGas(t *testing.T) {
+	effect := Sequence(
+		ConsumeStaticGas(5),
+		ConsumeDynamicGas(tosca.ColdSstoreSurchargeGas),
+		IncrementPc(1),
+		ConsumeGas(20),
+		Sequence(ConsumeDynamicGas(tosca.WarmSstoreGas)),
+	)
+	static, dynamic := GetGasPrices(effect)
+	if want, got := tosca.Gas(5), static; want != got {
+		t.Errorf("unexpected static gas, wanted %d, got %d", want, got)
+	}
+	want := []tosca.DynamicGasKind{tosca.ColdSstoreSurchargeGas, tosca.WarmSstoreGas}
+	if !slices.Equal(want, dynamic) {
+		t.Errorf("unexpected dynamic gas, wanted %v, got %v", want, dynamic)
+	}
+
+	static, dynamic = GetGasPrices(FailEffect())
+	if static != 0 || len(dynamic) != 0 {
+		t.Errorf("failing effect should not consume any gas, got %d and %v", static, dynamic)
+	}
+}
+
+func TestEffect_SequenceSkipsEffectsOnceExecutionEnded(t *testing.T) {
+	state := st.NewState(st.NewCode([]byte{}))
+	state.Gas = 10
+
+	Sequence(FailEffect(), IncrementPc(1)).Apply(state)
+
+	if state.Status != st.Failed || state.Pc != 0 {
+		t.Errorf("effects after failure should be skipped")
+	}
+}
+
+func TestEffect_StackEffects(t *testing.T) {
+	newState := func() *st.State {
+		state := st.NewState(st.NewCode([]byte{}))
+		state.Stack.Push(NewU256(1))
+		state.Stack.Push(NewU256(2))
+		state.Stack.Push(NewU256(3))
+		return state
+	}
+
+	tests := map[string]struct {
+		effect Effect
+		want   []U256 // < top of stack first
+	}{
+		"pop":  {Pop(2), []U256{NewU256(1)}},
+		"dup":  {Dup(3), []U256{NewU256(1), NewU256(3), NewU256(2), NewU256(1)}},
+		"swap": {Swap(2), []U256{NewU256(1), NewU256(2), NewU256(3)}},
+		"push": {Push(Param(1)), []U256{NewU256(2), NewU256(3), NewU256(2), NewU256(1)}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			state := newState()
+			test.effect.Apply(state)
+			if state.Stack.Size() != len(test.want) {
+				t.Fatalf("unexpected stack size, wanted %d, got %d", len(test.want), state.Stack.Size())
+			}
+			for i, want := range test.want {
+				if got := state.Stack.Get(i); got != want {
+					t.Errorf("unexpected stack element %d, wanted %v, got %v", i, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestEffect_PushDataPushesAndSkipsCodeData(t *testing.T) {
+	state := st.NewState(st.NewCode([]byte{byte(vm.PUSH2), 1, 2}))
+	state.Pc = 1
+
+	PushData(2).Apply(state)
+
+	if state.Pc != 3 {
+		t.Errorf("unexpected pc, wanted 3, got %d", state.Pc)
+	}
+	if want, got := NewU256(0x0102), state.Stack.Get(0); got != want {
+		t.Errorf("unexpected value, wanted %v, got %v", want, got)
+	}
+}
+
+func TestEffect_ExpandMemoryConsumesGasForExpansion(t *testing.T) {
+	state := st.NewState(st.NewCode([]byte{}))
+	state.Gas = 10
+	state.Stack.Push(NewU256(32))
+
+	ExpandMemory(Param(0), Constant(NewU256(32))).Apply(state)
+
+	if state.Status != st.Running || state.Gas != 4 || state.Memory.Size() != 64 {
+		t.Errorf("unexpected state after expansion, gas %d, memory size %d", state.Gas, state.Memory.Size())
+	}
+}
+
+func TestEffect_ExpandMemoryFailsWithoutSufficientGas(t *testing.T) {
+	state := st.NewState(st.NewCode([]byte{}))
+	state.Gas = 5
+	state.Stack.Push(NewU256(32))
+
+	ExpandMemory(Param(0), Constant(NewU256(32))).Apply(state)
+
+	if state.Status != st.Failed {
+		t.Errorf("expansion should have failed")
+	}
+}
+
+func TestEffect_StorageAndAccountEffects(t *testing.T) {
+	state := st.NewState(st.NewCode([]byte{}))
+	state.Stack.Push(NewU256(2))
+	state.Stack.Push(NewU256(1))
+
+	Sequence(
+		SetStorage(Param(0), Param(1)),
+		MarkStorageWarm(Param(0)),
+		MarkAddressWarm(Param(1)),
+		AddGasRefund(12),
+	).Apply(state)
+
+	if got := state.Storage.GetCurrent(NewU256(1)); got != NewU256(2) {
+		t.Errorf("unexpected storage value, got %v", got)
+	}
+	if !state.Storage.IsWarm(NewU256(1)) {
+		t.Errorf("storage slot should be warm")
+	}
+	if !state.Accounts.IsWarm(NewAddress(NewU256(2))) {
+		t.Errorf("account should be warm")
+	}
+	if state.GasRefund != 12 {
+		t.Errorf("unexpected gas refund, got %d", state.GasRefund)
+	}
+}
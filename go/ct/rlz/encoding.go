@@ -0,0 +1,763 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package rlz
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	. "github.com/0xsoniclabs/tosca/go/ct/common"
+	"github.com/0xsoniclabs/tosca/go/ct/st"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+)
+
+// Node is a declarative, serializable description of a condition, an
+// expression, a parameter, or an effect. The kind names the described
+// element, the value holds constants like compared values, and the arguments
+// describe nested conditions and expressions.
+type Node struct {
+	Kind      string          `json:"kind"`
+	Value     json.RawMessage `json:"value,omitempty"`
+	Arguments []Node          `json:"args,omitempty"`
+}
+
+func newNode(kind string, args ...Node) Node {
+	return Node{Kind: kind, Arguments: args}
+}
+
+func newValueNode(kind string, value any, args ...Node) (Node, error) {
+	encoded, err := encodeValue(value)
+	if err != nil {
+		return Node{}, err
+	}
+	return Node{Kind: kind, Value: encoded, Arguments: args}, nil
+}
+
+////////////////////////////////////////////////////////////
+// Conditions
+
+// comparison is implemented by all comparing conditions to provide access to
+// their operands independent of the compared type.
+type comparison interface {
+	comparison() (kind string, lhs any, rhs any)
+}
+
+func (e *eq[T]) comparison() (string, any, any) { return "eq", e.lhs, e.rhs }
+func (e *ne[T]) comparison() (string, any, any) { return "ne", e.lhs, e.rhs }
+func (c *lt[T]) comparison() (string, any, any) { return "lt", c.lhs, c.rhs }
+func (c *le[T]) comparison() (string, any, any) { return "le", c.lhs, c.rhs }
+func (c *gt[T]) comparison() (string, any, any) { return "gt", c.lhs, c.rhs }
+func (c *ge[T]) comparison() (string, any, any) { return "ge", c.lhs, c.rhs }
+
+// EncodeCondition produces the declarative description of a condition.
+func EncodeCondition(condition Condition) (Node, error) {
+	switch c := condition.(type) {
+	case *conjunction:
+		return encodeConditions("and", c.conditions)
+	case *disjunction:
+		return encodeConditions("or", c.conditions)
+	case *negation:
+		return encodeConditions("not", []Condition{c.condition})
+	case comparison:
+		kind, lhs, rhs := c.comparison()
+		arg, err := encodeExpression(lhs)
+		if err != nil {
+			return Node{}, err
+		}
+		return newValueNode(kind, rhs, arg)
+	case *revisionBounds:
+		return newValueNode("revision_bounds", []tosca.Revision{c.min, c.max})
+	case *isCode:
+		return encodeExpressions("is_code", c.position)
+	case *isData:
+		return encodeExpressions("is_data", c.position)
+	case *isStorageWarm:
+		return encodeExpressions("is_storage_warm", c.key)
+	case *isStorageCold:
+		return encodeExpressions("is_storage_cold", c.key)
+	case *storageConfiguration:
+		node, err := encodeExpressions("storage_configuration", c.key, c.newValue)
+		if err != nil {
+			return Node{}, err
+		}
+		node.Value, err = encodeValue(c.config.String())
+		return node, err
+	case *bindTransientStorageToNonZero:
+		return encodeExpressions("transient_storage_is_non_zero", c.key)
+	case *bindTransientStorageToZero:
+		return encodeExpressions("transient_storage_is_zero", c.key)
+	case *accountIsEmpty:
+		return encodeExpressions("account_is_empty", c.address)
+	case *accountIsNotEmpty:
+		return encodeExpressions("account_is_not_empty", c.address)
+	case *isAddressWarm:
+		return encodeExpressions("is_address_warm", c.key)
+	case *isAddressCold:
+		return encodeExpressions("is_address_cold", c.key)
+	case *falseCondition:
+		return newNode("false"), nil
+	case *isEofCode:
+		return newNode("is_eof_code"), nil
+	case *isLegacyCode:
+		return newNode("is_legacy_code"), nil
+	case *isNewContract:
+		return newNode("is_new_contract"), nil
+	case *isNotNewContract:
+		return newNode("is_not_new_contract"), nil
+	case *hasSelfDestructed:
+		return newNode("has_self_destructed"), nil
+	case *hasNotSelfDestructed:
+		return newNode("has_not_self_destructed"), nil
+	case *inRange256FromCurrentBlock:
+		return encodeExpressions("in_range_256_from_current_block", c.blockNumber)
+	case *outOfRange256FromCurrentBlock:
+		return encodeExpressions("out_of_range_256_from_current_block", c.blockNumber)
+	case *hasBlobHash:
+		return encodeExpressions("has_blob_hash", c.index)
+	case *hasNoBlobHash:
+		return encodeExpressions("has_no_blob_hash", c.index)
+	case *containsDelegationDesignation:
+		node, err := encodeExpressions("delegation_designator", c.address)
+		if err != nil {
+			return Node{}, err
+		}
+		node.Value, err = encodeValue(c.state.String())
+		return node, err
+	}
+	return Node{}, fmt.Errorf("unsupported condition %v", condition)
+}
+
+func encodeConditions(kind string, conditions []Condition) (Node, error) {
+	node := newNode(kind)
+	for _, condition := range conditions {
+		arg, err := EncodeCondition(condition)
+		if err != nil {
+			return Node{}, err
+		}
+		node.Arguments = append(node.Arguments, arg)
+	}
+	return node, nil
+}
+
+// DecodeCondition reconstructs a condition from its declarative description.
+func DecodeCondition(node Node) (Condition, error) {
+	switch node.Kind {
+	case "and", "or", "not":
+		conditions := make([]Condition, 0, len(node.Arguments))
+		for _, arg := range node.Arguments {
+			condition, err := DecodeCondition(arg)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, condition)
+		}
+		switch {
+		case node.Kind == "and":
+			return And(conditions...), nil
+		case len(conditions) == 0:
+			return nil, fmt.Errorf("%s requires at least one argument", node.Kind)
+		case node.Kind == "or":
+			return Or(conditions...), nil
+		case len(conditions) != 1:
+			return nil, fmt.Errorf("not requires exactly one argument")
+		}
+		return &negation{conditions[0]}, nil
+	case "eq", "ne", "lt", "le", "gt", "ge":
+		if len(node.Arguments) != 1 {
+			return nil, fmt.Errorf("%s requires exactly one argument", node.Kind)
+		}
+		lhs, err := decodeExpression(node.Arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		switch lhs := lhs.(type) {
+		case Expression[U256]:
+			return decodeComparison(node.Kind, lhs, node.Value)
+		case Expression[tosca.Address]:
+			return decodeComparison(node.Kind, lhs, node.Value)
+		case Expression[tosca.Gas]:
+			return decodeComparison(node.Kind, lhs, node.Value)
+		case Expression[st.StatusCode]:
+			return decodeComparison(node.Kind, lhs, node.Value)
+		case Expression[vm.OpCode]:
+			return decodeComparison(node.Kind, lhs, node.Value)
+		case Expression[bool]:
+			return decodeComparison(node.Kind, lhs, node.Value)
+		case Expression[int]:
+			return decodeComparison(node.Kind, lhs, node.Value)
+		}
+		return nil, fmt.Errorf("unsupported operand of %s: %v", node.Kind, lhs)
+	case "revision_bounds":
+		bounds, err := decodeValue[[]tosca.Revision](node.Value)
+		if err != nil {
+			return nil, err
+		}
+		if len(bounds) != 2 || bounds[0] > bounds[1] {
+			return nil, fmt.Errorf("invalid revision bounds: %v", bounds)
+		}
+		return RevisionBounds(bounds[0], bounds[1]), nil
+	case "storage_configuration":
+		args, err := decodeBindableArguments(node, 2)
+		if err != nil {
+			return nil, err
+		}
+		name, err := decodeValue[string](node.Value)
+		if err != nil {
+			return nil, err
+		}
+		for _, config := range tosca.GetAllStorageStatuses() {
+			if config.String() == name {
+				return StorageConfiguration(config, args[0], args[1]), nil
+			}
+		}
+		return nil, fmt.Errorf("unknown storage configuration %q", name)
+	case "delegation_designator":
+		args, err := decodeBindableArguments(node, 1)
+		if err != nil {
+			return nil, err
+		}
+		name, err := decodeValue[string](node.Value)
+		if err != nil {
+			return nil, err
+		}
+		for _, state := range []DelegationDesignatorState{NoDelegationDesignation, WarmDelegationDesignation, ColdDelegationDesignation} {
+			if state.String() == name {
+				return ConstraintDelegationDesignator(args[0], state), nil
+			}
+		}
+		return nil, fmt.Errorf("unknown delegation designator state %q", name)
+	}
+
+	if constructor, found := atomicConditions[node.Kind]; found {
+		if len(node.Arguments) != 0 {
+			return nil, fmt.Errorf("%s does not accept arguments", node.Kind)
+		}
+		return constructor(), nil
+	}
+	if constructor, found := unaryConditions[node.Kind]; found {
+		args, err := decodeBindableArguments(node, 1)
+		if err != nil {
+			return nil, err
+		}
+		return constructor(args[0]), nil
+	}
+	return nil, fmt.Errorf("unknown condition kind %q", node.Kind)
+}
+
+var atomicConditions = map[string]func() Condition{
+	"false":                   False,
+	"is_eof_code":             IsEofCode,
+	"is_legacy_code":          IsLegacyCode,
+	"is_new_contract":         IsNewContract,
+	"is_not_new_contract":     IsNotNewContract,
+	"has_self_destructed":     HasSelfDestructed,
+	"has_not_self_destructed": HasNotSelfDestructed,
+}
+
+var unaryConditions = map[string]func(BindableExpression[U256]) Condition{
+	"is_code":                             IsCode,
+	"is_data":                             IsData,
+	"is_storage_warm":                     IsStorageWarm,
+	"is_storage_cold":                     IsStorageCold,
+	"transient_storage_is_non_zero":       BindTransientStorageToNonZero,
+	"transient_storage_is_zero":           BindTransientStorageToZero,
+	"account_is_empty":                    func(e BindableExpression[U256]) Condition { return AccountIsEmpty(e) },
+	"account_is_not_empty":                func(e BindableExpression[U256]) Condition { return AccountIsNotEmpty(e) },
+	"is_address_warm":                     IsAddressWarm,
+	"is_address_cold":                     IsAddressCold,
+	"in_range_256_from_current_block":     InRange256FromCurrentBlock,
+	"out_of_range_256_from_current_block": OutOfRange256FromCurrentBlock,
+	"has_blob_hash":                       HasBlobHash,
+	"has_no_blob_hash":                    HasNoBlobHash,
+}
+
+func decodeComparison[T any](kind string, lhs Expression[T], value json.RawMessage) (Condition, error) {
+	rhs, err := decodeValue[T](value)
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case "eq":
+		return Eq(lhs, rhs), nil
+	case "ne":
+		return Ne(lhs, rhs), nil
+	case "lt":
+		return Lt(lhs, rhs), nil
+	case "le":
+		return Le(lhs, rhs), nil
+	case "gt":
+		return Gt(lhs, rhs), nil
+	}
+	return Ge(lhs, rhs), nil
+}
+
+////////////////////////////////////////////////////////////
+// Expressions
+
+func encodeExpression(expression any) (Node, error) {
+	switch e := expression.(type) {
+	case status:
+		return newNode("status"), nil
+	case pc:
+		return newNode("pc"), nil
+	case gas:
+		return newNode("gas"), nil
+	case selfAddress:
+		return newNode("self_address"), nil
+	case readOnly:
+		return newNode("read_only"), nil
+	case stackSize:
+		return newNode("stack_size"), nil
+	case returnStackSize:
+		return newNode("return_stack_size"), nil
+	case balance:
+		return encodeExpressions("balance", e.account)
+	case op:
+		return encodeExpressions("op", e.position)
+	case param:
+		if _, isValue := e.domain.(valueDomain); isValue {
+			return newValueNode("value_param", e.position)
+		}
+		return newValueNode("param", e.position)
+	case constant:
+		return newValueNode("constant", e.value)
+	case toAddress:
+		return encodeExpressions("to_address", e.expr)
+	}
+	return Node{}, fmt.Errorf("unsupported expression %v", expression)
+}
+
+func encodeExpressions(kind string, expressions ...any) (Node, error) {
+	node := newNode(kind)
+	for _, expression := range expressions {
+		arg, err := encodeExpression(expression)
+		if err != nil {
+			return Node{}, err
+		}
+		node.Arguments = append(node.Arguments, arg)
+	}
+	return node, nil
+}
+
+func decodeExpression(node Node) (any, error) {
+	switch node.Kind {
+	case "status":
+		return Status(), nil
+	case "pc":
+		return Pc(), nil
+	case "gas":
+		return Gas(), nil
+	case "self_address":
+		return SelfAddress(), nil
+	case "read_only":
+		return ReadOnly(), nil
+	case "stack_size":
+		return StackSize(), nil
+	case "return_stack_size":
+		return ReturnStackSize(), nil
+	case "balance":
+		if len(node.Arguments) != 1 {
+			return nil, fmt.Errorf("balance requires exactly one argument")
+		}
+		account, err := decodeExpression(node.Arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		if account, ok := account.(BindableExpression[tosca.Address]); ok {
+			return Balance(account), nil
+		}
+		return nil, fmt.Errorf("invalid account of balance: %v", account)
+	case "op":
+		args, err := decodeBindableArguments(node, 1)
+		if err != nil {
+			return nil, err
+		}
+		return Op(args[0]), nil
+	case "param", "value_param":
+		position, err := decodeValue[int](node.Value)
+		if err != nil {
+			return nil, err
+		}
+		if node.Kind == "value_param" {
+			return ValueParam(position), nil
+		}
+		return Param(position), nil
+	case "constant":
+		value, err := decodeValue[U256](node.Value)
+		if err != nil {
+			return nil, err
+		}
+		return Constant(value), nil
+	case "to_address":
+		args, err := decodeBindableArguments(node, 1)
+		if err != nil {
+			return nil, err
+		}
+		return ToAddress(args[0]), nil
+	}
+	return nil, fmt.Errorf("unknown expression kind %q", node.Kind)
+}
+
+func decodeBindableArguments(node Node, count int) ([]BindableExpression[U256], error) {
+	if len(node.Arguments) != count {
+		return nil, fmt.Errorf("%s requires %d argument(s), got %d", node.Kind, count, len(node.Arguments))
+	}
+	res := make([]BindableExpression[U256], 0, count)
+	for _, arg := range node.Arguments {
+		expression, err := decodeExpression(arg)
+		if err != nil {
+			return nil, err
+		}
+		bindable, ok := expression.(BindableExpression[U256])
+		if !ok {
+			return nil, fmt.Errorf("invalid argument of %s: %v", node.Kind, expression)
+		}
+		res = append(res, bindable)
+	}
+	return res, nil
+}
+
+////////////////////////////////////////////////////////////
+// Parameters
+
+var parameterKinds = map[reflect.Type]string{
+	reflect.TypeFor[NumericParameter]():      "numeric",
+	reflect.TypeFor[JumpTargetParameter]():   "jump_target",
+	reflect.TypeFor[MemoryOffsetParameter](): "memory_offset",
+	reflect.TypeFor[DataOffsetParameter]():   "data_offset",
+	reflect.TypeFor[SizeParameter]():         "size",
+	reflect.TypeFor[InitCodeSizeParameter](): "init_code_size",
+	reflect.TypeFor[TopicParameter]():        "topic",
+	reflect.TypeFor[AddressParameter]():      "address",
+	reflect.TypeFor[GasParameter]():          "gas",
+	reflect.TypeFor[ValueParameter]():        "value",
+}
+
+var parametersByKind = map[string]Parameter{
+	"numeric":        NumericParameter{},
+	"jump_target":    JumpTargetParameter{},
+	"memory_offset":  MemoryOffsetParameter{},
+	"data_offset":    DataOffsetParameter{},
+	"size":           SizeParameter{},
+	"init_code_size": InitCodeSizeParameter{},
+	"topic":          TopicParameter{},
+	"address":        AddressParameter{},
+	"gas":            GasParameter{},
+	"value":          ValueParameter{},
+}
+
+// EncodeParameter produces the declarative description of a parameter.
+func EncodeParameter(parameter Parameter) (Node, error) {
+	if kind, found := parameterKinds[reflect.TypeOf(parameter)]; found {
+		return newNode(kind), nil
+	}
+	return Node{}, fmt.Errorf("unsupported parameter %T", parameter)
+}
+
+// DecodeParameter reconstructs a parameter from its declarative description.
+func DecodeParameter(node Node) (Parameter, error) {
+	if parameter, found := parametersByKind[node.Kind]; found {
+		return parameter, nil
+	}
+	return nil, fmt.Errorf("unknown parameter kind %q", node.Kind)
+}
+
+////////////////////////////////////////////////////////////
+// Effects
+
+// EffectRegistry resolves references to effects implemented in Go. Effects
+// are arbitrary state transitions and can thus not be described by
+// declarative elements in general.
+type EffectRegistry map[string]Effect
+
+// Register adds the parts of the given effect without a declarative
+// description to the registry, using the references assigned to them by
+// EncodeEffect for the same reference.
+func (r EffectRegistry) Register(effect Effect, reference string) {
+	visitCustomEffects(effect, reference, func(reference string, effect Effect) {
+		r[reference] = effect
+	})
+}
+
+// visitCustomEffects calls the visitor for all parts of the given effect not
+// having a declarative description. The first part is referenced by the given
+// reference, further parts by the reference with their 1-based index
+// appended, e.g. "name#2".
+func visitCustomEffects(effect Effect, reference string, visit func(string, Effect)) {
+	count := 0
+	var visitEffect func(Effect)
+	visitEffect = func(effect Effect) {
+		switch e := effect.(type) {
+		case *sequence:
+			for _, part := range e.effects {
+				visitEffect(part)
+			}
+		case *change:
+			if e.kind != "" {
+				return
+			}
+			count++
+			if count == 1 {
+				visit(reference, e)
+			} else {
+				visit(fmt.Sprintf("%s#%d", reference, count), e)
+			}
+		}
+	}
+	visitEffect(effect)
+}
+
+// DescribeEffect produces a human-readable description of an effect. Parts of
+// effects without a declarative description are named by the references
+// EncodeEffect would use for them.
+func DescribeEffect(effect Effect, reference string) string {
+	references := map[Effect]string{}
+	visitCustomEffects(effect, reference, func(reference string, effect Effect) {
+		references[effect] = reference
+	})
+	var describe func(Effect) string
+	describe = func(effect Effect) string {
+		switch e := effect.(type) {
+		case *sequence:
+			parts := make([]string, 0, len(e.effects))
+			for _, part := range e.effects {
+				parts = append(parts, describe(part))
+			}
+			return strings.Join(parts, "; ")
+		case *change:
+			if reference, found := references[e]; found {
+				return fmt.Sprintf("custom change %s", reference)
+			}
+		}
+		return effect.String()
+	}
+	return describe(effect)
+}
+
+// EncodeEffect produces the declarative description of an effect. Parts of
+// effects without a declarative description are represented by references
+// derived from the given reference, to be resolved by an EffectRegistry
+// filled using Register when decoding.
+func EncodeEffect(effect Effect, reference string) (Node, error) {
+	references := map[Effect]string{}
+	visitCustomEffects(effect, reference, func(reference string, effect Effect) {
+		references[effect] = reference
+	})
+	return encodeEffect(effect, references)
+}
+
+func encodeEffect(effect Effect, references map[Effect]string) (Node, error) {
+	switch e := effect.(type) {
+	case *change:
+		if e.kind != "" {
+			return newNode(e.kind), nil
+		}
+		return newValueNode("ref", references[e])
+	case *sequence:
+		node := newNode("sequence")
+		for _, part := range e.effects {
+			arg, err := encodeEffect(part, references)
+			if err != nil {
+				return Node{}, err
+			}
+			node.Arguments = append(node.Arguments, arg)
+		}
+		return node, nil
+	case *consumeGas:
+		return newValueNode("consume_gas", e.amount)
+	case *consumeStaticGas:
+		return newValueNode("consume_static_gas", e.amount)
+	case *consumeDynamicGas:
+		return newValueNode("consume_dynamic_gas", e.kind)
+	case *addGasRefund:
+		return newValueNode("add_gas_refund", e.amount)
+	case *incrementPc:
+		return newValueNode("increment_pc", e.amount)
+	case *pop:
+		return newValueNode("pop", e.count)
+	case *dup:
+		return newValueNode("dup", e.position)
+	case *swap:
+		return newValueNode("swap", e.position)
+	case *push:
+		return encodeExpressions("push", e.value)
+	case *pushData:
+		return newValueNode("push_data", e.size)
+	case *expandMemory:
+		return encodeExpressions("expand_memory", e.offset, e.size)
+	case *setStorage:
+		return encodeExpressions("set_storage", e.key, e.value)
+	case *markStorageWarm:
+		return encodeExpressions("mark_storage_warm", e.key)
+	case *markAddressWarm:
+		return encodeExpressions("mark_address_warm", e.address)
+	}
+	return Node{}, fmt.Errorf("unsupported effect %v", effect)
+}
+
+// DecodeEffect reconstructs an effect from its declarative description.
+func DecodeEffect(node Node, registry EffectRegistry) (Effect, error) {
+	switch node.Kind {
+	case "none":
+		return NoEffect(), nil
+	case "fail":
+		return FailEffect(), nil
+	case "ref":
+		reference, err := decodeValue[string](node.Value)
+		if err != nil {
+			return nil, err
+		}
+		if effect, found := registry[reference]; found {
+			return effect, nil
+		}
+		return nil, fmt.Errorf("unknown effect reference %q", reference)
+	case "sequence":
+		effects := make([]Effect, 0, len(node.Arguments))
+		for _, arg := range node.Arguments {
+			effect, err := DecodeEffect(arg, registry)
+			if err != nil {
+				return nil, err
+			}
+			effects = append(effects, effect)
+		}
+		return Sequence(effects...), nil
+	case "consume_gas", "consume_static_gas", "add_gas_refund":
+		amount, err := decodeValue[tosca.Gas](node.Value)
+		if err != nil {
+			return nil, err
+		}
+		switch node.Kind {
+		case "consume_gas":
+			return ConsumeGas(amount), nil
+		case "consume_static_gas":
+			return ConsumeStaticGas(amount), nil
+		}
+		return AddGasRefund(amount), nil
+	case "consume_dynamic_gas":
+		kind, err := decodeValue[tosca.DynamicGasKind](node.Value)
+		if err != nil {
+			return nil, err
+		}
+		return ConsumeDynamicGas(kind), nil
+	case "increment_pc":
+		amount, err := decodeValue[uint16](node.Value)
+		if err != nil {
+			return nil, err
+		}
+		return IncrementPc(amount), nil
+	case "pop", "dup", "swap", "push_data":
+		value, err := decodeValue[int](node.Value)
+		if err != nil {
+			return nil, err
+		}
+		if value < 0 || value > st.MaxStackSize {
+			return nil, fmt.Errorf("invalid argument of %s: %d", node.Kind, value)
+		}
+		switch node.Kind {
+		case "pop":
+			return Pop(value), nil
+		case "dup":
+			return Dup(value), nil
+		case "swap":
+			return Swap(value), nil
+		}
+		return PushData(value), nil
+	case "push", "mark_storage_warm", "mark_address_warm":
+		args, err := decodeBindableArguments(node, 1)
+		if err != nil {
+			return nil, err
+		}
+		switch node.Kind {
+		case "push":
+			return Push(args[0]), nil
+		case "mark_storage_warm":
+			return MarkStorageWarm(args[0]), nil
+		}
+		return MarkAddressWarm(args[0]), nil
+	case "expand_memory", "set_storage":
+		args, err := decodeBindableArguments(node, 2)
+		if err != nil {
+			return nil, err
+		}
+		if node.Kind == "expand_memory" {
+			return ExpandMemory(args[0], args[1]), nil
+		}
+		return SetStorage(args[0], args[1]), nil
+	}
+	return nil, fmt.Errorf("unknown effect kind %q", node.Kind)
+}
+
+////////////////////////////////////////////////////////////
+// Values
+
+// encodeValue serializes constants of conditions and effects. Numbers are
+// encoded in decimal, operation codes and dynamic gas kinds by their names to
+// keep descriptions readable.
+func encodeValue(value any) (json.RawMessage, error) {
+	switch v := value.(type) {
+	case U256:
+		return json.Marshal(v.DecimalString())
+	case vm.OpCode:
+		return json.Marshal(v.String())
+	case tosca.DynamicGasKind:
+		return json.Marshal(v.String())
+	}
+	return json.Marshal(value)
+}
+
+func decodeValue[T any](data json.RawMessage) (T, error) {
+	var res T
+	switch target := any(&res).(type) {
+	case *U256:
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return res, err
+		}
+		value, ok := new(big.Int).SetString(text, 10)
+		if !ok || value.Sign() < 0 || value.BitLen() > 256 {
+			return res, fmt.Errorf("invalid 256-bit value %q", text)
+		}
+		*target = NewU256FromBigInt(value)
+		return res, nil
+	case *vm.OpCode:
+		var name string
+		if err := json.Unmarshal(data, &name); err != nil {
+			return res, err
+		}
+		for i := range 256 {
+			if op := vm.OpCode(i); op.String() == name {
+				*target = op
+				return res, nil
+			}
+		}
+		return res, fmt.Errorf("unknown operation %q", name)
+	case *tosca.DynamicGasKind:
+		var name string
+		if err := json.Unmarshal(data, &name); err != nil {
+			return res, err
+		}
+		for _, kind := range tosca.GetAllDynamicGasKinds() {
+			if kind.String() == name {
+				*target = kind
+				return res, nil
+			}
+		}
+		return res, fmt.Errorf("unknown dynamic gas kind %q", name)
+	}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return res, err
+	}
+	return res, nil
+}
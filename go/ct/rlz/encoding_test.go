@@ -0,0 +1,285 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package rlz
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	. "github.com/0xsoniclabs/tosca/go/ct/common"
+	"github.com/0xsoniclabs/tosca/go/ct/st"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+)
+
+func TestEncoding_ConditionsCanBeEncodedAndDecoded(t *testing.T) {
+	conditions := []Condition{
+		And(),
+		And(Eq(Status(), st.Running), Lt(Gas(), 12)),
+		Or(IsRevision(tosca.R10_London), Ne(ReadOnly(), true)),
+		Not(StorageConfiguration(tosca.StorageAdded, Param(0), Param(1))),
+		Eq(Op(Pc()), vm.ADD),
+		Eq(Op(Constant(NewU256(12))), vm.JUMPDEST),
+		Le(StackSize(), 1023),
+		Ge(ReturnStackSize(), 1024),
+		Gt(Balance(SelfAddress()), NewU256(1, 2)),
+		Ge(Param(2), MaxU256()),
+		Eq(ValueParam(1), NewU256(0)),
+		Eq(SelfAddress(), tosca.Address{1, 2, 3}),
+		Lt(Balance(ToAddress(Param(1))), NewU256(42)),
+		RevisionBounds(tosca.R09_Berlin, tosca.R13_Cancun),
+		IsCode(Pc()),
+		IsData(Param(0)),
+		IsStorageWarm(Param(0)),
+		IsStorageCold(Param(0)),
+		BindTransientStorageToNonZero(Param(0)),
+		BindTransientStorageToZero(Param(0)),
+		AccountIsEmpty(Param(0)),
+		AccountIsNotEmpty(Param(0)),
+		IsAddressWarm(Param(0)),
+		IsAddressCold(Param(0)),
+		False(),
+		IsEofCode(),
+		IsLegacyCode(),
+		IsNewContract(),
+		IsNotNewContract(),
+		HasSelfDestructed(),
+		HasNotSelfDestructed(),
+		InRange256FromCurrentBlock(Param(0)),
+		OutOfRange256FromCurrentBlock(Param(0)),
+		HasBlobHash(Param(0)),
+		HasNoBlobHash(Param(0)),
+		ConstraintDelegationDesignator(Param(1), WarmDelegationDesignation),
+	}
+
+	for _, condition := range conditions {
+		t.Run(condition.String(), func(t *testing.T) {
+			node, err := EncodeCondition(condition)
+			if err != nil {
+				t.Fatalf("failed to encode condition: %v", err)
+			}
+			encoded, err := json.Marshal(node)
+			if err != nil {
+				t.Fatalf("failed to serialize condition: %v", err)
+			}
+			var parsed Node
+			if err := json.Unmarshal(encoded, &parsed); err != nil {
+				t.Fatalf("failed to parse condition: %v", err)
+			}
+			restored, err := DecodeCondition(parsed)
+			if err != nil {
+				t.Fatalf("failed to decode condition: %v", err)
+			}
+			if want, got := condition.String(), restored.String(); want != got {
+				t.Errorf("unexpected condition, wanted %v, got %v", want, got)
+			}
+			if !reflect.DeepEqual(condition, restored) {
+				t.Errorf("restored condition differs from %v", condition)
+			}
+		})
+	}
+}
+
+func TestEncoding_ConditionsAreHumanReadable(t *testing.T) {
+	tests := map[string]Condition{
+		`{"kind":"eq","value":"ADD","args":[{"kind":"op","args":[{"kind":"pc"}]}]}`:                                                        Eq(Op(Pc()), vm.ADD),
+		`{"kind":"lt","value":"1024","args":[{"kind":"param","value":1}]}`:                                                                 Lt(Param(1), NewU256(1024)),
+		`{"kind":"eq","value":"running","args":[{"kind":"status"}]}`:                                                                       Eq(Status(), st.Running),
+		`{"kind":"revision_bounds","value":["Berlin","London"]}`:                                                                           RevisionBounds(tosca.R09_Berlin, tosca.R10_London),
+		`{"kind":"not","args":[{"kind":"delegation_designator","value":"no_delegation_designation","args":[{"kind":"param","value":0}]}]}`: Not(ConstraintDelegationDesignator(Param(0), NoDelegationDesignation)),
+	}
+	for want, condition := range tests {
+		node, err := EncodeCondition(condition)
+		if err != nil {
+			t.Fatalf("failed to encode %v: %v", condition, err)
+		}
+		got, err := json.Marshal(node)
+		if err != nil {
+			t.Fatalf("failed to serialize %v: %v", condition, err)
+		}
+		if want != string(got) {
+			t.Errorf("unexpected encoding of %v, wanted %s, got %s", condition, want, got)
+		}
+	}
+}
+
+func TestEncoding_InvalidConditionsAreDetected(t *testing.T) {
+	tests := map[string]Node{
+		"unknown kind":       {Kind: "unknown"},
+		"missing argument":   {Kind: "is_code"},
+		"empty disjunction":  {Kind: "or"},
+		"invalid operation":  {Kind: "eq", Value: json.RawMessage(`"NOPE"`), Arguments: []Node{{Kind: "op", Arguments: []Node{{Kind: "pc"}}}}},
+		"invalid number":     {Kind: "eq", Value: json.RawMessage(`"-1"`), Arguments: []Node{{Kind: "param", Value: json.RawMessage(`0`)}}},
+		"invalid bounds":     {Kind: "revision_bounds", Value: json.RawMessage(`["London","Berlin"]`)},
+		"unknown expression": {Kind: "is_code", Arguments: []Node{{Kind: "unknown"}}},
+		"unknown storage":    {Kind: "storage_configuration", Value: json.RawMessage(`"unknown"`), Arguments: []Node{{Kind: "pc"}, {Kind: "pc"}}},
+		"unexpected operand": {Kind: "is_new_contract", Arguments: []Node{{Kind: "pc"}}},
+	}
+	for name, node := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := DecodeCondition(node); err == nil {
+				t.Errorf("expected decoding of %v to fail", node)
+			}
+		})
+	}
+}
+
+func TestEncoding_ParametersCanBeEncodedAndDecoded(t *testing.T) {
+	parameters := []Parameter{
+		NumericParameter{},
+		JumpTargetParameter{},
+		MemoryOffsetParameter{},
+		DataOffsetParameter{},
+		SizeParameter{},
+		InitCodeSizeParameter{},
+		TopicParameter{},
+		AddressParameter{},
+		GasParameter{},
+		ValueParameter{},
+	}
+	for _, parameter := range parameters {
+		node, err := EncodeParameter(parameter)
+		if err != nil {
+			t.Fatalf("failed to encode %T: %v", parameter, err)
+		}
+		restored, err := DecodeParameter(node)
+		if err != nil {
+			t.Fatalf("failed to decode %T: %v", parameter, err)
+		}
+		if parameter != restored {
+			t.Errorf("unexpected parameter, wanted %T, got %T", parameter, restored)
+		}
+	}
+	if _, err := DecodeParameter(Node{Kind: "unknown"}); err == nil {
+		t.Errorf("expected decoding of unknown parameter to fail")
+	}
+}
+
+func TestEncoding_EffectsAreDescribedOrReferenced(t *testing.T) {
+	custom := Change(func(s *st.State) { s.Pc++ })
+	other := Change(func(s *st.State) { s.Gas++ })
+
+	tests := []struct {
+		effect Effect
+		kind   string
+	}{
+		{NoEffect(), "none"},
+		{FailEffect(), "fail"},
+		{custom, "ref"},
+		{ConsumeGas(5), "consume_gas"},
+		{ConsumeStaticGas(3), "consume_static_gas"},
+		{ConsumeDynamicGas(tosca.ColdSloadGas), "consume_dynamic_gas"},
+		{AddGasRefund(-7), "add_gas_refund"},
+		{IncrementPc(2), "increment_pc"},
+		{Pop(1), "pop"},
+		{Dup(2), "dup"},
+		{Swap(1), "swap"},
+		{Push(Param(1)), "push"},
+		{PushData(2), "push_data"},
+		{ExpandMemory(Param(0), Constant(NewU256(32))), "expand_memory"},
+		{SetStorage(Param(0), Param(1)), "set_storage"},
+		{MarkStorageWarm(Param(0)), "mark_storage_warm"},
+		{MarkAddressWarm(Param(1)), "mark_address_warm"},
+		{Sequence(ConsumeGas(3), custom, Pop(1), other), "sequence"},
+	}
+	for _, test := range tests {
+		node, err := EncodeEffect(test.effect, "custom")
+		if err != nil {
+			t.Fatalf("failed to encode %v: %v", test.effect, err)
+		}
+		if node.Kind != test.kind {
+			t.Errorf("unexpected kind, wanted %s, got %s", test.kind, node.Kind)
+		}
+		registry := EffectRegistry{}
+		registry.Register(test.effect, "custom")
+		restored, err := DecodeEffect(node, registry)
+		if err != nil {
+			t.Fatalf("failed to decode effect: %v", err)
+		}
+
+		want := getEffectTestState()
+		got := getEffectTestState()
+		test.effect.Apply(want)
+		restored.Apply(got)
+		if !want.Eq(got) {
+			t.Errorf("restored %s effect behaves differently", test.kind)
+		}
+	}
+
+	node, err := EncodeEffect(custom, "missing")
+	if err != nil {
+		t.Fatalf("failed to encode effect: %v", err)
+	}
+	if _, err := DecodeEffect(node, EffectRegistry{}); err == nil {
+		t.Errorf("expected decoding of unknown reference to fail")
+	}
+}
+
+func TestEncoding_CustomEffectPartsAreReferencedInOrder(t *testing.T) {
+	first := Change(func(s *st.State) { s.Pc++ })
+	second := Change(func(s *st.State) { s.Gas++ })
+	effect := Sequence(first, Pop(1), Sequence(second))
+
+	registry := EffectRegistry{}
+	registry.Register(effect, "rule")
+	if len(registry) != 2 || registry["rule"] != first || registry["rule#2"] != second {
+		t.Errorf("unexpected registry content: %v", registry)
+	}
+
+	node, err := EncodeEffect(effect, "rule")
+	if err != nil {
+		t.Fatalf("failed to encode effect: %v", err)
+	}
+	data, err := json.Marshal(node)
+	if err != nil {
+		t.Fatalf("failed to marshal effect: %v", err)
+	}
+	want := `{"kind":"sequence","args":[{"kind":"ref","value":"rule"},{"kind":"pop","value":1},{"kind":"sequence","args":[{"kind":"ref","value":"rule#2"}]}]}`
+	if string(data) != want {
+		t.Errorf("unexpected encoding, wanted %s, got %s", want, data)
+	}
+}
+
+func TestEncoding_EffectsAreDescribedWithReferencesOfCustomParts(t *testing.T) {
+	effect := Sequence(
+		ConsumeStaticGas(3),
+		Change(func(s *st.State) { s.Pc++ }),
+		Sequence(Pop(1), Change(func(s *st.State) { s.Gas++ })),
+		FailEffect(),
+	)
+	want := "consume 3 static gas; custom change rule; pop 1; custom change rule#2; fail, consuming all gas"
+	if got := DescribeEffect(effect, "rule"); want != got {
+		t.Errorf("unexpected description, wanted %q, got %q", want, got)
+	}
+}
+
+func TestEncoding_UnknownEffectsAreRejected(t *testing.T) {
+	if _, err := DecodeEffect(Node{Kind: "unknown"}, EffectRegistry{}); err == nil {
+		t.Errorf("expected decoding of unknown effect to fail")
+	}
+	if _, err := DecodeEffect(Node{Kind: "pop", Value: json.RawMessage("-1")}, EffectRegistry{}); err == nil {
+		t.Errorf("expected decoding of negative pop count to fail")
+	}
+	if _, err := DecodeEffect(Node{Kind: "push"}, EffectRegistry{}); err == nil {
+		t.Errorf("expected decoding of push without argument to fail")
+	}
+}
+
+func getEffectTestState() *st.State {
+	state := st.NewState(st.NewCode([]byte{byte(vm.PUSH2), 1, 2, byte(vm.STOP)}))
+	state.Gas = 1000
+	state.Pc = 1 // < at the data of the PUSH2 instruction
+	state.Stack.Push(NewU256(1))
+	state.Stack.Push(NewU256(2))
+	state.Stack.Push(NewU256(3))
+	return state
+}
@@ -350,6 +350,43 @@ func (stackSize) String() string {
 	return "stackSize"
 }
 
+////////////////////////////////////////////////////////////
+// Return Stack Size
+
+type returnStackSize struct{}
+
+// ReturnStackSize is the number of pending CALLF instructions of EOF code.
+func ReturnStackSize() Expression[int] {
+	return returnStackSize{}
+}
+
+func (returnStackSize) Property() Property { return Property("returnStackSize") }
+
+func (returnStackSize) Domain() Domain[int] { return stackSizeDomain{} }
+
+func (returnStackSize) Eval(s *st.State) (int, error) {
+	return len(s.ReturnStack), nil
+}
+
+func (returnStackSize) Restrict(kind RestrictionKind, size int, generator *gen.StateGenerator) {
+	switch kind {
+	case RestrictLess:
+		generator.AddReturnStackSizeUpperBound(size - 1)
+	case RestrictLessEqual:
+		generator.AddReturnStackSizeUpperBound(size)
+	case RestrictEqual:
+		generator.SetReturnStackSize(size)
+	case RestrictGreaterEqual:
+		generator.AddReturnStackSizeLowerBound(size)
+	case RestrictGreater:
+		generator.AddReturnStackSizeLowerBound(size + 1)
+	}
+}
+
+func (returnStackSize) String() string {
+	return "returnStackSize"
+}
+
 ////////////////////////////////////////////////////////////
 // Instruction Parameter
 
@@ -245,6 +245,37 @@ func TestExpression_StackSizeRestrict(t *testing.T) {
 	}
 }
 
+func TestExpression_ReturnStackSizeRestrict(t *testing.T) {
+	tests := map[string]struct {
+		kind  RestrictionKind
+		size  int
+		check func(int) bool
+	}{
+		"Less":         {RestrictLess, 5, func(size int) bool { return size < 5 }},
+		"LessEqual":    {RestrictLessEqual, 5, func(size int) bool { return size <= 5 }},
+		"Equal":        {RestrictEqual, 3, func(size int) bool { return size == 3 }},
+		"GreaterEqual": {RestrictGreaterEqual, 5, func(size int) bool { return size >= 5 }},
+		"Greater":      {RestrictGreater, 5, func(size int) bool { return size > 5 }},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			generator := gen.NewStateGenerator()
+			IsEofCode().Restrict(generator)
+			ReturnStackSize().Restrict(test.kind, test.size, generator)
+
+			state, err := generator.Generate(rand.New(0))
+			if err != nil {
+				t.Fatalf("State generation failed %v", err)
+			}
+			size, err := ReturnStackSize().Eval(state)
+			if err != nil || size != len(state.ReturnStack) || !test.check(size) {
+				t.Errorf("Generator was not restricted by expression. got: %d", size)
+			}
+		})
+	}
+}
+
 func TestConstant_HumanFriendlyPrinting(t *testing.T) {
 	tests := []struct {
 		expression BindableExpression[U256]
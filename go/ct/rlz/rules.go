@@ -30,11 +30,26 @@ type Rule struct {
 	Effect    Effect
 }
 
-// GenerateSatisfyingState produces an st.State satisfying this Rule.
+// GenerateSatisfyingState produces an st.State satisfying this Rule. For
+// conditions with disjunctions, alternatives are tried in random order until
+// a satisfiable one is found.
 func (r *Rule) GenerateSatisfyingState(rnd *rand.Rand) (*st.State, error) {
-	generator := gen.NewStateGenerator()
-	r.Condition.Restrict(generator)
-	return generator.Generate(rnd)
+	alternatives := getAlternatives(r.Condition)
+	order := []int{0}
+	if len(alternatives) > 1 {
+		order = rnd.Perm(len(alternatives))
+	}
+	var err error
+	for _, i := range order {
+		generator := gen.NewStateGenerator()
+		alternatives[i].Restrict(generator)
+		var state *st.State
+		state, err = generator.Generate(rnd)
+		if !errors.Is(err, gen.ErrUnsatisfiable) {
+			return state, err
+		}
+	}
+	return nil, err
 }
 
 // EnumerateTestCases generates interesting st.States according to this Rule.
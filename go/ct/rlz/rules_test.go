@@ -11,6 +11,7 @@
 package rlz
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -18,7 +19,9 @@ import (
 	"pgregory.net/rand"
 
 	. "github.com/0xsoniclabs/tosca/go/ct/common"
+	"github.com/0xsoniclabs/tosca/go/ct/gen"
 	"github.com/0xsoniclabs/tosca/go/ct/st"
+	"github.com/0xsoniclabs/tosca/go/tosca"
 	"github.com/0xsoniclabs/tosca/go/tosca/vm"
 )
 
@@ -33,6 +36,10 @@ func TestRule_GenerateSatisfyingState(t *testing.T) {
 		And(Eq(Op(Constant(NewU256(12))), vm.ADD), Eq(Op(Constant(NewU256(3))), vm.JUMP)),
 		And(Eq(Balance(SelfAddress()), NewU256(42))),
 		And(Gt(Balance(SelfAddress()), NewU256(0))),
+		Or(Eq(Status(), st.Failed), Eq(Pc(), NewU256(42))),
+		Or(And(Eq(Pc(), NewU256(1)), Eq(Pc(), NewU256(2))), Eq(Pc(), NewU256(3))), // < first alternative is unsatisfiable
+		Not(And(Eq(Status(), st.Failed), Eq(Pc(), NewU256(42)))),
+		Not(Or(Eq(Status(), st.Failed), Lt(Gas(), tosca.Gas(42)))),
 	}
 
 	rnd := rand.New(0)
@@ -54,6 +61,16 @@ func TestRule_GenerateSatisfyingState(t *testing.T) {
 	}
 }
 
+func TestRule_GenerateSatisfyingState_FailsIfAllAlternativesAreUnsatisfiable(t *testing.T) {
+	rule := Rule{Condition: Or(
+		And(Eq(Pc(), NewU256(1)), Eq(Pc(), NewU256(2))),
+		And(Eq(Pc(), NewU256(3)), Eq(Pc(), NewU256(4))),
+	)}
+	if _, err := rule.GenerateSatisfyingState(rand.New(0)); !errors.Is(err, gen.ErrUnsatisfiable) {
+		t.Errorf("expected unsatisfiable error, got %v", err)
+	}
+}
+
 func TestRule_EnumerateTestCases(t *testing.T) {
 	tests := []Condition{
 		And(), // = anything
@@ -0,0 +1,181 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package spc
+
+import (
+	"errors"
+	"slices"
+
+	"pgregory.net/rand"
+
+	"github.com/0xsoniclabs/tosca/go/ct/gen"
+	. "github.com/0xsoniclabs/tosca/go/ct/rlz"
+	"github.com/0xsoniclabs/tosca/go/ct/st"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+)
+
+// Conflict is a state satisfying the conditions of two rules with different
+// effects on it.
+type Conflict struct {
+	Rules   [2]Rule
+	Witness *st.State
+}
+
+// OverlapReport summarizes the pairwise analysis of rule conditions.
+type OverlapReport struct {
+	Pairs      int // number of analyzed pairs of rules
+	Disjoint   int // pairs proven to be disjoint by the constraint solver
+	Consistent int // overlapping pairs with equal effects on all samples
+	Unknown    int // pairs for which no sample satisfied both conditions
+	Conflicts  []Conflict
+}
+
+// CheckOverlaps analyses all pairs of the given rules which may apply to the
+// same state. For each pair, the constraint solver of the state generator
+// either proves the conditions disjoint, or produces states satisfying both
+// conditions on which the effects of the rules are compared. The effects of
+// overlapping rules are compared on up to the given number of samples.
+func CheckOverlaps(rules []Rule, rnd *rand.Rand, samples int) (OverlapReport, error) {
+	ops := make([]string, len(rules))
+	for i, rule := range rules {
+		ops[i] = ruleToOpString(rule)
+	}
+	report := OverlapReport{}
+	for i, first := range rules {
+		for j := i + 1; j < len(rules); j++ {
+			// Rules of different operations can only overlap if one of them
+			// is not bound to an operation.
+			if ops[i] != ops[j] && ops[i] != "noOp" && ops[j] != "noOp" {
+				continue
+			}
+			report.Pairs++
+			if err := checkOverlap(first, rules[j], rnd, samples, &report); err != nil {
+				return report, err
+			}
+		}
+	}
+	return report, nil
+}
+
+func checkOverlap(first, second Rule, rnd *rand.Rand, samples int, report *OverlapReport) error {
+	both := And(first.Condition, second.Condition)
+	overlapping := false
+	for range samples {
+		state, err := FindSatisfyingState(both, rnd, samples)
+		if errors.Is(err, gen.ErrUnsatisfiable) {
+			report.Disjoint++
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if state == nil {
+			break
+		}
+		overlapping = true
+
+		s0 := state.Clone()
+		s1 := state.Clone()
+		first.Effect.Apply(s0)
+		second.Effect.Apply(s1)
+		equal := s0.Eq(s1)
+		s0.Release()
+		s1.Release()
+		if !equal {
+			report.Conflicts = append(report.Conflicts, Conflict{
+				Rules:   [2]Rule{first, second},
+				Witness: state,
+			})
+			return nil
+		}
+		state.Release()
+	}
+	if overlapping {
+		report.Consistent++
+	} else {
+		report.Unknown++
+	}
+	return nil
+}
+
+// Gap is a state of a running operation for which no rule is defined.
+type Gap struct {
+	Op       vm.OpCode
+	Revision tosca.Revision
+	Witness  *st.State
+}
+
+// Cell identifies the region of states executing an operation in a revision.
+type Cell struct {
+	Op       vm.OpCode
+	Revision tosca.Revision
+}
+
+// CompletenessReport summarizes the coverage analysis of the state space.
+type CompletenessReport struct {
+	Covered int    // number of cells proven to be covered
+	Unknown []Cell // cells for which the analysis was inconclusive
+	Gaps    []Gap
+}
+
+// CheckCompleteness analyses whether every state executing one of the given
+// operations in one of the given revisions is covered by at least one of the
+// given rules. For each combination, either the constraint solver proves
+// coverage, or a witness state not covered by any rule is reported. Samples
+// and budget limit the search per combination, see rlz.FindUncoveredState.
+func CheckCompleteness(
+	rules []Rule,
+	ops []vm.OpCode,
+	revisions []tosca.Revision,
+	rnd *rand.Rand,
+	samples int,
+	budget int,
+) (CompletenessReport, error) {
+	groups := groupByOperation(rules)
+	report := CompletenessReport{}
+	for _, op := range ops {
+		conditions := []Condition{}
+		for _, rule := range slices.Concat(groups[op.String()], groups["noOp"]) {
+			conditions = append(conditions, rule.Condition)
+		}
+		for _, revision := range revisions {
+			domain := And(
+				IsRevision(revision),
+				Eq(Status(), st.Running),
+				Eq(Op(Pc()), op),
+				IsCode(Pc()),
+			)
+			coverage, witness, err := FindUncoveredState(domain, conditions, rnd, samples, budget)
+			if err != nil {
+				return report, err
+			}
+			switch coverage {
+			case Covered:
+				report.Covered++
+			case Uncovered:
+				report.Gaps = append(report.Gaps, Gap{Op: op, Revision: revision, Witness: witness})
+			case Unknown:
+				report.Unknown = append(report.Unknown, Cell{Op: op, Revision: revision})
+			}
+		}
+	}
+	return report, nil
+}
+
+func groupByOperation(rules []Rule) map[string][]Rule {
+	res := map[string][]Rule{}
+	for _, rule := range rules {
+		op := ruleToOpString(rule)
+		res[op] = append(res[op], rule)
+	}
+	return res
+}
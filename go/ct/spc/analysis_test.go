@@ -0,0 +1,141 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package spc
+
+import (
+	"testing"
+
+	"pgregory.net/rand"
+
+	"github.com/0xsoniclabs/tosca/go/ct/common"
+	"github.com/0xsoniclabs/tosca/go/ct/rlz"
+	"github.com/0xsoniclabs/tosca/go/ct/st"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+)
+
+func TestAnalysis_CheckOverlapsClassifiesPairs(t *testing.T) {
+	rules := []rlz.Rule{
+		{
+			Name:      "add_low_gas",
+			Condition: rlz.And(rlz.Eq(rlz.Op(rlz.Pc()), vm.ADD), rlz.Lt(rlz.Gas(), 3)),
+			Effect:    rlz.FailEffect(),
+		},
+		{
+			Name:      "add_high_gas",
+			Condition: rlz.And(rlz.Eq(rlz.Op(rlz.Pc()), vm.ADD), rlz.Ge(rlz.Gas(), 3)),
+			Effect:    rlz.NoEffect(),
+		},
+		{
+			Name:      "add_medium_gas",
+			Condition: rlz.And(rlz.Eq(rlz.Op(rlz.Pc()), vm.ADD), rlz.Lt(rlz.Gas(), 5)),
+			Effect:    rlz.NoEffect(),
+		},
+		{
+			Name:      "sub",
+			Condition: rlz.Eq(rlz.Op(rlz.Pc()), vm.SUB),
+			Effect:    rlz.FailEffect(),
+		},
+	}
+
+	report, err := CheckOverlaps(rules, rand.New(0), 10)
+	if err != nil {
+		t.Fatalf("failed to check overlaps: %v", err)
+	}
+	if want, got := 3, report.Pairs; want != got {
+		t.Errorf("unexpected number of pairs, wanted %d, got %d", want, got)
+	}
+	if want, got := 1, report.Disjoint; want != got {
+		t.Errorf("unexpected number of disjoint pairs, wanted %d, got %d", want, got)
+	}
+	if want, got := 1, report.Consistent; want != got {
+		t.Errorf("unexpected number of consistent pairs, wanted %d, got %d", want, got)
+	}
+	if len(report.Conflicts) != 1 {
+		t.Fatalf("unexpected conflicts, got %v", report.Conflicts)
+	}
+
+	conflict := report.Conflicts[0]
+	if conflict.Rules[0].Name != "add_low_gas" || conflict.Rules[1].Name != "add_medium_gas" {
+		t.Errorf("unexpected conflicting rules, got %s and %s", conflict.Rules[0].Name, conflict.Rules[1].Name)
+	}
+	for _, rule := range conflict.Rules {
+		if ok, err := rule.Condition.Check(conflict.Witness); !ok || err != nil {
+			t.Errorf("witness does not satisfy %v", rule.Condition)
+		}
+	}
+}
+
+func TestAnalysis_CheckCompletenessFindsGaps(t *testing.T) {
+	rules := []rlz.Rule{
+		{
+			Name:      "add_low_gas",
+			Condition: rlz.And(rlz.Eq(rlz.Op(rlz.Pc()), vm.ADD), rlz.Lt(rlz.Gas(), 3)),
+			Effect:    rlz.FailEffect(),
+		},
+		{
+			Name:      "add_high_gas",
+			Condition: rlz.And(rlz.Eq(rlz.Op(rlz.Pc()), vm.ADD), rlz.Gt(rlz.Gas(), 3)),
+			Effect:    rlz.NoEffect(),
+		},
+		{
+			Name:      "sub",
+			Condition: rlz.Eq(rlz.Op(rlz.Pc()), vm.SUB),
+			Effect:    rlz.FailEffect(),
+		},
+	}
+
+	ops := []vm.OpCode{vm.ADD, vm.SUB}
+	revisions := []tosca.Revision{tosca.R10_London}
+	report, err := CheckCompleteness(rules, ops, revisions, rand.New(0), 10, 100)
+	if err != nil {
+		t.Fatalf("failed to check completeness: %v", err)
+	}
+	if want, got := 1, report.Covered; want != got {
+		t.Errorf("unexpected number of covered cells, wanted %d, got %d", want, got)
+	}
+	if len(report.Unknown) != 0 {
+		t.Errorf("unexpected inconclusive cells: %v", report.Unknown)
+	}
+	if len(report.Gaps) != 1 {
+		t.Fatalf("unexpected gaps, got %v", report.Gaps)
+	}
+
+	gap := report.Gaps[0]
+	if gap.Op != vm.ADD || gap.Revision != tosca.R10_London {
+		t.Errorf("unexpected gap for %v in %v", gap.Op, gap.Revision)
+	}
+	if gap.Witness.Gas != 3 || gap.Witness.Status != st.Running || gap.Witness.Revision != tosca.R10_London {
+		t.Errorf("unexpected witness: %v", gap.Witness)
+	}
+}
+
+func TestAnalysis_SpecificationHasNoGaps(t *testing.T) {
+	ops := []vm.OpCode{}
+	for i := range 256 {
+		ops = append(ops, vm.OpCode(i))
+	}
+	revisions := []tosca.Revision{}
+	for revision := common.MinRevision; revision <= common.NewestSupportedRevision; revision = common.GetNextRevision(revision) {
+		revisions = append(revisions, revision)
+	}
+
+	report, err := CheckCompleteness(Spec.GetRules(), ops, revisions, rand.New(0), 10, 1000)
+	if err != nil {
+		t.Fatalf("failed to check completeness: %v", err)
+	}
+	for _, gap := range report.Gaps {
+		t.Errorf("no rule for %v in %v, witness: %v", gap.Op, gap.Revision, gap.Witness)
+	}
+	if report.Covered == 0 {
+		t.Errorf("no operation was proven to be covered")
+	}
+}
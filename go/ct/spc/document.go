@@ -0,0 +1,201 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package spc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	. "github.com/0xsoniclabs/tosca/go/ct/rlz"
+)
+
+// Document is a declarative, serializable representation of the rules of a
+// specification. Rules are sorted by name, such that documents of different
+// versions of a specification can be compared using textual diffs.
+type Document struct {
+	Rules []RuleDescription `json:"rules"`
+}
+
+// RuleDescription is the declarative representation of a single rule.
+// Effects without a declarative description refer to an implementation in
+// Go by the name of the rule.
+type RuleDescription struct {
+	Name       string `json:"name"`
+	Condition  Node   `json:"condition"`
+	Parameters []Node `json:"parameters,omitempty"`
+	Effect     Node   `json:"effect"`
+}
+
+// NewDocument produces the declarative representation of the given rules.
+func NewDocument(rules []Rule) (Document, error) {
+	res := Document{Rules: make([]RuleDescription, 0, len(rules))}
+	for _, rule := range rules {
+		condition, err := EncodeCondition(rule.Condition)
+		if err != nil {
+			return Document{}, fmt.Errorf("failed to encode condition of %s: %w", rule.Name, err)
+		}
+		parameters := make([]Node, 0, len(rule.Parameter))
+		for _, parameter := range rule.Parameter {
+			node, err := EncodeParameter(parameter)
+			if err != nil {
+				return Document{}, fmt.Errorf("failed to encode parameters of %s: %w", rule.Name, err)
+			}
+			parameters = append(parameters, node)
+		}
+		effect, err := EncodeEffect(rule.Effect, rule.Name)
+		if err != nil {
+			return Document{}, fmt.Errorf("failed to encode effect of %s: %w", rule.Name, err)
+		}
+		res.Rules = append(res.Rules, RuleDescription{
+			Name:       rule.Name,
+			Condition:  condition,
+			Parameters: parameters,
+			Effect:     effect,
+		})
+	}
+	slices.SortFunc(res.Rules, func(a, b RuleDescription) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return res, nil
+}
+
+// GetEffectRegistry provides the custom parts of the effects of the given
+// rules, to resolve the effect references of documents produced from those
+// rules.
+func GetEffectRegistry(rules []Rule) EffectRegistry {
+	res := EffectRegistry{}
+	for _, rule := range rules {
+		res.Register(rule.Effect, rule.Name)
+	}
+	return res
+}
+
+// ReadDocument parses a document in JSON format.
+func ReadDocument(reader io.Reader) (Document, error) {
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	res := Document{}
+	if err := decoder.Decode(&res); err != nil {
+		return Document{}, fmt.Errorf("failed to parse specification document: %w", err)
+	}
+	return res, nil
+}
+
+// Write serializes this document in JSON format.
+func (d Document) Write(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(d)
+}
+
+// GetRules reconstructs the rules described by this document. Effect
+// references are resolved using the given registry.
+func (d Document) GetRules(effects EffectRegistry) ([]Rule, error) {
+	res := make([]Rule, 0, len(d.Rules))
+	for _, description := range d.Rules {
+		condition, err := DecodeCondition(description.Condition)
+		if err != nil {
+			return nil, fmt.Errorf("invalid condition of %s: %w", description.Name, err)
+		}
+		parameters := make([]Parameter, 0, len(description.Parameters))
+		for _, node := range description.Parameters {
+			parameter, err := DecodeParameter(node)
+			if err != nil {
+				return nil, fmt.Errorf("invalid parameters of %s: %w", description.Name, err)
+			}
+			parameters = append(parameters, parameter)
+		}
+		effect, err := DecodeEffect(description.Effect, effects)
+		if err != nil {
+			return nil, fmt.Errorf("invalid effect of %s: %w", description.Name, err)
+		}
+		res = append(res, Rule{
+			Name:      description.Name,
+			Condition: condition,
+			Parameter: parameters,
+			Effect:    effect,
+		})
+	}
+	return res, nil
+}
+
+// Load creates a specification from the rules described by this document.
+func (d Document) Load(effects EffectRegistry) (Specification, error) {
+	rules, err := d.GetRules(effects)
+	if err != nil {
+		return nil, err
+	}
+	return NewSpecificationMap(rules...), nil
+}
+
+// RuleDiff describes a rule differing between two documents. Old is nil for
+// added rules and New is nil for removed rules.
+type RuleDiff struct {
+	Name string
+	Old  *RuleDescription
+	New  *RuleDescription
+}
+
+// Diff lists the rules added, removed, or modified between the given
+// documents, sorted by name.
+func Diff(old, new Document) ([]RuleDiff, error) {
+	before := map[string]*RuleDescription{}
+	for i := range old.Rules {
+		before[old.Rules[i].Name] = &old.Rules[i]
+	}
+	after := map[string]*RuleDescription{}
+	for i := range new.Rules {
+		after[new.Rules[i].Name] = &new.Rules[i]
+	}
+
+	res := []RuleDiff{}
+	for name, description := range before {
+		if _, found := after[name]; !found {
+			res = append(res, RuleDiff{Name: name, Old: description})
+		}
+	}
+	for name, description := range after {
+		previous, found := before[name]
+		if !found {
+			res = append(res, RuleDiff{Name: name, New: description})
+			continue
+		}
+		equal, err := isEqualDescription(previous, description)
+		if err != nil {
+			return nil, err
+		}
+		if !equal {
+			res = append(res, RuleDiff{Name: name, Old: previous, New: description})
+		}
+	}
+	slices.SortFunc(res, func(a, b RuleDiff) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return res, nil
+}
+
+// isEqualDescription compares rule descriptions by their canonical JSON
+// encoding, ignoring formatting differences of embedded values.
+func isEqualDescription(a, b *RuleDescription) (bool, error) {
+	encodedA, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	encodedB, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(encodedA, encodedB), nil
+}
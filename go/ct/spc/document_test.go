@@ -0,0 +1,207 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package spc
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"pgregory.net/rand"
+
+	"github.com/0xsoniclabs/tosca/go/ct/gen"
+	"github.com/0xsoniclabs/tosca/go/ct/rlz"
+	"github.com/0xsoniclabs/tosca/go/ct/st"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+)
+
+func TestDocument_RuleNamesAreUnique(t *testing.T) {
+	names := map[string]bool{}
+	for _, rule := range Spec.GetRules() {
+		if names[rule.Name] {
+			t.Errorf("duplicate rule name %s", rule.Name)
+		}
+		names[rule.Name] = true
+	}
+}
+
+func TestDocument_SpecificationSurvivesRoundTrip(t *testing.T) {
+	rules := Spec.GetRules()
+	document, err := NewDocument(rules)
+	if err != nil {
+		t.Fatalf("failed to create document: %v", err)
+	}
+
+	var buffer bytes.Buffer
+	if err := document.Write(&buffer); err != nil {
+		t.Fatalf("failed to write document: %v", err)
+	}
+	restored, err := ReadDocument(&buffer)
+	if err != nil {
+		t.Fatalf("failed to read document: %v", err)
+	}
+	loaded, err := restored.GetRules(GetEffectRegistry(rules))
+	if err != nil {
+		t.Fatalf("failed to load rules: %v", err)
+	}
+
+	if len(rules) != len(loaded) {
+		t.Fatalf("unexpected number of rules, wanted %d, got %d", len(rules), len(loaded))
+	}
+	slices.SortFunc(rules, func(a, b rlz.Rule) int { return strings.Compare(a.Name, b.Name) })
+	for i, want := range rules {
+		got := loaded[i]
+		if want.Name != got.Name {
+			t.Fatalf("unexpected rule, wanted %s, got %s", want.Name, got.Name)
+		}
+		if want.Condition.String() != got.Condition.String() {
+			t.Errorf("unexpected condition of %s, wanted %v, got %v", want.Name, want.Condition, got.Condition)
+		}
+		if len(want.Parameter) != len(got.Parameter) {
+			t.Errorf("unexpected parameters of %s, wanted %v, got %v", want.Name, want.Parameter, got.Parameter)
+		}
+		for j := range min(len(want.Parameter), len(got.Parameter)) {
+			if want.Parameter[j] != got.Parameter[j] {
+				t.Errorf("unexpected parameters of %s, wanted %v, got %v", want.Name, want.Parameter, got.Parameter)
+			}
+		}
+	}
+}
+
+func TestDocument_LoadedSpecificationSelectsSameRules(t *testing.T) {
+	rules := Spec.GetRules()
+	document, err := NewDocument(rules)
+	if err != nil {
+		t.Fatalf("failed to create document: %v", err)
+	}
+	spec, err := document.Load(GetEffectRegistry(rules))
+	if err != nil {
+		t.Fatalf("failed to load specification: %v", err)
+	}
+
+	rnd := rand.New(0)
+	generator := gen.NewStateGenerator()
+	for range 10_000 {
+		state, err := generator.Generate(rnd)
+		if err != nil {
+			t.Fatalf("failed to generate state: %v", err)
+		}
+		want := ruleNames(Spec.GetRulesFor(state))
+		got := ruleNames(spec.GetRulesFor(state))
+		if !slices.Equal(want, got) {
+			t.Fatalf("unexpected rules for state %v, wanted %v, got %v", state, want, got)
+		}
+		state.Release()
+	}
+}
+
+func TestDocument_LoadFailsForUnknownEffects(t *testing.T) {
+	rules := []rlz.Rule{{
+		Name:      "custom",
+		Condition: rlz.Eq(rlz.Op(rlz.Pc()), vm.ADD),
+		Effect:    rlz.Change(func(*st.State) {}),
+	}}
+	document, err := NewDocument(rules)
+	if err != nil {
+		t.Fatalf("failed to create document: %v", err)
+	}
+	if _, err := document.Load(rlz.EffectRegistry{}); err == nil {
+		t.Errorf("expected loading with unresolved effect to fail")
+	}
+	if _, err := document.Load(GetEffectRegistry(rules)); err != nil {
+		t.Errorf("failed to load specification: %v", err)
+	}
+}
+
+func TestDocument_ReadRejectsUnknownFields(t *testing.T) {
+	if _, err := ReadDocument(strings.NewReader(`{"rules":[],"extra":1}`)); err == nil {
+		t.Errorf("expected parsing to fail")
+	}
+}
+
+func TestDocument_DiffListsChangedRules(t *testing.T) {
+	add := rlz.Rule{Name: "add", Condition: rlz.Eq(rlz.Op(rlz.Pc()), vm.ADD), Effect: rlz.NoEffect()}
+	sub := rlz.Rule{Name: "sub", Condition: rlz.Eq(rlz.Op(rlz.Pc()), vm.SUB), Effect: rlz.NoEffect()}
+	mul := rlz.Rule{Name: "mul", Condition: rlz.Eq(rlz.Op(rlz.Pc()), vm.MUL), Effect: rlz.NoEffect()}
+	modifiedSub := sub
+	modifiedSub.Effect = rlz.FailEffect()
+
+	old, err := NewDocument([]rlz.Rule{add, sub})
+	if err != nil {
+		t.Fatalf("failed to create document: %v", err)
+	}
+	new, err := NewDocument([]rlz.Rule{mul, modifiedSub})
+	if err != nil {
+		t.Fatalf("failed to create document: %v", err)
+	}
+
+	diffs, err := Diff(old, new)
+	if err != nil {
+		t.Fatalf("failed to compute diff: %v", err)
+	}
+	if len(diffs) != 3 {
+		t.Fatalf("unexpected number of differences, got %v", diffs)
+	}
+	if diffs[0].Name != "add" || diffs[0].Old == nil || diffs[0].New != nil {
+		t.Errorf("expected removal of add, got %v", diffs[0])
+	}
+	if diffs[1].Name != "mul" || diffs[1].Old != nil || diffs[1].New == nil {
+		t.Errorf("expected addition of mul, got %v", diffs[1])
+	}
+	if diffs[2].Name != "sub" || diffs[2].Old == nil || diffs[2].New == nil {
+		t.Errorf("expected modification of sub, got %v", diffs[2])
+	}
+
+	if diffs, err := Diff(old, old); err != nil || len(diffs) != 0 {
+		t.Errorf("unexpected differences of identical documents: %v, %v", diffs, err)
+	}
+}
+
+func TestDocument_CommonEffectsAreDescribedDeclaratively(t *testing.T) {
+	rules := map[string]rlz.Rule{}
+	for _, rule := range Spec.GetRules() {
+		rules[rule.Name] = rule
+	}
+	for _, name := range []string{
+		"pop_regular",
+		"push2_regular",
+		"dup3_regular",
+		"swap4_regular",
+		"jumpdest_regular",
+		"sstore_regular_London_StorageAdded_cold",
+	} {
+		rule, found := rules[name]
+		if !found {
+			t.Fatalf("rule %s not found", name)
+		}
+		node, err := rlz.EncodeEffect(rule.Effect, rule.Name)
+		if err != nil {
+			t.Fatalf("failed to encode effect of %s: %v", name, err)
+		}
+		if hasReference(node) {
+			t.Errorf("effect of %s should be fully declarative, got %v", name, node)
+		}
+	}
+}
+
+func hasReference(node rlz.Node) bool {
+	return node.Kind == "ref" || slices.ContainsFunc(node.Arguments, hasReference)
+}
+
+func ruleNames(rules []rlz.Rule) []string {
+	res := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		res = append(res, rule.Name)
+	}
+	slices.Sort(res)
+	return res
+}
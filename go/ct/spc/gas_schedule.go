@@ -0,0 +1,192 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package spc
+
+import (
+	. "github.com/0xsoniclabs/tosca/go/ct/common"
+	"github.com/0xsoniclabs/tosca/go/ct/gen"
+	. "github.com/0xsoniclabs/tosca/go/ct/rlz"
+	"github.com/0xsoniclabs/tosca/go/ct/st"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+)
+
+// NewSpecificationWithGasSchedule derives a specification for interpreters
+// charging the prices of the given gas schedule from a specification based on
+// the Ethereum gas prices.
+//
+// Interpreters charge the static gas of an instruction before anything else,
+// followed by its dynamic gas, and all later effects only depend on the
+// remaining gas. Thus, a state in which an instruction is priced differently
+// by the schedule behaves like the same state with its gas level shifted by
+// the price difference under the Ethereum prices. The derived rules evaluate
+// the rules of the given specification on such shifted states. Since the
+// Ethereum prices are charged on the shifted gas level, the resulting gas
+// level is the one of the schedule.
+//
+// The Ethereum prices of an instruction are obtained from the rule of the
+// given specification applying to the state, which charges the static gas
+// and the kinds of dynamic gas in its effect (see rlz.GetGasPrices).
+//
+// The conditions of the derived rules produce the same test cases as the
+// underlying rules, so gas boundaries are probed at the Ethereum prices.
+func NewSpecificationWithGasSchedule(spec Specification, schedule *tosca.GasSchedule) Specification {
+	if schedule == nil || (len(schedule.Static) == 0 && len(schedule.Dynamic) == 0) {
+		return spec
+	}
+	return &gasScheduleSpecification{spec: spec, schedule: schedule}
+}
+
+type gasScheduleSpecification struct {
+	spec     Specification
+	schedule *tosca.GasSchedule
+}
+
+func (s *gasScheduleSpecification) GetRules() []Rule {
+	rules := s.spec.GetRules()
+	for i := range rules {
+		rules[i] = s.adapt(rules[i])
+	}
+	return rules
+}
+
+func (s *gasScheduleSpecification) GetRulesFor(state *st.State) []Rule {
+	shifted := state.Clone()
+	defer shifted.Release()
+	shifted.Gas += s.getGasShift(state).condition
+
+	rules := s.spec.GetRulesFor(shifted)
+	for i := range rules {
+		rules[i] = s.adapt(rules[i])
+	}
+	return rules
+}
+
+func (s *gasScheduleSpecification) adapt(rule Rule) Rule {
+	return Rule{
+		Name:      rule.Name,
+		Condition: &gasShiftedCondition{rule.Condition, s},
+		Parameter: rule.Parameter,
+		Effect:    &gasShiftedEffect{rule.Effect, s},
+	}
+}
+
+// gasShift is the amount of gas to be added to a state to obtain a state
+// behaving equally under the Ethereum gas prices. Conditions and effects
+// are evaluated on the same shift, except for SSTORE (see getGasShift).
+type gasShift struct {
+	condition tosca.Gas
+	effect    tosca.Gas
+}
+
+// getGasShift computes the amount of gas to be added to the given state to
+// obtain a state behaving equally under the Ethereum gas prices.
+func (s *gasScheduleSpecification) getGasShift(state *st.State) gasShift {
+	if state.Status != st.Running || state.Revision > NewestSupportedRevision {
+		return gasShift{}
+	}
+	op, err := state.Code.GetOperation(int(state.Pc))
+	if err != nil {
+		return gasShift{}
+	}
+	staticPrice, hasStaticPrice := s.schedule.GetStaticGas(op, state.Revision)
+	if !hasStaticPrice && len(s.schedule.Dynamic) == 0 {
+		return gasShift{}
+	}
+
+	// The Ethereum prices are those charged by the rule applying to the state
+	// if sufficient gas is available.
+	probe := state.Clone()
+	defer probe.Release()
+	probe.Gas = st.MaxGasUsedByCt
+	rules := s.spec.GetRulesFor(probe)
+	if len(rules) == 0 {
+		return gasShift{}
+	}
+	static, dynamic := GetGasPrices(rules[0].Effect)
+
+	var staticShift, dynamicShift tosca.Gas
+	if hasStaticPrice {
+		staticShift = static - staticPrice
+	}
+	for _, kind := range dynamic {
+		dynamicShift += kind.EthereumPrice() - s.schedule.GetDynamicGas(kind, state.Revision)
+	}
+	shift := staticShift + dynamicShift
+	if op != vm.SSTORE || dynamicShift == 0 {
+		return gasShift{condition: shift, effect: shift}
+	}
+
+	// SSTORE fails if no more than 2300 gas is left after charging its static
+	// gas (EIP-2200), independently of its dynamic gas. Thus, the dynamic gas
+	// may only be shifted for conditions as long as it does not affect this
+	// check. If the shifted gas drops below the limit while the instruction
+	// succeeds in the interpreter, conditions are evaluated at the limit.
+	const sentry = 2300
+	remaining := state.Gas + staticShift
+	if remaining <= sentry {
+		return gasShift{condition: staticShift, effect: staticShift}
+	}
+	if remaining+dynamicShift > sentry {
+		return gasShift{condition: shift, effect: shift}
+	}
+	cost := probe.Gas
+	rules[0].Effect.Apply(probe)
+	cost -= probe.Gas
+	if remaining+dynamicShift < cost {
+		return gasShift{condition: shift, effect: shift}
+	}
+	return gasShift{condition: sentry + 1 - state.Gas, effect: shift}
+}
+
+// gasShiftedCondition evaluates a condition on the state with shifted gas.
+type gasShiftedCondition struct {
+	condition Condition
+	spec      *gasScheduleSpecification
+}
+
+func (c *gasShiftedCondition) Check(state *st.State) (bool, error) {
+	shift := c.spec.getGasShift(state).condition
+	if shift == 0 {
+		return c.condition.Check(state)
+	}
+	shifted := state.Clone()
+	defer shifted.Release()
+	shifted.Gas += shift
+	return c.condition.Check(shifted)
+}
+
+func (c *gasShiftedCondition) Restrict(generator *gen.StateGenerator) {
+	c.condition.Restrict(generator)
+}
+
+func (c *gasShiftedCondition) GetTestValues() []TestValue {
+	return c.condition.GetTestValues()
+}
+
+func (c *gasShiftedCondition) String() string {
+	return c.condition.String()
+}
+
+// gasShiftedEffect applies an effect on the state with shifted gas.
+type gasShiftedEffect struct {
+	effect Effect
+	spec   *gasScheduleSpecification
+}
+
+func (e *gasShiftedEffect) Apply(state *st.State) {
+	state.Gas += e.spec.getGasShift(state).effect
+	e.effect.Apply(state)
+}
+
+func (e *gasShiftedEffect) String() string {
+	return e.effect.String()
+}
@@ -0,0 +1,243 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package spc
+
+import (
+	"testing"
+
+	"github.com/0xsoniclabs/tosca/go/ct/common"
+	"github.com/0xsoniclabs/tosca/go/ct/st"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+)
+
+func TestGasSchedule_EmptyScheduleKeepsSpecification(t *testing.T) {
+	if got := NewSpecificationWithGasSchedule(Spec, nil); got != Spec {
+		t.Errorf("nil schedule should not alter the specification")
+	}
+	if got := NewSpecificationWithGasSchedule(Spec, &tosca.GasSchedule{}); got != Spec {
+		t.Errorf("empty schedule should not alter the specification")
+	}
+}
+
+func TestGasSchedule_StaticOverridesAreCharged(t *testing.T) {
+	schedule := &tosca.GasSchedule{
+		Static: []tosca.StaticGasOverride{
+			{OpCode: vm.ADD, Revision: tosca.R07_Istanbul, Price: 10},
+		},
+	}
+	spec := NewSpecificationWithGasSchedule(Spec, schedule)
+
+	tests := map[string]struct {
+		gas        tosca.Gas
+		wantStatus st.StatusCode
+		wantGas    tosca.Gas
+	}{
+		"sufficient gas":     {gas: 12, wantStatus: st.Running, wantGas: 2},
+		"exact gas":          {gas: 10, wantStatus: st.Running, wantGas: 0},
+		"insufficient gas":   {gas: 9, wantStatus: st.Failed},
+		"ethereum price gas": {gas: 3, wantStatus: st.Failed},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			state := st.NewState(st.NewCode([]byte{byte(vm.ADD)}))
+			defer state.Release()
+			state.Status = st.Running
+			state.Revision = tosca.R13_Cancun
+			state.Stack = st.NewStack(common.NewU256(1), common.NewU256(2))
+			state.Gas = test.gas
+
+			applyRule(t, spec, state)
+			checkStatusAndGas(t, state, test.wantStatus, test.wantGas)
+		})
+	}
+}
+
+func TestGasSchedule_StaticPricesAreDerivedFromRules(t *testing.T) {
+	tests := map[string]struct {
+		op       vm.OpCode
+		revision tosca.Revision
+		want     tosca.Gas
+	}{
+		"ADD":              {op: vm.ADD, revision: tosca.R13_Cancun, want: 3},
+		"BALANCE Istanbul": {op: vm.BALANCE, revision: tosca.R07_Istanbul, want: 700},
+		"BALANCE Berlin":   {op: vm.BALANCE, revision: tosca.R09_Berlin, want: 0},
+		"SLOAD Istanbul":   {op: vm.SLOAD, revision: tosca.R07_Istanbul, want: 800},
+		"SLOAD Berlin":     {op: vm.SLOAD, revision: tosca.R09_Berlin, want: 0},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Overriding the price by 1 gas shifts the gas level relative to
+			// the Ethereum price derived from the specification.
+			schedule := &tosca.GasSchedule{
+				Static: []tosca.StaticGasOverride{
+					{OpCode: test.op, Revision: tosca.R07_Istanbul, Price: 1},
+				},
+			}
+			spec := NewSpecificationWithGasSchedule(Spec, schedule).(*gasScheduleSpecification)
+
+			state := st.NewState(st.NewCode([]byte{byte(test.op)}))
+			defer state.Release()
+			state.Status = st.Running
+			state.Revision = test.revision
+			state.Stack = st.NewStack(common.NewU256(1), common.NewU256(2))
+			state.Gas = 10_000
+
+			shift := spec.getGasShift(state)
+			if want, got := test.want-1, shift.condition; want != got {
+				t.Errorf("unexpected shift of conditions, want %d, got %d", want, got)
+			}
+			if want, got := test.want-1, shift.effect; want != got {
+				t.Errorf("unexpected shift of effects, want %d, got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestGasSchedule_AccountAccessPricesAreCharged(t *testing.T) {
+	schedule := &tosca.GasSchedule{
+		Dynamic: []tosca.DynamicGasOverride{
+			{Kind: tosca.WarmAccountAccessGas, Revision: tosca.R09_Berlin, Price: 10},
+			{Kind: tosca.ColdAccountAccessGas, Revision: tosca.R09_Berlin, Price: 50},
+		},
+	}
+	spec := NewSpecificationWithGasSchedule(Spec, schedule)
+
+	tests := map[string]struct {
+		warm       bool
+		gas        tosca.Gas
+		wantStatus st.StatusCode
+		wantGas    tosca.Gas
+	}{
+		"cold with sufficient gas":   {gas: 60, wantStatus: st.Running, wantGas: 10},
+		"cold with insufficient gas": {gas: 49, wantStatus: st.Failed},
+		"warm with sufficient gas":   {warm: true, gas: 60, wantStatus: st.Running, wantGas: 50},
+		"warm with insufficient gas": {warm: true, gas: 9, wantStatus: st.Failed},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			state := st.NewState(st.NewCode([]byte{byte(vm.BALANCE)}))
+			defer state.Release()
+			state.Status = st.Running
+			state.Revision = tosca.R13_Cancun
+			state.Stack = st.NewStack(common.NewU256(42))
+			state.Gas = test.gas
+			if test.warm {
+				state.Accounts.MarkWarm(tosca.Address{19: 42})
+			}
+
+			applyRule(t, spec, state)
+			checkStatusAndGas(t, state, test.wantStatus, test.wantGas)
+			if test.wantStatus == st.Running && !state.Accounts.IsWarm(tosca.Address{19: 42}) {
+				t.Errorf("accessed account should be warm")
+			}
+		})
+	}
+}
+
+func TestGasSchedule_SstorePricesAreCharged(t *testing.T) {
+	tests := map[string]struct {
+		schedule   []tosca.DynamicGasOverride
+		warm       bool
+		value      uint64
+		gas        tosca.Gas
+		wantStatus st.StatusCode
+		wantGas    tosca.Gas
+	}{
+		"cold surcharge": {
+			schedule:   []tosca.DynamicGasOverride{{Kind: tosca.ColdSstoreSurchargeGas, Price: 100}},
+			value:      1, // < adds a value to the slot
+			gas:        20_105,
+			wantStatus: st.Running,
+			wantGas:    5,
+		},
+		"cold surcharge with insufficient gas": {
+			schedule:   []tosca.DynamicGasOverride{{Kind: tosca.ColdSstoreSurchargeGas, Price: 100}},
+			value:      1,
+			gas:        20_099,
+			wantStatus: st.Failed,
+		},
+		"cheaper warm slot": {
+			schedule:   []tosca.DynamicGasOverride{{Kind: tosca.WarmSstoreGas, Price: 10}},
+			warm:       true,
+			gas:        2_301,
+			wantStatus: st.Running,
+			wantGas:    2_291,
+		},
+		"cheaper warm slot without gas beyond EIP-2200 minimum": {
+			schedule:   []tosca.DynamicGasOverride{{Kind: tosca.WarmSstoreGas, Price: 10}},
+			warm:       true,
+			gas:        2_300,
+			wantStatus: st.Failed,
+		},
+		"more expensive warm slot": {
+			schedule:   []tosca.DynamicGasOverride{{Kind: tosca.WarmSstoreGas, Price: 1_000}},
+			warm:       true,
+			gas:        2_400,
+			wantStatus: st.Running,
+			wantGas:    1_400,
+		},
+		"more expensive warm slot with insufficient gas": {
+			schedule:   []tosca.DynamicGasOverride{{Kind: tosca.WarmSstoreGas, Price: 3_000}},
+			warm:       true,
+			gas:        2_999,
+			wantStatus: st.Failed,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			schedule := &tosca.GasSchedule{Dynamic: test.schedule}
+			for i := range schedule.Dynamic {
+				schedule.Dynamic[i].Revision = tosca.R09_Berlin
+			}
+			spec := NewSpecificationWithGasSchedule(Spec, schedule)
+
+			state := st.NewState(st.NewCode([]byte{byte(vm.SSTORE)}))
+			defer state.Release()
+			state.Status = st.Running
+			state.Revision = tosca.R13_Cancun
+			state.Stack = st.NewStack(common.NewU256(test.value), common.NewU256(1))
+			state.Storage = st.NewStorageBuilder().SetWarm(common.NewU256(1), test.warm).Build()
+			state.Gas = test.gas
+
+			applyRule(t, spec, state)
+			checkStatusAndGas(t, state, test.wantStatus, test.wantGas)
+		})
+	}
+}
+
+// applyRule applies the first rule of the given specification applying to
+// the given state.
+func applyRule(t *testing.T, spec Specification, state *st.State) {
+	t.Helper()
+	rules := spec.GetRulesFor(state)
+	if len(rules) == 0 {
+		t.Fatalf("expected a rule applying to the state")
+	}
+	if match, err := rules[0].Condition.Check(state); err != nil || !match {
+		t.Fatalf("rule %v does not match state: %v", rules[0].Name, err)
+	}
+	rules[0].Effect.Apply(state)
+}
+
+func checkStatusAndGas(t *testing.T, state *st.State, wantStatus st.StatusCode, wantGas tosca.Gas) {
+	t.Helper()
+	if want, got := wantStatus, state.Status; want != got {
+		t.Fatalf("unexpected status, want %v, got %v", want, got)
+	}
+	if want, got := wantGas, state.Gas; wantStatus == st.Running && want != got {
+		t.Errorf("unexpected gas, want %d, got %d", want, got)
+	}
+}
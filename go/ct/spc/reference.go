@@ -0,0 +1,336 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package spc
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"slices"
+	"strings"
+
+	. "github.com/0xsoniclabs/tosca/go/ct/common"
+	. "github.com/0xsoniclabs/tosca/go/ct/rlz"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+)
+
+// Reference is a human-readable description of the rules of a specification,
+// grouped by the operation they describe.
+type Reference struct {
+	Operations []OperationReference
+}
+
+// OperationReference describes the rules of a single operation. Rules not
+// bound to an operation are collected in an operation named "noOp".
+type OperationReference struct {
+	Name    string
+	Rules   []RuleReference
+	Changes []RevisionChange
+}
+
+// RuleReference is the human-readable description of a single rule.
+type RuleReference struct {
+	Name       string
+	Condition  string
+	Parameters []string
+	Effect     string
+	Revisions  []tosca.Revision
+}
+
+// RevisionChange lists the rules of an operation which start or stop to
+// apply with a known revision, compared to its preceding revision.
+type RevisionChange struct {
+	Revision tosca.Revision
+	Added    []string
+	Removed  []string
+}
+
+// NewReference produces the reference of the given rules. Operations are
+// sorted by their op-code, rules by their name.
+func NewReference(rules []Rule) Reference {
+	res := Reference{}
+	for name, group := range groupByOperation(rules) {
+		operation := OperationReference{Name: name}
+		for _, rule := range group {
+			operation.Rules = append(operation.Rules, newRuleReference(rule))
+		}
+		slices.SortFunc(operation.Rules, func(a, b RuleReference) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		operation.Changes = getRevisionChanges(operation.Rules)
+		res.Operations = append(res.Operations, operation)
+	}
+	order := getOperationOrder()
+	slices.SortFunc(res.Operations, func(a, b OperationReference) int {
+		if a, b := order(a.Name), order(b.Name); a != b {
+			return a - b
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return res
+}
+
+func newRuleReference(rule Rule) RuleReference {
+	res := RuleReference{
+		Name:      rule.Name,
+		Condition: rule.Condition.String(),
+		Revisions: GetRevisions(rule.Condition),
+	}
+	for _, parameter := range rule.Parameter {
+		description := fmt.Sprintf("%T", parameter)
+		if node, err := EncodeParameter(parameter); err == nil {
+			description = node.Kind
+		}
+		res.Parameters = append(res.Parameters, description)
+	}
+	res.Effect = DescribeEffect(rule.Effect, rule.Name)
+	return res
+}
+
+// getOperationOrder provides the sort key of operation names, listing rules
+// not bound to an operation first and unknown names last.
+func getOperationOrder() func(string) int {
+	codes := map[string]int{"noOp": -1}
+	for i := range 256 {
+		codes[vm.OpCode(i).String()] = i
+	}
+	return func(name string) int {
+		if code, found := codes[name]; found {
+			return code
+		}
+		return 256
+	}
+}
+
+func getKnownRevisions() []tosca.Revision {
+	res := []tosca.Revision{}
+	for revision := MinRevision; revision <= NewestSupportedRevision; revision = GetNextRevision(revision) {
+		res = append(res, revision)
+	}
+	return res
+}
+
+func getRevisionChanges(rules []RuleReference) []RevisionChange {
+	res := []RevisionChange{}
+	revisions := getKnownRevisions()
+	for i := 1; i < len(revisions); i++ {
+		change := RevisionChange{Revision: revisions[i]}
+		for _, rule := range rules {
+			before := slices.Contains(rule.Revisions, revisions[i-1])
+			after := slices.Contains(rule.Revisions, revisions[i])
+			if !before && after {
+				change.Added = append(change.Added, rule.Name)
+			}
+			if before && !after {
+				change.Removed = append(change.Removed, rule.Name)
+			}
+		}
+		if len(change.Added) > 0 || len(change.Removed) > 0 {
+			res = append(res, change)
+		}
+	}
+	return res
+}
+
+// getChangedOperations lists the names of operations with rules changing
+// with the given revision.
+func (r Reference) getChangedOperations(revision tosca.Revision) []string {
+	res := []string{}
+	for _, operation := range r.Operations {
+		if slices.ContainsFunc(operation.Changes, func(change RevisionChange) bool {
+			return change.Revision == revision
+		}) {
+			res = append(res, operation.Name)
+		}
+	}
+	return res
+}
+
+// WriteMarkdown renders this reference as a Markdown document.
+func (r Reference) WriteMarkdown(writer io.Writer) error {
+	out := bufio.NewWriter(writer)
+	operationLink := func(name string) string {
+		return fmt.Sprintf("[%s](#%s)", name, operationAnchor(name))
+	}
+	ruleLink := func(name string) string {
+		return fmt.Sprintf("[`%s`](#rule-%s)", name, name)
+	}
+
+	fmt.Fprintf(out, "# Specification Reference\n\n")
+	fmt.Fprintf(out, "## Operations\n\n")
+	for _, operation := range r.Operations {
+		fmt.Fprintf(out, "- %s (%d rules)\n", operationLink(operation.Name), len(operation.Rules))
+	}
+	fmt.Fprintf(out, "\n## Revisions\n\n")
+	for _, revision := range getKnownRevisions()[1:] {
+		changed := r.getChangedOperations(revision)
+		links := make([]string, 0, len(changed))
+		for _, name := range changed {
+			links = append(links, operationLink(name))
+		}
+		if len(links) == 0 {
+			links = append(links, "no changes")
+		}
+		fmt.Fprintf(out, "- %s: %s\n", revisionName(revision), strings.Join(links, ", "))
+	}
+
+	for _, operation := range r.Operations {
+		fmt.Fprintf(out, "\n<a id=\"%s\"></a>\n\n## %s\n", operationAnchor(operation.Name), operation.Name)
+		if len(operation.Changes) > 0 {
+			fmt.Fprintf(out, "\n### Changes\n\n")
+			for _, change := range operation.Changes {
+				fmt.Fprintf(out, "- %s:", revisionName(change.Revision))
+				separator := ""
+				for _, names := range []struct {
+					label string
+					rules []string
+				}{{"added", change.Added}, {"removed", change.Removed}} {
+					if len(names.rules) == 0 {
+						continue
+					}
+					links := make([]string, 0, len(names.rules))
+					for _, name := range names.rules {
+						links = append(links, ruleLink(name))
+					}
+					fmt.Fprintf(out, "%s %s %s", separator, names.label, strings.Join(links, ", "))
+					separator = ";"
+				}
+				fmt.Fprintf(out, "\n")
+			}
+		}
+		fmt.Fprintf(out, "\n### Rules\n")
+		for _, rule := range operation.Rules {
+			fmt.Fprintf(out, "\n<a id=\"rule-%s\"></a>\n\n#### %s\n\n", rule.Name, rule.Name)
+			fmt.Fprintf(out, "- Revisions: %s\n", formatRevisions(rule.Revisions))
+			fmt.Fprintf(out, "- Condition: `%s`\n", rule.Condition)
+			if len(rule.Parameters) > 0 {
+				fmt.Fprintf(out, "- Parameters: %s\n", strings.Join(rule.Parameters, ", "))
+			}
+			fmt.Fprintf(out, "- Effect: %s\n", rule.Effect)
+		}
+	}
+	return out.Flush()
+}
+
+// WriteHTML renders this reference as a self-contained HTML document.
+func (r Reference) WriteHTML(writer io.Writer) error {
+	out := bufio.NewWriter(writer)
+	escape := html.EscapeString
+	operationLink := func(name string) string {
+		return fmt.Sprintf(`<a href="#%s">%s</a>`, operationAnchor(name), escape(name))
+	}
+	ruleLink := func(name string) string {
+		return fmt.Sprintf(`<a href="#rule-%s"><code>%s</code></a>`, escape(name), escape(name))
+	}
+
+	fmt.Fprintf(out, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(out, "<title>Specification Reference</title>\n")
+	fmt.Fprintf(out, "<style>body{font-family:sans-serif;max-width:80em;margin:auto}code{word-break:break-word}</style>\n")
+	fmt.Fprintf(out, "</head>\n<body>\n<h1>Specification Reference</h1>\n")
+	fmt.Fprintf(out, "<h2>Operations</h2>\n<ul>\n")
+	for _, operation := range r.Operations {
+		fmt.Fprintf(out, "<li>%s (%d rules)</li>\n", operationLink(operation.Name), len(operation.Rules))
+	}
+	fmt.Fprintf(out, "</ul>\n<h2>Revisions</h2>\n<ul>\n")
+	for _, revision := range getKnownRevisions()[1:] {
+		changed := r.getChangedOperations(revision)
+		links := make([]string, 0, len(changed))
+		for _, name := range changed {
+			links = append(links, operationLink(name))
+		}
+		if len(links) == 0 {
+			links = append(links, "no changes")
+		}
+		fmt.Fprintf(out, "<li>%s: %s</li>\n", escape(revisionName(revision)), strings.Join(links, ", "))
+	}
+	fmt.Fprintf(out, "</ul>\n")
+
+	for _, operation := range r.Operations {
+		fmt.Fprintf(out, "<h2 id=\"%s\">%s</h2>\n", operationAnchor(operation.Name), escape(operation.Name))
+		if len(operation.Changes) > 0 {
+			fmt.Fprintf(out, "<h3>Changes</h3>\n<ul>\n")
+			for _, change := range operation.Changes {
+				fmt.Fprintf(out, "<li>%s:", escape(revisionName(change.Revision)))
+				separator := ""
+				for _, names := range []struct {
+					label string
+					rules []string
+				}{{"added", change.Added}, {"removed", change.Removed}} {
+					if len(names.rules) == 0 {
+						continue
+					}
+					links := make([]string, 0, len(names.rules))
+					for _, name := range names.rules {
+						links = append(links, ruleLink(name))
+					}
+					fmt.Fprintf(out, "%s %s %s", separator, names.label, strings.Join(links, ", "))
+					separator = ";"
+				}
+				fmt.Fprintf(out, "</li>\n")
+			}
+			fmt.Fprintf(out, "</ul>\n")
+		}
+		fmt.Fprintf(out, "<h3>Rules</h3>\n")
+		for _, rule := range operation.Rules {
+			fmt.Fprintf(out, "<h4 id=\"rule-%s\">%s</h4>\n<ul>\n", escape(rule.Name), escape(rule.Name))
+			fmt.Fprintf(out, "<li>Revisions: %s</li>\n", escape(formatRevisions(rule.Revisions)))
+			fmt.Fprintf(out, "<li>Condition: <code>%s</code></li>\n", escape(rule.Condition))
+			if len(rule.Parameters) > 0 {
+				fmt.Fprintf(out, "<li>Parameters: %s</li>\n", escape(strings.Join(rule.Parameters, ", ")))
+			}
+			fmt.Fprintf(out, "<li>Effect: %s</li>\n</ul>\n", escape(rule.Effect))
+		}
+	}
+	fmt.Fprintf(out, "</body>\n</html>\n")
+	return out.Flush()
+}
+
+// operationAnchor provides the link target of the given operation, which is
+// free of characters requiring escaping in Markdown and HTML.
+func operationAnchor(name string) string {
+	return "op-" + strings.Map(func(r rune) rune {
+		if 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' {
+			return r
+		}
+		return '-'
+	}, name)
+}
+
+func revisionName(revision tosca.Revision) string {
+	if revision == R99_UnknownNextRevision {
+		return "unknown next revision"
+	}
+	return revision.String()
+}
+
+// formatRevisions summarizes the given sorted revisions as ranges of
+// consecutive revisions.
+func formatRevisions(revisions []tosca.Revision) string {
+	if len(revisions) == 0 {
+		return "none"
+	}
+	ranges := []string{}
+	for start := 0; start < len(revisions); {
+		end := start
+		for end+1 < len(revisions) && revisions[end+1] == revisions[end]+1 {
+			end++
+		}
+		if start == end {
+			ranges = append(ranges, revisionName(revisions[start]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%s - %s", revisionName(revisions[start]), revisionName(revisions[end])))
+		}
+		start = end + 1
+	}
+	return strings.Join(ranges, ", ")
+}
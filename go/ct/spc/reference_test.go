@@ -0,0 +1,154 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package spc
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/0xsoniclabs/tosca/go/ct/common"
+	"github.com/0xsoniclabs/tosca/go/ct/rlz"
+	"github.com/0xsoniclabs/tosca/go/ct/st"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+)
+
+func TestReference_RulesAreGroupedByOperation(t *testing.T) {
+	rules := []rlz.Rule{
+		{Name: "sub", Condition: rlz.Eq(rlz.Op(rlz.Pc()), vm.SUB), Effect: rlz.NoEffect()},
+		{Name: "stopped", Condition: rlz.Eq(rlz.Status(), st.Stopped), Effect: rlz.NoEffect()},
+		{Name: "add_b", Condition: rlz.Eq(rlz.Op(rlz.Pc()), vm.ADD), Effect: rlz.NoEffect()},
+		{Name: "add_a", Condition: rlz.Eq(rlz.Op(rlz.Pc()), vm.ADD), Effect: rlz.FailEffect()},
+	}
+	reference := NewReference(rules)
+
+	names := []string{}
+	for _, operation := range reference.Operations {
+		names = append(names, operation.Name)
+	}
+	if want := []string{"noOp", "ADD", "SUB"}; !slices.Equal(want, names) {
+		t.Fatalf("unexpected operations, wanted %v, got %v", want, names)
+	}
+	add := reference.Operations[1]
+	if len(add.Rules) != 2 || add.Rules[0].Name != "add_a" || add.Rules[1].Name != "add_b" {
+		t.Fatalf("unexpected rules of ADD: %v", add.Rules)
+	}
+	if add.Rules[0].Effect == add.Rules[1].Effect {
+		t.Errorf("failing and empty effects should be described differently")
+	}
+}
+
+func TestReference_RevisionChangesAreListed(t *testing.T) {
+	rules := []rlz.Rule{
+		{
+			Name:      "old",
+			Condition: rlz.And(rlz.RevisionBounds(MinRevision, tosca.R12_Shanghai), rlz.Eq(rlz.Op(rlz.Pc()), vm.PUSH0)),
+			Effect:    rlz.FailEffect(),
+		},
+		{
+			Name:      "new",
+			Condition: rlz.And(rlz.RevisionBounds(tosca.R13_Cancun, NewestSupportedRevision), rlz.Eq(rlz.Op(rlz.Pc()), vm.PUSH0)),
+			Effect:    rlz.NoEffect(),
+		},
+	}
+	reference := NewReference(rules)
+	if len(reference.Operations) != 1 {
+		t.Fatalf("unexpected operations: %v", reference.Operations)
+	}
+
+	want := []RevisionChange{
+		{Revision: tosca.R13_Cancun, Added: []string{"new"}, Removed: []string{"old"}},
+	}
+	got := reference.Operations[0].Changes
+	if len(want) != len(got) {
+		t.Fatalf("unexpected changes, wanted %v, got %v", want, got)
+	}
+	for i := range want {
+		if want[i].Revision != got[i].Revision ||
+			!slices.Equal(want[i].Added, got[i].Added) ||
+			!slices.Equal(want[i].Removed, got[i].Removed) {
+			t.Errorf("unexpected change, wanted %v, got %v", want[i], got[i])
+		}
+	}
+}
+
+func TestReference_AllRulesAreRendered(t *testing.T) {
+	rules := Spec.GetRules()
+	reference := NewReference(rules)
+
+	var markdown, html bytes.Buffer
+	if err := reference.WriteMarkdown(&markdown); err != nil {
+		t.Fatalf("failed to render Markdown: %v", err)
+	}
+	if err := reference.WriteHTML(&html); err != nil {
+		t.Fatalf("failed to render HTML: %v", err)
+	}
+	for _, rule := range rules {
+		if !strings.Contains(markdown.String(), "#### "+rule.Name+"\n") {
+			t.Errorf("rule %s is missing in Markdown reference", rule.Name)
+		}
+		if !strings.Contains(html.String(), `<h4 id="rule-`+rule.Name+`">`) {
+			t.Errorf("rule %s is missing in HTML reference", rule.Name)
+		}
+	}
+}
+
+func TestReference_EffectsAreDescribed(t *testing.T) {
+	rules := []rlz.Rule{{
+		Name:      "add_regular",
+		Condition: rlz.Eq(rlz.Op(rlz.Pc()), vm.ADD),
+		Effect: rlz.Sequence(
+			rlz.ConsumeStaticGas(3),
+			rlz.IncrementPc(1),
+			rlz.Change(func(s *st.State) {}),
+		),
+	}}
+	var markdown bytes.Buffer
+	if err := NewReference(rules).WriteMarkdown(&markdown); err != nil {
+		t.Fatalf("failed to render Markdown: %v", err)
+	}
+	want := "- Effect: consume 3 static gas; pc += 1; custom change add_regular\n"
+	if !strings.Contains(markdown.String(), want) {
+		t.Errorf("effect is not described, wanted %q in:\n%s", want, markdown.String())
+	}
+}
+
+func TestReference_HTMLIsEscaped(t *testing.T) {
+	rules := []rlz.Rule{{
+		Name:      "lt",
+		Condition: rlz.Lt(rlz.Gas(), 5),
+		Effect:    rlz.NoEffect(),
+	}}
+	var html bytes.Buffer
+	if err := NewReference(rules).WriteHTML(&html); err != nil {
+		t.Fatalf("failed to render HTML: %v", err)
+	}
+	if !strings.Contains(html.String(), "<code>Gas &lt; 5</code>") {
+		t.Errorf("condition is not escaped:\n%s", html.String())
+	}
+}
+
+func TestReference_FormatRevisions(t *testing.T) {
+	tests := map[string][]tosca.Revision{
+		"none":                         nil,
+		"London":                       {tosca.R10_London},
+		"Istanbul - London":            {tosca.R07_Istanbul, tosca.R09_Berlin, tosca.R10_London},
+		"Berlin, Shanghai - Cancun":    {tosca.R09_Berlin, tosca.R12_Shanghai, tosca.R13_Cancun},
+		"Osaka, unknown next revision": {tosca.R15_Osaka, R99_UnknownNextRevision},
+	}
+	for want, revisions := range tests {
+		if got := formatRevisions(revisions); want != got {
+			t.Errorf("unexpected format of %v, wanted %q, got %q", revisions, want, got)
+		}
+	}
+}
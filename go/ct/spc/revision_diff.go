@@ -0,0 +1,283 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package spc
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"pgregory.net/rand"
+
+	"github.com/0xsoniclabs/tosca/go/ct/gen"
+	. "github.com/0xsoniclabs/tosca/go/ct/rlz"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+)
+
+// RevisionDiff lists the operations whose semantics differ between two
+// revisions, sorted by their op-code.
+type RevisionDiff struct {
+	Old        tosca.Revision
+	New        tosca.Revision
+	Operations []OperationDiff
+}
+
+// OperationDiff describes how the rules of an operation differ between two
+// revisions. Rules are matched by the structure of their conditions,
+// disregarding revision bounds: rules with equal conditions are matched
+// first, remaining rules with conditions only differing in compared
+// constants, like gas thresholds, second. Matched rules are listed as
+// modified if their conditions or their effects differ; all others as added
+// or removed.
+type OperationDiff struct {
+	Name       string
+	Added      []string
+	Removed    []string
+	Modified   []RuleChange
+	StaticGas  *StaticGasChange  // < nil if the static gas price is unchanged
+	DynamicGas *DynamicGasChange // < nil if the charged dynamic gas is unchanged
+}
+
+// RuleChange describes a rule modified between two revisions.
+type RuleChange struct {
+	Old       string
+	New       string
+	Condition bool // < true if the conditions differ
+	Effect    bool // < true if the effects differ on sampled states
+}
+
+// StaticGasChange describes a modified static gas price of an operation.
+type StaticGasChange struct {
+	Old tosca.Gas
+	New tosca.Gas
+}
+
+// DynamicGasChange describes modified kinds of dynamic gas charged by the
+// rules of an operation.
+type DynamicGasChange struct {
+	Old []tosca.DynamicGasKind
+	New []tosca.DynamicGasKind
+}
+
+// DiffRevisions compares the given rules applying to the old revision to
+// those applying to the new revision. The effects of matched rules are
+// compared on up to the given number of sampled states.
+func DiffRevisions(
+	rules []Rule,
+	old, new tosca.Revision,
+	rnd *rand.Rand,
+	samples int,
+) (RevisionDiff, error) {
+	res := RevisionDiff{Old: old, New: new}
+	groups := groupByOperation(rules)
+	for _, name := range slices.Sorted(maps.Keys(groups)) {
+		diff := OperationDiff{Name: name}
+
+		before := []Rule{}
+		after := []Rule{}
+		for _, rule := range groups[name] {
+			revisions := GetRevisions(rule.Condition)
+			if slices.Contains(revisions, old) {
+				before = append(before, rule)
+			}
+			if slices.Contains(revisions, new) {
+				after = append(after, rule)
+			}
+		}
+
+		pairs, removed, added := matchRules(before, after)
+		for _, pair := range pairs {
+			change, err := compareRules(pair[0], pair[1], old, new, rnd, samples)
+			if err != nil {
+				return RevisionDiff{}, err
+			}
+			if change.Condition || change.Effect {
+				diff.Modified = append(diff.Modified, change)
+			}
+		}
+		for _, rule := range removed {
+			diff.Removed = append(diff.Removed, rule.Name)
+		}
+		for _, rule := range added {
+			diff.Added = append(diff.Added, rule.Name)
+		}
+
+		staticBefore, dynamicBefore := getGas(groups[name], old)
+		staticAfter, dynamicAfter := getGas(groups[name], new)
+		if staticBefore != staticAfter {
+			diff.StaticGas = &StaticGasChange{Old: staticBefore, New: staticAfter}
+		}
+		if !slices.Equal(dynamicBefore, dynamicAfter) {
+			diff.DynamicGas = &DynamicGasChange{Old: dynamicBefore, New: dynamicAfter}
+		}
+
+		if len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.Modified) == 0 &&
+			diff.StaticGas == nil && diff.DynamicGas == nil {
+			continue
+		}
+		slices.Sort(diff.Added)
+		slices.Sort(diff.Removed)
+		slices.SortFunc(diff.Modified, func(a, b RuleChange) int {
+			return strings.Compare(a.New, b.New)
+		})
+		res.Operations = append(res.Operations, diff)
+	}
+
+	order := getOperationOrder()
+	slices.SortFunc(res.Operations, func(a, b OperationDiff) int {
+		if a, b := order(a.Name), order(b.Name); a != b {
+			return a - b
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return res, nil
+}
+
+// compareRules compares a rule applying to the old revision with its
+// counterpart applying to the new revision. Effects are compared by applying
+// them to sampled states of the new revision and the same states moved to the
+// old revision.
+func compareRules(
+	before, after Rule,
+	old, new tosca.Revision,
+	rnd *rand.Rand,
+	samples int,
+) (RuleChange, error) {
+	res := RuleChange{Old: before.Name, New: after.Name}
+	if getConditionStructure(before.Condition, true) != getConditionStructure(after.Condition, true) {
+		res.Condition = true
+		return res, nil
+	}
+
+	condition := And(IsRevision(new), after.Condition)
+	for range samples {
+		state, err := FindSatisfyingState(condition, rnd, samples)
+		if errors.Is(err, gen.ErrUnsatisfiable) {
+			return res, nil
+		}
+		if err != nil {
+			return RuleChange{}, fmt.Errorf("failed to sample state for %s: %w", after.Name, err)
+		}
+		if state == nil {
+			continue
+		}
+		moved := state.Clone()
+		moved.Revision = old
+		if applies, err := before.Condition.Check(moved); err == nil && applies {
+			after.Effect.Apply(state)
+			before.Effect.Apply(moved)
+			moved.Revision = new
+			res.Effect = !state.Eq(moved)
+		}
+		state.Release()
+		moved.Release()
+		if res.Effect {
+			break
+		}
+	}
+	return res, nil
+}
+
+// matchRules pairs the rules applying to an old revision with the rules
+// applying to a new revision by the structure of their conditions. Rules
+// with equal conditions are paired first, starting with rules of the same
+// name; remaining rules are paired if their conditions only differ in
+// compared constants. Rules without a counterpart are returned as removed or
+// added.
+func matchRules(before, after []Rule) (pairs [][2]Rule, removed, added []Rule) {
+	byName := func(a, b Rule) int { return strings.Compare(a.Name, b.Name) }
+	removed = slices.SortedFunc(slices.Values(before), byName)
+	added = slices.SortedFunc(slices.Values(after), byName)
+	passes := []struct {
+		constants bool
+		sameName  bool
+	}{{true, true}, {true, false}, {false, false}}
+	for _, pass := range passes {
+		getKey := func(rule Rule) string {
+			key := getConditionStructure(rule.Condition, pass.constants)
+			if pass.sameName {
+				key = rule.Name + ":" + key
+			}
+			return key
+		}
+		candidates := map[string][]Rule{}
+		for _, rule := range added {
+			key := getKey(rule)
+			candidates[key] = append(candidates[key], rule)
+		}
+		unmatched := []Rule{}
+		matched := map[string]bool{}
+		for _, rule := range removed {
+			key := getKey(rule)
+			if len(candidates[key]) == 0 {
+				unmatched = append(unmatched, rule)
+				continue
+			}
+			pairs = append(pairs, [2]Rule{rule, candidates[key][0]})
+			matched[candidates[key][0].Name] = true
+			candidates[key] = candidates[key][1:]
+		}
+		removed = unmatched
+		added = slices.DeleteFunc(added, func(rule Rule) bool {
+			return matched[rule.Name]
+		})
+	}
+	return pairs, removed, added
+}
+
+// getConditionStructure describes the given condition disregarding revision
+// bounds and the order of conjunctions and disjunctions. Compared constants
+// are only included if requested.
+func getConditionStructure(condition Condition, constants bool) string {
+	condition = WithoutRevisionBounds(condition)
+	node, err := EncodeCondition(condition)
+	if err != nil {
+		return condition.String()
+	}
+	var describe func(Node) string
+	describe = func(node Node) string {
+		args := make([]string, 0, len(node.Arguments))
+		for _, arg := range node.Arguments {
+			args = append(args, describe(arg))
+		}
+		if node.Kind == "and" || node.Kind == "or" {
+			slices.Sort(args)
+		}
+		value := ""
+		if constants {
+			value = string(node.Value)
+		}
+		return fmt.Sprintf("%s[%s](%s)", node.Kind, value, strings.Join(args, ","))
+	}
+	return describe(node)
+}
+
+// getGas returns the static gas and the sorted kinds of dynamic gas charged
+// by the given rules of an operation in the given revision.
+func getGas(rules []Rule, revision tosca.Revision) (tosca.Gas, []tosca.DynamicGasKind) {
+	static := tosca.Gas(0)
+	dynamic := []tosca.DynamicGasKind{}
+	for _, rule := range rules {
+		if slices.Contains(GetRevisions(rule.Condition), revision) {
+			ruleStatic, ruleDynamic := GetGasPrices(rule.Effect)
+			static = max(static, ruleStatic)
+			for _, kind := range ruleDynamic {
+				if !slices.Contains(dynamic, kind) {
+					dynamic = append(dynamic, kind)
+				}
+			}
+		}
+	}
+	slices.Sort(dynamic)
+	return static, dynamic
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package spc
+
+import (
+	"slices"
+	"testing"
+
+	"pgregory.net/rand"
+
+	"github.com/0xsoniclabs/tosca/go/ct/rlz"
+	"github.com/0xsoniclabs/tosca/go/ct/st"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+)
+
+func TestRevisionDiff_RulesAreMatchedByTheStructureOfTheirConditions(t *testing.T) {
+	isAdd := rlz.Eq(rlz.Op(rlz.Pc()), vm.ADD)
+	before := []rlz.Rule{
+		{Name: "same", Condition: rlz.And(isAdd, rlz.Lt(rlz.Gas(), 3))},
+		{Name: "renamed_old", Condition: rlz.And(rlz.IsRevision(tosca.R07_Istanbul), rlz.Ge(rlz.Gas(), 3), isAdd)},
+		{Name: "threshold_old", Condition: rlz.And(isAdd, rlz.Lt(rlz.StackSize(), 2))},
+		{Name: "dropped", Condition: rlz.And(isAdd, rlz.Eq(rlz.Status(), st.Running))},
+	}
+	after := []rlz.Rule{
+		{Name: "same", Condition: rlz.And(isAdd, rlz.Lt(rlz.Gas(), 3))},
+		{Name: "renamed_new", Condition: rlz.And(isAdd, rlz.Ge(rlz.Gas(), 3), rlz.IsRevision(tosca.R09_Berlin))},
+		{Name: "threshold_new", Condition: rlz.And(isAdd, rlz.Lt(rlz.StackSize(), 5))},
+		{Name: "dropped", Condition: rlz.And(isAdd, rlz.IsStorageWarm(rlz.Param(0)))},
+	}
+
+	pairs, removed, added := matchRules(before, after)
+	got := []string{}
+	for _, pair := range pairs {
+		got = append(got, pair[0].Name+"->"+pair[1].Name)
+	}
+	slices.Sort(got)
+	want := []string{"renamed_old->renamed_new", "same->same", "threshold_old->threshold_new"}
+	if !slices.Equal(want, got) {
+		t.Errorf("unexpected pairs, wanted %v, got %v", want, got)
+	}
+	if len(removed) != 1 || removed[0].Name != "dropped" {
+		t.Errorf("unexpected removed rules: %v", removed)
+	}
+	if len(added) != 1 || added[0].Name != "dropped" {
+		t.Errorf("unexpected added rules: %v", added)
+	}
+}
+
+func TestRevisionDiff_ReportsAddedRemovedAndModifiedRules(t *testing.T) {
+	isBalance := rlz.Eq(rlz.Op(rlz.Pc()), vm.BALANCE)
+	rules := []rlz.Rule{
+		{
+			Name:      "balance_unchanged",
+			Condition: isBalance,
+			Effect:    rlz.NoEffect(),
+		},
+		{
+			Name:      "balance_old_only_preBerlin",
+			Condition: rlz.And(rlz.IsRevision(tosca.R07_Istanbul), isBalance),
+			Effect:    rlz.NoEffect(),
+		},
+		{
+			Name:      "balance_new_only",
+			Condition: rlz.And(rlz.RevisionBounds(tosca.R09_Berlin, tosca.R10_London), isBalance, rlz.Lt(rlz.Gas(), 5)),
+			Effect:    rlz.NoEffect(),
+		},
+		{
+			Name:      "balance_condition_istanbul",
+			Condition: rlz.And(rlz.IsRevision(tosca.R07_Istanbul), isBalance, rlz.Lt(rlz.Gas(), 700)),
+			Effect:    rlz.FailEffect(),
+		},
+		{
+			Name:      "balance_condition_berlin",
+			Condition: rlz.And(rlz.IsRevision(tosca.R09_Berlin), isBalance, rlz.Lt(rlz.Gas(), 100)),
+			Effect:    rlz.FailEffect(),
+		},
+		{
+			Name:      "balance_effect_istanbul",
+			Condition: rlz.And(rlz.IsRevision(tosca.R07_Istanbul), isBalance, rlz.Ge(rlz.Gas(), 700)),
+			Effect:    rlz.ConsumeStaticGas(700),
+		},
+		{
+			Name:      "balance_effect_berlin",
+			Condition: rlz.And(rlz.IsRevision(tosca.R09_Berlin), isBalance, rlz.Ge(rlz.Gas(), 700)),
+			Effect:    rlz.ConsumeDynamicGas(tosca.ColdAccountAccessGas),
+		},
+		{
+			Name:      "add_unchanged",
+			Condition: rlz.Eq(rlz.Op(rlz.Pc()), vm.ADD),
+			Effect:    rlz.Change(func(s *st.State) { s.Pc++ }),
+		},
+	}
+
+	diff, err := DiffRevisions(rules, tosca.R07_Istanbul, tosca.R09_Berlin, rand.New(0), 10)
+	if err != nil {
+		t.Fatalf("failed to compare revisions: %v", err)
+	}
+	if len(diff.Operations) != 1 || diff.Operations[0].Name != "BALANCE" {
+		t.Fatalf("unexpected operations in diff: %v", diff.Operations)
+	}
+	balance := diff.Operations[0]
+
+	if want := []string{"balance_new_only"}; !slices.Equal(want, balance.Added) {
+		t.Errorf("unexpected added rules, wanted %v, got %v", want, balance.Added)
+	}
+	if want := []string{"balance_old_only_preBerlin"}; !slices.Equal(want, balance.Removed) {
+		t.Errorf("unexpected removed rules, wanted %v, got %v", want, balance.Removed)
+	}
+	want := []RuleChange{
+		{Old: "balance_condition_istanbul", New: "balance_condition_berlin", Condition: true},
+		{Old: "balance_effect_istanbul", New: "balance_effect_berlin", Effect: true},
+	}
+	if !slices.Equal(want, balance.Modified) {
+		t.Errorf("unexpected modified rules, wanted %v, got %v", want, balance.Modified)
+	}
+	if want := (StaticGasChange{Old: 700, New: 0}); balance.StaticGas == nil || *balance.StaticGas != want {
+		t.Errorf("unexpected static gas change, wanted %v, got %v", want, balance.StaticGas)
+	}
+	if balance.DynamicGas == nil ||
+		len(balance.DynamicGas.Old) != 0 ||
+		!slices.Equal([]tosca.DynamicGasKind{tosca.ColdAccountAccessGas}, balance.DynamicGas.New) {
+		t.Errorf("unexpected dynamic gas change: %v", balance.DynamicGas)
+	}
+}
+
+func TestRevisionDiff_SpecificationListsCancunOperations(t *testing.T) {
+	diff, err := DiffRevisions(Spec.GetRules(), tosca.R12_Shanghai, tosca.R13_Cancun, rand.New(0), 10)
+	if err != nil {
+		t.Fatalf("failed to compare revisions: %v", err)
+	}
+	operations := []string{}
+	for _, operation := range diff.Operations {
+		operations = append(operations, operation.Name)
+	}
+	for _, op := range []vm.OpCode{vm.TLOAD, vm.TSTORE, vm.MCOPY, vm.BLOBHASH, vm.BLOBBASEFEE} {
+		if !slices.Contains(operations, op.String()) {
+			t.Errorf("operation %v introduced by Cancun is missing in %v", op, operations)
+		}
+	}
+	if slices.Contains(operations, vm.ADD.String()) {
+		t.Errorf("ADD is not modified by Cancun")
+	}
+}
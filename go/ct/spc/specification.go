@@ -21,6 +21,7 @@ import (
 	. "github.com/0xsoniclabs/tosca/go/ct/rlz"
 	"github.com/0xsoniclabs/tosca/go/ct/st"
 	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/eof"
 	"github.com/0xsoniclabs/tosca/go/tosca/vm"
 	"golang.org/x/exp/constraints"
 )
@@ -44,12 +45,34 @@ var Spec = func() Specification {
 type instruction struct {
 	op         vm.OpCode
 	staticGas  tosca.Gas
+	dynamicGas []tosca.DynamicGasKind // dynamic gas charged before the effects
+	minGas     tosca.Gas              // minimum of gas required, e.g. by EIP-2200
 	pops       int
 	pushes     int
 	conditions []Condition       // conditions for the regular case
 	parameters []Parameter       // parameters for the regular case
-	effect     func(s *st.State) // effect for the regular case
+	effects    []Effect          // declarative effects for the regular case
+	effect     func(s *st.State) // custom effect for the regular case, optional
 	name       string
+	eof        bool // < the instruction is only defined for EOF code
+}
+
+// requiredGas is the minimum amount of gas needed to execute the instruction.
+func (i instruction) requiredGas() tosca.Gas {
+	gas := i.staticGas
+	for _, kind := range i.dynamicGas {
+		gas += kind.EthereumPrice()
+	}
+	return max(gas, i.minGas)
+}
+
+// revisionConditions restricts the rules of the instruction to the revisions
+// and kinds of code the instruction is defined for.
+func (i instruction) revisionConditions() []Condition {
+	if i.eof {
+		return []Condition{IsRevision(tosca.R98_Experimental), IsEofCode()}
+	}
+	return []Condition{AnyKnownRevision()}
 }
 
 ////////////////////////////////////////////////////////////
@@ -97,19 +120,37 @@ func getAllRules() []Rule {
 	for i := range 256 {
 		op := vm.OpCode(i)
 		if !vm.IsValid(op) {
+			conditions := []Condition{
+				Eq(Status(), st.Running),
+				Eq(Op(Pc()), op),
+				IsCode(Pc()),
+				AnyKnownRevision(),
+			}
+			// Instructions of EOF code are only invalid in legacy code.
+			if vm.IsValidInEof(op) {
+				conditions = append(conditions, IsLegacyCode())
+			}
 			rules = append(rules, Rule{
-				Name: fmt.Sprintf("%v_invalid", op),
-				Condition: And(
-					Eq(Status(), st.Running),
-					Eq(Op(Pc()), op),
-					IsCode(Pc()),
-					AnyKnownRevision(),
-				),
-				Effect: FailEffect(),
+				Name:      fmt.Sprintf("%v_invalid", op),
+				Condition: And(conditions...),
+				Effect:    FailEffect(),
 			})
 		}
 	}
 
+	// The designated invalid instruction aborts the execution of EOF code.
+	rules = append(rules, Rule{
+		Name: "INVALID_eof",
+		Condition: And(
+			Eq(Status(), st.Running),
+			Eq(Op(Pc()), vm.INVALID),
+			IsCode(Pc()),
+			IsRevision(tosca.R98_Experimental),
+			IsEofCode(),
+		),
+		Effect: FailEffect(),
+	})
+
 	// --- Error States ---
 
 	rules = append(rules, []Rule{
@@ -293,16 +334,13 @@ func getAllRules() []Rule {
 			MemoryOffsetParameter{},
 			SizeParameter{},
 		},
+		effects: []Effect{
+			ExpandMemory(Param(0), Param(1)),
+		},
 		effect: func(s *st.State) {
 			offsetU256 := s.Stack.Pop()
 			sizeU256 := s.Stack.Pop()
-
-			memExpCost, offset, size := s.Memory.ExpansionCosts(offsetU256, sizeU256)
-			if s.Gas < memExpCost {
-				s.Status = st.Failed
-				return
-			}
-			s.Gas -= memExpCost
+			_, offset, size := s.Memory.ExpansionCosts(offsetU256, sizeU256)
 
 			wordCost := tosca.Gas(6 * tosca.SizeInWords(size))
 			if s.Gas < wordCost {
@@ -320,10 +358,10 @@ func getAllRules() []Rule {
 
 	// cold
 	rules = append(rules, rulesFor(instruction{
-		op:        vm.BALANCE,
-		staticGas: 0 + 2600, // 2600 dynamic cost for cold address
-		pops:      1,
-		pushes:    1,
+		op:         vm.BALANCE,
+		dynamicGas: []tosca.DynamicGasKind{tosca.ColdAccountAccessGas},
+		pops:       1,
+		pushes:     1,
 		conditions: []Condition{
 			RevisionBounds(tosca.R09_Berlin, NewestSupportedRevision),
 			IsAddressCold(Param(0)),
@@ -331,20 +369,22 @@ func getAllRules() []Rule {
 		parameters: []Parameter{
 			AddressParameter{},
 		},
+		effects: []Effect{
+			MarkAddressWarm(Param(0)),
+		},
 		effect: func(s *st.State) {
 			address := NewAddress(s.Stack.Pop())
 			s.Stack.Push(s.Accounts.GetBalance(address))
-			s.Accounts.MarkWarm(address)
 		},
 		name: "_cold",
 	})...)
 
 	// warm
 	rules = append(rules, rulesFor(instruction{
-		op:        vm.BALANCE,
-		staticGas: 0 + 100, // 100 dynamic cost for warm address
-		pops:      1,
-		pushes:    1,
+		op:         vm.BALANCE,
+		dynamicGas: []tosca.DynamicGasKind{tosca.WarmAccountAccessGas},
+		pops:       1,
+		pushes:     1,
 		conditions: []Condition{
 			RevisionBounds(tosca.R09_Berlin, NewestSupportedRevision),
 			IsAddressWarm(Param(0)),
@@ -388,16 +428,11 @@ func getAllRules() []Rule {
 		parameters: []Parameter{
 			MemoryOffsetParameter{},
 		},
+		effects: []Effect{
+			ExpandMemory(Param(0), Constant(NewU256(32))),
+		},
 		effect: func(s *st.State) {
-			offsetU256 := s.Stack.Pop()
-
-			cost, offset, _ := s.Memory.ExpansionCosts(offsetU256, NewU256(32))
-			if s.Gas < cost {
-				s.Status = st.Failed
-				return
-			}
-			s.Gas -= cost
-
+			offset := s.Stack.Pop().Uint64()
 			value := NewU256FromBytes(s.Memory.Read(offset, 32)...)
 			s.Stack.Push(value)
 		},
@@ -414,17 +449,12 @@ func getAllRules() []Rule {
 			MemoryOffsetParameter{},
 			NumericParameter{},
 		},
+		effects: []Effect{
+			ExpandMemory(Param(0), Constant(NewU256(32))),
+		},
 		effect: func(s *st.State) {
-			offsetU256 := s.Stack.Pop()
+			offset := s.Stack.Pop().Uint64()
 			value := s.Stack.Pop()
-
-			cost, offset, _ := s.Memory.ExpansionCosts(offsetU256, NewU256(32))
-			if s.Gas < cost {
-				s.Status = st.Failed
-				return
-			}
-			s.Gas -= cost
-
 			bytes := value.Bytes32be()
 			s.Memory.Write(bytes[:], offset)
 		},
@@ -441,19 +471,13 @@ func getAllRules() []Rule {
 			MemoryOffsetParameter{},
 			NumericParameter{},
 		},
+		effects: []Effect{
+			ExpandMemory(Param(0), Constant(NewU256(1))),
+		},
 		effect: func(s *st.State) {
-			offsetU256 := s.Stack.Pop()
+			offset := s.Stack.Pop().Uint64()
 			value := s.Stack.Pop()
-
-			cost, offset, _ := s.Memory.ExpansionCosts(offsetU256, NewU256(1))
-			if s.Gas < cost {
-				s.Status = st.Failed
-				return
-			}
-			s.Gas -= cost
-
 			s.Memory.Write([]byte{value.Bytes32be()[31]}, offset)
-
 		},
 	})...)
 
@@ -461,10 +485,10 @@ func getAllRules() []Rule {
 
 	// cold
 	rules = append(rules, rulesFor(instruction{
-		op:        vm.SLOAD,
-		staticGas: 100 + 2000, // 2000 are from the dynamic cost of cold mem
-		pops:      1,
-		pushes:    1,
+		op:         vm.SLOAD,
+		dynamicGas: []tosca.DynamicGasKind{tosca.ColdSloadGas},
+		pops:       1,
+		pushes:     1,
 		conditions: []Condition{
 			RevisionBounds(tosca.R09_Berlin, NewestSupportedRevision),
 			IsStorageCold(Param(0)),
@@ -472,20 +496,22 @@ func getAllRules() []Rule {
 		parameters: []Parameter{
 			NumericParameter{},
 		},
+		effects: []Effect{
+			MarkStorageWarm(Param(0)),
+		},
 		effect: func(s *st.State) {
 			key := s.Stack.Pop()
 			s.Stack.Push(s.Storage.GetCurrent(key))
-			s.Storage.MarkWarm(key)
 		},
 		name: "_cold",
 	})...)
 
 	// warm
 	rules = append(rules, rulesFor(instruction{
-		op:        vm.SLOAD,
-		staticGas: 100,
-		pops:      1,
-		pushes:    1,
+		op:         vm.SLOAD,
+		dynamicGas: []tosca.DynamicGasKind{tosca.WarmSloadGas},
+		pops:       1,
+		pushes:     1,
 		conditions: []Condition{
 			RevisionBounds(tosca.R09_Berlin, NewestSupportedRevision),
 			IsStorageWarm(Param(0)),
@@ -556,7 +582,7 @@ func getAllRules() []Rule {
 		{revision: tosca.R09_Berlin, warm: true, config: tosca.StorageModifiedRestored, gasCost: 100, gasRefund: 2800},
 	}
 
-	for rev := tosca.R10_London; rev <= NewestSupportedRevision; rev++ {
+	for rev := tosca.R10_London; rev <= NewestSupportedRevision; rev = GetNextRevision(rev) {
 		// Certain storage configurations imply warm access. Not all
 		// combinations are possible; invalid ones are marked below.
 		sstoreRules = append(sstoreRules, []sstoreOpParams{
@@ -625,7 +651,7 @@ func getAllRules() []Rule {
 			Eq(Op(Pc()), vm.JUMP),
 			IsData(Param(0)),
 		},
-		effect: FailEffect().Apply,
+		effects: []Effect{FailEffect()},
 	})...)
 
 	rules = append(rules, rulesFor(instruction{
@@ -644,7 +670,7 @@ func getAllRules() []Rule {
 			IsCode(Param(0)),
 			Ne(Op(Param(0)), vm.JUMPDEST),
 		},
-		effect: FailEffect().Apply,
+		effects: []Effect{FailEffect()},
 	})...)
 
 	// --- JUMPI ---
@@ -703,7 +729,7 @@ func getAllRules() []Rule {
 			IsData(Param(0)),
 			Ne(Param(1), NewU256(0)),
 		},
-		effect: FailEffect().Apply,
+		effects: []Effect{FailEffect()},
 	})...)
 
 	rules = append(rules, rulesFor(instruction{
@@ -721,7 +747,7 @@ func getAllRules() []Rule {
 			IsCode(Param(0)),
 			Ne(Op(Param(0)), vm.JUMPDEST),
 		},
-		effect: FailEffect().Apply,
+		effects: []Effect{FailEffect()},
 	})...)
 
 	// --- PC ---
@@ -767,13 +793,13 @@ func getAllRules() []Rule {
 		staticGas: 1,
 		pops:      0,
 		pushes:    0,
-		effect:    NoEffect().Apply,
 	})...)
 
 	// --- TLOAD ---
 
 	rules = append(rules, rulesFor(instruction{
 		op:        vm.TLOAD,
+		name:      "_non_zero",
 		staticGas: 100,
 		pops:      1,
 		pushes:    1,
@@ -793,6 +819,7 @@ func getAllRules() []Rule {
 
 	rules = append(rules, rulesFor(instruction{
 		op:        vm.TLOAD,
+		name:      "_zero",
 		staticGas: 100,
 		pops:      1,
 		pushes:    1,
@@ -824,6 +851,7 @@ func getAllRules() []Rule {
 
 	rules = append(rules, rulesFor(instruction{
 		op:        vm.TSTORE,
+		name:      "_non_zero",
 		staticGas: 100,
 		pops:      2,
 		pushes:    0,
@@ -845,6 +873,7 @@ func getAllRules() []Rule {
 
 	rules = append(rules, rulesFor(instruction{
 		op:        vm.TSTORE,
+		name:      "_zero",
 		staticGas: 100,
 		pops:      2,
 		pushes:    0,
@@ -910,7 +939,7 @@ func getAllRules() []Rule {
 		conditions: []Condition{
 			RevisionBounds(tosca.R07_Istanbul, tosca.R11_Paris),
 		},
-		effect: FailEffect().Apply,
+		effects: []Effect{FailEffect()},
 	})...)
 
 	// --- MCOPY ---
@@ -976,8 +1005,8 @@ func getAllRules() []Rule {
 		staticGas: 2,
 		pops:      1,
 		pushes:    0,
-		effect: func(s *st.State) {
-			s.Stack.Pop()
+		effects: []Effect{
+			Pop(1),
 		},
 	})...)
 
@@ -1146,10 +1175,10 @@ func getAllRules() []Rule {
 
 	// cold
 	rules = append(rules, rulesFor(instruction{
-		op:        vm.EXTCODESIZE,
-		staticGas: 0 + 2600, // 2600 dynamic cost for cold address
-		pops:      1,
-		pushes:    1,
+		op:         vm.EXTCODESIZE,
+		dynamicGas: []tosca.DynamicGasKind{tosca.ColdAccountAccessGas},
+		pops:       1,
+		pushes:     1,
 		conditions: []Condition{
 			RevisionBounds(tosca.R09_Berlin, NewestSupportedRevision),
 			IsAddressCold(Param(0)),
@@ -1157,21 +1186,23 @@ func getAllRules() []Rule {
 		parameters: []Parameter{
 			AddressParameter{},
 		},
+		effects: []Effect{
+			MarkAddressWarm(Param(0)),
+		},
 		effect: func(s *st.State) {
 			address := NewAddress(s.Stack.Pop())
 			size := s.Accounts.GetCode(address).Length()
 			s.Stack.Push(NewU256(uint64(size)))
-			s.Accounts.MarkWarm(address)
 		},
 		name: "_cold",
 	})...)
 
 	// warm
 	rules = append(rules, rulesFor(instruction{
-		op:        vm.EXTCODESIZE,
-		staticGas: 0 + 100, // 100 dynamic cost for warm address
-		pops:      1,
-		pushes:    1,
+		op:         vm.EXTCODESIZE,
+		dynamicGas: []tosca.DynamicGasKind{tosca.WarmAccountAccessGas},
+		pops:       1,
+		pushes:     1,
 		conditions: []Condition{
 			RevisionBounds(tosca.R09_Berlin, NewestSupportedRevision),
 			IsAddressWarm(Param(0)),
@@ -1211,10 +1242,10 @@ func getAllRules() []Rule {
 
 	// cold
 	rules = append(rules, rulesFor(instruction{
-		op:        vm.EXTCODECOPY,
-		staticGas: 2600,
-		pops:      4,
-		pushes:    0,
+		op:         vm.EXTCODECOPY,
+		dynamicGas: []tosca.DynamicGasKind{tosca.ColdAccountAccessGas},
+		pops:       4,
+		pushes:     0,
 		conditions: []Condition{
 			RevisionBounds(tosca.R09_Berlin, NewestSupportedRevision),
 			IsAddressCold(Param(0)),
@@ -1224,18 +1255,20 @@ func getAllRules() []Rule {
 			MemoryOffsetParameter{},
 			DataOffsetParameter{},
 			SizeParameter{}},
-		effect: func(s *st.State) {
-			extCodeCopyEffect(s, true)
+		effects: []Effect{
+			ExpandMemory(Param(1), Param(3)),
+			MarkAddressWarm(Param(0)),
 		},
-		name: "_cold",
+		effect: extCodeCopyEffect,
+		name:   "_cold",
 	})...)
 
 	// warm
 	rules = append(rules, rulesFor(instruction{
-		op:        vm.EXTCODECOPY,
-		staticGas: 100,
-		pops:      4,
-		pushes:    0,
+		op:         vm.EXTCODECOPY,
+		dynamicGas: []tosca.DynamicGasKind{tosca.WarmAccountAccessGas},
+		pops:       4,
+		pushes:     0,
 		conditions: []Condition{
 			RevisionBounds(tosca.R09_Berlin, NewestSupportedRevision),
 			IsAddressWarm(Param(0)),
@@ -1245,10 +1278,11 @@ func getAllRules() []Rule {
 			MemoryOffsetParameter{},
 			DataOffsetParameter{},
 			SizeParameter{}},
-		effect: func(s *st.State) {
-			extCodeCopyEffect(s, false)
+		effects: []Effect{
+			ExpandMemory(Param(1), Param(3)),
 		},
-		name: "_warm",
+		effect: extCodeCopyEffect,
+		name:   "_warm",
 	})...)
 
 	// pre Berlin
@@ -1265,10 +1299,11 @@ func getAllRules() []Rule {
 			MemoryOffsetParameter{},
 			DataOffsetParameter{},
 			SizeParameter{}},
-		effect: func(s *st.State) {
-			extCodeCopyEffect(s, false)
+		effects: []Effect{
+			ExpandMemory(Param(1), Param(3)),
 		},
-		name: "_preBerlin",
+		effect: extCodeCopyEffect,
+		name:   "_preBerlin",
 	})...)
 
 	// --- TIMESTAMP ---
@@ -1384,11 +1419,12 @@ func getAllRules() []Rule {
 
 	// --- EXTCODEHASH ---
 
-	for _, revision := range tosca.GetAllKnownRevisions() {
+	for revision := MinRevision; revision <= NewestSupportedRevision; revision = GetNextRevision(revision) {
 		for _, warm := range []bool{true, false} {
 			for _, isEmpty := range []bool{true, false} {
 				name := "_" + revision.String()
-				staticGas := tosca.Gas(100) // warm access
+				staticGas := tosca.Gas(0)
+				accessGas := tosca.WarmAccountAccessGas
 				conditions := []Condition{IsRevision(revision)}
 
 				if warm {
@@ -1396,12 +1432,19 @@ func getAllRules() []Rule {
 					conditions = append(conditions, IsAddressWarm(Param(0)))
 				} else {
 					name += "_cold"
-					staticGas = 2600
+					accessGas = tosca.ColdAccountAccessGas
 					conditions = append(conditions, IsAddressCold(Param(0)))
 				}
 
+				dynamicGas := []tosca.DynamicGasKind{}
+				effects := []Effect{}
 				if revision < tosca.R09_Berlin {
 					staticGas = 700
+				} else {
+					dynamicGas = append(dynamicGas, accessGas)
+					if !warm {
+						effects = append(effects, MarkAddressWarm(Param(0)))
+					}
 				}
 
 				if isEmpty {
@@ -1416,12 +1459,14 @@ func getAllRules() []Rule {
 					op:         vm.EXTCODEHASH,
 					name:       name,
 					staticGas:  staticGas,
+					dynamicGas: dynamicGas,
 					pops:       1,
 					pushes:     1,
 					conditions: conditions,
 					parameters: []Parameter{
 						AddressParameter{},
 					},
+					effects: effects,
 					effect: func(s *st.State) {
 						address := NewAddress(s.Stack.Pop())
 						if s.Accounts.IsEmpty(address) {
@@ -1430,9 +1475,6 @@ func getAllRules() []Rule {
 							hash := s.Accounts.GetCodeHash(address)
 							s.Stack.Push(NewU256FromBytes(hash[:]...))
 						}
-						if revision >= tosca.R09_Berlin && !warm {
-							s.Accounts.MarkWarm(address)
-						}
 					},
 				})...)
 			}
@@ -1474,14 +1516,17 @@ func getAllRules() []Rule {
 			MemoryOffsetParameter{},
 			DataOffsetParameter{},
 			SizeParameter{}},
+		effects: []Effect{
+			ExpandMemory(Param(0), Param(2)),
+		},
 		effect: func(s *st.State) {
 			destOffsetU256 := s.Stack.Pop()
 			offsetU256 := s.Stack.Pop()
 			sizeU256 := s.Stack.Pop()
 
-			cost, destOffset, size := s.Memory.ExpansionCosts(destOffsetU256, sizeU256)
-			cost, overflow := sumWithOverflow(cost, tosca.Gas(3*tosca.SizeInWords(size)))
-			if s.Gas < cost || overflow {
+			_, destOffset, size := s.Memory.ExpansionCosts(destOffsetU256, sizeU256)
+			cost := tosca.Gas(3 * tosca.SizeInWords(size))
+			if s.Gas < cost {
 				s.Status = st.Failed
 				return
 			}
@@ -1547,18 +1592,21 @@ func getAllRules() []Rule {
 			MemoryOffsetParameter{},
 			DataOffsetParameter{},
 			SizeParameter{}},
+		effects: []Effect{
+			ExpandMemory(Param(0), Param(2)),
+		},
 		effect: func(s *st.State) {
 			destOffsetU256 := s.Stack.Pop()
 			offsetU256 := s.Stack.Pop()
 			sizeU256 := s.Stack.Pop()
 
-			expansionCost, destOffset, size := s.Memory.ExpansionCosts(destOffsetU256, sizeU256)
-			expansionCost, overflow := sumWithOverflow(expansionCost, tosca.Gas(3*tosca.SizeInWords(size)))
-			if s.Gas < expansionCost || overflow {
+			_, destOffset, size := s.Memory.ExpansionCosts(destOffsetU256, sizeU256)
+			copyCost := tosca.Gas(3 * tosca.SizeInWords(size))
+			if s.Gas < copyCost {
 				s.Status = st.Failed
 				return
 			}
-			s.Gas -= expansionCost
+			s.Gas -= copyCost
 
 			start := offsetU256.Uint64()
 			len := s.CallData.Length()
@@ -1604,6 +1652,9 @@ func getAllRules() []Rule {
 		staticGas: 3,
 		pops:      3,
 		pushes:    0,
+		conditions: []Condition{
+			IsLegacyCode(),
+		},
 		parameters: []Parameter{
 			MemoryOffsetParameter{},
 			DataOffsetParameter{},
@@ -1644,17 +1695,13 @@ func getAllRules() []Rule {
 		parameters: []Parameter{
 			MemoryOffsetParameter{},
 			SizeParameter{}},
+		effects: []Effect{
+			ExpandMemory(Param(0), Param(1)),
+		},
 		effect: func(s *st.State) {
 			offsetU256 := s.Stack.Pop()
 			sizeU256 := s.Stack.Pop()
-
-			expansionCost, offset, size := s.Memory.ExpansionCosts(offsetU256, sizeU256)
-			if s.Gas < expansionCost {
-				s.Status = st.Failed
-				return
-			}
-			s.Gas -= expansionCost
-
+			_, offset, size := s.Memory.ExpansionCosts(offsetU256, sizeU256)
 			s.ReturnData = NewBytes(s.Memory.Read(offset, size))
 			s.Status = st.Stopped
 		},
@@ -1670,17 +1717,13 @@ func getAllRules() []Rule {
 		parameters: []Parameter{
 			MemoryOffsetParameter{},
 			SizeParameter{}},
+		effects: []Effect{
+			ExpandMemory(Param(0), Param(1)),
+		},
 		effect: func(s *st.State) {
 			offsetU256 := s.Stack.Pop()
 			sizeU256 := s.Stack.Pop()
-
-			expansionCost, offset, size := s.Memory.ExpansionCosts(offsetU256, sizeU256)
-			if s.Gas < expansionCost {
-				s.Status = st.Failed
-				return
-			}
-			s.Gas -= expansionCost
-
+			_, offset, size := s.Memory.ExpansionCosts(offsetU256, sizeU256)
 			s.ReturnData = NewBytes(s.Memory.Read(offset, size))
 			s.Status = st.Reverted
 		},
@@ -1692,7 +1735,7 @@ func getAllRules() []Rule {
 
 	// --- SELFDESTRUCT ---
 
-	for revision := tosca.R07_Istanbul; revision <= NewestSupportedRevision; revision++ {
+	for revision := tosca.R07_Istanbul; revision <= NewestSupportedRevision; revision = GetNextRevision(revision) {
 		for _, originatorHasFunds := range []bool{true, false} {
 			for _, beneficiaryAccountEmpty := range []bool{true, false} {
 				for _, beneficiaryAccountIsWarm := range []bool{true, false} {
@@ -1722,7 +1765,7 @@ func getAllRules() []Rule {
 			Eq(ReadOnly(), true),
 			AnyKnownRevision(),
 		},
-		effect: FailEffect().Apply,
+		effects: []Effect{FailEffect()},
 	})...)
 
 	// --- CREATE ---
@@ -1741,7 +1784,7 @@ func getAllRules() []Rule {
 			MemoryOffsetParameter{},
 			InitCodeSizeParameter{},
 		},
-		effect: FailEffect().Apply,
+		effects: []Effect{FailEffect()},
 	})...)
 
 	rules = append(rules, rulesFor(instruction{
@@ -1779,7 +1822,7 @@ func getAllRules() []Rule {
 			InitCodeSizeParameter{},
 			NumericParameter{},
 		},
-		effect: FailEffect().Apply,
+		effects: []Effect{FailEffect()},
 	})...)
 
 	rules = append(rules, rulesFor(instruction{
@@ -1801,6 +1844,10 @@ func getAllRules() []Rule {
 		},
 	})...)
 
+	// --- EOF ---
+
+	rules = append(rules, getRulesForEof()...)
+
 	// --- End ---
 
 	return rules
@@ -1971,19 +2018,8 @@ func pushOp(n int) []Rule {
 		staticGas: 3,
 		pops:      0,
 		pushes:    1,
-		effect: func(s *st.State) {
-			data := make([]byte, n)
-			for i := range n {
-				b, err := s.Code.GetData(int(s.Pc) + i)
-				// This panic will never be triggered because the code generator always ensures that
-				// after a PUSHX op there are X data bytes. This should be fixed by #592
-				if err != nil {
-					panic(err)
-				}
-				data[i] = b
-			}
-			s.Stack.Push(NewU256FromBytes(data...))
-			s.Pc += uint16(n)
+		effects: []Effect{
+			PushData(n),
 		},
 	})
 }
@@ -1997,8 +2033,8 @@ func dupOp(n int) []Rule {
 		staticGas: 3,
 		pops:      n,
 		pushes:    n + 1,
-		effect: func(s *st.State) {
-			s.Stack.Push(s.Stack.Get(n - 1))
+		effects: []Effect{
+			Dup(n),
 		},
 	})
 }
@@ -2012,24 +2048,23 @@ func swapOp(n int) []Rule {
 		staticGas: 3,
 		pops:      n + 1,
 		pushes:    n + 1,
-		effect: func(s *st.State) {
-			a := s.Stack.Get(0)
-			b := s.Stack.Get(n)
-			s.Stack.Set(0, b)
-			s.Stack.Set(n, a)
+		effects: []Effect{
+			Swap(n),
 		},
 	})
 }
 
-func extCodeCopyEffect(s *st.State, markWarm bool) {
+// extCodeCopyEffect copies the code of an account to the memory, which is
+// expected to have been expanded already.
+func extCodeCopyEffect(s *st.State) {
 	address := NewAddress(s.Stack.Pop())
 	destOffsetU256 := s.Stack.Pop()
 	offsetU256 := s.Stack.Pop()
 	sizeU256 := s.Stack.Pop()
 
-	cost, destOffset, size := s.Memory.ExpansionCosts(destOffsetU256, sizeU256)
-	cost, overflow := sumWithOverflow(cost, tosca.Gas(3*tosca.SizeInWords(size)))
-	if s.Gas < cost || overflow {
+	_, destOffset, size := s.Memory.ExpansionCosts(destOffsetU256, sizeU256)
+	cost := tosca.Gas(3 * tosca.SizeInWords(size))
+	if s.Gas < cost {
 		s.Status = st.Failed
 		return
 	}
@@ -2045,9 +2080,6 @@ func extCodeCopyEffect(s *st.State, markWarm bool) {
 	codeCopy := RightPadSlice(s.Accounts.GetCode(address).ToBytes()[start:end], int(size))
 
 	s.Memory.Write(codeCopy, destOffset)
-	if markWarm {
-		s.Accounts.MarkWarm(address)
-	}
 }
 
 type sstoreOpParams struct {
@@ -2058,6 +2090,29 @@ type sstoreOpParams struct {
 	gasRefund tosca.Gas
 }
 
+// getGas splits the costs of an SSTORE into the dynamic gas kinds priced by
+// gas schedules and the remaining costs. Since Berlin, cold slots are charged
+// a surcharge, and configurations neither adding, modifying, nor deleting the
+// original value of the slot are charged the price of warm slots.
+func (p sstoreOpParams) getGas() ([]tosca.DynamicGasKind, tosca.Gas) {
+	if p.revision < tosca.R09_Berlin {
+		return nil, p.gasCost
+	}
+	dynamicGas := []tosca.DynamicGasKind{}
+	remaining := p.gasCost
+	if !p.warm {
+		dynamicGas = append(dynamicGas, tosca.ColdSstoreSurchargeGas)
+		remaining -= tosca.ColdSstoreSurchargeGas.EthereumPrice()
+	}
+	switch p.config {
+	case tosca.StorageAdded, tosca.StorageModified, tosca.StorageDeleted:
+	default:
+		dynamicGas = append(dynamicGas, tosca.WarmSstoreGas)
+		remaining -= tosca.WarmSstoreGas.EthereumPrice()
+	}
+	return dynamicGas, remaining
+}
+
 func sstoreOpRegular(params sstoreOpParams) []Rule {
 	name := fmt.Sprintf("_%v_%v", params.revision, params.config)
 
@@ -2072,22 +2127,29 @@ func sstoreOpRegular(params sstoreOpParams) []Rule {
 		}
 	}
 
-	// EIP-2200 introduced a minimum amount of available gas for SSTORE.
-	// The gas price still does not change for configurations smaller than the minimum.
-	gasLimit := tosca.Gas(2301) // EIP2200
-	priceDiff := tosca.Gas(0)
-	if params.gasCost > gasLimit {
-		gasLimit = params.gasCost
-	} else {
-		priceDiff = gasLimit - params.gasCost
+	dynamicGas, remainingGas := params.getGas()
+	effects := []Effect{}
+	if remainingGas > 0 {
+		effects = append(effects, ConsumeGas(remainingGas))
+	}
+	if params.gasRefund != 0 {
+		effects = append(effects, AddGasRefund(params.gasRefund))
+	}
+	effects = append(effects, SetStorage(Param(0), Param(1)))
+	if params.revision >= tosca.R09_Berlin {
+		effects = append(effects, MarkStorageWarm(Param(0)))
 	}
+	effects = append(effects, Pop(2))
 
 	rules := rulesFor(instruction{
-		name:      name,
-		op:        vm.SSTORE,
-		staticGas: gasLimit,
-		pops:      2,
-		pushes:    0,
+		name:       name,
+		op:         vm.SSTORE,
+		dynamicGas: dynamicGas,
+		// EIP-2200 introduced a minimum amount of available gas for SSTORE.
+		// The gas price still does not change for configurations smaller than the minimum.
+		minGas: max(2301, params.gasCost),
+		pops:   2,
+		pushes: 0,
 		conditions: append(conditions, []Condition{
 			IsRevision(params.revision),
 			Eq(ReadOnly(), false),
@@ -2097,19 +2159,7 @@ func sstoreOpRegular(params sstoreOpParams) []Rule {
 			NumericParameter{},
 			NumericParameter{},
 		},
-		effect: func(s *st.State) {
-			// We use the minimum available gas to generate the test cases.
-			// The price difference is added back for configurations with lower gas cost.
-			s.Gas += priceDiff
-
-			s.GasRefund += params.gasRefund
-			key := s.Stack.Pop()
-			value := s.Stack.Pop()
-			s.Storage.SetCurrent(key, value)
-			if s.Revision >= tosca.R09_Berlin {
-				s.Storage.MarkWarm(key)
-			}
-		},
+		effects: effects,
 	})
 
 	return rules
@@ -2118,26 +2168,35 @@ func sstoreOpRegular(params sstoreOpParams) []Rule {
 func sstoreOpReadOnlyMode(params sstoreOpParams) []Rule {
 	name := fmt.Sprintf("_read_only_%v_%v", params.revision, params.config)
 
-	gasLimit := max(
-		// EIP2200
-		params.gasCost, tosca.Gas(2301))
+	conditions := []Condition{}
+	if params.revision >= tosca.R09_Berlin {
+		if params.warm {
+			name += "_warm"
+			conditions = append(conditions, IsStorageWarm(Param(0)))
+		} else {
+			name += "_cold"
+			conditions = append(conditions, IsStorageCold(Param(0)))
+		}
+	}
 
+	dynamicGas, _ := params.getGas()
 	rules := rulesFor(instruction{
-		name:      name,
-		op:        vm.SSTORE,
-		staticGas: gasLimit,
-		pops:      2,
-		pushes:    0,
-		conditions: []Condition{
+		name:       name,
+		op:         vm.SSTORE,
+		dynamicGas: dynamicGas,
+		minGas:     max(2301, params.gasCost), // EIP2200
+		pops:       2,
+		pushes:     0,
+		conditions: append(conditions,
 			IsRevision(params.revision),
 			Eq(ReadOnly(), true),
 			StorageConfiguration(params.config, Param(0), Param(1)),
-		},
+		),
 		parameters: []Parameter{
 			NumericParameter{},
 			NumericParameter{},
 		},
-		effect: FailEffect().Apply,
+		effects: []Effect{FailEffect()},
 	})
 
 	return rules
@@ -2165,6 +2224,9 @@ func logOp(n int) []Rule {
 		pushes:     0,
 		conditions: conditions,
 		parameters: parameter,
+		effects: []Effect{
+			ExpandMemory(Param(0), Param(1)),
+		},
 		effect: func(s *st.State) {
 			offsetU256 := s.Stack.Pop()
 			sizeU256 := s.Stack.Pop()
@@ -2174,12 +2236,7 @@ func logOp(n int) []Rule {
 				topics = append(topics, s.Stack.Pop())
 			}
 
-			memExpCost, offset, size := s.Memory.ExpansionCosts(offsetU256, sizeU256)
-			if s.Gas < memExpCost {
-				s.Status = st.Failed
-				return
-			}
-			s.Gas -= memExpCost
+			_, offset, size := s.Memory.ExpansionCosts(offsetU256, sizeU256)
 
 			if s.Gas < tosca.Gas(8*size) {
 				s.Status = st.Failed
@@ -2202,7 +2259,7 @@ func logOp(n int) []Rule {
 		conditions: []Condition{
 			Eq(ReadOnly(), true),
 		},
-		effect: FailEffect().Apply,
+		effects: []Effect{FailEffect()},
 	})...)
 
 	return rules
@@ -2264,11 +2321,21 @@ func makeSelfDestructRules(
 		name += "_is_not_new_contract"
 	}
 
+	// Accessing a cold beneficiary is charged since Berlin, while accessing a
+	// warm beneficiary is free of charge (EIP-2929).
+	var dynamicGas []tosca.DynamicGasKind
+	var effects []Effect
+	if revision >= tosca.R09_Berlin && !beneficiaryAccountIsWarm {
+		dynamicGas = append(dynamicGas, tosca.ColdAccountAccessGas)
+		effects = append(effects, MarkAddressWarm(Param(0)))
+	}
+
 	instruction := instruction{
-		op:        vm.SELFDESTRUCT,
-		name:      name,
-		staticGas: 5000,
-		pops:      1,
+		op:         vm.SELFDESTRUCT,
+		name:       name,
+		staticGas:  5000,
+		dynamicGas: dynamicGas,
+		pops:       1,
 		conditions: []Condition{
 			Eq(ReadOnly(), false),
 			IsRevision(revision),
@@ -2279,6 +2346,7 @@ func makeSelfDestructRules(
 			isNewContractCondition,
 		},
 		parameters: []Parameter{AddressParameter{}},
+		effects:    effects,
 		effect:     selfDestructEffect,
 	}
 
@@ -2297,12 +2365,6 @@ func selfDestructEffect(s *st.State) {
 
 	dynamicCost := tosca.Gas(0)
 
-	// Add warm-up costs if the beneficiary account is cold.
-	if s.Revision > tosca.R07_Istanbul && !s.Accounts.IsWarm(beneficiaryAccount) {
-		dynamicCost += 2600
-		s.Accounts.MarkWarm(beneficiaryAccount)
-	}
-
 	// Add costs for transferring the remaining balance.
 	if !originatorBalance.IsZero() {
 		// If the target account is empty, the account creation fee is added.
@@ -2345,12 +2407,11 @@ func selfDestructEffect(s *st.State) {
 }
 
 func tooLittleGas(i instruction) []Rule {
-	localConditions := append(i.conditions,
-		AnyKnownRevision(),
+	localConditions := slices.Concat(i.conditions, i.revisionConditions(), []Condition{
 		Eq(Status(), st.Running),
 		Eq(Op(Pc()), i.op),
 		IsCode(Pc()),
-		Lt(Gas(), i.staticGas))
+		Lt(Gas(), i.requiredGas())})
 	return []Rule{{
 		Name:      fmt.Sprintf("%v_with_too_little_gas%v", strings.ToLower(i.op.String()), i.name),
 		Condition: And(localConditions...),
@@ -2359,12 +2420,11 @@ func tooLittleGas(i instruction) []Rule {
 }
 
 func notEnoughSpace(i instruction) []Rule {
-	localConditions := append(i.conditions,
-		AnyKnownRevision(),
+	localConditions := slices.Concat(i.conditions, i.revisionConditions(), []Condition{
 		Eq(Status(), st.Running),
 		Eq(Op(Pc()), i.op),
 		IsCode(Pc()),
-		Ge(StackSize(), st.MaxStackSize))
+		Ge(StackSize(), st.MaxStackSize)})
 	return []Rule{{
 		Name:      fmt.Sprintf("%v_with_not_enough_space%v", strings.ToLower(i.op.String()), i.name),
 		Condition: And(localConditions...),
@@ -2373,8 +2433,7 @@ func notEnoughSpace(i instruction) []Rule {
 }
 
 func tooFewElements(i instruction) []Rule {
-	localConditions := append([]Condition{},
-		AnyKnownRevision(),
+	localConditions := append(i.revisionConditions(),
 		Eq(Status(), st.Running),
 		Eq(Op(Pc()), i.op),
 		IsCode(Pc()),
@@ -2388,12 +2447,13 @@ func tooFewElements(i instruction) []Rule {
 
 // rulesFor instantiates the basic rules depending on the instruction info.
 // any rule that cannot be expressed using this function must be implemented manually.
-// This function subtracts i.staticGas from state.Gas and increases state.Pc by one,
-// these two are always done before calling i.effect. This should be kept
-// in mind when implementing the effects of new rules.
+// This function subtracts i.staticGas and the prices of i.dynamicGas from state.Gas
+// and increases state.Pc by one, these are always done before applying i.effects
+// and calling i.effect.
+// This should be kept in mind when implementing the effects of new rules.
 func rulesFor(i instruction) []Rule {
 	res := []Rule{}
-	if i.staticGas > 0 {
+	if i.requiredGas() > 0 {
 		res = append(res, tooLittleGas(i)...)
 	}
 	if i.pops > 0 {
@@ -2405,43 +2465,61 @@ func rulesFor(i instruction) []Rule {
 	localConditions := append(i.conditions,
 		Eq(Status(), st.Running),
 		Eq(Op(Pc()), i.op),
-		Ge(Gas(), i.staticGas),
+		Ge(Gas(), i.requiredGas()),
 		Ge(StackSize(), i.pops),
 		Le(StackSize(), st.MaxStackSize-(max(i.pushes-i.pops, 0))),
 	)
 
-	if !slices.ContainsFunc(i.conditions, IsRevisionCondition) {
-		localConditions = append(localConditions, AnyKnownRevision())
+	if i.eof || !slices.ContainsFunc(i.conditions, IsRevisionCondition) {
+		localConditions = append(localConditions, i.revisionConditions()...)
 	}
 
 	res = append(res, Rule{
 		Name:      fmt.Sprintf("%s_regular%v", strings.ToLower(i.op.String()), i.name),
 		Condition: And(localConditions...),
 		Parameter: i.parameters,
-		Effect: Change(func(s *st.State) {
-			s.Gas -= i.staticGas
-			s.Pc++
-			i.effect(s)
-		}),
+		Effect:    i.getEffect(),
 	})
 	return res
 }
 
+// getEffect combines the effects of the regular case of the instruction,
+// consuming the static and dynamic gas and moving to the next instruction
+// before applying the declarative effects and the custom effect.
+func (i instruction) getEffect() Effect {
+	effects := []Effect{}
+	if i.staticGas > 0 {
+		effects = append(effects, ConsumeStaticGas(i.staticGas))
+	}
+	for _, kind := range i.dynamicGas {
+		effects = append(effects, ConsumeDynamicGas(kind))
+	}
+	effects = append(effects, IncrementPc(1))
+	effects = append(effects, i.effects...)
+	if i.effect != nil {
+		effects = append(effects, Change(i.effect))
+	}
+	return Sequence(effects...)
+}
+
 // getRulesForAllCallTypes returns rules for CALL, CALLCODE, STATICCALL and DELEGATECALL
 func getRulesForAllCallTypes() []Rule {
 	// NOTE: this rule only covers Istanbul, Berlin and London cases in a coarse-grained way.
 	// Follow-work is required to cover other revisions and situations,
 	// as well as special cases currently covered in the effect function.
-	callFailEffect := func(s *st.State, addrAccessCost tosca.Gas, op vm.OpCode) {
+	callFailEffect := func(s *st.State, op vm.OpCode) {
 		FailEffect().Apply(s)
 	}
 
 	res := []Rule{}
 	for _, op := range []vm.OpCode{vm.CALL, vm.CALLCODE, vm.STATICCALL, vm.DELEGATECALL} {
-		for rev := tosca.R07_Istanbul; rev <= NewestSupportedRevision; rev++ {
+		for rev := tosca.R07_Istanbul; rev <= NewestSupportedRevision; rev = GetNextRevision(rev) {
 			for _, warm := range []bool{true, false} {
 				for _, static := range []bool{true, false} {
 					for _, zeroValue := range []bool{true, false} {
+						if !zeroValue && (op == vm.STATICCALL || op == vm.DELEGATECALL) {
+							continue // < operations without value would produce duplicate rules
+						}
 						effect := callEffect
 						if op == vm.CALL && static && !zeroValue {
 							effect = callFailEffect
@@ -2468,22 +2546,32 @@ func getRulesForCall(
 	revision tosca.Revision,
 	warm, zeroValue bool,
 	delegationDesignator *DelegationDesignatorState,
-	opEffect func(s *st.State, addrAccessCost tosca.Gas, op vm.OpCode),
+	opEffect func(s *st.State, op vm.OpCode),
 	static bool,
 ) []Rule {
 
 	var staticGas tosca.Gas
-	var addressAccessCost tosca.Gas
+	var dynamicGas []tosca.DynamicGasKind
 
 	if revision == tosca.R07_Istanbul {
 		staticGas = 700
-		addressAccessCost = 0
 	} else if revision >= tosca.R09_Berlin {
 		staticGas = 0
 		if warm {
-			addressAccessCost = 100
+			dynamicGas = append(dynamicGas, tosca.WarmAccountAccessGas)
 		} else {
-			addressAccessCost = 2600
+			dynamicGas = append(dynamicGas, tosca.ColdAccountAccessGas)
+		}
+	}
+
+	// Accessing the delegate of an account with a delegation designator is
+	// charged like any other account access (EIP-7702).
+	if delegationDesignator != nil {
+		switch *delegationDesignator {
+		case WarmDelegationDesignation:
+			dynamicGas = append(dynamicGas, tosca.WarmAccountAccessGas)
+		case ColdDelegationDesignation:
+			dynamicGas = append(dynamicGas, tosca.ColdAccountAccessGas)
 		}
 	}
 
@@ -2567,17 +2655,18 @@ func getRulesForCall(
 		op:         op,
 		name:       name,
 		staticGas:  staticGas,
+		dynamicGas: dynamicGas,
 		pops:       pops,
 		pushes:     1,
 		conditions: callConditions,
 		parameters: parameters,
 		effect: func(s *st.State) {
-			opEffect(s, addressAccessCost, op)
+			opEffect(s, op)
 		},
 	})
 }
 
-func callEffect(s *st.State, addrAccessCost tosca.Gas, op vm.OpCode) {
+func callEffect(s *st.State, op vm.OpCode) {
 
 	gas := s.Stack.Pop()
 	target := s.Stack.Pop()
@@ -2612,20 +2701,12 @@ func callEffect(s *st.State, addrAccessCost tosca.Gas, op vm.OpCode) {
 		valueToEmptyAccountCost = 25000
 	}
 
-	// Apply costs releated to delegate designator.
-	// see https://eips.ethereum.org/EIPS/eip-7702
-
-	delegationDesignatorAccessCost := tosca.Gas(0)
+	// The access of the delegate of a delegate designator has been charged
+	// already, see https://eips.ethereum.org/EIPS/eip-7702
 	if s.Revision >= tosca.R14_Prague {
 		targetCode := s.Accounts.GetCode(target.Bytes20be())
-		delegateAddress, isDelegate := ParseDelegationDesignator(targetCode)
-		if isDelegate {
-			if s.Accounts.IsWarm(delegateAddress) {
-				delegationDesignatorAccessCost = 100
-			} else {
-				s.Accounts.MarkWarm(delegateAddress)
-				delegationDesignatorAccessCost = 2600
-			}
+		if delegateAddress, isDelegate := ParseDelegationDesignator(targetCode); isDelegate {
+			s.Accounts.MarkWarm(delegateAddress)
 		}
 	}
 
@@ -2634,8 +2715,6 @@ func callEffect(s *st.State, addrAccessCost tosca.Gas, op vm.OpCode) {
 		memoryExpansionCost,
 		positiveValueCost,
 		valueToEmptyAccountCost,
-		addrAccessCost,
-		delegationDesignatorAccessCost,
 	)
 	if s.Gas < dynamicGas || overflow {
 		s.Status = st.Failed
@@ -2741,3 +2820,563 @@ func sumWithOverflow[T constraints.Integer](values ...T) (T, bool) {
 	}
 	return res, false
 }
+
+// getRulesForEof returns the rules for the instructions of EOF code, which are
+// only defined for the tosca.R98_Experimental revision.
+func getRulesForEof() []Rule {
+	rules := []Rule{}
+
+	// --- RJUMP ---
+
+	rules = append(rules, rulesFor(instruction{
+		op:        vm.RJUMP,
+		staticGas: 2,
+		eof:       true,
+		effect: func(s *st.State) {
+			s.Pc = getRelativeJumpTarget(s, s.Pc, s.Pc+2)
+		},
+	})...)
+
+	// --- RJUMPI ---
+
+	rules = append(rules, rulesFor(instruction{
+		op:        vm.RJUMPI,
+		staticGas: 4,
+		pops:      1,
+		eof:       true,
+		parameters: []Parameter{
+			NumericParameter{},
+		},
+		effect: func(s *st.State) {
+			condition := s.Stack.Pop()
+			if condition.IsZero() {
+				s.Pc += 2
+				return
+			}
+			s.Pc = getRelativeJumpTarget(s, s.Pc, s.Pc+2)
+		},
+	})...)
+
+	// --- RJUMPV ---
+
+	rules = append(rules, rulesFor(instruction{
+		op:        vm.RJUMPV,
+		staticGas: 4,
+		pops:      1,
+		eof:       true,
+		parameters: []Parameter{
+			NumericParameter{},
+		},
+		effect: func(s *st.State) {
+			index := s.Stack.Pop()
+			maxIndex, _ := s.Code.GetData(int(s.Pc))
+			count := uint64(maxIndex) + 1
+			next := s.Pc + 1 + 2*uint16(count)
+			// Indices out of range continue after the jump table.
+			if !index.IsUint64() || index.Uint64() >= count {
+				s.Pc = next
+				return
+			}
+			s.Pc = getRelativeJumpTarget(s, s.Pc+1+2*uint16(index.Uint64()), next)
+		},
+	})...)
+
+	// --- CALLF ---
+
+	rules = append(rules, rulesFor(instruction{
+		op:        vm.CALLF,
+		name:      "_return_stack_overflow",
+		staticGas: 5,
+		eof:       true,
+		conditions: []Condition{
+			Ge(ReturnStackSize(), st.MaxReturnStackSize),
+		},
+		effects: []Effect{FailEffect()},
+	})...)
+
+	rules = append(rules, rulesFor(instruction{
+		op:        vm.CALLF,
+		staticGas: 5,
+		eof:       true,
+		conditions: []Condition{
+			Lt(ReturnStackSize(), st.MaxReturnStackSize),
+		},
+		effect: func(s *st.State) {
+			next := s.Pc + 2
+			if !enterSection(s, int(getImmediateUint16(s, s.Pc))) {
+				return
+			}
+			s.ReturnStack = append(s.ReturnStack, next)
+		},
+	})...)
+
+	// --- RETF ---
+
+	rules = append(rules, rulesFor(instruction{
+		op:        vm.RETF,
+		name:      "_empty_return_stack",
+		staticGas: 3,
+		eof:       true,
+		conditions: []Condition{
+			Eq(ReturnStackSize(), 0),
+		},
+		effects: []Effect{FailEffect()},
+	})...)
+
+	rules = append(rules, rulesFor(instruction{
+		op:        vm.RETF,
+		staticGas: 3,
+		eof:       true,
+		conditions: []Condition{
+			Ge(ReturnStackSize(), 1),
+		},
+		effect: func(s *st.State) {
+			last := len(s.ReturnStack) - 1
+			s.Pc = s.ReturnStack[last]
+			s.ReturnStack = s.ReturnStack[:last]
+		},
+	})...)
+
+	// --- JUMPF ---
+
+	rules = append(rules, rulesFor(instruction{
+		op:        vm.JUMPF,
+		staticGas: 5,
+		eof:       true,
+		effect: func(s *st.State) {
+			enterSection(s, int(getImmediateUint16(s, s.Pc)))
+		},
+	})...)
+
+	// --- DATALOAD ---
+
+	rules = append(rules, rulesFor(instruction{
+		op:        vm.DATALOAD,
+		staticGas: 4,
+		pops:      1,
+		pushes:    1,
+		eof:       true,
+		parameters: []Parameter{
+			DataOffsetParameter{},
+		},
+		effect: func(s *st.State) {
+			offset := s.Stack.Pop()
+			data := s.Code.GetEofContainer().Data
+			s.Stack.Push(NewU256FromBytes(getPaddedData(data, offset, 32)...))
+		},
+	})...)
+
+	// --- DATALOADN ---
+
+	rules = append(rules, rulesFor(instruction{
+		op:        vm.DATALOADN,
+		staticGas: 3,
+		pushes:    1,
+		eof:       true,
+		effect: func(s *st.State) {
+			offset := NewU256(uint64(getImmediateUint16(s, s.Pc)))
+			data := s.Code.GetEofContainer().Data
+			s.Stack.Push(NewU256FromBytes(getPaddedData(data, offset, 32)...))
+			s.Pc += 2
+		},
+	})...)
+
+	// --- DATASIZE ---
+
+	rules = append(rules, rulesFor(instruction{
+		op:        vm.DATASIZE,
+		staticGas: 2,
+		pushes:    1,
+		eof:       true,
+		effect: func(s *st.State) {
+			s.Stack.Push(NewU256(uint64(len(s.Code.GetEofContainer().Data))))
+		},
+	})...)
+
+	// --- DATACOPY ---
+
+	rules = append(rules, rulesFor(instruction{
+		op:        vm.DATACOPY,
+		staticGas: 3,
+		pops:      3,
+		eof:       true,
+		parameters: []Parameter{
+			MemoryOffsetParameter{},
+			DataOffsetParameter{},
+			SizeParameter{}},
+		effects: []Effect{
+			ExpandMemory(Param(0), Param(2)),
+		},
+		effect: func(s *st.State) {
+			eofDataCopyEffect(s, s.Code.GetEofContainer().Data)
+		},
+	})...)
+
+	// --- RETURNDATALOAD ---
+
+	rules = append(rules, rulesFor(instruction{
+		op:        vm.RETURNDATALOAD,
+		staticGas: 3,
+		pops:      1,
+		pushes:    1,
+		eof:       true,
+		parameters: []Parameter{
+			DataOffsetParameter{},
+		},
+		effect: func(s *st.State) {
+			offset := s.Stack.Pop()
+			data := s.LastCallReturnData.ToBytes()
+			s.Stack.Push(NewU256FromBytes(getPaddedData(data, offset, 32)...))
+		},
+	})...)
+
+	// --- RETURNDATACOPY ---
+
+	// In EOF code, reading beyond the end of the return data is padded with
+	// zeros instead of failing (EIP-7069).
+	rules = append(rules, rulesFor(instruction{
+		op:        vm.RETURNDATACOPY,
+		name:      "_eof",
+		staticGas: 3,
+		pops:      3,
+		eof:       true,
+		parameters: []Parameter{
+			MemoryOffsetParameter{},
+			DataOffsetParameter{},
+			SizeParameter{}},
+		effects: []Effect{
+			ExpandMemory(Param(0), Param(2)),
+		},
+		effect: func(s *st.State) {
+			eofDataCopyEffect(s, s.LastCallReturnData.ToBytes())
+		},
+	})...)
+
+	// --- EXTCALL, EXTDELEGATECALL and EXTSTATICCALL ---
+
+	for _, op := range []vm.OpCode{vm.EXTCALL, vm.EXTDELEGATECALL, vm.EXTSTATICCALL} {
+		for _, warm := range []bool{true, false} {
+			rules = append(rules, getRulesForExtCall(op, warm)...)
+		}
+	}
+
+	// --- EOFCREATE ---
+
+	rules = append(rules, rulesFor(instruction{
+		op:        vm.EOFCREATE,
+		name:      "_static",
+		staticGas: 32000,
+		pops:      4,
+		pushes:    1,
+		eof:       true,
+		conditions: []Condition{
+			Eq(ReadOnly(), true),
+		},
+		effects: []Effect{FailEffect()},
+	})...)
+
+	rules = append(rules, rulesFor(instruction{
+		op:        vm.EOFCREATE,
+		staticGas: 32000,
+		pops:      4,
+		pushes:    1,
+		eof:       true,
+		conditions: []Condition{
+			Eq(ReadOnly(), false),
+		},
+		parameters: []Parameter{
+			ValueParameter{},
+			NumericParameter{},
+			MemoryOffsetParameter{},
+			SizeParameter{},
+		},
+		effects: []Effect{
+			ExpandMemory(Param(2), Param(3)),
+		},
+		effect: eofCreateEffect,
+	})...)
+
+	// --- RETURNCONTRACT ---
+
+	rules = append(rules, rulesFor(instruction{
+		op:        vm.RETURNCONTRACT,
+		staticGas: 0,
+		pops:      2,
+		eof:       true,
+		parameters: []Parameter{
+			MemoryOffsetParameter{},
+			SizeParameter{},
+		},
+		effects: []Effect{
+			ExpandMemory(Param(0), Param(1)),
+		},
+		effect: func(s *st.State) {
+			offsetU256 := s.Stack.Pop()
+			sizeU256 := s.Stack.Pop()
+			_, offset, size := s.Memory.ExpansionCosts(offsetU256, sizeU256)
+			index, _ := s.Code.GetData(int(s.Pc))
+			container := s.Code.GetEofContainer().Containers[index]
+			deployed, err := eof.AppendAuxData(container, s.Memory.Read(offset, size))
+			if err != nil {
+				s.Status = st.Failed
+				return
+			}
+			s.ReturnData = NewBytes(deployed)
+			s.Status = st.Stopped
+		},
+	})...)
+
+	return rules
+}
+
+// getImmediateUint16 reads the big-endian 16-bit immediate argument of an
+// instruction of EOF code starting at the given position.
+func getImmediateUint16(s *st.State, pos uint16) uint16 {
+	high, _ := s.Code.GetData(int(pos))
+	low, _ := s.Code.GetData(int(pos) + 1)
+	return uint16(high)<<8 | uint16(low)
+}
+
+// getRelativeJumpTarget computes the target of a relative jump, with the
+// signed offset read at the given position and relative to the given base.
+func getRelativeJumpTarget(s *st.State, pos uint16, base uint16) uint16 {
+	return uint16(int(base) + int(int16(getImmediateUint16(s, pos))))
+}
+
+// enterSection continues the execution at the start of the given code section
+// if its maximum stack increase does not exceed the stack limit. Otherwise,
+// the execution fails and false is returned.
+func enterSection(s *st.State, section int) bool {
+	increase := int(s.Code.GetEofContainer().Types[section].MaxStackIncrease)
+	if s.Stack.Size()+increase > st.MaxStackSize {
+		s.Status = st.Failed
+		return false
+	}
+	s.Pc = uint16(s.Code.GetSectionStart(section))
+	return true
+}
+
+// getPaddedData returns size bytes of the given data starting at the given
+// offset, padded with zeros beyond the end of the data.
+func getPaddedData(data []byte, offsetU256 U256, size uint64) []byte {
+	start := uint64(len(data))
+	if offsetU256.IsUint64() && offsetU256.Uint64() < start {
+		start = offsetU256.Uint64()
+	}
+	end := min(start+size, uint64(len(data)))
+	return RightPadSlice(data[start:end], int(size))
+}
+
+// eofDataCopyEffect copies the given data to the memory, as done by DATACOPY
+// and RETURNDATACOPY in EOF code. The memory has been expanded already.
+func eofDataCopyEffect(s *st.State, data []byte) {
+	memOffsetU256 := s.Stack.Pop()
+	dataOffsetU256 := s.Stack.Pop()
+	sizeU256 := s.Stack.Pop()
+
+	_, memOffset, size := s.Memory.ExpansionCosts(memOffsetU256, sizeU256)
+	copyCost := tosca.Gas(3 * tosca.SizeInWords(size))
+	if s.Gas < copyCost {
+		s.Status = st.Failed
+		return
+	}
+	s.Gas -= copyCost
+	s.Memory.Write(getPaddedData(data, dataOffsetU256, size), memOffset)
+}
+
+func eofCreateEffect(s *st.State) {
+	valueU256 := s.Stack.Pop()
+	saltU256 := s.Stack.Pop()
+	offsetU256 := s.Stack.Pop()
+	sizeU256 := s.Stack.Pop()
+	_, offset, size := s.Memory.ExpansionCosts(offsetU256, sizeU256)
+
+	index, _ := s.Code.GetData(int(s.Pc))
+	s.Pc++
+	initCode := s.Code.GetEofContainer().Containers[index]
+
+	// Hashing the initcode container to compute the target address is charged.
+	hashCost := tosca.Gas(6 * tosca.SizeInWords(uint64(len(initCode))))
+	if s.Gas < hashCost {
+		s.Status = st.Failed
+		return
+	}
+	s.Gas -= hashCost
+	input := s.Memory.Read(offset, size)
+
+	if !valueU256.IsZero() {
+		balance := s.Accounts.GetBalance(s.CallContext.AccountAddress)
+		if balance.Lt(valueU256) {
+			s.Stack.Push(AddressToU256(tosca.Address{}))
+			s.LastCallReturnData = Bytes{}
+			return
+		}
+	}
+
+	limit := s.Gas - s.Gas/64
+
+	res := s.CallJournal.Call(tosca.EofCreate, tosca.CallParameters{
+		Sender:   s.CallContext.AccountAddress,
+		Value:    valueU256.Bytes32be(),
+		Gas:      limit,
+		Input:    input,
+		Salt:     saltU256.Bytes32be(),
+		InitCode: initCode,
+	})
+
+	s.Gas -= limit - res.GasLeft
+	s.GasRefund += res.GasRefund
+
+	if !res.Success {
+		s.Stack.Push(AddressToU256(tosca.Address{}))
+		s.LastCallReturnData = NewBytes(res.Output)
+		return
+	}
+	s.LastCallReturnData = Bytes{}
+	s.Stack.Push(AddressToU256(res.CreatedAddress))
+}
+
+// getRulesForExtCall returns the rules for EXTCALL, EXTDELEGATECALL and
+// EXTSTATICCALL, charging the access to a warm or cold target account.
+func getRulesForExtCall(op vm.OpCode, warm bool) []Rule {
+	name := "_warm"
+	accessGas := tosca.WarmAccountAccessGas
+	targetWarm := IsAddressWarm(Param(0))
+	if !warm {
+		name = "_cold"
+		accessGas = tosca.ColdAccountAccessGas
+		targetWarm = IsAddressCold(Param(0))
+	}
+
+	pops := 3
+	parameters := []Parameter{
+		AddressParameter{},
+		MemoryOffsetParameter{},
+		SizeParameter{},
+	}
+	if op == vm.EXTCALL {
+		pops = 4
+		parameters = append(parameters, ValueParameter{})
+	}
+
+	return rulesFor(instruction{
+		op:         op,
+		name:       name,
+		staticGas:  0,
+		dynamicGas: []tosca.DynamicGasKind{accessGas},
+		pops:       pops,
+		pushes:     1,
+		eof:        true,
+		conditions: []Condition{
+			targetWarm,
+		},
+		parameters: parameters,
+		effects: []Effect{
+			ExpandMemory(Param(1), Param(2)),
+		},
+		effect: func(s *st.State) {
+			extCallEffect(s, op)
+		},
+	})
+}
+
+func extCallEffect(s *st.State, op vm.OpCode) {
+	targetU256 := s.Stack.Pop()
+	offsetU256 := s.Stack.Pop()
+	sizeU256 := s.Stack.Pop()
+	var value U256
+	if op == vm.EXTCALL {
+		value = s.Stack.Pop()
+	}
+
+	// Targets must be valid addresses, with no bits set beyond 20 bytes.
+	if !targetU256.Shr(NewU256(160)).IsZero() {
+		s.Status = st.Failed
+		return
+	}
+	target := tosca.Address(targetU256.Bytes20be())
+	s.Accounts.MarkWarm(target)
+
+	_, offset, size := s.Memory.ExpansionCosts(offsetU256, sizeU256)
+	input := s.Memory.Read(offset, size)
+
+	// Transferring value is charged, more so if an account is created.
+	if !value.IsZero() {
+		if s.ReadOnly {
+			s.Status = st.Failed
+			return
+		}
+		valueCost := tosca.Gas(9000)
+		if s.Accounts.IsEmpty(target) {
+			valueCost += 25000
+		}
+		if s.Gas < valueCost {
+			s.Status = st.Failed
+			return
+		}
+		s.Gas -= valueCost
+	}
+
+	const (
+		success = 0
+		revert  = 1
+		failure = 2
+	)
+	s.LastCallReturnData = Bytes{}
+
+	// The caller retains at least 5000 gas, and calls with less than 2300 gas
+	// for the callee are not executed (EIP-7069).
+	limit := s.Gas - max(s.Gas/64, 5000)
+	if limit < 2300 {
+		s.Stack.Push(NewU256(revert))
+		return
+	}
+	if !value.IsZero() {
+		balance := s.Accounts.GetBalance(s.CallContext.AccountAddress)
+		if balance.Lt(value) {
+			s.Stack.Push(NewU256(revert))
+			return
+		}
+	}
+	// Delegate calls are only supported for EOF targets.
+	if op == vm.EXTDELEGATECALL && !eof.HasMagic(s.Accounts.GetCode(target).ToBytes()) {
+		s.Stack.Push(NewU256(revert))
+		return
+	}
+
+	kind := tosca.Call
+	sender := s.CallContext.AccountAddress
+	recipient := target
+	switch {
+	case op == vm.EXTDELEGATECALL:
+		kind = tosca.DelegateCall
+		sender = s.CallContext.CallerAddress
+		recipient = s.CallContext.AccountAddress
+		value = s.CallContext.Value
+	case op == vm.EXTSTATICCALL || s.ReadOnly:
+		kind = tosca.StaticCall
+	}
+
+	res := s.CallJournal.Call(kind, tosca.CallParameters{
+		Sender:      sender,
+		Recipient:   recipient,
+		Value:       value.Bytes32be(),
+		Gas:         limit,
+		Input:       input,
+		CodeAddress: target,
+	})
+
+	// Failed calls consume all gas and produce no output, which distinguishes
+	// them from reverted calls.
+	switch {
+	case res.Success:
+		s.Stack.Push(NewU256(success))
+	case res.GasLeft > 0 || len(res.Output) > 0:
+		s.Stack.Push(NewU256(revert))
+	default:
+		s.Stack.Push(NewU256(failure))
+	}
+	s.Gas -= limit - res.GasLeft
+	s.GasRefund += res.GasRefund
+	s.LastCallReturnData = NewBytes(res.Output)
+}
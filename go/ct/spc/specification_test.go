@@ -420,7 +420,7 @@ func TestSpecification_OpsWithDynamicCostHandleOverflowSizes(t *testing.T) {
 	rnd := rand.New(0)
 
 	for op := range indexOfMemParam {
-		for revision := common.MinRevision; revision <= common.NewestSupportedRevision; revision++ {
+		for revision := common.MinRevision; revision <= common.NewestSupportedRevision; revision = common.GetNextRevision(revision) {
 			for _, overflowParameterPosition := range indexOfMemParam[op] {
 				for _, value := range testValues {
 					t.Run(fmt.Sprintf("%v_%v_%v_%v", op, revision, overflowParameterPosition, value), func(t *testing.T) {
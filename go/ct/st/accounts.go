@@ -110,6 +110,25 @@ func (a *Accounts) MarkWarm(address tosca.Address) {
 	a.warm[address] = struct{}{}
 }
 
+// GetAddresses returns the addresses of all existing accounts in ascending
+// order.
+func (a *Accounts) GetAddresses() []tosca.Address {
+	return sortedAddresses(maps.Keys(a.accounts))
+}
+
+// GetWarmAddresses returns the addresses of all warm accounts in ascending
+// order.
+func (a *Accounts) GetWarmAddresses() []tosca.Address {
+	return sortedAddresses(maps.Keys(a.warm))
+}
+
+func sortedAddresses(addresses []tosca.Address) []tosca.Address {
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i][:], addresses[j][:]) < 0
+	})
+	return addresses
+}
+
 // -- State Management --
 
 func (a *Accounts) Clone() *Accounts {
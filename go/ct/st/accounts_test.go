@@ -12,6 +12,7 @@ package st
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"testing"
 
@@ -239,3 +240,21 @@ func BenchmarkAccountCloneModifyBalance(b *testing.B) {
 		b2.SetBalance(a, NewU256(3))
 	}
 }
+
+func TestAccounts_GetAddressesReturnsAddressesInOrder(t *testing.T) {
+	accounts := NewAccountsBuilder().
+		SetBalance(tosca.Address{2}, NewU256(1)).
+		SetCode(tosca.Address{1}, NewBytes([]byte{1})).
+		SetWarm(tosca.Address{3}).
+		SetWarm(tosca.Address{1}).
+		Build()
+
+	want := []tosca.Address{{1}, {2}}
+	if got := accounts.GetAddresses(); !slices.Equal(want, got) {
+		t.Errorf("unexpected addresses, wanted %v, got %v", want, got)
+	}
+	want = []tosca.Address{{1}, {3}}
+	if got := accounts.GetWarmAddresses(); !slices.Equal(want, got) {
+		t.Errorf("unexpected warm addresses, wanted %v, got %v", want, got)
+	}
+}
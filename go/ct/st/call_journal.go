@@ -21,7 +21,9 @@ import (
 // CallJournal is a part of the state modeling the effect of recursive
 // contract calls. It covers past calls to verify the proper execution
 // of calls as well as the effect of future calls to be triggered
-// by CREATE and CALL expressions.
+// by CREATE and CALL expressions. The execution of nested frames is
+// not modeled by the state; only the processor specification in
+// ct/processors covers frame transitions, for a fixed set of contracts.
 type CallJournal struct {
 	Past   []PastCall
 	Future []FutureCall
@@ -20,6 +20,7 @@ import (
 	"golang.org/x/crypto/sha3"
 
 	. "github.com/0xsoniclabs/tosca/go/ct/common"
+	"github.com/0xsoniclabs/tosca/go/tosca/eof"
 	"github.com/0xsoniclabs/tosca/go/tosca/vm"
 )
 
@@ -28,10 +29,14 @@ import (
 const MaxCodeSize = 1<<14 + 1<<13 // = 24576
 
 // Code is an immutable representation of EVM byte code which may be freely
-// copied and shared through shallow copies.
+// copied and shared through shallow copies. Codes forming a valid EOF
+// container are analyzed as such: only the instructions of the code sections
+// are code, everything else is data, including positions beyond the end.
 type Code struct {
 	code           []byte
 	isCode         []bool
+	container      *eof.Container // < the parsed container of EOF code, nil for legacy code
+	sections       []int          // < the start positions of the code sections of EOF code
 	hash           [32]byte
 	hashCalculated bool
 	hashMutex      sync.Mutex
@@ -45,6 +50,11 @@ const ErrInvalidPosition = ConstErr("invalid position")
 // code representation. The resulting code contains a copy of the provided code
 // to guarantee immutability.
 func NewCode(code []byte) *Code {
+	code = slices.Clone(code)[:len(code):len(code)]
+	if res := newEofCode(code); res != nil {
+		return res
+	}
+
 	isCode := make([]bool, 0, len(code)+32)
 	for i := 0; i < len(code); i++ {
 		isCode = append(isCode, true)
@@ -57,11 +67,59 @@ func NewCode(code []byte) *Code {
 	}
 
 	return &Code{
-		code:   slices.Clone(code)[:len(code):len(code)],
+		code:   code,
 		isCode: isCode,
 	}
 }
 
+// newEofCode analyzes the given code as an EOF container. If the code is
+// neither a valid runtime nor a valid initcode container, nil is returned.
+func newEofCode(code []byte) *Code {
+	if !eof.HasMagic(code) {
+		return nil
+	}
+	container, err := eof.ParseAndValidate(code, eof.RuntimeContainer)
+	if err != nil {
+		container, err = eof.ParseAndValidate(code, eof.InitcodeContainer)
+	}
+	if err != nil {
+		return nil
+	}
+
+	// The sections of the container are sub-slices of the given code,
+	// preceding the sub-containers and the data section.
+	start := len(code) - len(container.Data)
+	for _, sub := range container.Containers {
+		start -= len(sub)
+	}
+	for _, section := range container.Code {
+		start -= len(section)
+	}
+
+	isCode := make([]bool, len(code))
+	sections := make([]int, len(container.Code))
+	for i, section := range container.Code {
+		sections[i] = start
+		for pos := 0; pos < len(section); {
+			isCode[start+pos] = true
+			op := vm.OpCode(section[pos])
+			maxIndex := byte(0)
+			if op == vm.RJUMPV {
+				maxIndex = section[pos+1]
+			}
+			pos += 1 + eof.GetImmediateSize(op, maxIndex)
+		}
+		start += len(section)
+	}
+
+	return &Code{
+		code:      code,
+		isCode:    isCode,
+		container: container,
+		sections:  sections,
+	}
+}
+
 func (c *Code) Clone() *Code {
 	return c
 }
@@ -85,7 +143,7 @@ func (c *Code) Hash() [32]byte {
 
 func (c *Code) IsCode(pos int) bool {
 	if pos < 0 || pos >= len(c.isCode) {
-		return true // out-of-bounds STOP
+		return c.container == nil // out-of-bounds STOP in legacy code
 	}
 	return c.isCode[pos]
 }
@@ -95,9 +153,12 @@ func (c *Code) IsData(pos int) bool {
 }
 
 func (c *Code) GetOperation(pos int) (vm.OpCode, error) {
-	if pos < 0 || pos >= len(c.isCode) {
+	if c.container == nil && (pos < 0 || pos >= len(c.isCode)) {
 		return vm.STOP, nil
 	}
+	if pos < 0 || pos >= len(c.isCode) {
+		return vm.INVALID, ErrInvalidPosition
+	}
 	if !c.isCode[pos] {
 		return vm.INVALID, ErrInvalidPosition
 	}
@@ -114,6 +175,39 @@ func (c *Code) GetData(pos int) (byte, error) {
 	return c.code[pos], nil
 }
 
+// IsEof reports whether the code is a valid EOF container, either for the use
+// as runtime code or as initcode.
+func (c *Code) IsEof() bool {
+	return c.container != nil
+}
+
+// GetEofContainer returns the parsed container of EOF code, or nil for legacy
+// code. The container is shared and must not be modified.
+func (c *Code) GetEofContainer() *eof.Container {
+	return c.container
+}
+
+// GetSection returns the index of the code section of EOF code containing the
+// given position. For legacy code and positions outside of code sections,
+// false is returned.
+func (c *Code) GetSection(pos int) (int, bool) {
+	for i := len(c.sections) - 1; i >= 0; i-- {
+		if pos >= c.sections[i] {
+			if pos-c.sections[i] < len(c.container.Code[i]) {
+				return i, true
+			}
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// GetSectionStart returns the position of the first instruction of the given
+// code section of EOF code.
+func (c *Code) GetSectionStart(section int) int {
+	return c.sections[section]
+}
+
 // CopyCodeSlice copies code from the slice [start:end] to dst.
 // Returns the number of elements copied.
 func (c *Code) CopyCodeSlice(start, end int, dst []byte) int {
@@ -17,6 +17,7 @@ import (
 	"slices"
 	"testing"
 
+	"github.com/0xsoniclabs/tosca/go/tosca/eof"
 	"github.com/0xsoniclabs/tosca/go/tosca/vm"
 )
 
@@ -103,6 +104,58 @@ func TestCode_GetData(t *testing.T) {
 	}
 }
 
+func TestCode_EofCodeIsAnalyzedByContainerLayout(t *testing.T) {
+	container := eof.Container{
+		Types: []eof.FunctionType{{Inputs: 0, Outputs: eof.NonReturning, MaxStackIncrease: 1}},
+		Code:  [][]byte{{byte(vm.PUSH1), byte(vm.STOP), byte(vm.POP), byte(vm.STOP)}},
+		Data:  []byte{byte(vm.ADD), byte(vm.STOP)},
+	}
+	container.DataSize = uint16(len(container.Data))
+	raw := container.Bytes()
+	start := len(raw) - len(container.Data) - len(container.Code[0])
+
+	code := NewCode(raw)
+	if !code.IsEof() {
+		t.Fatalf("valid container is not recognized as EOF code")
+	}
+	if want, got := start, code.GetSectionStart(0); want != got {
+		t.Errorf("unexpected start of section, wanted %d, got %d", want, got)
+	}
+
+	instructions := map[int]vm.OpCode{start: vm.PUSH1, start + 2: vm.POP, start + 3: vm.STOP}
+	for pos := -1; pos <= len(raw); pos++ {
+		op, isInstruction := instructions[pos]
+		if got := code.IsCode(pos); got != isInstruction {
+			t.Errorf("unexpected IsCode of position %d, wanted %t, got %t", pos, isInstruction, got)
+		}
+		got, err := code.GetOperation(pos)
+		if isInstruction && (err != nil || got != op) {
+			t.Errorf("unexpected operation at position %d, wanted %v, got %v, err %v", pos, op, got, err)
+		}
+		if !isInstruction && !errors.Is(err, ErrInvalidPosition) {
+			t.Errorf("unexpected error for position %d: %v", pos, err)
+		}
+		section, inSection := code.GetSection(pos)
+		if want := pos >= start && pos < start+4; inSection != want || section != 0 {
+			t.Errorf("unexpected section of position %d: %d, %t", pos, section, inSection)
+		}
+	}
+}
+
+func TestCode_InvalidContainersAreLegacyCode(t *testing.T) {
+	container := eof.Container{
+		Types: []eof.FunctionType{{Inputs: 0, Outputs: eof.NonReturning, MaxStackIncrease: 0}},
+		Code:  [][]byte{{byte(vm.JUMPDEST)}}, // < runs past the end of the section
+	}
+	code := NewCode(container.Bytes())
+	if code.IsEof() || code.GetEofContainer() != nil {
+		t.Errorf("invalid container is considered EOF code")
+	}
+	if _, found := code.GetSection(0); found {
+		t.Errorf("legacy code has code sections")
+	}
+}
+
 func TestCode_Copy(t *testing.T) {
 	src := []byte{byte(vm.ADD), byte(vm.PUSH1), 5, byte(vm.PUSH2)}
 	code := NewCode(src)
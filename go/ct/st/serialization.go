@@ -13,6 +13,7 @@ package st
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"maps"
 	"os"
 	"slices"
@@ -24,6 +25,13 @@ import (
 ////////////////////////////////////////////////////////////
 // Importing/exporting state
 
+// jsonVersion is the version of the JSON format written by ExportStateJSON.
+// It must be incremented whenever the layout of the format changes. Files
+// written before versions were introduced lack the version field; their
+// layout is identical to version 1. Version 2 adds the return stack of EOF
+// code.
+const jsonVersion = 2
+
 // ExportStateJSON exports the given state in json format to the given file path.
 // If the file does not exist, it will be created.
 // If the file already exists, it will be overwritten.
@@ -47,6 +55,10 @@ func ImportStateJSON(filePath string) (*State, error) {
 	if err != nil {
 		return nil, err
 	}
+	if serializableState.Version > jsonVersion {
+		return nil, fmt.Errorf("unsupported version %d of state file, supported are versions up to %d",
+			serializableState.Version, jsonVersion)
+	}
 	return serializableState.deserialize(), nil
 }
 
@@ -56,6 +68,7 @@ func ImportStateJSON(filePath string) (*State, error) {
 // stateSerializable is a serializable representation of the State struct.
 // It can be used to serialize and deserialize a State struct.
 type stateSerializable struct {
+	Version               int `json:",omitempty"`
 	Status                StatusCode
 	Revision              tosca.Revision
 	ReadOnly              bool
@@ -80,6 +93,7 @@ type stateSerializable struct {
 	SelfDestructedJournal []serializableSelfDestructEntry
 	RecentBlockHashes     ImmutableHashArray
 	TransactionContext    *TransactionContext
+	ReturnStack           []uint16 `json:",omitempty"`
 }
 
 // storageSerializable is a serializable representation of the Storage struct.
@@ -143,6 +157,7 @@ func newSerializableJournal(journal []SelfDestructEntry) []serializableSelfDestr
 // The data of the input state is deep copied.
 func newStateSerializableFromState(state *State) *stateSerializable {
 	return &stateSerializable{
+		Version:               jsonVersion,
 		Status:                state.Status,
 		Revision:              state.Revision,
 		ReadOnly:              state.ReadOnly,
@@ -167,6 +182,7 @@ func newStateSerializableFromState(state *State) *stateSerializable {
 		SelfDestructedJournal: newSerializableJournal(state.SelfDestructedJournal),
 		RecentBlockHashes:     state.RecentBlockHashes,
 		TransactionContext:    state.TransactionContext.Clone(),
+		ReturnStack:           slices.Clone(state.ReturnStack),
 	}
 }
 
@@ -262,6 +278,7 @@ func (s *stateSerializable) deserialize() *State {
 		}
 	}
 	state.RecentBlockHashes = s.RecentBlockHashes
+	state.ReturnStack = slices.Clone(s.ReturnStack)
 	return state
 }
 
@@ -0,0 +1,675 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package st
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	. "github.com/0xsoniclabs/tosca/go/ct/common"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+)
+
+////////////////////////////////////////////////////////////
+// Binary encoding of states
+//
+// A binary stream starts with a header consisting of binaryMagic and the
+// version of the encoding as an unsigned varint. It is followed by any
+// number of records, each holding the length of an encoded state as an
+// unsigned varint and the encoded state. Integers within a state are
+// varint encoded, 256-bit values, addresses, and hashes are stored with
+// their fixed size, and maps are written in the order of their keys.
+// Thus, equal states are encoded identically.
+
+// binaryMagic identifies binary encoded states.
+const binaryMagic = "CTST"
+
+// binaryVersion is the version of the binary encoding produced by this
+// package. It must be incremented whenever the encoding of states changes;
+// decoding of older versions has to be retained. Version 2 adds the return
+// stack of EOF code.
+const binaryVersion = 2
+
+// maxBinaryStateSize is the maximum size of a single encoded state accepted
+// when reading a stream, protecting against corrupted length prefixes.
+const maxBinaryStateSize = 1 << 30
+
+// ExportStateBinary exports the given state in the binary format to the
+// given file path. If the file already exists, it will be overwritten.
+func ExportStateBinary(state *State, filePath string) error {
+	var buffer bytes.Buffer
+	writer, err := NewStateWriter(&buffer)
+	if err != nil {
+		return err
+	}
+	if err := writer.Write(state); err != nil {
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, buffer.Bytes(), 0644)
+}
+
+// ImportStateBinary imports a single state from the given binary file.
+// Files containing no or multiple states are rejected.
+func ImportStateBinary(filePath string) (*State, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	reader, err := NewStateReader(file)
+	if err != nil {
+		return nil, err
+	}
+	state, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("no state in %v", filePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := reader.Read(); err != io.EOF {
+		return nil, fmt.Errorf("more than one state in %v", filePath)
+	}
+	return state, nil
+}
+
+// ImportState imports a state from the given file, which may either be in
+// the JSON or in the binary format.
+func ImportState(filePath string) (*State, error) {
+	isBinary, err := isBinaryFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if isBinary {
+		return ImportStateBinary(filePath)
+	}
+	return ImportStateJSON(filePath)
+}
+
+// ImportStates imports all states of the given file and passes them to the
+// consumer in the order they are stored. The file may either be a JSON file
+// holding a single state or a binary stream holding any number of states.
+// Errors of the consumer abort the import and are returned.
+func ImportStates(filePath string, consume func(*State) error) error {
+	isBinary, err := isBinaryFile(filePath)
+	if err != nil {
+		return err
+	}
+	if !isBinary {
+		state, err := ImportStateJSON(filePath)
+		if err != nil {
+			return err
+		}
+		return consume(state)
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	reader, err := NewStateReader(file)
+	if err != nil {
+		return err
+	}
+	for {
+		state, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := consume(state); err != nil {
+			return err
+		}
+	}
+}
+
+// isBinaryFile reports whether the given file starts with the header of the
+// binary format.
+func isBinaryFile(filePath string) (bool, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+	prefix := make([]byte, len(binaryMagic))
+	_, err = io.ReadFull(file, prefix)
+	return err == nil && string(prefix) == binaryMagic, nil
+}
+
+// StateWriter writes a stream of binary encoded states. Writes are buffered;
+// Flush has to be called after the last state was written.
+type StateWriter struct {
+	writer *bufio.Writer
+	buffer []byte
+}
+
+// NewStateWriter creates a writer producing a stream of states in the
+// current version of the binary format. The header of the stream is
+// written immediately.
+func NewStateWriter(writer io.Writer) (*StateWriter, error) {
+	res := &StateWriter{writer: bufio.NewWriter(writer)}
+	header := binary.AppendUvarint([]byte(binaryMagic), binaryVersion)
+	if _, err := res.writer.Write(header); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Write appends the given state to the stream.
+func (w *StateWriter) Write(state *State) error {
+	encoder := binaryEncoder{data: w.buffer[:0]}
+	encoder.state(state)
+	w.buffer = encoder.data
+
+	var length [binary.MaxVarintLen64]byte
+	if _, err := w.writer.Write(binary.AppendUvarint(length[:0], uint64(len(w.buffer)))); err != nil {
+		return err
+	}
+	_, err := w.writer.Write(w.buffer)
+	return err
+}
+
+// Flush writes all buffered data to the underlying writer.
+func (w *StateWriter) Flush() error {
+	return w.writer.Flush()
+}
+
+// StateReader reads a stream of binary encoded states.
+type StateReader struct {
+	reader  *bufio.Reader
+	version uint64
+	buffer  []byte
+}
+
+// NewStateReader creates a reader for a stream of states written by a
+// StateWriter of this or an older version of this package. The header of
+// the stream is consumed and verified immediately.
+func NewStateReader(reader io.Reader) (*StateReader, error) {
+	res := &StateReader{reader: bufio.NewReader(reader)}
+	magic := make([]byte, len(binaryMagic))
+	if _, err := io.ReadFull(res.reader, magic); err != nil || string(magic) != binaryMagic {
+		return nil, fmt.Errorf("not a binary state stream")
+	}
+	version, err := binary.ReadUvarint(res.reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read version of state stream: %w", err)
+	}
+	if version == 0 || version > binaryVersion {
+		return nil, fmt.Errorf("unsupported version %d of state stream, supported are versions up to %d", version, binaryVersion)
+	}
+	res.version = version
+	return res, nil
+}
+
+// Read returns the next state of the stream, or io.EOF if the end of the
+// stream is reached.
+func (r *StateReader) Read() (*State, error) {
+	length, err := binary.ReadUvarint(r.reader)
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read length of state: %w", err)
+	}
+	if length > maxBinaryStateSize {
+		return nil, fmt.Errorf("encoded state size %d exceeds limit of %d", length, maxBinaryStateSize)
+	}
+	if uint64(cap(r.buffer)) < length {
+		r.buffer = make([]byte, length)
+	}
+	r.buffer = r.buffer[:length]
+	if _, err := io.ReadFull(r.reader, r.buffer); err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+
+	decoder := binaryDecoder{data: r.buffer, version: r.version}
+	state := decoder.state()
+	if decoder.err == nil && len(decoder.data) != 0 {
+		decoder.err = fmt.Errorf("%d trailing bytes after state", len(decoder.data))
+	}
+	if decoder.err != nil {
+		return nil, fmt.Errorf("invalid encoded state: %w", decoder.err)
+	}
+	return state, nil
+}
+
+////////////////////////////////////////////////////////////
+// Encoder
+
+type binaryEncoder struct {
+	data []byte
+}
+
+func (e *binaryEncoder) uint(value uint64) {
+	e.data = binary.AppendUvarint(e.data, value)
+}
+
+func (e *binaryEncoder) int(value int64) {
+	e.data = binary.AppendVarint(e.data, value)
+}
+
+func (e *binaryEncoder) bool(value bool) {
+	if value {
+		e.data = append(e.data, 1)
+	} else {
+		e.data = append(e.data, 0)
+	}
+}
+
+func (e *binaryEncoder) bytes(value []byte) {
+	e.uint(uint64(len(value)))
+	e.data = append(e.data, value...)
+}
+
+func (e *binaryEncoder) u256(value U256) {
+	word := value.Bytes32be()
+	e.data = append(e.data, word[:]...)
+}
+
+func (e *binaryEncoder) address(value tosca.Address) {
+	e.data = append(e.data, value[:]...)
+}
+
+func (e *binaryEncoder) hash(value tosca.Hash) {
+	e.data = append(e.data, value[:]...)
+}
+
+func (e *binaryEncoder) words(values map[U256]U256) {
+	e.uint(uint64(len(values)))
+	for _, key := range sortedKeys(values) {
+		e.u256(key)
+		e.u256(values[key])
+	}
+}
+
+func (e *binaryEncoder) state(state *State) {
+	e.int(int64(state.Status))
+	e.int(int64(state.Revision))
+	e.bool(state.ReadOnly)
+	e.uint(uint64(state.Pc))
+	e.int(int64(state.Gas))
+	e.int(int64(state.GasRefund))
+	e.bytes(state.Code.code)
+
+	e.uint(uint64(len(state.Stack.stack)))
+	for _, value := range state.Stack.stack {
+		e.u256(value)
+	}
+	e.bytes(state.Memory.mem)
+
+	e.words(state.Storage.current)
+	e.words(state.Storage.original)
+	e.uint(uint64(len(state.Storage.warm)))
+	for _, key := range sortedKeys(state.Storage.warm) {
+		e.u256(key)
+		e.bool(state.Storage.warm[key])
+	}
+	e.words(state.TransientStorage.storage)
+
+	addresses := state.Accounts.GetAddresses()
+	e.uint(uint64(len(addresses)))
+	for _, address := range addresses {
+		account := state.Accounts.accounts[address]
+		e.address(address)
+		e.u256(account.Balance)
+		e.bytes(account.Code.ToBytes())
+	}
+	warm := state.Accounts.GetWarmAddresses()
+	e.uint(uint64(len(warm)))
+	for _, address := range warm {
+		e.address(address)
+	}
+
+	e.uint(uint64(len(state.Logs.Entries)))
+	for _, entry := range state.Logs.Entries {
+		e.uint(uint64(len(entry.Topics)))
+		for _, topic := range entry.Topics {
+			e.u256(topic)
+		}
+		e.bytes(entry.Data)
+	}
+
+	e.address(state.CallContext.AccountAddress)
+	e.address(state.CallContext.CallerAddress)
+	e.u256(state.CallContext.Value)
+
+	journal := state.CallJournal
+	if journal == nil {
+		journal = NewCallJournal()
+	}
+	e.uint(uint64(len(journal.Past)))
+	for _, call := range journal.Past {
+		e.int(int64(call.Kind))
+		e.address(call.Recipient)
+		e.address(call.Sender)
+		e.bytes(call.Input.ToBytes())
+		e.data = append(e.data, call.Value[:]...)
+		e.int(int64(call.Gas))
+		e.address(call.CodeAddress)
+	}
+	e.uint(uint64(len(journal.Future)))
+	for _, call := range journal.Future {
+		e.bool(call.Success)
+		e.bytes(call.Output.ToBytes())
+		e.int(int64(call.GasCosts))
+		e.int(int64(call.GasRefund))
+		e.address(call.CreatedAccount)
+	}
+
+	block := state.BlockContext
+	e.u256(block.BaseFee)
+	e.u256(block.BlobBaseFee)
+	e.uint(block.BlockNumber)
+	e.u256(block.ChainID)
+	e.address(block.CoinBase)
+	e.uint(block.GasLimit)
+	e.u256(block.GasPrice)
+	e.u256(block.PrevRandao)
+	e.uint(block.TimeStamp)
+
+	e.bytes(state.CallData.ToBytes())
+	e.bytes(state.LastCallReturnData.ToBytes())
+	e.bytes(state.ReturnData.ToBytes())
+	e.bool(state.IsNewContract)
+	e.bool(state.HasSelfDestructed)
+	e.uint(uint64(len(state.SelfDestructedJournal)))
+	for _, entry := range state.SelfDestructedJournal {
+		e.address(entry.account)
+		e.address(entry.beneficiary)
+	}
+
+	// Trailing zero hashes are omitted, which is the common case for
+	// states without recent block hashes.
+	hashes := 256
+	for hashes > 0 && state.RecentBlockHashes.Get(uint64(hashes-1)) == (tosca.Hash{}) {
+		hashes--
+	}
+	e.uint(uint64(hashes))
+	for i := range hashes {
+		e.hash(state.RecentBlockHashes.Get(uint64(i)))
+	}
+
+	transaction := state.TransactionContext
+	if transaction == nil {
+		transaction = NewTransactionContext()
+	}
+	e.address(transaction.OriginAddress)
+	e.uint(uint64(len(transaction.BlobHashes)))
+	for _, hash := range transaction.BlobHashes {
+		e.hash(hash)
+	}
+
+	e.uint(uint64(len(state.ReturnStack)))
+	for _, pos := range state.ReturnStack {
+		e.uint(uint64(pos))
+	}
+}
+
+////////////////////////////////////////////////////////////
+// Decoder
+
+// binaryDecoder decodes values from a byte slice. The first error is
+// retained; all subsequent reads return zero values.
+type binaryDecoder struct {
+	data    []byte
+	version uint64
+	err     error
+}
+
+var errTruncated = errors.New("unexpected end of data")
+
+func (d *binaryDecoder) next(size int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if size > len(d.data) {
+		d.err = errTruncated
+		return nil
+	}
+	res := d.data[:size]
+	d.data = d.data[size:]
+	return res
+}
+
+func (d *binaryDecoder) uint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	value, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = errTruncated
+		return 0
+	}
+	d.data = d.data[n:]
+	return value
+}
+
+func (d *binaryDecoder) int() int64 {
+	if d.err != nil {
+		return 0
+	}
+	value, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.err = errTruncated
+		return 0
+	}
+	d.data = d.data[n:]
+	return value
+}
+
+// length reads the number of elements of a list or map. Since every
+// element occupies at least one byte, larger lengths indicate corrupted
+// data and are rejected before allocating memory for them.
+func (d *binaryDecoder) length() int {
+	length := d.uint()
+	if length > uint64(len(d.data)) {
+		if d.err == nil {
+			d.err = fmt.Errorf("length %d exceeds remaining data", length)
+		}
+		return 0
+	}
+	return int(length)
+}
+
+func (d *binaryDecoder) bool() bool {
+	data := d.next(1)
+	if data == nil {
+		return false
+	}
+	if data[0] > 1 {
+		d.err = fmt.Errorf("invalid boolean value %d", data[0])
+	}
+	return data[0] == 1
+}
+
+func (d *binaryDecoder) bytes() []byte {
+	return bytes.Clone(d.next(d.length()))
+}
+
+func (d *binaryDecoder) u256() U256 {
+	data := d.next(32)
+	if data == nil {
+		return U256{}
+	}
+	return NewU256FromBytes(data...)
+}
+
+func (d *binaryDecoder) address() (res tosca.Address) {
+	copy(res[:], d.next(len(res)))
+	return res
+}
+
+func (d *binaryDecoder) hash() (res tosca.Hash) {
+	copy(res[:], d.next(len(res)))
+	return res
+}
+
+func (d *binaryDecoder) words() map[U256]U256 {
+	length := d.length()
+	res := make(map[U256]U256, length)
+	for range length {
+		key := d.u256()
+		res[key] = d.u256()
+	}
+	return res
+}
+
+func (d *binaryDecoder) state() *State {
+	status := StatusCode(d.int())
+	revision := tosca.Revision(d.int())
+	readOnly := d.bool()
+	pc := d.uint()
+	if pc > 0xffff {
+		d.err = fmt.Errorf("program counter %d out of range", pc)
+	}
+	gas := tosca.Gas(d.int())
+	refund := tosca.Gas(d.int())
+
+	state := NewState(NewCode(d.bytes()))
+	state.Status = status
+	state.Revision = revision
+	state.ReadOnly = readOnly
+	state.Pc = uint16(pc)
+	state.Gas = gas
+	state.GasRefund = refund
+
+	stack := make([]U256, d.length())
+	for i := range stack {
+		stack[i] = d.u256()
+	}
+	state.Stack = NewStack(stack...)
+	state.Memory = NewMemory(d.bytes()...)
+
+	storage := NewStorageBuilder()
+	for key, value := range d.words() {
+		storage.SetCurrent(key, value)
+	}
+	for key, value := range d.words() {
+		storage.SetOriginal(key, value)
+	}
+	for range d.length() {
+		key := d.u256()
+		storage.SetWarm(key, d.bool())
+	}
+	state.Storage = storage.Build()
+	state.TransientStorage = &TransientStorage{d.words()}
+
+	accounts := NewAccountsBuilder()
+	for range d.length() {
+		address := d.address()
+		accounts.SetBalance(address, d.u256())
+		accounts.SetCode(address, NewBytes(d.bytes()))
+	}
+	for range d.length() {
+		accounts.SetWarm(d.address())
+	}
+	state.Accounts = accounts.Build()
+
+	for range d.length() {
+		topics := make([]U256, d.length())
+		for i := range topics {
+			topics[i] = d.u256()
+		}
+		state.Logs.AddLog(d.bytes(), topics...)
+	}
+
+	state.CallContext.AccountAddress = d.address()
+	state.CallContext.CallerAddress = d.address()
+	state.CallContext.Value = d.u256()
+
+	for range d.length() {
+		var call PastCall
+		call.Kind = tosca.CallKind(d.int())
+		call.Recipient = d.address()
+		call.Sender = d.address()
+		call.Input = NewBytes(d.bytes())
+		copy(call.Value[:], d.next(len(call.Value)))
+		call.Gas = tosca.Gas(d.int())
+		call.CodeAddress = d.address()
+		state.CallJournal.Past = append(state.CallJournal.Past, call)
+	}
+	for range d.length() {
+		var call FutureCall
+		call.Success = d.bool()
+		call.Output = NewBytes(d.bytes())
+		call.GasCosts = tosca.Gas(d.int())
+		call.GasRefund = tosca.Gas(d.int())
+		call.CreatedAccount = d.address()
+		state.CallJournal.Future = append(state.CallJournal.Future, call)
+	}
+
+	block := &state.BlockContext
+	block.BaseFee = d.u256()
+	block.BlobBaseFee = d.u256()
+	block.BlockNumber = d.uint()
+	block.ChainID = d.u256()
+	block.CoinBase = d.address()
+	block.GasLimit = d.uint()
+	block.GasPrice = d.u256()
+	block.PrevRandao = d.u256()
+	block.TimeStamp = d.uint()
+
+	state.CallData = NewBytes(d.bytes())
+	state.LastCallReturnData = NewBytes(d.bytes())
+	state.ReturnData = NewBytes(d.bytes())
+	state.IsNewContract = d.bool()
+	state.HasSelfDestructed = d.bool()
+	for range d.length() {
+		account := d.address()
+		state.SelfDestructedJournal = append(state.SelfDestructedJournal, SelfDestructEntry{account, d.address()})
+	}
+
+	hashes := d.length()
+	if hashes > 256 {
+		d.err = fmt.Errorf("number of recent block hashes %d out of range", hashes)
+		hashes = 0
+	}
+	if hashes > 0 {
+		recent := make([]tosca.Hash, hashes)
+		for i := range recent {
+			recent[i] = d.hash()
+		}
+		state.RecentBlockHashes = NewImmutableHashArray(recent...)
+	}
+
+	state.TransactionContext.OriginAddress = d.address()
+	if blobs := d.length(); blobs > 0 {
+		state.TransactionContext.BlobHashes = make([]tosca.Hash, blobs)
+		for i := range blobs {
+			state.TransactionContext.BlobHashes[i] = d.hash()
+		}
+	}
+
+	if d.version >= 2 {
+		if positions := d.length(); positions > 0 {
+			state.ReturnStack = make([]uint16, positions)
+			for i := range positions {
+				pos := d.uint()
+				if pos > 0xffff {
+					d.err = fmt.Errorf("return position %d out of range", pos)
+				}
+				state.ReturnStack[i] = uint16(pos)
+			}
+		}
+	}
+	return state
+}
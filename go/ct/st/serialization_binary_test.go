@@ -0,0 +1,395 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package st
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	. "github.com/0xsoniclabs/tosca/go/ct/common"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+)
+
+func TestSerialization_BinaryRoundTrip(t *testing.T) {
+	tests := map[string]*State{
+		"empty":  NewState(NewCode(nil)),
+		"filled": getNewFilledState(),
+	}
+	for name, state := range tests {
+		t.Run(name, func(t *testing.T) {
+			testFilePath := path.Join(t.TempDir(), "state.bin")
+			if err := ExportStateBinary(state, testFilePath); err != nil {
+				t.Fatal(err)
+			}
+			restored, err := ImportStateBinary(testFilePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !state.Eq(restored) {
+				t.Error("invalid deserialization, differences found:")
+				for _, diff := range state.Diff(restored) {
+					t.Error(diff)
+				}
+			}
+		})
+	}
+}
+
+func TestSerialization_BinaryEncodingIsDeterministicAndCompact(t *testing.T) {
+	state := getNewFilledState()
+	state.Accounts = NewAccountsBuilder().
+		SetBalance(tosca.Address{1}, NewU256(1)).
+		SetBalance(tosca.Address{2}, NewU256(2)).
+		SetCode(tosca.Address{3}, NewBytes([]byte{1, 2, 3})).
+		SetWarm(tosca.Address{4}).
+		SetWarm(tosca.Address{5}).
+		Build()
+
+	first := binaryEncoder{}
+	first.state(state)
+	second := binaryEncoder{}
+	second.state(state.Clone())
+	if !bytes.Equal(first.data, second.data) {
+		t.Errorf("encodings of equal states differ")
+	}
+
+	serialized, err := newStateSerializableFromState(state).serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.data) >= len(serialized) {
+		t.Errorf("binary encoding is not smaller than JSON: %d vs %d bytes", len(first.data), len(serialized))
+	}
+}
+
+func TestSerialization_StateStreamContainsAllWrittenStates(t *testing.T) {
+	states := []*State{}
+	for i := range 10 {
+		state := getNewFilledState()
+		state.Pc = uint16(i)
+		state.Stack = NewStack(NewU256(uint64(i)))
+		states = append(states, state)
+	}
+
+	var buffer bytes.Buffer
+	writer, err := NewStateWriter(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, state := range states {
+		if err := writer.Write(state); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := NewStateReader(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range states {
+		got, err := reader.Read()
+		if err != nil {
+			t.Fatalf("failed to read state %d: %v", i, err)
+		}
+		if !want.Eq(got) {
+			t.Errorf("unexpected state %d: %v", i, want.Diff(got))
+		}
+	}
+	if _, err := reader.Read(); err != io.EOF {
+		t.Errorf("expected end of stream, got %v", err)
+	}
+}
+
+func TestSerialization_StateReaderRejectsInvalidHeaders(t *testing.T) {
+	tests := map[string]struct {
+		data []byte
+		want string
+	}{
+		"empty": {
+			data: []byte{},
+			want: "not a binary state stream",
+		},
+		"wrong magic": {
+			data: []byte("{\"Status\": 1}"),
+			want: "not a binary state stream",
+		},
+		"missing version": {
+			data: []byte(binaryMagic),
+			want: "failed to read version",
+		},
+		"unknown version": {
+			data: binary.AppendUvarint([]byte(binaryMagic), binaryVersion+1),
+			want: "unsupported version",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewStateReader(bytes.NewReader(test.data))
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("unexpected error, wanted %q, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestSerialization_StateReaderDecodesStatesOfVersion1(t *testing.T) {
+	state := getNewFilledState()
+	state.ReturnStack = nil
+	encoder := binaryEncoder{}
+	encoder.state(state)
+
+	// Version 1 lacks the length of the return stack at the end of a state.
+	record := encoder.data[:len(encoder.data)-1]
+	data := binary.AppendUvarint([]byte(binaryMagic), 1)
+	data = binary.AppendUvarint(data, uint64(len(record)))
+	data = append(data, record...)
+
+	reader, err := NewStateReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, err := reader.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.Eq(restored) {
+		t.Errorf("unexpected state: %v", state.Diff(restored))
+	}
+}
+
+func TestSerialization_StateReaderRejectsCorruptedStates(t *testing.T) {
+	encoder := binaryEncoder{}
+	encoder.state(getNewFilledState())
+	encoded := encoder.data
+
+	read := func(record []byte) error {
+		data := binary.AppendUvarint([]byte(binaryMagic), binaryVersion)
+		data = append(data, record...)
+		reader, err := NewStateReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = reader.Read()
+		return err
+	}
+
+	for size := range len(encoded) {
+		record := binary.AppendUvarint(nil, uint64(size))
+		record = append(record, encoded[:size]...)
+		if err := read(record); err == nil {
+			t.Errorf("truncated state of size %d was accepted", size)
+		}
+	}
+
+	record := binary.AppendUvarint(nil, uint64(len(encoded)+1))
+	record = append(record, encoded...)
+	record = append(record, 0)
+	if err := read(record); err == nil || !strings.Contains(err.Error(), "trailing bytes") {
+		t.Errorf("expected error about trailing bytes, got %v", err)
+	}
+
+	record = binary.AppendUvarint(nil, uint64(len(encoded)))
+	if err := read(record); err == nil {
+		t.Errorf("missing state data was accepted")
+	}
+}
+
+func TestSerialization_ImportStateBinaryRequiresSingleState(t *testing.T) {
+	for _, count := range []int{0, 2} {
+		var buffer bytes.Buffer
+		writer, err := NewStateWriter(&buffer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for range count {
+			if err := writer.Write(getNewFilledState()); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := writer.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		testFilePath := path.Join(t.TempDir(), "states.bin")
+		if err := os.WriteFile(testFilePath, buffer.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ImportStateBinary(testFilePath); err == nil {
+			t.Errorf("file with %d states was accepted", count)
+		}
+	}
+}
+
+func TestSerialization_ImportStateDetectsFormat(t *testing.T) {
+	state := getNewFilledState()
+	dir := t.TempDir()
+	jsonPath := path.Join(dir, "state.json")
+	binaryPath := path.Join(dir, "state.bin")
+	if err := ExportStateJSON(state, jsonPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := ExportStateBinary(state, binaryPath); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range []string{jsonPath, binaryPath} {
+		restored, err := ImportState(file)
+		if err != nil {
+			t.Fatalf("failed to import %v: %v", file, err)
+		}
+		if !state.Eq(restored) {
+			t.Errorf("unexpected state imported from %v: %v", file, state.Diff(restored))
+		}
+	}
+}
+
+func TestSerialization_ImportStatesReadsAllStatesOfFile(t *testing.T) {
+	states := []*State{}
+	for i := range 3 {
+		state := getNewFilledState()
+		state.Pc = uint16(i)
+		states = append(states, state)
+	}
+
+	dir := t.TempDir()
+	jsonPath := path.Join(dir, "state.json")
+	if err := ExportStateJSON(states[0], jsonPath); err != nil {
+		t.Fatal(err)
+	}
+	var buffer bytes.Buffer
+	writer, err := NewStateWriter(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, state := range states {
+		if err := writer.Write(state); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	streamPath := path.Join(dir, "states.bin")
+	if err := os.WriteFile(streamPath, buffer.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		file string
+		want []*State
+	}{
+		"json":   {file: jsonPath, want: states[:1]},
+		"stream": {file: streamPath, want: states},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := []*State{}
+			err := ImportStates(test.file, func(state *State) error {
+				got = append(got, state)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("unexpected number of states, wanted %d, got %d", len(test.want), len(got))
+			}
+			for i, want := range test.want {
+				if !want.Eq(got[i]) {
+					t.Errorf("unexpected state %d: %v", i, want.Diff(got[i]))
+				}
+			}
+		})
+	}
+}
+
+func TestSerialization_ImportStatesStopsOnConsumerError(t *testing.T) {
+	var buffer bytes.Buffer
+	writer, err := NewStateWriter(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		if err := writer.Write(getNewFilledState()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	streamPath := path.Join(t.TempDir(), "states.bin")
+	if err := os.WriteFile(streamPath, buffer.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	injected := fmt.Errorf("injected error")
+	calls := 0
+	err = ImportStates(streamPath, func(*State) error {
+		calls++
+		return injected
+	})
+	if err != injected {
+		t.Errorf("unexpected error, wanted %v, got %v", injected, err)
+	}
+	if calls != 1 {
+		t.Errorf("consumer should be called once, got %d calls", calls)
+	}
+}
+
+func TestSerialization_ImportStateJSONChecksVersion(t *testing.T) {
+	tests := map[string]struct {
+		data string
+		want string
+	}{
+		"unversioned": {
+			data: `{"Status": "stopped", "Revision": "London", "Pc": 3}`,
+		},
+		"version 1": {
+			data: `{"Version": 1, "Status": "stopped", "Revision": "London", "Pc": 3}`,
+		},
+		"current version": {
+			data: `{"Version": 2, "Status": "stopped", "Revision": "London", "Pc": 3, "ReturnStack": [5]}`,
+		},
+		"future version": {
+			data: `{"Version": 3, "Status": "stopped", "Revision": "London", "Pc": 3}`,
+			want: "unsupported version",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			testFilePath := path.Join(t.TempDir(), testFileName)
+			if err := os.WriteFile(testFilePath, []byte(test.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			state, err := ImportStateJSON(testFilePath)
+			if test.want != "" {
+				if err == nil || !strings.Contains(err.Error(), test.want) {
+					t.Errorf("unexpected error, wanted %q, got %v", test.want, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if state.Status != Stopped || state.Revision != tosca.R10_London || state.Pc != 3 {
+				t.Errorf("unexpected state: %v", state)
+			}
+		})
+	}
+}
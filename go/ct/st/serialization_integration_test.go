@@ -11,6 +11,8 @@
 package st_test
 
 import (
+	"bytes"
+	"io"
 	"path/filepath"
 	"testing"
 
@@ -50,6 +52,52 @@ func TestSerialization_EndToEndTest(t *testing.T) {
 	}
 }
 
+func TestSerialization_BinaryStreamEndToEndTest(t *testing.T) {
+	const N = 100
+	rnd := rand.New(0)
+	gen := gen.NewStateGenerator()
+
+	var buffer bytes.Buffer
+	writer, err := st.NewStateWriter(&buffer)
+	if err != nil {
+		t.Fatalf("failed to create writer: %v", err)
+	}
+	states := []*st.State{}
+	for range N {
+		state, err := gen.Generate(rnd)
+		if err != nil {
+			t.Fatalf("failed to generate random state: %v", err)
+		}
+		if err := writer.Write(state); err != nil {
+			t.Fatalf("failed to write state: %v", err)
+		}
+		states = append(states, state)
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("failed to flush states: %v", err)
+	}
+
+	reader, err := st.NewStateReader(&buffer)
+	if err != nil {
+		t.Fatalf("failed to create reader: %v", err)
+	}
+	for _, state := range states {
+		restored, err := reader.Read()
+		if err != nil {
+			t.Fatalf("failed to read state: %v", err)
+		}
+		if !state.Eq(restored) {
+			t.Errorf("failed to restore state\nwanted: %v\ngot: %v\n", state, restored)
+			for _, cur := range state.Diff(restored) {
+				t.Errorf("%s\n", cur)
+			}
+		}
+	}
+	if _, err := reader.Read(); err != io.EOF {
+		t.Errorf("expected end of stream, got %v", err)
+	}
+}
+
 func BenchmarkSerliazation_EndToEnd(b *testing.B) {
 	rnd := rand.New(0)
 	gen := gen.NewStateGenerator()
@@ -49,6 +49,10 @@ import (
 // is far beyond any real-world budget but acceptable for a test environment.
 const MaxGasUsedByCt = 500_000_000_000
 
+// MaxReturnStackSize is the maximum number of entries of the return stack of
+// EOF code, limited by the number of nested CALLF instructions (EIP-4750).
+const MaxReturnStackSize = 1024
+
 // MaxDataSize is the maximum length of the call data vector generated for a test state. While
 // the maximum size is not limited in a real-world setup, larger inputs are not expected to trigger
 // additional issues in EVM implementations (with the exception of resource issues). Thus, this
@@ -136,6 +140,10 @@ type Releaser interface {
 ////////////////////////////////////////////////////////////
 
 // State represents an EVM's execution state.
+//
+// TODO: model a stack of call frames sharing the world state, such that the
+// execution inside callees and the propagation of reverts can be verified;
+// nested calls are currently abstracted by the CallJournal.
 type State struct {
 	Status                StatusCode
 	Revision              tosca.Revision
@@ -161,6 +169,7 @@ type State struct {
 	SelfDestructedJournal []SelfDestructEntry
 	RecentBlockHashes     ImmutableHashArray
 	TransactionContext    *TransactionContext
+	ReturnStack           []uint16 // < the positions to continue at after the RETF instructions of EOF code
 }
 
 // NewState creates a new State instance with the given code.
@@ -216,6 +225,7 @@ func (s *State) Clone() *State {
 	clone.SelfDestructedJournal = slices.Clone(s.SelfDestructedJournal)
 	clone.RecentBlockHashes = s.RecentBlockHashes
 	clone.TransactionContext = s.TransactionContext.Clone()
+	clone.ReturnStack = slices.Clone(s.ReturnStack)
 	return clone
 }
 
@@ -270,7 +280,8 @@ func (s *State) Eq(other *State) bool {
 		equivalentPc &&
 		s.Stack.Eq(other.Stack) &&
 		s.Memory.Eq(other.Memory) &&
-		s.LastCallReturnData == other.LastCallReturnData
+		s.LastCallReturnData == other.LastCallReturnData &&
+		slices.Equal(s.ReturnStack, other.ReturnStack)
 }
 
 const dataCutoffLength = 20
@@ -309,6 +320,9 @@ func (s *State) String() string {
 	if s.Stack.Size() > stackCutOffLength {
 		write("\t    ...\n")
 	}
+	if len(s.ReturnStack) > 0 {
+		write("\tReturn stack: %v\n", s.ReturnStack)
+	}
 	write("\tMemory size: %d\n", s.Memory.Size())
 	write("\tStorage.Current:\n")
 	for k, v := range s.Storage.current {
@@ -440,6 +454,10 @@ func (s *State) Diff(o *State) []string {
 		res = append(res, s.Memory.Diff(o.Memory)...)
 	}
 
+	if !slices.Equal(s.ReturnStack, o.ReturnStack) {
+		res = append(res, fmt.Sprintf("Different return stack: %v vs %v", s.ReturnStack, o.ReturnStack))
+	}
+
 	if !s.Storage.Eq(o.Storage) {
 		res = append(res, s.Storage.Diff(o.Storage)...)
 	}
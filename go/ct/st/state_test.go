@@ -57,6 +57,7 @@ func getNewFilledState() *State {
 	s.HasSelfDestructed = true
 	s.SelfDestructedJournal = []SelfDestructEntry{{tosca.Address{1}, tosca.Address{2}}}
 	s.RecentBlockHashes = NewImmutableHashArray(tosca.Hash{0x01})
+	s.ReturnStack = []uint16{7}
 	return s
 }
 
@@ -167,6 +168,11 @@ func getTestChanges() map[string]testStruct {
 		},
 			"Different has-self-destructed journal entry",
 		},
+		"return_stack": {func(state *State) {
+			state.ReturnStack = append(state.ReturnStack, 12)
+		},
+			"Different return stack",
+		},
 		"block_number_hashes": {func(state *State) {
 			state.RecentBlockHashes = NewImmutableHashArray(tosca.Hash{0x02})
 		},
@@ -825,6 +831,10 @@ func TestState_EqualityConsidersRelevantFieldsDependingOnStatus(t *testing.T) {
 			modify:      func(s *State) { s.RecentBlockHashes = NewImmutableHashArray(tosca.Hash{0xf2}) },
 			relevantFor: allButFailed,
 		},
+		"return_stack": {
+			modify:      func(s *State) { s.ReturnStack = append(s.ReturnStack, 1) },
+			relevantFor: onlyRunning,
+		},
 	}
 
 	code := NewCode([]byte{1, 2, 3})
@@ -12,6 +12,7 @@ package st
 
 import (
 	"fmt"
+	"sort"
 
 	"golang.org/x/exp/maps"
 
@@ -130,6 +131,30 @@ func (s *Storage) MarkCold(key U256) {
 	delete(s.warm, key)
 }
 
+// GetKeys returns the keys of all slots with a current or original value or
+// a warm status in ascending order.
+func (s *Storage) GetKeys() []U256 {
+	keys := map[U256]struct{}{}
+	for _, source := range []map[U256]U256{s.current, s.original} {
+		for key := range source {
+			keys[key] = struct{}{}
+		}
+	}
+	for key := range s.warm {
+		keys[key] = struct{}{}
+	}
+	return sortedKeys(keys)
+}
+
+func sortedKeys[V any](m map[U256]V) []U256 {
+	res := make([]U256, 0, len(m))
+	for key := range m {
+		res = append(res, key)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Lt(res[j]) })
+	return res
+}
+
 func (s *Storage) Clone() *Storage {
 	return &Storage{
 		current:  s.current,
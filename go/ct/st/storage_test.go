@@ -11,6 +11,7 @@
 package st
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 
@@ -204,3 +205,17 @@ func BenchmarkStorage_CloneModified(b *testing.B) {
 	}
 
 }
+
+func TestStorage_GetKeysReturnsAllKnownKeysInOrder(t *testing.T) {
+	s := NewStorageBuilder().
+		SetCurrent(NewU256(42), NewU256(1)).
+		SetOriginal(NewU256(7), NewU256(2)).
+		SetOriginal(NewU256(42), NewU256(3)).
+		SetWarm(NewU256(1, 0), true).
+		Build()
+
+	want := []U256{NewU256(7), NewU256(42), NewU256(1, 0)}
+	if got := s.GetKeys(); !reflect.DeepEqual(want, got) {
+		t.Errorf("unexpected keys, wanted %v, got %v", want, got)
+	}
+}
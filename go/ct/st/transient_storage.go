@@ -39,6 +39,11 @@ func (t *TransientStorage) IsZero(key U256) bool {
 	return t.storage[key].IsZero()
 }
 
+// GetKeys returns the keys of all non-zero entries in ascending order.
+func (t *TransientStorage) GetKeys() []U256 {
+	return sortedKeys(t.storage)
+}
+
 func (t *TransientStorage) Clone() *TransientStorage {
 	return &TransientStorage{maps.Clone(t.storage)}
 }
@@ -106,3 +106,15 @@ func TestTransient_EqAndDiffAreCompatible(t *testing.T) {
 		})
 	}
 }
+
+func TestTransient_GetKeysReturnsNonZeroKeysInOrder(t *testing.T) {
+	transient := &TransientStorage{}
+	transient.Set(NewU256(42), NewU256(1))
+	transient.Set(NewU256(7), NewU256(2))
+	transient.Set(NewU256(13), NewU256(0))
+
+	want := []U256{NewU256(7), NewU256(42)}
+	if got := transient.GetKeys(); !reflect.DeepEqual(want, got) {
+		t.Errorf("unexpected keys, wanted %v, got %v", want, got)
+	}
+}
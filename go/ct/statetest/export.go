@@ -0,0 +1,453 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package statetest
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+
+	. "github.com/0xsoniclabs/tosca/go/ct/common"
+	"github.com/0xsoniclabs/tosca/go/ct/spc"
+	"github.com/0xsoniclabs/tosca/go/ct/st"
+	"github.com/0xsoniclabs/tosca/go/processor/precompile"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// senderKey is the private key of the account sending the transactions of
+	// exported tests. It is the key used throughout the ethereum/tests.
+	senderKey = "45a915e4d060149eb4365960e6a7a45f334393093061116b197e3240065ff2d8"
+
+	// maxTransactionGas is the cap of transaction gas limits introduced by
+	// EIP-7825 in Osaka.
+	maxTransactionGas = 1 << 24
+
+	// blobGasPerBlob is the blob gas consumed by each blob hash of a
+	// transaction, see EIP-4844.
+	blobGasPerBlob = 1 << 17
+
+	// maxBlobsPerTransaction is the number of blob hashes accepted in a
+	// transaction by all supported revisions.
+	maxBlobsPerTransaction = 6
+
+	// maxObservedStackElements is the number of elements of the top of the
+	// stack logged by the epilogue. It covers all elements accessible by
+	// DUP and SWAP instructions.
+	maxObservedStackElements = 17
+
+	// probeGas is the gas used to measure the costs of the prologue of a
+	// program re-establishing a state.
+	probeGas = tosca.Gas(1 << 40)
+)
+
+var (
+	senderPrivateKey = func() *ecdsa.PrivateKey {
+		key, err := crypto.HexToECDSA(senderKey)
+		if err != nil {
+			panic(err)
+		}
+		return key
+	}()
+	sender = tosca.Address(crypto.PubkeyToAddress(senderPrivateKey.PublicKey))
+
+	precompiles = precompile.NewStandardRegistry()
+)
+
+// unsupportedOperations are instructions whose effects on other accounts are
+// not modeled by the specification, such that no post state can be derived,
+// and instructions depending on the code of the state, which is replaced by a
+// program placing the current instruction at a different offset.
+var unsupportedOperations = []vm.OpCode{
+	vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL,
+	vm.CREATE, vm.CREATE2, vm.SELFDESTRUCT,
+	vm.JUMP, vm.JUMPI, vm.PC, vm.CODESIZE, vm.CODECOPY,
+}
+
+// extCodeOperations are instructions observing the code of the account on
+// top of the stack, which is only supported for accounts other than the
+// contract of the state.
+var extCodeOperations = []vm.OpCode{
+	vm.EXTCODESIZE, vm.EXTCODECOPY, vm.EXTCODEHASH,
+}
+
+// Export converts the given state into a state test fixture. The contract of
+// the state is replaced by a program re-establishing the state's storage,
+// warm accounts, transient storage, memory, and stack before executing the
+// state's current instruction with the state's gas. If the remaining gas
+// suffices, the instruction is followed by an epilogue logging the top of
+// the stack and the memory to make the instruction's results observable.
+// The expected post state is derived by running this program on the
+// specification. Parts of the state that can not be set up by a transaction
+// are normalized, e.g., the origin and caller become the sender of the
+// transaction. States that can not be reproduced this way are rejected with
+// an error.
+func Export(state *st.State) (*Fixture, error) {
+	if err := checkExportable(state); err != nil {
+		return nil, err
+	}
+
+	program, offset, size := newProgram(state)
+	start := newStartState(state, program)
+
+	// Measure the costs of the prologue by running it with plenty of gas.
+	probe := start.Clone()
+	probe.Gas = probeGas
+	if err := simulate(probe, offset); err != nil {
+		return nil, err
+	}
+	if probe.Status != st.Running {
+		return nil, fmt.Errorf("failed to re-establish state, prologue ended with status %v", probe.Status)
+	}
+	start.Gas = state.Gas + probeGas - probe.Gas
+	if start.Gas < state.Gas {
+		return nil, fmt.Errorf("gas of state too high: %d", state.Gas)
+	}
+
+	intrinsicGas, floorGas := getIntrinsicGas(state.Revision, state.CallData.ToBytes())
+	gasLimit := intrinsicGas + uint64(start.Gas)
+	if state.Revision >= tosca.R15_Osaka && gasLimit > maxTransactionGas {
+		return nil, fmt.Errorf("transaction gas limit %d exceeds cap of %d", gasLimit, maxTransactionGas)
+	}
+	if gasLimit < floorGas {
+		return nil, fmt.Errorf("transaction gas limit %d is below the calldata floor of %d", gasLimit, floorGas)
+	}
+	start.BlockContext.GasLimit = max(start.BlockContext.GasLimit, gasLimit)
+
+	end := start.Clone()
+	if err := simulate(end, offset); err != nil {
+		return nil, err
+	}
+	if end.Status != st.Running || end.Gas != state.Gas {
+		return nil, fmt.Errorf("failed to re-establish state, got status %v and gas %d, wanted %v and %d",
+			end.Status, end.Gas, st.Running, state.Gas)
+	}
+	if err := simulate(end, offset+1); err != nil {
+		return nil, err
+	}
+
+	// The epilogue is only added if the instruction continues with the next
+	// one and the epilogue can be completed. Otherwise, the instruction is
+	// the last one of the program.
+	if end.Status == st.Running && int(end.Pc) == offset+size {
+		observed := slices.Concat(
+			program,
+			make([]byte, offset+size-len(program)),
+			newEpilogue(min(end.Stack.Size(), maxObservedStackElements)),
+		)
+		candidate := newStartState(state, observed)
+		candidate.Gas = start.Gas
+		candidate.BlockContext.GasLimit = start.BlockContext.GasLimit
+		result := candidate.Clone()
+		if err := simulate(result, math.MaxInt); err != nil {
+			return nil, err
+		}
+		if result.Status == st.Stopped {
+			return newFixture(candidate, result, gasLimit, floorGas)
+		}
+	}
+
+	if err := simulate(end, math.MaxInt); err != nil {
+		return nil, err
+	}
+	return newFixture(start, end, gasLimit, floorGas)
+}
+
+func checkExportable(state *st.State) error {
+	if state.Status != st.Running {
+		return fmt.Errorf("only running states can be exported, got status %v", state.Status)
+	}
+	if state.Revision > tosca.R15_Osaka {
+		return fmt.Errorf("unsupported revision %v", state.Revision)
+	}
+	if state.ReadOnly {
+		return fmt.Errorf("read-only states can not be reproduced by a transaction")
+	}
+	pc := int(state.Pc)
+	if pc < state.Code.Length() {
+		op, err := state.Code.GetOperation(pc)
+		if err != nil {
+			return fmt.Errorf("program counter does not point to an instruction: %w", err)
+		}
+		if slices.Contains(unsupportedOperations, op) {
+			return fmt.Errorf("unsupported instruction %v", op)
+		}
+		if slices.Contains(extCodeOperations, op) && state.Stack.Size() > 0 &&
+			state.Stack.Get(0).Bytes20be() == state.CallContext.AccountAddress {
+			return fmt.Errorf("unsupported instruction %v on the code of the contract", op)
+		}
+	}
+	contract := state.CallContext.AccountAddress
+	if contract == sender {
+		return fmt.Errorf("account address %v is reserved for the sender", contract)
+	}
+	if slices.Contains(precompiles.Addresses(state.Revision), contract) {
+		return fmt.Errorf("account address %v is a precompiled contract", contract)
+	}
+	if state.Accounts.GetCode(sender).Length() > 0 {
+		return fmt.Errorf("sender account %v must not have code", sender)
+	}
+	if state.Memory.Size()%32 != 0 {
+		return fmt.Errorf("memory size %d is not a multiple of 32", state.Memory.Size())
+	}
+	return nil
+}
+
+// newProgram produces the code of the contract of an exported test. Its
+// prologue re-establishes the dynamic parts of the given state and is
+// followed by the state's current instruction. The offset and the size of
+// this instruction are returned alongside the program. The size includes
+// data of truncated PUSH instructions missing in the program.
+func newProgram(state *st.State) ([]byte, int, int) {
+	code := []byte{}
+	push32 := func(value U256) {
+		word := value.Bytes32be()
+		code = append(code, byte(vm.PUSH32))
+		code = append(code, word[:]...)
+	}
+
+	// Modified storage slots are set first, while there is enough gas left
+	// to satisfy the minimum gas required by SSTORE.
+	keys := state.Storage.GetKeys()
+	for _, key := range keys {
+		current := state.Storage.GetCurrent(key)
+		if current != state.Storage.GetOriginal(key) {
+			push32(current)
+			push32(key)
+			code = append(code, byte(vm.SSTORE))
+		}
+	}
+
+	if state.Revision >= tosca.R09_Berlin {
+		for _, key := range keys {
+			if state.Storage.IsWarm(key) && state.Storage.GetCurrent(key) == state.Storage.GetOriginal(key) {
+				push32(key)
+				code = append(code, byte(vm.SLOAD), byte(vm.POP))
+			}
+		}
+		warm := getInitiallyWarmAddresses(state)
+		for _, address := range state.Accounts.GetWarmAddresses() {
+			if !slices.Contains(warm, address) {
+				code = append(code, byte(vm.PUSH20))
+				code = append(code, address[:]...)
+				code = append(code, byte(vm.BALANCE), byte(vm.POP))
+			}
+		}
+	}
+
+	// Transient storage can not be observed before Cancun.
+	if state.Revision >= tosca.R13_Cancun {
+		for _, key := range state.TransientStorage.GetKeys() {
+			push32(state.TransientStorage.Get(key))
+			push32(key)
+			code = append(code, byte(vm.TSTORE))
+		}
+	}
+
+	// Zero words only need to be written to establish the memory size.
+	size := state.Memory.Size()
+	for offset := 0; offset < size; offset += 32 {
+		word := NewU256FromBytes(state.Memory.Read(uint64(offset), 32)...)
+		if !word.IsZero() || offset+32 == size {
+			push32(word)
+			push32(NewU256(uint64(offset)))
+			code = append(code, byte(vm.MSTORE))
+		}
+	}
+
+	for i := state.Stack.Size() - 1; i >= 0; i-- {
+		push32(state.Stack.Get(i))
+	}
+
+	offset, size := len(code), 0
+	original := state.Code.Copy()
+	if pc := int(state.Pc); pc < len(original) {
+		size = 1
+		if op := vm.OpCode(original[pc]); vm.PUSH1 <= op && op <= vm.PUSH32 {
+			size += int(op-vm.PUSH1) + 1
+		}
+		code = append(code, original[pc:min(pc+size, len(original))]...)
+	}
+	return code, offset, size
+}
+
+// newEpilogue produces code appending the given number of elements of the
+// top of the stack to the memory and logging the full memory afterwards.
+func newEpilogue(elements int) []byte {
+	code := []byte{}
+	for range elements {
+		code = append(code, byte(vm.MSIZE), byte(vm.MSTORE))
+	}
+	return append(code, byte(vm.MSIZE), byte(vm.PUSH1), 0, byte(vm.LOG0))
+}
+
+// newStartState creates the state at the beginning of the execution of the
+// given program by the exported test's transaction.
+func newStartState(state *st.State, program []byte) *st.State {
+	revision := state.Revision
+	contract := state.CallContext.AccountAddress
+	value := state.CallContext.Value
+
+	res := st.NewState(st.NewCode(program))
+	res.Revision = revision
+	res.CallContext = st.CallContext{
+		AccountAddress: contract,
+		CallerAddress:  sender,
+		Value:          value,
+	}
+	res.CallData = state.CallData
+	res.BlockContext = getBlockContext(state)
+	res.RecentBlockHashes = getRecentBlockHashes(res.BlockContext.BlockNumber)
+	res.TransactionContext = &st.TransactionContext{
+		OriginAddress: sender,
+		BlobHashes:    getBlobHashes(state),
+	}
+
+	storage := st.NewStorageBuilder()
+	for _, key := range state.Storage.GetKeys() {
+		if original := state.Storage.GetOriginal(key); !original.IsZero() {
+			storage.SetOriginal(key, original)
+			storage.SetCurrent(key, original)
+		}
+	}
+	res.Storage = storage.Build()
+
+	accounts := st.NewAccountsBuilder()
+	for _, address := range state.Accounts.GetAddresses() {
+		if address == contract || state.Accounts.IsEmpty(address) {
+			continue
+		}
+		accounts.SetBalance(address, state.Accounts.GetBalance(address))
+		accounts.SetCode(address, state.Accounts.GetCode(address))
+	}
+
+	// The sender has a non-zero nonce during the execution and is thus never
+	// empty. A non-zero balance makes the specification agree with that.
+	senderBalance := state.Accounts.GetBalance(sender)
+	if senderBalance.IsZero() {
+		senderBalance = NewU256(1)
+	}
+	accounts.SetBalance(sender, senderBalance)
+
+	// The contract receives the value before its code is executed.
+	contractBalance := state.Accounts.GetBalance(contract)
+	if contractBalance.Lt(value) {
+		contractBalance = value
+	}
+	accounts.SetBalance(contract, contractBalance)
+	accounts.SetCode(contract, NewBytes(program))
+
+	if revision >= tosca.R09_Berlin {
+		for _, address := range getInitiallyWarmAddresses(state) {
+			accounts.SetWarm(address)
+		}
+	}
+	res.Accounts = accounts.Build()
+	return res
+}
+
+// getInitiallyWarmAddresses lists the accounts being warm at the beginning
+// of the execution of an exported test's transaction, see EIP-2929.
+func getInitiallyWarmAddresses(state *st.State) []tosca.Address {
+	res := []tosca.Address{sender, state.CallContext.AccountAddress}
+	res = append(res, precompiles.Addresses(state.Revision)...)
+	if state.Revision >= tosca.R12_Shanghai {
+		res = append(res, state.BlockContext.CoinBase)
+	}
+	return res
+}
+
+func getBlockContext(state *st.State) st.BlockContext {
+	res := state.BlockContext
+	res.ChainID = NewU256(1)
+	if state.Revision < tosca.R10_London {
+		res.BaseFee = NewU256()
+	}
+	// Prices are limited such that fees can not exceed the range of balances.
+	maxPrice := NewU256(math.MaxUint64)
+	if maxPrice.Lt(res.BaseFee) {
+		res.BaseFee = maxPrice
+	}
+	if maxPrice.Lt(res.GasPrice) {
+		res.GasPrice = maxPrice
+	}
+	if res.GasPrice.Lt(res.BaseFee) {
+		res.GasPrice = res.BaseFee
+	}
+	// Without excess blob gas, the blob base fee is the minimum of 1 wei.
+	res.BlobBaseFee = NewU256()
+	if state.Revision >= tosca.R13_Cancun {
+		res.BlobBaseFee = NewU256(1)
+	}
+	return res
+}
+
+// getBlobHashes returns the blob hashes of the given state, trimmed to the
+// number accepted in a transaction and marked as KZG commitment hashes.
+func getBlobHashes(state *st.State) []tosca.Hash {
+	if state.Revision < tosca.R13_Cancun || state.TransactionContext == nil {
+		return nil
+	}
+	hashes := state.TransactionContext.BlobHashes
+	res := slices.Clone(hashes[:min(len(hashes), maxBlobsPerTransaction)])
+	for i := range res {
+		res[i][0] = 0x01
+	}
+	return res
+}
+
+// getRecentBlockHashes returns the hashes of the blocks preceding the given
+// block as provided by the state test harnesses of Ethereum clients.
+func getRecentBlockHashes(number uint64) ImmutableHashArray {
+	hashes := []tosca.Hash{}
+	for i := uint64(0); i < 256 && i < number; i++ {
+		hashes = append(hashes, getBlockHash(number-1-i))
+	}
+	return NewImmutableHashArray(hashes...)
+}
+
+func getBlockHash(number uint64) tosca.Hash {
+	return tosca.Hash(crypto.Keccak256Hash([]byte(big.NewInt(int64(number)).String())))
+}
+
+// getIntrinsicGas returns the intrinsic gas of a transaction with the given
+// call data as well as the minimum gas it is charged for, see EIP-7623.
+func getIntrinsicGas(revision tosca.Revision, data []byte) (intrinsic, floor uint64) {
+	zeros := uint64(0)
+	for _, b := range data {
+		if b == 0 {
+			zeros++
+		}
+	}
+	nonZeros := uint64(len(data)) - zeros
+	intrinsic = 21_000 + 4*zeros + 16*nonZeros
+	if revision >= tosca.R14_Prague {
+		floor = 21_000 + 10*(zeros+4*nonZeros)
+	}
+	return intrinsic, floor
+}
+
+// simulate applies the rules of the specification to the given state until
+// it is no longer running or reaches the given program counter.
+func simulate(state *st.State, end int) error {
+	for state.Status == st.Running && int(state.Pc) < end {
+		rules := spc.Spec.GetRulesFor(state)
+		if len(rules) == 0 {
+			return fmt.Errorf("no rule applies to state at pc %d", state.Pc)
+		}
+		rules[0].Effect.Apply(state)
+	}
+	return nil
+}
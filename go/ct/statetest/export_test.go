@@ -0,0 +1,348 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package statetest
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/0xsoniclabs/tosca/go/ct/common"
+	"github.com/0xsoniclabs/tosca/go/ct/rlz"
+	"github.com/0xsoniclabs/tosca/go/ct/spc"
+	"github.com/0xsoniclabs/tosca/go/ct/st"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	geth_vm "github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/tests"
+	"pgregory.net/rand"
+)
+
+func TestExport_SenderIsAccountOfKnownKey(t *testing.T) {
+	want := tosca.Address{
+		0xa9, 0x4f, 0x53, 0x74, 0xfc, 0xe5, 0xed, 0xbc, 0x8e, 0x2a,
+		0x86, 0x97, 0xc1, 0x53, 0x31, 0x67, 0x7e, 0x6e, 0xbf, 0x0b,
+	}
+	if sender != want {
+		t.Errorf("unexpected sender, wanted %v, got %v", want, sender)
+	}
+}
+
+func TestExport_ExportedStatesPassInGeth(t *testing.T) {
+	tests := map[string]func(*st.State){
+		"add": func(s *st.State) {
+			s.Code = st.NewCode([]byte{byte(vm.ADD)})
+			s.Stack = st.NewStack(NewU256(1), NewU256(2))
+		},
+		"stop": func(s *st.State) {
+			s.Code = st.NewCode([]byte{byte(vm.STOP)})
+		},
+		"end_of_code": func(s *st.State) {
+			s.Code = st.NewCode([]byte{byte(vm.ADD)})
+			s.Pc = 1
+		},
+		"truncated_push": func(s *st.State) {
+			s.Code = st.NewCode([]byte{byte(vm.PUSH4), 1, 2})
+		},
+		"sstore_with_refund": func(s *st.State) {
+			s.Code = st.NewCode([]byte{byte(vm.SSTORE)})
+			s.Stack = st.NewStack(NewU256(0), NewU256(1))
+			s.Storage = st.NewStorageBuilder().
+				SetOriginal(NewU256(1), NewU256(5)).
+				SetCurrent(NewU256(1), NewU256(5)).
+				SetOriginal(NewU256(2), NewU256(6)).
+				SetCurrent(NewU256(2), NewU256(7)).
+				SetWarm(NewU256(3), true).
+				Build()
+		},
+		"log": func(s *st.State) {
+			s.Code = st.NewCode([]byte{byte(vm.LOG1)})
+			s.Stack = st.NewStack(NewU256(42), NewU256(32), NewU256(16))
+			s.Memory = st.NewMemory(make([]byte, 64)...)
+			s.Memory.Write([]byte{1, 2, 3}, 20)
+		},
+		"revert": func(s *st.State) {
+			s.Code = st.NewCode([]byte{byte(vm.REVERT)})
+			s.Stack = st.NewStack(NewU256(0), NewU256(0))
+			s.Storage = st.NewStorageBuilder().
+				SetOriginal(NewU256(1), NewU256(5)).
+				SetCurrent(NewU256(1), NewU256(6)).
+				Build()
+		},
+		"failure": func(s *st.State) {
+			s.Code = st.NewCode([]byte{byte(vm.INVALID)})
+			s.CallContext.Value = NewU256(1000)
+		},
+		"out_of_gas": func(s *st.State) {
+			s.Code = st.NewCode([]byte{byte(vm.SLOAD)})
+			s.Stack = st.NewStack(NewU256(1))
+			s.Gas = 50
+		},
+		"balance_of_warm_account": func(s *st.State) {
+			s.Code = st.NewCode([]byte{byte(vm.BALANCE)})
+			s.Stack = st.NewStack(NewU256FromBytes(0x42))
+			s.Accounts = st.NewAccountsBuilder().
+				SetBalance(tosca.Address{19: 0x42}, NewU256(7)).
+				SetWarm(tosca.Address{19: 0x42}).
+				SetWarm(tosca.Address{19: 0x43}).
+				Build()
+		},
+		"blockhash": func(s *st.State) {
+			s.Code = st.NewCode([]byte{byte(vm.BLOCKHASH)})
+			s.Stack = st.NewStack(NewU256(s.BlockContext.BlockNumber - 3))
+		},
+		"blob_hash": func(s *st.State) {
+			s.Code = st.NewCode([]byte{byte(vm.BLOBHASH)})
+			s.Stack = st.NewStack(NewU256(1))
+			s.TransactionContext.BlobHashes = []tosca.Hash{{1}, {2}}
+		},
+		"transient_storage": func(s *st.State) {
+			s.Code = st.NewCode([]byte{byte(vm.TLOAD)})
+			s.Stack = st.NewStack(NewU256(3))
+			if s.Revision >= tosca.R13_Cancun {
+				s.TransientStorage.Set(NewU256(3), NewU256(4))
+			}
+		},
+		"call_data": func(s *st.State) {
+			s.Code = st.NewCode([]byte{byte(vm.CALLDATALOAD)})
+			s.Stack = st.NewStack(NewU256(1))
+			s.CallData = NewBytes([]byte{0, 1, 2, 3, 0, 0, 4})
+		},
+		"extcodesize_of_other_account": func(s *st.State) {
+			s.Code = st.NewCode([]byte{byte(vm.EXTCODESIZE)})
+			s.Stack = st.NewStack(NewU256FromBytes(0x42))
+			s.Accounts = st.NewAccountsBuilder().
+				SetCode(tosca.Address{19: 0x42}, NewBytes([]byte{1, 2, 3})).
+				Build()
+		},
+	}
+
+	for _, op := range []vm.OpCode{
+		vm.COINBASE, vm.TIMESTAMP, vm.NUMBER, vm.PREVRANDAO, vm.GASLIMIT,
+		vm.CHAINID, vm.GASPRICE, vm.ORIGIN, vm.CALLER, vm.CALLVALUE,
+		vm.SELFBALANCE, vm.BASEFEE, vm.BLOBBASEFEE,
+	} {
+		tests[strings.ToLower(op.String())] = func(s *st.State) {
+			s.Code = st.NewCode([]byte{byte(op)})
+			s.CallContext.Value = NewU256(12)
+		}
+	}
+
+	for name, setup := range tests {
+		for _, revision := range []tosca.Revision{tosca.R07_Istanbul, tosca.R10_London, tosca.R13_Cancun, tosca.R15_Osaka} {
+			t.Run(name+"_"+revision.String(), func(t *testing.T) {
+				state := newTestState(revision)
+				setup(state)
+				fixture, err := Export(state)
+				if err != nil {
+					t.Fatalf("failed to export state: %v", err)
+				}
+				if err := runInGeth(fixture); err != nil {
+					t.Errorf("exported fixture failed: %v", err)
+				}
+			})
+		}
+	}
+}
+
+func TestExport_StatesGeneratedForRulesPassInGeth(t *testing.T) {
+	exported := 0
+	for _, rule := range spc.Spec.GetRules() {
+		// Only a few test cases of each rule are tried to keep the test fast.
+		rnd := rand.New(0)
+		attempts := 0
+		err := rule.EnumerateTestCases(rnd, func(state *st.State) rlz.ConsumerResult {
+			if attempts++; attempts > 16 {
+				return rlz.ConsumeAbort
+			}
+			if match, err := rule.Condition.Check(state); err != nil || !match {
+				return rlz.ConsumeContinue
+			}
+			fixture, err := Export(state)
+			if err != nil {
+				return rlz.ConsumeContinue
+			}
+			exported++
+			if err := runInGeth(fixture); err != nil {
+				t.Errorf("fixture of rule %v failed: %v", rule.Name, err)
+			}
+			return rlz.ConsumeAbort
+		})
+		if err != nil {
+			t.Fatalf("failed to enumerate test cases of rule %v: %v", rule.Name, err)
+		}
+	}
+	t.Logf("exported states of %d rules", exported)
+	if exported == 0 {
+		t.Errorf("no generated state could be exported")
+	}
+}
+
+func TestExport_RegressionInputsPassInGeth(t *testing.T) {
+	files, err := filepath.Glob("../../../regression_inputs/*.json")
+	if err != nil {
+		t.Fatalf("failed to list regression inputs: %v", err)
+	}
+	for _, file := range files {
+		state, err := st.ImportStateJSON(file)
+		if err != nil {
+			t.Fatalf("failed to import %v: %v", file, err)
+		}
+		fixture, err := Export(state)
+		if err != nil {
+			continue
+		}
+		if err := runInGeth(fixture); err != nil {
+			t.Errorf("fixture of %v failed: %v", file, err)
+		}
+	}
+}
+
+func TestExport_UnsupportedStatesAreRejected(t *testing.T) {
+	tests := map[string]struct {
+		setup func(*st.State)
+		want  string
+	}{
+		"not running": {
+			setup: func(s *st.State) { s.Status = st.Stopped },
+			want:  "only running states",
+		},
+		"read only": {
+			setup: func(s *st.State) { s.ReadOnly = true },
+			want:  "read-only",
+		},
+		"unknown revision": {
+			setup: func(s *st.State) { s.Revision = tosca.R98_Experimental },
+			want:  "unsupported revision",
+		},
+		"call": {
+			setup: func(s *st.State) { s.Code = st.NewCode([]byte{byte(vm.CALL)}) },
+			want:  "unsupported instruction",
+		},
+		"self-destruct": {
+			setup: func(s *st.State) { s.Code = st.NewCode([]byte{byte(vm.SELFDESTRUCT)}) },
+			want:  "unsupported instruction",
+		},
+		"jump": {
+			setup: func(s *st.State) {
+				s.Code = st.NewCode([]byte{byte(vm.JUMPDEST), byte(vm.JUMP)})
+				s.Pc = 1
+				s.Stack = st.NewStack(NewU256(0))
+			},
+			want: "unsupported instruction JUMP",
+		},
+		"jumpi": {
+			setup: func(s *st.State) {
+				s.Code = st.NewCode([]byte{byte(vm.JUMPDEST), byte(vm.JUMPI)})
+				s.Pc = 1
+				s.Stack = st.NewStack(NewU256(1), NewU256(0))
+			},
+			want: "unsupported instruction JUMPI",
+		},
+		"pc": {
+			setup: func(s *st.State) {
+				s.Code = st.NewCode([]byte{byte(vm.ADD), byte(vm.PC)})
+				s.Pc = 1
+			},
+			want: "unsupported instruction PC",
+		},
+		"codesize": {
+			setup: func(s *st.State) { s.Code = st.NewCode([]byte{byte(vm.CODESIZE), byte(vm.STOP)}) },
+			want:  "unsupported instruction CODESIZE",
+		},
+		"codecopy": {
+			setup: func(s *st.State) { s.Code = st.NewCode([]byte{byte(vm.CODECOPY)}) },
+			want:  "unsupported instruction CODECOPY",
+		},
+		"extcodesize of contract": {
+			setup: func(s *st.State) {
+				s.Code = st.NewCode([]byte{byte(vm.EXTCODESIZE)})
+				s.Stack = st.NewStack(AddressToU256(s.CallContext.AccountAddress))
+			},
+			want: "on the code of the contract",
+		},
+		"pc on data": {
+			setup: func(s *st.State) {
+				s.Code = st.NewCode([]byte{byte(vm.PUSH1), 1})
+				s.Pc = 1
+			},
+			want: "does not point to an instruction",
+		},
+		"precompile": {
+			setup: func(s *st.State) { s.CallContext.AccountAddress = tosca.Address{19: 1} },
+			want:  "precompiled contract",
+		},
+		"sender": {
+			setup: func(s *st.State) { s.CallContext.AccountAddress = sender },
+			want:  "reserved for the sender",
+		},
+		"too much gas": {
+			setup: func(s *st.State) { s.Gas = 1 << 24 },
+			want:  "exceeds cap",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			state := newTestState(tosca.R15_Osaka)
+			test.setup(state)
+			_, err := Export(state)
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("unexpected error, wanted %q, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func newTestState(revision tosca.Revision) *st.State {
+	state := st.NewState(st.NewCode([]byte{byte(vm.STOP)}))
+	state.Revision = revision
+	state.Gas = 100_000
+	state.CallContext = st.CallContext{
+		AccountAddress: tosca.Address{0x10},
+		CallerAddress:  tosca.Address{0x20},
+	}
+	state.BlockContext = st.BlockContext{
+		BaseFee:     NewU256(10),
+		BlobBaseFee: NewU256(3),
+		BlockNumber: 1000,
+		ChainID:     NewU256(1),
+		CoinBase:    tosca.Address{0x30},
+		GasLimit:    30_000_000,
+		GasPrice:    NewU256(20),
+		PrevRandao:  NewU256(1234),
+		TimeStamp:   1_700_000_000,
+	}
+	return state
+}
+
+// runInGeth runs all post states of the given fixture using the state test
+// harness of geth.
+func runInGeth(fixture *Fixture) error {
+	encoded, err := json.Marshal(map[string]*Fixture{"test": fixture})
+	if err != nil {
+		return err
+	}
+	var stateTests map[string]*tests.StateTest
+	if err := json.Unmarshal(encoded, &stateTests); err != nil {
+		return err
+	}
+	test := stateTests["test"]
+	for _, subtest := range test.Subtests() {
+		err := test.Run(subtest, geth_vm.Config{}, false, rawdb.HashScheme, func(error, *tests.StateTestState) {})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
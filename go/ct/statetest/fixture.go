@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+// Package statetest converts CT states into Ethereum state test fixtures in
+// the format of the GeneralStateTests of the ethereum/tests repository. This
+// way, edge cases found by the CT can be replayed in the test harness of any
+// Ethereum client.
+package statetest
+
+// Fixture is a single filled state test. Numeric values are encoded as
+// 0x-prefixed hex quantities, byte strings as 0x-prefixed hex data.
+type Fixture struct {
+	Env         Environment            `json:"env"`
+	Pre         map[string]Account     `json:"pre"`
+	Transaction Transaction            `json:"transaction"`
+	Post        map[string][]PostState `json:"post"`
+}
+
+// Environment describes the block the transaction of a test is executed in.
+type Environment struct {
+	Coinbase      string `json:"currentCoinbase"`
+	Difficulty    string `json:"currentDifficulty"`
+	Random        string `json:"currentRandom,omitempty"`
+	GasLimit      string `json:"currentGasLimit"`
+	Number        string `json:"currentNumber"`
+	Timestamp     string `json:"currentTimestamp"`
+	BaseFee       string `json:"currentBaseFee,omitempty"`
+	ExcessBlobGas string `json:"currentExcessBlobGas,omitempty"`
+}
+
+// Account is the state of a single account before or after the transaction.
+type Account struct {
+	Balance string            `json:"balance"`
+	Code    string            `json:"code"`
+	Nonce   string            `json:"nonce"`
+	Storage map[string]string `json:"storage"`
+}
+
+// Transaction is the transaction executed by a test. Exported fixtures
+// contain a single variant of data, gas limit, and value.
+type Transaction struct {
+	Data                []string `json:"data"`
+	GasLimit            []string `json:"gasLimit"`
+	GasPrice            string   `json:"gasPrice"`
+	Nonce               string   `json:"nonce"`
+	SecretKey           string   `json:"secretKey"`
+	Sender              string   `json:"sender"`
+	To                  string   `json:"to"`
+	Value               []string `json:"value"`
+	BlobVersionedHashes []string `json:"blobVersionedHashes,omitempty"`
+	MaxFeePerBlobGas    string   `json:"maxFeePerBlobGas,omitempty"`
+}
+
+// PostState is the expected outcome of the transaction for a fork.
+type PostState struct {
+	Hash    string             `json:"hash"`
+	Logs    string             `json:"logs"`
+	TxBytes string             `json:"txbytes"`
+	Indexes Indexes            `json:"indexes"`
+	State   map[string]Account `json:"state"`
+}
+
+// Indexes select the transaction variant a post state belongs to.
+type Indexes struct {
+	Data  int `json:"data"`
+	Gas   int `json:"gas"`
+	Value int `json:"value"`
+}
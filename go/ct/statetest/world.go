@@ -0,0 +1,313 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package statetest
+
+import (
+	"fmt"
+	"maps"
+	"math/big"
+
+	. "github.com/0xsoniclabs/tosca/go/ct/common"
+	"github.com/0xsoniclabs/tosca/go/ct/st"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/holiman/uint256"
+)
+
+// world is the state of all accounts before or after a transaction.
+type world map[tosca.Address]*account
+
+type account struct {
+	balance *big.Int
+	code    []byte
+	nonce   uint64
+	storage map[U256]U256
+}
+
+// newWorld collects the non-empty accounts of the given state. The storage
+// of the state is attributed to the state's contract.
+func newWorld(state *st.State) world {
+	res := world{}
+	for _, address := range state.Accounts.GetAddresses() {
+		if state.Accounts.IsEmpty(address) {
+			continue
+		}
+		res[address] = &account{
+			balance: state.Accounts.GetBalance(address).ToBigInt(),
+			code:    state.Accounts.GetCode(address).ToBytes(),
+			storage: map[U256]U256{},
+		}
+	}
+	storage := res.get(state.CallContext.AccountAddress).storage
+	for _, key := range state.Storage.GetKeys() {
+		if value := state.Storage.GetCurrent(key); !value.IsZero() {
+			storage[key] = value
+		}
+	}
+	return res
+}
+
+// get returns the account of the given address, creating it if needed.
+func (w world) get(address tosca.Address) *account {
+	res, found := w[address]
+	if !found {
+		res = &account{balance: new(big.Int), storage: map[U256]U256{}}
+		w[address] = res
+	}
+	return res
+}
+
+func (w world) addBalance(address tosca.Address, amount *big.Int) {
+	balance := w.get(address).balance
+	balance.Add(balance, amount)
+}
+
+// check verifies that all balances can be represented by 256-bit values.
+func (w world) check() error {
+	for address, account := range w {
+		if account.balance.Sign() < 0 || account.balance.BitLen() > 256 {
+			return fmt.Errorf("balance of account %v out of range: %v", address, account.balance)
+		}
+	}
+	return nil
+}
+
+// removeEmpty drops accounts removed at the end of a transaction, see EIP-161.
+func (w world) removeEmpty() {
+	maps.DeleteFunc(w, func(_ tosca.Address, account *account) bool {
+		return account.balance.Sign() == 0 && len(account.code) == 0 && account.nonce == 0
+	})
+}
+
+func (w world) toJSON() map[string]Account {
+	res := map[string]Account{}
+	for address, account := range w {
+		storage := map[string]string{}
+		for key, value := range account.storage {
+			storage[encodeWord(key)] = encodeWord(value)
+		}
+		res[hexutil.Encode(address[:])] = Account{
+			Balance: hexutil.EncodeBig(account.balance),
+			Code:    hexutil.Encode(account.code),
+			Nonce:   hexutil.EncodeUint64(account.nonce),
+			Storage: storage,
+		}
+	}
+	return res
+}
+
+// encodeWord encodes a storage key or value as a 32-byte hex string.
+func encodeWord(value U256) string {
+	word := value.Bytes32be()
+	return hexutil.Encode(word[:])
+}
+
+// getRoot computes the state root of the world as committed at the end of
+// a transaction.
+func (w world) getRoot() (common.Hash, error) {
+	db, err := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	for address, account := range w {
+		address := common.Address(address)
+		db.SetBalance(address, uint256.MustFromBig(account.balance), tracing.BalanceChangeUnspecified)
+		db.SetNonce(address, account.nonce, tracing.NonceChangeUnspecified)
+		db.SetCode(address, account.code, tracing.CodeChangeUnspecified)
+		for key, value := range account.storage {
+			db.SetState(address, key.Bytes32be(), value.Bytes32be())
+		}
+	}
+	return db.IntermediateRoot(true), nil
+}
+
+// newFixture assembles a fixture from the state at the beginning of the
+// execution of the exported program and the final state of this execution.
+func newFixture(start, end *st.State, gasLimit, floorGas uint64) (*Fixture, error) {
+	revision := start.Revision
+	block := start.BlockContext
+	contract := start.CallContext.AccountAddress
+	value := start.CallContext.Value.ToBigInt()
+	price := block.GasPrice.ToBigInt()
+	blobHashes := start.TransactionContext.BlobHashes
+	blobFee := big.NewInt(int64(len(blobHashes) * blobGasPerBlob))
+
+	// The start state is the world after the sender bought the gas and
+	// transferred the value to the contract.
+	pre := newWorld(start)
+	pre.addBalance(sender, new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), price))
+	pre.addBalance(sender, blobFee)
+	pre.addBalance(sender, value)
+	pre.addBalance(contract, new(big.Int).Neg(value))
+	if err := pre.check(); err != nil {
+		return nil, err
+	}
+
+	var post world
+	var gasLeft, refund tosca.Gas
+	var logs []*types.Log
+	switch end.Status {
+	case st.Stopped:
+		post = newWorld(end)
+		gasLeft, refund = end.Gas, end.GasRefund
+		for _, entry := range end.Logs.Entries {
+			topics := make([]common.Hash, 0, len(entry.Topics))
+			for _, topic := range entry.Topics {
+				topics = append(topics, topic.Bytes32be())
+			}
+			logs = append(logs, &types.Log{
+				Address: common.Address(contract),
+				Topics:  topics,
+				Data:    entry.Data,
+			})
+		}
+	case st.Reverted, st.Failed:
+		post = newWorld(start)
+		post.addBalance(sender, value)
+		post.addBalance(contract, new(big.Int).Neg(value))
+		if end.Status == st.Reverted {
+			gasLeft = end.Gas
+		}
+	default:
+		return nil, fmt.Errorf("unexpected final status %v", end.Status)
+	}
+	if refund < 0 {
+		return nil, fmt.Errorf("negative refund %d at end of transaction", refund)
+	}
+
+	gasUsed := gasLimit - uint64(gasLeft)
+	refundQuotient := uint64(2)
+	if revision >= tosca.R10_London {
+		refundQuotient = 5
+	}
+	gasUsed -= min(uint64(refund), gasUsed/refundQuotient)
+	gasUsed = max(gasUsed, floorGas)
+
+	tip := new(big.Int).Sub(price, block.BaseFee.ToBigInt())
+	post.addBalance(sender, new(big.Int).Mul(new(big.Int).SetUint64(gasLimit-gasUsed), price))
+	post.addBalance(block.CoinBase, new(big.Int).Mul(new(big.Int).SetUint64(gasUsed), tip))
+	post.get(sender).nonce = 1
+	post.removeEmpty()
+	if err := post.check(); err != nil {
+		return nil, err
+	}
+
+	root, err := post.getRoot()
+	if err != nil {
+		return nil, err
+	}
+	encodedLogs, err := rlp.EncodeToBytes(logs)
+	if err != nil {
+		return nil, err
+	}
+	txBytes, err := signTransaction(start, gasLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	env := Environment{
+		Coinbase:   hexutil.Encode(block.CoinBase[:]),
+		Difficulty: hexutil.EncodeBig(block.PrevRandao.ToBigInt()),
+		GasLimit:   hexutil.EncodeUint64(block.GasLimit),
+		Number:     hexutil.EncodeUint64(block.BlockNumber),
+		Timestamp:  hexutil.EncodeUint64(block.TimeStamp),
+	}
+	if revision >= tosca.R10_London {
+		env.BaseFee = hexutil.EncodeBig(block.BaseFee.ToBigInt())
+	}
+	if revision >= tosca.R11_Paris {
+		env.Difficulty = hexutil.EncodeUint64(0)
+		env.Random = hexutil.EncodeBig(block.PrevRandao.ToBigInt())
+	}
+	if revision >= tosca.R13_Cancun {
+		env.ExcessBlobGas = hexutil.EncodeUint64(0)
+	}
+
+	transaction := Transaction{
+		Data:      []string{hexutil.Encode(start.CallData.ToBytes())},
+		GasLimit:  []string{hexutil.EncodeUint64(gasLimit)},
+		GasPrice:  hexutil.EncodeBig(price),
+		Nonce:     hexutil.EncodeUint64(0),
+		SecretKey: "0x" + senderKey,
+		Sender:    hexutil.Encode(sender[:]),
+		To:        hexutil.Encode(contract[:]),
+		Value:     []string{hexutil.EncodeBig(value)},
+	}
+	if len(blobHashes) > 0 {
+		for _, hash := range blobHashes {
+			transaction.BlobVersionedHashes = append(transaction.BlobVersionedHashes, hexutil.Encode(hash[:]))
+		}
+		transaction.MaxFeePerBlobGas = hexutil.EncodeUint64(1)
+	}
+
+	return &Fixture{
+		Env:         env,
+		Pre:         pre.toJSON(),
+		Transaction: transaction,
+		Post: map[string][]PostState{
+			revision.String(): {{
+				Hash:    root.Hex(),
+				Logs:    crypto.Keccak256Hash(encodedLogs).Hex(),
+				TxBytes: hexutil.Encode(txBytes),
+				State:   post.toJSON(),
+			}},
+		},
+	}, nil
+}
+
+// signTransaction produces the signed transaction of an exported test in its
+// binary encoding.
+func signTransaction(start *st.State, gasLimit uint64) ([]byte, error) {
+	to := common.Address(start.CallContext.AccountAddress)
+	price := start.BlockContext.GasPrice.Uint256()
+	value := start.CallContext.Value.Uint256()
+	data := start.CallData.ToBytes()
+	blobHashes := start.TransactionContext.BlobHashes
+
+	var transaction types.TxData
+	if len(blobHashes) > 0 {
+		hashes := make([]common.Hash, 0, len(blobHashes))
+		for _, hash := range blobHashes {
+			hashes = append(hashes, common.Hash(hash))
+		}
+		transaction = &types.BlobTx{
+			ChainID:    uint256.NewInt(1),
+			GasTipCap:  &price,
+			GasFeeCap:  &price,
+			Gas:        gasLimit,
+			To:         to,
+			Value:      &value,
+			Data:       data,
+			BlobFeeCap: uint256.NewInt(1),
+			BlobHashes: hashes,
+		}
+	} else {
+		transaction = &types.LegacyTx{
+			GasPrice: price.ToBig(),
+			Gas:      gasLimit,
+			To:       &to,
+			Value:    value.ToBig(),
+			Data:     data,
+		}
+	}
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	signed, err := types.SignNewTx(senderPrivateKey, signer, transaction)
+	if err != nil {
+		return nil, err
+	}
+	return signed.MarshalBinary()
+}
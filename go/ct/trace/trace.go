@@ -0,0 +1,166 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+// Package trace converts steps of EIP-3155 execution traces recorded by
+// other EVM implementations into CT states. Combined with a description of
+// the call frame the step was recorded in, such states can be added to the
+// regression inputs to check divergences against all CT targets.
+package trace
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+
+	. "github.com/0xsoniclabs/tosca/go/ct/common"
+	"github.com/0xsoniclabs/tosca/go/ct/st"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethmath "github.com/ethereum/go-ethereum/common/math"
+	"github.com/holiman/uint256"
+)
+
+// maxLineLength is the maximum length of a line of a trace. Lines may
+// contain the full memory of the traced execution.
+const maxLineLength = 1 << 28
+
+// Step is a single step of an EIP-3155 trace, describing the state before
+// the execution of an instruction. The stack is listed from the bottom to
+// the top. The memory is optional; if missing, it is zero-initialized.
+type Step struct {
+	Pc         uint64                  `json:"pc"`
+	Op         byte                    `json:"op"`
+	Gas        gethmath.HexOrDecimal64 `json:"gas"`
+	Memory     hexutil.Bytes           `json:"memory,omitempty"`
+	MemorySize int                     `json:"memSize"`
+	Stack      []hexutil.U256          `json:"stack"`
+	ReturnData hexutil.Bytes           `json:"returnData,omitempty"`
+	Depth      int                     `json:"depth"`
+	Refund     uint64                  `json:"refund"`
+	OpName     string                  `json:"opName"`
+}
+
+// Frame describes the call frame a trace step was recorded in. Fields use
+// the JSON encoding of the corresponding fields of st.State. Storage and
+// balances are optional and describe the state of the accounts at the time
+// of the step. The code is attributed to the frame's account.
+type Frame struct {
+	Revision           tosca.Revision
+	ReadOnly           bool
+	Code               Bytes
+	CallData           Bytes
+	CallContext        st.CallContext
+	BlockContext       st.BlockContext
+	TransactionContext *st.TransactionContext
+	Storage            map[U256]U256
+	Balances           map[tosca.Address]U256
+}
+
+// ReadTrace parses an EIP-3155 trace with one JSON object per line. Lines
+// not describing steps, like the summary at the end of a trace, are skipped.
+func ReadTrace(reader io.Reader) ([]Step, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, maxLineLength)
+	steps := []Step{}
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		var probe struct {
+			Pc *uint64 `json:"pc"`
+		}
+		if err := json.Unmarshal(data, &probe); err != nil {
+			return nil, fmt.Errorf("invalid trace line %d: %w", line, err)
+		}
+		if probe.Pc == nil {
+			continue
+		}
+		var step Step
+		if err := json.Unmarshal(data, &step); err != nil {
+			return nil, fmt.Errorf("invalid trace line %d: %w", line, err)
+		}
+		steps = append(steps, step)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return steps, nil
+}
+
+// NewState creates the CT state of the given trace step recorded in the
+// given frame. Parts of the state not covered by the step or the frame,
+// like the warm status of accounts and storage slots, are left at their
+// default values.
+func NewState(frame *Frame, step *Step) (*st.State, error) {
+	code := frame.Code.ToBytes()
+	if step.Pc > math.MaxUint16 {
+		return nil, fmt.Errorf("program counter %d out of range", step.Pc)
+	}
+	op := vm.STOP
+	if step.Pc < uint64(len(code)) {
+		op = vm.OpCode(code[step.Pc])
+	}
+	if op != vm.OpCode(step.Op) {
+		return nil, fmt.Errorf("traced operation %v does not match operation %v at pc %d of the code",
+			vm.OpCode(step.Op), op, step.Pc)
+	}
+	if step.Gas > math.MaxInt64 {
+		return nil, fmt.Errorf("gas %d out of range", step.Gas)
+	}
+	if step.Refund > math.MaxInt64 {
+		return nil, fmt.Errorf("refund %d out of range", step.Refund)
+	}
+	if len(step.Stack) > st.MaxStackSize {
+		return nil, fmt.Errorf("stack size %d exceeds limit of %d", len(step.Stack), st.MaxStackSize)
+	}
+
+	stack := make([]U256, 0, len(step.Stack))
+	for _, value := range step.Stack {
+		stack = append(stack, NewU256FromUint256((*uint256.Int)(&value)))
+	}
+	memory := make([]byte, max(len(step.Memory), step.MemorySize))
+	copy(memory, step.Memory)
+
+	state := st.NewState(st.NewCode(code))
+	state.Revision = frame.Revision
+	state.ReadOnly = frame.ReadOnly
+	state.Pc = uint16(step.Pc)
+	state.Gas = tosca.Gas(step.Gas)
+	state.GasRefund = tosca.Gas(step.Refund)
+	state.Stack = st.NewStack(stack...)
+	state.Memory = st.NewMemory(memory...)
+	state.CallData = frame.CallData
+	state.CallContext = frame.CallContext
+	state.BlockContext = frame.BlockContext
+	state.LastCallReturnData = NewBytes(step.ReturnData)
+	if frame.TransactionContext != nil {
+		state.TransactionContext = frame.TransactionContext
+	}
+
+	storage := st.NewStorageBuilder()
+	for key, value := range frame.Storage {
+		storage.SetCurrent(key, value)
+		storage.SetOriginal(key, value)
+	}
+	state.Storage = storage.Build()
+
+	accounts := st.NewAccountsBuilder()
+	for address, balance := range frame.Balances {
+		accounts.SetBalance(address, balance)
+	}
+	accounts.SetCode(frame.CallContext.AccountAddress, frame.Code)
+	state.Accounts = accounts.Build()
+	return state, nil
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package trace
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/0xsoniclabs/tosca/go/ct/common"
+	"github.com/0xsoniclabs/tosca/go/ct/spc"
+	"github.com/0xsoniclabs/tosca/go/ct/st"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	geth_vm "github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/eth/tracers/logger"
+	"github.com/holiman/uint256"
+)
+
+func TestReadTrace_SkipsLinesNotDescribingSteps(t *testing.T) {
+	input := `{"pc":0,"op":96,"gas":"0x2710","gasCost":"0x3","memSize":0,"stack":[],"depth":1,"refund":0,"opName":"PUSH1"}
+
+{"pc":2,"op":0,"gas":"0x270d","gasCost":"0x0","memSize":0,"stack":["0x1"],"depth":1,"refund":0,"opName":"STOP"}
+{"output":"","gasUsed":"0x3"}
+`
+	steps, err := ReadTrace(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("failed to read trace: %v", err)
+	}
+	if len(steps) != 2 {
+		t.Fatalf("unexpected number of steps, wanted 2, got %d", len(steps))
+	}
+	if steps[1].Pc != 2 || steps[1].Gas != 0x270d || len(steps[1].Stack) != 1 {
+		t.Errorf("unexpected second step: %+v", steps[1])
+	}
+}
+
+func TestReadTrace_ReportsInvalidLines(t *testing.T) {
+	_, err := ReadTrace(strings.NewReader("{\"pc\":0}\nnot json\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected error referring to line 2, got %v", err)
+	}
+}
+
+func TestNewState_ConvertsTraceStep(t *testing.T) {
+	frame := &Frame{
+		Revision: tosca.R13_Cancun,
+		Code:     NewBytes([]byte{byte(vm.PUSH1), 1, byte(vm.ADD)}),
+		CallContext: st.CallContext{
+			AccountAddress: tosca.Address{0x10},
+		},
+		Storage:  map[U256]U256{NewU256(1): NewU256(2)},
+		Balances: map[tosca.Address]U256{{0x20}: NewU256(3)},
+	}
+	step := &Step{
+		Pc:         2,
+		Op:         byte(vm.ADD),
+		Gas:        1000,
+		Memory:     hexutil.Bytes{1, 2},
+		MemorySize: 32,
+		Stack:      []hexutil.U256{hexutil.U256(*uint256.NewInt(7)), hexutil.U256(*uint256.NewInt(1))},
+		Refund:     5,
+	}
+
+	state, err := NewState(frame, step)
+	if err != nil {
+		t.Fatalf("failed to convert step: %v", err)
+	}
+	if want, got := tosca.R13_Cancun, state.Revision; want != got {
+		t.Errorf("unexpected revision, wanted %v, got %v", want, got)
+	}
+	if state.Pc != 2 || state.Gas != 1000 || state.GasRefund != 5 {
+		t.Errorf("unexpected pc, gas or refund: %d, %d, %d", state.Pc, state.Gas, state.GasRefund)
+	}
+	if want, got := st.NewStack(NewU256(7), NewU256(1)), state.Stack; !want.Eq(got) {
+		t.Errorf("unexpected stack, wanted %v, got %v", want, got)
+	}
+	if want, got := 32, state.Memory.Size(); want != got {
+		t.Errorf("unexpected memory size, wanted %d, got %d", want, got)
+	}
+	if want, got := []byte{1, 2, 0}, state.Memory.Read(0, 3); !bytes.Equal(want, got) {
+		t.Errorf("unexpected memory content, wanted %x, got %x", want, got)
+	}
+	if want, got := NewU256(2), state.Storage.GetOriginal(NewU256(1)); want != got {
+		t.Errorf("unexpected original storage value, wanted %v, got %v", want, got)
+	}
+	if want, got := NewU256(3), state.Accounts.GetBalance(tosca.Address{0x20}); want != got {
+		t.Errorf("unexpected balance, wanted %v, got %v", want, got)
+	}
+	if want, got := frame.Code, state.Accounts.GetCode(tosca.Address{0x10}); want != got {
+		t.Errorf("unexpected code of frame account, wanted %v, got %v", want, got)
+	}
+}
+
+func TestNewState_RejectsInconsistentSteps(t *testing.T) {
+	tests := map[string]struct {
+		step Step
+		want string
+	}{
+		"mismatching operation": {
+			step: Step{Pc: 0, Op: byte(vm.ADD)},
+			want: "does not match",
+		},
+		"operation past end of code": {
+			step: Step{Pc: 5, Op: byte(vm.ADD)},
+			want: "does not match",
+		},
+		"pc out of range": {
+			step: Step{Pc: 1 << 16},
+			want: "program counter",
+		},
+		"gas out of range": {
+			step: Step{Op: byte(vm.PUSH1), Gas: 1 << 63},
+			want: "gas",
+		},
+		"stack too large": {
+			step: Step{Op: byte(vm.PUSH1), Stack: make([]hexutil.U256, st.MaxStackSize+1)},
+			want: "stack size",
+		},
+	}
+
+	frame := &Frame{Code: NewBytes([]byte{byte(vm.PUSH1), 1})}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewState(frame, &test.step)
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("unexpected error, wanted %q, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestNewState_StepsOfGethTraceAreReproducedBySpecification(t *testing.T) {
+	code := []byte{
+		byte(vm.PUSH1), 1,
+		byte(vm.PUSH1), 2,
+		byte(vm.ADD),
+		byte(vm.PUSH1), 0,
+		byte(vm.MSTORE),
+		byte(vm.PUSH1), 32,
+		byte(vm.PUSH1), 0,
+		byte(vm.RETURN),
+	}
+
+	var buffer bytes.Buffer
+	_, _, err := runtime.Execute(code, nil, &runtime.Config{
+		GasLimit: 100_000,
+		EVMConfig: geth_vm.Config{
+			Tracer: logger.NewJSONLogger(&logger.Config{EnableMemory: true}, &buffer),
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to run code in geth: %v", err)
+	}
+
+	steps, err := ReadTrace(&buffer)
+	if err != nil {
+		t.Fatalf("failed to read trace: %v", err)
+	}
+	if want, got := 8, len(steps); want != got {
+		t.Fatalf("unexpected number of steps, wanted %d, got %d", want, got)
+	}
+
+	frame := &Frame{Revision: tosca.R13_Cancun, Code: NewBytes(code)}
+	for i := 0; i+1 < len(steps); i++ {
+		state, err := NewState(frame, &steps[i])
+		if err != nil {
+			t.Fatalf("failed to convert step %d: %v", i, err)
+		}
+		rules := spc.Spec.GetRulesFor(state)
+		if len(rules) == 0 {
+			t.Fatalf("no rule for step %d", i)
+		}
+		rules[0].Effect.Apply(state)
+
+		want, err := NewState(frame, &steps[i+1])
+		if err != nil {
+			t.Fatalf("failed to convert step %d: %v", i+1, err)
+		}
+		if state.Pc != want.Pc || state.Gas != want.Gas {
+			t.Errorf("step %d: unexpected pc or gas, wanted %d/%d, got %d/%d", i, want.Pc, want.Gas, state.Pc, state.Gas)
+		}
+		if !state.Stack.Eq(want.Stack) {
+			t.Errorf("step %d: unexpected stack, wanted %v, got %v", i, want.Stack, state.Stack)
+		}
+		if !state.Memory.Eq(want.Memory) {
+			t.Errorf("step %d: unexpected memory, wanted %v, got %v", i, want.Memory, state.Memory)
+		}
+	}
+}
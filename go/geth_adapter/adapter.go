@@ -21,6 +21,7 @@ package geth_adapter
 import (
 	"fmt"
 	"math/big"
+	"sync"
 
 	"github.com/0xsoniclabs/tosca/go/tosca"
 	common "github.com/ethereum/go-ethereum/common"
@@ -45,9 +46,57 @@ type gethInterpreterAdapter struct {
 	evm         *geth.EVM
 }
 
+// cancelSignals maps EVM instances observed by CancelOnDone to channels closed
+// once the EVM gets canceled. Interpreter adapters are created by geth for each
+// call and can thus not hold this signal themselves.
+var cancelSignals sync.Map // *geth.EVM -> chan struct{}
+
+// CancelOnDone cancels the execution of the given EVM as soon as the done
+// channel is closed. The returned function ends the observation of the channel
+// and must be called once the execution of the EVM has finished. While the
+// observation is active, interpreter runs of the EVM are provided with a Done
+// channel closed on cancellation, such that ongoing runs get aborted as well.
+func CancelOnDone(evm *geth.EVM, done <-chan struct{}) (stop func()) {
+	if done == nil {
+		return func() {}
+	}
+	canceled := make(chan struct{})
+	cancelSignals.Store(evm, canceled)
+	stopped := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		select {
+		case <-done:
+			evm.Cancel()
+			close(canceled)
+		case <-stopped:
+		}
+	}()
+	return func() {
+		close(stopped)
+		<-finished
+		cancelSignals.Delete(evm)
+	}
+}
+
+// getCancelSignal returns the channel closed when the given EVM gets canceled
+// through CancelOnDone, or nil if the EVM is not observed.
+func getCancelSignal(evm *geth.EVM) <-chan struct{} {
+	if signal, found := cancelSignals.Load(evm); found {
+		return signal.(chan struct{})
+	}
+	return nil
+}
+
 func (a *gethInterpreterAdapter) Interpret(contract *geth.Contract, input []byte, readOnly bool) (ret []byte, err error) {
 	var result tosca.Result
 
+	// Canceled executions do not start any further interpreter runs.
+	if a.evm.Cancelled() {
+		return nil, tosca.ErrCanceled
+	}
+
 	// Tosca EVM implementations update the refund in the StateDB only at the
 	// end of a contract execution. As a result, it may happen that the refund
 	// becomes temporary negative, since a nested contract may trigger a
@@ -132,6 +181,7 @@ func (a *gethInterpreterAdapter) Interpret(contract *geth.Contract, input []byte
 		Origin:     tosca.Address(a.evm.Origin),
 		GasPrice:   gasPrice,
 		BlobHashes: blobHashes,
+		Done:       getCancelSignal(a.evm),
 	}
 
 	params := tosca.Parameters{
@@ -174,8 +224,9 @@ func (a *gethInterpreterAdapter) Interpret(contract *geth.Contract, input []byte
 	// In geth, reverted executions are signaled through an error.
 	// The only two types that need to be differentiated are revert
 	// errors (in which gas is accounted for accurately) and any
-	// other error.
-	if (result.GasLeft > 0 || len(result.Output) > 0) && !result.Success {
+	// other error. Interpreters not flagging reverts explicitly are
+	// covered by considering remaining gas and output.
+	if !result.Success && (result.Reverted || result.GasLeft > 0 || len(result.Output) > 0) {
 		return result.Output, geth.ErrExecutionReverted
 	}
 	if !result.Success {
@@ -302,6 +353,7 @@ func (a *runContextAdapter) Call(kind tosca.CallKind, parameter tosca.CallParame
 		GasRefund:      0, // refunds of nested calls are managed by the geth EVM and this adapter
 		CreatedAddress: createdAddress,
 		Success:        err == nil,
+		Reverted:       err == geth.ErrExecutionReverted,
 	}, nil
 }
 
@@ -19,6 +19,7 @@ import (
 	"reflect"
 	"slices"
 	"testing"
+	"time"
 
 	"github.com/0xsoniclabs/tosca/go/interpreter/lfvm"
 	"github.com/0xsoniclabs/tosca/go/tosca"
@@ -1911,3 +1912,73 @@ func newEVMWithPassingChainConfig() *geth.EVM {
 	}
 	return geth.NewEVM(blockContext, nil, chainConfig, geth.Config{})
 }
+
+func TestCancelOnDone_CancelsEvmWhenDoneIsClosed(t *testing.T) {
+	evm := &geth.EVM{}
+	done := make(chan struct{})
+	stop := CancelOnDone(evm, done)
+	defer stop()
+
+	require.False(t, evm.Cancelled())
+	close(done)
+	require.Eventually(t, evm.Cancelled, time.Second, time.Millisecond)
+}
+
+func TestCancelOnDone_StoppedObservationDoesNotCancelEvm(t *testing.T) {
+	evm := &geth.EVM{}
+	done := make(chan struct{})
+	stop := CancelOnDone(evm, done)
+	stop()
+
+	close(done)
+	require.Never(t, evm.Cancelled, 50*time.Millisecond, time.Millisecond)
+}
+
+func TestCancelOnDone_NilChannelNeverCancelsEvm(t *testing.T) {
+	evm := &geth.EVM{}
+	stop := CancelOnDone(evm, nil)
+	stop()
+	require.False(t, evm.Cancelled())
+}
+
+func TestCancelOnDone_CancelSignalIsClosedWhenEvmIsCanceled(t *testing.T) {
+	evm := &geth.EVM{}
+	done := make(chan struct{})
+	stop := CancelOnDone(evm, done)
+	defer stop()
+
+	signal := getCancelSignal(evm)
+	require.NotNil(t, signal)
+	select {
+	case <-signal:
+		t.Fatal("cancel signal closed before cancellation")
+	default:
+	}
+
+	close(done)
+	select {
+	case <-signal:
+		require.True(t, evm.Cancelled())
+	case <-time.After(time.Second):
+		t.Fatal("cancel signal not closed after cancellation")
+	}
+}
+
+func TestCancelOnDone_CancelSignalIsRemovedWhenObservationStops(t *testing.T) {
+	evm := &geth.EVM{}
+	stop := CancelOnDone(evm, make(chan struct{}))
+	require.NotNil(t, getCancelSignal(evm))
+	stop()
+	require.Nil(t, getCancelSignal(evm))
+}
+
+func TestGethAdapter_InterpretReturnsErrorIfEvmIsCanceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	interpreter := tosca.NewMockInterpreter(ctrl)
+	evm := &geth.EVM{}
+	evm.Cancel()
+
+	adapter := &gethInterpreterAdapter{interpreter: interpreter, evm: evm}
+	_, err := adapter.Interpret(nil, nil, false)
+	require.ErrorIs(t, err, tosca.ErrCanceled)
+}
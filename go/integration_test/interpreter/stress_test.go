@@ -81,6 +81,103 @@ func BenchmarkParallel_StressTests(b *testing.B) {
 	}
 }
 
+// BenchmarkNestedCalls_Allocations runs chains of nested calls in which each
+// frame uses a few KiB of memory. It reports allocations to track the reuse
+// of execution contexts and memory across nested calls.
+func BenchmarkNestedCalls_Allocations(b *testing.B) {
+	code := getStressBenchmark_NestedCalls()
+	for _, interpreterName := range getAllInterpreterVariantsForTests() {
+		interpreter, err := tosca.NewInterpreter(interpreterName)
+		if err != nil {
+			b.Fatalf("failed to load %s with error: %v", interpreterName, err)
+		}
+		for _, depth := range []int{1, 16, 256} {
+			b.Run(fmt.Sprintf("%s-depth-%d", interpreterName, depth), func(b *testing.B) {
+				runContext := &nestedCallRunContext{
+					interpreter: interpreter,
+					code:        code,
+					maxDepth:    depth,
+				}
+				params := tosca.Parameters{
+					BlockParameters: tosca.BlockParameters{Revision: tosca.R13_Cancun},
+					Context:         runContext,
+					Gas:             10_000_000,
+					Code:            code,
+				}
+
+				b.ReportAllocs()
+				b.ResetTimer()
+				for range b.N {
+					result, err := interpreter.Run(params)
+					if err != nil || !result.Success {
+						b.Fatalf("interpreter run failed or was not successful, err: %v", err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// nestedCallRunContext executes calls by running the given code with the
+// given interpreter until the maximum depth is reached. Only the context
+// functions required by the nested calls benchmark are implemented.
+type nestedCallRunContext struct {
+	tosca.RunContext
+	interpreter tosca.Interpreter
+	code        []byte
+	depth       int
+	maxDepth    int
+}
+
+func (c *nestedCallRunContext) AccessAccount(tosca.Address) tosca.AccessStatus {
+	return tosca.WarmAccess
+}
+
+func (c *nestedCallRunContext) Call(_ tosca.CallKind, params tosca.CallParameters) (tosca.CallResult, error) {
+	if c.depth >= c.maxDepth {
+		return tosca.CallResult{Success: true, GasLeft: params.Gas}, nil
+	}
+	c.depth++
+	defer func() { c.depth-- }()
+	result, err := c.interpreter.Run(tosca.Parameters{
+		BlockParameters: tosca.BlockParameters{Revision: tosca.R13_Cancun},
+		Context:         c,
+		Input:           params.Input,
+		Gas:             params.Gas,
+		Code:            c.code,
+		Depth:           c.depth,
+	})
+	if err != nil {
+		return tosca.CallResult{}, err
+	}
+	return tosca.CallResult{
+		Output:  result.Output,
+		GasLeft: result.GasLeft,
+		Success: result.Success,
+	}, nil
+}
+
+func getStressBenchmark_NestedCalls() []byte {
+	code := []byte{
+		byte(vm.PUSH1), byte(1), // value to store
+		byte(vm.PUSH2), byte(0x0f), byte(0xe0), // offset 4064, expanding memory to 4 KiB
+		byte(vm.MSTORE),          // store value in memory
+		byte(vm.PUSH1), byte(32), // output size
+		byte(vm.PUSH1), byte(0), // output offset
+		byte(vm.PUSH1), byte(32), // input size
+		byte(vm.PUSH1), byte(0), // input offset
+		byte(vm.PUSH1), byte(0), // value
+		byte(vm.PUSH1), byte(0), // address
+		byte(vm.GAS),             // forward all available gas
+		byte(vm.CALL),            // call the next frame
+		byte(vm.POP),             // pop call result
+		byte(vm.PUSH1), byte(32), // return size
+		byte(vm.PUSH1), byte(0), // return offset
+		byte(vm.RETURN), // return
+	}
+	return code
+}
+
 func getStressBenchmark_NoCodeReUsage() []byte {
 	code := make([]byte, 6*3*1000)
 	for i := 0; i < 6*3*1000; i += 6 {
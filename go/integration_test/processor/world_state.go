@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"maps"
 
+	"github.com/0xsoniclabs/tosca/go/processor/tracing"
 	"github.com/0xsoniclabs/tosca/go/tosca"
 )
 
@@ -27,6 +28,26 @@ import (
 // test scenarios for transaction processors.
 type WorldState map[tosca.Address]Account
 
+// NewWorldStateFromPrestate converts a prestate recorded by a
+// tracing.PrestateTracer into a world state. This way, transactions observed
+// on a live chain can be reproduced offline in test scenarios.
+func NewWorldStateFromPrestate(prestate map[tosca.Address]tracing.Account) WorldState {
+	res := make(WorldState, len(prestate))
+	for address, account := range prestate {
+		var balance tosca.Value
+		if account.Balance != nil {
+			balance = *account.Balance
+		}
+		res[address] = Account{
+			Balance: balance,
+			Nonce:   account.Nonce,
+			Code:    bytes.Clone(account.Code),
+			Storage: maps.Clone(Storage(account.Storage)),
+		}
+	}
+	return res
+}
+
 func (s WorldState) Equal(other WorldState) bool {
 	return equalMapsIgnoringZero(s, other, func(a, b Account) bool {
 		return a.Equal(&b)
@@ -16,6 +16,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/0xsoniclabs/tosca/go/processor/tracing"
 	"github.com/0xsoniclabs/tosca/go/tosca"
 	"github.com/0xsoniclabs/tosca/go/tosca/vm"
 )
@@ -48,6 +49,32 @@ func TestWorldState_Equal(t *testing.T) {
 	}
 }
 
+func TestWorldState_NewWorldStateFromPrestate(t *testing.T) {
+	balance := tosca.NewValue(12)
+	prestate := map[tosca.Address]tracing.Account{
+		{1}: {
+			Balance: &balance,
+			Nonce:   3,
+			Code:    tosca.Code{byte(vm.STOP)},
+			Storage: map[tosca.Key]tosca.Word{{1}: {2}},
+		},
+		{2}: {},
+	}
+
+	want := WorldState{
+		{1}: Account{
+			Balance: balance,
+			Nonce:   3,
+			Code:    tosca.Code{byte(vm.STOP)},
+			Storage: Storage{{1}: {2}},
+		},
+	}
+	got := NewWorldStateFromPrestate(prestate)
+	if !want.Equal(got) {
+		t.Errorf("unexpected world state, diff: %v", want.Diff(got))
+	}
+}
+
 func TestWorldState_Clone(t *testing.T) {
 	tests := map[string]struct {
 		a WorldState
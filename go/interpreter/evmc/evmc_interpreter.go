@@ -105,6 +105,7 @@ func (e *EvmcInterpreter) Run(params tosca.Parameters) (tosca.Result, error) {
 		// This is not really an error, but actually a revert.
 		// This is to be processed as a successful execution.
 		res.Success = false // < signal that execution reverted
+		res.Reverted = true
 		return res, nil
 	case evmc.Error(C.EVMC_OUT_OF_GAS),
 		evmc.Error(C.EVMC_INVALID_INSTRUCTION),
@@ -0,0 +1,128 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package lfvm
+
+import (
+	"github.com/0xsoniclabs/tosca/go/tosca"
+)
+
+// basicBlock summarizes the static properties of a sequence of instructions
+// that, unless an error occurs, is always executed as a whole. By checking the
+// stack bounds and charging the static gas costs of all instructions of a
+// block once when entering it, those checks can be skipped for the individual
+// instructions.
+type basicBlock struct {
+	// staticGas is the sum of the static gas prices of all instructions in
+	// the block, indexed by the static gas price table of a revision (see
+	// gasPrices). The sums of all blocks of a code share one allocation.
+	staticGas []tosca.Gas
+	// minStack is the minimum stack size required when entering the block.
+	minStack int32
+	// maxStack is the maximum stack size allowed when entering the block.
+	maxStack int32
+	// last is the position of the last instruction of the block.
+	last int32
+}
+
+// basicBlocks lists the basic blocks of a code, indexed by the position of
+// their first instruction. Entries at any other position are zero and thus
+// have a last position preceding their own position, with the exception of
+// position 0, which is always the start of a basic block.
+type basicBlocks []basicBlock
+
+// computeBasicBlocks splits the given code into basic blocks. A basic block
+// starts at the beginning of the code, at each JUMPDEST, and after each
+// instruction ending a block. Blocks are ended by instructions changing the
+// control flow, by invalid instructions, and by instructions depending on the
+// amount of remaining gas. The latter is required since the static gas of
+// instructions following those in the same block would already be charged.
+// The static gas of each block is summed up for all tables of the given prices.
+func computeBasicBlocks(code Code, prices *gasPrices) basicBlocks {
+	res := make(basicBlocks, len(code))
+	tables := len(prices.static)
+	starts := []int{}
+	sums := []tosca.Gas{}
+	gas := make([]tosca.Gas, tables)
+	usage := stackUsage{}
+	add := func(start, last int) {
+		res[start] = basicBlock{
+			minStack: int32(-usage.from),
+			maxStack: int32(maxStackSize - usage.to),
+			last:     int32(last),
+		}
+		starts = append(starts, start)
+		sums = append(sums, gas...)
+		clear(gas)
+		usage = stackUsage{}
+	}
+
+	start, previous := 0, 0
+	for i := 0; i < len(code); {
+		op := code[i].opcode
+		if op == JUMPDEST && i > start {
+			add(start, previous)
+			start = i
+		}
+
+		usage = combineStackUsage(usage, computeStackUsage(op))
+		for table := range prices.static {
+			gas[table] += prices.static[table].get(op)
+		}
+
+		next := code.next(i)
+		if _endsBasicBlock.get(op) || next >= len(code) {
+			add(start, i)
+			start = next
+		}
+		previous, i = i, next
+	}
+
+	for i, start := range starts {
+		res[start].staticGas = sums[i*tables : (i+1)*tables : (i+1)*tables]
+	}
+	return res
+}
+
+// enter checks the stack bounds of the block and charges its static gas. If
+// any of those fails, false is returned and the context is not modified. In
+// this case, the instructions of the block need to be checked individually to
+// produce the exact same behavior as if no blocks were used.
+func (b *basicBlock) enter(c *context, gasTable int) bool {
+	size := c.stack.len()
+	if size < int(b.minStack) || size > int(b.maxStack) {
+		return false
+	}
+	return c.useGas(b.staticGas[gasTable]) == nil
+}
+
+var _endsBasicBlock = newOpCodePropertyMap(func(op OpCode) bool {
+	if op.isSuperInstruction() {
+		for _, subOp := range op.decompose() {
+			if endsBasicBlock(subOp) {
+				return true
+			}
+		}
+		return false
+	}
+	return endsBasicBlock(op)
+})
+
+func endsBasicBlock(op OpCode) bool {
+	switch op {
+	// instructions changing the control flow
+	case JUMP, JUMPI, JUMP_TO, JUMP_STATIC, JUMPI_STATIC, STOP, RETURN, REVERT, SELFDESTRUCT, INVALID:
+		return true
+	// instructions depending on the remaining gas
+	case GAS, SSTORE, CALL, CALLCODE, DELEGATECALL, STATICCALL, CREATE, CREATE2:
+		return true
+	}
+	return getStaticGasPriceInternal(op) == UNKNOWN_GAS_PRICE
+}
@@ -0,0 +1,398 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package lfvm
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+	"github.com/holiman/uint256"
+)
+
+func TestComputeBasicBlocks_SplitsCodeAtBlockBoundaries(t *testing.T) {
+	code := Code{
+		{PUSH1, 1},
+		{PUSH1, 2},
+		{ADD, 0},
+		{JUMPDEST, 0},
+		{POP, 0},
+		{GAS, 0},
+		{PUSH3, 1},
+		{DATA, 2},
+		{JUMPDEST, 0},
+		{SLOAD, 0},
+	}
+
+	want := map[int]basicBlock{
+		0: {staticGas: []tosca.Gas{9, 9}, minStack: 0, maxStack: maxStackSize - 2, last: 2},
+		3: {staticGas: []tosca.Gas{5, 5}, minStack: 1, maxStack: maxStackSize, last: 5},
+		6: {staticGas: []tosca.Gas{3, 3}, minStack: 0, maxStack: maxStackSize - 1, last: 6},
+		8: {staticGas: []tosca.Gas{801, 1}, minStack: 1, maxStack: maxStackSize, last: 9},
+	}
+	got := computeBasicBlocks(code, defaultGasPrices)
+	if len(got) != len(code) {
+		t.Fatalf("unexpected number of entries, want %d, got %d", len(code), len(got))
+	}
+	for i := range code {
+		if !reflect.DeepEqual(want[i], got[i]) {
+			t.Errorf("unexpected block at position %d, want %v, got %v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestComputeBasicBlocks_BlocksEndAfterInstructionsDependingOnGasOrChangingControlFlow(t *testing.T) {
+	for _, op := range []OpCode{
+		JUMP, JUMPI, JUMP_TO, STOP, RETURN, REVERT, SELFDESTRUCT, INVALID,
+		GAS, SSTORE, CALL, CALLCODE, DELEGATECALL, STATICCALL, CREATE, CREATE2,
+		PUSH2_JUMP, ISZERO_PUSH2_JUMPI, OpCode(0xef),
+	} {
+		t.Run(op.String(), func(t *testing.T) {
+			blocks := computeBasicBlocks(Code{{op, 0}, {ADD, 0}}, defaultGasPrices)
+			if want, got := int32(0), blocks[0].last; want != got {
+				t.Errorf("unexpected end of first block, want %d, got %d", want, got)
+			}
+			if want, got := int32(1), blocks[1].last; want != got {
+				t.Errorf("unexpected end of second block, want %d, got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestComputeBasicBlocks_DataInstructionsAreNotSplitFromTheirInstruction(t *testing.T) {
+	codes := map[string]Code{}
+	for numBytes := 1; numBytes <= 32; numBytes++ {
+		push := append([]byte{byte(vm.PUSH1) + byte(numBytes-1)}, make([]byte, numBytes)...)
+		codes[fmt.Sprintf("PUSH%d", numBytes)] = convert(append(push, byte(vm.STOP)), ConversionConfig{})
+	}
+	withSuperInstructions := ConversionConfig{WithSuperInstructions: true}
+	codes["PUSH1_PUSH4_DUP3"] = convert([]byte{byte(vm.PUSH1), 1, byte(vm.PUSH4), 1, 2, 3, 4, byte(vm.DUP3), byte(vm.STOP)}, withSuperInstructions)
+	codes["PUSH1_PUSH1_PUSH1_SHL_SUB"] = convert([]byte{byte(vm.PUSH1), 1, byte(vm.PUSH1), 2, byte(vm.PUSH1), 3, byte(vm.SHL), byte(vm.SUB), byte(vm.STOP)}, withSuperInstructions)
+
+	for name, code := range codes {
+		t.Run(name, func(t *testing.T) {
+			blocks := computeBasicBlocks(code, defaultGasPrices)
+			if want, got := int32(len(code)-1), blocks[0].last; want != got {
+				t.Errorf("unexpected end of block, want %d, got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestBasicBlock_enterChecksStackAndChargesStaticGas(t *testing.T) {
+	block := basicBlock{staticGas: []tosca.Gas{20, 10}, minStack: 1, maxStack: 3}
+	tests := map[string]struct {
+		stackSize int
+		gas       tosca.Gas
+		revision  tosca.Revision
+		success   bool
+		gasLeft   tosca.Gas
+	}{
+		"success":          {stackSize: 2, gas: 15, revision: tosca.R09_Berlin, success: true, gasLeft: 5},
+		"pre-Berlin price": {stackSize: 2, gas: 25, revision: tosca.R07_Istanbul, success: true, gasLeft: 5},
+		"out of gas":       {stackSize: 2, gas: 15, revision: tosca.R07_Istanbul, gasLeft: 15},
+		"stack underflow":  {stackSize: 0, gas: 15, revision: tosca.R09_Berlin, gasLeft: 15},
+		"stack overflow":   {stackSize: 4, gas: 15, revision: tosca.R09_Berlin, gasLeft: 15},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctxt := getContext(Code{}, nil, nil, test.stackSize, test.gas, test.revision)
+			defer ReturnStack(ctxt.stack)
+			if want, got := test.success, block.enter(&ctxt, defaultGasPrices.table[test.revision]); want != got {
+				t.Errorf("unexpected result, want %t, got %t", want, got)
+			}
+			if want, got := test.gasLeft, ctxt.gas; want != got {
+				t.Errorf("unexpected gas left, want %d, got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestBasicBlocks_ExecutionResultsMatchInstructionWiseChecks(t *testing.T) {
+	codes := map[string][]byte{
+		"loop reporting remaining gas": {
+			byte(vm.PUSH1), 10,
+			byte(vm.JUMPDEST),
+			byte(vm.PUSH1), 1,
+			byte(vm.SWAP1),
+			byte(vm.SUB),
+			byte(vm.DUP1),
+			byte(vm.PUSH1), 2,
+			byte(vm.JUMPI),
+			byte(vm.GAS),
+			byte(vm.PUSH1), 0,
+			byte(vm.MSTORE),
+			byte(vm.PUSH1), 32,
+			byte(vm.PUSH1), 0,
+			byte(vm.RETURN),
+		},
+		"stack overflow in loop": {
+			byte(vm.JUMPDEST),
+			byte(vm.CALLVALUE),
+			byte(vm.PUSH1), 0,
+			byte(vm.JUMP),
+		},
+		"stack underflow within block": {
+			byte(vm.PUSH1), 1,
+			byte(vm.PUSH1), 2,
+			byte(vm.ADD),
+			byte(vm.ADD),
+			byte(vm.STOP),
+		},
+		"memory expansion within block": {
+			byte(vm.PUSH1), 1,
+			byte(vm.PUSH2), 0x0f, 0xff,
+			byte(vm.MSTORE),
+			byte(vm.PUSH1), 1,
+			byte(vm.POP),
+			byte(vm.STOP),
+		},
+		"revert with remaining gas": {
+			byte(vm.PUSH1), 1,
+			byte(vm.PUSH1), 2,
+			byte(vm.ADD),
+			byte(vm.PUSH1), 0,
+			byte(vm.MSTORE),
+			byte(vm.PUSH1), 32,
+			byte(vm.PUSH1), 0,
+			byte(vm.REVERT),
+		},
+		"invalid instruction within block": {
+			byte(vm.PUSH1), 1,
+			byte(vm.INVALID),
+			byte(vm.PUSH1), 1,
+		},
+		"truncated push": {
+			byte(vm.PUSH1), 1,
+			byte(vm.PUSH32), 1, 2,
+		},
+	}
+
+	for name, code := range codes {
+		for _, revision := range []tosca.Revision{tosca.R07_Istanbul, tosca.R13_Cancun} {
+			for _, gas := range []tosca.Gas{0, 5, 10, 20, 50, 100, 200, 500, 1000, 100_000} {
+				t.Run(fmt.Sprintf("%s/%v/%d", name, revision, gas), func(t *testing.T) {
+					converted := newConvertedCode(code, ConversionConfig{}, defaultGasPrices)
+					params := tosca.Parameters{
+						BlockParameters: tosca.BlockParameters{Revision: revision},
+						Gas:             gas,
+						Code:            code,
+					}
+					want, err := run(config{}, params, converted.code, nil)
+					if err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+					got, err := run(config{}, params, converted.code, converted.blocks)
+					if err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+					if want.Success != got.Success || want.GasLeft != got.GasLeft || string(want.Output) != string(got.Output) {
+						t.Errorf("unexpected result, want %v, got %v", want, got)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestBasicBlocks_BlockModeMatchesSingleStepMode(t *testing.T) {
+	codes := map[string][]byte{
+		"arithmetic loop": {
+			byte(vm.PUSH1), 10,
+			byte(vm.JUMPDEST),
+			byte(vm.PUSH1), 3,
+			byte(vm.PUSH1), 5,
+			byte(vm.MUL),
+			byte(vm.POP),
+			byte(vm.PUSH1), 1,
+			byte(vm.SWAP1),
+			byte(vm.SUB),
+			byte(vm.DUP1),
+			byte(vm.PUSH1), 2,
+			byte(vm.JUMPI),
+			byte(vm.STOP),
+		},
+		"branches": {
+			byte(vm.CALLVALUE),
+			byte(vm.PUSH1), 8,
+			byte(vm.JUMPI),
+			byte(vm.PUSH1), 1,
+			byte(vm.PUSH1), 12,
+			byte(vm.JUMP),
+			byte(vm.JUMPDEST),
+			byte(vm.PUSH1), 2,
+			byte(vm.PUSH1), 0,
+			byte(vm.JUMPDEST),
+			byte(vm.PUSH1), 0,
+			byte(vm.MSTORE),
+			byte(vm.GAS),
+			byte(vm.PUSH1), 32,
+			byte(vm.MSTORE),
+			byte(vm.STOP),
+		},
+	}
+	for i := range 50 {
+		codes[fmt.Sprintf("random-%d", i)] = getRandomBlockProgram(rand.New(rand.NewSource(int64(i))))
+	}
+
+	configs := map[string]ConversionConfig{}
+	for _, superInstructions := range []bool{false, true} {
+		for _, staticJumps := range []bool{false, true} {
+			name := fmt.Sprintf("si=%t/static-jumps=%t", superInstructions, staticJumps)
+			configs[name] = ConversionConfig{
+				WithSuperInstructions: superInstructions,
+				WithStaticJumps:       staticJumps,
+			}
+		}
+	}
+
+	stacks := map[string][]uint256.Int{
+		"empty": nil,
+		"small": {*uint256.NewInt(1), *uint256.NewInt(0)},
+	}
+	for i := 1; i <= 4; i++ {
+		stacks[fmt.Sprintf("full-%d", i)] = make([]uint256.Int, maxStackSize-i)
+	}
+
+	for codeName, code := range codes {
+		for configName, config := range configs {
+			converted := newConvertedCode(code, config, defaultGasPrices)
+			for stackName, stack := range stacks {
+				for _, gas := range []tosca.Gas{0, 10, 50, 200, 1000, 100_000} {
+					name := fmt.Sprintf("%s/%s/%s/%d", codeName, configName, stackName, gas)
+					t.Run(name, func(t *testing.T) {
+						newContext := func(blocks basicBlocks) *context {
+							ctxt := getContext(converted.code, nil, nil, 0, gas, tosca.R13_Cancun)
+							ctxt.params.Value = tosca.Value{31: 1}
+							ctxt.blocks = blocks
+							for i := range stack {
+								ctxt.stack.push(&stack[i])
+							}
+							return &ctxt
+						}
+
+						want := newContext(nil)
+						defer ReturnStack(want.stack)
+						defer ReturnMemory(want.memory)
+						wantStatus := statusRunning
+						for wantStatus == statusRunning {
+							wantStatus = execute(want, true)
+						}
+
+						got := newContext(converted.blocks)
+						defer ReturnStack(got.stack)
+						defer ReturnMemory(got.memory)
+						gotStatus := execute(got, false)
+
+						if wantStatus != gotStatus {
+							t.Fatalf("unexpected status, want %v, got %v", wantStatus, gotStatus)
+						}
+						if want.pc != got.pc {
+							t.Errorf("unexpected pc, want %d, got %d", want.pc, got.pc)
+						}
+						// Failed executions consume all gas, blocks may charge
+						// the static gas of succeeding instructions before.
+						if wantStatus != statusFailed {
+							if want.gas != got.gas || want.refund != got.refund {
+								t.Errorf("unexpected gas, want %d (refund %d), got %d (refund %d)", want.gas, want.refund, got.gas, got.refund)
+							}
+							if !bytes.Equal(want.memory.store, got.memory.store) {
+								t.Errorf("unexpected memory, want %x, got %x", want.memory.store, got.memory.store)
+							}
+						}
+						if want.stack.len() != got.stack.len() {
+							t.Fatalf("unexpected stack size, want %d, got %d", want.stack.len(), got.stack.len())
+						}
+						for i := range want.stack.len() {
+							if want, got := want.stack.get(i), got.stack.get(i); !want.Eq(got) {
+								t.Errorf("unexpected stack element %d, want %v, got %v", i, want, got)
+							}
+						}
+					})
+				}
+			}
+		}
+	}
+}
+
+// getRandomBlockProgram produces a random program of instructions not
+// depending on the run context, including jumps to random destinations.
+func getRandomBlockProgram(rnd *rand.Rand) []byte {
+	ops := []vm.OpCode{
+		vm.ADD, vm.SUB, vm.MUL, vm.DIV, vm.LT, vm.ISZERO, vm.POP,
+		vm.DUP1, vm.DUP2, vm.DUP3, vm.SWAP1, vm.SWAP2, vm.MLOAD, vm.MSTORE,
+		vm.JUMPDEST, vm.JUMPDEST, vm.JUMP, vm.JUMPI, vm.GAS, vm.PC,
+		vm.CALLVALUE, vm.SHA3, vm.PUSH0, vm.PUSH1, vm.PUSH1, vm.PUSH2,
+		vm.STOP, vm.INVALID,
+	}
+	size := 16 + rnd.Intn(64)
+	code := []byte{}
+	for len(code) < size {
+		op := ops[rnd.Intn(len(ops))]
+		code = append(code, byte(op))
+		switch op {
+		case vm.PUSH1:
+			// Small values are likely jump destinations and memory offsets.
+			code = append(code, byte(rnd.Intn(size)))
+		case vm.PUSH2:
+			code = append(code, byte(rnd.Intn(4)), byte(rnd.Intn(256)))
+		}
+	}
+	return code
+}
+
+func BenchmarkBasicBlocks_ArithmeticLoop(b *testing.B) {
+	code := []byte{
+		byte(vm.PUSH2), 0x10, 0x00,
+		byte(vm.JUMPDEST),
+		byte(vm.PUSH1), 3,
+		byte(vm.PUSH1), 5,
+		byte(vm.MUL),
+		byte(vm.PUSH1), 7,
+		byte(vm.ADD),
+		byte(vm.PUSH1), 2,
+		byte(vm.SWAP1),
+		byte(vm.DIV),
+		byte(vm.POP),
+		byte(vm.PUSH1), 1,
+		byte(vm.SWAP1),
+		byte(vm.SUB),
+		byte(vm.DUP1),
+		byte(vm.PUSH1), 3,
+		byte(vm.JUMPI),
+		byte(vm.STOP),
+	}
+	converted := newConvertedCode(code, ConversionConfig{}, defaultGasPrices)
+	params := tosca.Parameters{
+		BlockParameters: tosca.BlockParameters{Revision: tosca.R13_Cancun},
+		Gas:             1 << 32,
+		Code:            code,
+	}
+
+	for _, withBlocks := range []bool{false, true} {
+		b.Run(fmt.Sprintf("blocks=%t", withBlocks), func(b *testing.B) {
+			var blocks basicBlocks
+			if withBlocks {
+				blocks = converted.blocks
+			}
+			for i := 0; i < b.N; i++ {
+				if _, err := run(config{}, params, converted.code, blocks); err != nil {
+					b.Fatalf("unexpected error: %v", err)
+				}
+			}
+		})
+	}
+}
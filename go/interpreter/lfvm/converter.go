@@ -16,7 +16,7 @@ import (
 
 	"github.com/0xsoniclabs/tosca/go/ct/common"
 	"github.com/0xsoniclabs/tosca/go/tosca"
-
+	"github.com/0xsoniclabs/tosca/go/tosca/eof"
 	"github.com/0xsoniclabs/tosca/go/tosca/vm"
 	lru "github.com/hashicorp/golang-lru/v2"
 )
@@ -31,46 +31,86 @@ type ConversionConfig struct {
 	CacheSize int
 	// WithSuperInstructions enables the use of super instructions.
 	WithSuperInstructions bool
+	// WithStaticJumps enables the resolution of constant jump destinations
+	// during the conversion and the marking of unreachable code. The resulting
+	// code may only be entered at its beginning.
+	WithStaticJumps bool
 }
 
 // Converter converts EVM code to LFVM code.
 type Converter struct {
 	config ConversionConfig
-	cache  *lru.Cache[tosca.Hash, Code]
+	prices *gasPrices // < the prices the static gas of basic blocks is based on
+	cache  *lru.Cache[tosca.Hash, *convertedCode]
+}
+
+// convertedCode is the result of a code conversion, comprising the LFVM code
+// and the basic blocks of this code. For EOF containers, no basic blocks are
+// computed and the properties of the container are retained instead.
+type convertedCode struct {
+	code   Code
+	blocks basicBlocks
+	eof    *eofCode
 }
 
 // NewConverter creates a new code converter with the provided configuration.
 func NewConverter(config ConversionConfig) (*Converter, error) {
+	return newConverter(config, defaultGasPrices)
+}
+
+// newConverter creates a new code converter computing the static gas costs of
+// basic blocks based on the given gas prices.
+func newConverter(config ConversionConfig, prices *gasPrices) (*Converter, error) {
 	if config.CacheSize == 0 {
 		config.CacheSize = (1 << 30) // = 1GiB
 	}
 
-	var cache *lru.Cache[tosca.Hash, Code]
+	var cache *lru.Cache[tosca.Hash, *convertedCode]
 	if config.CacheSize > 0 {
 		var err error
-		const instructionSize = int(unsafe.Sizeof(Instruction{}))
+		instructionSize := getInstructionSize(prices)
 		capacity := config.CacheSize / maxCachedCodeLength / instructionSize
-		cache, err = lru.New[tosca.Hash, Code](capacity)
+		cache, err = lru.New[tosca.Hash, *convertedCode](capacity)
 		if err != nil {
 			return nil, err
 		}
 	}
 	return &Converter{
 		config: config,
+		prices: prices,
 		cache:  cache,
 	}, nil
 }
 
+// getInstructionSize returns the number of bytes a cached instruction occupies
+// for the given prices. Each instruction is accompanied by an entry in the
+// basic block list and, in the worst case, starts a block with static gas sums
+// for all price tables.
+func getInstructionSize(prices *gasPrices) int {
+	return int(unsafe.Sizeof(Instruction{})+unsafe.Sizeof(basicBlock{})) +
+		len(prices.static)*int(unsafe.Sizeof(tosca.Gas(0)))
+}
+
 // Convert converts EVM code to LFVM code. If the provided code hash is not nil,
 // it is assumed to be a valid hash of the code and is used to cache the
 // conversion result. If the hash is nil, the conversion result is not cached.
 func (c *Converter) Convert(code []byte, codeHash *tosca.Hash) (Code, error) {
-	if len(code) > math.MaxUint16 {
-		return Code{}, errCodeSizeExceeded
+	res, err := c.convertWithBlocks(code, codeHash)
+	if err != nil {
+		return Code{}, err
+	}
+	return res.code, nil
+}
+
+// convertWithBlocks is like Convert but also provides the basic blocks of the
+// resulting code. Basic blocks are cached together with the code.
+func (c *Converter) convertWithBlocks(code []byte, codeHash *tosca.Hash) (*convertedCode, error) {
+	if len(code) > maxCodeLength {
+		return nil, errCodeSizeExceeded
 	}
 
 	if c.cache == nil || codeHash == nil {
-		return convert(code, c.config), nil
+		return newConvertedCode(code, c.config, c.prices), nil
 	}
 
 	res, exists := c.cache.Get(*codeHash)
@@ -78,8 +118,8 @@ func (c *Converter) Convert(code []byte, codeHash *tosca.Hash) (Code, error) {
 		return res, nil
 	}
 
-	res = convert(code, c.config)
-	if len(res) > maxCachedCodeLength {
+	res = newConvertedCode(code, c.config, c.prices)
+	if len(res.code) > maxCachedCodeLength {
 		return res, nil
 	}
 
@@ -87,6 +127,10 @@ func (c *Converter) Convert(code []byte, codeHash *tosca.Hash) (Code, error) {
 	return res, nil
 }
 
+// maxCodeLength is the maximum length of a code that can be converted. It is
+// limited by the range of the program counter.
+const maxCodeLength = math.MaxInt32
+
 // maxCachedCodeLength is the maximum length of a code in bytes that are
 // retained in the cache. To avoid excessive memory usage, longer codes are not
 // cached. The defined limit is the current limit for codes stored on the chain.
@@ -135,10 +179,34 @@ func (b *codeBuilder) padNoOpsUntil(pos int) {
 	b.nextPos = pos
 }
 
+// appendJumpTo appends JUMP_TO instructions skipping all positions up to the
+// given target, which are filled with NOOPs. If the builder is already at the
+// target position, nothing is appended.
+func (b *codeBuilder) appendJumpTo(target int) {
+	for b.nextPos < target {
+		distance := min(target-b.nextPos, math.MaxUint16)
+		b.appendOp(JUMP_TO, uint16(distance))
+		b.padNoOpsUntil(b.nextPos - 1 + distance)
+	}
+}
+
 func (b *codeBuilder) toCode() Code {
 	return b.code[0:b.nextPos]
 }
 
+func newConvertedCode(code []byte, options ConversionConfig, prices *gasPrices) *convertedCode {
+	if eof.HasMagic(code) {
+		if res := convertEof(code); res != nil {
+			return res
+		}
+	}
+	res := convert(code, options)
+	return &convertedCode{
+		code:   res,
+		blocks: computeBasicBlocks(res, prices),
+	}
+}
+
 func convert(code []byte, options ConversionConfig) Code {
 	return convertWithObserver(code, options, func(int, int) {})
 }
@@ -157,10 +225,7 @@ func convertWithObserver(
 		// Handle jump destinations
 		if code[i] == byte(vm.JUMPDEST) {
 			// Jump to the next jump destination and fill space with noops
-			if res.length() < i {
-				res.appendOp(JUMP_TO, uint16(i))
-			}
-			res.padNoOpsUntil(i)
+			res.appendJumpTo(i)
 			res.appendCode(JUMPDEST)
 			observer(i, i)
 			i++
@@ -172,7 +237,13 @@ func convertWithObserver(
 		inc := appendInstructions(&res, i, code, options.WithSuperInstructions)
 		i += inc + 1
 	}
-	return res.toCode()
+
+	result := res.toCode()
+	if options.WithStaticJumps {
+		resolveStaticJumps(result)
+		markUnreachableCode(result)
+	}
+	return result
 }
 
 func appendInstructions(res *codeBuilder, pos int, code []byte, withSuperInstructions bool) int {
@@ -187,11 +258,16 @@ func appendInstructions(res *codeBuilder, pos int, code []byte, withSuperInstruc
 	toscaOpCode := vm.OpCode(code[pos])
 
 	if toscaOpCode == vm.PC {
-		if pos > math.MaxUint16 {
-			res.appendCode(INVALID)
-			return 1
+		// PC instructions encode the offset between the position in the EVM
+		// code and the position in the LFVM code. If the offset exceeds the
+		// range of the argument, the upper bits are stored in a succeeding
+		// DATA instruction. Since the LFVM code is shorter than the EVM code
+		// by at least the offset, there is always space for it.
+		offset := pos - res.length()
+		res.appendOp(PC, uint16(offset))
+		if offset > math.MaxUint16 {
+			res.appendData(uint16(offset >> 16))
 		}
-		res.appendOp(PC, uint16(pos))
 		return 0
 	}
 
@@ -234,6 +310,12 @@ func appendInstructions(res *codeBuilder, pos int, code []byte, withSuperInstruc
 		return numBytes
 	}
 
+	// Instructions of the EVM Object Format are invalid in legacy code.
+	if vm.IsValidInEof(toscaOpCode) && !vm.IsValid(toscaOpCode) {
+		res.appendCode(INVALID)
+		return 0
+	}
+
 	// All the rest converts to a single instruction.
 	res.appendCode(OpCode(toscaOpCode))
 	return 0
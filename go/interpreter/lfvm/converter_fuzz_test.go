@@ -11,7 +11,6 @@
 package lfvm
 
 import (
-	"math"
 	"testing"
 
 	"github.com/0xsoniclabs/tosca/go/tosca/vm"
@@ -31,12 +30,10 @@ func FuzzLfvmConverter(f *testing.F) {
 		// (see https://eips.ethereum.org/EIPS/eip-170)
 		// EIP-3860 stablish maximum init code size to 49_152 bytes
 		// (see https://eips.ethereum.org/EIPS/eip-3860)
-		// Before EIP-3860, any size was allowed, but in the Fantom
-		// network anything larger than 2^16 was observed, which is
-		// also the limit for the conversion code (due to a 16-bit PC
-		// counter representation in the PC instruction). Thus, we test
-		// the code up to this level.
-		maxCodeSize := math.MaxUint16
+		// Before EIP-3860, any size was allowed, and in the Fantom
+		// network codes larger than 2^16 were observed. Thus, we test
+		// codes beyond this size.
+		maxCodeSize := 1 << 20
 		if len(toscaCode) > maxCodeSize {
 			t.Skip()
 		}
@@ -78,7 +75,10 @@ func FuzzLfvmConverter(f *testing.F) {
 
 			// Check that PC instructions point to the correct target.
 			if toscaOpCode == vm.PC {
-				target := int(lfvmCode[lfvmPos].arg)
+				target := lfvmPos + int(lfvmCode[lfvmPos].arg)
+				if lfvmPos+1 < len(lfvmCode) && lfvmCode[lfvmPos+1].opcode == DATA {
+					target += int(lfvmCode[lfvmPos+1].arg) << 16
+				}
 				if target != originalPos {
 					t.Errorf("Invalid PC target, wanted %d, got %d", originalPos, target)
 				}
@@ -90,14 +90,15 @@ func FuzzLfvmConverter(f *testing.F) {
 			}
 		}
 
-		// Check that JUMP_TO instructions point to their immediately succeeding JUMPDEST.
+		// Check that JUMP_TO instructions point to their immediately succeeding
+		// JUMPDEST or to a JUMP_TO continuing the chain.
 		for i := range lfvmCode {
 			if lfvmCode[i].opcode == JUMP_TO {
-				trg := int(lfvmCode[i].arg)
-				if trg < i {
+				trg := i + int(lfvmCode[i].arg)
+				if trg <= i {
 					t.Errorf("invalid JUMP_TO target from %d to %d", i, trg)
 				}
-				if trg >= len(lfvmCode) || lfvmCode[trg].opcode != JUMPDEST {
+				if trg >= len(lfvmCode) || (lfvmCode[trg].opcode != JUMPDEST && lfvmCode[trg].opcode != JUMP_TO) {
 					t.Fatalf("JUMP_TO target %d is not a JUMPDEST", trg)
 				}
 				for j := i + 1; j < trg; j++ {
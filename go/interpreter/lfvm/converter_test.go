@@ -18,7 +18,6 @@ import (
 	"slices"
 	"testing"
 	"time"
-	"unsafe"
 
 	"github.com/0xsoniclabs/tosca/go/tosca"
 	"github.com/0xsoniclabs/tosca/go/tosca/vm"
@@ -75,7 +74,7 @@ func TestConverter_LongExampleCode(t *testing.T) {
 	}
 }
 
-func TestConverter_CodeLargerThanMaxUint16ReturnsAnError(t *testing.T) {
+func TestConverter_CodeLargerThanMaxUint16IsSupported(t *testing.T) {
 	tests := map[string]struct {
 		codeSize int
 		err      error
@@ -90,7 +89,11 @@ func TestConverter_CodeLargerThanMaxUint16ReturnsAnError(t *testing.T) {
 		},
 		"large code": {
 			codeSize: math.MaxUint16 + 1,
-			err:      errCodeSizeExceeded,
+			err:      nil,
+		},
+		"very large code": {
+			codeSize: 1 << 20,
+			err:      nil,
 		},
 	}
 
@@ -134,7 +137,7 @@ func TestConverter_InputsAreCachedUsingHashAsKey(t *testing.T) {
 
 func TestConverter_CacheSizeLimitIsEnforced(t *testing.T) {
 	for _, limit := range []int{10, 100, 1000} {
-		const instructionSize = int(unsafe.Sizeof(Instruction{}))
+		instructionSize := getInstructionSize(defaultGasPrices)
 		converter, err := NewConverter(ConversionConfig{
 			CacheSize: limit * maxCachedCodeLength * instructionSize,
 		})
@@ -190,7 +193,7 @@ func TestConverter_ResultsAreCached(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to convert code: %v", err)
 	}
-	if got, found := converter.cache.Get(hash); !found || !slices.Equal(want, got) {
+	if got, found := converter.cache.Get(hash); !found || !slices.Equal(want, got.code) {
 		t.Errorf("converted code not added to cache")
 	}
 }
@@ -287,7 +290,11 @@ func TestConvertWithObserver_PreservesJumpDestLocations(t *testing.T) {
 
 		// Check that all operations are mapped to matching operations.
 		for evm, lfvm := range mapping {
-			if want, got := OpCode(code[evm]), res[lfvm].opcode; want != got {
+			want := OpCode(code[evm])
+			if isEofInstruction(want) {
+				want = INVALID
+			}
+			if got := res[lfvm].opcode; want != got {
 				t.Errorf("Expected %v, got %v", want, got)
 			}
 		}
@@ -316,23 +323,68 @@ func TestConvertWithObserver_PreservesJumpDestLocations(t *testing.T) {
 	}
 }
 
-func TestConvert_ProgramCounterBeyond16bitAreConvertedIntoInvalidInstructions(t *testing.T) {
+func TestConvert_ProgramCounterBeyond16bitIsPreserved(t *testing.T) {
+	// PC instructions are separated by PUSH32 instructions, each increasing
+	// the offset between EVM and LFVM positions by 16.
 	max := math.MaxUint16
-	positions := []int{0, 1, max / 2, max - 1, max, max + 1}
-	code := make([]byte, max+2)
+	positions := []int{0, 1, max / 2, max, max + 1, 3 * max, 3*max + 1}
+	code := []byte{}
 	for _, pos := range positions {
-		code[pos] = byte(vm.PC)
+		for len(code)+33 <= pos {
+			code = append(code, byte(vm.PUSH32))
+			code = append(code, make([]byte, 32)...)
+		}
+		for len(code) < pos {
+			code = append(code, byte(vm.STOP))
+		}
+		code = append(code, byte(vm.PC))
+	}
+
+	mapping := map[int]int{}
+	res := convertWithObserver(code, ConversionConfig{}, func(evm, lfvm int) {
+		mapping[evm] = lfvm
+	})
+	if offset := positions[len(positions)-1] - mapping[positions[len(positions)-1]]; offset <= max {
+		t.Fatalf("offset %d does not exceed the range of the argument", offset)
 	}
-	res := convert(code, ConversionConfig{})
 
 	for _, pos := range positions {
-		want := PC
-		if pos > max {
-			want = INVALID
+		lfvmPos := mapping[pos]
+		if res[lfvmPos].opcode != PC {
+			t.Fatalf("unexpected instruction at position %d, got %v", lfvmPos, res[lfvmPos].opcode)
 		}
-		if got := res[pos].opcode; want != got {
-			t.Errorf("Expected %v at position %d, got %v", want, pos, got)
+
+		ctxt := context{code: res, pc: int32(lfvmPos), stack: NewStack()}
+		opPc(&ctxt)
+		if want, got := uint64(pos), ctxt.stack.peek().Uint64(); want != got {
+			t.Errorf("unexpected PC value, wanted %d, got %d", want, got)
 		}
+		ReturnStack(ctxt.stack)
+	}
+}
+
+func TestConvert_LargeGapsAreSkippedByChainedJumpTos(t *testing.T) {
+	code := make([]byte, 3*math.MaxUint16)
+	for i := range code {
+		code[i] = byte(vm.PUSH1)
+	}
+	code[len(code)-1] = byte(vm.JUMPDEST)
+	res := convert(code, ConversionConfig{})
+
+	// Follow the chain of JUMP_TO instructions to the JUMPDEST.
+	pos, jumps := len(code)/2, 0
+	for res[pos].opcode == NOOP {
+		pos--
+	}
+	for res[pos].opcode == JUMP_TO {
+		pos += int(res[pos].arg)
+		jumps++
+	}
+	if want, got := len(code)-1, pos; want != got {
+		t.Errorf("unexpected end of JUMP_TO chain, wanted %d, got %d", want, got)
+	}
+	if jumps < 2 {
+		t.Errorf("expected a chain of JUMP_TO instructions, got %d jumps", jumps)
 	}
 }
 
@@ -344,7 +396,11 @@ func TestConvert_BaseInstructionsAreConvertedToEquivalents(t *testing.T) {
 		t.Run(op.String(), func(t *testing.T) {
 			code := []byte{byte(op)}
 			res := convert(code, config)
-			if want, got := op, res[0].opcode; want != got {
+			want := op
+			if isEofInstruction(op) {
+				want = INVALID // < EOF instructions are invalid in legacy code
+			}
+			if got := res[0].opcode; want != got {
 				t.Errorf("Expected %v, got %v", want, got)
 			}
 		})
@@ -415,11 +471,11 @@ func TestConvert_AllJumpToOperationsPointToSubsequentJumpdest(t *testing.T) {
 		for i, instruction := range res {
 			if instruction.opcode == JUMP_TO {
 				counter++
-				trg := instruction.arg
-				if trg <= uint16(i) {
+				trg := i + int(instruction.arg)
+				if trg <= i {
 					t.Errorf("JUMP_TO %d points to preceding position %d", trg, i)
 				}
-				if trg >= uint16(len(res)) {
+				if trg >= len(res) {
 					t.Fatalf("JUMP_TO %d out of bounds", trg)
 				}
 				if res[trg].opcode != JUMPDEST {
@@ -428,7 +484,7 @@ func TestConvert_AllJumpToOperationsPointToSubsequentJumpdest(t *testing.T) {
 
 				// Everything from the JUMP_TO to to the jump destination is a
 				// NOOP instruction.
-				for pos := i + 1; pos < int(trg); pos++ {
+				for pos := i + 1; pos < trg; pos++ {
 					if res[pos].opcode != NOOP {
 						t.Errorf("Expected NOOP at position %d, got %v", pos, res[pos].opcode)
 					}
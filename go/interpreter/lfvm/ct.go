@@ -22,27 +22,45 @@ import (
 )
 
 func NewConformanceTestingTarget() ct.Evm {
-
 	// Can only fail for invalid configuration. Configuration is hardcoded.
-	sanctionedVm, _ := NewInterpreter(Config{})
+	target, _ := NewConformanceTestingTargetWithGasSchedule(nil)
+	return target
+}
+
+// NewConformanceTestingTargetWithGasSchedule creates a conformance testing
+// target charging the prices of the given gas schedule. It is to be checked
+// against a specification using the same schedule (see
+// spc.NewSpecificationWithGasSchedule).
+func NewConformanceTestingTargetWithGasSchedule(schedule *tosca.GasSchedule) (ct.Evm, error) {
+	sanctionedVm, err := NewInterpreter(Config{GasSchedule: schedule})
+	if err != nil {
+		return nil, err
+	}
 
 	// can only fail for non-positive size
-	cache, _ := lru.New[[32]byte, *pcMap](4096)
+	cache, _ := lru.New[pcMapKey, *pcMap](4096)
 
 	return &ctAdapter{
 		vm:         sanctionedVm,
 		pcMapCache: cache,
-	}
+	}, nil
 }
 
 type ctAdapter struct {
 	vm         *lfvm
-	pcMapCache *lru.Cache[[32]byte, *pcMap]
+	pcMapCache *lru.Cache[pcMapKey, *pcMap]
+}
+
+// pcMapKey identifies the program counter map of a code, which depends on
+// whether the code is executed as an EOF container.
+type pcMapKey struct {
+	hash  [32]byte
+	isEof bool
 }
 
 func (a *ctAdapter) StepN(state *st.State, numSteps int) (*st.State, error) {
 	params := utils.ToVmParameters(state)
-	if params.Revision > newestSupportedRevision {
+	if params.Revision > newestSupportedRevision && params.Revision != tosca.R98_Experimental {
 		return state, &tosca.ErrUnsupportedRevision{Revision: params.Revision}
 	}
 
@@ -51,7 +69,7 @@ func (a *ctAdapter) StepN(state *st.State, numSteps int) (*st.State, error) {
 		return state, nil
 	}
 
-	converted, err := a.vm.converter.Convert(
+	converted, err := a.vm.converter.convertWithBlocks(
 		params.Code,
 		params.CodeHash,
 	)
@@ -59,7 +77,13 @@ func (a *ctAdapter) StepN(state *st.State, numSteps int) (*st.State, error) {
 		return &st.State{}, fmt.Errorf("failed to convert code: %w", err)
 	}
 
-	pcMap := a.getPcMap(state.Code)
+	// EOF containers are legacy code starting with an invalid instruction in
+	// revisions without EOF support.
+	if converted.eof != nil && params.Revision < tosca.R98_Experimental {
+		converted = &convertedCode{code: convert(params.Code, a.vm.converter.config)}
+	}
+
+	pcMap := a.getPcMap(state.Code, converted)
 
 	memory := convertCtMemoryToLfvmMemory(state.Memory)
 
@@ -72,11 +96,25 @@ func (a *ctAdapter) StepN(state *st.State, numSteps int) (*st.State, error) {
 		refund:       tosca.Gas(state.GasRefund),
 		stack:        convertCtStackToLfvmStack(state.Stack),
 		memory:       memory,
-		code:         converted,
+		code:         converted.code,
+		prices:       a.vm.config.prices,
 		returnData:   state.LastCallReturnData.ToBytes(),
 		withShaCache: a.vm.config.WithShaCache,
 	}
 
+	if converted.eof != nil {
+		section, _ := state.Code.GetSection(int(state.Pc))
+		ctxt.eof = converted.eof
+		ctxt.section = int32(section)
+		for _, pos := range state.ReturnStack {
+			section, _ := state.Code.GetSection(int(pos))
+			ctxt.returnStack = append(ctxt.returnStack, returnFrame{
+				section: int32(section),
+				pc:      int32(pcMap.evmToLfvm[pos]) - 1, // < the position of the CALLF instruction
+			})
+		}
+	}
+
 	defer func() {
 		ReturnStack(ctxt.stack)
 	}()
@@ -91,7 +129,14 @@ func (a *ctAdapter) StepN(state *st.State, numSteps int) (*st.State, error) {
 	state.Status = convertLfvmStatusToCtStatus(status)
 
 	if status == statusRunning {
-		state.Pc = pcMap.lfvmToEvm[ctxt.pc]
+		// The CT state is limited to 16-bit program counters.
+		state.Pc = uint16(pcMap.lfvmToEvm[ctxt.pc])
+	}
+	if converted.eof != nil {
+		state.ReturnStack = state.ReturnStack[:0]
+		for _, frame := range ctxt.returnStack {
+			state.ReturnStack = append(state.ReturnStack, uint16(pcMap.lfvmToEvm[frame.pc+1]))
+		}
 	}
 
 	state.Gas = ctxt.gas
@@ -106,56 +151,89 @@ func (a *ctAdapter) StepN(state *st.State, numSteps int) (*st.State, error) {
 	return state, nil
 }
 
-func (a *ctAdapter) getPcMap(code *st.Code) *pcMap {
-	hash := code.Hash()
-	pcMap, found := a.pcMapCache.Get(hash)
+func (a *ctAdapter) getPcMap(code *st.Code, converted *convertedCode) *pcMap {
+	key := pcMapKey{hash: code.Hash(), isEof: converted.eof != nil}
+	pcMap, found := a.pcMapCache.Get(key)
 	if found {
 		return pcMap
 	}
-	byteCode := code.Copy()
-	pcMap = genPcMap(byteCode)
-	a.pcMapCache.Add(hash, pcMap)
+	if converted.eof != nil {
+		pcMap = genEofPcMap(code, converted.code)
+	} else {
+		pcMap = genPcMap(code.Copy())
+	}
+	a.pcMapCache.Add(key, pcMap)
 	return pcMap
 }
 
 // pcMap is a bidirectional map to map program counters between evm <-> lfvm.
 type pcMap struct {
-	evmToLfvm []uint16
-	lfvmToEvm []uint16
+	evmToLfvm []uint32
+	lfvmToEvm []uint32
 }
 
 // genPcMap creates a bidirectional program counter map for a given code,
 // allowing mapping from a program counter in evm code to lfvm and vice versa.
 func genPcMap(code []byte) *pcMap {
-	evmToLfvm := make([]uint16, len(code)+1)
-	lfvmToEvm := make([]uint16, len(code)+1)
+	evmToLfvm := make([]uint32, len(code)+1)
+	lfvmToEvm := make([]uint32, len(code)+1)
 
 	config := ConversionConfig{
 		WithSuperInstructions: false,
 	}
 	res := convertWithObserver(code, config, func(evm, lfvm int) {
-		evmToLfvm[evm] = uint16(lfvm)
-		lfvmToEvm[lfvm] = uint16(evm)
+		evmToLfvm[evm] = uint32(lfvm)
+		lfvmToEvm[lfvm] = uint32(evm)
 	})
 
 	// A program counter may correctly point to the position after the last
 	// instruction, which would lead to an implicit STOP.
-	evmToLfvm[len(code)] = uint16(len(res))
+	evmToLfvm[len(code)] = uint32(len(res))
 
 	// The LFVM code could also be longer than the input code if extra padding
 	// of truncated PUSH instructions has been added.
 	if len(res)+1 > len(lfvmToEvm) {
-		lfvmToEvm = append(lfvmToEvm, make([]uint16, len(res)+1-len(lfvmToEvm))...)
+		lfvmToEvm = append(lfvmToEvm, make([]uint32, len(res)+1-len(lfvmToEvm))...)
 	}
-	lfvmToEvm[len(res)] = uint16(len(code))
+	lfvmToEvm[len(res)] = uint32(len(code))
 
 	// Locations pointing to JUMP_TO instructions in LFVM need to be updated to
-	// the position of the jump target.
-	for i := range res {
+	// the position of the jump target. Since JUMP_TO instructions may be
+	// chained, the code is processed backwards to resolve the final targets.
+	for i := len(res) - 1; i >= 0; i-- {
 		if res[i].opcode == JUMP_TO {
-			lfvmToEvm[i] = res[i].arg
+			lfvmToEvm[i] = lfvmToEvm[i+int(res[i].arg)]
+		}
+	}
+
+	return &pcMap{
+		evmToLfvm: evmToLfvm,
+		lfvmToEvm: lfvmToEvm,
+	}
+}
+
+// genEofPcMap creates a bidirectional program counter map for an EOF
+// container and its converted code sections. The instructions of the code
+// sections are converted in order, each into a single instruction followed
+// by DATA instructions for its arguments.
+func genEofPcMap(code *st.Code, converted Code) *pcMap {
+	evmToLfvm := make([]uint32, code.Length()+1)
+	lfvmToEvm := make([]uint32, len(converted)+1)
+
+	lfvm := 0
+	for evm := range code.Length() {
+		if !code.IsCode(evm) {
+			continue
+		}
+		for converted[lfvm].opcode == DATA {
+			lfvm++
 		}
+		evmToLfvm[evm] = uint32(lfvm)
+		lfvmToEvm[lfvm] = uint32(evm)
+		lfvm++
 	}
+	evmToLfvm[code.Length()] = uint32(len(converted))
+	lfvmToEvm[len(converted)] = uint32(code.Length())
 
 	return &pcMap{
 		evmToLfvm: evmToLfvm,
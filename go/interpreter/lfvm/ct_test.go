@@ -19,8 +19,10 @@ import (
 
 	"github.com/0xsoniclabs/tosca/go/ct"
 	cc "github.com/0xsoniclabs/tosca/go/ct/common"
+	"github.com/0xsoniclabs/tosca/go/ct/spc"
 	"github.com/0xsoniclabs/tosca/go/ct/st"
 	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/eof"
 	"github.com/0xsoniclabs/tosca/go/tosca/vm"
 )
 
@@ -55,6 +57,82 @@ func TestCtAdapter_Add(t *testing.T) {
 	}
 }
 
+func TestCtAdapter_ConformsToSpecificationWithGasSchedule(t *testing.T) {
+	schedule := &tosca.GasSchedule{
+		Static: []tosca.StaticGasOverride{
+			{OpCode: vm.ADD, Revision: tosca.R07_Istanbul, Price: 1},
+			{OpCode: vm.ADD, Revision: tosca.R13_Cancun, Price: 7},
+			{OpCode: vm.SLOAD, Revision: tosca.R09_Berlin, Price: 4},
+		},
+		Dynamic: []tosca.DynamicGasOverride{
+			{Kind: tosca.WarmSloadGas, Revision: tosca.R10_London, Price: 10},
+			{Kind: tosca.ColdSloadGas, Revision: tosca.R09_Berlin, Price: 500},
+			{Kind: tosca.WarmAccountAccessGas, Revision: tosca.R09_Berlin, Price: 7},
+			{Kind: tosca.ColdAccountAccessGas, Revision: tosca.R10_London, Price: 300},
+			{Kind: tosca.WarmSstoreGas, Revision: tosca.R09_Berlin, Price: 20},
+			{Kind: tosca.WarmSstoreGas, Revision: tosca.R13_Cancun, Price: 1000},
+			{Kind: tosca.ColdSstoreSurchargeGas, Revision: tosca.R09_Berlin, Price: 50},
+		},
+	}
+	spec := spc.NewSpecificationWithGasSchedule(spc.Spec, schedule)
+	evm, err := NewConformanceTestingTargetWithGasSchedule(schedule)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	key := cc.NewU256(5)
+	address := cc.NewU256(42)
+	inputs := map[string]struct {
+		op    vm.OpCode
+		stack []cc.U256
+		warm  bool
+	}{
+		"add":                {op: vm.ADD, stack: []cc.U256{cc.NewU256(1), cc.NewU256(2)}},
+		"sload cold":         {op: vm.SLOAD, stack: []cc.U256{key}},
+		"sload warm":         {op: vm.SLOAD, stack: []cc.U256{key}, warm: true},
+		"mul":                {op: vm.MUL, stack: []cc.U256{cc.NewU256(1), cc.NewU256(2)}},
+		"balance cold":       {op: vm.BALANCE, stack: []cc.U256{address}},
+		"balance warm":       {op: vm.BALANCE, stack: []cc.U256{address}, warm: true},
+		"sstore cold add":    {op: vm.SSTORE, stack: []cc.U256{cc.NewU256(1), key}},
+		"sstore warm assign": {op: vm.SSTORE, stack: []cc.U256{cc.NewU256(0), key}, warm: true},
+	}
+	gasLevels := []tosca.Gas{
+		0, 1, 2, 3, 4, 5, 6, 7, 8, 13, 14, 15, 99, 100, 103, 104, 300, 301, 504, 505,
+		2100, 2101, 2300, 2301, 2320, 2321, 3300, 3301, 5000, 20049, 20050, 22100, 22101,
+	}
+
+	for name, input := range inputs {
+		for _, revision := range cc.AllSupportedRevisions() {
+			for _, gas := range gasLevels {
+				state := st.NewState(st.NewCode([]byte{byte(input.op)}))
+				state.Status = st.Running
+				state.Revision = revision
+				state.Gas = gas
+				state.Stack = st.NewStack(input.stack...)
+				state.Storage = st.NewStorageBuilder().SetWarm(key, input.warm).Build()
+				if input.warm {
+					state.Accounts.MarkWarm(address.Bytes20be())
+				}
+
+				rules := spec.GetRulesFor(state)
+				if len(rules) == 0 {
+					t.Fatalf("no rule for %s in %v with gas %d", name, revision, gas)
+				}
+				want := state.Clone()
+				rules[0].Effect.Apply(want)
+
+				got, err := evm.StepN(state.Clone(), 1)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !want.Eq(got) {
+					t.Errorf("unexpected result for %s in %v with gas %d, want %v, got %v", name, revision, gas, want, got)
+				}
+			}
+		}
+	}
+}
+
 func TestCtAdapter_Interface(t *testing.T) {
 	// Compile time check that ctAdapter implements the st.Evm interface.
 	var _ ct.Evm = &ctAdapter{}
@@ -95,16 +173,26 @@ func TestCtAdapter_ReturnsErrorForUnsupportedRevisions(t *testing.T) {
 	}
 }
 
-func TestCtAdapter_ReturnsErrorIfCodeSizeExceeded(t *testing.T) {
-	s := st.NewState(st.NewCode(make([]byte, math.MaxUint16+1)))
+func TestCtAdapter_SupportsCodeLargerThanMaxUint16(t *testing.T) {
+	code := make([]byte, math.MaxUint16+1)
+	code[0] = byte(vm.PUSH1)
+	code[1] = 0x42
+	s := st.NewState(st.NewCode(code))
 	s.Status = st.Running
 	s.Revision = tosca.R07_Istanbul
+	s.Gas = 100
+	s.Stack = st.NewStack()
 
 	c := NewConformanceTestingTarget()
-	_, err := c.StepN(s, 1)
-
-	if !errors.Is(err, errCodeSizeExceeded) {
-		t.Errorf("unexpected error, wanted %v, got %v", errCodeSizeExceeded, err)
+	got, err := c.StepN(s, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want, got := uint16(2), got.Pc; want != got {
+		t.Errorf("unexpected program counter, wanted %d, got %d", want, got)
+	}
+	if want, got := 1, got.Stack.Size(); want != got {
+		t.Errorf("unexpected stack size, wanted %d, got %d", want, got)
 	}
 }
 
@@ -162,6 +250,81 @@ func TestCtAdapter_FillsReturnDataOnResultingState(t *testing.T) {
 	}
 }
 
+func TestCtAdapter_TracksReturnStackOfEofCode(t *testing.T) {
+	container := &eof.Container{
+		Types: []eof.FunctionType{
+			{Outputs: eof.NonReturning},
+			{Outputs: 0},
+		},
+		Code: [][]byte{
+			{byte(vm.PUSH0), byte(vm.CALLF), 0, 1, byte(vm.STOP)},
+			{byte(vm.RETF)},
+		},
+		Data: []byte{},
+	}
+	container.Types[0].MaxStackIncrease = 1
+	code := st.NewCode(container.Bytes())
+	if !code.IsEof() {
+		t.Fatalf("test container is invalid")
+	}
+	main, function := code.GetSectionStart(0), code.GetSectionStart(1)
+
+	s := st.NewState(code)
+	s.Revision = tosca.R98_Experimental
+	s.Gas = 100
+	s.Pc = uint16(main)
+	s.Stack = st.NewStack()
+	defer s.Stack.Release()
+	c := NewConformanceTestingTarget()
+
+	steps := []struct {
+		pc          int
+		returnStack []uint16
+	}{
+		{main + 1, nil},
+		{function, []uint16{uint16(main + 4)}},
+		{main + 4, nil},
+	}
+	for _, step := range steps {
+		var err error
+		s, err = c.StepN(s, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if s.Status != st.Running {
+			t.Fatalf("unexpected status %v", s.Status)
+		}
+		if want, got := uint16(step.pc), s.Pc; want != got {
+			t.Errorf("unexpected pc, wanted %d, got %d", want, got)
+		}
+		if want, got := step.returnStack, s.ReturnStack; len(want) != len(got) || (len(want) > 0 && !reflect.DeepEqual(want, got)) {
+			t.Errorf("unexpected return stack, wanted %v, got %v", want, got)
+		}
+	}
+}
+
+func TestCtAdapter_EofCodeIsLegacyCodeInEarlierRevisions(t *testing.T) {
+	container := &eof.Container{
+		Types: []eof.FunctionType{{Outputs: eof.NonReturning}},
+		Code:  [][]byte{{byte(vm.STOP)}},
+		Data:  []byte{},
+	}
+	s := st.NewState(st.NewCode(container.Bytes()))
+	s.Revision = tosca.R15_Osaka
+	s.Gas = 100
+	s.Stack = st.NewStack()
+	defer s.Stack.Release()
+
+	c := NewConformanceTestingTarget()
+	s, err := c.StepN(s, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want, got := st.Failed, s.Status; want != got {
+		t.Errorf("unexpected status, wanted %v, got %v", want, got)
+	}
+}
+
 ////////////////////////////////////////////////////////////
 // ct -> lfvm
 
@@ -194,8 +357,8 @@ func TestConvertToLfvm_StatusCodeFailsOnUnknownStatus(t *testing.T) {
 func TestConvertToLfvm_Pc(t *testing.T) {
 	tests := map[string][]struct {
 		evmCode []byte
-		evmPc   uint16
-		lfvmPc  uint16
+		evmPc   uint32
+		lfvmPc  uint32
 	}{
 		"empty":        {{}},
 		"pos-0":        {{[]byte{byte(vm.STOP)}, 0, 0}},
@@ -251,7 +414,7 @@ func TestConvertToLfvm_Code(t *testing.T) {
 			Code{Instruction{PUSH1, 0x0400},
 				Instruction{JUMP, 0x0000},
 				Instruction{INVALID, 0x0000},
-				Instruction{JUMP_TO, 0x0004},
+				Instruction{JUMP_TO, 0x0001},
 				Instruction{JUMPDEST, 0x0000}}}},
 		"jumpdest": {{[]byte{
 			byte(vm.PUSH3), 0x00, 0x00, 0x06,
@@ -262,7 +425,7 @@ func TestConvertToLfvm_Code(t *testing.T) {
 				Instruction{DATA, 0x0600},
 				Instruction{JUMP, 0x0000},
 				Instruction{INVALID, 0x0000},
-				Instruction{JUMP_TO, 0x0006},
+				Instruction{JUMP_TO, 0x0002},
 				Instruction{NOOP, 0x0000},
 				Instruction{JUMPDEST, 0x0000}}}},
 		"push2": {{[]byte{byte(vm.PUSH2), 0xBA, 0xAD}, Code{Instruction{PUSH2, 0xBAAD}}}},
@@ -486,8 +649,8 @@ func TestConvertToLfvm_Stack(t *testing.T) {
 func TestConvertToCt_Pc(t *testing.T) {
 	tests := map[string][]struct {
 		evmCode []byte
-		lfvmPc  uint16
-		evmPc   uint16
+		lfvmPc  uint32
+		evmPc   uint32
 	}{
 		"empty":        {{}},
 		"pos-0":        {{[]byte{byte(vm.STOP)}, 0, 0}},
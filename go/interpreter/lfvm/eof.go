@@ -0,0 +1,459 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package lfvm
+
+import (
+	"bytes"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/eof"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+	"github.com/holiman/uint256"
+)
+
+// eofCode summarizes the properties of a converted EOF container required for
+// its execution. EOF code is only executed in the tosca.R98_Experimental
+// revision.
+type eofCode struct {
+	container *eof.Container
+	// sections lists the start positions of the code sections in the
+	// converted code.
+	sections []int32
+	// isRuntime is true if the container is a valid runtime container.
+	isRuntime bool
+	// isInitcode is true if the container is a valid initcode container.
+	isInitcode bool
+}
+
+// maxReturnStackSize is the maximum number of nested CALLF calls.
+const maxReturnStackSize = 1024
+
+// returnFrame is an entry of the return stack of CALLF calls.
+type returnFrame struct {
+	section int32 // < the section to return to
+	pc      int32 // < the position of the CALLF instruction to return to
+}
+
+// convertEof converts the given code into LFVM code if it is a valid EOF
+// container. The code sections are converted individually and concatenated.
+// Immediate arguments of EOF instructions are converted into instruction
+// arguments. In particular, the arguments of relative jumps are the targets
+// in the converted code, relative to the start of the enclosing section. If
+// the code is not a valid EOF container, nil is returned.
+func convertEof(code []byte) *convertedCode {
+	container, err := eof.Parse(code)
+	if err != nil {
+		return nil
+	}
+	info := &eofCode{
+		container:  container,
+		isRuntime:  container.Validate(eof.RuntimeContainer) == nil,
+		isInitcode: container.Validate(eof.InitcodeContainer) == nil,
+	}
+	if !info.isRuntime && !info.isInitcode {
+		return nil
+	}
+
+	size := 0
+	for _, section := range container.Code {
+		size += len(section)
+	}
+	res := newCodeBuilder(size)
+	info.sections = make([]int32, len(container.Code))
+	for i, section := range container.Code {
+		info.sections[i] = int32(res.length())
+		appendEofSection(&res, section)
+	}
+	return &convertedCode{code: res.toCode(), eof: info}
+}
+
+// appendEofSection converts a single validated EOF code section.
+func appendEofSection(res *codeBuilder, code []byte) {
+	start := res.length()
+	positions := make([]int, len(code)+1)
+	jumps := []int{} // < positions of converted relative jumps
+
+	for i := 0; i < len(code); {
+		positions[i] = res.length() - start
+		op := vm.OpCode(code[i])
+		maxIndex := byte(0)
+		switch op {
+		case vm.RJUMP, vm.RJUMPI:
+			jumps = append(jumps, res.length())
+			res.appendOp(OpCode(op), uint16(i))
+		case vm.RJUMPV:
+			jumps = append(jumps, res.length())
+			maxIndex = code[i+1]
+			count := int(maxIndex) + 1
+			res.appendOp(RJUMPV, uint16(count))
+			for range count {
+				res.appendData(uint16(i))
+			}
+		case vm.CALLF, vm.JUMPF, vm.DATALOADN:
+			res.appendOp(OpCode(op), uint16(code[i+1])<<8|uint16(code[i+2]))
+		case vm.EOFCREATE, vm.RETURNCONTRACT:
+			res.appendOp(OpCode(op), uint16(code[i+1]))
+		default:
+			if vm.PUSH1 <= op && op <= vm.PUSH32 {
+				appendInstructions(res, i, code, false)
+			} else {
+				res.appendCode(OpCode(op))
+			}
+		}
+		i += 1 + eof.GetImmediateSize(op, maxIndex)
+	}
+
+	// Resolve the jump targets, which are relative to the succeeding
+	// instruction in the EVM code, into positions in the converted section.
+	// The arguments of the jumps have been set to their EVM positions above.
+	for _, pos := range jumps {
+		evmPos := int(res.code[pos].arg)
+		if res.code[pos].opcode == RJUMPV {
+			evmPos = int(res.code[pos+1].arg)
+		}
+		targets := eof.GetRelativeJumpTargets(code, evmPos)
+		next := evmPos + 1 + eof.GetImmediateSize(vm.OpCode(code[evmPos]), code[evmPos+1])
+		if res.code[pos].opcode == RJUMPV {
+			for i, target := range targets {
+				res.code[pos+1+i].arg = uint16(positions[next+target])
+			}
+		} else {
+			res.code[pos].arg = uint16(positions[next+targets[0]])
+		}
+	}
+}
+
+// --- EOF instructions ---
+
+func opRJump(c *context) error {
+	return eofJumpTo(c, c.code[c.pc].arg)
+}
+
+func opRJumpi(c *context) error {
+	if c.stack.pop().IsZero() {
+		return nil
+	}
+	return eofJumpTo(c, c.code[c.pc].arg)
+}
+
+func opRJumpv(c *context) error {
+	count := uint64(c.code[c.pc].arg)
+	index := c.stack.pop()
+	if !index.IsUint64() || index.Uint64() >= count {
+		c.pc += int32(count)
+		return nil
+	}
+	return eofJumpTo(c, c.code[c.pc+1+int32(index.Uint64())].arg)
+}
+
+// eofJumpTo continues the execution at the given position of the current code
+// section. Backward jumps check whether the execution has been canceled, which
+// bounds the time until a cancellation takes effect.
+func eofJumpTo(c *context, target uint16) error {
+	pos := c.eof.sections[c.section] + int32(target)
+	if pos <= c.pc && c.params.IsCanceled() {
+		return tosca.ErrCanceled
+	}
+	c.pc = pos - 1 // < the program counter is incremented after the instruction
+	return nil
+}
+
+func opCallf(c *context) error {
+	if len(c.returnStack) >= maxReturnStackSize {
+		return errStackOverflow
+	}
+	target := int32(c.code[c.pc].arg)
+	if err := checkEofStackIncrease(c, target); err != nil {
+		return err
+	}
+	c.returnStack = append(c.returnStack, returnFrame{section: c.section, pc: c.pc})
+	enterSection(c, target)
+	return nil
+}
+
+func opJumpf(c *context) error {
+	target := int32(c.code[c.pc].arg)
+	if err := checkEofStackIncrease(c, target); err != nil {
+		return err
+	}
+	enterSection(c, target)
+	return nil
+}
+
+func opRetf(c *context) error {
+	// Valid containers only return from sections entered by CALLF, an empty
+	// return stack can only be encountered in manipulated states.
+	if len(c.returnStack) == 0 {
+		return errStackUnderflow
+	}
+	frame := c.returnStack[len(c.returnStack)-1]
+	c.returnStack = c.returnStack[:len(c.returnStack)-1]
+	c.section = frame.section
+	c.pc = frame.pc
+	return nil
+}
+
+// checkEofStackIncrease checks that the maximum stack growth of the given code
+// section does not exceed the stack limit.
+func checkEofStackIncrease(c *context, section int32) error {
+	increase := int(c.eof.container.Types[section].MaxStackIncrease)
+	if c.stack.len()+increase > maxStackSize {
+		return errStackOverflow
+	}
+	return nil
+}
+
+func enterSection(c *context, section int32) {
+	c.section = section
+	c.pc = c.eof.sections[section] - 1 // < the program counter is incremented after the instruction
+}
+
+func opDataLoad(c *context) {
+	top := c.stack.peek()
+	top.SetBytes(getData(c.eof.container.Data, top, 32))
+}
+
+func opDataLoadN(c *context) {
+	offset := uint256.NewInt(uint64(c.code[c.pc].arg))
+	c.stack.pushUndefined().SetBytes(getData(c.eof.container.Data, offset, 32))
+}
+
+func opDataSize(c *context) {
+	c.stack.pushUndefined().SetUint64(uint64(len(c.eof.container.Data)))
+}
+
+func opReturnDataLoad(c *context) {
+	top := c.stack.peek()
+	top.SetBytes(getData(c.returnData, top, 32))
+}
+
+func opReturnContract(c *context) error {
+	offset := c.stack.pop()
+	size := c.stack.pop()
+	aux, err := c.memory.getSlice(offset, size, c)
+	if err != nil {
+		return err
+	}
+	container := c.eof.container.Containers[c.code[c.pc].arg]
+	deployed, err := eof.AppendAuxData(container, aux)
+	if err != nil {
+		return err
+	}
+	c.returnData = deployed
+	return nil
+}
+
+func opEofCreate(c *context) error {
+	if c.params.Static {
+		return errStaticContextViolation
+	}
+
+	var (
+		value  = c.stack.pop()
+		salt   = c.stack.pop().Bytes32()
+		offset = c.stack.pop()
+		size   = c.stack.pop()
+	)
+
+	input, err := c.memory.getSlice(offset, size, c)
+	if err != nil {
+		return err
+	}
+
+	// Charge for hashing the initcode container to compute the target address.
+	initCode := c.eof.container.Containers[c.code[c.pc].arg]
+	words := tosca.SizeInWords(uint64(len(initCode)))
+	if err := c.useGas(tosca.Gas(6 * words)); err != nil {
+		return err
+	}
+
+	if !value.IsZero() {
+		balance := c.context.GetBalance(c.params.Recipient)
+		if value.Gt(new(uint256.Int).SetBytes(balance[:])) {
+			c.stack.pushUndefined().Clear()
+			c.returnData = nil
+			return nil
+		}
+	}
+
+	nestedCallGas := c.gas - c.gas/64
+	res, err := c.context.Call(tosca.EofCreate, tosca.CallParameters{
+		Sender:   c.params.Recipient,
+		Value:    tosca.Value(value.Bytes32()),
+		Input:    bytes.Clone(input),
+		Gas:      nestedCallGas,
+		Salt:     salt,
+		InitCode: tosca.Code(initCode),
+	})
+
+	// A cancellation during the nested call aborts this execution as well.
+	if c.params.IsCanceled() {
+		return tosca.ErrCanceled
+	}
+
+	success := c.stack.pushUndefined()
+	if !res.Success || err != nil {
+		success.Clear()
+	} else {
+		success.SetBytes20(res.CreatedAddress[:])
+	}
+
+	if !res.Success && err == nil {
+		c.returnData = res.Output
+	} else {
+		c.returnData = nil
+	}
+
+	c.gas -= nestedCallGas
+	c.gas += res.GasLeft
+	c.refund += res.GasRefund
+	return nil
+}
+
+// Result codes of EXTCALL, EXTDELEGATECALL, and EXTSTATICCALL.
+const (
+	extCallSuccess = 0
+	extCallRevert  = 1
+	extCallFailure = 2
+)
+
+const (
+	// extCallMinRetainedGas is the minimum gas retained by the caller of an
+	// EXT*CALL instruction.
+	extCallMinRetainedGas tosca.Gas = 5000
+	// extCallMinCalleeGas is the minimum gas required for the callee of an
+	// EXT*CALL instruction, otherwise the call is not executed.
+	extCallMinCalleeGas tosca.Gas = 2300
+)
+
+func genericExtCall(c *context, kind tosca.CallKind) error {
+	var (
+		target = c.stack.pop()
+		offset = c.stack.pop()
+		size   = c.stack.pop()
+		value  = uint256.NewInt(0)
+	)
+	if kind == tosca.Call {
+		value = c.stack.pop()
+	}
+
+	// Targets must be valid addresses, with no bits set beyond 20 bytes.
+	word := target.Bytes32()
+	if word[0]|word[1]|word[2]|word[3]|word[4]|word[5]|word[6]|word[7]|word[8]|word[9]|word[10]|word[11] != 0 {
+		return errInvalidAddress
+	}
+	address := tosca.Address(target.Bytes20())
+
+	input, err := c.memory.getSlice(offset, size, c)
+	if err != nil {
+		return err
+	}
+
+	accessPrice := c.getDynamicGasPrice(tosca.WarmAccountAccessGas)
+	if c.context.AccessAccount(address) == tosca.ColdAccess {
+		accessPrice = c.getDynamicGasPrice(tosca.ColdAccountAccessGas)
+	}
+	if err := c.useGas(accessPrice); err != nil {
+		return err
+	}
+
+	if !value.IsZero() {
+		if c.params.Static {
+			return errStaticContextViolation
+		}
+		if err := c.useGas(CallValueTransferGas); err != nil {
+			return err
+		}
+		if isEmpty(c.context, address) {
+			if err := c.useGas(CallNewAccountGas); err != nil {
+				return err
+			}
+		}
+	}
+
+	result := c.stack.pushUndefined()
+	c.returnData = nil
+
+	nestedCallGas := c.gas - max(c.gas/64, extCallMinRetainedGas)
+	if nestedCallGas < extCallMinCalleeGas {
+		result.SetUint64(extCallRevert)
+		return nil
+	}
+	if !value.IsZero() {
+		balance := c.context.GetBalance(c.params.Recipient)
+		if value.Gt(new(uint256.Int).SetBytes(balance[:])) {
+			result.SetUint64(extCallRevert)
+			return nil
+		}
+	}
+	// Delegate calls are only supported for EOF targets.
+	if kind == tosca.DelegateCall && !eof.HasMagic(c.context.GetCode(address)) {
+		result.SetUint64(extCallRevert)
+		return nil
+	}
+
+	if c.params.Static && kind == tosca.Call {
+		kind = tosca.StaticCall
+	}
+	params := tosca.CallParameters{
+		Sender:      c.params.Recipient,
+		Recipient:   address,
+		Value:       tosca.Value(value.Bytes32()),
+		Input:       bytes.Clone(input),
+		Gas:         nestedCallGas,
+		CodeAddress: address,
+	}
+	if kind == tosca.DelegateCall {
+		params.Sender = c.params.Sender
+		params.Recipient = c.params.Recipient
+		params.Value = c.params.Value
+	}
+
+	c.gas -= nestedCallGas
+	res, err := c.context.Call(kind, params)
+
+	// A cancellation during the nested call aborts this execution as well.
+	if c.params.IsCanceled() {
+		return tosca.ErrCanceled
+	}
+
+	// Failed calls consume all gas and produce no output, which distinguishes
+	// them from reverted calls. Reverts consuming all gas without producing
+	// an output are thus reported as failures.
+	switch {
+	case err == nil && res.Success:
+		result.SetUint64(extCallSuccess)
+	case err == nil && (res.GasLeft > 0 || len(res.Output) > 0):
+		result.SetUint64(extCallRevert)
+	default:
+		result.SetUint64(extCallFailure)
+	}
+	if err == nil {
+		c.returnData = res.Output
+	}
+	c.gas += res.GasLeft
+	c.refund += res.GasRefund
+	return nil
+}
+
+// isEofExecution checks whether the given code may be executed with the
+// given parameters. EOF code is only supported in the experimental revision,
+// initcode containers may only be executed by EOFCREATE, and runtime
+// containers only by calls.
+func isEofExecution(params tosca.Parameters, code *eofCode) bool {
+	if params.Revision < tosca.R98_Experimental {
+		return false
+	}
+	if params.Kind == tosca.EofCreate {
+		return code.isInitcode
+	}
+	return code.isRuntime && params.Kind != tosca.Create && params.Kind != tosca.Create2
+}
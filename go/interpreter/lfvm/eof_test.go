@@ -0,0 +1,419 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package lfvm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/eof"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+	"go.uber.org/mock/gomock"
+)
+
+// returnTop is an EOF code snippet returning the top of the stack as a 32-byte
+// word. It requires a stack height of 2 above the value to be returned.
+var returnTop = []byte{
+	byte(vm.PUSH0), byte(vm.MSTORE),
+	byte(vm.PUSH1), 32, byte(vm.PUSH0), byte(vm.RETURN),
+}
+
+func TestConvertEof_RelativeJumpsAreResolved(t *testing.T) {
+	container := newEofTestContainer(2, getEofCountDownLoop()...)
+	res := newConvertedCode(container.Bytes(), ConversionConfig{}, defaultGasPrices)
+	if res.eof == nil {
+		t.Fatalf("container was not converted as EOF code")
+	}
+	if res.blocks != nil {
+		t.Errorf("EOF code should not have basic blocks")
+	}
+	if want, got := (Instruction{RJUMPI, 1}), res.code[5]; want != got {
+		t.Errorf("unexpected conversion of jump, want %v, got %v", want, got)
+	}
+}
+
+func TestConvertEof_SectionsAreConcatenated(t *testing.T) {
+	container := getEofFunctionCallExample()
+	res := newConvertedCode(container.Bytes(), ConversionConfig{}, defaultGasPrices)
+	if res.eof == nil {
+		t.Fatalf("container was not converted as EOF code")
+	}
+	if want, got := []int32{0, 8}, res.eof.sections; len(want) != len(got) || want[0] != got[0] || want[1] != got[1] {
+		t.Errorf("unexpected section starts, want %v, got %v", want, got)
+	}
+	if want, got := (Instruction{CALLF, 1}), res.code[2]; want != got {
+		t.Errorf("unexpected conversion of call, want %v, got %v", want, got)
+	}
+}
+
+func TestConvertEof_InvalidContainersAreConvertedAsLegacyCode(t *testing.T) {
+	code := []byte{0xEF, 0x00, byte(vm.STOP)}
+	res := newConvertedCode(code, ConversionConfig{}, defaultGasPrices)
+	if res.eof != nil {
+		t.Fatalf("invalid container should not be converted as EOF code")
+	}
+	if res.blocks == nil {
+		t.Errorf("legacy code should have basic blocks")
+	}
+}
+
+func TestConvert_EofInstructionsAreInvalidInLegacyCode(t *testing.T) {
+	for _, op := range []vm.OpCode{vm.RJUMP, vm.CALLF, vm.EXTCALL, vm.DATALOAD} {
+		res := convert([]byte{byte(op)}, ConversionConfig{})
+		if want, got := INVALID, res[0].opcode; want != got {
+			t.Errorf("unexpected conversion of %v, want %v, got %v", op, want, got)
+		}
+	}
+}
+
+func TestEof_ProgramsProduceExpectedResults(t *testing.T) {
+	tests := map[string]struct {
+		container *eof.Container
+		input     []byte
+		output    []byte
+		gasUsed   tosca.Gas
+	}{
+		"function call": {
+			container: getEofFunctionCallExample(),
+			output:    wordOf(3),
+			gasUsed:   3 + 3 + 5 + 3 + 3 + 2 + 3 + 3 + 3 + 2,
+		},
+		"loop": {
+			container: newEofTestContainer(2, getEofCountDownLoop()...),
+			output:    wordOf(0),
+			gasUsed:   3 + 5*(3+3+3+3+4) + 2 + 3 + 3 + 3 + 2,
+		},
+		"jump table first": {
+			container: newEofTestContainer(2, getEofJumpTable()...),
+			output:    wordOf(0x0B),
+		},
+		"jump table second": {
+			container: newEofTestContainer(2, getEofJumpTable()...),
+			input:     []byte{1},
+			output:    wordOf(0x0C),
+		},
+		"jump table default": {
+			container: newEofTestContainer(2, getEofJumpTable()...),
+			input:     []byte{1, 2},
+			output:    wordOf(0x0A),
+		},
+		"data section": {
+			container: func() *eof.Container {
+				c := newEofTestContainer(2,
+					byte(vm.PUSH0), byte(vm.DATALOAD), byte(vm.PUSH0), byte(vm.MSTORE),
+					byte(vm.DATASIZE), byte(vm.PUSH0), byte(vm.RETURN),
+				)
+				c.Data, c.DataSize = []byte{1, 2, 3, 4}, 4
+				return c
+			}(),
+			output: []byte{1, 2, 3, 4},
+		},
+		"return data is padded": {
+			container: newEofTestContainer(3,
+				byte(vm.PUSH1), 32, byte(vm.PUSH0), byte(vm.PUSH0), byte(vm.RETURNDATACOPY),
+				byte(vm.PUSH1), 32, byte(vm.PUSH0), byte(vm.RETURN),
+			),
+			output: make([]byte, 32),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			const gas = 1_000_000
+			result, err := runEofTestContainer(t, test.container, tosca.Call, test.input, gas, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.Success {
+				t.Fatalf("execution failed")
+			}
+			if !bytes.Equal(test.output, result.Output) {
+				t.Errorf("unexpected output, want %x, got %x", test.output, result.Output)
+			}
+			if test.gasUsed != 0 {
+				if want, got := test.gasUsed, gas-result.GasLeft; want != got {
+					t.Errorf("unexpected gas usage, want %d, got %d", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestEof_ContainersAreOnlyExecutedInExperimentalRevision(t *testing.T) {
+	code := newEofTestContainer(0, byte(vm.STOP)).Bytes()
+	for _, revision := range []tosca.Revision{tosca.R14_Prague, newestSupportedRevision, tosca.R98_Experimental} {
+		vm, err := NewInterpreter(Config{})
+		if err != nil {
+			t.Fatalf("failed to create interpreter: %v", err)
+		}
+		result, err := vm.Run(tosca.Parameters{
+			BlockParameters: tosca.BlockParameters{Revision: revision},
+			Gas:             100,
+			Code:            code,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want, got := revision == tosca.R98_Experimental, result.Success; want != got {
+			t.Errorf("unexpected success in %v, want %t, got %t", revision, want, got)
+		}
+	}
+}
+
+func TestEof_RevisionsBetweenNewestAndExperimentalAreRejected(t *testing.T) {
+	vm, err := NewInterpreter(Config{})
+	if err != nil {
+		t.Fatalf("failed to create interpreter: %v", err)
+	}
+	_, err = vm.Run(tosca.Parameters{
+		BlockParameters: tosca.BlockParameters{Revision: newestSupportedRevision + 1},
+	})
+	if err == nil {
+		t.Errorf("expected unsupported revision to be rejected")
+	}
+}
+
+func TestEof_ContainersAreOnlyExecutedForMatchingCallKinds(t *testing.T) {
+	runtime := newEofTestContainer(0, byte(vm.STOP))
+	initcode := getEofInitcodeExample()
+
+	tests := map[string]struct {
+		container *eof.Container
+		kind      tosca.CallKind
+		success   bool
+	}{
+		"runtime by call":           {runtime, tosca.Call, true},
+		"runtime by static call":    {runtime, tosca.StaticCall, true},
+		"runtime by eof create":     {runtime, tosca.EofCreate, false},
+		"runtime by create":         {runtime, tosca.Create, false},
+		"initcode by eof create":    {initcode, tosca.EofCreate, true},
+		"initcode by call":          {initcode, tosca.Call, false},
+		"initcode by legacy create": {initcode, tosca.Create2, false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := runEofTestContainer(t, test.container, test.kind, nil, 1000, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if want, got := test.success, result.Success; want != got {
+				t.Errorf("unexpected success, want %t, got %t", want, got)
+			}
+		})
+	}
+}
+
+func TestEof_ReturnContractAppendsAuxData(t *testing.T) {
+	result, err := runEofTestContainer(t, getEofInitcodeExample(), tosca.EofCreate, nil, 1000, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deployed := newEofTestContainer(0, byte(vm.STOP))
+	deployed.Data, deployed.DataSize = []byte{0, 0}, 2
+	if want, got := deployed.Bytes(), []byte(result.Output); !bytes.Equal(want, got) {
+		t.Errorf("unexpected deployed container, want %x, got %x", want, got)
+	}
+}
+
+func TestEof_EofCreateCreatesContractFromSubContainer(t *testing.T) {
+	initcode := getEofInitcodeExample().Bytes()
+	container := newEofTestContainer(4, append([]byte{
+		byte(vm.PUSH0), byte(vm.PUSH0), byte(vm.PUSH1), 7, byte(vm.PUSH0),
+		byte(vm.EOFCREATE), 0,
+	}, returnTop...)...)
+	container.Containers = [][]byte{initcode}
+
+	created := tosca.Address{0x42}
+	result, err := runEofTestContainer(t, container, tosca.Call, nil, 100_000, func(mock *tosca.MockRunContext) {
+		mock.EXPECT().Call(tosca.EofCreate, gomock.Any()).DoAndReturn(
+			func(_ tosca.CallKind, params tosca.CallParameters) (tosca.CallResult, error) {
+				if want, got := initcode, []byte(params.InitCode); !bytes.Equal(want, got) {
+					t.Errorf("unexpected initcode, want %x, got %x", want, got)
+				}
+				if want, got := (tosca.Hash{31: 7}), params.Salt; want != got {
+					t.Errorf("unexpected salt, want %v, got %v", want, got)
+				}
+				return tosca.CallResult{Success: true, CreatedAddress: created, GasLeft: params.Gas}, nil
+			})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("execution failed")
+	}
+	want := make([]byte, 32)
+	copy(want[12:], created[:])
+	if !bytes.Equal(want, result.Output) {
+		t.Errorf("unexpected output, want %x, got %x", want, result.Output)
+	}
+}
+
+func TestEof_ExtCallProducesResultCodes(t *testing.T) {
+	tests := map[string]struct {
+		result tosca.CallResult
+		want   byte
+	}{
+		"success": {tosca.CallResult{Success: true}, extCallSuccess},
+		"revert":  {tosca.CallResult{GasLeft: 1}, extCallRevert},
+		"failure": {tosca.CallResult{}, extCallFailure},
+	}
+
+	container := newEofTestContainer(4, append([]byte{
+		byte(vm.PUSH0), byte(vm.PUSH0), byte(vm.PUSH0), byte(vm.PUSH1), 0x42,
+		byte(vm.EXTCALL),
+	}, returnTop...)...)
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := runEofTestContainer(t, container, tosca.Call, nil, 100_000, func(mock *tosca.MockRunContext) {
+				mock.EXPECT().AccessAccount(tosca.Address{19: 0x42}).Return(tosca.WarmAccess)
+				mock.EXPECT().Call(tosca.Call, gomock.Any()).Return(test.result, nil)
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.Success {
+				t.Fatalf("execution failed")
+			}
+			if want, got := wordOf(test.want), []byte(result.Output); !bytes.Equal(want, got) {
+				t.Errorf("unexpected result code, want %x, got %x", want, got)
+			}
+		})
+	}
+}
+
+func TestEof_ExtCallWithInvalidAddressFails(t *testing.T) {
+	target := make([]byte, 32)
+	target[0] = 1
+	container := newEofTestContainer(4, append(append([]byte{
+		byte(vm.PUSH0), byte(vm.PUSH0), byte(vm.PUSH0), byte(vm.PUSH32),
+	}, target...), byte(vm.EXTCALL), byte(vm.POP), byte(vm.STOP))...)
+
+	result, err := runEofTestContainer(t, container, tosca.Call, nil, 100_000, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Success {
+		t.Errorf("expected execution to fail")
+	}
+}
+
+func TestEof_ExtDelegateCallToLegacyCodeIsNotExecuted(t *testing.T) {
+	container := newEofTestContainer(3, append([]byte{
+		byte(vm.PUSH0), byte(vm.PUSH0), byte(vm.PUSH1), 0x42,
+		byte(vm.EXTDELEGATECALL),
+	}, returnTop...)...)
+
+	result, err := runEofTestContainer(t, container, tosca.Call, nil, 100_000, func(mock *tosca.MockRunContext) {
+		mock.EXPECT().AccessAccount(gomock.Any()).Return(tosca.WarmAccess)
+		mock.EXPECT().GetCode(tosca.Address{19: 0x42}).Return(tosca.Code{byte(vm.STOP)})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want, got := wordOf(extCallRevert), []byte(result.Output); !bytes.Equal(want, got) {
+		t.Errorf("unexpected result code, want %x, got %x", want, got)
+	}
+}
+
+// newEofTestContainer creates a container with a single code section with the
+// given maximum stack increase.
+func newEofTestContainer(maxStackIncrease uint16, code ...byte) *eof.Container {
+	return &eof.Container{
+		Types: []eof.FunctionType{{Outputs: eof.NonReturning, MaxStackIncrease: maxStackIncrease}},
+		Code:  [][]byte{code},
+		Data:  []byte{},
+	}
+}
+
+// getEofFunctionCallExample returns a container adding 1 and 2 in a function.
+func getEofFunctionCallExample() *eof.Container {
+	return &eof.Container{
+		Types: []eof.FunctionType{
+			{Outputs: eof.NonReturning, MaxStackIncrease: 2},
+			{Inputs: 2, Outputs: 1},
+		},
+		Code: [][]byte{
+			append([]byte{
+				byte(vm.PUSH1), 1, byte(vm.PUSH1), 2, byte(vm.CALLF), 0, 1,
+			}, returnTop...),
+			{byte(vm.ADD), byte(vm.RETF)},
+		},
+	}
+}
+
+// getEofCountDownLoop returns code counting down from 5 to 0 in a loop.
+func getEofCountDownLoop() []byte {
+	return append([]byte{
+		byte(vm.PUSH1), 5,
+		byte(vm.PUSH1), 1, byte(vm.SWAP1), byte(vm.SUB), byte(vm.DUP1),
+		byte(vm.RJUMPI), 0xFF, 0xF8, // < to the second PUSH1
+	}, returnTop...)
+}
+
+// getEofJumpTable returns code returning 0x0B if the call data is empty, 0x0C
+// if it has a length of 1, and 0x0A otherwise.
+func getEofJumpTable() []byte {
+	return append([]byte{
+		byte(vm.CALLDATASIZE),
+		byte(vm.RJUMPV), 1, 0, 5, 0, 10,
+		byte(vm.PUSH1), 0x0A, byte(vm.RJUMP), 0, 7,
+		byte(vm.PUSH1), 0x0B, byte(vm.RJUMP), 0, 2,
+		byte(vm.PUSH1), 0x0C,
+	}, returnTop...)
+}
+
+// getEofInitcodeExample returns an initcode container deploying a container
+// with a truncated data section of two bytes, filled with zeros.
+func getEofInitcodeExample() *eof.Container {
+	deployed := newEofTestContainer(0, byte(vm.STOP))
+	deployed.DataSize = 2
+	res := newEofTestContainer(2, byte(vm.PUSH1), 2, byte(vm.PUSH0), byte(vm.RETURNCONTRACT), 0)
+	res.Containers = [][]byte{deployed.Bytes()}
+	return res
+}
+
+func runEofTestContainer(
+	t *testing.T,
+	container *eof.Container,
+	kind tosca.CallKind,
+	input []byte,
+	gas tosca.Gas,
+	setup func(*tosca.MockRunContext),
+) (tosca.Result, error) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	context := tosca.NewMockRunContext(ctrl)
+	if setup != nil {
+		setup(context)
+	}
+	vm, err := NewInterpreter(Config{})
+	if err != nil {
+		t.Fatalf("failed to create interpreter: %v", err)
+	}
+	return vm.Run(tosca.Parameters{
+		BlockParameters: tosca.BlockParameters{Revision: tosca.R98_Experimental},
+		Context:         context,
+		Kind:            kind,
+		Gas:             gas,
+		Input:           input,
+		Code:            container.Bytes(),
+	})
+}
+
+func wordOf(value byte) []byte {
+	res := make([]byte, 32)
+	res[31] = value
+	return res
+}
@@ -25,4 +25,5 @@ const (
 	errStackUnderflow         = tosca.ConstError("stack underflow")
 	errStackOverflow          = tosca.ConstError("stack overflow")
 	errCodeSizeExceeded       = tosca.ConstError("max code size exceeded")
+	errInvalidAddress         = tosca.ConstError("invalid address")
 )
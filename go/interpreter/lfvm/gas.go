@@ -11,7 +11,11 @@
 package lfvm
 
 import (
+	"fmt"
+	"slices"
+
 	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
 )
 
 const (
@@ -32,8 +36,100 @@ const (
 	UNKNOWN_GAS_PRICE = 999999
 )
 
-var static_gas_prices = newOpCodePropertyMap(getStaticGasPriceInternal)
-var static_gas_prices_berlin = newOpCodePropertyMap(getBerlinGasPriceInternal)
+// gasPrices are the prices of a gas schedule, prepared for the lookup during
+// the execution of instructions.
+type gasPrices struct {
+	// static lists the distinct static gas price tables of all revisions.
+	static []opCodePropertyMap[tosca.Gas]
+	// table is the index of the static gas price table of each revision.
+	table [newestSupportedRevision + 1]int
+	// dynamic lists the prices of the dynamic gas kinds of each revision,
+	// indexed by the kind.
+	dynamic [newestSupportedRevision + 1][]tosca.Gas
+}
+
+// defaultGasPrices are the gas prices defined by Ethereum.
+var defaultGasPrices = func() *gasPrices {
+	// can only fail for schedules requiring too many tables
+	prices, _ := newGasPrices(nil)
+	return prices
+}()
+
+// newGasPrices prepares the prices of the given schedule for the use by the
+// interpreter. A nil schedule results in the Ethereum gas prices. Revisions
+// with identical static prices share a table; basic blocks precompute their
+// static gas costs for each table.
+func newGasPrices(schedule *tosca.GasSchedule) (*gasPrices, error) {
+	if err := schedule.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid gas schedule: %w", err)
+	}
+	res := &gasPrices{}
+	for revision := tosca.R07_Istanbul; revision <= newestSupportedRevision; revision++ {
+		table := newOpCodePropertyMap(func(op OpCode) tosca.Gas {
+			return getScheduledStaticGasPrice(schedule, op, revision)
+		})
+		index := slices.Index(res.static, table)
+		if index < 0 {
+			index = len(res.static)
+			res.static = append(res.static, table)
+		}
+		res.table[revision] = index
+		for _, kind := range tosca.GetAllDynamicGasKinds() {
+			res.dynamic[revision] = append(res.dynamic[revision], schedule.GetDynamicGas(kind, revision))
+		}
+	}
+	return res, nil
+}
+
+// getStaticGasPrices returns the static gas prices of the given revision.
+func (p *gasPrices) getStaticGasPrices(revision tosca.Revision) *opCodePropertyMap[tosca.Gas] {
+	return &p.static[p.table[getPricingRevision(revision)]]
+}
+
+// getDynamicGasPrice returns the price of the given dynamic gas kind in the
+// given revision.
+func (p *gasPrices) getDynamicGasPrice(kind tosca.DynamicGasKind, revision tosca.Revision) tosca.Gas {
+	return p.dynamic[getPricingRevision(revision)][kind]
+}
+
+// getPricingRevision returns the revision whose prices are charged in the
+// given revision. The experimental revision is priced like the newest
+// supported revision.
+func getPricingRevision(revision tosca.Revision) tosca.Revision {
+	return min(revision, newestSupportedRevision)
+}
+
+// getScheduledStaticGasPrice returns the static gas price of the given op-code
+// in the given revision, taking overrides of the gas schedule into account.
+// Static jumps are priced like their dynamic counterparts and super
+// instructions like the sequence of instructions they are composed of.
+func getScheduledStaticGasPrice(schedule *tosca.GasSchedule, op OpCode, revision tosca.Revision) tosca.Gas {
+	if op.isSuperInstruction() {
+		var sum tosca.Gas
+		for _, subOp := range op.decompose() {
+			sum += getScheduledStaticGasPrice(schedule, subOp, revision)
+		}
+		return sum
+	}
+
+	base := op
+	switch op {
+	case JUMP_STATIC:
+		base = JUMP
+	case JUMPI_STATIC:
+		base = JUMPI
+	}
+	if base.isBaseInstruction() {
+		if price, found := schedule.GetStaticGas(vm.OpCode(base), revision); found {
+			return price
+		}
+	}
+
+	if revision >= tosca.R09_Berlin {
+		return getBerlinGasPriceInternal(op)
+	}
+	return getStaticGasPriceInternal(op)
+}
 
 func getBerlinGasPriceInternal(op OpCode) tosca.Gas {
 	gp := getStaticGasPriceInternal(op)
@@ -62,13 +158,6 @@ func getBerlinGasPriceInternal(op OpCode) tosca.Gas {
 	return gp
 }
 
-func getStaticGasPrices(revision tosca.Revision) *opCodePropertyMap[tosca.Gas] {
-	if revision >= tosca.R09_Berlin {
-		return &static_gas_prices_berlin
-	}
-	return &static_gas_prices
-}
-
 func getStaticGasPriceInternal(op OpCode) tosca.Gas {
 	if PUSH1 <= op && op <= PUSH32 {
 		return 3
@@ -172,9 +261,9 @@ func getStaticGasPriceInternal(op OpCode) tosca.Gas {
 		return 800 // This is supposed to be 100 for warm and 2100 for cold accesses
 	case SSTORE:
 		return 0 // Costs are handled in gasSStore(..) function below
-	case JUMP:
+	case JUMP, JUMP_STATIC:
 		return 8
-	case JUMPI:
+	case JUMPI, JUMPI_STATIC:
 		return 10
 	case JUMPDEST:
 		return 1
@@ -224,6 +313,34 @@ func getStaticGasPriceInternal(op OpCode) tosca.Gas {
 		return 700
 	case SELFDESTRUCT:
 		return 5000
+	case RJUMP:
+		return 2
+	case RJUMPI:
+		return 4
+	case RJUMPV:
+		return 4
+	case CALLF:
+		return 5
+	case RETF:
+		return 3
+	case JUMPF:
+		return 5
+	case DATALOAD:
+		return 4
+	case DATALOADN:
+		return 3
+	case DATASIZE:
+		return 2
+	case DATACOPY:
+		return 3
+	case RETURNDATALOAD:
+		return 3
+	case EXTCALL, EXTDELEGATECALL, EXTSTATICCALL:
+		return 0 // The account access is charged dynamically
+	case EOFCREATE:
+		return 32000
+	case RETURNCONTRACT:
+		return 0
 	}
 
 	if op.isSuperInstruction() {
@@ -240,6 +357,7 @@ func getStaticGasPriceInternal(op OpCode) tosca.Gas {
 func getDynamicCostsForSstore(
 	revision tosca.Revision,
 	storageStatus tosca.StorageStatus,
+	prices *gasPrices,
 ) tosca.Gas {
 	switch storageStatus {
 	case tosca.StorageAdded:
@@ -253,7 +371,7 @@ func getDynamicCostsForSstore(
 		}
 	default:
 		if revision >= tosca.R09_Berlin {
-			return 100
+			return prices.getDynamicGasPrice(tosca.WarmSstoreGas, revision)
 		}
 		return 800
 	}
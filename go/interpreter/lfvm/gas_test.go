@@ -14,8 +14,127 @@ import (
 	"testing"
 
 	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
 )
 
+// --- Gas Schedules ---
+
+func TestGasPrices_DefaultPricesAreEthereumPrices(t *testing.T) {
+	for revision := tosca.R07_Istanbul; revision <= newestSupportedRevision; revision++ {
+		prices := defaultGasPrices.getStaticGasPrices(revision)
+		for i := range numOpCodes {
+			op := OpCode(i)
+			want := getStaticGasPriceInternal(op)
+			if revision >= tosca.R09_Berlin {
+				want = getBerlinGasPriceInternal(op)
+			}
+			if got := prices.get(op); want != got {
+				t.Errorf("unexpected price of %v in %v, want %d, got %d", op, revision, want, got)
+			}
+		}
+		for _, kind := range tosca.GetAllDynamicGasKinds() {
+			if want, got := kind.EthereumPrice(), defaultGasPrices.getDynamicGasPrice(kind, revision); want != got {
+				t.Errorf("unexpected price of %v in %v, want %d, got %d", kind, revision, want, got)
+			}
+		}
+	}
+	if want, got := 2, len(defaultGasPrices.static); want != got {
+		t.Errorf("unexpected number of static gas price tables, want %d, got %d", want, got)
+	}
+}
+
+func TestGasPrices_OverridesOfScheduleAreApplied(t *testing.T) {
+	prices, err := newGasPrices(&tosca.GasSchedule{
+		Static: []tosca.StaticGasOverride{
+			{OpCode: vm.ADD, Revision: tosca.R13_Cancun, Price: 1},
+			{OpCode: vm.JUMP, Revision: tosca.R07_Istanbul, Price: 2},
+		},
+		Dynamic: []tosca.DynamicGasOverride{
+			{Kind: tosca.ColdSloadGas, Revision: tosca.R10_London, Price: 500},
+			{Kind: tosca.WarmSstoreGas, Revision: tosca.R11_Paris, Price: 7},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		op       OpCode
+		revision tosca.Revision
+		want     tosca.Gas
+	}{
+		{ADD, tosca.R12_Shanghai, 3},
+		{ADD, tosca.R13_Cancun, 1},
+		{PUSH1_ADD, tosca.R13_Cancun, 3 + 1},
+		{JUMP, tosca.R07_Istanbul, 2},
+		{JUMP_STATIC, tosca.R09_Berlin, 2},
+		{POP_JUMP, tosca.R15_Osaka, 2 + 2},
+		{JUMPI, tosca.R15_Osaka, 10},
+	}
+	for _, test := range tests {
+		if want, got := test.want, prices.getStaticGasPrices(test.revision).get(test.op); want != got {
+			t.Errorf("unexpected price of %v in %v, want %d, got %d", test.op, test.revision, want, got)
+		}
+	}
+
+	dynamicTests := []struct {
+		kind     tosca.DynamicGasKind
+		revision tosca.Revision
+		want     tosca.Gas
+	}{
+		{tosca.ColdSloadGas, tosca.R09_Berlin, 2100},
+		{tosca.ColdSloadGas, tosca.R10_London, 500},
+		{tosca.WarmSstoreGas, tosca.R10_London, 100},
+		{tosca.WarmSstoreGas, tosca.R11_Paris, 7},
+		{tosca.WarmSstoreGas, newestSupportedRevision + 1, 7},
+	}
+	for _, test := range dynamicTests {
+		if want, got := test.want, prices.getDynamicGasPrice(test.kind, test.revision); want != got {
+			t.Errorf("unexpected price of %v in %v, want %d, got %d", test.kind, test.revision, want, got)
+		}
+	}
+}
+
+func TestGasPrices_SchedulesMayUseDistinctPricesInEveryRevision(t *testing.T) {
+	schedule := &tosca.GasSchedule{}
+	price := func(revision tosca.Revision) tosca.Gas {
+		return tosca.Gas(100 + revision)
+	}
+	revisions := []tosca.Revision{}
+	for revision := tosca.R07_Istanbul; revision <= newestSupportedRevision; revision++ {
+		revisions = append(revisions, revision)
+		schedule.Static = append(schedule.Static, tosca.StaticGasOverride{
+			OpCode: vm.ADD, Revision: revision, Price: price(revision),
+		})
+	}
+	prices, err := newGasPrices(schedule)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want, got := len(revisions), len(prices.static); want != got {
+		t.Errorf("unexpected number of tables, want %d, got %d", want, got)
+	}
+
+	blocks := computeBasicBlocks(Code{{ADD, 0}}, prices)
+	for _, revision := range revisions {
+		if want, got := price(revision), prices.getStaticGasPrices(revision).get(ADD); want != got {
+			t.Errorf("unexpected price in %v, want %d, got %d", revision, want, got)
+		}
+		if want, got := price(revision), blocks[0].staticGas[prices.table[revision]]; want != got {
+			t.Errorf("unexpected block gas in %v, want %d, got %d", revision, want, got)
+		}
+	}
+}
+
+func TestGasPrices_InvalidSchedulesAreRejected(t *testing.T) {
+	_, err := newGasPrices(&tosca.GasSchedule{
+		Static: []tosca.StaticGasOverride{{OpCode: vm.ADD, Price: -1}},
+	})
+	if err == nil {
+		t.Errorf("expected error for invalid schedule")
+	}
+}
+
 // --- SStore ---
 
 func TestGas_getDynamicCostsForSstore_exhaustive(t *testing.T) {
@@ -71,7 +190,7 @@ func TestGas_getDynamicCostsForSstore_exhaustive(t *testing.T) {
 		}
 		for storageStatus, example := range getStorageStateExamples() {
 			want := spec(example)
-			got := getDynamicCostsForSstore(revision, storageStatus)
+			got := getDynamicCostsForSstore(revision, storageStatus, defaultGasPrices)
 			if got != want {
 				t.Errorf(
 					"unexpected result for (%v,%v), wanted %d, got %d",
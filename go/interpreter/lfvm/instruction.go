@@ -40,3 +40,13 @@ func (c Code) String() string {
 	}
 	return buffer.String()
 }
+
+// next returns the position of the instruction following the one at the given
+// position, skipping the DATA instructions belonging to it.
+func (c Code) next(pos int) int {
+	pos++
+	for pos < len(c) && c[pos].opcode == DATA {
+		pos++
+	}
+	return pos
+}
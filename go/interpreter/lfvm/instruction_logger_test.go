@@ -58,7 +58,7 @@ func TestInterpreter_Logger_ExecutesCodeAndLogs(t *testing.T) {
 			config := config{
 				runner: logger,
 			}
-			_, err := run(config, params, code)
+			_, err := run(config, params, code, nil)
 			if err != nil {
 				t.Errorf("unexpected error: %v", err)
 			}
@@ -92,7 +92,7 @@ func TestInterpreter_Logger_RunsWithoutOutput(t *testing.T) {
 		runner: logger,
 	}
 
-	_, err := run(config, params, code)
+	_, err := run(config, params, code, nil)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -139,7 +139,7 @@ func TestInterpreter_logger_PropagatesWriterError(t *testing.T) {
 	params := tosca.Parameters{}
 	code := []Instruction{{STOP, 0}}
 
-	_, err := run(config, params, code)
+	_, err := run(config, params, code, nil)
 	if strings.Compare(err.Error(), "error") != 0 {
 		t.Errorf("unexpected error: want error, got %v", err)
 	}
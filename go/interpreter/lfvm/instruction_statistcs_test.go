@@ -35,7 +35,7 @@ func TestStatisticsRunner_RunWithStatistics(t *testing.T) {
 	config := config{
 		runner: statsRunner,
 	}
-	_, err := run(config, params, code)
+	_, err := run(config, params, code, nil)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
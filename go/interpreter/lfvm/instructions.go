@@ -31,7 +31,15 @@ func opEndWithResult(c *context) error {
 }
 
 func opPc(c *context) {
-	c.stack.pushUndefined().SetUint64(uint64(c.code[c.pc].arg))
+	// The argument is the offset between the EVM and the LFVM position. For
+	// large offsets, its upper bits are stored in a succeeding DATA instruction.
+	pos := uint64(c.pc)
+	offset := uint64(c.code[c.pc].arg)
+	if int(c.pc+1) < len(c.code) && c.code[c.pc+1].opcode == DATA {
+		c.pc++
+		offset |= uint64(c.code[c.pc].arg) << 16
+	}
+	c.stack.pushUndefined().SetUint64(pos + offset)
 }
 
 func checkJumpDest(c *context) error {
@@ -41,6 +49,15 @@ func checkJumpDest(c *context) error {
 	return nil
 }
 
+// opJumpDest checks whether the execution has been canceled. Since every loop
+// passes a JUMPDEST, this bounds the time until a cancellation takes effect.
+func opJumpDest(c *context) error {
+	if c.params.IsCanceled() {
+		return tosca.ErrCanceled
+	}
+	return nil
+}
+
 func opJump(c *context) error {
 	destination := c.stack.pop()
 	// overflow check
@@ -67,9 +84,25 @@ func opJumpi(c *context) error {
 	return nil
 }
 
+func opJumpStatic(c *context) {
+	// The destination has been validated during the code conversion. The
+	// argument is the signed distance to the destination.
+	c.stack.pop()
+	c.pc += int32(int16(c.code[c.pc].arg)) - 1
+}
+
+func opJumpiStatic(c *context) {
+	c.stack.pop()
+	condition := c.stack.pop()
+	if !condition.IsZero() {
+		c.pc += int32(int16(c.code[c.pc].arg)) - 1
+	}
+}
+
 func opJumpTo(c *context) {
-	// Update the PC to the jump destination -1 since interpreter will increase PC by 1 afterward.
-	c.pc = int32(c.code[c.pc].arg) - 1
+	// The argument is the distance to the jump destination. Update the PC to
+	// the jump destination -1 since interpreter will increase PC by 1 afterward.
+	c.pc += int32(c.code[c.pc].arg) - 1
 }
 
 func opPop(c *context) {
@@ -222,12 +255,12 @@ func opSstore(c *context) error {
 	cost := tosca.Gas(0)
 	if c.isAtLeast(tosca.R09_Berlin) &&
 		c.context.AccessStorage(c.params.Recipient, key) == tosca.ColdAccess {
-		cost += 2100
+		cost += c.getDynamicGasPrice(tosca.ColdSstoreSurchargeGas)
 	}
 
 	storageStatus := c.context.SetStorage(c.params.Recipient, key, value)
 
-	cost += getDynamicCostsForSstore(c.params.Revision, storageStatus)
+	cost += getDynamicCostsForSstore(c.params.Revision, storageStatus, c.gasPrices())
 	if err := c.useGas(cost); err != nil {
 		return err
 	}
@@ -243,9 +276,9 @@ func opSload(c *context) error {
 	slot := tosca.Key(top.Bytes32())
 	if c.isAtLeast(tosca.R09_Berlin) {
 		// charge costs for warm/cold slot access
-		costs := tosca.Gas(100)
+		costs := c.getDynamicGasPrice(tosca.WarmSloadGas)
 		if c.context.AccessStorage(addr, slot) == tosca.ColdAccess {
-			costs = 2100
+			costs = c.getDynamicGasPrice(tosca.ColdSloadGas)
 		}
 		if err := c.useGas(costs); err != nil {
 			return err
@@ -601,7 +634,7 @@ func opBalance(c *context) error {
 	slot := c.stack.peek()
 	address := tosca.Address(slot.Bytes20())
 	if c.isAtLeast(tosca.R09_Berlin) {
-		if err := c.useGas(getAccessCost(c.context.AccessAccount(address))); err != nil {
+		if err := c.useGas(c.getAccessCost(c.context.AccessAccount(address))); err != nil {
 			return err
 		}
 	}
@@ -662,7 +695,7 @@ func opSelfdestruct(c *context) (status, error) {
 		// as https://eips.ethereum.org/EIPS/eip-2929#selfdestruct-changes says,
 		// selfdestruct does not charge for warm access
 		if accessStatus := c.context.AccessAccount(beneficiary); accessStatus != tosca.WarmAccess {
-			cost += getAccessCost(accessStatus)
+			cost += c.getAccessCost(accessStatus)
 		}
 	}
 
@@ -745,7 +778,7 @@ func opExtcodesize(c *context) error {
 	top := c.stack.peek()
 	address := tosca.Address(top.Bytes20())
 	if c.isAtLeast(tosca.R09_Berlin) {
-		if err := c.useGas(getAccessCost(c.context.AccessAccount(address))); err != nil {
+		if err := c.useGas(c.getAccessCost(c.context.AccessAccount(address))); err != nil {
 			return err
 		}
 	}
@@ -757,7 +790,7 @@ func opExtcodehash(c *context) error {
 	slot := c.stack.peek()
 	address := tosca.Address(slot.Bytes20())
 	if c.isAtLeast(tosca.R09_Berlin) {
-		if err := c.useGas(getAccessCost(c.context.AccessAccount(address))); err != nil {
+		if err := c.useGas(c.getAccessCost(c.context.AccessAccount(address))); err != nil {
 			return err
 		}
 	}
@@ -826,14 +859,21 @@ func genericCreate(c *context, kind tosca.CallKind) error {
 	nestedCallGas := c.gas
 	nestedCallGas -= nestedCallGas / 64
 
+	// Memory buffers are recycled after the execution, so the init code is
+	// copied to remain valid for the context.
 	res, err := c.context.Call(kind, tosca.CallParameters{
 		Sender: c.params.Recipient,
 		Value:  tosca.Value(value.Bytes32()),
-		Input:  input,
+		Input:  bytes.Clone(input),
 		Gas:    nestedCallGas,
 		Salt:   salt,
 	})
 
+	// A cancellation during the nested call aborts this execution as well.
+	if c.params.IsCanceled() {
+		return tosca.ErrCanceled
+	}
+
 	// Push item on the stack based on the returned error.
 	success := c.stack.pushUndefined()
 	if !res.Success || err != nil {
@@ -910,7 +950,7 @@ func opExtCodeCopy(c *context) error {
 	address := c.stack.pop().Bytes20()
 
 	if c.isAtLeast(tosca.R09_Berlin) {
-		if err := c.useGas(getAccessCost(c.context.AccessAccount(address))); err != nil {
+		if err := c.useGas(c.getAccessCost(c.context.AccessAccount(address))); err != nil {
 			return err
 		}
 	}
@@ -918,13 +958,13 @@ func opExtCodeCopy(c *context) error {
 	return genericDataCopy(c, c.context.GetCode(address))
 }
 
-func getAccessCost(accessStatus tosca.AccessStatus) tosca.Gas {
-	// EIP-2929 says that cold access cost is 2600 and warm is 100.
-	// (https://eips.ethereum.org/EIPS/eip-2929)
+// getAccessCost returns the price of accessing a warm or cold account as
+// introduced by EIP-2929 (https://eips.ethereum.org/EIPS/eip-2929).
+func (c *context) getAccessCost(accessStatus tosca.AccessStatus) tosca.Gas {
 	if accessStatus == tosca.ColdAccess {
-		return tosca.Gas(2600)
+		return c.getDynamicGasPrice(tosca.ColdAccountAccessGas)
 	}
-	return tosca.Gas(100)
+	return c.getDynamicGasPrice(tosca.WarmAccountAccessGas)
 }
 
 func genericCall(c *context, kind tosca.CallKind) error {
@@ -956,7 +996,7 @@ func genericCall(c *context, kind tosca.CallKind) error {
 
 	// from berlin onwards access cost changes depending on warm/cold access.
 	if c.isAtLeast(tosca.R09_Berlin) {
-		if err := c.useGas(getAccessCost(c.context.AccessAccount(toAddr))); err != nil {
+		if err := c.useGas(c.getAccessCost(c.context.AccessAccount(toAddr))); err != nil {
 			return err
 		}
 	}
@@ -965,7 +1005,7 @@ func genericCall(c *context, kind tosca.CallKind) error {
 	if c.isAtLeast(tosca.R14_Prague) {
 		target, isDelegation := parseDelegationDesignation(c.context.GetCode(toAddr))
 		if isDelegation {
-			if err := c.useGas(getAccessCost(c.context.AccessAccount(target))); err != nil {
+			if err := c.useGas(c.getAccessCost(c.context.AccessAccount(target))); err != nil {
 				return err
 			}
 		}
@@ -1023,9 +1063,11 @@ func genericCall(c *context, kind tosca.CallKind) error {
 		kind = tosca.StaticCall
 	}
 
-	// Prepare arguments, depending on call kind
+	// Prepare arguments, depending on call kind. Memory buffers are recycled
+	// after the execution, so the input is copied to remain valid for the
+	// context.
 	callParams := tosca.CallParameters{
-		Input:       args,
+		Input:       bytes.Clone(args),
 		Gas:         nestedCallGas,
 		Value:       tosca.Value(value.Bytes32()),
 		CodeAddress: toAddr,
@@ -1049,6 +1091,11 @@ func genericCall(c *context, kind tosca.CallKind) error {
 	// Perform the call.
 	ret, err := c.context.Call(kind, callParams)
 
+	// A cancellation during the nested call aborts this execution as well.
+	if c.params.IsCanceled() {
+		return tosca.ErrCanceled
+	}
+
 	if err == nil {
 		copy(output, ret.Output)
 	}
@@ -1099,6 +1146,11 @@ func opReturnDataSize(c *context) {
 }
 
 func opReturnDataCopy(c *context) error {
+	// In EOF code, reads beyond the return data are padded with zeros.
+	if c.eof != nil {
+		return genericDataCopy(c, c.returnData)
+	}
+
 	var (
 		memOffset  = c.stack.pop()
 		dataOffset = c.stack.pop()
@@ -11,7 +11,9 @@
 package lfvm
 
 import (
+	"bytes"
 	"fmt"
+	"sync"
 
 	"github.com/0xsoniclabs/tosca/go/tosca"
 )
@@ -28,17 +30,22 @@ const (
 	statusReturned                     // < execution stopped with a RETURN
 	statusSelfDestructed               // < execution stopped with a SELF-DESTRUCT
 	statusFailed                       // < execution stopped with a logic error
+	statusCanceled                     // < execution aborted by a cancellation signal
 )
 
 // context is the execution environment of an interpreter run. It contains all
 // the necessary state to execute a contract, including input parameters, the
 // contract code, and internal execution state such as the program counter,
-// stack, and memory. For each contract execution, a new context is created.
+// stack, and memory. For each contract execution, a context is obtained from
+// a reuse pool.
 type context struct {
 	// Inputs
 	params  tosca.Parameters
 	context tosca.RunContext
-	code    Code // the contract code in LFVM format
+	code    Code        // the contract code in LFVM format
+	blocks  basicBlocks // the basic blocks of the code, nil if instructions are to be checked individually
+	prices  *gasPrices  // the gas prices to be charged, the Ethereum prices if nil
+	eof     *eofCode    // the properties of the executed EOF container, nil for legacy code
 
 	// Execution state
 	pc     int32
@@ -47,6 +54,10 @@ type context struct {
 	stack  *stack
 	memory *Memory
 
+	// EOF execution state
+	section     int32         // < the currently executed code section
+	returnStack []returnFrame // < the return stack of CALLF calls
+
 	// Intermediate data
 	returnData []byte // < the result of the last nested contract call
 
@@ -66,12 +77,55 @@ func (c *context) useGas(amount tosca.Gas) error {
 	return nil
 }
 
+// gasPrices returns the gas prices to be charged by the execution.
+func (c *context) gasPrices() *gasPrices {
+	if c.prices == nil {
+		return defaultGasPrices
+	}
+	return c.prices
+}
+
+// getDynamicGasPrice returns the price of the given dynamic gas kind to be
+// charged by the execution.
+func (c *context) getDynamicGasPrice(kind tosca.DynamicGasKind) tosca.Gas {
+	return c.gasPrices().getDynamicGasPrice(kind, c.params.Revision)
+}
+
 // isAtLeast returns true if the interpreter is is running at least at the given
 // revision or newer, false otherwise.
 func (c *context) isAtLeast(revision tosca.Revision) bool {
 	return c.params.Revision >= revision
 }
 
+// contextPool recycles contexts across interpreter runs. Since each run,
+// including runs of nested calls, obtains its own context, the number of
+// pooled contexts is bounded by the maximum call depth of concurrent runs.
+var contextPool = sync.Pool{
+	New: func() any {
+		return &context{}
+	},
+}
+
+// newContext obtains a context from the reuse pool. The content of the
+// context is undefined and needs to be initialized by the caller.
+func newContext() *context {
+	return contextPool.Get().(*context)
+}
+
+// returnContext releases the stack and memory of the given context and
+// returns it to the reuse pool. References held by the context are cleared
+// to not retain any data of the finished run.
+func returnContext(c *context) {
+	if c.stack != nil {
+		ReturnStack(c.stack)
+	}
+	if c.memory != nil {
+		ReturnMemory(c.memory)
+	}
+	*c = context{}
+	contextPool.Put(c)
+}
+
 // --- Interpreter ---
 
 type runner interface {
@@ -87,7 +141,19 @@ func run(
 	config config,
 	params tosca.Parameters,
 	code Code,
+	blocks basicBlocks,
+) (tosca.Result, error) {
+	return runConverted(config, params, &convertedCode{code: code, blocks: blocks})
+}
+
+// runConverted is like run but also supports the execution of EOF containers.
+func runConverted(
+	config config,
+	params tosca.Parameters,
+	converted *convertedCode,
 ) (tosca.Result, error) {
+	code := converted.code
+
 	// Don't bother with the execution if there's no code.
 	if len(code) == 0 {
 		return tosca.Result{
@@ -97,27 +163,37 @@ func run(
 		}, nil
 	}
 
+	// EOF containers fail like any other code starting with 0xEF if they may
+	// not be executed with the given parameters.
+	if converted.eof != nil && !isEofExecution(params, converted.eof) {
+		return tosca.Result{Success: false}, nil
+	}
+
 	// Set up execution context.
-	var ctxt = context{
+	ctxt := newContext()
+	defer returnContext(ctxt)
+	*ctxt = context{
 		params:       params,
 		context:      params.Context,
 		gas:          params.Gas,
 		stack:        NewStack(),
 		memory:       NewMemory(),
 		code:         code,
+		blocks:       converted.blocks,
+		prices:       config.prices,
+		eof:          converted.eof,
 		withShaCache: config.WithShaCache,
 	}
-	defer ReturnStack(ctxt.stack)
 
 	if config.runner == nil {
 		config.runner = vanillaRunner{}
 	}
-	status, err := config.runner.run(&ctxt)
+	status, err := config.runner.run(ctxt)
 	if err != nil {
 		return tosca.Result{}, err
 	}
 
-	return generateResult(status, &ctxt)
+	return generateResult(status, ctxt)
 }
 
 func generateResult(status status, ctxt *context) (tosca.Result, error) {
@@ -132,20 +208,23 @@ func generateResult(status status, ctxt *context) (tosca.Result, error) {
 	case statusReturned:
 		return tosca.Result{
 			Success:   true,
-			Output:    ctxt.returnData,
+			Output:    bytes.Clone(ctxt.returnData),
 			GasLeft:   ctxt.gas,
 			GasRefund: ctxt.refund,
 		}, nil
 	case statusReverted:
 		return tosca.Result{
-			Success: false,
-			Output:  ctxt.returnData,
-			GasLeft: ctxt.gas,
+			Success:  false,
+			Reverted: true,
+			Output:   bytes.Clone(ctxt.returnData),
+			GasLeft:  ctxt.gas,
 		}, nil
 	case statusFailed:
 		return tosca.Result{
 			Success: false,
 		}, nil
+	case statusCanceled:
+		return tosca.Result{}, tosca.ErrCanceled
 	default:
 		return tosca.Result{}, fmt.Errorf("unexpected error in interpreter, unknown status: %v", status)
 	}
@@ -166,9 +245,13 @@ func (r vanillaRunner) run(c *context) (status, error) {
 // execute runs the contract code in the given context. If oneStepOnly is true,
 // only the instruction pointed to by the program counter will be executed.
 // If the contract execution yields any execution violation (i.e. out of gas,
-// stack underflow, etc), the function returns statusFailed
+// stack underflow, etc), the function returns statusFailed. If the execution
+// has been canceled, statusCanceled is returned.
 func execute(c *context, oneStepOnly bool) status {
 	status, error := steps(c, oneStepOnly)
+	if error == tosca.ErrCanceled {
+		return statusCanceled
+	}
 	if error != nil {
 		return statusFailed
 	}
@@ -180,8 +263,22 @@ func execute(c *context, oneStepOnly bool) status {
 // counter will be executed.
 // steps returns the status of the execution and an error if the contract
 // execution yields any execution violation (i.e. out of gas, stack underflow, etc).
+//
+// If the basic blocks of the code are known, stack bounds and static gas are
+// checked once per basic block instead of once per instruction. If those
+// checks fail for a block, its instructions are checked individually, such
+// that the error is reported by the instruction causing it.
 func steps(c *context, oneStepOnly bool) (status, error) {
-	staticGasPrices := getStaticGasPrices(c.params.Revision)
+	prices := c.gasPrices()
+	staticGasPrices := prices.getStaticGasPrices(c.params.Revision)
+	gasTable := prices.table[getPricingRevision(c.params.Revision)]
+	useBlocks := c.blocks != nil && !oneStepOnly
+
+	// The position of the last instruction of the current basic block, or -1
+	// if the next instruction starts a new block.
+	blockEnd := int32(-1)
+	// True if the checks of the current block have been performed on entry.
+	blockChecked := false
 
 	status := statusRunning
 	for status == statusRunning {
@@ -189,16 +286,26 @@ func steps(c *context, oneStepOnly bool) (status, error) {
 			return statusStopped, nil
 		}
 
-		op := c.code[c.pc].opcode
+		pc := c.pc
+		op := c.code[pc].opcode
 
-		// Check stack boundary for every instruction
-		if err := checkStackLimits(c.stack.len(), op); err != nil {
-			return status, err
+		if useBlocks && blockEnd < 0 {
+			if block := &c.blocks[pc]; block.last >= pc {
+				blockEnd = block.last
+				blockChecked = block.enter(c, gasTable)
+			}
 		}
 
-		// Consume static gas price for instruction before execution
-		if err := c.useGas(staticGasPrices.get(op)); err != nil {
-			return status, err
+		if !blockChecked {
+			// Check stack boundary for every instruction
+			if err := checkStackLimits(c.stack.len(), op); err != nil {
+				return status, err
+			}
+
+			// Consume static gas price for instruction before execution
+			if err := c.useGas(staticGasPrices.get(op)); err != nil {
+				return status, err
+			}
 		}
 
 		var err error
@@ -226,7 +333,7 @@ func steps(c *context, oneStepOnly bool) (status, error) {
 		case JUMP:
 			err = opJump(c)
 		case JUMPDEST:
-			// nothing
+			err = opJumpDest(c)
 		case SWAP1:
 			opSwap(c, 1)
 		case SWAP2:
@@ -239,6 +346,10 @@ func steps(c *context, oneStepOnly bool) (status, error) {
 			opSwap(c, 3)
 		case JUMPI:
 			err = opJumpi(c)
+		case JUMP_STATIC:
+			opJumpStatic(c)
+		case JUMPI_STATIC:
+			opJumpiStatic(c)
 		case GT:
 			opGt(c)
 		case DUP4:
@@ -507,6 +618,40 @@ func steps(c *context, oneStepOnly bool) (status, error) {
 			err = opLog(c, 3)
 		case LOG4:
 			err = opLog(c, 4)
+		// --- EOF Instructions ---
+		case RJUMP:
+			err = opRJump(c)
+		case RJUMPI:
+			err = opRJumpi(c)
+		case RJUMPV:
+			err = opRJumpv(c)
+		case CALLF:
+			err = opCallf(c)
+		case RETF:
+			err = opRetf(c)
+		case JUMPF:
+			err = opJumpf(c)
+		case DATALOAD:
+			opDataLoad(c)
+		case DATALOADN:
+			opDataLoadN(c)
+		case DATASIZE:
+			opDataSize(c)
+		case DATACOPY:
+			err = genericDataCopy(c, c.eof.container.Data)
+		case RETURNDATALOAD:
+			opReturnDataLoad(c)
+		case EXTCALL:
+			err = genericExtCall(c, tosca.Call)
+		case EXTDELEGATECALL:
+			err = genericExtCall(c, tosca.DelegateCall)
+		case EXTSTATICCALL:
+			err = genericExtCall(c, tosca.StaticCall)
+		case EOFCREATE:
+			err = opEofCreate(c)
+		case RETURNCONTRACT:
+			err = opReturnContract(c)
+			status = statusReturned
 		// --- Super Instructions ---
 		case SWAP2_SWAP1_POP_JUMP:
 			err = opSwap2_Swap1_Pop_Jump(c)
@@ -556,6 +701,11 @@ func steps(c *context, oneStepOnly bool) (status, error) {
 			return status, err
 		}
 
+		if pc == blockEnd {
+			blockEnd = -1
+			blockChecked = false
+		}
+
 		c.pc++
 
 		if oneStepOnly {
@@ -25,6 +25,7 @@ import (
 	"testing"
 
 	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
 	"github.com/holiman/uint256"
 	"go.uber.org/mock/gomock"
 )
@@ -287,7 +288,7 @@ func TestInterpreter_Vanilla_RunsWithoutOutput(t *testing.T) {
 	os.Stdout = w
 
 	// Run testing code
-	_, err := run(config{}, params, code)
+	_, err := run(config{}, params, code, nil)
 	// read the output
 	_ = w.Close() // ignore error in test
 	out, _ := io.ReadAll(r)
@@ -309,7 +310,7 @@ func TestInterpreter_EmptyCodeBypassesRunnerAndSucceeds(t *testing.T) {
 		runner: NewMockrunner(gomock.NewController(t)),
 	}
 
-	result, err := run(config, params, code)
+	result, err := run(config, params, code, nil)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -330,7 +331,7 @@ func TestInterpreter_run_ReturnsErrorOnRuntimeError(t *testing.T) {
 
 	runner.EXPECT().run(gomock.Any()).Return(statusFailed, expectedError)
 
-	_, err := run(config, params, code)
+	_, err := run(config, params, code, nil)
 	if !errors.Is(err, expectedError) {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -360,6 +361,7 @@ func TestRun_GenerateResult(t *testing.T) {
 			status: statusReverted,
 			expectedResult: tosca.Result{
 				Success:   false,
+				Reverted:  true,
 				Output:    baseOutput,
 				GasLeft:   baseGas,
 				GasRefund: 0,
@@ -389,9 +391,14 @@ func TestRun_GenerateResult(t *testing.T) {
 				Success: false,
 			},
 		},
+		"canceled": {
+			status:         statusCanceled,
+			expectedErr:    tosca.ErrCanceled,
+			expectedResult: tosca.Result{},
+		},
 		"unknown status": {
-			status:         statusFailed + 1,
-			expectedErr:    fmt.Errorf("unexpected error in interpreter, unknown status: %v", statusFailed+1),
+			status:         statusCanceled + 1,
+			expectedErr:    fmt.Errorf("unexpected error in interpreter, unknown status: %v", statusCanceled+1),
 			expectedResult: tosca.Result{},
 		},
 	}
@@ -533,7 +540,7 @@ func TestSteps_FailsWithLessGasThanStaticCost(t *testing.T) {
 		t.Run(op.String(), func(t *testing.T) {
 			forEachRevision(t, op, func(t *testing.T, revision tosca.Revision) {
 
-				expectedGas := getStaticGasPrices(revision).get(op)
+				expectedGas := defaultGasPrices.getStaticGasPrices(revision).get(op)
 				if expectedGas == 0 {
 					t.Skip("operation has static cost zero")
 				}
@@ -649,6 +656,8 @@ func generateCodeFor(op OpCode) Code {
 		code = append(code, Instruction{op, 1}) // hardcoded jump destination
 	case PUSH2_JUMPI:
 		code = append(code, Instruction{op, 1}) // hardcoded jump destination
+	case JUMP_STATIC, JUMPI_STATIC:
+		code = append(code, Instruction{op, 1}) // hardcoded jump destination
 	default:
 		code = append(code, Instruction{op, 0})
 	}
@@ -705,13 +714,22 @@ func isExecutable(op OpCode) bool {
 	if slices.Contains([]OpCode{INVALID, NOOP, DATA}, op) {
 		return false
 	}
+	// EOF instructions require the context of an EOF container, they are
+	// covered by the tests in eof_test.go.
+	if isEofInstruction(op) {
+		return false
+	}
 	return !_isUndefinedOpCodeRegex.MatchString(op.String())
 }
 
+func isEofInstruction(op OpCode) bool {
+	return op.isBaseInstruction() && vm.IsValidInEof(vm.OpCode(op)) && !vm.IsValid(vm.OpCode(op))
+}
+
 func isJump(op OpCode) bool {
 	ops := append(op.decompose(), op)
 	return slices.ContainsFunc(ops, func(op OpCode) bool {
-		return op == JUMP || op == JUMPI
+		return op == JUMP || op == JUMPI || op == JUMP_STATIC || op == JUMPI_STATIC
 	})
 }
 
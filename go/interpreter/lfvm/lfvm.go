@@ -19,23 +19,36 @@ import (
 
 // Config provides a set of user-definable options for the LFVM interpreter.
 type Config struct {
+	// GasSchedule overrides the Ethereum gas prices charged by the
+	// interpreter. If nil, the Ethereum gas prices are charged.
+	GasSchedule *tosca.GasSchedule
 }
 
 // NewInterpreter creates a new LFVM interpreter instance with the official
 // configuration for production purposes.
-func NewInterpreter(Config) (*lfvm, error) {
+func NewInterpreter(c Config) (*lfvm, error) {
 	return newVm(config{
 		ConversionConfig: ConversionConfig{
 			WithSuperInstructions: false,
 		},
 		WithShaCache: true,
+		GasSchedule:  c.GasSchedule,
 	})
 }
 
-// Registers the long-form EVM as a possible interpreter implementation.
+// Registers the long-form EVM as a possible interpreter implementation. A
+// Config may be provided as configuration, otherwise the defaults are used.
+// Configurations of any other type are rejected.
 func init() {
-	tosca.MustRegisterInterpreterFactory("lfvm", func(any) (tosca.Interpreter, error) {
-		return NewInterpreter(Config{})
+	tosca.MustRegisterInterpreterFactory("lfvm", func(c any) (tosca.Interpreter, error) {
+		switch config := c.(type) {
+		case nil:
+			return NewInterpreter(Config{})
+		case Config:
+			return NewInterpreter(config)
+		default:
+			return nil, fmt.Errorf("unsupported configuration of type %T, expected lfvm.Config", c)
+		}
 	})
 }
 
@@ -79,6 +92,18 @@ func RegisterExperimentalInterpreterConfigurations() error {
 		}
 	}
 
+	configs["lfvm-static-jumps"] = config{
+		ConversionConfig: ConversionConfig{WithStaticJumps: true},
+		WithShaCache:     true,
+	}
+	configs["lfvm-si-static-jumps"] = config{
+		ConversionConfig: ConversionConfig{
+			WithSuperInstructions: true,
+			WithStaticJumps:       true,
+		},
+		WithShaCache: true,
+	}
+
 	configs["lfvm-no-code-cache"] = config{
 		ConversionConfig: ConversionConfig{CacheSize: -1},
 	}
@@ -101,7 +126,9 @@ func RegisterExperimentalInterpreterConfigurations() error {
 type config struct {
 	ConversionConfig
 	WithShaCache bool
+	GasSchedule  *tosca.GasSchedule
 	runner       runner
+	prices       *gasPrices // < derived from the gas schedule by newVm
 }
 
 type lfvm struct {
@@ -110,7 +137,12 @@ type lfvm struct {
 }
 
 func newVm(config config) (*lfvm, error) {
-	converter, err := NewConverter(config.ConversionConfig)
+	prices, err := newGasPrices(config.GasSchedule)
+	if err != nil {
+		return nil, err
+	}
+	config.prices = prices
+	converter, err := newConverter(config.ConversionConfig, prices)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create converter: %v", err)
 	}
@@ -121,11 +153,11 @@ func newVm(config config) (*lfvm, error) {
 const newestSupportedRevision = tosca.R15_Osaka
 
 func (e *lfvm) Run(params tosca.Parameters) (tosca.Result, error) {
-	if params.Revision > newestSupportedRevision {
+	if params.Revision > newestSupportedRevision && params.Revision != tosca.R98_Experimental {
 		return tosca.Result{}, &tosca.ErrUnsupportedRevision{Revision: params.Revision}
 	}
 
-	converted, err := e.converter.Convert(
+	converted, err := e.converter.convertWithBlocks(
 		params.Code,
 		params.CodeHash,
 	)
@@ -133,7 +165,7 @@ func (e *lfvm) Run(params tosca.Parameters) (tosca.Result, error) {
 		return tosca.Result{}, fmt.Errorf("failed to convert code: %w", err)
 	}
 
-	return run(e.config, params, converted)
+	return runConverted(e.config, params, converted)
 }
 
 func (e *lfvm) DumpProfile() {
@@ -11,10 +11,16 @@
 package lfvm
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"slices"
 	"testing"
 
 	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+	"github.com/holiman/uint256"
+	"go.uber.org/mock/gomock"
 )
 
 func TestNewInterpreter_ProducesInstanceWithSanctionedProperties(t *testing.T) {
@@ -47,6 +53,25 @@ func TestLfvm_OfficialConfigurationHasSanctionedProperties(t *testing.T) {
 	}
 }
 
+func TestLfvm_FactoryUsesProvidedConfig(t *testing.T) {
+	schedule := &tosca.GasSchedule{}
+	vm, err := tosca.NewInterpreter("lfvm", Config{GasSchedule: schedule})
+	if err != nil {
+		t.Fatalf("failed to create lfvm: %v", err)
+	}
+	if got := vm.(*lfvm).config.GasSchedule; got != schedule {
+		t.Errorf("gas schedule of configuration was not used")
+	}
+}
+
+func TestLfvm_FactoryRejectsConfigOfUnexpectedType(t *testing.T) {
+	for _, config := range []any{&Config{}, 42, "lfvm"} {
+		if _, err := tosca.NewInterpreter("lfvm", config); err == nil {
+			t.Errorf("configuration of type %T was accepted", config)
+		}
+	}
+}
+
 func TestLfvm_InterpreterReturnsErrorWhenExecutingUnsupportedRevision(t *testing.T) {
 	vm, err := tosca.NewInterpreter("lfvm")
 	if err != nil {
@@ -71,3 +96,270 @@ func TestLfvm_newVm_returnsErrorWithWrongConfiguration(t *testing.T) {
 		t.Fatalf("expected error, got nil")
 	}
 }
+
+func TestLfvm_ExecutesCodeLargerThanMaxUint16(t *testing.T) {
+	// The code jumps over a large gap of PUSH32 instructions to a jump
+	// destination beyond 16-bit range, where it returns the program counter.
+	target := 3 * math.MaxUint16
+	code := []byte{byte(vm.PUSH3), byte(target >> 16), byte(target >> 8), byte(target), byte(vm.JUMP)}
+	for len(code)+33 <= target {
+		code = append(code, byte(vm.PUSH32))
+		code = append(code, make([]byte, 32)...)
+	}
+	for len(code) < target {
+		code = append(code, byte(vm.STOP))
+	}
+	code = append(code,
+		byte(vm.JUMPDEST),
+		byte(vm.PC),
+		byte(vm.PUSH1), 0,
+		byte(vm.MSTORE),
+		byte(vm.PUSH1), 32,
+		byte(vm.PUSH1), 0,
+		byte(vm.RETURN),
+	)
+
+	for _, withSuperInstructions := range []bool{false, true} {
+		t.Run(fmt.Sprintf("superInstructions=%t", withSuperInstructions), func(t *testing.T) {
+			instance, err := newVm(config{
+				ConversionConfig: ConversionConfig{WithSuperInstructions: withSuperInstructions},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			params := tosca.Parameters{
+				BlockParameters: tosca.BlockParameters{Revision: tosca.R13_Cancun},
+				Gas:             100_000,
+				Code:            code,
+			}
+			result, err := instance.Run(params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.Success {
+				t.Fatalf("execution failed")
+			}
+			if want, got := uint64(target+1), new(uint256.Int).SetBytes(result.Output).Uint64(); want != got {
+				t.Errorf("unexpected program counter, want %d, got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestLfvm_CanceledExecutionIsAbortedAtJumpDestination(t *testing.T) {
+	instance, err := newVm(config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	done := make(chan struct{})
+	close(done)
+	params := tosca.Parameters{
+		BlockParameters:       tosca.BlockParameters{Revision: tosca.R13_Cancun},
+		TransactionParameters: tosca.TransactionParameters{Done: done},
+		Gas:                   math.MaxInt64,
+		Code:                  []byte{byte(vm.JUMPDEST), byte(vm.PUSH1), 0, byte(vm.JUMP)},
+	}
+	_, err = instance.Run(params)
+	if !errors.Is(err, tosca.ErrCanceled) {
+		t.Errorf("unexpected error, want %v, got %v", tosca.ErrCanceled, err)
+	}
+}
+
+func TestLfvm_ExecutionCanceledDuringNestedCallIsAborted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	runContext := tosca.NewMockRunContext(ctrl)
+
+	instance, err := newVm(config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	done := make(chan struct{})
+	runContext.EXPECT().Call(tosca.Call, gomock.Any()).DoAndReturn(
+		func(tosca.CallKind, tosca.CallParameters) (tosca.CallResult, error) {
+			close(done)
+			return tosca.CallResult{Success: true}, nil
+		})
+
+	code := []byte{}
+	for range 6 {
+		code = append(code, byte(vm.PUSH1), 0)
+	}
+	code = append(code, byte(vm.GAS), byte(vm.CALL), byte(vm.STOP))
+	params := tosca.Parameters{
+		BlockParameters:       tosca.BlockParameters{Revision: tosca.R07_Istanbul},
+		TransactionParameters: tosca.TransactionParameters{Done: done},
+		Context:               runContext,
+		Gas:                   100_000,
+		Code:                  code,
+	}
+	_, err = instance.Run(params)
+	if !errors.Is(err, tosca.ErrCanceled) {
+		t.Errorf("unexpected error, want %v, got %v", tosca.ErrCanceled, err)
+	}
+}
+
+func TestLfvm_ChargesPricesOfGasSchedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	runContext := tosca.NewMockRunContext(ctrl)
+	runContext.EXPECT().AccessStorage(gomock.Any(), gomock.Any()).Return(tosca.ColdAccess).AnyTimes()
+	runContext.EXPECT().GetStorage(gomock.Any(), gomock.Any()).Return(tosca.Word{}).AnyTimes()
+
+	schedule := &tosca.GasSchedule{
+		Static: []tosca.StaticGasOverride{
+			{OpCode: vm.ADD, Revision: tosca.R07_Istanbul, Price: 10},
+		},
+		Dynamic: []tosca.DynamicGasOverride{
+			{Kind: tosca.ColdSloadGas, Revision: tosca.R09_Berlin, Price: 500},
+		},
+	}
+	code := []byte{
+		byte(vm.PUSH1), 1,
+		byte(vm.PUSH1), 2,
+		byte(vm.ADD),
+		byte(vm.SLOAD),
+		byte(vm.STOP),
+	}
+
+	for _, withSuperInstructions := range []bool{false, true} {
+		t.Run(fmt.Sprintf("superInstructions=%t", withSuperInstructions), func(t *testing.T) {
+			instance, err := newVm(config{
+				ConversionConfig: ConversionConfig{WithSuperInstructions: withSuperInstructions},
+				GasSchedule:      schedule,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			params := tosca.Parameters{
+				BlockParameters: tosca.BlockParameters{Revision: tosca.R13_Cancun},
+				Context:         runContext,
+				Gas:             1000,
+				Code:            code,
+			}
+			result, err := instance.Run(params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.Success {
+				t.Fatalf("execution failed")
+			}
+			if want, got := tosca.Gas(1000-3-3-10-500), result.GasLeft; want != got {
+				t.Errorf("unexpected gas left, want %d, got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestLfvm_InvalidGasScheduleIsRejected(t *testing.T) {
+	_, err := NewInterpreter(Config{
+		GasSchedule: &tosca.GasSchedule{
+			Static: []tosca.StaticGasOverride{{OpCode: vm.ADD, Price: -1}},
+		},
+	})
+	if err == nil {
+		t.Errorf("expected error for invalid gas schedule")
+	}
+}
+
+func TestLfvm_OutputIsNotAffectedBySubsequentRuns(t *testing.T) {
+	// The code returns 32 bytes of memory filled with the value of its first
+	// argument. Memory of finished runs is reused, so the output of the first
+	// run must not be affected by the second.
+	code := func(value byte) []byte {
+		return []byte{
+			byte(vm.PUSH1), value,
+			byte(vm.PUSH1), 0,
+			byte(vm.MSTORE),
+			byte(vm.PUSH1), 32,
+			byte(vm.PUSH1), 0,
+			byte(vm.RETURN),
+		}
+	}
+	instance, err := newVm(config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	run := func(value byte) []byte {
+		result, err := instance.Run(tosca.Parameters{
+			BlockParameters: tosca.BlockParameters{Revision: tosca.R13_Cancun},
+			Gas:             1000,
+			Code:            code(value),
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("execution failed: %v", err)
+		}
+		return result.Output
+	}
+
+	first := run(1)
+	for range 10 {
+		run(2)
+	}
+	if want, got := byte(1), first[31]; want != got {
+		t.Errorf("output of first run was modified, want %d, got %d", want, got)
+	}
+}
+
+func TestLfvm_CallInputIsNotAffectedBySubsequentRuns(t *testing.T) {
+	// The code stores its first argument in memory and passes the first 32
+	// bytes of memory as the input of a call and as the init code of a
+	// create. Memory of finished runs is reused, so inputs retained by the
+	// run context must not be affected by later runs.
+	code := func(value byte) []byte {
+		return []byte{
+			byte(vm.PUSH1), value,
+			byte(vm.PUSH1), 0,
+			byte(vm.MSTORE),
+			byte(vm.PUSH1), 0, // < output size
+			byte(vm.PUSH1), 0, // < output offset
+			byte(vm.PUSH1), 32, // < input size
+			byte(vm.PUSH1), 0, // < input offset
+			byte(vm.PUSH1), 0, // < value
+			byte(vm.PUSH1), 1, // < address
+			byte(vm.PUSH2), 0xff, 0xff, // < gas
+			byte(vm.CALL),
+			byte(vm.POP),
+			byte(vm.PUSH1), 32, // < init code size
+			byte(vm.PUSH1), 0, // < init code offset
+			byte(vm.PUSH1), 0, // < value
+			byte(vm.CREATE),
+			byte(vm.POP),
+			byte(vm.STOP),
+		}
+	}
+
+	var inputs [][]byte
+	runContext := tosca.NewMockRunContext(gomock.NewController(t))
+	runContext.EXPECT().AccessAccount(gomock.Any()).Return(tosca.WarmAccess).AnyTimes()
+	runContext.EXPECT().Call(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ tosca.CallKind, params tosca.CallParameters) (tosca.CallResult, error) {
+			inputs = append(inputs, params.Input)
+			return tosca.CallResult{Success: true}, nil
+		}).AnyTimes()
+
+	instance, err := newVm(config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	run := func(value byte) {
+		result, err := instance.Run(tosca.Parameters{
+			BlockParameters: tosca.BlockParameters{Revision: tosca.R13_Cancun},
+			Context:         runContext,
+			Gas:             1_000_000,
+			Code:            code(value),
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("execution failed: %v", err)
+		}
+	}
+
+	run(1)
+	retained := slices.Clone(inputs)
+	for range 10 {
+		run(2)
+	}
+	for i, input := range retained {
+		if want, got := byte(1), input[31]; want != got {
+			t.Errorf("input %d of first run was modified, want %d, got %d", i, want, got)
+		}
+	}
+}
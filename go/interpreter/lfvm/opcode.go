@@ -211,6 +211,26 @@ const (
 
 	// Invalid instruction
 	INVALID = OpCode(vm.INVALID)
+
+	// EOF instructions, only valid in the code sections of EOF containers.
+	// Their immediate arguments are converted into instruction arguments,
+	// see convertEof.
+	RJUMP           = OpCode(vm.RJUMP)
+	RJUMPI          = OpCode(vm.RJUMPI)
+	RJUMPV          = OpCode(vm.RJUMPV)
+	CALLF           = OpCode(vm.CALLF)
+	RETF            = OpCode(vm.RETF)
+	JUMPF           = OpCode(vm.JUMPF)
+	DATALOAD        = OpCode(vm.DATALOAD)
+	DATALOADN       = OpCode(vm.DATALOADN)
+	DATASIZE        = OpCode(vm.DATASIZE)
+	DATACOPY        = OpCode(vm.DATACOPY)
+	EOFCREATE       = OpCode(vm.EOFCREATE)
+	RETURNCONTRACT  = OpCode(vm.RETURNCONTRACT)
+	RETURNDATALOAD  = OpCode(vm.RETURNDATALOAD)
+	EXTCALL         = OpCode(vm.EXTCALL)
+	EXTDELEGATECALL = OpCode(vm.EXTDELEGATECALL)
+	EXTSTATICCALL   = OpCode(vm.EXTSTATICCALL)
 )
 
 // The following constants define the extended set of OpCodes for the long-form
@@ -233,9 +253,14 @@ const (
 	// instructions. To avoid having to process long sequences of NOOPs,
 	// JUMP_TO instructions are used to skip them in a single step.
 	//
+	// The argument of a JUMP_TO instruction is the distance to its target,
+	// such that code of any size can be addressed. Gaps exceeding the range
+	// of the argument are skipped by a chain of JUMP_TO instructions.
+	//
 	// The following restrictions are imposed on JUMP_TO instructions:
-	//  - they must target the immediate succeeding JUMPDEST instruction
-	//  - all instructions between the JUMP_TO and the JUMPDEST must be NOOPs
+	//  - they must target the immediate succeeding JUMPDEST instruction or
+	//    another JUMP_TO instruction continuing the chain
+	//  - all instructions between the JUMP_TO and its target must be NOOPs
 	//
 	// These restrictions are enforced during the EVM to LFVM code conversion.
 	JUMP_TO OpCode = iota + 0x100
@@ -254,6 +279,14 @@ const (
 	// search (which could be cached to amortize costs).
 	DATA
 
+	// JUMP_STATIC and JUMPI_STATIC are variants of JUMP and JUMPI with a jump
+	// destination resolved during the code conversion. The argument is the
+	// signed 16-bit distance from the jump to its destination, which has been
+	// verified to be a valid JUMPDEST. Like their dynamic counterparts, they pop the destination from
+	// the stack, but they ignore its value.
+	JUMP_STATIC
+	JUMPI_STATIC
+
 	// Super-instructions
 	SWAP2_SWAP1_POP_JUMP
 	SWAP1_POP_SWAP2_SWAP1
@@ -290,6 +323,9 @@ var toString = map[OpCode]string{
 	NOOP:    "NOOP",
 	JUMP_TO: "JUMP_TO",
 
+	JUMP_STATIC:  "JUMP_STATIC",
+	JUMPI_STATIC: "JUMPI_STATIC",
+
 	SWAP2_SWAP1_POP_JUMP:  "SWAP2_SWAP1_POP_JUMP",
 	SWAP1_POP_SWAP2_SWAP1: "SWAP1_POP_SWAP2_SWAP1",
 	POP_SWAP2_SWAP1_POP:   "POP_SWAP2_SWAP1_POP",
@@ -335,7 +371,9 @@ func (o OpCode) HasArgument() bool {
 	switch o {
 	case DATA:
 		return true
-	case JUMP_TO:
+	case JUMP_TO, JUMP_STATIC, JUMPI_STATIC:
+		return true
+	case RJUMP, RJUMPI, RJUMPV, CALLF, JUMPF, DATALOADN, EOFCREATE, RETURNCONTRACT:
 		return true
 	}
 	if o.isSuperInstruction() {
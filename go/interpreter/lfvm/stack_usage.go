@@ -46,32 +46,38 @@ func computeStackUsage(op OpCode) stackUsage {
 	}
 
 	switch op {
-	case JUMPDEST, JUMP_TO, STOP:
+	case JUMPDEST, JUMP_TO, STOP, RJUMP:
 		return makeUsage(0, 0)
 	case PUSH0, MSIZE, ADDRESS, ORIGIN, CALLER, CALLVALUE, CALLDATASIZE,
 		CODESIZE, GASPRICE, COINBASE, TIMESTAMP, NUMBER,
 		PREVRANDAO, GASLIMIT, PC, GAS, RETURNDATASIZE,
-		SELFBALANCE, CHAINID, BASEFEE, BLOBBASEFEE:
+		SELFBALANCE, CHAINID, BASEFEE, BLOBBASEFEE, DATALOADN, DATASIZE:
 		return makeUsage(0, 1)
-	case POP, JUMP, SELFDESTRUCT:
+	case POP, JUMP, JUMP_STATIC, SELFDESTRUCT, RJUMPI, RJUMPV:
 		return makeUsage(1, 0)
 	case ISZERO, NOT, BALANCE, CALLDATALOAD, EXTCODESIZE,
-		BLOCKHASH, MLOAD, SLOAD, TLOAD, EXTCODEHASH, BLOBHASH, CLZ:
+		BLOCKHASH, MLOAD, SLOAD, TLOAD, EXTCODEHASH, BLOBHASH, CLZ,
+		DATALOAD, RETURNDATALOAD:
 		return makeUsage(1, 1)
-	case MSTORE, MSTORE8, SSTORE, TSTORE, JUMPI, RETURN, REVERT:
+	case MSTORE, MSTORE8, SSTORE, TSTORE, JUMPI, JUMPI_STATIC, RETURN, REVERT,
+		RETURNCONTRACT:
 		return makeUsage(2, 0)
 	case ADD, SUB, MUL, DIV, SDIV, MOD, SMOD, EXP, SIGNEXTEND,
 		SHA3, LT, GT, SLT, SGT, EQ, AND, XOR, OR, BYTE,
 		SHL, SHR, SAR:
 		return makeUsage(2, 1)
-	case CALLDATACOPY, CODECOPY, RETURNDATACOPY, MCOPY:
+	case CALLDATACOPY, CODECOPY, RETURNDATACOPY, MCOPY, DATACOPY:
 		return makeUsage(3, 0)
-	case ADDMOD, MULMOD, CREATE:
+	case ADDMOD, MULMOD, CREATE, EXTDELEGATECALL, EXTSTATICCALL:
 		return makeUsage(3, 1)
 	case EXTCODECOPY:
 		return makeUsage(4, 0)
-	case CREATE2:
+	case CREATE2, EOFCREATE, EXTCALL:
 		return makeUsage(4, 1)
+	case CALLF, RETF, JUMPF:
+		// The stack effect depends on the type of the targeted code section.
+		// It is established by the EOF validation and checked by the calls.
+		return makeUsage(0, 0)
 	case STATICCALL, DELEGATECALL:
 		return makeUsage(6, 1)
 	case CALL, CALLCODE:
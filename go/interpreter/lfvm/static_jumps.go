@@ -0,0 +1,173 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package lfvm
+
+import "math"
+
+// resolveStaticJumps replaces JUMP and JUMPI instructions with a constant
+// destination by their static counterparts. A destination is constant if it is
+// pushed by a PUSH instruction preceding the jump in a straight-line sequence
+// of instructions not touching the pushed value. Only destinations pointing to
+// a valid JUMPDEST are resolved, such that invalid jumps are still reported at
+// runtime. The code is modified in place.
+func resolveStaticJumps(code Code) {
+	for i := 0; i < len(code); i = code.next(i) {
+		destination, ok := getPushedConstant(code, i)
+		if !ok {
+			continue
+		}
+		jump, ok := findConsumingJump(code, code.next(i))
+		if !ok || !isValidStaticJumpDestination(code, destination) {
+			continue
+		}
+		// Like JUMP_TO, the argument is the distance to the destination, such
+		// that static jumps are not limited to the first 64K of the code.
+		distance := int64(destination) - int64(jump)
+		if distance < math.MinInt16 || distance > math.MaxInt16 {
+			continue
+		}
+		if code[jump].opcode == JUMP {
+			code[jump] = Instruction{JUMP_STATIC, uint16(int16(distance))}
+		} else {
+			code[jump] = Instruction{JUMPI_STATIC, uint16(int16(distance))}
+		}
+	}
+}
+
+// getPushedConstant returns the value pushed by the PUSH instruction at the
+// given position. Only values of up to 4 bytes are considered, since larger
+// values can not be valid jump destinations.
+func getPushedConstant(code Code, pos int) (uint64, bool) {
+	op := code[pos].opcode
+	if op < PUSH1 || op > PUSH4 {
+		return 0, false
+	}
+	value := uint64(code[pos].arg)
+	switch op {
+	case PUSH1:
+		value >>= 8
+	case PUSH3:
+		value = value<<8 | uint64(code[pos+1].arg>>8)
+	case PUSH4:
+		value = value<<16 | uint64(code[pos+1].arg)
+	}
+	return value, true
+}
+
+// findConsumingJump follows the straight-line sequence of instructions starting
+// at the given position and returns the position of a JUMP or JUMPI consuming
+// the value on top of the stack before the sequence as its destination.
+func findConsumingJump(code Code, pos int) (int, bool) {
+	depth := 0
+	for ; pos < len(code); pos = code.next(pos) {
+		op := code[pos].opcode
+		if op == JUMP || op == JUMPI {
+			return pos, depth == 0
+		}
+		// Only base instructions not altering the control flow are followed.
+		if !op.isBaseInstruction() || op == JUMPDEST {
+			return 0, false
+		}
+		if _, fallsThrough, _ := getSuccessors(code, pos); !fallsThrough {
+			return 0, false
+		}
+		// Instructions accessing the value end the search.
+		usage := computeStackUsage(op)
+		if -usage.from > depth {
+			return 0, false
+		}
+		depth += usage.delta
+	}
+	return 0, false
+}
+
+func isValidStaticJumpDestination(code Code, destination uint64) bool {
+	return destination < uint64(len(code)) &&
+		code[destination].opcode == JUMPDEST
+}
+
+// markUnreachableCode replaces all instructions that can not be reached from
+// the beginning of the code by INVALID instructions. NOOP instructions are
+// retained to preserve the structure of JUMP_TO gaps. JUMPDEST instructions
+// are only considered unreachable if the code contains no reachable dynamic
+// jump. The code is modified in place.
+func markUnreachableCode(code Code) {
+	reachable := make([]bool, len(code))
+	jumpDestinations := []int{}
+	for i, instruction := range code {
+		if instruction.opcode == JUMPDEST {
+			jumpDestinations = append(jumpDestinations, i)
+		}
+	}
+
+	worklist := []int{0}
+	dynamicJumpsFound := false
+	for len(worklist) > 0 {
+		pos := worklist[len(worklist)-1]
+		worklist = worklist[:len(worklist)-1]
+		for pos < len(code) && !reachable[pos] {
+			next := code.next(pos)
+			for i := pos; i < next; i++ {
+				reachable[i] = true
+			}
+
+			successors, fallsThrough, dynamic := getSuccessors(code, pos)
+			worklist = append(worklist, successors...)
+			if dynamic && !dynamicJumpsFound {
+				dynamicJumpsFound = true
+				worklist = append(worklist, jumpDestinations...)
+			}
+			if !fallsThrough {
+				break
+			}
+			pos = next
+		}
+	}
+
+	for i := range code {
+		if !reachable[i] && code[i].opcode != NOOP {
+			code[i] = Instruction{INVALID, 0}
+		}
+	}
+}
+
+// getSuccessors returns the explicit jump targets of the instruction at the
+// given position, whether the execution may continue with the next
+// instruction, and whether the instruction jumps to a dynamic destination.
+func getSuccessors(code Code, pos int) (targets []int, fallsThrough bool, dynamic bool) {
+	instruction := code[pos]
+	staticTarget := func(destination int) []int {
+		if destination < 0 || !isValidStaticJumpDestination(code, uint64(destination)) {
+			return nil
+		}
+		return []int{destination}
+	}
+	relativeTarget := pos + int(int16(instruction.arg))
+	switch instruction.opcode {
+	case STOP, RETURN, REVERT, INVALID, SELFDESTRUCT:
+		return nil, false, false
+	case JUMP_TO:
+		return []int{pos + int(instruction.arg)}, false, false
+	case JUMP_STATIC:
+		return staticTarget(relativeTarget), false, false
+	case JUMPI_STATIC:
+		return staticTarget(relativeTarget), true, false
+	case PUSH2_JUMP:
+		return staticTarget(int(instruction.arg)), false, false
+	case PUSH2_JUMPI, ISZERO_PUSH2_JUMPI:
+		return staticTarget(int(instruction.arg)), true, false
+	case JUMP, POP_JUMP, SWAP2_SWAP1_POP_JUMP:
+		return nil, false, true
+	case JUMPI:
+		return nil, true, true
+	}
+	return nil, true, false
+}
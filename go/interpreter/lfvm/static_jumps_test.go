@@ -0,0 +1,339 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package lfvm
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+	"github.com/holiman/uint256"
+)
+
+func TestResolveStaticJumps_ResolvesConstantJumpDestinations(t *testing.T) {
+	tests := map[string]struct {
+		code []byte
+		want Instruction
+	}{
+		"push1 jump": {
+			code: []byte{byte(vm.PUSH1), 3, byte(vm.JUMP), byte(vm.JUMPDEST)},
+			want: Instruction{JUMP_STATIC, 2},
+		},
+		"push2 jumpi": {
+			code: []byte{byte(vm.PUSH1), 1, byte(vm.PUSH2), 0, 6, byte(vm.JUMPI), byte(vm.JUMPDEST)},
+			want: Instruction{JUMPI_STATIC, 4},
+		},
+		"push4 jump": {
+			code: []byte{byte(vm.PUSH4), 0, 0, 0, 6, byte(vm.JUMP), byte(vm.JUMPDEST)},
+			want: Instruction{JUMP_STATIC, 4},
+		},
+		"across independent instructions": {
+			code: []byte{
+				byte(vm.PUSH1), 9,
+				byte(vm.PUSH1), 1,
+				byte(vm.PUSH1), 2,
+				byte(vm.ADD),
+				byte(vm.POP),
+				byte(vm.JUMP),
+				byte(vm.JUMPDEST),
+			},
+			want: Instruction{JUMP_STATIC, 4},
+		},
+		"across dup and swap not touching destination": {
+			code: []byte{
+				byte(vm.CALLVALUE),
+				byte(vm.PUSH1), 11,
+				byte(vm.CALLVALUE),
+				byte(vm.CALLVALUE),
+				byte(vm.SWAP1),
+				byte(vm.DUP1),
+				byte(vm.POP),
+				byte(vm.POP),
+				byte(vm.POP),
+				byte(vm.JUMP),
+				byte(vm.JUMPDEST),
+			},
+			want: Instruction{JUMP_STATIC, 2},
+		},
+		"backward jump": {
+			code: []byte{byte(vm.JUMPDEST), byte(vm.PUSH1), 0, byte(vm.JUMP)},
+			want: Instruction{JUMP_STATIC, uint16(0xffff - 1)},
+		},
+		"destination consumed": {
+			code: []byte{byte(vm.PUSH1), 4, byte(vm.ISZERO), byte(vm.JUMP), byte(vm.JUMPDEST)},
+			want: Instruction{JUMP, 0},
+		},
+		"destination swapped": {
+			code: []byte{byte(vm.PUSH1), 6, byte(vm.PUSH1), 0, byte(vm.SWAP1), byte(vm.JUMP), byte(vm.JUMPDEST)},
+			want: Instruction{JUMP, 0},
+		},
+		"destination not on top": {
+			code: []byte{byte(vm.PUSH1), 5, byte(vm.PUSH1), 0, byte(vm.JUMP), byte(vm.JUMPDEST)},
+			want: Instruction{JUMP, 0},
+		},
+		"across jump destination": {
+			code: []byte{byte(vm.PUSH1), 2, byte(vm.JUMPDEST), byte(vm.JUMP)},
+			want: Instruction{JUMP, 0},
+		},
+		"invalid destination": {
+			code: []byte{byte(vm.PUSH1), 3, byte(vm.JUMP), byte(vm.STOP)},
+			want: Instruction{JUMP, 0},
+		},
+		"destination in push data": {
+			code: []byte{byte(vm.PUSH1), 4, byte(vm.JUMP), byte(vm.PUSH1), byte(vm.JUMPDEST)},
+			want: Instruction{JUMP, 0},
+		},
+		"destination out of range": {
+			code: []byte{byte(vm.PUSH1), 9, byte(vm.JUMP)},
+			want: Instruction{JUMP, 0},
+		},
+		"destination too large": {
+			code: []byte{byte(vm.PUSH5), 1, 0, 0, 0, 0, byte(vm.JUMP)},
+			want: Instruction{JUMP, 0},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			code := convert(test.code, ConversionConfig{WithStaticJumps: true})
+			for _, instruction := range code {
+				switch instruction.opcode {
+				case JUMP, JUMPI, JUMP_STATIC, JUMPI_STATIC:
+					if want, got := test.want, instruction; want != got {
+						t.Errorf("unexpected jump, want %v, got %v", want, got)
+					}
+					return
+				}
+			}
+			t.Errorf("no jump found in %v", code)
+		})
+	}
+}
+
+func TestResolveStaticJumps_ResolvesJumpsBeyondFirst64KOfCode(t *testing.T) {
+	const destination = math.MaxUint16 + 10
+	code := bytes.Repeat([]byte{byte(vm.JUMPDEST)}, destination+1)
+	code[destination-5] = byte(vm.PUSH3)
+	code[destination-4] = destination >> 16
+	code[destination-3] = destination >> 8 & 0xff
+	code[destination-2] = destination & 0xff
+	code[destination-1] = byte(vm.JUMP)
+	code[destination] = byte(vm.JUMPDEST)
+
+	converted := convert(code, ConversionConfig{WithStaticJumps: true})
+	pos := len(converted) - 1
+	for pos > 0 && converted[pos].opcode != JUMP && converted[pos].opcode != JUMP_STATIC {
+		pos--
+	}
+	jump := converted[pos]
+	if want, got := JUMP_STATIC, jump.opcode; want != got {
+		t.Fatalf("unexpected jump, want %v, got %v", want, got)
+	}
+	if want, got := destination, pos+int(int16(jump.arg)); want != got {
+		t.Errorf("unexpected jump destination, want %d, got %d", want, got)
+	}
+}
+
+func TestResolveStaticJumps_DistantDestinationsRemainDynamic(t *testing.T) {
+	const destination = math.MaxInt16 + 10
+	code := make([]byte, destination+1)
+	code[0] = byte(vm.CALLVALUE)
+	code[1] = byte(vm.PUSH2)
+	code[2] = destination >> 8
+	code[3] = destination & 0xff
+	code[4] = byte(vm.JUMPI)
+	code[destination] = byte(vm.JUMPDEST)
+
+	converted := convert(code, ConversionConfig{WithStaticJumps: true})
+	for _, instruction := range converted {
+		if instruction.opcode == JUMP_STATIC || instruction.opcode == JUMPI_STATIC {
+			t.Errorf("jump to distant destination was resolved: %v", instruction)
+		}
+	}
+}
+
+func TestResolveStaticJumps_IsDisabledByDefault(t *testing.T) {
+	code := convert([]byte{byte(vm.PUSH1), 3, byte(vm.JUMP), byte(vm.JUMPDEST)}, ConversionConfig{})
+	if want, got := JUMP, code[1].opcode; want != got {
+		t.Errorf("unexpected instruction, want %v, got %v", want, got)
+	}
+}
+
+func TestMarkUnreachableCode_ReplacesUnreachableInstructionsByInvalid(t *testing.T) {
+	tests := map[string]struct {
+		code []byte
+		want []OpCode
+	}{
+		"code after stop": {
+			code: []byte{byte(vm.STOP), byte(vm.ADD), byte(vm.PUSH2), 1, 2},
+			want: []OpCode{STOP, INVALID, INVALID},
+		},
+		"skipped by static jump": {
+			code: []byte{byte(vm.PUSH1), 4, byte(vm.JUMP), byte(vm.ADD), byte(vm.JUMPDEST), byte(vm.STOP)},
+			want: []OpCode{PUSH1, JUMP_STATIC, INVALID, INVALID, JUMPDEST, STOP},
+		},
+		"both branches of conditional jump": {
+			code: []byte{byte(vm.CALLVALUE), byte(vm.PUSH1), 6, byte(vm.JUMPI), byte(vm.STOP), byte(vm.STOP), byte(vm.JUMPDEST)},
+			want: []OpCode{CALLVALUE, PUSH1, JUMPI_STATIC, STOP, INVALID, INVALID, JUMPDEST},
+		},
+		"unreachable jump destination": {
+			code: []byte{byte(vm.STOP), byte(vm.JUMPDEST), byte(vm.ADD)},
+			want: []OpCode{STOP, INVALID, INVALID},
+		},
+		"jump destinations reachable by dynamic jump": {
+			code: []byte{byte(vm.CALLVALUE), byte(vm.JUMP), byte(vm.ADD), byte(vm.JUMPDEST), byte(vm.ADD)},
+			want: []OpCode{CALLVALUE, JUMP, INVALID, JUMPDEST, ADD},
+		},
+		"push data of reachable instructions": {
+			code: []byte{byte(vm.PUSH4), 1, 2, 3, 4, byte(vm.STOP), byte(vm.PUSH4), 1, 2, 3, 4},
+			want: []OpCode{PUSH4, DATA, STOP, INVALID, INVALID},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			code := convert(test.code, ConversionConfig{WithStaticJumps: true})
+			got := []OpCode{}
+			for _, instruction := range code {
+				got = append(got, instruction.opcode)
+			}
+			if fmt.Sprint(test.want) != fmt.Sprint(got) {
+				t.Errorf("unexpected code, want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestStaticJumps_JumpToEncodedDestinationAndPopStack(t *testing.T) {
+	tests := map[string]struct {
+		op     OpCode
+		arg    uint16
+		pc     int32
+		stack  []uint64
+		wantPc int32
+	}{
+		"jump":          {op: JUMP_STATIC, arg: 5, stack: []uint64{7}, wantPc: 4},
+		"jumpi taken":   {op: JUMPI_STATIC, arg: 5, stack: []uint64{1, 7}, wantPc: 4},
+		"jumpi skipped": {op: JUMPI_STATIC, arg: 5, stack: []uint64{0, 7}, wantPc: 0},
+		"jump forward":  {op: JUMP_STATIC, arg: 5, pc: 2, stack: []uint64{7}, wantPc: 6},
+		"jump backward": {op: JUMP_STATIC, arg: uint16(0xffff - 2), pc: 9, stack: []uint64{7}, wantPc: 5},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctxt := getEmptyContext()
+			ctxt.code = make(Code, 10)
+			ctxt.code[test.pc] = Instruction{test.op, test.arg}
+			ctxt.pc = test.pc
+			for _, v := range test.stack {
+				ctxt.stack.push(uint256.NewInt(v))
+			}
+			if test.op == JUMP_STATIC {
+				opJumpStatic(&ctxt)
+			} else {
+				opJumpiStatic(&ctxt)
+			}
+			if want, got := test.wantPc, ctxt.pc; want != got {
+				t.Errorf("unexpected pc, want %d, got %d", want, got)
+			}
+			if want, got := 0, ctxt.stack.len(); want != got {
+				t.Errorf("unexpected stack size, want %d, got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestStaticJumps_ExecutionResultsMatchDynamicJumps(t *testing.T) {
+	codes := map[string][]byte{
+		"loop": {
+			byte(vm.PUSH1), 10,
+			byte(vm.JUMPDEST),
+			byte(vm.PUSH1), 1,
+			byte(vm.SWAP1),
+			byte(vm.SUB),
+			byte(vm.DUP1),
+			byte(vm.PUSH1), 2,
+			byte(vm.JUMPI),
+			byte(vm.GAS),
+			byte(vm.PUSH1), 0,
+			byte(vm.MSTORE),
+			byte(vm.PUSH1), 32,
+			byte(vm.PUSH1), 0,
+			byte(vm.RETURN),
+		},
+		"function call": {
+			byte(vm.PUSH1), 7, // return address
+			byte(vm.PUSH1), 42, // argument
+			byte(vm.PUSH1), 12, // function
+			byte(vm.JUMP),
+			byte(vm.JUMPDEST), // 7: return
+			byte(vm.PUSH1), 0,
+			byte(vm.MSTORE),
+			byte(vm.STOP),
+			byte(vm.JUMPDEST), // 12: function
+			byte(vm.PUSH1), 1,
+			byte(vm.ADD),
+			byte(vm.SWAP1),
+			byte(vm.JUMP),
+		},
+		"invalid jump": {
+			byte(vm.PUSH1), 3,
+			byte(vm.JUMP),
+			byte(vm.STOP),
+		},
+		"stack overflow before jump": {
+			byte(vm.JUMPDEST),
+			byte(vm.CALLVALUE),
+			byte(vm.PUSH1), 0,
+			byte(vm.JUMP),
+		},
+		"stack underflow before jump": {
+			byte(vm.PUSH1), 4,
+			byte(vm.POP),
+			byte(vm.POP),
+			byte(vm.JUMPDEST),
+			byte(vm.STOP),
+		},
+	}
+
+	for name, code := range codes {
+		for _, withSuperInstructions := range []bool{false, true} {
+			for _, gas := range []tosca.Gas{0, 10, 50, 100, 100_000} {
+				t.Run(fmt.Sprintf("%s/si=%t/%d", name, withSuperInstructions, gas), func(t *testing.T) {
+					params := tosca.Parameters{
+						BlockParameters: tosca.BlockParameters{Revision: tosca.R13_Cancun},
+						Gas:             gas,
+						Code:            code,
+					}
+					results := []tosca.Result{}
+					for _, withStaticJumps := range []bool{false, true} {
+						converted := newConvertedCode(code, ConversionConfig{
+							WithSuperInstructions: withSuperInstructions,
+							WithStaticJumps:       withStaticJumps,
+						}, defaultGasPrices)
+						result, err := run(config{}, params, converted.code, converted.blocks)
+						if err != nil {
+							t.Fatalf("unexpected error: %v", err)
+						}
+						results = append(results, result)
+					}
+					want, got := results[0], results[1]
+					if want.Success != got.Success || want.GasLeft != got.GasLeft || string(want.Output) != string(got.Output) {
+						t.Errorf("unexpected result, want %v, got %v", want, got)
+					}
+				})
+			}
+		}
+	}
+}
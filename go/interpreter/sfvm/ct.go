@@ -19,13 +19,23 @@ import (
 )
 
 func NewConformanceTestingTarget() ct.Evm {
-
 	// Can only fail for invalid configuration. Configuration is hardcoded.
-	sanctionedVm, _ := NewInterpreter(Config{})
+	target, _ := NewConformanceTestingTargetWithGasSchedule(nil)
+	return target
+}
 
+// NewConformanceTestingTargetWithGasSchedule creates a conformance testing
+// target charging the prices of the given gas schedule. It is to be checked
+// against a specification using the same schedule (see
+// spc.NewSpecificationWithGasSchedule).
+func NewConformanceTestingTargetWithGasSchedule(schedule *tosca.GasSchedule) (ct.Evm, error) {
+	sanctionedVm, err := NewInterpreter(Config{GasSchedule: schedule})
+	if err != nil {
+		return nil, err
+	}
 	return &ctAdapter{
 		vm: sanctionedVm,
-	}
+	}, nil
 }
 
 type ctAdapter struct {
@@ -56,6 +66,7 @@ func (a *ctAdapter) StepN(state *st.State, numSteps int) (*st.State, error) {
 		memory:       memory,
 		code:         params.Code,
 		analysis:     a.vm.analysis.analyzeJumpDest(params.Code, params.CodeHash),
+		prices:       a.vm.prices,
 		returnData:   state.LastCallReturnData.ToBytes(),
 		withShaCache: a.vm.config.WithShaCache,
 	}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/0xsoniclabs/tosca/go/ct"
 	cc "github.com/0xsoniclabs/tosca/go/ct/common"
+	"github.com/0xsoniclabs/tosca/go/ct/spc"
 	"github.com/0xsoniclabs/tosca/go/ct/st"
 	"github.com/0xsoniclabs/tosca/go/tosca"
 	"github.com/0xsoniclabs/tosca/go/tosca/vm"
@@ -53,6 +54,82 @@ func TestCtAdapter_Add(t *testing.T) {
 	}
 }
 
+func TestCtAdapter_ConformsToSpecificationWithGasSchedule(t *testing.T) {
+	schedule := &tosca.GasSchedule{
+		Static: []tosca.StaticGasOverride{
+			{OpCode: vm.ADD, Revision: tosca.R07_Istanbul, Price: 1},
+			{OpCode: vm.ADD, Revision: tosca.R13_Cancun, Price: 7},
+			{OpCode: vm.SLOAD, Revision: tosca.R09_Berlin, Price: 4},
+		},
+		Dynamic: []tosca.DynamicGasOverride{
+			{Kind: tosca.WarmSloadGas, Revision: tosca.R10_London, Price: 10},
+			{Kind: tosca.ColdSloadGas, Revision: tosca.R09_Berlin, Price: 500},
+			{Kind: tosca.WarmAccountAccessGas, Revision: tosca.R09_Berlin, Price: 7},
+			{Kind: tosca.ColdAccountAccessGas, Revision: tosca.R10_London, Price: 300},
+			{Kind: tosca.WarmSstoreGas, Revision: tosca.R09_Berlin, Price: 20},
+			{Kind: tosca.WarmSstoreGas, Revision: tosca.R13_Cancun, Price: 1000},
+			{Kind: tosca.ColdSstoreSurchargeGas, Revision: tosca.R09_Berlin, Price: 50},
+		},
+	}
+	spec := spc.NewSpecificationWithGasSchedule(spc.Spec, schedule)
+	evm, err := NewConformanceTestingTargetWithGasSchedule(schedule)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	key := cc.NewU256(5)
+	address := cc.NewU256(42)
+	inputs := map[string]struct {
+		op    vm.OpCode
+		stack []cc.U256
+		warm  bool
+	}{
+		"add":                {op: vm.ADD, stack: []cc.U256{cc.NewU256(1), cc.NewU256(2)}},
+		"sload cold":         {op: vm.SLOAD, stack: []cc.U256{key}},
+		"sload warm":         {op: vm.SLOAD, stack: []cc.U256{key}, warm: true},
+		"mul":                {op: vm.MUL, stack: []cc.U256{cc.NewU256(1), cc.NewU256(2)}},
+		"balance cold":       {op: vm.BALANCE, stack: []cc.U256{address}},
+		"balance warm":       {op: vm.BALANCE, stack: []cc.U256{address}, warm: true},
+		"sstore cold add":    {op: vm.SSTORE, stack: []cc.U256{cc.NewU256(1), key}},
+		"sstore warm assign": {op: vm.SSTORE, stack: []cc.U256{cc.NewU256(0), key}, warm: true},
+	}
+	gasLevels := []tosca.Gas{
+		0, 1, 2, 3, 4, 5, 6, 7, 8, 13, 14, 15, 99, 100, 103, 104, 300, 301, 504, 505,
+		2100, 2101, 2300, 2301, 2320, 2321, 3300, 3301, 5000, 20049, 20050, 22100, 22101,
+	}
+
+	for name, input := range inputs {
+		for _, revision := range cc.AllSupportedRevisions() {
+			for _, gas := range gasLevels {
+				state := st.NewState(st.NewCode([]byte{byte(input.op)}))
+				state.Status = st.Running
+				state.Revision = revision
+				state.Gas = gas
+				state.Stack = st.NewStack(input.stack...)
+				state.Storage = st.NewStorageBuilder().SetWarm(key, input.warm).Build()
+				if input.warm {
+					state.Accounts.MarkWarm(address.Bytes20be())
+				}
+
+				rules := spec.GetRulesFor(state)
+				if len(rules) == 0 {
+					t.Fatalf("no rule for %s in %v with gas %d", name, revision, gas)
+				}
+				want := state.Clone()
+				rules[0].Effect.Apply(want)
+
+				got, err := evm.StepN(state.Clone(), 1)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !want.Eq(got) {
+					t.Errorf("unexpected result for %s in %v with gas %d, want %v, got %v", name, revision, gas, want, got)
+				}
+			}
+		}
+	}
+}
+
 func TestCtAdapter_Interface(t *testing.T) {
 	// Compile time check that ctAdapter implements the st.Evm interface.
 	var _ ct.Evm = &ctAdapter{}
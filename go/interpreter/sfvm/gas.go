@@ -11,6 +11,8 @@
 package sfvm
 
 import (
+	"fmt"
+
 	"github.com/0xsoniclabs/tosca/go/tosca"
 	"github.com/0xsoniclabs/tosca/go/tosca/vm"
 )
@@ -33,8 +35,52 @@ const (
 	UNKNOWN_GAS_PRICE = 999999
 )
 
-var static_gas_prices = newOpCodePropertyMap(getStaticGasPriceInternal)
-var static_gas_prices_berlin = newOpCodePropertyMap(getBerlinGasPriceInternal)
+// gasPrices are the prices of a gas schedule, prepared for the lookup during
+// the execution of instructions.
+type gasPrices struct {
+	// static are the static gas prices per revision.
+	static [newestSupportedRevision + 1]opCodePropertyMap[tosca.Gas]
+	// dynamic lists the prices of the dynamic gas kinds of each revision,
+	// indexed by the kind.
+	dynamic [newestSupportedRevision + 1][]tosca.Gas
+}
+
+// defaultGasPrices are the gas prices defined by Ethereum.
+var defaultGasPrices = func() *gasPrices {
+	// can only fail for invalid schedules
+	prices, _ := newGasPrices(nil)
+	return prices
+}()
+
+// newGasPrices prepares the prices of the given schedule for the use by the
+// interpreter. A nil schedule results in the Ethereum gas prices.
+func newGasPrices(schedule *tosca.GasSchedule) (*gasPrices, error) {
+	if err := schedule.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid gas schedule: %w", err)
+	}
+	res := &gasPrices{}
+	for revision := tosca.R07_Istanbul; revision <= newestSupportedRevision; revision++ {
+		res.static[revision] = newOpCodePropertyMap(func(op vm.OpCode) tosca.Gas {
+			if price, found := schedule.GetStaticGas(op, revision); found {
+				return price
+			}
+			if revision >= tosca.R09_Berlin {
+				return getBerlinGasPriceInternal(op)
+			}
+			return getStaticGasPriceInternal(op)
+		})
+		for _, kind := range tosca.GetAllDynamicGasKinds() {
+			res.dynamic[revision] = append(res.dynamic[revision], schedule.GetDynamicGas(kind, revision))
+		}
+	}
+	return res, nil
+}
+
+// getDynamicGasPrice returns the price of the given dynamic gas kind in the
+// given revision.
+func (p *gasPrices) getDynamicGasPrice(kind tosca.DynamicGasKind, revision tosca.Revision) tosca.Gas {
+	return p.dynamic[revision][kind]
+}
 
 // numOpCodes is the number of opcodes in the EVM.
 const numOpCodes = 256
@@ -92,13 +138,6 @@ func getBerlinGasPriceInternal(op vm.OpCode) tosca.Gas {
 	return gp
 }
 
-func getStaticGasPrices(revision tosca.Revision) *opCodePropertyMap[tosca.Gas] {
-	if revision >= tosca.R09_Berlin {
-		return &static_gas_prices_berlin
-	}
-	return &static_gas_prices
-}
-
 func getStaticGasPriceInternal(op vm.OpCode) tosca.Gas {
 	if vm.PUSH1 <= op && op <= vm.PUSH32 {
 		return 3
@@ -260,6 +299,7 @@ func getStaticGasPriceInternal(op vm.OpCode) tosca.Gas {
 func getDynamicCostsForSstore(
 	revision tosca.Revision,
 	storageStatus tosca.StorageStatus,
+	prices *gasPrices,
 ) tosca.Gas {
 	switch storageStatus {
 	case tosca.StorageAdded:
@@ -273,7 +313,7 @@ func getDynamicCostsForSstore(
 		}
 	default:
 		if revision >= tosca.R09_Berlin {
-			return 100
+			return prices.getDynamicGasPrice(tosca.WarmSstoreGas, revision)
 		}
 		return 800
 	}
@@ -14,8 +14,79 @@ import (
 	"testing"
 
 	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
 )
 
+// --- Gas Schedules ---
+
+func TestGasPrices_DefaultPricesAreEthereumPrices(t *testing.T) {
+	for revision := tosca.R07_Istanbul; revision <= newestSupportedRevision; revision++ {
+		for i := range numOpCodes {
+			op := vm.OpCode(i)
+			want := getStaticGasPriceInternal(op)
+			if revision >= tosca.R09_Berlin {
+				want = getBerlinGasPriceInternal(op)
+			}
+			if got := defaultGasPrices.static[revision].get(op); want != got {
+				t.Errorf("unexpected price of %v in %v, want %d, got %d", op, revision, want, got)
+			}
+		}
+		for _, kind := range tosca.GetAllDynamicGasKinds() {
+			if want, got := kind.EthereumPrice(), defaultGasPrices.getDynamicGasPrice(kind, revision); want != got {
+				t.Errorf("unexpected price of %v in %v, want %d, got %d", kind, revision, want, got)
+			}
+		}
+	}
+}
+
+func TestGasPrices_OverridesOfScheduleAreApplied(t *testing.T) {
+	prices, err := newGasPrices(&tosca.GasSchedule{
+		Static: []tosca.StaticGasOverride{
+			{OpCode: vm.ADD, Revision: tosca.R13_Cancun, Price: 1},
+		},
+		Dynamic: []tosca.DynamicGasOverride{
+			{Kind: tosca.WarmSloadGas, Revision: tosca.R10_London, Price: 5},
+			{Kind: tosca.WarmSstoreGas, Revision: tosca.R11_Paris, Price: 7},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want, got := tosca.Gas(3), prices.static[tosca.R12_Shanghai].get(vm.ADD); want != got {
+		t.Errorf("unexpected price of ADD in Shanghai, want %d, got %d", want, got)
+	}
+	if want, got := tosca.Gas(1), prices.static[tosca.R13_Cancun].get(vm.ADD); want != got {
+		t.Errorf("unexpected price of ADD in Cancun, want %d, got %d", want, got)
+	}
+
+	dynamicTests := []struct {
+		kind     tosca.DynamicGasKind
+		revision tosca.Revision
+		want     tosca.Gas
+	}{
+		{tosca.WarmSloadGas, tosca.R09_Berlin, 100},
+		{tosca.WarmSloadGas, tosca.R10_London, 5},
+		{tosca.WarmSstoreGas, tosca.R10_London, 100},
+		{tosca.WarmSstoreGas, tosca.R11_Paris, 7},
+	}
+	for _, test := range dynamicTests {
+		if want, got := test.want, prices.getDynamicGasPrice(test.kind, test.revision); want != got {
+			t.Errorf("unexpected price of %v in %v, want %d, got %d", test.kind, test.revision, want, got)
+		}
+	}
+}
+
+func TestGasPrices_InvalidSchedulesAreRejected(t *testing.T) {
+	_, err := NewInterpreter(Config{
+		GasSchedule: &tosca.GasSchedule{
+			Static: []tosca.StaticGasOverride{{OpCode: vm.ADD, Price: -1}},
+		},
+	})
+	if err == nil {
+		t.Errorf("expected error for invalid gas schedule")
+	}
+}
+
 // --- SStore ---
 
 func TestGas_getDynamicCostsForSstore_exhaustive(t *testing.T) {
@@ -71,7 +142,7 @@ func TestGas_getDynamicCostsForSstore_exhaustive(t *testing.T) {
 		}
 		for storageStatus, example := range getStorageStateExamples() {
 			want := spec(example)
-			got := getDynamicCostsForSstore(revision, storageStatus)
+			got := getDynamicCostsForSstore(revision, storageStatus, defaultGasPrices)
 			if got != want {
 				t.Errorf(
 					"unexpected result for (%v,%v), wanted %d, got %d",
@@ -41,6 +41,15 @@ func checkJumpDest(c *context) error {
 	return nil
 }
 
+// opJumpDest checks whether the execution has been canceled. Since every loop
+// passes a JUMPDEST, this bounds the time until a cancellation takes effect.
+func opJumpDest(c *context) error {
+	if c.params.IsCanceled() {
+		return tosca.ErrCanceled
+	}
+	return nil
+}
+
 func opJump(c *context) error {
 	destination := c.stack.pop()
 	// overflow check
@@ -195,12 +204,12 @@ func opSstore(c *context) error {
 	cost := tosca.Gas(0)
 	if c.isAtLeast(tosca.R09_Berlin) &&
 		c.context.AccessStorage(c.params.Recipient, key) == tosca.ColdAccess {
-		cost += 2100
+		cost += c.getDynamicGasPrice(tosca.ColdSstoreSurchargeGas)
 	}
 
 	storageStatus := c.context.SetStorage(c.params.Recipient, key, value)
 
-	cost += getDynamicCostsForSstore(c.params.Revision, storageStatus)
+	cost += getDynamicCostsForSstore(c.params.Revision, storageStatus, c.gasPrices())
 	if err := c.useGas(cost); err != nil {
 		return err
 	}
@@ -216,9 +225,9 @@ func opSload(c *context) error {
 	slot := tosca.Key(top.Bytes32())
 	if c.isAtLeast(tosca.R09_Berlin) {
 		// charge costs for warm/cold slot access
-		costs := tosca.Gas(100)
+		costs := c.getDynamicGasPrice(tosca.WarmSloadGas)
 		if c.context.AccessStorage(addr, slot) == tosca.ColdAccess {
-			costs = 2100
+			costs = c.getDynamicGasPrice(tosca.ColdSloadGas)
 		}
 		if err := c.useGas(costs); err != nil {
 			return err
@@ -574,7 +583,7 @@ func opBalance(c *context) error {
 	slot := c.stack.peek()
 	address := tosca.Address(slot.Bytes20())
 	if c.isAtLeast(tosca.R09_Berlin) {
-		if err := c.useGas(getAccessCost(c.context.AccessAccount(address))); err != nil {
+		if err := c.useGas(c.getAccessCost(c.context.AccessAccount(address))); err != nil {
 			return err
 		}
 	}
@@ -635,7 +644,7 @@ func opSelfdestruct(c *context) (status, error) {
 		// as https://eips.ethereum.org/EIPS/eip-2929#selfdestruct-changes says,
 		// selfdestruct does not charge for warm access
 		if accessStatus := c.context.AccessAccount(beneficiary); accessStatus != tosca.WarmAccess {
-			cost += getAccessCost(accessStatus)
+			cost += c.getAccessCost(accessStatus)
 		}
 	}
 
@@ -718,7 +727,7 @@ func opExtcodesize(c *context) error {
 	top := c.stack.peek()
 	address := tosca.Address(top.Bytes20())
 	if c.isAtLeast(tosca.R09_Berlin) {
-		if err := c.useGas(getAccessCost(c.context.AccessAccount(address))); err != nil {
+		if err := c.useGas(c.getAccessCost(c.context.AccessAccount(address))); err != nil {
 			return err
 		}
 	}
@@ -730,7 +739,7 @@ func opExtcodehash(c *context) error {
 	slot := c.stack.peek()
 	address := tosca.Address(slot.Bytes20())
 	if c.isAtLeast(tosca.R09_Berlin) {
-		if err := c.useGas(getAccessCost(c.context.AccessAccount(address))); err != nil {
+		if err := c.useGas(c.getAccessCost(c.context.AccessAccount(address))); err != nil {
 			return err
 		}
 	}
@@ -799,14 +808,21 @@ func genericCreate(c *context, kind tosca.CallKind) error {
 	nestedCallGas := c.gas
 	nestedCallGas -= nestedCallGas / 64
 
+	// Memory buffers are recycled after the execution, so the init code is
+	// copied to remain valid for the context.
 	res, err := c.context.Call(kind, tosca.CallParameters{
 		Sender: c.params.Recipient,
 		Value:  tosca.Value(value.Bytes32()),
-		Input:  input,
+		Input:  bytes.Clone(input),
 		Gas:    nestedCallGas,
 		Salt:   salt,
 	})
 
+	// A cancellation during the nested call aborts this execution as well.
+	if c.params.IsCanceled() {
+		return tosca.ErrCanceled
+	}
+
 	// Push item on the stack based on the returned error.
 	success := c.stack.pushUndefined()
 	if !res.Success || err != nil {
@@ -883,7 +899,7 @@ func opExtCodeCopy(c *context) error {
 	address := c.stack.pop().Bytes20()
 
 	if c.isAtLeast(tosca.R09_Berlin) {
-		if err := c.useGas(getAccessCost(c.context.AccessAccount(address))); err != nil {
+		if err := c.useGas(c.getAccessCost(c.context.AccessAccount(address))); err != nil {
 			return err
 		}
 	}
@@ -891,13 +907,13 @@ func opExtCodeCopy(c *context) error {
 	return genericDataCopy(c, c.context.GetCode(address))
 }
 
-func getAccessCost(accessStatus tosca.AccessStatus) tosca.Gas {
-	// EIP-2929 says that cold access cost is 2600 and warm is 100.
-	// (https://eips.ethereum.org/EIPS/eip-2929)
+// getAccessCost returns the price of accessing a warm or cold account as
+// introduced by EIP-2929 (https://eips.ethereum.org/EIPS/eip-2929).
+func (c *context) getAccessCost(accessStatus tosca.AccessStatus) tosca.Gas {
 	if accessStatus == tosca.ColdAccess {
-		return tosca.Gas(2600)
+		return c.getDynamicGasPrice(tosca.ColdAccountAccessGas)
 	}
-	return tosca.Gas(100)
+	return c.getDynamicGasPrice(tosca.WarmAccountAccessGas)
 }
 
 func genericCall(c *context, kind tosca.CallKind) error {
@@ -929,7 +945,7 @@ func genericCall(c *context, kind tosca.CallKind) error {
 
 	// from berlin onwards access cost changes depending on warm/cold access.
 	if c.isAtLeast(tosca.R09_Berlin) {
-		if err := c.useGas(getAccessCost(c.context.AccessAccount(toAddr))); err != nil {
+		if err := c.useGas(c.getAccessCost(c.context.AccessAccount(toAddr))); err != nil {
 			return err
 		}
 	}
@@ -938,7 +954,7 @@ func genericCall(c *context, kind tosca.CallKind) error {
 	if c.isAtLeast(tosca.R14_Prague) {
 		target, isDelegation := parseDelegationDesignation(c.context.GetCode(toAddr))
 		if isDelegation {
-			if err := c.useGas(getAccessCost(c.context.AccessAccount(target))); err != nil {
+			if err := c.useGas(c.getAccessCost(c.context.AccessAccount(target))); err != nil {
 				return err
 			}
 		}
@@ -996,9 +1012,11 @@ func genericCall(c *context, kind tosca.CallKind) error {
 		kind = tosca.StaticCall
 	}
 
-	// Prepare arguments, depending on call kind
+	// Prepare arguments, depending on call kind. Memory buffers are recycled
+	// after the execution, so the input is copied to remain valid for the
+	// context.
 	callParams := tosca.CallParameters{
-		Input:       args,
+		Input:       bytes.Clone(args),
 		Gas:         nestedCallGas,
 		Value:       tosca.Value(value.Bytes32()),
 		CodeAddress: toAddr,
@@ -1022,6 +1040,11 @@ func genericCall(c *context, kind tosca.CallKind) error {
 	// Perform the call.
 	ret, err := c.context.Call(kind, callParams)
 
+	// A cancellation during the nested call aborts this execution as well.
+	if c.params.IsCanceled() {
+		return tosca.ErrCanceled
+	}
+
 	if err == nil {
 		copy(output, ret.Output)
 	}
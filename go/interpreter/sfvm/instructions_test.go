@@ -975,10 +975,33 @@ func TestGenericDataCopy_ReturnsErrorOn(t *testing.T) {
 }
 
 func TestGetAccessCost_RespondsWithProperGasPrice(t *testing.T) {
-	if want, got := tosca.Gas(100), getAccessCost(tosca.WarmAccess); want != got {
+	c := context{params: tosca.Parameters{BlockParameters: tosca.BlockParameters{Revision: tosca.R09_Berlin}}}
+	if want, got := tosca.Gas(100), c.getAccessCost(tosca.WarmAccess); want != got {
 		t.Errorf("unexpected gas cost, wanted %d, got %d", want, got)
 	}
-	if want, got := tosca.Gas(2600), getAccessCost(tosca.ColdAccess); want != got {
+	if want, got := tosca.Gas(2600), c.getAccessCost(tosca.ColdAccess); want != got {
+		t.Errorf("unexpected gas cost, wanted %d, got %d", want, got)
+	}
+}
+
+func TestGetAccessCost_ChargesPricesOfGasSchedule(t *testing.T) {
+	prices, err := newGasPrices(&tosca.GasSchedule{
+		Dynamic: []tosca.DynamicGasOverride{
+			{Kind: tosca.WarmAccountAccessGas, Revision: tosca.R09_Berlin, Price: 10},
+			{Kind: tosca.ColdAccountAccessGas, Revision: tosca.R09_Berlin, Price: 20},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c := context{
+		params: tosca.Parameters{BlockParameters: tosca.BlockParameters{Revision: tosca.R09_Berlin}},
+		prices: prices,
+	}
+	if want, got := tosca.Gas(10), c.getAccessCost(tosca.WarmAccess); want != got {
+		t.Errorf("unexpected gas cost, wanted %d, got %d", want, got)
+	}
+	if want, got := tosca.Gas(20), c.getAccessCost(tosca.ColdAccess); want != got {
 		t.Errorf("unexpected gas cost, wanted %d, got %d", want, got)
 	}
 }
@@ -1834,6 +1857,44 @@ func TestGenericCall_HandlesBigProvidedGasValues(t *testing.T) {
 	}
 }
 
+func TestGenericCall_InputIsRetainedIfReturnAreaReallocatesMemory(t *testing.T) {
+	zero := *uint256.NewInt(0)
+	input := bytes.Repeat([]byte{0x11}, 32)
+	retSize := uint64(4 * minPooledMemorySize) // < exceeds the initial buffer
+
+	runContext := tosca.NewMockRunContext(gomock.NewController(t))
+	runContext.EXPECT().Call(tosca.Call, gomock.Any()).DoAndReturn(
+		func(_ tosca.CallKind, params tosca.CallParameters) (tosca.CallResult, error) {
+			// The memory of the nested call may reuse recycled buffers.
+			nested := NewMemory()
+			defer ReturnMemory(nested)
+			if err := nested.expandMemory(0, minPooledMemorySize, &context{gas: 1_000_000}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(input, params.Input) {
+				t.Errorf("unexpected input, wanted %x, got %x", input, params.Input)
+			}
+			return tosca.CallResult{Success: true}, nil
+		})
+
+	ctxt := getEmptyContext()
+	ctxt.context = runContext
+	ctxt.gas = 1_000_000
+	defer ReturnMemory(ctxt.memory)
+	if err := ctxt.memory.set(uint256.NewInt(0), input, &ctxt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctxt.stack = fillStack(
+		zero, zero, zero, // gas, address, value
+		zero, *uint256.NewInt(uint64(len(input))), // input
+		zero, *uint256.NewInt(retSize), // output
+	)
+
+	if err := genericCall(&ctxt, tosca.Call); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestGenericCall_ForwardsCallParamsDependingOnCallKind(t *testing.T) {
 
 	zero := *uint256.NewInt(0)
@@ -11,7 +11,9 @@
 package sfvm
 
 import (
+	"bytes"
 	"fmt"
+	"sync"
 
 	"github.com/0xsoniclabs/tosca/go/tosca"
 	"github.com/0xsoniclabs/tosca/go/tosca/vm"
@@ -27,18 +29,21 @@ const (
 	statusReturned                     // < execution stopped with a RETURN
 	statusSelfDestructed               // < execution stopped with a SELF-DESTRUCT
 	statusFailed                       // < execution stopped with a logic error
+	statusCanceled                     // < execution aborted by a cancellation signal
 )
 
 // context is the execution environment of an interpreter run. It contains all
 // the necessary state to execute a contract, including input parameters, the
 // contract code, and internal execution state such as the program counter,
-// stack, and memory. For each contract execution, a new context is created.
+// stack, and memory. For each contract execution, a context is obtained from
+// a reuse pool.
 type context struct {
 	// Inputs
 	params   tosca.Parameters
 	context  tosca.RunContext
 	code     tosca.Code
 	analysis jumpDestMap
+	prices   *gasPrices // the gas prices to be charged, the Ethereum prices if nil
 
 	// Execution state
 	pc     int32
@@ -66,14 +71,58 @@ func (c *context) useGas(amount tosca.Gas) error {
 	return nil
 }
 
+// gasPrices returns the gas prices to be charged by the execution.
+func (c *context) gasPrices() *gasPrices {
+	if c.prices == nil {
+		return defaultGasPrices
+	}
+	return c.prices
+}
+
+// getDynamicGasPrice returns the price of the given dynamic gas kind to be
+// charged by the execution.
+func (c *context) getDynamicGasPrice(kind tosca.DynamicGasKind) tosca.Gas {
+	return c.gasPrices().getDynamicGasPrice(kind, c.params.Revision)
+}
+
 // isAtLeast returns true if the interpreter is is running at least at the given
 // revision or newer, false otherwise.
 func (c *context) isAtLeast(revision tosca.Revision) bool {
 	return c.params.Revision >= revision
 }
 
+// contextPool recycles contexts across interpreter runs. Since each run,
+// including runs of nested calls, obtains its own context, the number of
+// pooled contexts is bounded by the maximum call depth of concurrent runs.
+var contextPool = sync.Pool{
+	New: func() any {
+		return &context{}
+	},
+}
+
+// newContext obtains a context from the reuse pool. The content of the
+// context is undefined and needs to be initialized by the caller.
+func newContext() *context {
+	return contextPool.Get().(*context)
+}
+
+// returnContext releases the stack and memory of the given context and
+// returns it to the reuse pool. References held by the context are cleared
+// to not retain any data of the finished run.
+func returnContext(c *context) {
+	if c.stack != nil {
+		ReturnStack(c.stack)
+	}
+	if c.memory != nil {
+		ReturnMemory(c.memory)
+	}
+	*c = context{}
+	contextPool.Put(c)
+}
+
 func run(
 	analysis analysis,
+	prices *gasPrices,
 	config Config,
 	params tosca.Parameters,
 ) (tosca.Result, error) {
@@ -87,7 +136,9 @@ func run(
 	}
 
 	// Set up execution context.
-	var ctxt = context{
+	ctxt := newContext()
+	defer returnContext(ctxt)
+	*ctxt = context{
 		params:       params,
 		context:      params.Context,
 		gas:          params.Gas,
@@ -95,12 +146,12 @@ func run(
 		memory:       NewMemory(),
 		code:         params.Code,
 		analysis:     analysis.analyzeJumpDest(params.Code, params.CodeHash),
+		prices:       prices,
 		withShaCache: config.WithShaCache,
 	}
-	defer ReturnStack(ctxt.stack)
 
-	status := execute(&ctxt, false)
-	return generateResult(status, &ctxt)
+	status := execute(ctxt, false)
+	return generateResult(status, ctxt)
 }
 
 func generateResult(status status, ctxt *context) (tosca.Result, error) {
@@ -115,20 +166,23 @@ func generateResult(status status, ctxt *context) (tosca.Result, error) {
 	case statusReturned:
 		return tosca.Result{
 			Success:   true,
-			Output:    ctxt.returnData,
+			Output:    bytes.Clone(ctxt.returnData),
 			GasLeft:   ctxt.gas,
 			GasRefund: ctxt.refund,
 		}, nil
 	case statusReverted:
 		return tosca.Result{
-			Success: false,
-			Output:  ctxt.returnData,
-			GasLeft: ctxt.gas,
+			Success:  false,
+			Reverted: true,
+			Output:   bytes.Clone(ctxt.returnData),
+			GasLeft:  ctxt.gas,
 		}, nil
 	case statusFailed:
 		return tosca.Result{
 			Success: false,
 		}, nil
+	case statusCanceled:
+		return tosca.Result{}, tosca.ErrCanceled
 	default:
 		return tosca.Result{}, fmt.Errorf("unexpected error in interpreter, unknown status: %v", status)
 	}
@@ -139,9 +193,13 @@ func generateResult(status status, ctxt *context) (tosca.Result, error) {
 // execute runs the contract code in the given context. If oneStepOnly is true,
 // only the instruction pointed to by the program counter will be executed.
 // If the contract execution yields any execution violation (i.e. out of gas,
-// stack underflow, etc), the function returns statusFailed
+// stack underflow, etc), the function returns statusFailed. If the execution
+// has been canceled, statusCanceled is returned.
 func execute(c *context, oneStepOnly bool) status {
 	status, error := steps(c, oneStepOnly)
+	if error == tosca.ErrCanceled {
+		return statusCanceled
+	}
 	if error != nil {
 		return statusFailed
 	}
@@ -154,7 +212,7 @@ func execute(c *context, oneStepOnly bool) status {
 // steps returns the status of the execution and an error if the contract
 // execution yields any execution violation (i.e. out of gas, stack underflow, etc).
 func steps(c *context, oneStepOnly bool) (status, error) {
-	staticGasPrices := getStaticGasPrices(c.params.Revision)
+	staticGasPrices := &c.gasPrices().static[c.params.Revision]
 
 	status := statusRunning
 	for status == statusRunning {
@@ -199,7 +257,7 @@ func steps(c *context, oneStepOnly bool) (status, error) {
 		case vm.JUMP:
 			err = opJump(c)
 		case vm.JUMPDEST:
-			// nothing
+			err = opJumpDest(c)
 		case vm.SWAP1:
 			opSwap(c, 1)
 		case vm.SWAP2:
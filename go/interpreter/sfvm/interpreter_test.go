@@ -291,7 +291,7 @@ func TestInterpreter_Vanilla_RunsWithoutOutput(t *testing.T) {
 	os.Stdout = w
 
 	// Run testing code
-	_, err := run(analysis{}, Config{}, params)
+	_, err := run(analysis{}, nil, Config{}, params)
 	// read the output
 	_ = w.Close() // ignore error in test
 	out, _ := io.ReadAll(r)
@@ -311,7 +311,7 @@ func TestInterpreter_EmptyCodeBypassesRunnerAndSucceeds(t *testing.T) {
 	params := tosca.Parameters{Code: code}
 	config := Config{}
 
-	result, err := run(analysis{}, config, params)
+	result, err := run(analysis{}, nil, config, params)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -345,6 +345,7 @@ func TestRun_GenerateResult(t *testing.T) {
 			status: statusReverted,
 			expectedResult: tosca.Result{
 				Success:   false,
+				Reverted:  true,
 				Output:    baseOutput,
 				GasLeft:   baseGas,
 				GasRefund: 0,
@@ -374,9 +375,14 @@ func TestRun_GenerateResult(t *testing.T) {
 				Success: false,
 			},
 		},
+		"canceled": {
+			status:         statusCanceled,
+			expectedErr:    tosca.ErrCanceled,
+			expectedResult: tosca.Result{},
+		},
 		"unknown status": {
-			status:         statusFailed + 1,
-			expectedErr:    fmt.Errorf("unexpected error in interpreter, unknown status: %v", statusFailed+1),
+			status:         statusCanceled + 1,
+			expectedErr:    fmt.Errorf("unexpected error in interpreter, unknown status: %v", statusCanceled+1),
 			expectedResult: tosca.Result{},
 		},
 	}
@@ -412,6 +418,7 @@ func TestSteps_DetectsNonExecutableCode(t *testing.T) {
 			return undefinedOpCodeRegex.MatchString(op.String())
 		}
 	nonExecutableOpCodes = append(nonExecutableOpCodes, allOpCodesWhere(isUndefined)...)
+	nonExecutableOpCodes = append(nonExecutableOpCodes, allOpCodesWhere(isEofInstruction)...)
 
 	for _, opCode := range nonExecutableOpCodes {
 		t.Run(opCode.String(), func(t *testing.T) {
@@ -490,7 +497,7 @@ func TestSteps_FailsWithLessGasThanStaticCost(t *testing.T) {
 		t.Run(op.String(), func(t *testing.T) {
 			forEachRevision(t, op, func(t *testing.T, revision tosca.Revision) {
 
-				expectedGas := getStaticGasPrices(revision).get(op)
+				expectedGas := defaultGasPrices.static[revision].get(op)
 				if expectedGas == 0 {
 					t.Skip("operation has static cost zero")
 				}
@@ -629,12 +636,18 @@ func fillStackFor(op vm.OpCode, stack *stack, code tosca.Code) error {
 var _isUndefinedOpCodeRegex = regexp.MustCompile(`^op\(0x[0-9A-Fa-f]+\)$`)
 
 func isExecutable(op vm.OpCode) bool {
-	if slices.Contains([]vm.OpCode{vm.INVALID}, op) {
+	if slices.Contains([]vm.OpCode{vm.INVALID}, op) || isEofInstruction(op) {
 		return false
 	}
 	return !_isUndefinedOpCodeRegex.MatchString(op.String())
 }
 
+// isEofInstruction reports whether the given instruction is only valid in EOF
+// code, which is not supported by the sfvm.
+func isEofInstruction(op vm.OpCode) bool {
+	return vm.IsValidInEof(op) && !vm.IsValid(op)
+}
+
 func isJump(op vm.OpCode) bool {
 	return op == vm.JUMP || op == vm.JUMPI
 }
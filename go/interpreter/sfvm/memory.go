@@ -11,19 +11,20 @@
 package sfvm
 
 import (
+	"math/bits"
+	"sync"
+
 	"github.com/0xsoniclabs/tosca/go/tosca"
 	"github.com/holiman/uint256"
 )
 
 type Memory struct {
 	store             []byte
+	buffer            *[]byte   // < the pooled buffer backing store, nil if store is not pooled
+	retired           []*[]byte // < buffers replaced by growing the memory, returned with the memory
 	currentMemoryCost tosca.Gas
 }
 
-func NewMemory() *Memory {
-	return &Memory{}
-}
-
 const (
 	// Maximum memory size allowed
 	// This magic number comes from 'core/vm/gas_table.go' 'memoryGasCost' in geth
@@ -84,14 +85,34 @@ func (m *Memory) expandMemory(offset, size uint64, c *context) error {
 			return err
 		}
 
-		currentSize := m.length()
 		m.currentMemoryCost += fee
-		m.store = append(m.store, make([]byte, expandedSize-currentSize)...)
+		m.grow(expandedSize)
 	}
 
 	return nil
 }
 
+// grow extends the memory to the given size, filling the new range with zeros.
+// If the capacity of the current buffer is exceeded, a buffer of sufficient
+// size is obtained from the memory pool. The old buffer is only returned to
+// the pool by ReturnMemory, since slices obtained from the memory, like the
+// input of a nested call, may still refer to it.
+func (m *Memory) grow(size uint64) {
+	currentSize := m.length()
+	if uint64(cap(m.store)) < size {
+		buffer := getMemoryBuffer(size)
+		store := (*buffer)[:size]
+		copy(store, m.store)
+		if m.buffer != nil {
+			m.retired = append(m.retired, m.buffer)
+		}
+		m.store, m.buffer = store, buffer
+	} else {
+		m.store = m.store[:size]
+	}
+	clear(m.store[currentSize:])
+}
+
 func (m *Memory) length() uint64 {
 	return uint64(len(m.store))
 }
@@ -142,3 +163,92 @@ func (m *Memory) set(offset *uint256.Int, value []byte, c *context) error {
 	copy(data, value)
 	return nil
 }
+
+// ------------------ Memory Pool ------------------
+
+// Memory buffers are pooled in size classes of powers of two, ranging from
+// minPooledMemorySize to maxPooledMemorySize. Larger buffers are rare and
+// are left to the garbage collector.
+const (
+	minPooledMemorySize  = 1 << 10 // 1 KiB
+	maxPooledMemorySize  = 1 << 22 // 4 MiB
+	numMemorySizeClasses = 13
+)
+
+// static assert that the size classes cover the range of pooled sizes
+var _ = [1]struct{}{}[maxPooledMemorySize-minPooledMemorySize<<(numMemorySizeClasses-1)]
+
+var memoryBufferPools [numMemorySizeClasses]sync.Pool
+
+var memoryPool = sync.Pool{
+	New: func() any {
+		return &Memory{}
+	},
+}
+
+// getMemorySizeClass returns the smallest size class with buffers of at least
+// the given size. Sizes exceeding maxPooledMemorySize result in
+// numMemorySizeClasses.
+func getMemorySizeClass(size uint64) int {
+	if size <= minPooledMemorySize {
+		return 0
+	}
+	if size > maxPooledMemorySize {
+		return numMemorySizeClasses
+	}
+	return bits.Len64((size - 1) / minPooledMemorySize)
+}
+
+// getMemoryBuffer obtains a buffer with a capacity of at least the given size.
+// The content of the buffer is undefined.
+func getMemoryBuffer(size uint64) *[]byte {
+	class := getMemorySizeClass(size)
+	if class >= numMemorySizeClasses {
+		buffer := make([]byte, 0, size)
+		return &buffer
+	}
+	if buffer, ok := memoryBufferPools[class].Get().(*[]byte); ok {
+		return buffer
+	}
+	buffer := make([]byte, 0, minPooledMemorySize<<class)
+	return &buffer
+}
+
+// putMemoryBuffer returns a buffer obtained from getMemoryBuffer to the pool.
+// A nil buffer is ignored.
+func putMemoryBuffer(buffer *[]byte) {
+	if buffer == nil {
+		return
+	}
+	capacity := uint64(cap(*buffer))
+	if capacity < minPooledMemorySize || capacity > maxPooledMemorySize {
+		return
+	}
+	// Buffers are filed into the largest class they can serve.
+	class := getMemorySizeClass(capacity)
+	if minPooledMemorySize<<class > capacity {
+		class--
+	}
+	*buffer = (*buffer)[:0]
+	memoryBufferPools[class].Put(buffer)
+}
+
+// NewMemory returns an empty memory instance from a reuse pool. Memory
+// obtained this way should be returned using ReturnMemory once it is no
+// longer needed. This function is thread-safe.
+func NewMemory() *Memory {
+	return memoryPool.Get().(*Memory)
+}
+
+// ReturnMemory returns the memory and its buffer to the reuse pool. Any slices
+// obtained from the memory are invalidated. Any memory may only be returned
+// once to avoid concurrent re-use. This is not checked internally.
+// This function is thread-safe.
+func ReturnMemory(m *Memory) {
+	for _, buffer := range m.retired {
+		putMemoryBuffer(buffer)
+	}
+	putMemoryBuffer(m.buffer)
+	*m = Memory{}
+	memoryPool.Put(m)
+}
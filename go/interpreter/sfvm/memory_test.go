@@ -15,6 +15,7 @@ import (
 	"crypto/rand"
 	"errors"
 	"math"
+	"sync"
 	"testing"
 
 	"github.com/0xsoniclabs/tosca/go/tosca"
@@ -506,3 +507,123 @@ func generateRandomBytes(size int) []byte {
 	_, _ = rand.Read(data)
 	return data
 }
+
+func TestMemory_getMemorySizeClass_SelectsSmallestSufficientClass(t *testing.T) {
+	tests := map[uint64]int{
+		0:                           0,
+		1:                           0,
+		minPooledMemorySize:         0,
+		minPooledMemorySize + 1:     1,
+		2 * minPooledMemorySize:     1,
+		2*minPooledMemorySize + 1:   2,
+		maxPooledMemorySize:         numMemorySizeClasses - 1,
+		maxPooledMemorySize + 1:     numMemorySizeClasses,
+		maxMemoryExpansionSize + 32: numMemorySizeClasses,
+	}
+	for size, want := range tests {
+		if got := getMemorySizeClass(size); want != got {
+			t.Errorf("unexpected size class for size %d, want %d, got %d", size, want, got)
+		}
+		if want < numMemorySizeClasses && minPooledMemorySize<<want < size {
+			t.Errorf("size class %d is too small for size %d", want, size)
+		}
+	}
+}
+
+func TestMemory_getMemoryBuffer_ProvidesSufficientCapacity(t *testing.T) {
+	for _, size := range []uint64{0, 1, 32, 1000, 1025, 1 << 16, maxPooledMemorySize, maxPooledMemorySize + 32} {
+		buffer := getMemoryBuffer(size)
+		if got := uint64(cap(*buffer)); got < size {
+			t.Errorf("insufficient capacity for size %d, got %d", size, got)
+		}
+		putMemoryBuffer(buffer)
+	}
+}
+
+func TestMemory_ReturnMemory_ResetsMemory(t *testing.T) {
+	m := NewMemory()
+	c := &context{gas: 1_000_000}
+	if err := m.set(uint256.NewInt(0), bytes.Repeat([]byte{0xff}, 2*minPooledMemorySize), c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ReturnMemory(m)
+
+	if m.length() != 0 || m.buffer != nil || m.currentMemoryCost != 0 {
+		t.Errorf("memory was not reset")
+	}
+}
+
+func TestMemory_ExpansionOfReusedMemoryIsZeroed(t *testing.T) {
+	for range 10 {
+		m := NewMemory()
+		c := &context{gas: 1_000_000}
+		size := uint64(2 * minPooledMemorySize)
+		if err := m.expandMemory(0, size, c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(make([]byte, size), m.store) {
+			t.Fatalf("expanded memory is not zeroed")
+		}
+		// pollute the memory before it is returned and potentially reused
+		for i := range m.store {
+			m.store[i] = 0xff
+		}
+		ReturnMemory(m)
+	}
+}
+
+func TestMemory_BuffersReplacedByGrowingAreOnlyRecycledOnReturn(t *testing.T) {
+	m := NewMemory()
+	c := &context{gas: 1_000_000}
+	data := bytes.Repeat([]byte{0xff}, minPooledMemorySize)
+	if err := m.set(uint256.NewInt(0), data, c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	slice := m.store[:minPooledMemorySize]
+	old := m.buffer
+
+	if err := m.expandMemory(0, 4*minPooledMemorySize, c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want, got := []*[]byte{old}, m.retired; len(got) != 1 || want[0] != got[0] {
+		t.Fatalf("replaced buffer is not retained")
+	}
+
+	// Other memory instances must not obtain the replaced buffer.
+	other := NewMemory()
+	if err := other.expandMemory(0, minPooledMemorySize, c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(data, slice) {
+		t.Errorf("slice of replaced buffer was modified")
+	}
+	ReturnMemory(other)
+
+	ReturnMemory(m)
+	if m.retired != nil {
+		t.Errorf("retired buffers were not released")
+	}
+}
+
+func TestMemory_NewMemoryAndReturnMemory_AreThreadSafe(t *testing.T) {
+	// this test assumes to be executed using the --race flag.
+	const parallelism = 10
+	const iterations = 1000
+
+	var wg sync.WaitGroup
+	wg.Add(parallelism)
+	for range parallelism {
+		go func() {
+			defer wg.Done()
+			c := &context{gas: math.MaxInt64}
+			for i := range iterations {
+				m := NewMemory()
+				if err := m.expandMemory(0, uint64(i*32), c); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				ReturnMemory(m)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -11,6 +11,8 @@
 package sfvm
 
 import (
+	"fmt"
+
 	"github.com/0xsoniclabs/tosca/go/tosca"
 )
 
@@ -20,10 +22,19 @@ type Config struct {
 	WithAnalysisCache bool // Whether to enable caching of jump destination analyses
 	AnalysisCacheSize int  // Maximum size of the analysis cache in bytes (default: 256 MB)
 	MaxCachedCodeSize int  // Maximum code size in bytes for which analyses are cached (default: 24 KB)
+
+	// GasSchedule overrides the Ethereum gas prices charged by the
+	// interpreter. If nil, the Ethereum gas prices are charged.
+	GasSchedule *tosca.GasSchedule
 }
 
 // NewInterpreter creates a new SFVM interpreter instance with the given configuration.
 func NewInterpreter(config Config) (*sfvm, error) {
+	prices, err := newGasPrices(config.GasSchedule)
+	if err != nil {
+		return nil, err
+	}
+
 	var analysis analysis
 	if config.WithAnalysisCache {
 
@@ -43,23 +54,34 @@ func NewInterpreter(config Config) (*sfvm, error) {
 	sfvm := &sfvm{
 		config:   config,
 		analysis: analysis,
+		prices:   prices,
 	}
 	return sfvm, nil
 }
 
-// Registers the simple form EVM as a possible interpreter implementation.
+// Registers the simple form EVM as a possible interpreter implementation. A
+// Config may be provided as configuration, otherwise the defaults are used.
+// Configurations of any other type are rejected.
 func init() {
-	tosca.MustRegisterInterpreterFactory("sfvm", func(any) (tosca.Interpreter, error) {
-		return NewInterpreter(Config{
-			WithShaCache:      true,
-			WithAnalysisCache: true,
-		})
+	tosca.MustRegisterInterpreterFactory("sfvm", func(c any) (tosca.Interpreter, error) {
+		switch config := c.(type) {
+		case nil:
+			return NewInterpreter(Config{
+				WithShaCache:      true,
+				WithAnalysisCache: true,
+			})
+		case Config:
+			return NewInterpreter(config)
+		default:
+			return nil, fmt.Errorf("unsupported configuration of type %T, expected sfvm.Config", c)
+		}
 	})
 }
 
 type sfvm struct {
 	config   Config
 	analysis analysis
+	prices   *gasPrices
 }
 
 // Defines the newest supported revision for this interpreter implementation
@@ -70,5 +92,5 @@ func (s *sfvm) Run(params tosca.Parameters) (tosca.Result, error) {
 		return tosca.Result{}, &tosca.ErrUnsupportedRevision{Revision: params.Revision}
 	}
 
-	return run(s.analysis, s.config, params)
+	return run(s.analysis, s.prices, s.config, params)
 }
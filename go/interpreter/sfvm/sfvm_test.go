@@ -11,11 +11,16 @@
 package sfvm
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"slices"
 	"testing"
 
 	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 func TestSfvm_OfficialConfigurationHasSanctionedProperties(t *testing.T) {
@@ -87,6 +92,13 @@ func TestNewInterpreter_AnalysisCacheArgumentsAreForwarded(t *testing.T) {
 	}
 }
 
+func TestSfvm_FactoryRejectsConfigOfUnexpectedType(t *testing.T) {
+	for _, config := range []any{&Config{}, 42, "sfvm"} {
+		_, err := tosca.NewInterpreter("sfvm", config)
+		require.Error(t, err, "configuration of type %T was accepted", config)
+	}
+}
+
 func TestSfvm_InterpreterReturnsErrorWhenExecutingUnsupportedRevision(t *testing.T) {
 	vm, err := tosca.NewInterpreter("sfvm")
 	if err != nil {
@@ -101,3 +113,185 @@ func TestSfvm_InterpreterReturnsErrorWhenExecutingUnsupportedRevision(t *testing
 		t.Fatalf("unexpected error: want %q, got %q", want, got)
 	}
 }
+
+func TestSfvm_CanceledExecutionIsAbortedAtJumpDestination(t *testing.T) {
+	instance, err := NewInterpreter(Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	done := make(chan struct{})
+	close(done)
+	params := tosca.Parameters{
+		BlockParameters:       tosca.BlockParameters{Revision: tosca.R13_Cancun},
+		TransactionParameters: tosca.TransactionParameters{Done: done},
+		Gas:                   math.MaxInt64,
+		Code:                  []byte{byte(vm.JUMPDEST), byte(vm.PUSH1), 0, byte(vm.JUMP)},
+	}
+	_, err = instance.Run(params)
+	if !errors.Is(err, tosca.ErrCanceled) {
+		t.Errorf("unexpected error, want %v, got %v", tosca.ErrCanceled, err)
+	}
+}
+
+func TestSfvm_ExecutionCanceledDuringNestedCallIsAborted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	runContext := tosca.NewMockRunContext(ctrl)
+
+	instance, err := NewInterpreter(Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	done := make(chan struct{})
+	runContext.EXPECT().Call(tosca.Call, gomock.Any()).DoAndReturn(
+		func(tosca.CallKind, tosca.CallParameters) (tosca.CallResult, error) {
+			close(done)
+			return tosca.CallResult{Success: true}, nil
+		})
+
+	code := []byte{}
+	for range 6 {
+		code = append(code, byte(vm.PUSH1), 0)
+	}
+	code = append(code, byte(vm.GAS), byte(vm.CALL), byte(vm.STOP))
+	params := tosca.Parameters{
+		BlockParameters:       tosca.BlockParameters{Revision: tosca.R07_Istanbul},
+		TransactionParameters: tosca.TransactionParameters{Done: done},
+		Context:               runContext,
+		Gas:                   100_000,
+		Code:                  code,
+	}
+	_, err = instance.Run(params)
+	if !errors.Is(err, tosca.ErrCanceled) {
+		t.Errorf("unexpected error, want %v, got %v", tosca.ErrCanceled, err)
+	}
+}
+
+func TestSfvm_ChargesPricesOfGasSchedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	runContext := tosca.NewMockRunContext(ctrl)
+	runContext.EXPECT().AccessStorage(gomock.Any(), gomock.Any()).Return(tosca.ColdAccess)
+	runContext.EXPECT().GetStorage(gomock.Any(), gomock.Any()).Return(tosca.Word{})
+
+	instance, err := NewInterpreter(Config{
+		GasSchedule: &tosca.GasSchedule{
+			Static: []tosca.StaticGasOverride{
+				{OpCode: vm.ADD, Revision: tosca.R07_Istanbul, Price: 10},
+			},
+			Dynamic: []tosca.DynamicGasOverride{
+				{Kind: tosca.ColdSloadGas, Revision: tosca.R09_Berlin, Price: 500},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	params := tosca.Parameters{
+		BlockParameters: tosca.BlockParameters{Revision: tosca.R13_Cancun},
+		Context:         runContext,
+		Gas:             1000,
+		Code: []byte{
+			byte(vm.PUSH1), 1,
+			byte(vm.PUSH1), 2,
+			byte(vm.ADD),
+			byte(vm.SLOAD),
+			byte(vm.STOP),
+		},
+	}
+	result, err := instance.Run(params)
+	require.NoError(t, err)
+	require.True(t, result.Success)
+	require.Equal(t, tosca.Gas(1000-3-3-10-500), result.GasLeft)
+}
+
+func TestSfvm_OutputIsNotAffectedBySubsequentRuns(t *testing.T) {
+	// The code returns 32 bytes of memory filled with the value of its first
+	// argument. Memory of finished runs is reused, so the output of the first
+	// run must not be affected by the second.
+	code := func(value byte) []byte {
+		return []byte{
+			byte(vm.PUSH1), value,
+			byte(vm.PUSH1), 0,
+			byte(vm.MSTORE),
+			byte(vm.PUSH1), 32,
+			byte(vm.PUSH1), 0,
+			byte(vm.RETURN),
+		}
+	}
+	instance, err := NewInterpreter(Config{})
+	require.NoError(t, err)
+	run := func(value byte) []byte {
+		result, err := instance.Run(tosca.Parameters{
+			BlockParameters: tosca.BlockParameters{Revision: tosca.R13_Cancun},
+			Gas:             1000,
+			Code:            code(value),
+		})
+		require.NoError(t, err)
+		require.True(t, result.Success)
+		return result.Output
+	}
+
+	first := run(1)
+	for range 10 {
+		run(2)
+	}
+	require.Equal(t, byte(1), first[31])
+}
+
+func TestSfvm_CallInputIsNotAffectedBySubsequentRuns(t *testing.T) {
+	// The code stores its first argument in memory and passes the first 32
+	// bytes of memory as the input of a call and as the init code of a
+	// create. Memory of finished runs is reused, so inputs retained by the
+	// run context must not be affected by later runs.
+	code := func(value byte) []byte {
+		return []byte{
+			byte(vm.PUSH1), value,
+			byte(vm.PUSH1), 0,
+			byte(vm.MSTORE),
+			byte(vm.PUSH1), 0, // < output size
+			byte(vm.PUSH1), 0, // < output offset
+			byte(vm.PUSH1), 32, // < input size
+			byte(vm.PUSH1), 0, // < input offset
+			byte(vm.PUSH1), 0, // < value
+			byte(vm.PUSH1), 1, // < address
+			byte(vm.PUSH2), 0xff, 0xff, // < gas
+			byte(vm.CALL),
+			byte(vm.POP),
+			byte(vm.PUSH1), 32, // < init code size
+			byte(vm.PUSH1), 0, // < init code offset
+			byte(vm.PUSH1), 0, // < value
+			byte(vm.CREATE),
+			byte(vm.POP),
+			byte(vm.STOP),
+		}
+	}
+
+	var inputs [][]byte
+	runContext := tosca.NewMockRunContext(gomock.NewController(t))
+	runContext.EXPECT().AccessAccount(gomock.Any()).Return(tosca.WarmAccess).AnyTimes()
+	runContext.EXPECT().Call(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ tosca.CallKind, params tosca.CallParameters) (tosca.CallResult, error) {
+			inputs = append(inputs, params.Input)
+			return tosca.CallResult{Success: true}, nil
+		}).AnyTimes()
+
+	instance, err := NewInterpreter(Config{})
+	require.NoError(t, err)
+	run := func(value byte) {
+		result, err := instance.Run(tosca.Parameters{
+			BlockParameters: tosca.BlockParameters{Revision: tosca.R13_Cancun},
+			Context:         runContext,
+			Gas:             1_000_000,
+			Code:            code(value),
+		})
+		require.NoError(t, err)
+		require.True(t, result.Success)
+	}
+
+	run(1)
+	retained := slices.Clone(inputs)
+	for range 10 {
+		run(2)
+	}
+	for i, input := range retained {
+		require.Equal(t, byte(1), input[31], "input %d of first run was modified", i)
+	}
+}
@@ -14,18 +14,69 @@ import (
 	"fmt"
 	"math"
 
+	"github.com/0xsoniclabs/tosca/go/processor/precompile"
 	"github.com/0xsoniclabs/tosca/go/tosca"
 	"github.com/ethereum/go-ethereum/common"
 	geth "github.com/ethereum/go-ethereum/core/vm"
 )
 
-func isPrecompiled(address tosca.Address, revision tosca.Revision) bool {
-	_, ok := getPrecompiledContract(address, revision)
+// defaultPrecompiles contains the precompiled contracts used unless the
+// configuration of the processor defines otherwise. These are the contracts
+// of geth for Istanbul, Berlin, and Cancun, where revisions after Cancun
+// fall back to the contracts of Istanbul. The contracts defined by Ethereum
+// for Prague and Osaka are provided by precompile.NewStandardRegistry and
+// may be enabled through Config.Precompiles.
+var defaultPrecompiles = newGethPrecompiles()
+
+// getPrecompiles returns the registry of precompiled contracts to be used
+// with the given configuration.
+func getPrecompiles(config Config) *precompile.Registry {
+	if config.Precompiles != nil {
+		return config.Precompiles
+	}
+	return defaultPrecompiles
+}
+
+// newGethPrecompiles creates a registry of the precompiled contracts of geth
+// for the revisions supported by Sonic.
+func newGethPrecompiles() *precompile.Registry {
+	registry := precompile.NewRegistry()
+	register := func(revisions precompile.RevisionRange, contracts map[common.Address]geth.PrecompiledContract) {
+		for address, contract := range contracts {
+			if err := registry.Register(tosca.Address(address), revisions, gethContract{contract}); err != nil {
+				panic(fmt.Sprintf("invalid geth precompiled contract: %v", err))
+			}
+		}
+	}
+	// Istanbul is the oldest revision supported by Sonic.
+	register(precompile.Between(tosca.R07_Istanbul, tosca.R07_Istanbul), geth.PrecompiledContractsIstanbul)
+	register(precompile.Between(tosca.R09_Berlin, tosca.R12_Shanghai), geth.PrecompiledContractsBerlin)
+	register(precompile.Between(tosca.R13_Cancun, tosca.R13_Cancun), geth.PrecompiledContractsCancun)
+	register(precompile.Since(tosca.R14_Prague), geth.PrecompiledContractsIstanbul)
+	return registry
+}
+
+// gethContract adapts a precompiled contract of geth to the precompile.Contract
+// interface.
+type gethContract struct {
+	contract geth.PrecompiledContract
+}
+
+func (c gethContract) RequiredGas(input tosca.Data) uint64 {
+	return c.contract.RequiredGas(input)
+}
+
+func (c gethContract) Run(input tosca.Data) (tosca.Data, error) {
+	return c.contract.Run(input)
+}
+
+func isPrecompiled(precompiles *precompile.Registry, address tosca.Address, revision tosca.Revision) bool {
+	_, ok := precompiles.Get(address, revision)
 	return ok
 }
 
-func runPrecompiledContract(revision tosca.Revision, input tosca.Data, address tosca.Address, gas tosca.Gas) (tosca.CallResult, error) {
-	contract, ok := getPrecompiledContract(address, revision)
+func runPrecompiledContract(precompiles *precompile.Registry, revision tosca.Revision, input tosca.Data, address tosca.Address, gas tosca.Gas) (tosca.CallResult, error) {
+	contract, ok := precompiles.Get(address, revision)
 	if !ok {
 		return tosca.CallResult{}, fmt.Errorf("precompiled contract not found")
 	}
@@ -49,30 +100,15 @@ func runPrecompiledContract(revision tosca.Revision, input tosca.Data, address t
 	}, nil
 }
 
-func getPrecompiledContract(address tosca.Address, revision tosca.Revision) (geth.PrecompiledContract, bool) {
-	precompiles := getPrecompiledContracts(revision)
-	contract, ok := precompiles[common.Address(address)]
-	return contract, ok
+// PrecompiledAddresses returns the addresses of all precompiled contracts
+// available in the given revision using the configuration of the processor,
+// including chain-specific contracts.
+func (p *Processor) PrecompiledAddresses(revision tosca.Revision) []tosca.Address {
+	return getPrecompiles(p.Config).Addresses(revision)
 }
 
-func getPrecompiledContracts(revision tosca.Revision) map[common.Address]geth.PrecompiledContract {
-	var precompiles map[common.Address]geth.PrecompiledContract
-	switch revision {
-	case tosca.R13_Cancun:
-		precompiles = geth.PrecompiledContractsCancun
-	case tosca.R12_Shanghai, tosca.R11_Paris, tosca.R10_London, tosca.R09_Berlin:
-		precompiles = geth.PrecompiledContractsBerlin
-	default: // Istanbul is the oldest supported revision supported by Sonic
-		precompiles = geth.PrecompiledContractsIstanbul
-	}
-	return precompiles
-}
-
-func getPrecompiledAddresses(revision tosca.Revision) []tosca.Address {
-	precompiles := getPrecompiledContracts(revision)
-	addresses := make([]tosca.Address, 0, len(precompiles))
-	for addr := range precompiles {
-		addresses = append(addresses, tosca.Address(addr))
-	}
-	return addresses
+// PrecompiledAddresses returns the addresses of all precompiled contracts
+// available in the given revision using the default configuration.
+func PrecompiledAddresses(revision tosca.Revision) []tosca.Address {
+	return defaultPrecompiles.Addresses(revision)
 }
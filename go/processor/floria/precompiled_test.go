@@ -11,11 +11,15 @@
 package floria
 
 import (
+	"slices"
 	"strings"
 	"testing"
 
 	test_utils "github.com/0xsoniclabs/tosca/go/processor"
+	"github.com/0xsoniclabs/tosca/go/processor/precompile"
 	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/ethereum/go-ethereum/common"
+	geth "github.com/ethereum/go-ethereum/core/vm"
 	"github.com/stretchr/testify/require"
 )
 
@@ -30,13 +34,16 @@ func TestPrecompiled_RightNumberOfContractsDependingOnRevision(t *testing.T) {
 		{tosca.R11_Paris, 9},
 		{tosca.R12_Shanghai, 9},
 		{tosca.R13_Cancun, 10},
+		{tosca.R14_Prague, 9},
+		{tosca.R15_Osaka, 9},
+		{tosca.R98_Experimental, 9},
 	}
 
 	for _, test := range tests {
 		count := 0
-		for i := byte(0x01); i < byte(0x42); i++ {
-			address := test_utils.NewAddress(i)
-			isPrecompiled := isPrecompiled(address, test.revision)
+		for i := range 0x200 {
+			address := tosca.Address{18: byte(i >> 8), 19: byte(i)}
+			isPrecompiled := isPrecompiled(defaultPrecompiles, address, test.revision)
 			if isPrecompiled {
 				count++
 			}
@@ -44,12 +51,73 @@ func TestPrecompiled_RightNumberOfContractsDependingOnRevision(t *testing.T) {
 		if count != test.numberOfContracts {
 			t.Errorf("unexpected number of precompiled contracts for revision %v, want %v, got %v", test.revision, test.numberOfContracts, count)
 		}
-		if len(getPrecompiledAddresses(test.revision)) != test.numberOfContracts {
+		if len(PrecompiledAddresses(test.revision)) != test.numberOfContracts {
 			t.Errorf("unexpected number of precompiled contracts for revision %v, want %v, got %v", test.revision, test.numberOfContracts, count)
 		}
 	}
 }
 
+func TestPrecompiled_AddressesDependOnRevision(t *testing.T) {
+	addresses := func(suffixes ...uint16) []tosca.Address {
+		res := []tosca.Address{}
+		for _, suffix := range suffixes {
+			res = append(res, tosca.Address{18: byte(suffix >> 8), 19: byte(suffix)})
+		}
+		return res
+	}
+	istanbul := addresses(0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09)
+	cancun := append(slices.Clone(istanbul), addresses(0x0a)...)
+	prague := append(slices.Clone(cancun), addresses(0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10, 0x11)...)
+	osaka := append(slices.Clone(prague), addresses(0x0100)...)
+
+	tests := map[tosca.Revision]struct {
+		defaults []tosca.Address
+		standard []tosca.Address
+	}{
+		tosca.R07_Istanbul:     {istanbul, istanbul},
+		tosca.R09_Berlin:       {istanbul, istanbul},
+		tosca.R10_London:       {istanbul, istanbul},
+		tosca.R11_Paris:        {istanbul, istanbul},
+		tosca.R12_Shanghai:     {istanbul, istanbul},
+		tosca.R13_Cancun:       {cancun, cancun},
+		tosca.R14_Prague:       {istanbul, prague},
+		tosca.R15_Osaka:        {istanbul, osaka},
+		tosca.R98_Experimental: {istanbul, osaka},
+	}
+
+	standard := &Processor{Config: Config{Precompiles: precompile.NewStandardRegistry()}}
+	for revision, want := range tests {
+		t.Run(revision.String(), func(t *testing.T) {
+			require.Equal(t, want.defaults, PrecompiledAddresses(revision))
+			require.Equal(t, want.defaults, (&Processor{}).PrecompiledAddresses(revision))
+			require.Equal(t, want.standard, standard.PrecompiledAddresses(revision))
+		})
+	}
+}
+
+func TestPrecompiled_DefaultsMatchGethContracts(t *testing.T) {
+	tests := map[tosca.Revision]map[common.Address]geth.PrecompiledContract{
+		tosca.R07_Istanbul:     geth.PrecompiledContractsIstanbul,
+		tosca.R09_Berlin:       geth.PrecompiledContractsBerlin,
+		tosca.R12_Shanghai:     geth.PrecompiledContractsBerlin,
+		tosca.R13_Cancun:       geth.PrecompiledContractsCancun,
+		tosca.R14_Prague:       geth.PrecompiledContractsIstanbul,
+		tosca.R15_Osaka:        geth.PrecompiledContractsIstanbul,
+		tosca.R98_Experimental: geth.PrecompiledContractsIstanbul,
+	}
+
+	for revision, contracts := range tests {
+		t.Run(revision.String(), func(t *testing.T) {
+			require.Len(t, PrecompiledAddresses(revision), len(contracts))
+			for address, want := range contracts {
+				got, found := defaultPrecompiles.Get(tosca.Address(address), revision)
+				require.True(t, found, "missing contract at %v", address)
+				require.Equal(t, gethContract{want}, got)
+			}
+		})
+	}
+}
+
 func TestPrecompiled_AddressesAreHandledCorrectly(t *testing.T) {
 	tests := map[string]struct {
 		revision      tosca.Revision
@@ -74,12 +142,12 @@ func TestPrecompiled_AddressesAreHandledCorrectly(t *testing.T) {
 				input = test_utils.ValidPointEvaluationInput
 			}
 
-			isPrecompiled := isPrecompiled(test.address, test.revision)
+			isPrecompiled := isPrecompiled(defaultPrecompiles, test.address, test.revision)
 			if isPrecompiled != test.isPrecompiled {
 				t.Fatalf("unexpected precompiled, want %v, got %v", test.isPrecompiled, isPrecompiled)
 			}
 
-			result, err := runPrecompiledContract(test.revision, input, test.address, test.gas)
+			result, err := runPrecompiledContract(defaultPrecompiles, test.revision, input, test.address, test.gas)
 			if test.success {
 				require.NoError(t, err)
 				require.True(t, result.Success)
@@ -116,7 +184,7 @@ func TestPrecompiled_ErrorsAreHandledCorrectly(t *testing.T) {
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
 			input := tosca.Data{}
-			result, err := runPrecompiledContract(tosca.R13_Cancun, input, test.address, test.gas)
+			result, err := runPrecompiledContract(defaultPrecompiles, tosca.R13_Cancun, input, test.address, test.gas)
 			require.ErrorContains(t, err, test.expectedError)
 			require.False(t, result.Success, "expected the result to be unsuccessful due to error")
 			require.Equal(t, tosca.Gas(0), result.GasLeft, "expected gas left to be zero on error")
@@ -137,7 +205,49 @@ func TestPrecompiled_GasCostOverflowIsDetectedAndHandled(t *testing.T) {
 	}
 
 	modExpAddress := test_utils.NewAddress(0x05)
-	result, err := runPrecompiledContract(tosca.R13_Cancun, tosca.Data(data), modExpAddress, 100)
+	result, err := runPrecompiledContract(defaultPrecompiles, tosca.R13_Cancun, tosca.Data(data), modExpAddress, 100)
 	require.ErrorContains(t, err, "gas cost exceeds maximum limit")
 	require.False(t, result.Success, "expected the result to be unsuccessful due to gas cost overflow")
 }
+
+func TestPrecompiled_ConfiguredPrecompilesAreUsed(t *testing.T) {
+	custom := precompile.NewRegistry()
+	require.Equal(t, defaultPrecompiles, getPrecompiles(Config{}))
+	require.Equal(t, custom, getPrecompiles(Config{Precompiles: custom}))
+}
+
+func TestPrecompiled_ChainSpecificContractsCanBeAdded(t *testing.T) {
+	address := tosca.Address{0x42}
+	precompiles := precompile.NewStandardRegistry()
+	require.NoError(t, precompiles.Register(address, precompile.Since(tosca.R13_Cancun), constantContract{
+		gas:    100,
+		output: tosca.Data{1, 2, 3},
+	}))
+
+	require.False(t, isPrecompiled(precompiles, address, tosca.R12_Shanghai))
+	require.True(t, isPrecompiled(precompiles, address, tosca.R13_Cancun))
+	require.True(t, isPrecompiled(precompiles, test_utils.NewAddress(0x01), tosca.R13_Cancun))
+
+	result, err := runPrecompiledContract(precompiles, tosca.R13_Cancun, tosca.Data{}, address, 1000)
+	require.NoError(t, err)
+	require.Equal(t, tosca.CallResult{
+		Success: true,
+		Output:  tosca.Data{1, 2, 3},
+		GasLeft: 900,
+	}, result)
+}
+
+// constantContract is a precompiled contract producing a constant output for
+// a constant price.
+type constantContract struct {
+	gas    uint64
+	output tosca.Data
+}
+
+func (c constantContract) RequiredGas(tosca.Data) uint64 {
+	return c.gas
+}
+
+func (c constantContract) Run(tosca.Data) (tosca.Data, error) {
+	return c.output, nil
+}
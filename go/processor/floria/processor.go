@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/0xsoniclabs/tosca/go/processor/precompile"
 	"github.com/0xsoniclabs/tosca/go/tosca"
 	"github.com/holiman/uint256"
 )
@@ -67,6 +68,12 @@ type Processor struct {
 	Config      Config
 }
 
+// GasSchedule returns the gas schedule configured for the processor, which is
+// nil if the Ethereum prices are charged.
+func (p *Processor) GasSchedule() *tosca.GasSchedule {
+	return p.Config.GasSchedule
+}
+
 // BuiltInContract defines the Run function for special build in contracts that are not deployed as smart contracts.
 type BuiltInContract interface {
 	Run(state tosca.WorldState, sender tosca.Address, receiver tosca.Address, input []byte, gas tosca.Gas) tosca.CallResult
@@ -81,13 +88,30 @@ type Config struct {
 	// BuiltInContracts binds addresses to special contracts not deployed as smart contracts.
 	BuiltInContracts map[tosca.Address]BuiltInContract
 
+	// Precompiles defines the precompiled contracts available in each revision.
+	// If nil, the contracts of geth up to Cancun are used, where revisions after
+	// Cancun fall back to the contracts of Istanbul. To use the contracts
+	// defined by Ethereum up to Osaka, set this to a registry created by
+	// precompile.NewStandardRegistry, which may be extended by chain-specific
+	// contracts.
+	Precompiles *precompile.Registry
+
+	// GasSchedule overrides the Ethereum prices of the access list of
+	// transactions. If nil, the Ethereum prices are charged. Interpreters are
+	// configured independently and should be given the same schedule.
+	GasSchedule *tosca.GasSchedule
+
 	// OffChainSimulation flag indicates that the transaction does not have any effect
 	// on the state of the blockchain. It is used for simulation purposes.
 	// When enabled, EOA and nonce checks are skipped, and 0 is treated as a valid value for
 	// gasprice and blob gasprice.
 	OffChainSimulation bool
 
-	// TODO: add tracing/debug flag
+	// RunContextDecorator, if set, wraps the run context used for the top-level
+	// call of a transaction and handed to interpreters for nested calls. Thus,
+	// the decorated context observes all calls performed by a transaction,
+	// which may be used for tracing and debugging.
+	RunContextDecorator func(tosca.RunContext) tosca.RunContext
 }
 
 // Run checks whether the transaction can be executed and applies it if possible.
@@ -175,7 +199,7 @@ func calculateAvailableGas(
 		return tosca.Value{}, 0, fmt.Errorf("failed balance check: %w", err)
 	}
 
-	setupGas := calculateSetupGas(transaction, blockParameters.Revision)
+	setupGas := calculateSetupGas(transaction, blockParameters.Revision, config.GasSchedule)
 	if transaction.GasLimit < setupGas {
 		return tosca.Value{}, transaction.GasLimit, fmt.Errorf("insufficient gas for set up")
 	}
@@ -200,6 +224,7 @@ func (p *Processor) runTransaction(
 		Origin:     transaction.Sender,
 		GasPrice:   gasPrice,
 		BlobHashes: transaction.BlobHashes,
+		Done:       transaction.Done,
 	}
 
 	runContext := runContext{
@@ -213,7 +238,7 @@ func (p *Processor) runTransaction(
 	}
 
 	if blockParameters.Revision >= tosca.R09_Berlin {
-		setUpAccessList(transaction, &runContext, blockParameters.Revision, blockParameters.Coinbase)
+		setUpAccessList(transaction, &runContext, blockParameters.Revision, blockParameters.Coinbase, getPrecompiles(p.Config))
 	}
 
 	callParameters := callParameters(transaction, gas)
@@ -223,7 +248,21 @@ func (p *Processor) runTransaction(
 		context.SetNonce(transaction.Sender, context.GetNonce(transaction.Sender)+1)
 	}
 
-	return runContext.Call(kind, callParameters)
+	var result tosca.CallResult
+	var err error
+	if p.Config.RunContextDecorator != nil {
+		runContext.decorated = p.Config.RunContextDecorator(&runContext)
+		result, err = runContext.decorated.Call(kind, callParameters)
+	} else {
+		result, err = runContext.Call(kind, callParameters)
+	}
+
+	// Interpreters may not report cancellations of nested calls, thus the
+	// cancellation is checked once more after the execution.
+	if err == nil && transactionParameters.IsCanceled() {
+		return tosca.CallResult{}, tosca.ErrCanceled
+	}
+	return result, err
 }
 
 // returnExcessGas returns the excess gas back to the sender.
@@ -338,7 +377,7 @@ func buyGasInternal(transaction tosca.Transaction, gasPrice tosca.Value, blobGas
 }
 
 // setUpAccessList sets up the access list for the transaction by adding accounts and storage keys.
-func setUpAccessList(transaction tosca.Transaction, context tosca.TransactionContext, revision tosca.Revision, coinBase tosca.Address) {
+func setUpAccessList(transaction tosca.Transaction, context tosca.TransactionContext, revision tosca.Revision, coinBase tosca.Address, precompiles *precompile.Registry) {
 	if transaction.AccessList == nil {
 		return
 	}
@@ -348,8 +387,7 @@ func setUpAccessList(transaction tosca.Transaction, context tosca.TransactionCon
 		context.AccessAccount(*transaction.Recipient)
 	}
 
-	precompiles := getPrecompiledAddresses(revision)
-	for _, address := range precompiles {
+	for _, address := range precompiles.Addresses(revision) {
 		context.AccessAccount(address)
 	}
 
@@ -429,8 +467,9 @@ func refundGasInternal(context tosca.TransactionContext, sender tosca.Address, g
 }
 
 // calculateSetupGas calculates the gas required for setting up the transaction.
-// This includes costs for call or create, the input data and the access list.
-func calculateSetupGas(transaction tosca.Transaction, revision tosca.Revision) tosca.Gas {
+// This includes costs for call or create, the input data and the access list,
+// which is priced by the given gas schedule.
+func calculateSetupGas(transaction tosca.Transaction, revision tosca.Revision, schedule *tosca.GasSchedule) tosca.Gas {
 	var gas tosca.Gas
 	if transaction.Recipient == nil {
 		gas = TxGasContractCreation
@@ -460,11 +499,13 @@ func calculateSetupGas(transaction tosca.Transaction, revision tosca.Revision) t
 	}
 
 	if transaction.AccessList != nil {
-		gas += tosca.Gas(len(transaction.AccessList)) * TxAccessListAddressGas
+		addressGas := schedule.GetDynamicGas(tosca.AccessListAddressGas, revision)
+		gas += tosca.Gas(len(transaction.AccessList)) * addressGas
 
 		// charge for each storage key
+		storageKeyGas := schedule.GetDynamicGas(tosca.AccessListStorageKeyGas, revision)
 		for _, accessTuple := range transaction.AccessList {
-			gas += tosca.Gas(len(accessTuple.Keys)) * TxAccessListStorageKeyGas
+			gas += tosca.Gas(len(accessTuple.Keys)) * storageKeyGas
 		}
 	}
 
@@ -742,7 +742,7 @@ func TestProcessor_SetupGasBilling(t *testing.T) {
 				AccessList: test.accessList,
 			}
 
-			actualGasUsed := calculateSetupGas(transaction, tosca.R12_Shanghai)
+			actualGasUsed := calculateSetupGas(transaction, tosca.R12_Shanghai, nil)
 			if actualGasUsed != test.expectedGasUsed {
 				t.Errorf("setupGasBilling returned incorrect gas used, got: %d, want: %d", actualGasUsed, test.expectedGasUsed)
 			}
@@ -750,6 +750,41 @@ func TestProcessor_SetupGasBilling(t *testing.T) {
 	}
 }
 
+func TestProcessor_SetupGasChargesAccessListPricesOfGasSchedule(t *testing.T) {
+	schedule := &tosca.GasSchedule{
+		Dynamic: []tosca.DynamicGasOverride{
+			{Kind: tosca.AccessListAddressGas, Revision: tosca.R12_Shanghai, Price: 10},
+			{Kind: tosca.AccessListStorageKeyGas, Revision: tosca.R13_Cancun, Price: 1},
+		},
+	}
+	transaction := tosca.Transaction{
+		Recipient: &tosca.Address{1},
+		AccessList: []tosca.AccessTuple{
+			{Address: tosca.Address{1}, Keys: []tosca.Key{{1}, {2}, {3}}},
+			{Address: tosca.Address{2}},
+		},
+	}
+
+	tests := map[tosca.Revision]tosca.Gas{
+		tosca.R11_Paris:    TxGas + 2*TxAccessListAddressGas + 3*TxAccessListStorageKeyGas,
+		tosca.R12_Shanghai: TxGas + 2*10 + 3*TxAccessListStorageKeyGas,
+		tosca.R13_Cancun:   TxGas + 2*10 + 3*1,
+	}
+	for revision, want := range tests {
+		if got := calculateSetupGas(transaction, revision, schedule); want != got {
+			t.Errorf("unexpected setup gas in %v, want %d, got %d", revision, want, got)
+		}
+	}
+}
+
+func TestProcessor_GasScheduleOfConfigIsReported(t *testing.T) {
+	schedule := &tosca.GasSchedule{}
+	processor := &Processor{Config: Config{GasSchedule: schedule}}
+	if want, got := schedule, processor.GasSchedule(); want != got {
+		t.Errorf("unexpected gas schedule, want %v, got %v", want, got)
+	}
+}
+
 func TestProcessor_BalanceCheckReturnsErrors(t *testing.T) {
 	tests := map[string]struct {
 		gasLimit      tosca.Gas
@@ -944,7 +979,7 @@ func TestProcessor_runTransactionSetsUpAccessListAfterBerlin(t *testing.T) {
 
 	addPrecompiles := func(context *tosca.MockTransactionContext, revision tosca.Revision) {
 		for i := range 100 {
-			if address := test_utils.NewAddress(byte(i)); isPrecompiled(address, revision) {
+			if address := test_utils.NewAddress(byte(i)); isPrecompiled(defaultPrecompiles, address, revision) {
 				context.EXPECT().AccessAccount(address)
 			}
 		}
@@ -1120,7 +1155,7 @@ func TestProcessor_SetUpAccessList(t *testing.T) {
 		},
 	}
 
-	for _, contract := range getPrecompiledAddresses(tosca.R13_Cancun) {
+	for _, contract := range PrecompiledAddresses(tosca.R13_Cancun) {
 		context.EXPECT().AccessAccount(contract)
 	}
 	context.EXPECT().AccessAccount(sender)
@@ -1130,7 +1165,7 @@ func TestProcessor_SetUpAccessList(t *testing.T) {
 	context.EXPECT().AccessStorage(accessListAddress, tosca.Key{2})
 	context.EXPECT().AccessAccount(coinbase)
 
-	setUpAccessList(transaction, context, tosca.R13_Cancun, coinbase)
+	setUpAccessList(transaction, context, tosca.R13_Cancun, coinbase, defaultPrecompiles)
 }
 
 func TestProcessor_AccessListIsNotCreatedIfTransactionHasNone(t *testing.T) {
@@ -1146,7 +1181,7 @@ func TestProcessor_AccessListIsNotCreatedIfTransactionHasNone(t *testing.T) {
 		Recipient: &recipient,
 	}
 
-	setUpAccessList(transaction, context, tosca.R09_Berlin, tosca.Address{})
+	setUpAccessList(transaction, context, tosca.R09_Berlin, tosca.Address{}, defaultPrecompiles)
 }
 
 // return excess gas tests
@@ -1400,3 +1435,39 @@ func TestProcessor_ethCompatibleReturnDoesNotConsumeExtraGasAndUpdatesCoinbase(t
 		})
 	}
 }
+
+func TestProcessor_RunContextDecoratorIsUsedForTopLevelCall(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	context := tosca.NewMockTransactionContext(ctrl)
+	interpreter := tosca.NewMockInterpreter(ctrl)
+	decorated := tosca.NewMockRunContext(ctrl)
+
+	context.EXPECT().GetNonce(gomock.Any()).AnyTimes()
+	context.EXPECT().SetNonce(gomock.Any(), gomock.Any()).AnyTimes()
+	context.EXPECT().GetCodeHash(gomock.Any()).AnyTimes()
+	context.EXPECT().GetBalance(gomock.Any()).AnyTimes()
+	context.EXPECT().SetBalance(gomock.Any(), gomock.Any()).AnyTimes()
+	context.EXPECT().GetLogs().AnyTimes()
+	decorated.EXPECT().Call(tosca.Call, gomock.Any()).Return(tosca.CallResult{Success: true}, nil)
+
+	processor := &Processor{
+		Interpreter: interpreter,
+		Config: Config{
+			RunContextDecorator: func(inner tosca.RunContext) tosca.RunContext {
+				if _, ok := inner.(*runContext); !ok {
+					t.Errorf("unexpected run context type %T", inner)
+				}
+				return decorated
+			},
+		},
+	}
+
+	transaction := tosca.Transaction{
+		Sender:    tosca.Address{1},
+		Recipient: &tosca.Address{2},
+		GasLimit:  tosca.Gas(1000000),
+	}
+	receipt, err := processor.Run(tosca.BlockParameters{}, transaction, context)
+	require.NoError(t, err)
+	require.True(t, receipt.Success)
+}
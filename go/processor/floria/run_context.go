@@ -30,10 +30,14 @@ type runContext struct {
 	transactionParameters tosca.TransactionParameters
 	depth                 int
 	static                bool
+	decorated             tosca.RunContext // < handed to interpreters instead of the run context itself if set
 }
 
 func (r *runContext) Call(kind tosca.CallKind, parameters tosca.CallParameters) (tosca.CallResult, error) {
-	if kind == tosca.Create || kind == tosca.Create2 {
+	if r.transactionParameters.IsCanceled() {
+		return tosca.CallResult{}, tosca.ErrCanceled
+	}
+	if kind == tosca.Create || kind == tosca.Create2 || kind == tosca.EofCreate {
 		return r.executeCreate(kind, parameters)
 	}
 	return r.executeCall(kind, parameters)
@@ -79,9 +83,10 @@ func (r *runContext) executeCall(kind tosca.CallKind, parameters tosca.CallParam
 		}
 	}
 
-	if isPrecompiled(parameters.CodeAddress, r.blockParameters.Revision) {
+	precompiles := getPrecompiles(r.config)
+	if isPrecompiled(precompiles, parameters.CodeAddress, r.blockParameters.Revision) {
 		result, err :=
-			runPrecompiledContract(r.blockParameters.Revision, parameters.Input, parameters.CodeAddress, parameters.Gas)
+			runPrecompiledContract(precompiles, r.blockParameters.Revision, parameters.Input, parameters.CodeAddress, parameters.Gas)
 		if err != nil {
 			result.Success = false
 		}
@@ -98,6 +103,7 @@ func (r *runContext) executeCall(kind tosca.CallKind, parameters tosca.CallParam
 		GasLeft:   result.GasLeft,
 		GasRefund: result.GasRefund,
 		Success:   result.Success,
+		Reverted:  result.Reverted,
 	}, nil
 }
 
@@ -151,10 +157,11 @@ func (r *runContext) executeCreate(kind tosca.CallKind, parameters tosca.CallPar
 			GasLeft:        result.GasLeft,
 			GasRefund:      result.GasRefund,
 			CreatedAddress: createdAddress,
+			Reverted:       result.Reverted,
 		}, nil
 	}
 
-	result = checkAndDeployCode(result, createdAddress, r.blockParameters.Revision, r)
+	result = checkAndDeployCode(kind, result, createdAddress, r.blockParameters.Revision, r)
 
 	return tosca.CallResult{
 		Output:         result.Output,
@@ -213,6 +220,14 @@ func createAddress(
 			common.Hash(parameters.Salt),
 			initHash[:],
 		))
+	case tosca.EofCreate:
+		// Like CREATE2, but based on the hash of the initcode container.
+		initHash := crypto.Keccak256(parameters.InitCode)
+		createdAddress = tosca.Address(crypto.CreateAddress2(
+			common.Address(parameters.Sender),
+			common.Hash(parameters.Salt),
+			initHash[:],
+		))
 	default:
 		return tosca.Address{}, fmt.Errorf("invalid call kind for create: %d", kind)
 	}
@@ -239,6 +254,7 @@ func isEmpty(context tosca.TransactionContext, address tosca.Address) bool {
 // If all checks pass, the code is deployed, in the case of failure the snapshot is restored and
 // the gas consumed.
 func checkAndDeployCode(
+	kind tosca.CallKind,
 	result tosca.Result,
 	createdAddress tosca.Address,
 	revision tosca.Revision,
@@ -250,8 +266,9 @@ func checkAndDeployCode(
 		result.Success = false
 	}
 
-	// with eip-3541 code is not allowed to start with 0xEF
-	if revision >= tosca.R10_London && len(outCode) > 0 && outCode[0] == 0xEF {
+	// with eip-3541 code is not allowed to start with 0xEF, EOF containers
+	// deployed by EOFCREATE have been validated by the interpreter
+	if revision >= tosca.R10_London && len(outCode) > 0 && outCode[0] == 0xEF && kind != tosca.EofCreate {
 		result.Success = false
 	}
 
@@ -286,12 +303,21 @@ func (r *runContext) runInterpreter(kind tosca.CallKind, parameters tosca.CallPa
 		code = tosca.Code(parameters.Input)
 		codeHash = tosca.Hash(crypto.Keccak256(code))
 		parameters.Input = nil
+	case tosca.EofCreate:
+		code = parameters.InitCode
+		codeHash = tosca.Hash(crypto.Keccak256(code))
+	}
+
+	var context tosca.RunContext = r
+	if r.decorated != nil {
+		context = r.decorated
 	}
 
 	interpreterParameters := tosca.Parameters{
 		BlockParameters:       r.blockParameters,
 		TransactionParameters: r.transactionParameters,
-		Context:               r,
+		Context:               context,
+		Kind:                  kind,
 		Static:                r.static,
 		Depth:                 r.depth - 1, // depth has already been incremented
 		Gas:                   parameters.Gas,
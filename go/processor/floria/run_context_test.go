@@ -16,6 +16,7 @@ import (
 	"testing"
 
 	test_utils "github.com/0xsoniclabs/tosca/go/processor"
+	"github.com/0xsoniclabs/tosca/go/processor/precompile"
 	"github.com/0xsoniclabs/tosca/go/tosca"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -432,6 +433,31 @@ func TestCreate_CreateAddress_UnsupportedKindTriggersError(t *testing.T) {
 	require.ErrorContains(t, err, "invalid call kind for create")
 }
 
+func TestCreate_CreateAddress_EofCreateUsesHashOfInitCode(t *testing.T) {
+	sender := tosca.Address{1}
+	salt := tosca.Hash{2}
+	initCode := tosca.Code{0xEF, 0x00, 0x01}
+	initHash := crypto.Keccak256(initCode)
+	want := tosca.Address(crypto.CreateAddress2(common.Address(sender), common.Hash(salt), initHash))
+
+	ctrl := gomock.NewController(t)
+	context := tosca.NewMockTransactionContext(ctrl)
+	context.EXPECT().AccessAccount(want)
+	context.EXPECT().GetNonce(want)
+	context.EXPECT().HasEmptyStorage(want).Return(true)
+	context.EXPECT().GetCodeHash(want)
+
+	parameters := tosca.CallParameters{
+		Sender:   sender,
+		Salt:     salt,
+		Input:    tosca.Data{1, 2, 3}, // < the call data does not influence the address
+		InitCode: initCode,
+	}
+	got, err := createAddress(tosca.EofCreate, parameters, tosca.R98_Experimental, context)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
+
 func TestCreate_CreateAddressReturnErrorIfAddressIsNotEmpty(t *testing.T) {
 	tests := map[string]struct {
 		nonce         uint64
@@ -550,7 +576,7 @@ func TestCreate_CheckAndDeployCode_SetsCodeOrResetsResult(t *testing.T) {
 				context.EXPECT().SetCode(createdAddress, tosca.Code(test.code))
 			}
 
-			finalizedResult := checkAndDeployCode(result, createdAddress, test.revision, context)
+			finalizedResult := checkAndDeployCode(tosca.Create, result, createdAddress, test.revision, context)
 
 			result.GasLeft = test.resultGasLeft
 			if !test.success {
@@ -562,6 +588,24 @@ func TestCreate_CheckAndDeployCode_SetsCodeOrResetsResult(t *testing.T) {
 	}
 }
 
+func TestCreate_CheckAndDeployCode_EofCreateDeploysEofContainers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	context := tosca.NewMockTransactionContext(ctrl)
+
+	createdAddress := tosca.Address{1}
+	code := tosca.Code{0xEF, 0x00, 0x01}
+	context.EXPECT().SetCode(createdAddress, code)
+
+	result := tosca.Result{
+		Success: true,
+		Output:  tosca.Data(code),
+		GasLeft: tosca.Gas(601),
+	}
+	finalizedResult := checkAndDeployCode(tosca.EofCreate, result, createdAddress, tosca.R98_Experimental, context)
+	require.True(t, finalizedResult.Success)
+	require.Equal(t, tosca.Gas(1), finalizedResult.GasLeft)
+}
+
 func TestCreate_senderCreateSetUp_ReturnsError(t *testing.T) {
 	tests := map[string]struct {
 		nonce uint64
@@ -840,6 +884,31 @@ func TestRunContext_runInterpreterCreateComputesCorrectCodeHash(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestRunContext_runInterpreterEofCreateRunsInitCodeWithCallData(t *testing.T) {
+	initCode := tosca.Code{0xEF, 0x00, 0x01}
+	input := tosca.Data{1, 2, 3}
+
+	ctrl := gomock.NewController(t)
+	interpreter := tosca.NewMockInterpreter(ctrl)
+	runContext := runContext{
+		interpreter: interpreter,
+	}
+
+	interpreter.EXPECT().Run(gomock.Any()).DoAndReturn(func(parameters tosca.Parameters) (tosca.Result, error) {
+		require.Equal(t, tosca.EofCreate, parameters.Kind)
+		require.Equal(t, input, parameters.Input)
+		require.Equal(t, initCode, parameters.Code)
+		require.Equal(t, tosca.Hash(crypto.Keccak256(initCode)), *parameters.CodeHash)
+		return tosca.Result{Success: true}, nil
+	})
+
+	_, err := runContext.runInterpreter(tosca.EofCreate, tosca.CallParameters{
+		Input:    input,
+		InitCode: initCode,
+	})
+	require.NoError(t, err)
+}
+
 func TestRunContext_runInterpreterForwardsValuesCorrectly(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	context := tosca.NewMockTransactionContext(ctrl)
@@ -957,6 +1026,38 @@ func TestCall_PrecompiledCheckDependsOnCodeAddress(t *testing.T) {
 	}
 }
 
+func TestCall_PrecompilesAreTakenFromConfig(t *testing.T) {
+	address := tosca.Address{0x42}
+	precompiles := precompile.NewRegistry()
+	require.NoError(t, precompiles.Register(address, precompile.Since(tosca.R07_Istanbul), constantContract{
+		gas:    10,
+		output: tosca.Data{0xff},
+	}))
+
+	ctrl := gomock.NewController(t)
+	context := tosca.NewMockTransactionContext(ctrl)
+	context.EXPECT().CreateSnapshot()
+
+	// No calls to the interpreter because the call is handled by the precompiled contract.
+	interpreter := tosca.NewMockInterpreter(ctrl)
+	runContext := runContext{
+		TransactionContext: context,
+		interpreter:        interpreter,
+		config:             Config{Precompiles: precompiles},
+	}
+
+	result, err := runContext.executeCall(tosca.Call, tosca.CallParameters{
+		Sender:      DriverAddress(),
+		Recipient:   address,
+		CodeAddress: address,
+		Gas:         100,
+	})
+	require.NoError(t, err)
+	require.True(t, result.Success)
+	require.Equal(t, tosca.Data{0xff}, result.Output)
+	require.Equal(t, tosca.Gas(90), result.GasLeft)
+}
+
 func TestCall_StateAndPrecompileErrorRestoresSnapshot(t *testing.T) {
 	tests := map[string]struct {
 		codeAddress tosca.Address
@@ -1412,3 +1513,44 @@ func TestRunContext_IncrementDepth(t *testing.T) {
 		})
 	}
 }
+
+func TestRunContext_runInterpreterHandsDecoratedContextToInterpreter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	context := tosca.NewMockTransactionContext(ctrl)
+	interpreter := tosca.NewMockInterpreter(ctrl)
+	decorated := tosca.NewMockRunContext(ctrl)
+	runContext := runContext{
+		TransactionContext: context,
+		interpreter:        interpreter,
+		decorated:          decorated,
+	}
+
+	context.EXPECT().GetCode(gomock.Any())
+	context.EXPECT().GetCodeHash(gomock.Any())
+	interpreter.EXPECT().Run(gomock.Any()).DoAndReturn(func(p tosca.Parameters) (tosca.Result, error) {
+		require.Same(t, decorated, p.Context)
+		return tosca.Result{Success: true}, nil
+	})
+
+	_, err := runContext.runInterpreter(tosca.Call, tosca.CallParameters{})
+	require.NoError(t, err)
+}
+
+func TestRunContext_CallIsRejectedIfTransactionIsCanceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	interpreter := tosca.NewMockInterpreter(ctrl)
+	context := tosca.NewMockTransactionContext(ctrl)
+
+	done := make(chan struct{})
+	close(done)
+	runContext := runContext{
+		TransactionContext: context,
+		interpreter:        interpreter,
+		transactionParameters: tosca.TransactionParameters{
+			Done: done,
+		},
+	}
+
+	_, err := runContext.Call(tosca.Call, tosca.CallParameters{})
+	require.ErrorIs(t, err, tosca.ErrCanceled)
+}
@@ -81,7 +81,13 @@ func (p *Processor) Run(
 	gasPool := core.NewGasPool(uint64(transaction.GasLimit))
 
 	snapshot := context.CreateSnapshot()
+	stop := geth_adapter.CancelOnDone(evm, transaction.Done)
 	result, err := core.ApplyMessage(evm, msg, gasPool)
+	stop()
+	if evm.Cancelled() {
+		context.RestoreSnapshot(snapshot)
+		return tosca.Receipt{}, tosca.ErrCanceled
+	}
 	if err != nil {
 		context.RestoreSnapshot(snapshot)
 		return tosca.Receipt{}, err
@@ -13,9 +13,13 @@ package geth_processor
 import (
 	"bytes"
 	"math/big"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/0xsoniclabs/tosca/go/interpreter/lfvm"
 	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/require"
@@ -267,3 +271,63 @@ func TestCalculateGasPrices_InternalTransactions_GetPricesBelowBaseFee(t *testin
 	require.NoError(err)
 	require.Equal(price, gasFeeCap)
 }
+
+func TestProcessor_InfiniteLoopIsAbortedWhenTransactionIsCanceled(t *testing.T) {
+	interpreter, err := lfvm.NewInterpreter(lfvm.Config{})
+	require.NoError(t, err)
+	processor := sonicProcessor(interpreter)
+
+	recipient := tosca.Address{1}
+	code := tosca.Code{byte(vm.JUMPDEST), byte(vm.PUSH1), 0, byte(vm.JUMP)}
+	started := make(chan struct{})
+	var once sync.Once
+
+	ctrl := gomock.NewController(t)
+	context := tosca.NewMockTransactionContext(ctrl)
+	context.EXPECT().GetCode(recipient).DoAndReturn(func(tosca.Address) tosca.Code {
+		once.Do(func() { close(started) })
+		return code
+	}).AnyTimes()
+	context.EXPECT().GetCodeHash(recipient).Return(tosca.Hash{1}).AnyTimes()
+	context.EXPECT().GetCode(gomock.Any()).AnyTimes()
+	context.EXPECT().GetCodeHash(gomock.Any()).AnyTimes()
+	context.EXPECT().GetCodeSize(gomock.Any()).AnyTimes()
+	context.EXPECT().AccountExists(gomock.Any()).Return(true).AnyTimes()
+	context.EXPECT().GetBalance(gomock.Any()).AnyTimes()
+	context.EXPECT().SetBalance(gomock.Any(), gomock.Any()).AnyTimes()
+	context.EXPECT().GetNonce(gomock.Any()).AnyTimes()
+	context.EXPECT().SetNonce(gomock.Any(), gomock.Any()).AnyTimes()
+	context.EXPECT().CreateSnapshot().AnyTimes()
+	context.EXPECT().RestoreSnapshot(gomock.Any()).AnyTimes()
+	context.EXPECT().IsAddressInAccessList(gomock.Any()).AnyTimes()
+	context.EXPECT().AccessAccount(gomock.Any()).AnyTimes()
+	context.EXPECT().HasSelfDestructed(gomock.Any()).AnyTimes()
+	context.EXPECT().GetLogs().AnyTimes()
+
+	done := make(chan struct{})
+	transaction := tosca.Transaction{
+		Sender:    tosca.Address{2},
+		Recipient: &recipient,
+		GasLimit:  1 << 50,
+		Done:      done,
+	}
+	blockParameters := tosca.BlockParameters{
+		Revision: tosca.R13_Cancun,
+		GasLimit: 1 << 50,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := processor.Run(blockParameters, transaction, context)
+		errs <- err
+	}()
+
+	<-started
+	close(done)
+	select {
+	case err := <-errs:
+		require.ErrorIs(t, err, tosca.ErrCanceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("execution was not aborted after cancellation")
+	}
+}
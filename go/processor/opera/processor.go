@@ -232,6 +232,7 @@ func (p *processor) Run(
 		vmError         error
 		createdContract *tosca.Address
 	)
+	stop := geth_adapter.CancelOnDone(evm, transaction.Done)
 	if contractCreation {
 		var created common.Address
 		output, created, gasLeft, vmError = evm.Create(sender, transaction.Input, uint64(gas), transaction.Value.ToUint256())
@@ -242,6 +243,10 @@ func (p *processor) Run(
 		stateDb.SetNonce(common.Address(transaction.Sender), stateDb.GetNonce(common.Address(transaction.Sender))+1, tracing.NonceChangeUnspecified)
 		output, gasLeft, vmError = evm.Call(sender, common.Address(*transaction.Recipient), transaction.Input, uint64(gas), transaction.Value.ToUint256())
 	}
+	stop()
+	if evm.Cancelled() {
+		return tosca.Receipt{}, tosca.ErrCanceled
+	}
 
 	// For whatever reason, 10% of remaining gas is charged for non-internal transactions.
 	if !isInternal(transaction) {
@@ -0,0 +1,326 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package precompile
+
+import (
+	"errors"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// The precompiled contracts for the BLS12-381 curve are defined by EIP-2537
+// (Prague). Field elements are encoded in 64 bytes, the leading 16 of which
+// must be zero. G1 points are encoded in 128 bytes, G2 points in 256 bytes.
+const (
+	bls12381G1AddGas          = 375
+	bls12381G1MulGas          = 12000
+	bls12381G2AddGas          = 600
+	bls12381G2MulGas          = 22500
+	bls12381PairingBaseGas    = 37700
+	bls12381PairingPerPairGas = 32600
+	bls12381MapG1Gas          = 5500
+	bls12381MapG2Gas          = 23800
+
+	bls12381FieldElementLength = 64
+	bls12381G1PointLength      = 2 * bls12381FieldElementLength
+	bls12381G2PointLength      = 4 * bls12381FieldElementLength
+	bls12381ScalarLength       = 32
+	bls12381G1MsmPairLength    = bls12381G1PointLength + bls12381ScalarLength
+	bls12381G2MsmPairLength    = bls12381G2PointLength + bls12381ScalarLength
+	bls12381PairingPairLength  = bls12381G1PointLength + bls12381G2PointLength
+)
+
+var (
+	errBls12381InvalidInputLength  = errors.New("invalid input length")
+	errBls12381InvalidFieldElement = errors.New("invalid field element")
+	errBls12381PointNotOnCurve     = errors.New("point is not on curve")
+	errBls12381PointNotInSubgroup  = errors.New("point is not in the correct subgroup")
+)
+
+// bls12381G1MsmDiscounts are the discounts in per mille applied to the price
+// of a G1 multi-scalar multiplication depending on the number of pairs.
+var bls12381G1MsmDiscounts = [128]uint64{1000, 949, 848, 797, 764, 750, 738, 728, 719, 712, 705, 698, 692, 687, 682, 677, 673, 669, 665, 661, 658, 654, 651, 648, 645, 642, 640, 637, 635, 632, 630, 627, 625, 623, 621, 619, 617, 615, 613, 611, 609, 608, 606, 604, 603, 601, 599, 598, 596, 595, 593, 592, 591, 589, 588, 586, 585, 584, 582, 581, 580, 579, 577, 576, 575, 574, 573, 572, 570, 569, 568, 567, 566, 565, 564, 563, 562, 561, 560, 559, 558, 557, 556, 555, 554, 553, 552, 551, 550, 549, 548, 547, 547, 546, 545, 544, 543, 542, 541, 540, 540, 539, 538, 537, 536, 536, 535, 534, 533, 532, 532, 531, 530, 529, 528, 528, 527, 526, 525, 525, 524, 523, 522, 522, 521, 520, 520, 519}
+
+// bls12381G2MsmDiscounts are the discounts in per mille applied to the price
+// of a G2 multi-scalar multiplication depending on the number of pairs.
+var bls12381G2MsmDiscounts = [128]uint64{1000, 1000, 923, 884, 855, 832, 812, 796, 782, 770, 759, 749, 740, 732, 724, 717, 711, 704, 699, 693, 688, 683, 679, 674, 670, 666, 663, 659, 655, 652, 649, 646, 643, 640, 637, 634, 632, 629, 627, 624, 622, 620, 618, 615, 613, 611, 609, 607, 606, 604, 602, 600, 598, 597, 595, 593, 592, 590, 589, 587, 586, 584, 583, 582, 580, 579, 578, 576, 575, 574, 573, 571, 570, 569, 568, 567, 566, 565, 563, 562, 561, 560, 559, 558, 557, 556, 555, 554, 553, 552, 552, 551, 550, 549, 548, 547, 546, 545, 545, 544, 543, 542, 541, 541, 540, 539, 538, 537, 537, 536, 535, 535, 534, 533, 532, 532, 531, 530, 530, 529, 528, 528, 527, 526, 526, 525, 524, 524}
+
+// bls12381G1Add adds two G1 points (address 0x0b).
+type bls12381G1Add struct{}
+
+func (bls12381G1Add) RequiredGas(tosca.Data) uint64 {
+	return bls12381G1AddGas
+}
+
+func (bls12381G1Add) Run(input tosca.Data) (tosca.Data, error) {
+	if len(input) != 2*bls12381G1PointLength {
+		return nil, errBls12381InvalidInputLength
+	}
+	a, err := decodeBls12381G1(input[:bls12381G1PointLength])
+	if err != nil {
+		return nil, err
+	}
+	b, err := decodeBls12381G1(input[bls12381G1PointLength:])
+	if err != nil {
+		return nil, err
+	}
+	// Additions do not require subgroup checks.
+	return encodeBls12381G1(new(bls12381.G1Affine).Add(a, b)), nil
+}
+
+// bls12381G1MultiExp computes a multi-scalar multiplication of G1 points
+// (address 0x0c).
+type bls12381G1MultiExp struct{}
+
+func (bls12381G1MultiExp) RequiredGas(input tosca.Data) uint64 {
+	return bls12381MsmGas(len(input)/bls12381G1MsmPairLength, bls12381G1MulGas, &bls12381G1MsmDiscounts)
+}
+
+func (bls12381G1MultiExp) Run(input tosca.Data) (tosca.Data, error) {
+	if len(input) == 0 || len(input)%bls12381G1MsmPairLength != 0 {
+		return nil, errBls12381InvalidInputLength
+	}
+	k := len(input) / bls12381G1MsmPairLength
+	points := make([]bls12381.G1Affine, k)
+	scalars := make([]fr.Element, k)
+	for i := range k {
+		pair := input[i*bls12381G1MsmPairLength:]
+		point, err := decodeBls12381G1(pair[:bls12381G1PointLength])
+		if err != nil {
+			return nil, err
+		}
+		if !point.IsInSubGroup() {
+			return nil, errBls12381PointNotInSubgroup
+		}
+		points[i] = *point
+		scalars[i].SetBytes(pair[bls12381G1PointLength:bls12381G1MsmPairLength])
+	}
+	result := new(bls12381.G1Affine)
+	if _, err := result.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return nil, err
+	}
+	return encodeBls12381G1(result), nil
+}
+
+// bls12381G2Add adds two G2 points (address 0x0d).
+type bls12381G2Add struct{}
+
+func (bls12381G2Add) RequiredGas(tosca.Data) uint64 {
+	return bls12381G2AddGas
+}
+
+func (bls12381G2Add) Run(input tosca.Data) (tosca.Data, error) {
+	if len(input) != 2*bls12381G2PointLength {
+		return nil, errBls12381InvalidInputLength
+	}
+	a, err := decodeBls12381G2(input[:bls12381G2PointLength])
+	if err != nil {
+		return nil, err
+	}
+	b, err := decodeBls12381G2(input[bls12381G2PointLength:])
+	if err != nil {
+		return nil, err
+	}
+	// Additions do not require subgroup checks.
+	return encodeBls12381G2(new(bls12381.G2Affine).Add(a, b)), nil
+}
+
+// bls12381G2MultiExp computes a multi-scalar multiplication of G2 points
+// (address 0x0e).
+type bls12381G2MultiExp struct{}
+
+func (bls12381G2MultiExp) RequiredGas(input tosca.Data) uint64 {
+	return bls12381MsmGas(len(input)/bls12381G2MsmPairLength, bls12381G2MulGas, &bls12381G2MsmDiscounts)
+}
+
+func (bls12381G2MultiExp) Run(input tosca.Data) (tosca.Data, error) {
+	if len(input) == 0 || len(input)%bls12381G2MsmPairLength != 0 {
+		return nil, errBls12381InvalidInputLength
+	}
+	k := len(input) / bls12381G2MsmPairLength
+	points := make([]bls12381.G2Affine, k)
+	scalars := make([]fr.Element, k)
+	for i := range k {
+		pair := input[i*bls12381G2MsmPairLength:]
+		point, err := decodeBls12381G2(pair[:bls12381G2PointLength])
+		if err != nil {
+			return nil, err
+		}
+		if !point.IsInSubGroup() {
+			return nil, errBls12381PointNotInSubgroup
+		}
+		points[i] = *point
+		scalars[i].SetBytes(pair[bls12381G2PointLength:bls12381G2MsmPairLength])
+	}
+	result := new(bls12381.G2Affine)
+	if _, err := result.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return nil, err
+	}
+	return encodeBls12381G2(result), nil
+}
+
+// bls12381Pairing checks a pairing equation on the BLS12-381 curve (address
+// 0x0f).
+type bls12381Pairing struct{}
+
+func (bls12381Pairing) RequiredGas(input tosca.Data) uint64 {
+	return bls12381PairingBaseGas + uint64(len(input)/bls12381PairingPairLength)*bls12381PairingPerPairGas
+}
+
+func (bls12381Pairing) Run(input tosca.Data) (tosca.Data, error) {
+	if len(input) == 0 || len(input)%bls12381PairingPairLength != 0 {
+		return nil, errBls12381InvalidInputLength
+	}
+	k := len(input) / bls12381PairingPairLength
+	g1s := make([]bls12381.G1Affine, k)
+	g2s := make([]bls12381.G2Affine, k)
+	for i := range k {
+		pair := input[i*bls12381PairingPairLength:]
+		g1, err := decodeBls12381G1(pair[:bls12381G1PointLength])
+		if err != nil {
+			return nil, err
+		}
+		g2, err := decodeBls12381G2(pair[bls12381G1PointLength:bls12381PairingPairLength])
+		if err != nil {
+			return nil, err
+		}
+		if !g1.IsInSubGroup() || !g2.IsInSubGroup() {
+			return nil, errBls12381PointNotInSubgroup
+		}
+		g1s[i] = *g1
+		g2s[i] = *g2
+	}
+	ok, err := bls12381.PairingCheck(g1s, g2s)
+	return encodeBool(err == nil && ok), nil
+}
+
+// bls12381MapG1 maps a base field element to a G1 point (address 0x10).
+type bls12381MapG1 struct{}
+
+func (bls12381MapG1) RequiredGas(tosca.Data) uint64 {
+	return bls12381MapG1Gas
+}
+
+func (bls12381MapG1) Run(input tosca.Data) (tosca.Data, error) {
+	if len(input) != bls12381FieldElementLength {
+		return nil, errBls12381InvalidInputLength
+	}
+	element, err := decodeBls12381FieldElement(input)
+	if err != nil {
+		return nil, err
+	}
+	point := bls12381.MapToG1(element)
+	return encodeBls12381G1(&point), nil
+}
+
+// bls12381MapG2 maps an element of the quadratic extension field to a G2
+// point (address 0x11).
+type bls12381MapG2 struct{}
+
+func (bls12381MapG2) RequiredGas(tosca.Data) uint64 {
+	return bls12381MapG2Gas
+}
+
+func (bls12381MapG2) Run(input tosca.Data) (tosca.Data, error) {
+	if len(input) != 2*bls12381FieldElementLength {
+		return nil, errBls12381InvalidInputLength
+	}
+	a0, err := decodeBls12381FieldElement(input[:bls12381FieldElementLength])
+	if err != nil {
+		return nil, err
+	}
+	a1, err := decodeBls12381FieldElement(input[bls12381FieldElementLength:])
+	if err != nil {
+		return nil, err
+	}
+	point := bls12381.MapToG2(bls12381.E2{A0: a0, A1: a1})
+	return encodeBls12381G2(&point), nil
+}
+
+// bls12381MsmGas computes the price of a multi-scalar multiplication of k
+// pairs, including the discount for the number of pairs.
+func bls12381MsmGas(k int, mulGas uint64, discounts *[128]uint64) uint64 {
+	if k == 0 {
+		return 0
+	}
+	discount := discounts[min(k, len(discounts))-1]
+	return uint64(k) * mulGas * discount / 1000
+}
+
+func decodeBls12381FieldElement(data []byte) (fp.Element, error) {
+	if !allZero(data[:16]) {
+		return fp.Element{}, errBls12381InvalidFieldElement
+	}
+	element, err := fp.BigEndian.Element((*[fp.Bytes]byte)(data[16:bls12381FieldElementLength]))
+	if err != nil {
+		return fp.Element{}, errBls12381InvalidFieldElement
+	}
+	return element, nil
+}
+
+func decodeBls12381G1(data []byte) (*bls12381.G1Affine, error) {
+	x, err := decodeBls12381FieldElement(data[:bls12381FieldElementLength])
+	if err != nil {
+		return nil, err
+	}
+	y, err := decodeBls12381FieldElement(data[bls12381FieldElementLength:bls12381G1PointLength])
+	if err != nil {
+		return nil, err
+	}
+	point := &bls12381.G1Affine{X: x, Y: y}
+	if !point.IsOnCurve() {
+		return nil, errBls12381PointNotOnCurve
+	}
+	return point, nil
+}
+
+func decodeBls12381G2(data []byte) (*bls12381.G2Affine, error) {
+	var elements [4]fp.Element
+	for i := range elements {
+		element, err := decodeBls12381FieldElement(data[i*bls12381FieldElementLength:])
+		if err != nil {
+			return nil, err
+		}
+		elements[i] = element
+	}
+	point := &bls12381.G2Affine{
+		X: bls12381.E2{A0: elements[0], A1: elements[1]},
+		Y: bls12381.E2{A0: elements[2], A1: elements[3]},
+	}
+	if !point.IsOnCurve() {
+		return nil, errBls12381PointNotOnCurve
+	}
+	return point, nil
+}
+
+func encodeBls12381G1(point *bls12381.G1Affine) tosca.Data {
+	res := make(tosca.Data, bls12381G1PointLength)
+	putBls12381FieldElement(res[0:], point.X)
+	putBls12381FieldElement(res[64:], point.Y)
+	return res
+}
+
+func encodeBls12381G2(point *bls12381.G2Affine) tosca.Data {
+	res := make(tosca.Data, bls12381G2PointLength)
+	putBls12381FieldElement(res[0:], point.X.A0)
+	putBls12381FieldElement(res[64:], point.X.A1)
+	putBls12381FieldElement(res[128:], point.Y.A0)
+	putBls12381FieldElement(res[192:], point.Y.A1)
+	return res
+}
+
+func putBls12381FieldElement(out []byte, element fp.Element) {
+	fp.BigEndian.PutElement((*[fp.Bytes]byte)(out[16:bls12381FieldElementLength]), element)
+}
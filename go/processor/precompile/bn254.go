@@ -0,0 +1,120 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package precompile
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/ethereum/go-ethereum/crypto/bn256"
+)
+
+// The precompiled contracts for the alt_bn128 curve are defined by EIP-196 and
+// EIP-197. Their prices are the ones introduced by EIP-1108 (Istanbul).
+const (
+	bn254AddGas             = 150
+	bn254ScalarMulGas       = 6000
+	bn254PairingBaseGas     = 45000
+	bn254PairingPerPointGas = 34000
+)
+
+const bn254PairingInputLength = 192
+
+var errBn254InvalidPairingInput = errors.New("invalid bn254 pairing input length")
+
+// bn254Add adds two points of the alt_bn128 curve (address 0x06).
+type bn254Add struct{}
+
+func (bn254Add) RequiredGas(tosca.Data) uint64 {
+	return bn254AddGas
+}
+
+func (bn254Add) Run(input tosca.Data) (tosca.Data, error) {
+	a, err := decodeBn254G1(getData(input, 0, 64))
+	if err != nil {
+		return nil, err
+	}
+	b, err := decodeBn254G1(getData(input, 64, 64))
+	if err != nil {
+		return nil, err
+	}
+	sum := new(bn256.G1)
+	sum.Add(a, b)
+	return sum.Marshal(), nil
+}
+
+// bn254ScalarMul multiplies a point of the alt_bn128 curve by a scalar
+// (address 0x07).
+type bn254ScalarMul struct{}
+
+func (bn254ScalarMul) RequiredGas(tosca.Data) uint64 {
+	return bn254ScalarMulGas
+}
+
+func (bn254ScalarMul) Run(input tosca.Data) (tosca.Data, error) {
+	point, err := decodeBn254G1(getData(input, 0, 64))
+	if err != nil {
+		return nil, err
+	}
+	scalar := new(big.Int).SetBytes(getData(input, 64, 32))
+	product := new(bn256.G1)
+	product.ScalarMult(point, scalar)
+	return product.Marshal(), nil
+}
+
+// bn254Pairing checks a pairing equation on the alt_bn128 curve (address
+// 0x08).
+type bn254Pairing struct{}
+
+func (bn254Pairing) RequiredGas(input tosca.Data) uint64 {
+	return bn254PairingBaseGas + uint64(len(input)/bn254PairingInputLength)*bn254PairingPerPointGas
+}
+
+func (bn254Pairing) Run(input tosca.Data) (tosca.Data, error) {
+	if len(input)%bn254PairingInputLength != 0 {
+		return nil, errBn254InvalidPairingInput
+	}
+	var (
+		g1s []*bn256.G1
+		g2s []*bn256.G2
+	)
+	for i := 0; i < len(input); i += bn254PairingInputLength {
+		g1, err := decodeBn254G1(input[i : i+64])
+		if err != nil {
+			return nil, err
+		}
+		g2 := new(bn256.G2)
+		if _, err := g2.Unmarshal(input[i+64 : i+bn254PairingInputLength]); err != nil {
+			return nil, err
+		}
+		g1s = append(g1s, g1)
+		g2s = append(g2s, g2)
+	}
+	return encodeBool(bn256.PairingCheck(g1s, g2s)), nil
+}
+
+func decodeBn254G1(data []byte) (*bn256.G1, error) {
+	point := new(bn256.G1)
+	if _, err := point.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	return point, nil
+}
+
+// encodeBool encodes the given value as a 32-byte word.
+func encodeBool(value bool) tosca.Data {
+	res := make(tosca.Data, 32)
+	if value {
+		res[31] = 1
+	}
+	return res
+}
@@ -0,0 +1,235 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package precompile
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	test_utils "github.com/0xsoniclabs/tosca/go/processor"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/ethereum/go-ethereum/common"
+	geth "github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/bn256"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStandardContracts_MatchGethImplementation(t *testing.T) {
+	registry := NewStandardRegistry()
+	inputs := getTestInputs(t)
+	for _, revision := range append(tosca.GetAllKnownRevisions(), tosca.R98_Experimental) {
+		reference := getGethPrecompiles(revision)
+		require.Len(t, registry.Addresses(revision), len(reference), "revision %v", revision)
+		for address, want := range reference {
+			got, found := registry.Get(tosca.Address(address), revision)
+			require.True(t, found, "missing contract %v in revision %v", address, revision)
+			for _, input := range inputs {
+				require.Equal(t, want.RequiredGas(input), got.RequiredGas(input),
+					"gas of %v in revision %v for input %x", want.Name(), revision, input)
+				if want.RequiredGas(input) > 1<<32 {
+					continue // < too expensive to be run
+				}
+				wantOutput, wantErr := want.Run(input)
+				gotOutput, gotErr := got.Run(input)
+				require.Equal(t, wantErr == nil, gotErr == nil,
+					"error of %v in revision %v for input %x: want %v, got %v", want.Name(), revision, input, wantErr, gotErr)
+				require.True(t, bytes.Equal(wantOutput, gotOutput),
+					"output of %v in revision %v for input %x: want %x, got %x", want.Name(), revision, input, wantOutput, gotOutput)
+			}
+		}
+	}
+}
+
+func TestIdentity_ResultIsACopy(t *testing.T) {
+	input := tosca.Data{1, 2, 3}
+	output, err := identity{}.Run(input)
+	require.NoError(t, err)
+	require.Equal(t, input, output)
+	output[0] = 4
+	require.Equal(t, tosca.Data{1, 2, 3}, input)
+}
+
+func TestKzgPointEvaluation_ValidProofProducesFieldParameters(t *testing.T) {
+	output, err := kzgPointEvaluation{}.Run(test_utils.ValidPointEvaluationInput)
+	require.NoError(t, err)
+	require.Equal(t, common.Hex2Bytes("000000000000000000000000000000000000000000000000000000000000100073eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001"), []byte(output))
+}
+
+func TestP256Verify_ValidSignaturesAreAccepted(t *testing.T) {
+	input := newP256VerifyInput(t)
+	output, err := p256Verify{}.Run(input)
+	require.NoError(t, err)
+	require.Equal(t, encodeBool(true), output)
+}
+
+func TestP256Verify_InvalidInputsProduceEmptyOutput(t *testing.T) {
+	n := elliptic.P256().Params().N
+	p := elliptic.P256().Params().P
+	tests := map[string]func(input []byte) []byte{
+		"too short":     func(input []byte) []byte { return input[:159] },
+		"too long":      func(input []byte) []byte { return append(input, 0) },
+		"modified hash": func(input []byte) []byte { input[0]++; return input },
+		"zero r":        func(input []byte) []byte { clear(input[32:64]); return input },
+		"r equals n":    func(input []byte) []byte { n.FillBytes(input[32:64]); return input },
+		"zero s":        func(input []byte) []byte { clear(input[64:96]); return input },
+		"s equals n":    func(input []byte) []byte { n.FillBytes(input[64:96]); return input },
+		"x equals p":    func(input []byte) []byte { p.FillBytes(input[96:128]); return input },
+		"y equals p":    func(input []byte) []byte { p.FillBytes(input[128:160]); return input },
+		"infinity":      func(input []byte) []byte { clear(input[96:160]); return input },
+		"not on curve":  func(input []byte) []byte { input[159]++; return input },
+	}
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			output, err := p256Verify{}.Run(modify(newP256VerifyInput(t)))
+			require.NoError(t, err)
+			require.Empty(t, output)
+		})
+	}
+}
+
+func getGethPrecompiles(revision tosca.Revision) geth.PrecompiledContracts {
+	switch revision {
+	case tosca.R07_Istanbul:
+		return geth.PrecompiledContractsIstanbul
+	case tosca.R09_Berlin, tosca.R10_London, tosca.R11_Paris, tosca.R12_Shanghai:
+		return geth.PrecompiledContractsBerlin
+	case tosca.R13_Cancun:
+		return geth.PrecompiledContractsCancun
+	case tosca.R14_Prague:
+		return geth.PrecompiledContractsPrague
+	default:
+		return geth.PrecompiledContractsOsaka
+	}
+}
+
+// getTestInputs produces inputs covering valid and invalid calls of all
+// standard contracts.
+func getTestInputs(t *testing.T) []tosca.Data {
+	t.Helper()
+	random := rand.New(rand.NewSource(42))
+	inputs := []tosca.Data{nil, {}}
+	for _, size := range []int{1, 31, 32, 33, 64, 96, 128, 160, 192, 213, 256, 288, 384, 512} {
+		input := make(tosca.Data, size)
+		random.Read(input)
+		inputs = append(inputs, input, make(tosca.Data, size))
+	}
+
+	// ecrecover
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	hash := crypto.Keccak256([]byte("hello"))
+	signature, err := crypto.Sign(hash, key)
+	require.NoError(t, err)
+	ecRecoverInput := concat(hash, leftPad([]byte{signature[64] + 27}, 32), signature[:64])
+	inputs = append(inputs, ecRecoverInput, ecRecoverInput[:100])
+
+	// modexp
+	for _, lengths := range [][3]int{{1, 1, 1}, {0, 0, 0}, {0, 1, 1}, {32, 32, 32}, {64, 40, 64}, {1, 1, 0}, {1025, 1, 1}, {100, 200, 300}} {
+		header := concat(
+			leftPad(big.NewInt(int64(lengths[0])).Bytes(), 32),
+			leftPad(big.NewInt(int64(lengths[1])).Bytes(), 32),
+			leftPad(big.NewInt(int64(lengths[2])).Bytes(), 32),
+		)
+		body := make([]byte, lengths[0]+lengths[1]+lengths[2])
+		random.Read(body)
+		inputs = append(inputs, concat(header, body), concat(header, body[:len(body)/2]))
+	}
+	inputs = append(inputs, concat(make([]byte, 31), []byte{1}, leftPad([]byte{0x01, 0x00}, 32), make([]byte, 31), []byte{1}, []byte{3}, []byte{0xff}, []byte{7}))
+
+	// bn254
+	generator, err := decodeBn254G1(concat(leftPad([]byte{1}, 32), leftPad([]byte{2}, 32)))
+	require.NoError(t, err)
+	point := new(bn256.G1)
+	point.ScalarMult(generator, big.NewInt(3))
+	g1 := point.Marshal()
+	g2 := concat( // < the generator of G2 as defined by EIP-197
+		decimalToWord(t, "11559732032986387107991004021392285783925812861821192530917403151452391805634"),
+		decimalToWord(t, "10857046999023057135944570762232829481370756359578518086990519993285655852781"),
+		decimalToWord(t, "4082367875863433681332203403145435568316851327593401208105741076214120093531"),
+		decimalToWord(t, "8495653923123431417604973247489272438418190587263600148770280649306958101930"),
+	)
+	inputs = append(inputs,
+		concat(g1, g1),
+		concat(g1, leftPad([]byte{7}, 32)),
+		concat(g1, g2),
+		concat(g1, g2, g1, g2),
+	)
+
+	// blake2f
+	blake2FInput := make([]byte, blake2FInputLength)
+	random.Read(blake2FInput)
+	copy(blake2FInput[:4], []byte{0, 0, 0, 12})
+	blake2FInput[212] = 1
+	inputs = append(inputs, blake2FInput)
+
+	// point evaluation
+	inputs = append(inputs, test_utils.ValidPointEvaluationInput)
+
+	// bls12-381
+	_, _, blsG1, blsG2 := bls12381.Generators()
+	blsG1Encoded := encodeBls12381G1(&blsG1)
+	blsG2Encoded := encodeBls12381G2(&blsG2)
+	scalar := leftPad([]byte{9}, 32)
+	fieldElement := leftPad([]byte{5}, 64)
+	inputs = append(inputs,
+		concat(blsG1Encoded, blsG1Encoded),
+		concat(blsG1Encoded, scalar),
+		concat(blsG1Encoded, scalar, blsG1Encoded, scalar),
+		concat(blsG2Encoded, blsG2Encoded),
+		concat(blsG2Encoded, scalar),
+		concat(blsG1Encoded, blsG2Encoded),
+		fieldElement,
+		concat(fieldElement, fieldElement),
+	)
+
+	// p256verify
+	inputs = append(inputs, newP256VerifyInput(t))
+	return inputs
+}
+
+func newP256VerifyInput(t *testing.T) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), cryptorand.Reader)
+	require.NoError(t, err)
+	hash := sha256.Sum256([]byte("hello"))
+	r, s, err := ecdsa.Sign(cryptorand.Reader, key, hash[:])
+	require.NoError(t, err)
+	return concat(
+		hash[:],
+		r.FillBytes(make([]byte, 32)),
+		s.FillBytes(make([]byte, 32)),
+		key.X.FillBytes(make([]byte, 32)),
+		key.Y.FillBytes(make([]byte, 32)),
+	)
+}
+
+func decimalToWord(t *testing.T, decimal string) []byte {
+	t.Helper()
+	value, ok := new(big.Int).SetString(decimal, 10)
+	require.True(t, ok)
+	return value.FillBytes(make([]byte, 32))
+}
+
+func concat(parts ...[]byte) tosca.Data {
+	res := tosca.Data{}
+	for _, part := range parts {
+		res = append(res, part...)
+	}
+	return res
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package precompile
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/blake2b"
+	"golang.org/x/crypto/ripemd160"
+)
+
+const (
+	ecRecoverGas        = 3000
+	sha256BaseGas       = 60
+	sha256PerWordGas    = 12
+	ripemd160BaseGas    = 600
+	ripemd160PerWordGas = 120
+	identityBaseGas     = 15
+	identityPerWordGas  = 3
+)
+
+// ecRecover recovers the address of the signer of a hash (address 0x01).
+type ecRecover struct{}
+
+func (ecRecover) RequiredGas(tosca.Data) uint64 {
+	return ecRecoverGas
+}
+
+func (ecRecover) Run(input tosca.Data) (tosca.Data, error) {
+	// The input is (hash, v, r, s), each 32 bytes, right-padded with zeros.
+	input = getData(input, 0, 128)
+	r := new(big.Int).SetBytes(input[64:96])
+	s := new(big.Int).SetBytes(input[96:128])
+	v := input[63] - 27
+
+	// Invalid signatures do not fail the call but produce an empty output.
+	if !allZero(input[32:63]) || !crypto.ValidateSignatureValues(v, r, s, false) {
+		return nil, nil
+	}
+	var signature [65]byte
+	copy(signature[:], input[64:128])
+	signature[64] = v
+	publicKey, err := crypto.Ecrecover(input[:32], signature[:])
+	if err != nil {
+		return nil, nil
+	}
+	return leftPad(crypto.Keccak256(publicKey[1:])[12:], 32), nil
+}
+
+// sha256Hash computes the SHA2-256 hash of its input (address 0x02).
+type sha256Hash struct{}
+
+func (sha256Hash) RequiredGas(input tosca.Data) uint64 {
+	return sha256BaseGas + sha256PerWordGas*wordCount(input)
+}
+
+func (sha256Hash) Run(input tosca.Data) (tosca.Data, error) {
+	hash := sha256.Sum256(input)
+	return hash[:], nil
+}
+
+// ripemd160Hash computes the RIPEMD-160 hash of its input (address 0x03).
+type ripemd160Hash struct{}
+
+func (ripemd160Hash) RequiredGas(input tosca.Data) uint64 {
+	return ripemd160BaseGas + ripemd160PerWordGas*wordCount(input)
+}
+
+func (ripemd160Hash) Run(input tosca.Data) (tosca.Data, error) {
+	hasher := ripemd160.New()
+	hasher.Write(input)
+	return leftPad(hasher.Sum(nil), 32), nil
+}
+
+// identity returns a copy of its input (address 0x04).
+type identity struct{}
+
+func (identity) RequiredGas(input tosca.Data) uint64 {
+	return identityBaseGas + identityPerWordGas*wordCount(input)
+}
+
+func (identity) Run(input tosca.Data) (tosca.Data, error) {
+	return tosca.Data(append([]byte{}, input...)), nil
+}
+
+// blake2F runs the compression function F of BLAKE2b as defined by EIP-152
+// (address 0x09).
+type blake2F struct{}
+
+const blake2FInputLength = 213
+
+var (
+	errBlake2FInvalidInputLength = errors.New("invalid input length")
+	errBlake2FInvalidFinalFlag   = errors.New("invalid final flag")
+)
+
+func (blake2F) RequiredGas(input tosca.Data) uint64 {
+	// Malformed inputs are not charged, their execution fails consuming all
+	// provided gas.
+	if len(input) != blake2FInputLength {
+		return 0
+	}
+	return uint64(binary.BigEndian.Uint32(input[0:4]))
+}
+
+func (blake2F) Run(input tosca.Data) (tosca.Data, error) {
+	if len(input) != blake2FInputLength {
+		return nil, errBlake2FInvalidInputLength
+	}
+	if input[212] > 1 {
+		return nil, errBlake2FInvalidFinalFlag
+	}
+	var (
+		rounds = binary.BigEndian.Uint32(input[0:4])
+		final  = input[212] == 1
+		h      [8]uint64
+		m      [16]uint64
+		t      [2]uint64
+	)
+	for i := range h {
+		h[i] = binary.LittleEndian.Uint64(input[4+i*8:])
+	}
+	for i := range m {
+		m[i] = binary.LittleEndian.Uint64(input[68+i*8:])
+	}
+	t[0] = binary.LittleEndian.Uint64(input[196:204])
+	t[1] = binary.LittleEndian.Uint64(input[204:212])
+
+	blake2b.F(&h, m, t, final, rounds)
+
+	output := make([]byte, 64)
+	for i := range h {
+		binary.LittleEndian.PutUint64(output[i*8:], h[i])
+	}
+	return output, nil
+}
+
+// wordCount returns the number of 32-byte words needed to hold the data.
+func wordCount(data []byte) uint64 {
+	return (uint64(len(data)) + 31) / 32
+}
+
+// getData returns size bytes of data starting at the given offset. Bytes
+// beyond the end of the data are filled with zeros.
+func getData(data []byte, offset, size uint64) []byte {
+	res := make([]byte, size)
+	if offset < uint64(len(data)) {
+		copy(res, data[offset:])
+	}
+	return res
+}
+
+// leftPad returns the data left-padded with zeros to the given size.
+func leftPad(data []byte, size int) []byte {
+	if len(data) >= size {
+		return data
+	}
+	res := make([]byte, size)
+	copy(res[size-len(data):], data)
+	return res
+}
+
+func allZero(data []byte) bool {
+	for _, b := range data {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package precompile
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+// kzgPointEvaluation verifies a KZG proof claiming that a blob evaluates to a
+// given value at a given point, as defined by EIP-4844 (address 0x0a).
+type kzgPointEvaluation struct{}
+
+const (
+	kzgPointEvaluationGas         = 50000
+	kzgPointEvaluationInputLength = 192
+	kzgVersionedHashVersion       = 0x01
+)
+
+var (
+	errKzgInvalidInputLength = errors.New("invalid input length")
+	errKzgMismatchedVersion  = errors.New("mismatched versioned hash")
+	errKzgInvalidProof       = errors.New("invalid kzg proof")
+)
+
+// kzgPointEvaluationResult is the output of a successful evaluation: the
+// number of field elements per blob and the modulus of the BLS12-381 scalar
+// field, each encoded as a 32-byte word.
+var kzgPointEvaluationResult = tosca.Data{
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10, 0x00,
+	0x73, 0xed, 0xa7, 0x53, 0x29, 0x9d, 0x7d, 0x48, 0x33, 0x39, 0xd8, 0x08, 0x09, 0xa1, 0xd8, 0x05,
+	0x53, 0xbd, 0xa4, 0x02, 0xff, 0xfe, 0x5b, 0xfe, 0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x01,
+}
+
+func (kzgPointEvaluation) RequiredGas(tosca.Data) uint64 {
+	return kzgPointEvaluationGas
+}
+
+func (kzgPointEvaluation) Run(input tosca.Data) (tosca.Data, error) {
+	if len(input) != kzgPointEvaluationInputLength {
+		return nil, errKzgInvalidInputLength
+	}
+	var (
+		point      kzg4844.Point
+		claim      kzg4844.Claim
+		commitment kzg4844.Commitment
+		proof      kzg4844.Proof
+	)
+	copy(point[:], input[32:64])
+	copy(claim[:], input[64:96])
+	copy(commitment[:], input[96:144])
+	copy(proof[:], input[144:192])
+
+	versionedHash := sha256.Sum256(commitment[:])
+	versionedHash[0] = kzgVersionedHashVersion
+	if tosca.Hash(versionedHash) != tosca.Hash(input[:32]) {
+		return nil, errKzgMismatchedVersion
+	}
+	if err := kzg4844.VerifyProof(commitment, point, claim, proof); err != nil {
+		return nil, fmt.Errorf("%w: %v", errKzgInvalidProof, err)
+	}
+	return append(tosca.Data{}, kzgPointEvaluationResult...), nil
+}
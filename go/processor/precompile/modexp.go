@@ -0,0 +1,198 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package precompile
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	big "github.com/ethereum/go-bigmodexpfix/src/math/big"
+	"github.com/holiman/uint256"
+)
+
+// modExp computes arbitrary precision modular exponentiations as defined by
+// EIP-198 (address 0x05). Its pricing was changed by EIP-2565 (Berlin) and
+// EIP-7883 (Osaka); EIP-7823 (Osaka) limits the size of its operands.
+type modExp struct {
+	pricing        modExpPricing
+	limitInputSize bool
+}
+
+type modExpPricing int
+
+const (
+	modExpPricingByzantium modExpPricing = iota // EIP-198
+	modExpPricingBerlin                         // EIP-2565
+	modExpPricingOsaka                          // EIP-7883
+)
+
+// modExpMaxInputSize is the maximum size of the operands in bytes (EIP-7823).
+const modExpMaxInputSize = 1024
+
+var errModExpInputTooLarge = errors.New("modexp operand exceeds 1024 bytes")
+
+func (c modExp) RequiredGas(input tosca.Data) uint64 {
+	baseLen := toUint64Saturated(getData(input, 0, 32))
+	expLen := toUint64Saturated(getData(input, 32, 32))
+	modLen := toUint64Saturated(getData(input, 64, 32))
+
+	// Only the leading 32 bytes of the exponent affect the costs.
+	var expHead uint256.Int
+	if data := input[min(len(input), 96):]; uint64(len(data)) > baseLen {
+		expHead.SetBytes(getData(data, baseLen, min(expLen, 32)))
+	}
+
+	switch c.pricing {
+	case modExpPricingByzantium:
+		return modExpGasByzantium(max(baseLen, modLen), expLen, &expHead)
+	case modExpPricingBerlin:
+		return modExpGasBerlin(max(baseLen, modLen), expLen, &expHead)
+	default:
+		return modExpGasOsaka(max(baseLen, modLen), expLen, &expHead)
+	}
+}
+
+func (c modExp) Run(input tosca.Data) (tosca.Data, error) {
+	baseLenWord := new(uint256.Int).SetBytes(getData(input, 0, 32))
+	expLenWord := new(uint256.Int).SetBytes(getData(input, 32, 32))
+	modLenWord := new(uint256.Int).SetBytes(getData(input, 64, 32))
+	baseLen := baseLenWord.Uint64()
+	expLen := expLenWord.Uint64()
+	modLen := modLenWord.Uint64()
+	data := input[min(len(input), 96):]
+
+	if c.limitInputSize {
+		for _, length := range []*uint256.Int{baseLenWord, expLenWord, modLenWord} {
+			if !length.IsUint64() || length.Uint64() > modExpMaxInputSize {
+				return nil, errModExpInputTooLarge
+			}
+		}
+	}
+	if baseLen == 0 && modLen == 0 {
+		return tosca.Data{}, nil
+	}
+
+	base := new(big.Int).SetBytes(getData(data, 0, baseLen))
+	exp := new(big.Int).SetBytes(getData(data, baseLen, expLen))
+	mod := new(big.Int).SetBytes(getData(data, baseLen+expLen, modLen))
+
+	var result []byte
+	switch {
+	case mod.BitLen() == 0:
+		// The result of a modulo 0 is defined to be zero.
+	case base.BitLen() == 1:
+		// A base of 1 does not need to be exponentiated.
+		result = base.Mod(base, mod).Bytes()
+	default:
+		result = base.Exp(base, exp, mod).Bytes()
+	}
+	return leftPad(result, int(modLen)), nil
+}
+
+func modExpGasByzantium(maxLen, expLen uint64, expHead *uint256.Int) uint64 {
+	var complexity uint64
+	switch {
+	case maxLen <= 64:
+		complexity = maxLen * maxLen
+	case maxLen <= 1024:
+		complexity = maxLen*maxLen/4 + 96*maxLen - 3072
+	default:
+		hi, square := bits.Mul64(maxLen, maxLen)
+		if hi != 0 {
+			return math.MaxUint64
+		}
+		sum, carry := bits.Add64(square/16, 480*maxLen-199680, 0)
+		if carry != 0 {
+			return math.MaxUint64
+		}
+		complexity = sum
+	}
+	iterations := modExpIterationCount(expLen, expHead, 8)
+	hi, gas := bits.Mul64(complexity, iterations)
+	if hi != 0 {
+		return math.MaxUint64
+	}
+	return gas / 20
+}
+
+func modExpGasBerlin(maxLen, expLen uint64, expHead *uint256.Int) uint64 {
+	complexity := modExpWordsSquared(maxLen)
+	if complexity == math.MaxUint64 {
+		return math.MaxUint64
+	}
+	iterations := modExpIterationCount(expLen, expHead, 8)
+	hi, gas := bits.Mul64(complexity, iterations)
+	if hi != 0 {
+		return math.MaxUint64
+	}
+	return max(gas/3, 200)
+}
+
+func modExpGasOsaka(maxLen, expLen uint64, expHead *uint256.Int) uint64 {
+	complexity := uint64(16)
+	if maxLen > 32 {
+		hi, doubled := bits.Mul64(modExpWordsSquared(maxLen), 2)
+		if hi != 0 {
+			return math.MaxUint64
+		}
+		complexity = doubled
+	}
+	iterations := modExpIterationCount(expLen, expHead, 16)
+	hi, gas := bits.Mul64(complexity, iterations)
+	if hi != 0 {
+		return math.MaxUint64
+	}
+	return max(gas, 500)
+}
+
+// modExpWordsSquared computes ceil(length/8)^2, saturating at math.MaxUint64.
+func modExpWordsSquared(length uint64) uint64 {
+	words := length/8 + min(length%8, 1)
+	hi, square := bits.Mul64(words, words)
+	if hi != 0 {
+		return math.MaxUint64
+	}
+	return square
+}
+
+// modExpIterationCount computes the adjusted exponent length used for
+// pricing, which is at least 1. Each 32-byte word of the exponent beyond the
+// first accounts for the given number of iterations.
+func modExpIterationCount(expLen uint64, expHead *uint256.Int, wordIterations uint64) uint64 {
+	var count uint64
+	if expLen > 32 {
+		hi, lo := bits.Mul64(expLen-32, wordIterations)
+		if hi != 0 {
+			return math.MaxUint64
+		}
+		count = lo
+	}
+	if bitLen := expHead.BitLen(); bitLen > 0 {
+		sum, carry := bits.Add64(count, uint64(bitLen-1), 0)
+		if carry != 0 {
+			return math.MaxUint64
+		}
+		count = sum
+	}
+	return max(count, 1)
+}
+
+// toUint64Saturated interprets the data as a big-endian number and returns it
+// as a uint64, or math.MaxUint64 if it does not fit.
+func toUint64Saturated(data []byte) uint64 {
+	value := new(uint256.Int).SetBytes(data)
+	if !value.IsUint64() {
+		return math.MaxUint64
+	}
+	return value.Uint64()
+}
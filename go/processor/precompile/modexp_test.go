@@ -0,0 +1,148 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package precompile
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModExp_ComputesModularExponentiation(t *testing.T) {
+	// 3^0xff mod 7 = 6
+	input := newModExpInput(1, 1, 1, []byte{3}, []byte{0xff}, []byte{7})
+	for _, pricing := range []modExpPricing{modExpPricingByzantium, modExpPricingBerlin, modExpPricingOsaka} {
+		output, err := modExp{pricing: pricing}.Run(input)
+		require.NoError(t, err)
+		require.Equal(t, tosca.Data{6}, output)
+	}
+}
+
+func TestModExp_ResultIsPaddedToModulusLength(t *testing.T) {
+	input := newModExpInput(1, 1, 4, []byte{2}, []byte{3}, []byte{0, 0, 0, 100})
+	output, err := modExp{}.Run(input)
+	require.NoError(t, err)
+	require.Equal(t, tosca.Data{0, 0, 0, 8}, output)
+}
+
+func TestModExp_ModuloZeroProducesZero(t *testing.T) {
+	input := newModExpInput(1, 1, 2, []byte{2}, []byte{3}, []byte{0, 0})
+	output, err := modExp{}.Run(input)
+	require.NoError(t, err)
+	require.Equal(t, tosca.Data{0, 0}, output)
+}
+
+func TestModExp_OperandSizeIsLimitedSinceOsaka(t *testing.T) {
+	tests := map[string]struct {
+		baseLen, expLen, modLen uint64
+		fails                   bool
+	}{
+		"within limit":      {1024, 1024, 1024, false},
+		"base too large":    {1025, 1, 1, true},
+		"exponent too big":  {1, 1025, 1, true},
+		"modulus too large": {1, 1, 1025, true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			input := newModExpInput(test.baseLen, test.expLen, test.modLen, nil, nil, nil)
+
+			_, err := modExp{pricing: modExpPricingOsaka, limitInputSize: true}.Run(input)
+			if test.fails {
+				require.ErrorIs(t, err, errModExpInputTooLarge)
+			} else {
+				require.NoError(t, err)
+			}
+
+			_, err = modExp{pricing: modExpPricingBerlin}.Run(input)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestModExp_LengthsExceedingUint64AreRejectedSinceOsaka(t *testing.T) {
+	input := make(tosca.Data, 96)
+	input[0] = 1 // < base length of 2^248
+	_, err := modExp{pricing: modExpPricingOsaka, limitInputSize: true}.Run(input)
+	require.ErrorIs(t, err, errModExpInputTooLarge)
+}
+
+func TestModExp_GasDependsOnPricing(t *testing.T) {
+	tests := map[string]struct {
+		input     tosca.Data
+		byzantium uint64
+		berlin    uint64
+		osaka     uint64
+	}{
+		"empty": {
+			input:     nil,
+			byzantium: 0,
+			berlin:    200,
+			osaka:     500,
+		},
+		"small operands": {
+			// max length 1, iterations 7
+			input:     newModExpInput(1, 1, 1, []byte{3}, []byte{0xff}, []byte{7}),
+			byzantium: 0,
+			berlin:    200,
+			osaka:     500,
+		},
+		"32 byte operands": {
+			// max length 32, exponent 2^255: iterations 255
+			input:     newModExpInput(32, 32, 32, make([]byte, 32), append([]byte{0x80}, make([]byte, 31)...), make([]byte, 32)),
+			byzantium: 1024 * 255 / 20,
+			berlin:    16 * 255 / 3,
+			osaka:     16 * 255,
+		},
+		"large operands": {
+			// max length 256, exponent 2^255 of 64 bytes: iterations 32*8+255 or 32*16+255
+			input:     newModExpInput(256, 64, 256, make([]byte, 256), append([]byte{0x80}, make([]byte, 63)...), make([]byte, 256)),
+			byzantium: (256*256/4 + 96*256 - 3072) * (32*8 + 255) / 20,
+			berlin:    32 * 32 * (32*8 + 255) / 3,
+			osaka:     2 * 32 * 32 * (32*16 + 255),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, test.byzantium, modExp{pricing: modExpPricingByzantium}.RequiredGas(test.input))
+			require.Equal(t, test.berlin, modExp{pricing: modExpPricingBerlin}.RequiredGas(test.input))
+			require.Equal(t, test.osaka, modExp{pricing: modExpPricingOsaka}.RequiredGas(test.input))
+		})
+	}
+}
+
+func TestModExp_GasIsUnaffordableOnOverflow(t *testing.T) {
+	huge := uint64(math.MaxUint64)
+	inputs := []tosca.Data{
+		newModExpInput(huge, 1, 1, nil, nil, nil),
+		newModExpInput(1, huge, 1, nil, nil, nil),
+		newModExpInput(1, 1, huge, nil, nil, nil),
+		newModExpInput(1<<32, 1<<32, 1<<32, nil, nil, nil),
+	}
+	for _, input := range inputs {
+		for _, pricing := range []modExpPricing{modExpPricingByzantium, modExpPricingBerlin, modExpPricingOsaka} {
+			// Prices are divided after saturating the product of complexity and
+			// iterations, thus they may be below math.MaxUint64.
+			require.Greater(t, modExp{pricing: pricing}.RequiredGas(input), uint64(math.MaxUint64/21))
+		}
+	}
+}
+
+func newModExpInput(baseLen, expLen, modLen uint64, base, exp, mod []byte) tosca.Data {
+	return concat(
+		new(big.Int).SetUint64(baseLen).FillBytes(make([]byte, 32)),
+		new(big.Int).SetUint64(expLen).FillBytes(make([]byte, 32)),
+		new(big.Int).SetUint64(modLen).FillBytes(make([]byte, 32)),
+		base, exp, mod,
+	)
+}
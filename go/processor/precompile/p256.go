@@ -0,0 +1,61 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package precompile
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"math/big"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+)
+
+// p256Verify verifies ECDSA signatures on the secp256r1 curve as defined by
+// EIP-7951 (address 0x0100).
+type p256Verify struct{}
+
+const (
+	p256VerifyGas         = 6900
+	p256VerifyInputLength = 160
+)
+
+func (p256Verify) RequiredGas(tosca.Data) uint64 {
+	return p256VerifyGas
+}
+
+func (p256Verify) Run(input tosca.Data) (tosca.Data, error) {
+	// Malformed inputs and invalid signatures do not fail the call but
+	// produce an empty output.
+	if len(input) != p256VerifyInputLength {
+		return nil, nil
+	}
+	hash := input[0:32]
+	r := new(big.Int).SetBytes(input[32:64])
+	s := new(big.Int).SetBytes(input[64:96])
+	x := new(big.Int).SetBytes(input[96:128])
+	y := new(big.Int).SetBytes(input[128:160])
+
+	curve := elliptic.P256()
+	params := curve.Params()
+	if r.Sign() == 0 || r.Cmp(params.N) >= 0 || s.Sign() == 0 || s.Cmp(params.N) >= 0 {
+		return nil, nil
+	}
+	// The point at infinity and coordinates outside of the field are rejected
+	// by the curve check.
+	if x.Cmp(params.P) >= 0 || y.Cmp(params.P) >= 0 || !curve.IsOnCurve(x, y) {
+		return nil, nil
+	}
+	key := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+	if !ecdsa.Verify(key, hash, r, s) {
+		return nil, nil
+	}
+	return encodeBool(true), nil
+}
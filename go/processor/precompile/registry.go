@@ -0,0 +1,185 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+// Package precompile provides native implementations of the precompiled
+// contracts defined by Ethereum and a registry binding them to addresses for
+// ranges of revisions. Chains may extend a registry by their own precompiled
+// contracts.
+package precompile
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+)
+
+// Contract is a precompiled contract implemented natively.
+type Contract interface {
+	// RequiredGas computes the gas required for running the contract on the
+	// given input. If the costs exceed the range of a uint64, math.MaxUint64
+	// is returned.
+	RequiredGas(input tosca.Data) uint64
+
+	// Run executes the contract on the given input. An error is returned if
+	// the execution failed, in which case all gas provided to the contract is
+	// consumed.
+	Run(input tosca.Data) (tosca.Data, error)
+}
+
+// RevisionRange is an inclusive range of revisions.
+type RevisionRange struct {
+	First tosca.Revision
+	Last  tosca.Revision
+}
+
+// Since returns the range of all revisions starting with the given revision,
+// including all revisions that will be introduced in the future.
+func Since(first tosca.Revision) RevisionRange {
+	return RevisionRange{First: first, Last: tosca.Revision(math.MaxInt)}
+}
+
+// Between returns the range of revisions from first to last, both included.
+func Between(first, last tosca.Revision) RevisionRange {
+	return RevisionRange{First: first, Last: last}
+}
+
+// Contains reports whether the given revision is part of the range.
+func (r RevisionRange) Contains(revision tosca.Revision) bool {
+	return r.First <= revision && revision <= r.Last
+}
+
+func (r RevisionRange) overlaps(other RevisionRange) bool {
+	return r.First <= other.Last && other.First <= r.Last
+}
+
+// Registry binds precompiled contracts to addresses for ranges of revisions.
+// The zero value is an empty registry ready to use. A registry must not be
+// modified while being used concurrently.
+type Registry struct {
+	contracts map[tosca.Address][]registration
+}
+
+type registration struct {
+	revisions RevisionRange
+	contract  Contract
+}
+
+// NewRegistry creates a new, empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// NewStandardRegistry creates a new registry containing the precompiled
+// contracts defined by Ethereum for all revisions supported by Tosca. The
+// resulting registry may be extended by chain-specific contracts.
+func NewStandardRegistry() *Registry {
+	registry := NewRegistry()
+	for _, entry := range standardContracts {
+		if err := registry.Register(entry.address, entry.revisions, entry.contract); err != nil {
+			panic(fmt.Sprintf("invalid standard precompiled contract: %v", err))
+		}
+	}
+	return registry
+}
+
+// Register binds the given contract to the given address for the given range
+// of revisions. An error is returned if the range is empty or overlaps with
+// the range of a contract already registered for the same address.
+func (r *Registry) Register(address tosca.Address, revisions RevisionRange, contract Contract) error {
+	if contract == nil {
+		return fmt.Errorf("contract for address %v must not be nil", address)
+	}
+	if revisions.First > revisions.Last {
+		return fmt.Errorf("empty revision range [%v, %v] for address %v", revisions.First, revisions.Last, address)
+	}
+	for _, existing := range r.contracts[address] {
+		if existing.revisions.overlaps(revisions) {
+			return fmt.Errorf(
+				"revision range [%v, %v] for address %v overlaps with registered range [%v, %v]",
+				revisions.First, revisions.Last, address, existing.revisions.First, existing.revisions.Last,
+			)
+		}
+	}
+	if r.contracts == nil {
+		r.contracts = map[tosca.Address][]registration{}
+	}
+	r.contracts[address] = append(r.contracts[address], registration{
+		revisions: revisions,
+		contract:  contract,
+	})
+	return nil
+}
+
+// Get returns the contract bound to the given address in the given revision.
+func (r *Registry) Get(address tosca.Address, revision tosca.Revision) (Contract, bool) {
+	for _, entry := range r.contracts[address] {
+		if entry.revisions.Contains(revision) {
+			return entry.contract, true
+		}
+	}
+	return nil, false
+}
+
+// Addresses returns the sorted list of addresses of all contracts available
+// in the given revision.
+func (r *Registry) Addresses(revision tosca.Revision) []tosca.Address {
+	res := []tosca.Address{}
+	for address := range r.contracts {
+		if _, found := r.Get(address, revision); found {
+			res = append(res, address)
+		}
+	}
+	slices.SortFunc(res, func(a, b tosca.Address) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	return res
+}
+
+type standardContract struct {
+	address   tosca.Address
+	revisions RevisionRange
+	contract  Contract
+}
+
+// standardContracts lists the precompiled contracts defined by Ethereum. Tosca
+// does not support revisions before Istanbul, thus the pricing of older
+// revisions is not covered.
+var standardContracts = []standardContract{
+	{addressOf(0x01), Since(tosca.R07_Istanbul), ecRecover{}},
+	{addressOf(0x02), Since(tosca.R07_Istanbul), sha256Hash{}},
+	{addressOf(0x03), Since(tosca.R07_Istanbul), ripemd160Hash{}},
+	{addressOf(0x04), Since(tosca.R07_Istanbul), identity{}},
+	{addressOf(0x05), Between(tosca.R07_Istanbul, tosca.R07_Istanbul), modExp{pricing: modExpPricingByzantium}},
+	{addressOf(0x05), Between(tosca.R09_Berlin, tosca.R14_Prague), modExp{pricing: modExpPricingBerlin}},
+	{addressOf(0x05), Since(tosca.R15_Osaka), modExp{pricing: modExpPricingOsaka, limitInputSize: true}},
+	{addressOf(0x06), Since(tosca.R07_Istanbul), bn254Add{}},
+	{addressOf(0x07), Since(tosca.R07_Istanbul), bn254ScalarMul{}},
+	{addressOf(0x08), Since(tosca.R07_Istanbul), bn254Pairing{}},
+	{addressOf(0x09), Since(tosca.R07_Istanbul), blake2F{}},
+	{addressOf(0x0a), Since(tosca.R13_Cancun), kzgPointEvaluation{}},
+	{addressOf(0x0b), Since(tosca.R14_Prague), bls12381G1Add{}},
+	{addressOf(0x0c), Since(tosca.R14_Prague), bls12381G1MultiExp{}},
+	{addressOf(0x0d), Since(tosca.R14_Prague), bls12381G2Add{}},
+	{addressOf(0x0e), Since(tosca.R14_Prague), bls12381G2MultiExp{}},
+	{addressOf(0x0f), Since(tosca.R14_Prague), bls12381Pairing{}},
+	{addressOf(0x10), Since(tosca.R14_Prague), bls12381MapG1{}},
+	{addressOf(0x11), Since(tosca.R14_Prague), bls12381MapG2{}},
+	{addressOf(0x01, 0x00), Since(tosca.R15_Osaka), p256Verify{}},
+}
+
+// addressOf returns the address with the given bytes as its suffix.
+func addressOf(suffix ...byte) tosca.Address {
+	res := tosca.Address{}
+	copy(res[len(res)-len(suffix):], suffix)
+	return res
+}
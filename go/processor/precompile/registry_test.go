@@ -0,0 +1,152 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package precompile
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevisionRange_ContainsRevisionsBetweenBounds(t *testing.T) {
+	r := Between(tosca.R09_Berlin, tosca.R12_Shanghai)
+	for _, revision := range append(tosca.GetAllKnownRevisions(), tosca.R98_Experimental) {
+		want := tosca.R09_Berlin <= revision && revision <= tosca.R12_Shanghai
+		require.Equal(t, want, r.Contains(revision), "revision %v", revision)
+	}
+}
+
+func TestRevisionRange_SinceContainsFutureRevisions(t *testing.T) {
+	r := Since(tosca.R13_Cancun)
+	require.False(t, r.Contains(tosca.R12_Shanghai))
+	require.True(t, r.Contains(tosca.R13_Cancun))
+	require.True(t, r.Contains(tosca.R15_Osaka))
+	require.True(t, r.Contains(tosca.R98_Experimental))
+}
+
+func TestRegistry_ZeroValueIsEmptyRegistry(t *testing.T) {
+	registry := Registry{}
+	_, found := registry.Get(tosca.Address{1}, tosca.R13_Cancun)
+	require.False(t, found)
+	require.Empty(t, registry.Addresses(tosca.R13_Cancun))
+	require.NoError(t, registry.Register(tosca.Address{1}, Since(tosca.R07_Istanbul), identity{}))
+}
+
+func TestRegistry_ContractsAreBoundToTheirRevisions(t *testing.T) {
+	registry := NewRegistry()
+	address := tosca.Address{1}
+	require.NoError(t, registry.Register(address, Between(tosca.R07_Istanbul, tosca.R10_London), identity{}))
+	require.NoError(t, registry.Register(address, Since(tosca.R11_Paris), sha256Hash{}))
+
+	for _, revision := range tosca.GetAllKnownRevisions() {
+		contract, found := registry.Get(address, revision)
+		require.True(t, found)
+		if revision <= tosca.R10_London {
+			require.Equal(t, identity{}, contract)
+		} else {
+			require.Equal(t, sha256Hash{}, contract)
+		}
+	}
+
+	_, found := registry.Get(tosca.Address{2}, tosca.R13_Cancun)
+	require.False(t, found)
+}
+
+func TestRegistry_InvalidRegistrationsAreRejected(t *testing.T) {
+	address := tosca.Address{1}
+	tests := map[string]struct {
+		revisions RevisionRange
+		contract  Contract
+		want      string
+	}{
+		"nil contract": {
+			revisions: Since(tosca.R07_Istanbul),
+			want:      "must not be nil",
+		},
+		"empty range": {
+			revisions: Between(tosca.R13_Cancun, tosca.R12_Shanghai),
+			contract:  identity{},
+			want:      "empty revision range",
+		},
+		"overlapping range": {
+			revisions: Between(tosca.R12_Shanghai, tosca.R13_Cancun),
+			contract:  identity{},
+			want:      "overlaps",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			registry := NewRegistry()
+			require.NoError(t, registry.Register(address, Between(tosca.R07_Istanbul, tosca.R12_Shanghai), identity{}))
+			err := registry.Register(address, test.revisions, test.contract)
+			require.ErrorContains(t, err, test.want)
+		})
+	}
+}
+
+func TestRegistry_AddressesAreSortedAndFilteredByRevision(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(tosca.Address{3}, Since(tosca.R07_Istanbul), identity{}))
+	require.NoError(t, registry.Register(tosca.Address{1}, Since(tosca.R07_Istanbul), identity{}))
+	require.NoError(t, registry.Register(tosca.Address{2}, Since(tosca.R13_Cancun), identity{}))
+
+	require.Equal(t, []tosca.Address{{1}, {3}}, registry.Addresses(tosca.R12_Shanghai))
+	require.Equal(t, []tosca.Address{{1}, {2}, {3}}, registry.Addresses(tosca.R13_Cancun))
+}
+
+func TestStandardRegistry_ContainsContractsOfEachRevision(t *testing.T) {
+	tests := map[tosca.Revision]int{
+		tosca.R07_Istanbul:     9,
+		tosca.R09_Berlin:       9,
+		tosca.R10_London:       9,
+		tosca.R11_Paris:        9,
+		tosca.R12_Shanghai:     9,
+		tosca.R13_Cancun:       10,
+		tosca.R14_Prague:       17,
+		tosca.R15_Osaka:        18,
+		tosca.R98_Experimental: 18,
+	}
+	registry := NewStandardRegistry()
+	for revision, want := range tests {
+		addresses := registry.Addresses(revision)
+		require.Len(t, addresses, want, "revision %v", revision)
+		for i, address := range addresses[:min(len(addresses), 17)] {
+			require.Equal(t, addressOf(byte(i+1)), address)
+		}
+	}
+	require.True(t, slices.Contains(registry.Addresses(tosca.R15_Osaka), addressOf(0x01, 0x00)))
+}
+
+func TestStandardRegistry_ModExpPricingDependsOnRevision(t *testing.T) {
+	registry := NewStandardRegistry()
+	tests := map[tosca.Revision]modExp{
+		tosca.R07_Istanbul: {pricing: modExpPricingByzantium},
+		tosca.R09_Berlin:   {pricing: modExpPricingBerlin},
+		tosca.R14_Prague:   {pricing: modExpPricingBerlin},
+		tosca.R15_Osaka:    {pricing: modExpPricingOsaka, limitInputSize: true},
+	}
+	for revision, want := range tests {
+		contract, found := registry.Get(addressOf(0x05), revision)
+		require.True(t, found)
+		require.Equal(t, want, contract)
+	}
+}
+
+func TestStandardRegistry_InstancesAreIndependent(t *testing.T) {
+	a := NewStandardRegistry()
+	b := NewStandardRegistry()
+	require.NoError(t, a.Register(tosca.Address{0x42}, Since(tosca.R07_Istanbul), identity{}))
+	_, found := b.Get(tosca.Address{0x42}, tosca.R07_Istanbul)
+	require.False(t, found)
+}
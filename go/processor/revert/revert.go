@@ -0,0 +1,173 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package revert
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Kind classifies the encoding of the output of a reverted execution.
+type Kind int
+
+const (
+	// Unknown is used for outputs not matching any known error encoding.
+	Unknown Kind = iota
+	// Error is used for Error(string) outputs produced by require and revert.
+	Error
+	// Panic is used for Panic(uint256) outputs produced by failed assertions
+	// and runtime errors of Solidity contracts.
+	Panic
+	// Custom is used for custom errors defined in a contract ABI.
+	Custom
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Unknown:
+		return "Unknown"
+	case Error:
+		return "Error"
+	case Panic:
+		return "Panic"
+	case Custom:
+		return "Custom"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Reason is the structured representation of the output of a reverted
+// execution.
+type Reason struct {
+	Kind      Kind
+	Selector  [4]byte    // the first four bytes of the output, zero if the output is shorter
+	Name      string     // the name of the error, e.g. Error, Panic, or the name of a custom error
+	Message   string     // the message of an Error(string)
+	PanicCode *big.Int   // the code of a Panic(uint256)
+	Arguments []any      // the decoded arguments of a custom error
+	Output    tosca.Data // the raw output
+}
+
+// String produces a human readable description of the revert reason.
+func (r Reason) String() string {
+	switch r.Kind {
+	case Error:
+		return r.Message
+	case Panic:
+		if r.PanicCode.IsUint64() {
+			if description, found := panicReasons[r.PanicCode.Uint64()]; found {
+				return fmt.Sprintf("panic: %s (%#x)", description, r.PanicCode)
+			}
+		}
+		return fmt.Sprintf("panic: unknown panic code %#x", r.PanicCode)
+	case Custom:
+		arguments := make([]string, 0, len(r.Arguments))
+		for _, argument := range r.Arguments {
+			arguments = append(arguments, fmt.Sprintf("%v", argument))
+		}
+		return fmt.Sprintf("%s(%s)", r.Name, strings.Join(arguments, ", "))
+	}
+	return fmt.Sprintf("0x%x", r.Output)
+}
+
+// Decode interprets the given output of a reverted execution. Error(string)
+// and Panic(uint256) outputs are always recognized, custom errors only if
+// they are defined in one of the given ABIs. Outputs that can not be decoded
+// are reported as Unknown.
+func Decode(output tosca.Data, abis ...*abi.ABI) Reason {
+	res := Reason{Kind: Unknown, Output: output}
+	if len(output) < 4 {
+		return res
+	}
+	copy(res.Selector[:], output)
+
+	switch {
+	case bytes.Equal(res.Selector[:], errorSelector):
+		if values, err := errorArguments.Unpack(output[4:]); err == nil {
+			res.Kind = Error
+			res.Name = "Error"
+			res.Message = values[0].(string)
+		}
+		return res
+	case bytes.Equal(res.Selector[:], panicSelector):
+		if values, err := panicArguments.Unpack(output[4:]); err == nil {
+			res.Kind = Panic
+			res.Name = "Panic"
+			res.PanicCode = values[0].(*big.Int)
+		}
+		return res
+	}
+
+	for _, contract := range abis {
+		if contract == nil {
+			continue
+		}
+		definition, err := contract.ErrorByID(res.Selector)
+		if err != nil {
+			continue
+		}
+		values, err := definition.Inputs.Unpack(output[4:])
+		if err != nil {
+			continue
+		}
+		res.Kind = Custom
+		res.Name = definition.Name
+		res.Arguments = values
+		return res
+	}
+	return res
+}
+
+// DecodeReceipt decodes the output of the given receipt if the execution
+// failed. For successful executions, nil is returned.
+func DecodeReceipt(receipt tosca.Receipt, abis ...*abi.ABI) *Reason {
+	if receipt.Success {
+		return nil
+	}
+	res := Decode(receipt.Output, abis...)
+	return &res
+}
+
+var (
+	errorSelector  = crypto.Keccak256([]byte("Error(string)"))[:4]
+	panicSelector  = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+	errorArguments = newArguments("string")
+	panicArguments = newArguments("uint256")
+)
+
+// panicReasons describes the panic codes used by the Solidity compiler, see
+// https://docs.soliditylang.org/en/latest/control-structures.html#panic-via-assert-and-error-via-require
+var panicReasons = map[uint64]string{
+	0x00: "generic panic",
+	0x01: "assert(false)",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "enum overflow",
+	0x22: "invalid encoded storage byte array accessed",
+	0x31: "out-of-bounds array access; popping on an empty array",
+	0x32: "out-of-bounds access of an array or bytesN",
+	0x41: "out of memory",
+	0x51: "uninitialized function",
+}
+
+func newArguments(typeName string) abi.Arguments {
+	argumentType, err := abi.NewType(typeName, "", nil)
+	if err != nil {
+		panic(fmt.Errorf("failed to create ABI type %s: %w", typeName, err))
+	}
+	return abi.Arguments{{Type: argumentType}}
+}
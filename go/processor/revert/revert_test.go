@@ -0,0 +1,137 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package revert
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/stretchr/testify/require"
+)
+
+const testABI = `[{
+	"type": "error",
+	"name": "InsufficientBalance",
+	"inputs": [
+		{"name": "available", "type": "uint256"},
+		{"name": "required", "type": "uint256"}
+	]
+}]`
+
+func newTestABI(t *testing.T) *abi.ABI {
+	t.Helper()
+	res, err := abi.JSON(strings.NewReader(testABI))
+	require.NoError(t, err)
+	return &res
+}
+
+func encode(t *testing.T, selector []byte, types []string, values ...any) tosca.Data {
+	t.Helper()
+	arguments := abi.Arguments{}
+	for _, name := range types {
+		argumentType, err := abi.NewType(name, "", nil)
+		require.NoError(t, err)
+		arguments = append(arguments, abi.Argument{Type: argumentType})
+	}
+	data, err := arguments.Pack(values...)
+	require.NoError(t, err)
+	return append(tosca.Data(selector), data...)
+}
+
+func TestDecode_RecognizesErrorMessages(t *testing.T) {
+	output := encode(t, errorSelector, []string{"string"}, "insufficient funds")
+	reason := Decode(output)
+	require.Equal(t, Error, reason.Kind)
+	require.Equal(t, "Error", reason.Name)
+	require.Equal(t, "insufficient funds", reason.Message)
+	require.Equal(t, [4]byte{0x08, 0xc3, 0x79, 0xa0}, reason.Selector)
+	require.Equal(t, "insufficient funds", reason.String())
+}
+
+func TestDecode_RecognizesPanics(t *testing.T) {
+	tests := map[string]struct {
+		code *big.Int
+		want string
+	}{
+		"overflow":     {code: big.NewInt(0x11), want: "panic: arithmetic underflow or overflow (0x11)"},
+		"unknown code": {code: big.NewInt(0x99), want: "panic: unknown panic code 0x99"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			reason := Decode(encode(t, panicSelector, []string{"uint256"}, test.code))
+			require.Equal(t, Panic, reason.Kind)
+			require.Equal(t, "Panic", reason.Name)
+			require.Equal(t, 0, test.code.Cmp(reason.PanicCode))
+			require.Equal(t, test.want, reason.String())
+		})
+	}
+}
+
+func TestDecode_RecognizesCustomErrorsDefinedInABI(t *testing.T) {
+	contract := newTestABI(t)
+	definition := contract.Errors["InsufficientBalance"]
+	output := encode(t, definition.ID[:4], []string{"uint256", "uint256"}, big.NewInt(1), big.NewInt(2))
+
+	reason := Decode(output, nil, contract)
+	require.Equal(t, Custom, reason.Kind)
+	require.Equal(t, "InsufficientBalance", reason.Name)
+	require.Equal(t, []any{big.NewInt(1), big.NewInt(2)}, reason.Arguments)
+	require.Equal(t, "InsufficientBalance(1, 2)", reason.String())
+
+	reason = Decode(output)
+	require.Equal(t, Unknown, reason.Kind)
+}
+
+func TestDecode_UndecodableOutputsAreUnknown(t *testing.T) {
+	custom := newTestABI(t).Errors["InsufficientBalance"].ID
+	tests := map[string]tosca.Data{
+		"empty":              nil,
+		"too short":          {1, 2, 3},
+		"unknown selector":   {1, 2, 3, 4, 5},
+		"truncated message":  append(tosca.Data(errorSelector), 1, 2),
+		"truncated panic":    append(tosca.Data(panicSelector), 1, 2),
+		"mismatching fields": encode(t, custom[:4], []string{"uint8"}, uint8(1)),
+	}
+
+	for name, output := range tests {
+		t.Run(name, func(t *testing.T) {
+			reason := Decode(output, newTestABI(t))
+			require.Equal(t, Unknown, reason.Kind)
+			require.Equal(t, output, reason.Output)
+			require.Empty(t, reason.Name)
+		})
+	}
+}
+
+func TestDecode_UnknownOutputIsPrintedInHex(t *testing.T) {
+	require.Equal(t, "0x0102", Decode(tosca.Data{1, 2}).String())
+}
+
+func TestDecodeReceipt_IgnoresSuccessfulExecutions(t *testing.T) {
+	output := encode(t, errorSelector, []string{"string"}, "boom")
+	require.Nil(t, DecodeReceipt(tosca.Receipt{Success: true, Output: output}))
+
+	reason := DecodeReceipt(tosca.Receipt{Output: output})
+	require.NotNil(t, reason)
+	require.Equal(t, "boom", reason.Message)
+}
+
+func TestKind_String(t *testing.T) {
+	require.Equal(t, "Unknown", Unknown.String())
+	require.Equal(t, "Error", Error.String())
+	require.Equal(t, "Panic", Panic.String())
+	require.Equal(t, "Custom", Custom.String())
+	require.Equal(t, "Kind(42)", Kind(42).String())
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package simulation
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// AccessListResult summarizes the outcome of an access list generation.
+type AccessListResult struct {
+	AccessList []tosca.AccessTuple // the generated access list
+	Receipt    tosca.Receipt       // the receipt of the transaction using the generated access list
+	GasSaved   tosca.Gas           // the gas saved by using the generated access list, may be negative
+}
+
+// maxAccessListIterations limits the number of executions performed to find
+// a stable access list. The list may fail to stabilize if it affects the
+// control flow of the transaction, e.g. by checking the remaining gas.
+const maxAccessListIterations = 16
+
+// PrecompileLister is implemented by processors able to list the precompiled
+// contracts they provide, including chain-specific ones.
+type PrecompileLister interface {
+	PrecompiledAddresses(tosca.Revision) []tosca.Address
+}
+
+// GasScheduleProvider is implemented by processors able to report the gas
+// schedule they charge, which is nil for the Ethereum prices.
+type GasScheduleProvider interface {
+	GasSchedule() *tosca.GasSchedule
+}
+
+// GenerateAccessList determines the accounts and storage slots accessed by
+// the given transaction when being run by the given processor on top of the
+// given state. The state is not modified.
+//
+// Starting with an empty access list, the transaction is executed repeatedly
+// with the access list derived by the previous execution until the list no
+// longer changes. Thus, entries of the access list of the given transaction
+// are only retained if they are actually accessed. If the list does not
+// stabilize within maxAccessListIterations executions, an error is returned.
+//
+// Accounts which are warm by default according to EIP-2929, EIP-2930 and
+// EIP-3651 are only included if listing their accessed storage slots saves
+// more gas than listing the account costs. Those are the sender, the
+// recipient or the created contract, the given precompiled contracts, and
+// the coinbase. Whether listing pays off is decided based on the prices of
+// the given gas schedule. The saved gas is computed by comparing the gas used
+// with the generated list to the gas used with the access list of the given
+// transaction.
+func GenerateAccessList(
+	processor tosca.Processor,
+	precompiles []tosca.Address,
+	schedule *tosca.GasSchedule,
+	blockParameters tosca.BlockParameters,
+	transaction tosca.Transaction,
+	state tosca.TransactionContext,
+) (AccessListResult, error) {
+	excluded := map[tosca.Address]bool{
+		transaction.Sender:       true,
+		blockParameters.Coinbase: true,
+	}
+	if transaction.Recipient != nil {
+		excluded[*transaction.Recipient] = true
+	} else {
+		excluded[tosca.Address(crypto.CreateAddress(common.Address(transaction.Sender), transaction.Nonce))] = true
+	}
+	for _, address := range precompiles {
+		excluded[address] = true
+	}
+
+	run := func(accessList []tosca.AccessTuple) (tosca.Receipt, *accessRecorder, error) {
+		recorder := newAccessRecorder(newOverlay(state))
+		transaction.AccessList = accessList
+		receipt, err := processor.Run(blockParameters, transaction, recorder)
+		return receipt, recorder, err
+	}
+
+	original, _, err := run(transaction.AccessList)
+	if err != nil {
+		return AccessListResult{}, err
+	}
+
+	accessList := []tosca.AccessTuple{}
+	for range maxAccessListIterations {
+		receipt, recorder, err := run(accessList)
+		if err != nil {
+			return AccessListResult{}, err
+		}
+		next := recorder.accessList(excluded, func(keys int) bool {
+			return isWorthListing(keys, schedule, blockParameters.Revision)
+		})
+		if equalAccessLists(accessList, next) {
+			return AccessListResult{
+				AccessList: accessList,
+				Receipt:    receipt,
+				GasSaved:   original.GasUsed - receipt.GasUsed,
+			}, nil
+		}
+		accessList = next
+	}
+	return AccessListResult{}, fmt.Errorf("access list did not stabilize within %d executions", maxAccessListIterations)
+}
+
+// accessRecorder is a tosca.TransactionContext wrapper recording all accessed
+// accounts and storage slots. Other than the access list maintained by the
+// wrapped context, the recorded accesses are not affected by reverts.
+type accessRecorder struct {
+	tosca.TransactionContext
+	accounts map[tosca.Address]bool
+	slots    map[tosca.Address]map[tosca.Key]bool
+}
+
+func newAccessRecorder(context tosca.TransactionContext) *accessRecorder {
+	return &accessRecorder{
+		TransactionContext: context,
+		accounts:           map[tosca.Address]bool{},
+		slots:              map[tosca.Address]map[tosca.Key]bool{},
+	}
+}
+
+func (r *accessRecorder) AccessAccount(address tosca.Address) tosca.AccessStatus {
+	r.accounts[address] = true
+	return r.TransactionContext.AccessAccount(address)
+}
+
+func (r *accessRecorder) AccessStorage(address tosca.Address, key tosca.Key) tosca.AccessStatus {
+	if _, found := r.slots[address]; !found {
+		r.slots[address] = map[tosca.Key]bool{}
+	}
+	r.slots[address][key] = true
+	return r.TransactionContext.AccessStorage(address, key)
+}
+
+// accessList returns the recorded accounts and storage slots, excluding the
+// given addresses unless listing their storage slots is worth it, in a
+// deterministic order.
+func (r *accessRecorder) accessList(
+	excluded map[tosca.Address]bool,
+	isWorthListing func(keys int) bool,
+) []tosca.AccessTuple {
+	addresses := map[tosca.Address]bool{}
+	for address := range r.accounts {
+		addresses[address] = true
+	}
+	for address := range r.slots {
+		addresses[address] = true
+	}
+
+	res := []tosca.AccessTuple{}
+	for address := range addresses {
+		keys := []tosca.Key{}
+		for key := range r.slots[address] {
+			keys = append(keys, key)
+		}
+		// Storage slots of excluded accounts are still cold, but listing them
+		// requires listing the account, which only pays off for many slots.
+		if excluded[address] && !isWorthListing(len(keys)) {
+			continue
+		}
+		slices.SortFunc(keys, func(a, b tosca.Key) int { return bytes.Compare(a[:], b[:]) })
+		res = append(res, tosca.AccessTuple{Address: address, Keys: keys})
+	}
+	slices.SortFunc(res, func(a, b tosca.AccessTuple) int { return bytes.Compare(a.Address[:], b.Address[:]) })
+	return res
+}
+
+// isWorthListing determines whether listing the given number of storage slots
+// of an account which is warm by default saves gas under the prices of the
+// given gas schedule (EIP-2929 and EIP-2930).
+func isWorthListing(keys int, schedule *tosca.GasSchedule, revision tosca.Revision) bool {
+	savedPerKey := schedule.GetDynamicGas(tosca.ColdSloadGas, revision) -
+		schedule.GetDynamicGas(tosca.WarmSloadGas, revision) -
+		schedule.GetDynamicGas(tosca.AccessListStorageKeyGas, revision)
+	return tosca.Gas(keys)*savedPerKey > schedule.GetDynamicGas(tosca.AccessListAddressGas, revision)
+}
+
+func equalAccessLists(a, b []tosca.AccessTuple) bool {
+	return slices.EqualFunc(a, b, func(a, b tosca.AccessTuple) bool {
+		return a.Address == b.Address && slices.Equal(a.Keys, b.Keys)
+	})
+}
@@ -0,0 +1,259 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package simulation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/0xsoniclabs/tosca/go/interpreter/lfvm"
+	"github.com/0xsoniclabs/tosca/go/processor/floria"
+	"github.com/0xsoniclabs/tosca/go/processor/precompile"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGenerateAccessList_ReportsSavedGas(t *testing.T) {
+	simulator := newTestSimulator(t)
+	state, err := applyOverrides(newEmptyState(t), withCode(
+		byte(vm.PUSH1), other[19],
+		byte(vm.EXTCODESIZE),
+		byte(vm.STOP),
+	))
+	require.NoError(t, err)
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &recipient,
+		GasLimit:  100_000,
+	}
+
+	result, err := GenerateAccessList(simulator.Processor, floria.PrecompiledAddresses(tosca.R13_Cancun), nil, newBlockParameters(), transaction, state)
+	require.NoError(t, err)
+	require.True(t, result.Receipt.Success)
+	require.Equal(t, []tosca.AccessTuple{
+		{Address: other, Keys: []tosca.Key{}},
+	}, result.AccessList)
+
+	// A cold account access costs 2600 gas, while listing an account costs
+	// 2400 gas and a warm access costs 100 gas.
+	require.Equal(t, tosca.Gas(2600-2400-100), result.GasSaved)
+}
+
+func TestGenerateAccessList_ExcludesAccountsWarmByDefault(t *testing.T) {
+	simulator := newTestSimulator(t)
+	coinbase := tosca.Address{19: 0x44}
+	state, err := applyOverrides(newEmptyState(t), withCode(
+		byte(vm.COINBASE),
+		byte(vm.BALANCE),
+		byte(vm.ADDRESS),
+		byte(vm.BALANCE),
+		byte(vm.CALLER),
+		byte(vm.BALANCE),
+		byte(vm.PUSH1), 0x01,
+		byte(vm.BALANCE),
+		byte(vm.STOP),
+	))
+	require.NoError(t, err)
+	blockParameters := newBlockParameters()
+	blockParameters.Coinbase = coinbase
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &recipient,
+		GasLimit:  100_000,
+	}
+
+	result, err := GenerateAccessList(simulator.Processor, floria.PrecompiledAddresses(blockParameters.Revision), nil, blockParameters, transaction, state)
+	require.NoError(t, err)
+	require.Empty(t, result.AccessList)
+}
+
+func TestGenerateAccessList_UnusedEntriesOfTheTransactionAreDropped(t *testing.T) {
+	simulator := newTestSimulator(t)
+	state, err := applyOverrides(newEmptyState(t), withCode(byte(vm.STOP)))
+	require.NoError(t, err)
+	transaction := tosca.Transaction{
+		Sender:     sender,
+		Recipient:  &recipient,
+		GasLimit:   100_000,
+		AccessList: []tosca.AccessTuple{{Address: other, Keys: []tosca.Key{{1}}}},
+	}
+
+	result, err := GenerateAccessList(simulator.Processor, floria.PrecompiledAddresses(tosca.R13_Cancun), nil, newBlockParameters(), transaction, state)
+	require.NoError(t, err)
+	require.Empty(t, result.AccessList)
+	require.Equal(t, tosca.Gas(2400+1900), result.GasSaved)
+}
+
+func TestGenerateAccessList_AccessesInRevertedCallsAreListed(t *testing.T) {
+	simulator := newTestSimulator(t)
+	revertingCode := tosca.Code{
+		byte(vm.PUSH1), 3,
+		byte(vm.SLOAD),
+		byte(vm.PUSH1), 0,
+		byte(vm.PUSH1), 0,
+		byte(vm.REVERT),
+	}
+	overrides := withCode(
+		byte(vm.PUSH1), 0, // retSize
+		byte(vm.PUSH1), 0, // retOffset
+		byte(vm.PUSH1), 0, // argsSize
+		byte(vm.PUSH1), 0, // argsOffset
+		byte(vm.PUSH1), 0, // value
+		byte(vm.PUSH1), other[19],
+		byte(vm.GAS),
+		byte(vm.CALL),
+		byte(vm.STOP),
+	)
+	overrides[other] = AccountOverride{Code: &revertingCode}
+	state, err := applyOverrides(newEmptyState(t), overrides)
+	require.NoError(t, err)
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &recipient,
+		GasLimit:  100_000,
+	}
+
+	result, err := GenerateAccessList(simulator.Processor, floria.PrecompiledAddresses(tosca.R13_Cancun), nil, newBlockParameters(), transaction, state)
+	require.NoError(t, err)
+	require.Equal(t, []tosca.AccessTuple{
+		{Address: other, Keys: []tosca.Key{{31: 3}}},
+	}, result.AccessList)
+}
+
+func TestGenerateAccessList_ProcessorErrorsAreForwarded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	processor := tosca.NewMockProcessor(ctrl)
+	injectedError := fmt.Errorf("injected error")
+	processor.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any()).Return(tosca.Receipt{}, injectedError)
+
+	_, err := GenerateAccessList(processor, nil, nil, newBlockParameters(), tosca.Transaction{}, newEmptyState(t))
+	require.ErrorIs(t, err, injectedError)
+}
+
+func TestGenerateAccessList_SlotsOfAccountsWarmByDefaultAreOnlyListedIfWorthIt(t *testing.T) {
+	for _, slots := range []int{1, 24, 25} {
+		t.Run(fmt.Sprintf("%d slots", slots), func(t *testing.T) {
+			simulator := newTestSimulator(t)
+			code := []byte{}
+			for i := range slots {
+				code = append(code, byte(vm.PUSH1), byte(i), byte(vm.SLOAD), byte(vm.POP))
+			}
+			state, err := applyOverrides(newEmptyState(t), withCode(code...))
+			require.NoError(t, err)
+			transaction := tosca.Transaction{
+				Sender:    sender,
+				Recipient: &recipient,
+				GasLimit:  200_000,
+			}
+
+			result, err := GenerateAccessList(simulator.Processor, floria.PrecompiledAddresses(tosca.R13_Cancun), nil, newBlockParameters(), transaction, state)
+			require.NoError(t, err)
+
+			// Listing a slot saves 100 gas, listing the account costs 2400 gas.
+			if slots*100 > 2400 {
+				require.Len(t, result.AccessList, 1)
+				require.Equal(t, recipient, result.AccessList[0].Address)
+				require.Len(t, result.AccessList[0].Keys, slots)
+				require.Equal(t, tosca.Gas(slots*100-2400), result.GasSaved)
+			} else {
+				require.Empty(t, result.AccessList)
+				require.Equal(t, tosca.Gas(0), result.GasSaved)
+			}
+		})
+	}
+}
+
+func TestGenerateAccessList_GasScheduleOfTheSimulatorDecidesWhetherListingIsWorthIt(t *testing.T) {
+	schedule := &tosca.GasSchedule{
+		Dynamic: []tosca.DynamicGasOverride{
+			{Kind: tosca.AccessListAddressGas, Revision: tosca.R09_Berlin, Price: 150},
+		},
+	}
+	interpreter, err := lfvm.NewInterpreter(lfvm.Config{GasSchedule: schedule})
+	require.NoError(t, err)
+	simulator := NewSimulator(interpreter, floria.Config{EthCompatible: true, GasSchedule: schedule})
+
+	for _, slots := range []int{1, 2} {
+		t.Run(fmt.Sprintf("%d slots", slots), func(t *testing.T) {
+			code := []byte{}
+			for i := range slots {
+				code = append(code, byte(vm.PUSH1), byte(i), byte(vm.SLOAD), byte(vm.POP))
+			}
+			transaction := tosca.Transaction{
+				Sender:    sender,
+				Recipient: &recipient,
+				GasLimit:  100_000,
+			}
+			result, err := simulator.CreateAccessList(newBlockParameters(), transaction, newEmptyState(t), withCode(code...))
+			require.NoError(t, err)
+
+			// Listing a slot saves 100 gas, listing the account costs 150 gas.
+			if slots*100 > 150 {
+				require.Len(t, result.AccessList, 1)
+				require.Equal(t, tosca.Gas(slots*100-150), result.GasSaved)
+			} else {
+				require.Empty(t, result.AccessList)
+			}
+		})
+	}
+}
+
+func TestGenerateAccessList_ChainSpecificPrecompilesOfTheSimulatorAreExcluded(t *testing.T) {
+	custom := tosca.Address{19: 0xaa}
+	registry := precompile.NewStandardRegistry()
+	require.NoError(t, registry.Register(custom, precompile.Since(tosca.R07_Istanbul), nopContract{}))
+	interpreter, err := lfvm.NewInterpreter(lfvm.Config{})
+	require.NoError(t, err)
+	simulator := NewSimulator(interpreter, floria.Config{EthCompatible: true, Precompiles: registry})
+
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &recipient,
+		GasLimit:  100_000,
+	}
+	result, err := simulator.CreateAccessList(newBlockParameters(), transaction, newEmptyState(t), withCode(
+		byte(vm.PUSH1), custom[19],
+		byte(vm.BALANCE),
+		byte(vm.STOP),
+	))
+	require.NoError(t, err)
+	require.Empty(t, result.AccessList)
+}
+
+func TestGenerateAccessList_UnstableAccessListsAreReported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	processor := tosca.NewMockProcessor(ctrl)
+	counter := byte(0)
+	processor.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ tosca.BlockParameters, _ tosca.Transaction, context tosca.TransactionContext) (tosca.Receipt, error) {
+			// Every run accesses a different account.
+			counter++
+			context.AccessAccount(tosca.Address{19: counter})
+			return tosca.Receipt{Success: true}, nil
+		}).AnyTimes()
+
+	_, err := GenerateAccessList(processor, nil, nil, newBlockParameters(), tosca.Transaction{}, newEmptyState(t))
+	require.ErrorContains(t, err, "did not stabilize")
+	require.Equal(t, byte(maxAccessListIterations+1), counter)
+}
+
+// nopContract is a precompiled contract doing nothing.
+type nopContract struct{}
+
+func (nopContract) RequiredGas(tosca.Data) uint64 {
+	return 0
+}
+
+func (nopContract) Run(tosca.Data) (tosca.Data, error) {
+	return nil, nil
+}
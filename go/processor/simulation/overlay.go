@@ -0,0 +1,292 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package simulation
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var emptyCodeHash = tosca.Hash(crypto.Keccak256(nil))
+
+// overlay is a tosca.TransactionContext recording all modifications in memory
+// on top of a parent context. The parent context is only read, never written,
+// making it possible to execute transactions without committing any effects.
+// Transaction-local information like access lists, transient storage, logs
+// and self-destructs are tracked by the overlay and start out empty.
+type overlay struct {
+	parent tosca.TransactionContext
+
+	balances       map[tosca.Address]tosca.Value
+	nonces         map[tosca.Address]uint64
+	codes          map[tosca.Address]tosca.Code
+	storage        map[tosca.Address]map[tosca.Key]tosca.Word
+	clearedStorage map[tosca.Address]bool
+	created        map[tosca.Address]bool
+	selfDestructed map[tosca.Address]bool
+
+	transientStorage map[tosca.Address]map[tosca.Key]tosca.Word
+	accessedAccounts map[tosca.Address]bool
+	accessedSlots    map[tosca.Address]map[tosca.Key]bool
+	logs             []tosca.Log
+
+	undo []func()
+}
+
+func newOverlay(parent tosca.TransactionContext) *overlay {
+	return &overlay{
+		parent:           parent,
+		balances:         map[tosca.Address]tosca.Value{},
+		nonces:           map[tosca.Address]uint64{},
+		codes:            map[tosca.Address]tosca.Code{},
+		storage:          map[tosca.Address]map[tosca.Key]tosca.Word{},
+		clearedStorage:   map[tosca.Address]bool{},
+		created:          map[tosca.Address]bool{},
+		selfDestructed:   map[tosca.Address]bool{},
+		transientStorage: map[tosca.Address]map[tosca.Key]tosca.Word{},
+		accessedAccounts: map[tosca.Address]bool{},
+		accessedSlots:    map[tosca.Address]map[tosca.Key]bool{},
+	}
+}
+
+// setValue updates the given map entry and records the corresponding undo operation.
+func setValue[K comparable, V any](o *overlay, m map[K]V, key K, value V) {
+	old, present := m[key]
+	m[key] = value
+	o.undo = append(o.undo, func() {
+		if present {
+			m[key] = old
+		} else {
+			delete(m, key)
+		}
+	})
+}
+
+// setNested updates an entry of a two-level map and records the corresponding undo operation.
+func setNested[V any](o *overlay, m map[tosca.Address]map[tosca.Key]V, addr tosca.Address, key tosca.Key, value V) {
+	inner, found := m[addr]
+	if !found {
+		inner = map[tosca.Key]V{}
+		m[addr] = inner
+	}
+	setValue(o, inner, key, value)
+}
+
+func (o *overlay) AccountExists(addr tosca.Address) bool {
+	if o.created[addr] {
+		return true
+	}
+	if _, found := o.balances[addr]; found {
+		return true
+	}
+	if _, found := o.nonces[addr]; found {
+		return true
+	}
+	if _, found := o.codes[addr]; found {
+		return true
+	}
+	return o.parent.AccountExists(addr)
+}
+
+func (o *overlay) CreateContract(addr tosca.Address) {
+	setValue(o, o.created, addr, true)
+	setValue(o, o.clearedStorage, addr, true)
+	if slots, found := o.storage[addr]; found {
+		old := slots
+		o.storage[addr] = map[tosca.Key]tosca.Word{}
+		o.undo = append(o.undo, func() { o.storage[addr] = old })
+	}
+}
+
+func (o *overlay) IsNewContract(addr tosca.Address) bool {
+	return o.created[addr]
+}
+
+func (o *overlay) GetBalance(addr tosca.Address) tosca.Value {
+	if balance, found := o.balances[addr]; found {
+		return balance
+	}
+	return o.parent.GetBalance(addr)
+}
+
+func (o *overlay) SetBalance(addr tosca.Address, value tosca.Value) {
+	setValue(o, o.balances, addr, value)
+}
+
+func (o *overlay) GetNonce(addr tosca.Address) uint64 {
+	if nonce, found := o.nonces[addr]; found {
+		return nonce
+	}
+	return o.parent.GetNonce(addr)
+}
+
+func (o *overlay) SetNonce(addr tosca.Address, nonce uint64) {
+	setValue(o, o.nonces, addr, nonce)
+}
+
+func (o *overlay) GetCode(addr tosca.Address) tosca.Code {
+	if code, found := o.codes[addr]; found {
+		return code
+	}
+	return o.parent.GetCode(addr)
+}
+
+func (o *overlay) GetCodeHash(addr tosca.Address) tosca.Hash {
+	code, found := o.codes[addr]
+	if !found {
+		return o.parent.GetCodeHash(addr)
+	}
+	if len(code) == 0 {
+		return emptyCodeHash
+	}
+	return tosca.Hash(crypto.Keccak256(code))
+}
+
+func (o *overlay) GetCodeSize(addr tosca.Address) int {
+	if code, found := o.codes[addr]; found {
+		return len(code)
+	}
+	return o.parent.GetCodeSize(addr)
+}
+
+func (o *overlay) SetCode(addr tosca.Address, code tosca.Code) {
+	setValue(o, o.codes, addr, slices.Clone(code))
+}
+
+func (o *overlay) HasEmptyStorage(addr tosca.Address) bool {
+	for _, value := range o.storage[addr] {
+		if value != (tosca.Word{}) {
+			return false
+		}
+	}
+	return o.clearedStorage[addr] || o.parent.HasEmptyStorage(addr)
+}
+
+func (o *overlay) GetStorage(addr tosca.Address, key tosca.Key) tosca.Word {
+	if value, found := o.storage[addr][key]; found {
+		return value
+	}
+	if o.clearedStorage[addr] {
+		return tosca.Word{}
+	}
+	return o.parent.GetStorage(addr, key)
+}
+
+func (o *overlay) SetStorage(addr tosca.Address, key tosca.Key, value tosca.Word) tosca.StorageStatus {
+	original := o.GetCommittedStorage(addr, key)
+	current := o.GetStorage(addr, key)
+	setNested(o, o.storage, addr, key, value)
+	return tosca.GetStorageStatus(original, current, value)
+}
+
+func (o *overlay) SelfDestruct(addr tosca.Address, beneficiary tosca.Address) bool {
+	if o.selfDestructed[addr] {
+		return false
+	}
+	setValue(o, o.selfDestructed, addr, true)
+	return true
+}
+
+func (o *overlay) CreateSnapshot() tosca.Snapshot {
+	return tosca.Snapshot(len(o.undo))
+}
+
+func (o *overlay) RestoreSnapshot(snapshot tosca.Snapshot) {
+	for len(o.undo) > int(snapshot) {
+		o.undo[len(o.undo)-1]()
+		o.undo = o.undo[:len(o.undo)-1]
+	}
+}
+
+func (o *overlay) GetTransientStorage(addr tosca.Address, key tosca.Key) tosca.Word {
+	return o.transientStorage[addr][key]
+}
+
+func (o *overlay) SetTransientStorage(addr tosca.Address, key tosca.Key, value tosca.Word) {
+	setNested(o, o.transientStorage, addr, key, value)
+}
+
+func (o *overlay) AccessAccount(addr tosca.Address) tosca.AccessStatus {
+	if o.accessedAccounts[addr] {
+		return tosca.WarmAccess
+	}
+	setValue(o, o.accessedAccounts, addr, true)
+	return tosca.ColdAccess
+}
+
+func (o *overlay) AccessStorage(addr tosca.Address, key tosca.Key) tosca.AccessStatus {
+	if o.accessedSlots[addr][key] {
+		return tosca.WarmAccess
+	}
+	setNested(o, o.accessedSlots, addr, key, true)
+	return tosca.ColdAccess
+}
+
+func (o *overlay) EmitLog(log tosca.Log) {
+	size := len(o.logs)
+	o.logs = append(o.logs, log)
+	o.undo = append(o.undo, func() { o.logs = o.logs[:size] })
+}
+
+func (o *overlay) GetLogs() []tosca.Log {
+	return slices.Clone(o.logs)
+}
+
+func (o *overlay) GetBlockHash(number int64) tosca.Hash {
+	return o.parent.GetBlockHash(number)
+}
+
+// GetCommittedStorage returns the storage value at the beginning of the
+// transaction, which is the current value of the parent context.
+func (o *overlay) GetCommittedStorage(addr tosca.Address, key tosca.Key) tosca.Word {
+	if o.created[addr] {
+		return tosca.Word{}
+	}
+	return o.parent.GetStorage(addr, key)
+}
+
+func (o *overlay) IsAddressInAccessList(addr tosca.Address) bool {
+	return o.accessedAccounts[addr]
+}
+
+func (o *overlay) IsSlotInAccessList(addr tosca.Address, key tosca.Key) (addressPresent, slotPresent bool) {
+	slots := o.accessedSlots[addr]
+	return o.accessedAccounts[addr] || len(slots) > 0, slots[key]
+}
+
+func (o *overlay) HasSelfDestructed(addr tosca.Address) bool {
+	return o.selfDestructed[addr]
+}
+
+// applyOverride applies the given account override to the overlay. Storage
+// provided by State replaces the full storage of the account, while entries
+// of StateDiff are applied on top of the existing storage.
+func (o *overlay) applyOverride(addr tosca.Address, override AccountOverride) {
+	if override.Balance != nil {
+		o.SetBalance(addr, *override.Balance)
+	}
+	if override.Nonce != nil {
+		o.SetNonce(addr, *override.Nonce)
+	}
+	if override.Code != nil {
+		o.SetCode(addr, *override.Code)
+	}
+	if override.State != nil {
+		o.clearedStorage[addr] = true
+		o.storage[addr] = maps.Clone(override.State)
+	}
+	for key, value := range override.StateDiff {
+		setNested(o, o.storage, addr, key, value)
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package simulation
+
+import (
+	"testing"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newEmptyState creates a read-only context representing an empty world state.
+// Any attempt to modify the state fails the test.
+func newEmptyState(t *testing.T) *tosca.MockTransactionContext {
+	state := tosca.NewMockTransactionContext(gomock.NewController(t))
+	state.EXPECT().AccountExists(gomock.Any()).AnyTimes()
+	state.EXPECT().GetBalance(gomock.Any()).AnyTimes()
+	state.EXPECT().GetNonce(gomock.Any()).AnyTimes()
+	state.EXPECT().GetCode(gomock.Any()).AnyTimes()
+	state.EXPECT().GetCodeHash(gomock.Any()).AnyTimes()
+	state.EXPECT().GetCodeSize(gomock.Any()).AnyTimes()
+	state.EXPECT().GetStorage(gomock.Any(), gomock.Any()).AnyTimes()
+	state.EXPECT().HasEmptyStorage(gomock.Any()).Return(true).AnyTimes()
+	state.EXPECT().GetBlockHash(gomock.Any()).AnyTimes()
+	return state
+}
+
+func TestOverlay_ReadsAreForwardedToParent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	parent := tosca.NewMockTransactionContext(ctrl)
+	address := tosca.Address{1}
+	key := tosca.Key{2}
+
+	parent.EXPECT().AccountExists(address).Return(true)
+	parent.EXPECT().GetBalance(address).Return(tosca.NewValue(3))
+	parent.EXPECT().GetNonce(address).Return(uint64(4))
+	parent.EXPECT().GetCode(address).Return(tosca.Code{5})
+	parent.EXPECT().GetCodeHash(address).Return(tosca.Hash{6})
+	parent.EXPECT().GetCodeSize(address).Return(1)
+	parent.EXPECT().GetStorage(address, key).Return(tosca.Word{7}).Times(2)
+	parent.EXPECT().GetBlockHash(int64(8)).Return(tosca.Hash{9})
+
+	overlay := newOverlay(parent)
+	require.True(t, overlay.AccountExists(address))
+	require.Equal(t, tosca.NewValue(3), overlay.GetBalance(address))
+	require.Equal(t, uint64(4), overlay.GetNonce(address))
+	require.Equal(t, tosca.Code{5}, overlay.GetCode(address))
+	require.Equal(t, tosca.Hash{6}, overlay.GetCodeHash(address))
+	require.Equal(t, 1, overlay.GetCodeSize(address))
+	require.Equal(t, tosca.Word{7}, overlay.GetStorage(address, key))
+	require.Equal(t, tosca.Word{7}, overlay.GetCommittedStorage(address, key))
+	require.Equal(t, tosca.Hash{9}, overlay.GetBlockHash(8))
+}
+
+func TestOverlay_WritesAreNotForwardedToParent(t *testing.T) {
+	address := tosca.Address{1}
+	key := tosca.Key{2}
+	code := tosca.Code{0x60, 0x01}
+
+	overlay := newOverlay(newEmptyState(t))
+	overlay.SetBalance(address, tosca.NewValue(3))
+	overlay.SetNonce(address, 4)
+	overlay.SetCode(address, code)
+	status := overlay.SetStorage(address, key, tosca.Word{5})
+	overlay.SetTransientStorage(address, key, tosca.Word{6})
+	overlay.EmitLog(tosca.Log{Address: address})
+
+	require.Equal(t, tosca.StorageAdded, status)
+	require.True(t, overlay.AccountExists(address))
+	require.Equal(t, tosca.NewValue(3), overlay.GetBalance(address))
+	require.Equal(t, uint64(4), overlay.GetNonce(address))
+	require.Equal(t, code, overlay.GetCode(address))
+	require.Equal(t, len(code), overlay.GetCodeSize(address))
+	require.NotEqual(t, emptyCodeHash, overlay.GetCodeHash(address))
+	require.Equal(t, tosca.Word{5}, overlay.GetStorage(address, key))
+	require.Equal(t, tosca.Word{}, overlay.GetCommittedStorage(address, key))
+	require.False(t, overlay.HasEmptyStorage(address))
+	require.Equal(t, tosca.Word{6}, overlay.GetTransientStorage(address, key))
+	require.Equal(t, []tosca.Log{{Address: address}}, overlay.GetLogs())
+}
+
+func TestOverlay_RestoreSnapshotRevertsAllModifications(t *testing.T) {
+	address := tosca.Address{1}
+	key := tosca.Key{2}
+
+	overlay := newOverlay(newEmptyState(t))
+	overlay.SetBalance(address, tosca.NewValue(1))
+	snapshot := overlay.CreateSnapshot()
+
+	overlay.SetBalance(address, tosca.NewValue(2))
+	overlay.SetNonce(address, 3)
+	overlay.SetCode(address, tosca.Code{4})
+	overlay.SetStorage(address, key, tosca.Word{5})
+	overlay.SetTransientStorage(address, key, tosca.Word{6})
+	overlay.AccessAccount(address)
+	overlay.AccessStorage(address, key)
+	overlay.EmitLog(tosca.Log{})
+	overlay.CreateContract(address)
+	overlay.SelfDestruct(address, tosca.Address{})
+
+	overlay.RestoreSnapshot(snapshot)
+
+	require.Equal(t, tosca.NewValue(1), overlay.GetBalance(address))
+	require.Equal(t, uint64(0), overlay.GetNonce(address))
+	require.Empty(t, overlay.GetCode(address))
+	require.Equal(t, tosca.Word{}, overlay.GetStorage(address, key))
+	require.Equal(t, tosca.Word{}, overlay.GetTransientStorage(address, key))
+	require.False(t, overlay.IsAddressInAccessList(address))
+	addressPresent, slotPresent := overlay.IsSlotInAccessList(address, key)
+	require.False(t, addressPresent)
+	require.False(t, slotPresent)
+	require.Empty(t, overlay.GetLogs())
+	require.False(t, overlay.IsNewContract(address))
+	require.False(t, overlay.HasSelfDestructed(address))
+}
+
+func TestOverlay_CreateContractClearsStorage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	parent := tosca.NewMockTransactionContext(ctrl)
+	address := tosca.Address{1}
+
+	overlay := newOverlay(parent)
+	overlay.CreateContract(address)
+
+	require.True(t, overlay.IsNewContract(address))
+	require.True(t, overlay.HasEmptyStorage(address))
+	require.Equal(t, tosca.Word{}, overlay.GetStorage(address, tosca.Key{2}))
+	require.Equal(t, tosca.Word{}, overlay.GetCommittedStorage(address, tosca.Key{2}))
+}
+
+func TestOverlay_AccessStatusIsTrackedLocally(t *testing.T) {
+	address := tosca.Address{1}
+	key := tosca.Key{2}
+
+	overlay := newOverlay(tosca.NewMockTransactionContext(gomock.NewController(t)))
+	require.Equal(t, tosca.ColdAccess, overlay.AccessAccount(address))
+	require.Equal(t, tosca.WarmAccess, overlay.AccessAccount(address))
+	require.Equal(t, tosca.ColdAccess, overlay.AccessStorage(address, key))
+	require.Equal(t, tosca.WarmAccess, overlay.AccessStorage(address, key))
+
+	addressPresent, slotPresent := overlay.IsSlotInAccessList(address, key)
+	require.True(t, addressPresent)
+	require.True(t, slotPresent)
+}
+
+func TestOverlay_SelfDestructReportsFirstDestructionOnly(t *testing.T) {
+	overlay := newOverlay(tosca.NewMockTransactionContext(gomock.NewController(t)))
+	require.True(t, overlay.SelfDestruct(tosca.Address{1}, tosca.Address{2}))
+	require.False(t, overlay.SelfDestruct(tosca.Address{1}, tosca.Address{2}))
+	require.True(t, overlay.HasSelfDestructed(tosca.Address{1}))
+}
+
+func TestOverlay_StateOverrideReplacesStorage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	parent := tosca.NewMockTransactionContext(ctrl)
+	address := tosca.Address{1}
+
+	parent.EXPECT().GetStorage(address, tosca.Key{3}).Return(tosca.Word{3})
+
+	replaced := newOverlay(parent)
+	replaced.applyOverride(address, AccountOverride{
+		State: map[tosca.Key]tosca.Word{{1}: {1}},
+	})
+	require.Equal(t, tosca.Word{1}, replaced.GetStorage(address, tosca.Key{1}))
+	require.Equal(t, tosca.Word{}, replaced.GetStorage(address, tosca.Key{2}))
+
+	patched := newOverlay(parent)
+	patched.applyOverride(address, AccountOverride{
+		StateDiff: map[tosca.Key]tosca.Word{{1}: {1}},
+	})
+	require.Equal(t, tosca.Word{1}, patched.GetStorage(address, tosca.Key{1}))
+	require.Equal(t, tosca.Word{3}, patched.GetStorage(address, tosca.Key{3}))
+}
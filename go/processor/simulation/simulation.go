@@ -0,0 +1,247 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/0xsoniclabs/tosca/go/processor/floria"
+	"github.com/0xsoniclabs/tosca/go/processor/revert"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+)
+
+// callStipend is the gas forwarded to the recipient of a value transfer for free.
+const callStipend = 2300
+
+// ErrExecutionReverted is returned if a simulated transaction required to
+// succeed did revert.
+var ErrExecutionReverted = errors.New("execution reverted")
+
+// StateOverride maps addresses to modifications of the corresponding accounts
+// applied before a transaction is simulated.
+type StateOverride map[tosca.Address]AccountOverride
+
+// AccountOverride describes the modifications applied to a single account.
+// Nil fields are left unchanged. State replaces the full storage of the
+// account while StateDiff only updates the listed slots. At most one of
+// State and StateDiff may be set.
+type AccountOverride struct {
+	Balance   *tosca.Value
+	Nonce     *uint64
+	Code      *tosca.Code
+	State     map[tosca.Key]tosca.Word
+	StateDiff map[tosca.Key]tosca.Word
+}
+
+// Simulator executes transactions on top of a given state without committing
+// any of their effects. It provides the building blocks for the eth_call,
+// eth_estimateGas and eth_createAccessList RPC methods.
+type Simulator struct {
+	Processor tosca.Processor
+}
+
+// NewSimulator creates a simulator running transactions on a Floria processor
+// using the given interpreter and configuration. Off-chain simulation is
+// enabled regardless of the provided configuration.
+func NewSimulator(interpreter tosca.Interpreter, config floria.Config) *Simulator {
+	config.OffChainSimulation = true
+	return &Simulator{
+		Processor: &floria.Processor{
+			Interpreter: interpreter,
+			Config:      config,
+		},
+	}
+}
+
+// Call executes the given transaction on top of the provided state, modified
+// by the given overrides, and returns the resulting receipt. The provided
+// state is only read, never modified.
+func (s *Simulator) Call(
+	blockParameters tosca.BlockParameters,
+	transaction tosca.Transaction,
+	state tosca.TransactionContext,
+	overrides StateOverride,
+) (tosca.Receipt, error) {
+	context, err := newSimulationContext(state, overrides)
+	if err != nil {
+		return tosca.Receipt{}, err
+	}
+	return s.Processor.Run(blockParameters, transaction, context)
+}
+
+// EstimateGas determines the lowest gas limit for which the given transaction
+// succeeds. The search starts at the gas limit of the transaction, or the
+// block gas limit if the transaction does not specify one, and performs a
+// binary search over repeated executions on fresh copies of the state. If
+// the transaction specifies a gas fee cap, the search is further limited to
+// the gas the sender can afford. An error is returned if the transaction
+// does not succeed with the initial gas limit.
+func (s *Simulator) EstimateGas(
+	blockParameters tosca.BlockParameters,
+	transaction tosca.Transaction,
+	state tosca.TransactionContext,
+	overrides StateOverride,
+) (tosca.Gas, error) {
+	context, err := newSimulationContext(state, overrides)
+	if err != nil {
+		return 0, err
+	}
+
+	hi := transaction.GasLimit
+	if hi == 0 {
+		hi = blockParameters.GasLimit
+	}
+	allowance, err := getGasAllowance(transaction, context.GetBalance(transaction.Sender))
+	if err != nil {
+		return 0, err
+	}
+	if allowance != nil && allowance.Cmp(big.NewInt(int64(hi))) < 0 {
+		hi = tosca.Gas(allowance.Int64())
+	}
+	lo := tosca.Gas(floria.TxGas - 1)
+	if transaction.Recipient == nil {
+		lo = floria.TxGasContractCreation - 1
+	}
+
+	run := func(gas tosca.Gas) (tosca.Receipt, error) {
+		transaction.GasLimit = gas
+		return s.Call(blockParameters, transaction, state, overrides)
+	}
+
+	receipt, err := run(hi)
+	if err != nil {
+		return 0, err
+	}
+	if !receipt.Success {
+		if len(receipt.Output) > 0 {
+			return 0, fmt.Errorf("%w: %v", ErrExecutionReverted, revert.Decode(receipt.Output))
+		}
+		return 0, fmt.Errorf("gas required exceeds allowance (%d)", hi)
+	}
+
+	// Only 63/64 of the available gas is forwarded to nested calls. Thus, the
+	// gas used by the transaction scaled by 64/63 is very likely sufficient,
+	// which allows to narrow down the search range right away.
+	optimistic := (receipt.GasUsed + callStipend) * 64 / 63
+	if optimistic > lo && optimistic < hi {
+		receipt, err := run(optimistic)
+		if errors.Is(err, tosca.ErrCanceled) {
+			return 0, err
+		}
+		if err == nil && receipt.Success {
+			hi = optimistic
+		} else {
+			lo = optimistic
+		}
+	}
+
+	for lo+1 < hi {
+		mid := lo + (hi-lo)/2
+		receipt, err := run(mid)
+		if errors.Is(err, tosca.ErrCanceled) {
+			return 0, err
+		}
+		if err == nil && receipt.Success {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi, nil
+}
+
+// getGasAllowance computes the maximum gas limit the sender can afford with
+// the given balance after paying for the transferred value and blob gas. Nil
+// is returned if the transaction does not specify a gas price, in which case
+// the gas limit is not restricted by the balance.
+func getGasAllowance(transaction tosca.Transaction, balance tosca.Value) (*big.Int, error) {
+	feeCap := transaction.GasFeeCap.ToBig()
+	if feeCap.Sign() == 0 {
+		return nil, nil
+	}
+	available := balance.ToBig()
+	value := transaction.Value.ToBig()
+	if available.Cmp(value) < 0 {
+		return nil, fmt.Errorf("insufficient funds for transfer: balance %v, value %v", available, value)
+	}
+	available.Sub(available, value)
+
+	blobGas := big.NewInt(int64(len(transaction.BlobHashes) * floria.BlobTxBlobGasPerBlob))
+	blobFee := blobGas.Mul(blobGas, transaction.BlobGasFeeCap.ToBig())
+	if available.Cmp(blobFee) < 0 {
+		return nil, fmt.Errorf("insufficient funds for blob gas: balance %v, blob fee %v", available, blobFee)
+	}
+	available.Sub(available, blobFee)
+	return available.Div(available, feeCap), nil
+}
+
+// CreateAccessList determines the access list of the given transaction
+// executed on top of the provided state modified by the given overrides. See
+// GenerateAccessList for details.
+func (s *Simulator) CreateAccessList(
+	blockParameters tosca.BlockParameters,
+	transaction tosca.Transaction,
+	state tosca.TransactionContext,
+	overrides StateOverride,
+) (AccessListResult, error) {
+	state, err := applyOverrides(state, overrides)
+	if err != nil {
+		return AccessListResult{}, err
+	}
+	return GenerateAccessList(s.Processor, s.getPrecompiles(blockParameters.Revision), s.getGasSchedule(), blockParameters, transaction, state)
+}
+
+// getPrecompiles returns the precompiled contracts of the processor of the
+// simulator. If the processor cannot list them, the contracts defined by
+// Ethereum are assumed.
+func (s *Simulator) getPrecompiles(revision tosca.Revision) []tosca.Address {
+	if lister, ok := s.Processor.(PrecompileLister); ok {
+		return lister.PrecompiledAddresses(revision)
+	}
+	return floria.PrecompiledAddresses(revision)
+}
+
+// getGasSchedule returns the gas schedule of the processor of the simulator.
+// If the processor cannot report it, the Ethereum prices are assumed.
+func (s *Simulator) getGasSchedule() *tosca.GasSchedule {
+	if provider, ok := s.Processor.(GasScheduleProvider); ok {
+		return provider.GasSchedule()
+	}
+	return nil
+}
+
+// newSimulationContext creates a context for executing a single transaction on
+// top of the given state with the given overrides applied.
+func newSimulationContext(state tosca.TransactionContext, overrides StateOverride) (*overlay, error) {
+	state, err := applyOverrides(state, overrides)
+	if err != nil {
+		return nil, err
+	}
+	return newOverlay(state), nil
+}
+
+// applyOverrides returns a read-only view of the given state with the given
+// overrides applied.
+func applyOverrides(state tosca.TransactionContext, overrides StateOverride) (tosca.TransactionContext, error) {
+	if len(overrides) == 0 {
+		return state, nil
+	}
+	modified := newOverlay(state)
+	for address, override := range overrides {
+		if override.State != nil && override.StateDiff != nil {
+			return nil, fmt.Errorf("account %v has both 'State' and 'StateDiff' overrides", address)
+		}
+		modified.applyOverride(address, override)
+	}
+	return modified, nil
+}
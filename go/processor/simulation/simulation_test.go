@@ -0,0 +1,306 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package simulation
+
+import (
+	"testing"
+
+	"github.com/0xsoniclabs/tosca/go/interpreter/lfvm"
+	"github.com/0xsoniclabs/tosca/go/processor/floria"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	sender    = tosca.Address{0x01}
+	recipient = tosca.Address{19: 0x42}
+	other     = tosca.Address{19: 0x43}
+)
+
+func newTestSimulator(t *testing.T) *Simulator {
+	t.Helper()
+	interpreter, err := lfvm.NewInterpreter(lfvm.Config{})
+	require.NoError(t, err)
+	return NewSimulator(interpreter, floria.Config{EthCompatible: true})
+}
+
+func newBlockParameters() tosca.BlockParameters {
+	return tosca.BlockParameters{
+		Revision: tosca.R13_Cancun,
+		GasLimit: 10_000_000,
+	}
+}
+
+func withCode(code ...byte) StateOverride {
+	c := tosca.Code(code)
+	return StateOverride{recipient: {Code: &c}}
+}
+
+func TestSimulator_CallDoesNotModifyState(t *testing.T) {
+	simulator := newTestSimulator(t)
+	balance := tosca.NewValue(1000)
+	overrides := withCode(
+		byte(vm.PUSH1), 1,
+		byte(vm.PUSH1), 0,
+		byte(vm.SSTORE),
+		byte(vm.STOP),
+	)
+	overrides[sender] = AccountOverride{Balance: &balance}
+
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &recipient,
+		Value:     tosca.NewValue(10),
+		GasLimit:  100_000,
+	}
+
+	// The empty state fails the test on any modification attempt.
+	receipt, err := simulator.Call(newBlockParameters(), transaction, newEmptyState(t), overrides)
+	require.NoError(t, err)
+	require.True(t, receipt.Success)
+}
+
+func TestSimulator_CallUsesOverriddenState(t *testing.T) {
+	simulator := newTestSimulator(t)
+	overrides := withCode(
+		byte(vm.PUSH1), 7,
+		byte(vm.SLOAD),
+		byte(vm.PUSH1), 0,
+		byte(vm.MSTORE),
+		byte(vm.PUSH1), 32,
+		byte(vm.PUSH1), 0,
+		byte(vm.RETURN),
+	)
+	override := overrides[recipient]
+	override.StateDiff = map[tosca.Key]tosca.Word{{31: 7}: {31: 42}}
+	overrides[recipient] = override
+
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &recipient,
+		GasLimit:  100_000,
+	}
+
+	receipt, err := simulator.Call(newBlockParameters(), transaction, newEmptyState(t), overrides)
+	require.NoError(t, err)
+	require.True(t, receipt.Success)
+	want := tosca.Word{31: 42}
+	require.Equal(t, tosca.Data(want[:]), receipt.Output)
+}
+
+func TestSimulator_OverridesWithStateAndStateDiffAreRejected(t *testing.T) {
+	simulator := newTestSimulator(t)
+	overrides := StateOverride{
+		recipient: {
+			State:     map[tosca.Key]tosca.Word{},
+			StateDiff: map[tosca.Key]tosca.Word{},
+		},
+	}
+	transaction := tosca.Transaction{Sender: sender, Recipient: &recipient}
+
+	_, err := simulator.Call(newBlockParameters(), transaction, newEmptyState(t), overrides)
+	require.ErrorContains(t, err, "both 'State' and 'StateDiff'")
+	_, err = simulator.EstimateGas(newBlockParameters(), transaction, newEmptyState(t), overrides)
+	require.ErrorContains(t, err, "both 'State' and 'StateDiff'")
+	_, err = simulator.CreateAccessList(newBlockParameters(), transaction, newEmptyState(t), overrides)
+	require.ErrorContains(t, err, "both 'State' and 'StateDiff'")
+}
+
+func TestSimulator_EstimateGasOfValueTransferIsTransactionGas(t *testing.T) {
+	simulator := newTestSimulator(t)
+	balance := tosca.NewValue(1000)
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &recipient,
+		Value:     tosca.NewValue(10),
+	}
+	overrides := StateOverride{sender: {Balance: &balance}}
+
+	gas, err := simulator.EstimateGas(newBlockParameters(), transaction, newEmptyState(t), overrides)
+	require.NoError(t, err)
+	require.Equal(t, tosca.Gas(floria.TxGas), gas)
+}
+
+func TestSimulator_EstimateGasFindsLowestSufficientGasLimit(t *testing.T) {
+	simulator := newTestSimulator(t)
+	// The contract forwards all its gas to a nested call performing an
+	// SSTORE, which makes the required gas limit subject to the 63/64 rule.
+	overrides := withCode(
+		byte(vm.PUSH1), 0, // retSize
+		byte(vm.PUSH1), 0, // retOffset
+		byte(vm.PUSH1), 0, // argsSize
+		byte(vm.PUSH1), 0, // argsOffset
+		byte(vm.PUSH1), 0, // value
+		byte(vm.PUSH1), other[19],
+		byte(vm.GAS),
+		byte(vm.CALL),
+		byte(vm.ISZERO),
+		byte(vm.PUSH1), 18,
+		byte(vm.JUMPI),
+		byte(vm.STOP),
+		byte(vm.JUMPDEST), // 18
+		byte(vm.PUSH1), 0,
+		byte(vm.PUSH1), 0,
+		byte(vm.REVERT),
+	)
+	code := tosca.Code{
+		byte(vm.PUSH1), 1,
+		byte(vm.PUSH1), 0,
+		byte(vm.SSTORE),
+		byte(vm.STOP),
+	}
+	overrides[other] = AccountOverride{Code: &code}
+
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &recipient,
+	}
+
+	gas, err := simulator.EstimateGas(newBlockParameters(), transaction, newEmptyState(t), overrides)
+	require.NoError(t, err)
+
+	transaction.GasLimit = gas
+	receipt, err := simulator.Call(newBlockParameters(), transaction, newEmptyState(t), overrides)
+	require.NoError(t, err)
+	require.True(t, receipt.Success)
+
+	transaction.GasLimit = gas - 1
+	receipt, err = simulator.Call(newBlockParameters(), transaction, newEmptyState(t), overrides)
+	require.NoError(t, err)
+	require.False(t, receipt.Success)
+}
+
+func TestSimulator_EstimateGasReportsFailures(t *testing.T) {
+	tests := map[string]struct {
+		code  StateOverride
+		error string
+	}{
+		"revert with output": {
+			code: withCode(
+				byte(vm.PUSH1), 0x2a,
+				byte(vm.PUSH1), 0,
+				byte(vm.MSTORE),
+				byte(vm.PUSH1), 32,
+				byte(vm.PUSH1), 0,
+				byte(vm.REVERT),
+			),
+			error: ErrExecutionReverted.Error(),
+		},
+		"invalid instruction": {
+			code:  withCode(byte(vm.INVALID)),
+			error: "gas required exceeds allowance",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			simulator := newTestSimulator(t)
+			transaction := tosca.Transaction{
+				Sender:    sender,
+				Recipient: &recipient,
+			}
+			_, err := simulator.EstimateGas(newBlockParameters(), transaction, newEmptyState(t), test.code)
+			require.ErrorContains(t, err, test.error)
+		})
+	}
+}
+
+func TestSimulator_EstimateGasIsLimitedByBalanceOfSender(t *testing.T) {
+	simulator := newTestSimulator(t)
+	// The balance covers the value and exactly the gas of a plain transfer.
+	// Without limiting the search to the affordable gas, the first probe
+	// with the block gas limit fails the balance check.
+	balance := tosca.NewValue(10 + 2*floria.TxGas)
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &recipient,
+		Value:     tosca.NewValue(10),
+		GasFeeCap: tosca.NewValue(2),
+	}
+	overrides := StateOverride{sender: {Balance: &balance}}
+
+	gas, err := simulator.EstimateGas(newBlockParameters(), transaction, newEmptyState(t), overrides)
+	require.NoError(t, err)
+	require.Equal(t, tosca.Gas(floria.TxGas), gas)
+
+	// With one gas unit less affordable, the transaction can not succeed.
+	balance = tosca.NewValue(10 + 2*floria.TxGas - 1)
+	overrides = StateOverride{sender: {Balance: &balance}}
+	_, err = simulator.EstimateGas(newBlockParameters(), transaction, newEmptyState(t), overrides)
+	require.ErrorContains(t, err, "insufficient")
+}
+
+func TestSimulator_EstimateGasReportsInsufficientFundsForTransfer(t *testing.T) {
+	simulator := newTestSimulator(t)
+	balance := tosca.NewValue(5)
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &recipient,
+		Value:     tosca.NewValue(10),
+		GasFeeCap: tosca.NewValue(1),
+	}
+	overrides := StateOverride{sender: {Balance: &balance}}
+
+	_, err := simulator.EstimateGas(newBlockParameters(), transaction, newEmptyState(t), overrides)
+	require.ErrorContains(t, err, "insufficient funds for transfer")
+}
+
+func TestSimulator_CanceledExecutionsReportCancellation(t *testing.T) {
+	simulator := newTestSimulator(t)
+	overrides := withCode(
+		byte(vm.JUMPDEST),
+		byte(vm.PUSH1), 0,
+		byte(vm.JUMP),
+	)
+	done := make(chan struct{})
+	close(done)
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &recipient,
+		GasLimit:  1_000_000,
+		Done:      done,
+	}
+
+	_, err := simulator.Call(newBlockParameters(), transaction, newEmptyState(t), overrides)
+	require.ErrorIs(t, err, tosca.ErrCanceled)
+
+	_, err = simulator.EstimateGas(newBlockParameters(), transaction, newEmptyState(t), overrides)
+	require.ErrorIs(t, err, tosca.ErrCanceled)
+}
+
+func TestSimulator_CreateAccessListListsAccessedAccountsAndSlots(t *testing.T) {
+	simulator := newTestSimulator(t)
+	overrides := withCode(
+		byte(vm.PUSH1), 7, // a single slot of the recipient is not worth listing
+		byte(vm.SLOAD),
+		byte(vm.POP),
+		byte(vm.PUSH1), other[19],
+		byte(vm.BALANCE),
+		byte(vm.POP),
+		byte(vm.PUSH1), 0x09, // precompiled contracts are not listed
+		byte(vm.BALANCE),
+		byte(vm.POP),
+		byte(vm.STOP),
+	)
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &recipient,
+		GasLimit:  100_000,
+	}
+
+	result, err := simulator.CreateAccessList(newBlockParameters(), transaction, newEmptyState(t), overrides)
+	require.NoError(t, err)
+	require.True(t, result.Receipt.Success)
+	require.Equal(t, []tosca.AccessTuple{
+		{Address: other, Keys: []tosca.Key{}},
+	}, result.AccessList)
+}
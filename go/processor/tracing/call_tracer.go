@@ -0,0 +1,306 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/0xsoniclabs/tosca/go/processor/floria"
+	"github.com/0xsoniclabs/tosca/go/processor/revert"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+)
+
+// CallFrame describes a single call within the call tree of a transaction.
+// Its JSON encoding matches the output of geth's callTracer.
+type CallFrame struct {
+	Type         string         // the kind of call, e.g. CALL, DELEGATECALL, CREATE2, or SELFDESTRUCT
+	From         tosca.Address  // the caller
+	To           *tosca.Address // the callee, for creates the created contract if known
+	Value        *tosca.Value   // the transferred value, nil for static and delegate calls
+	Gas          tosca.Gas      // the gas provided to the call
+	GasUsed      tosca.Gas      // the gas consumed by the call
+	Input        tosca.Data     // the call data or init code
+	Output       tosca.Data     // the return data or deployed code
+	Error        string         // empty if the call was successful
+	RevertReason string         // the decoded revert reason, if available
+	Calls        []CallFrame    // the nested calls in the order of execution
+	Logs         []CallLog      // the logs emitted by this call, empty if the call failed
+}
+
+// CallLog is a log emitted within a call frame.
+type CallLog struct {
+	Address  tosca.Address
+	Topics   []tosca.Hash
+	Data     tosca.Data
+	Position int // the number of nested calls performed before the log was emitted
+}
+
+// CallTracer records the call tree of transactions executed by Floria. Use
+// Decorate as the processor's RunContextDecorator or TraceCalls to enable it.
+// A CallTracer is not thread-safe and should be used for a single transaction.
+type CallTracer struct {
+	root  *CallFrame
+	stack []*CallFrame
+}
+
+// NewCallTracer creates a new tracer with an empty call tree.
+func NewCallTracer() *CallTracer {
+	return &CallTracer{}
+}
+
+// Decorate wraps the given run context such that all calls and logs passing
+// through it are recorded by this tracer.
+func (t *CallTracer) Decorate(context tosca.RunContext) tosca.RunContext {
+	return &callTracingContext{RunContext: context, tracer: t}
+}
+
+// Result returns the root of the recorded call tree or nil if no call has been
+// recorded yet.
+func (t *CallTracer) Result() *CallFrame {
+	return t.root
+}
+
+// TraceCalls runs the given transaction using a copy of the given processor
+// with call tracing enabled. In line with geth's callTracer, the gas of the
+// returned top-level frame covers the full gas limit and gas usage of the
+// transaction, including intrinsic gas.
+func TraceCalls(
+	processor floria.Processor,
+	blockParameters tosca.BlockParameters,
+	transaction tosca.Transaction,
+	context tosca.TransactionContext,
+) (*CallFrame, tosca.Receipt, error) {
+	tracer := NewCallTracer()
+	decorate := processor.Config.RunContextDecorator
+	processor.Config.RunContextDecorator = func(context tosca.RunContext) tosca.RunContext {
+		if decorate != nil {
+			context = decorate(context)
+		}
+		return tracer.Decorate(context)
+	}
+
+	receipt, err := processor.Run(blockParameters, transaction, context)
+	if err != nil {
+		return nil, receipt, err
+	}
+	root := tracer.Result()
+	if root != nil {
+		root.Gas = transaction.GasLimit
+		root.GasUsed = receipt.GasUsed
+	}
+	return root, receipt, nil
+}
+
+func (t *CallTracer) enter(frame CallFrame) {
+	if len(t.stack) == 0 {
+		t.root = &frame
+		t.stack = append(t.stack, t.root)
+		return
+	}
+	parent := t.stack[len(t.stack)-1]
+	parent.Calls = append(parent.Calls, frame)
+	t.stack = append(t.stack, &parent.Calls[len(parent.Calls)-1])
+}
+
+func (t *CallTracer) exit(kind tosca.CallKind, result tosca.CallResult, err error) {
+	frame := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+
+	frame.GasUsed = frame.Gas - result.GasLeft
+	frame.Output = result.Output
+	if (kind == tosca.Create || kind == tosca.Create2 || kind == tosca.EofCreate) && result.CreatedAddress != (tosca.Address{}) {
+		frame.To = &result.CreatedAddress
+	}
+
+	switch {
+	case err != nil:
+		frame.Error = err.Error()
+	case !result.Success && result.Reverted:
+		frame.Error = "execution reverted"
+		if reason := revert.Decode(result.Output); reason.Kind == revert.Error || reason.Kind == revert.Panic {
+			frame.RevertReason = reason.String()
+		}
+	case !result.Success:
+		frame.Error = "execution failed"
+	}
+	if frame.Error != "" {
+		frame.clearLogs()
+	}
+}
+
+func (t *CallTracer) log(log tosca.Log) {
+	if len(t.stack) == 0 {
+		return
+	}
+	frame := t.stack[len(t.stack)-1]
+	frame.Logs = append(frame.Logs, CallLog{
+		Address:  log.Address,
+		Topics:   log.Topics,
+		Data:     log.Data,
+		Position: len(frame.Calls),
+	})
+}
+
+func (t *CallTracer) selfDestruct(address, beneficiary tosca.Address, balance tosca.Value) {
+	t.enter(CallFrame{
+		Type:  "SELFDESTRUCT",
+		From:  address,
+		To:    &beneficiary,
+		Value: &balance,
+	})
+	t.stack = t.stack[:len(t.stack)-1]
+}
+
+// clearLogs removes the logs of this frame and all nested frames since the
+// effects of failed calls are reverted.
+func (f *CallFrame) clearLogs() {
+	f.Logs = nil
+	for i := range f.Calls {
+		f.Calls[i].clearLogs()
+	}
+}
+
+// callTracingContext is a run context reporting all calls, logs, and
+// self-destructs to a call tracer.
+type callTracingContext struct {
+	tosca.RunContext
+	tracer *CallTracer
+}
+
+func (c *callTracingContext) Call(kind tosca.CallKind, parameters tosca.CallParameters) (tosca.CallResult, error) {
+	frame := CallFrame{
+		Type:  callType(kind),
+		From:  parameters.Sender,
+		Gas:   parameters.Gas,
+		Input: bytes.Clone(parameters.Input), // < the caller may reuse its buffer
+	}
+	switch kind {
+	case tosca.Call, tosca.StaticCall:
+		to := parameters.Recipient
+		frame.To = &to
+	case tosca.DelegateCall, tosca.CallCode:
+		to := parameters.CodeAddress
+		frame.To = &to
+	}
+	if kind != tosca.StaticCall && kind != tosca.DelegateCall {
+		value := parameters.Value
+		frame.Value = &value
+	}
+
+	c.tracer.enter(frame)
+	result, err := c.RunContext.Call(kind, parameters)
+	c.tracer.exit(kind, result, err)
+	return result, err
+}
+
+func (c *callTracingContext) EmitLog(log tosca.Log) {
+	c.tracer.log(log)
+	c.RunContext.EmitLog(log)
+}
+
+func (c *callTracingContext) SelfDestruct(address tosca.Address, beneficiary tosca.Address) bool {
+	c.tracer.selfDestruct(address, beneficiary, c.RunContext.GetBalance(address))
+	return c.RunContext.SelfDestruct(address, beneficiary)
+}
+
+func callType(kind tosca.CallKind) string {
+	switch kind {
+	case tosca.Call:
+		return "CALL"
+	case tosca.StaticCall:
+		return "STATICCALL"
+	case tosca.DelegateCall:
+		return "DELEGATECALL"
+	case tosca.CallCode:
+		return "CALLCODE"
+	case tosca.Create:
+		return "CREATE"
+	case tosca.Create2:
+		return "CREATE2"
+	case tosca.EofCreate:
+		return "EOFCREATE"
+	}
+	return strings.ToUpper(kind.String())
+}
+
+type callFrameJSON struct {
+	Type         string         `json:"type"`
+	From         tosca.Address  `json:"from"`
+	To           *tosca.Address `json:"to,omitempty"`
+	Value        string         `json:"value,omitempty"`
+	Gas          string         `json:"gas"`
+	GasUsed      string         `json:"gasUsed"`
+	Input        string         `json:"input"`
+	Output       string         `json:"output,omitempty"`
+	Error        string         `json:"error,omitempty"`
+	RevertReason string         `json:"revertReason,omitempty"`
+	Calls        []CallFrame    `json:"calls,omitempty"`
+	Logs         []CallLog      `json:"logs,omitempty"`
+}
+
+func (f CallFrame) MarshalJSON() ([]byte, error) {
+	res := callFrameJSON{
+		Type:         f.Type,
+		From:         f.From,
+		To:           f.To,
+		Gas:          encodeQuantity(uint64(f.Gas)),
+		GasUsed:      encodeQuantity(uint64(f.GasUsed)),
+		Input:        encodeData(f.Input),
+		Error:        f.Error,
+		RevertReason: f.RevertReason,
+		Calls:        f.Calls,
+		Logs:         f.Logs,
+	}
+	if f.Value != nil {
+		res.Value = encodeValue(*f.Value)
+	}
+	if len(f.Output) > 0 {
+		res.Output = encodeData(f.Output)
+	}
+	return json.Marshal(res)
+}
+
+type callLogJSON struct {
+	Address  tosca.Address `json:"address"`
+	Topics   []string      `json:"topics"`
+	Data     string        `json:"data"`
+	Position string        `json:"position"`
+}
+
+func (l CallLog) MarshalJSON() ([]byte, error) {
+	topics := make([]string, 0, len(l.Topics))
+	for _, topic := range l.Topics {
+		topics = append(topics, encodeData(topic[:]))
+	}
+	return json.Marshal(callLogJSON{
+		Address:  l.Address,
+		Topics:   topics,
+		Data:     encodeData(l.Data),
+		Position: encodeQuantity(uint64(l.Position)),
+	})
+}
+
+// encodeQuantity encodes the given number as a hex string without leading zeros.
+func encodeQuantity(value uint64) string {
+	return fmt.Sprintf("0x%x", value)
+}
+
+// encodeValue encodes the given value as a hex string without leading zeros.
+func encodeValue(value tosca.Value) string {
+	return "0x" + value.ToBig().Text(16)
+}
+
+func encodeData(data []byte) string {
+	return fmt.Sprintf("0x%x", data)
+}
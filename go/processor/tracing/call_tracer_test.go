@@ -0,0 +1,368 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/0xsoniclabs/tosca/go/interpreter/lfvm"
+	"github.com/0xsoniclabs/tosca/go/processor/floria"
+	"github.com/0xsoniclabs/tosca/go/processor/simulation"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	sender = tosca.Address{19: 0x01}
+	first  = tosca.Address{19: 0x42}
+	second = tosca.Address{19: 0x43}
+	third  = tosca.Address{19: 0x44}
+)
+
+// callTracingProcessor is a processor recording the call tree of the most
+// recently executed transaction.
+type callTracingProcessor struct {
+	processor floria.Processor
+	trace     *CallFrame
+}
+
+func (p *callTracingProcessor) Run(
+	blockParameters tosca.BlockParameters,
+	transaction tosca.Transaction,
+	context tosca.TransactionContext,
+) (tosca.Receipt, error) {
+	trace, receipt, err := TraceCalls(p.processor, blockParameters, transaction, context)
+	p.trace = trace
+	return receipt, err
+}
+
+// newEmptyState creates a read-only context representing an empty world state.
+func newEmptyState(t *testing.T) tosca.TransactionContext {
+	state := tosca.NewMockTransactionContext(gomock.NewController(t))
+	state.EXPECT().AccountExists(gomock.Any()).AnyTimes()
+	state.EXPECT().GetBalance(gomock.Any()).AnyTimes()
+	state.EXPECT().GetNonce(gomock.Any()).AnyTimes()
+	state.EXPECT().GetCode(gomock.Any()).AnyTimes()
+	state.EXPECT().GetCodeHash(gomock.Any()).AnyTimes()
+	state.EXPECT().GetCodeSize(gomock.Any()).AnyTimes()
+	state.EXPECT().GetStorage(gomock.Any(), gomock.Any()).AnyTimes()
+	state.EXPECT().HasEmptyStorage(gomock.Any()).Return(true).AnyTimes()
+	return state
+}
+
+// runTraced runs the given transaction on a state containing the given
+// contracts and returns the recorded call tree.
+func runTraced(t *testing.T, transaction tosca.Transaction, contracts map[tosca.Address]tosca.Code) (*CallFrame, tosca.Receipt) {
+	t.Helper()
+	interpreter, err := lfvm.NewInterpreter(lfvm.Config{})
+	require.NoError(t, err)
+	processor := &callTracingProcessor{
+		processor: floria.Processor{
+			Interpreter: interpreter,
+			Config:      floria.Config{EthCompatible: true, OffChainSimulation: true},
+		},
+	}
+	overrides := simulation.StateOverride{}
+	for address, code := range contracts {
+		overrides[address] = simulation.AccountOverride{Code: &code}
+	}
+	blockParameters := tosca.BlockParameters{Revision: tosca.R13_Cancun, GasLimit: 10_000_000}
+	simulator := simulation.Simulator{Processor: processor}
+	receipt, err := simulator.Call(blockParameters, transaction, newEmptyState(t), overrides)
+	require.NoError(t, err)
+	return processor.trace, receipt
+}
+
+func callCode(target tosca.Address) []byte {
+	return []byte{
+		byte(vm.PUSH1), 0, // retSize
+		byte(vm.PUSH1), 0, // retOffset
+		byte(vm.PUSH1), 0, // argsSize
+		byte(vm.PUSH1), 0, // argsOffset
+		byte(vm.PUSH1), 0, // value
+		byte(vm.PUSH1), target[19],
+		byte(vm.GAS),
+		byte(vm.CALL),
+		byte(vm.POP),
+	}
+}
+
+func revertCode(output []byte) tosca.Code {
+	code := tosca.Code{
+		byte(vm.PUSH1), byte(len(output)),
+		byte(vm.PUSH1), 12,
+		byte(vm.PUSH1), 0,
+		byte(vm.CODECOPY),
+		byte(vm.PUSH1), byte(len(output)),
+		byte(vm.PUSH1), 0,
+		byte(vm.REVERT),
+	}
+	return append(code, output...)
+}
+
+// errorOutput returns the ABI encoding of Error(message).
+func errorOutput(message string) []byte {
+	offset := tosca.Word{31: 0x20}
+	length := tosca.Word{31: byte(len(message))}
+	padded := tosca.Word{}
+	copy(padded[:], message)
+	output := crypto.Keccak256([]byte("Error(string)"))[:4]
+	output = append(output, offset[:]...)
+	output = append(output, length[:]...)
+	return append(output, padded[:]...)
+}
+
+func TestCallTracer_RecordsNestedCalls(t *testing.T) {
+	firstCode := append(callCode(second), callCode(third)...)
+	firstCode = append(firstCode,
+		byte(vm.PUSH1), 0,
+		byte(vm.PUSH1), 0,
+		byte(vm.LOG0),
+		byte(vm.STOP),
+	)
+	secondCode := tosca.Code{
+		byte(vm.PUSH1), 1, // topic
+		byte(vm.PUSH1), 0, // size
+		byte(vm.PUSH1), 0, // offset
+		byte(vm.LOG1),
+		byte(vm.STOP),
+	}
+
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &first,
+		GasLimit:  100_000,
+		Input:     tosca.Data{1, 2, 3},
+	}
+	trace, receipt := runTraced(t, transaction, map[tosca.Address]tosca.Code{
+		first:  firstCode,
+		second: secondCode,
+		third:  revertCode(errorOutput("boom")),
+	})
+	require.True(t, receipt.Success)
+	require.NotNil(t, trace)
+
+	require.Equal(t, "CALL", trace.Type)
+	require.Equal(t, sender, trace.From)
+	require.Equal(t, first, *trace.To)
+	require.Equal(t, tosca.Gas(100_000), trace.Gas)
+	require.Equal(t, receipt.GasUsed, trace.GasUsed)
+	require.Equal(t, tosca.Data{1, 2, 3}, trace.Input)
+	require.Empty(t, trace.Error)
+	require.Len(t, trace.Logs, 1)
+	require.Equal(t, first, trace.Logs[0].Address)
+	require.Empty(t, trace.Logs[0].Topics)
+	require.Equal(t, 2, trace.Logs[0].Position)
+
+	require.Len(t, trace.Calls, 2)
+	call := trace.Calls[0]
+	require.Equal(t, "CALL", call.Type)
+	require.Equal(t, first, call.From)
+	require.Equal(t, second, *call.To)
+	require.Empty(t, call.Error)
+	require.Len(t, call.Logs, 1)
+	require.Equal(t, second, call.Logs[0].Address)
+	require.Equal(t, []tosca.Hash{{31: 1}}, call.Logs[0].Topics)
+	require.Equal(t, 0, call.Logs[0].Position)
+
+	call = trace.Calls[1]
+	require.Equal(t, third, *call.To)
+	require.Equal(t, "execution reverted", call.Error)
+	require.Equal(t, "boom", call.RevertReason)
+	require.Equal(t, tosca.Data(errorOutput("boom")), call.Output)
+	require.Less(t, call.GasUsed, call.Gas)
+}
+
+func TestCallTracer_LogsOfFailedCallsAreCleared(t *testing.T) {
+	code := tosca.Code{
+		byte(vm.PUSH1), 0,
+		byte(vm.PUSH1), 0,
+		byte(vm.LOG0),
+		byte(vm.PUSH1), 0,
+		byte(vm.PUSH1), 0,
+		byte(vm.REVERT),
+	}
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &first,
+		GasLimit:  100_000,
+	}
+	trace, receipt := runTraced(t, transaction, map[tosca.Address]tosca.Code{first: code})
+	require.False(t, receipt.Success)
+	require.Equal(t, "execution reverted", trace.Error)
+	require.Empty(t, trace.RevertReason)
+	require.Empty(t, trace.Logs)
+}
+
+func TestCallTracer_InputIsNotAffectedByLaterExecutions(t *testing.T) {
+	// Interpreters recycle the memory holding call inputs once their
+	// execution is complete, so later executions overwrite the buffer.
+	context := tosca.NewMockRunContext(gomock.NewController(t))
+	context.EXPECT().Call(tosca.Call, gomock.Any()).Return(tosca.CallResult{Success: true}, nil).Times(2)
+
+	buffer := bytes.Repeat([]byte{0xaa}, 32)
+	tracer := NewCallTracer()
+	_, err := tracer.Decorate(context).Call(tosca.Call, tosca.CallParameters{Input: buffer})
+	require.NoError(t, err)
+
+	copy(buffer, bytes.Repeat([]byte{0xbb}, 32))
+	_, err = NewCallTracer().Decorate(context).Call(tosca.Call, tosca.CallParameters{Input: buffer})
+	require.NoError(t, err)
+
+	require.Equal(t, tosca.Data(bytes.Repeat([]byte{0xaa}, 32)), tracer.Result().Input)
+}
+
+func TestCallTracer_CreateReportsCreatedAddress(t *testing.T) {
+	initCode := tosca.Code{
+		byte(vm.PUSH1), 0,
+		byte(vm.PUSH1), 0,
+		byte(vm.RETURN),
+	}
+	transaction := tosca.Transaction{
+		Sender:   sender,
+		GasLimit: 100_000,
+		Input:    tosca.Data(initCode),
+	}
+	trace, receipt := runTraced(t, transaction, nil)
+	require.True(t, receipt.Success)
+	require.Equal(t, "CREATE", trace.Type)
+	require.Equal(t, *receipt.ContractAddress, *trace.To)
+}
+
+func TestCallTracer_SelfDestructIsRecorded(t *testing.T) {
+	code := tosca.Code{
+		byte(vm.PUSH1), second[19],
+		byte(vm.SELFDESTRUCT),
+	}
+	transaction := tosca.Transaction{
+		Sender:    sender,
+		Recipient: &first,
+		GasLimit:  100_000,
+	}
+	trace, _ := runTraced(t, transaction, map[tosca.Address]tosca.Code{first: code})
+	require.Len(t, trace.Calls, 1)
+	require.Equal(t, "SELFDESTRUCT", trace.Calls[0].Type)
+	require.Equal(t, first, trace.Calls[0].From)
+	require.Equal(t, second, *trace.Calls[0].To)
+}
+
+func TestCallTracer_ErrorsAreRecorded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	context := tosca.NewMockRunContext(ctrl)
+	injectedError := fmt.Errorf("injected error")
+	context.EXPECT().Call(tosca.StaticCall, gomock.Any()).Return(tosca.CallResult{}, injectedError)
+
+	tracer := NewCallTracer()
+	_, err := tracer.Decorate(context).Call(tosca.StaticCall, tosca.CallParameters{Gas: 10})
+	require.ErrorIs(t, err, injectedError)
+	require.Equal(t, "injected error", tracer.Result().Error)
+	require.Nil(t, tracer.Result().Value)
+	require.Equal(t, tosca.Gas(10), tracer.Result().GasUsed)
+}
+
+func TestCallTracer_RevertWithoutGasAndOutputIsReportedAsRevert(t *testing.T) {
+	// The callee consumes all its 6 gas units before reverting without
+	// output, which is indistinguishable from a failure by the result alone.
+	caller := []byte{
+		byte(vm.PUSH1), 0, // retSize
+		byte(vm.PUSH1), 0, // retOffset
+		byte(vm.PUSH1), 0, // argsSize
+		byte(vm.PUSH1), 0, // argsOffset
+		byte(vm.PUSH1), 0, // value
+		byte(vm.PUSH1), second[19],
+		byte(vm.PUSH1), 6, // gas
+		byte(vm.CALL),
+		byte(vm.POP),
+	}
+	callee := tosca.Code{
+		byte(vm.PUSH1), 0,
+		byte(vm.PUSH1), 0,
+		byte(vm.REVERT),
+	}
+	transaction := tosca.Transaction{Sender: sender, Recipient: &first, GasLimit: 100_000}
+	trace, _ := runTraced(t, transaction, map[tosca.Address]tosca.Code{first: caller, second: callee})
+
+	require.Len(t, trace.Calls, 1)
+	require.Equal(t, tosca.Gas(6), trace.Calls[0].GasUsed)
+	require.Empty(t, trace.Calls[0].Output)
+	require.Equal(t, "execution reverted", trace.Calls[0].Error)
+}
+
+func TestCallTracer_FailuresAreNotReportedAsRevert(t *testing.T) {
+	transaction := tosca.Transaction{Sender: sender, Recipient: &first, GasLimit: 100_000}
+	trace, _ := runTraced(t, transaction, map[tosca.Address]tosca.Code{
+		first:  callCode(second),
+		second: {byte(vm.INVALID)},
+	})
+
+	require.Len(t, trace.Calls, 1)
+	require.Equal(t, "execution failed", trace.Calls[0].Error)
+}
+
+func TestCallTracer_JSONMatchesGethCallTracerFormat(t *testing.T) {
+	value := tosca.NewValue(256)
+	frame := CallFrame{
+		Type:    "CALL",
+		From:    tosca.Address{19: 1},
+		To:      &tosca.Address{19: 2},
+		Value:   &value,
+		Gas:     100,
+		GasUsed: 42,
+		Input:   tosca.Data{0xab},
+		Calls: []CallFrame{{
+			Type:         "STATICCALL",
+			From:         tosca.Address{19: 2},
+			To:           &tosca.Address{19: 3},
+			Error:        "execution reverted",
+			RevertReason: "boom",
+		}},
+		Logs: []CallLog{{
+			Address:  tosca.Address{19: 2},
+			Topics:   []tosca.Hash{{31: 1}},
+			Data:     tosca.Data{0xcd},
+			Position: 1,
+		}},
+	}
+
+	data, err := json.Marshal(frame)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"type": "CALL",
+		"from": "0x0000000000000000000000000000000000000001",
+		"to": "0x0000000000000000000000000000000000000002",
+		"value": "0x100",
+		"gas": "0x64",
+		"gasUsed": "0x2a",
+		"input": "0xab",
+		"calls": [{
+			"type": "STATICCALL",
+			"from": "0x0000000000000000000000000000000000000002",
+			"to": "0x0000000000000000000000000000000000000003",
+			"gas": "0x0",
+			"gasUsed": "0x0",
+			"input": "0x",
+			"error": "execution reverted",
+			"revertReason": "boom"
+		}],
+		"logs": [{
+			"address": "0x0000000000000000000000000000000000000002",
+			"topics": ["0x0000000000000000000000000000000000000000000000000000000000000001"],
+			"data": "0xcd",
+			"position": "0x1"
+		}]
+	}`, string(data))
+}
@@ -0,0 +1,279 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+)
+
+// Account summarizes the state of an account as reported by the prestate
+// tracer. Its JSON encoding matches the account format of geth's
+// prestateTracer. In diff mode, unmodified fields are left empty.
+type Account struct {
+	Balance *tosca.Value
+	Nonce   uint64
+	Code    tosca.Code
+	Storage map[tosca.Key]tosca.Word
+}
+
+// StateDiff lists the pre- and post-values of all accounts modified by a
+// transaction. Its JSON encoding matches the output of geth's prestateTracer
+// in diff mode.
+type StateDiff struct {
+	Pre  map[tosca.Address]Account `json:"pre"`
+	Post map[tosca.Address]Account `json:"post"`
+}
+
+// PrestateTracer is a tosca.TransactionContext wrapper recording the state of
+// every account and storage slot touched by a transaction before it is first
+// read or modified. After the transaction has been executed, the recorded
+// values can be obtained as a prestate or, compared to the current values of
+// the wrapped context, as a state diff.
+type PrestateTracer struct {
+	tosca.TransactionContext
+	revision tosca.Revision
+	pre      map[tosca.Address]*prestateAccount
+}
+
+type prestateAccount struct {
+	Account
+	exists bool
+}
+
+// NewPrestateTracer creates a tracer recording all state accessed through the
+// given context. The revision is required to determine which self-destructed
+// accounts get deleted at the end of the transaction.
+func NewPrestateTracer(context tosca.TransactionContext, revision tosca.Revision) *PrestateTracer {
+	return &PrestateTracer{
+		TransactionContext: context,
+		revision:           revision,
+		pre:                map[tosca.Address]*prestateAccount{},
+	}
+}
+
+// Prestate returns the recorded state of all touched accounts before the
+// transaction, including all accessed storage slots.
+func (t *PrestateTracer) Prestate() map[tosca.Address]Account {
+	res := make(map[tosca.Address]Account, len(t.pre))
+	for address, account := range t.pre {
+		res[address] = account.clone()
+	}
+	return res
+}
+
+// Diff compares the recorded state of all touched accounts to their current
+// state and returns the pre- and post-values of modified accounts. Pre-values
+// are omitted for accounts that did not exist before the transaction, and
+// post-values are omitted for accounts deleted by the transaction. Only
+// modified storage slots are reported, and post-values only include modified
+// fields.
+func (t *PrestateTracer) Diff() StateDiff {
+	res := StateDiff{
+		Pre:  map[tosca.Address]Account{},
+		Post: map[tosca.Address]Account{},
+	}
+	for address, account := range t.pre {
+		pre := account.clone()
+		pre.Storage = map[tosca.Key]tosca.Word{}
+		post := Account{Storage: map[tosca.Key]tosca.Word{}}
+
+		deleted := t.isDeleted(address)
+		modified := deleted
+
+		balance := t.TransactionContext.GetBalance(address)
+		if balance != *account.Balance {
+			modified = true
+			post.Balance = &balance
+		}
+		if nonce := t.TransactionContext.GetNonce(address); nonce != account.Nonce {
+			modified = true
+			post.Nonce = nonce
+		}
+		if code := t.TransactionContext.GetCode(address); !bytes.Equal(code, account.Code) {
+			modified = true
+			post.Code = code
+		}
+		for key, value := range account.Storage {
+			current := t.TransactionContext.GetStorage(address, key)
+			if deleted {
+				current = tosca.Word{}
+			}
+			if current == value {
+				continue
+			}
+			modified = true
+			if value != (tosca.Word{}) {
+				pre.Storage[key] = value
+			}
+			if current != (tosca.Word{}) {
+				post.Storage[key] = current
+			}
+		}
+
+		if !modified {
+			continue
+		}
+		if account.exists {
+			res.Pre[address] = pre
+		}
+		if !deleted {
+			res.Post[address] = post
+		}
+	}
+	return res
+}
+
+// isDeleted determines whether the given account is removed at the end of the
+// transaction. Since EIP-6780, only accounts created within the transaction
+// are deleted by a self-destruct.
+func (t *PrestateTracer) isDeleted(address tosca.Address) bool {
+	if !t.TransactionContext.HasSelfDestructed(address) {
+		return false
+	}
+	return t.revision < tosca.R13_Cancun || t.TransactionContext.IsNewContract(address)
+}
+
+// touch records the state of the given account if it is accessed for the first time.
+func (t *PrestateTracer) touch(address tosca.Address) *prestateAccount {
+	if account, found := t.pre[address]; found {
+		return account
+	}
+	balance := t.TransactionContext.GetBalance(address)
+	account := &prestateAccount{
+		Account: Account{
+			Balance: &balance,
+			Nonce:   t.TransactionContext.GetNonce(address),
+			Code:    t.TransactionContext.GetCode(address),
+			Storage: map[tosca.Key]tosca.Word{},
+		},
+		exists: t.TransactionContext.AccountExists(address),
+	}
+	t.pre[address] = account
+	return account
+}
+
+// touchSlot records the value of the given storage slot if it is accessed for the first time.
+func (t *PrestateTracer) touchSlot(address tosca.Address, key tosca.Key) {
+	account := t.touch(address)
+	if _, found := account.Storage[key]; !found {
+		account.Storage[key] = t.TransactionContext.GetStorage(address, key)
+	}
+}
+
+func (t *PrestateTracer) AccountExists(address tosca.Address) bool {
+	t.touch(address)
+	return t.TransactionContext.AccountExists(address)
+}
+
+func (t *PrestateTracer) CreateContract(address tosca.Address) {
+	t.touch(address)
+	t.TransactionContext.CreateContract(address)
+}
+
+func (t *PrestateTracer) GetBalance(address tosca.Address) tosca.Value {
+	t.touch(address)
+	return t.TransactionContext.GetBalance(address)
+}
+
+func (t *PrestateTracer) SetBalance(address tosca.Address, value tosca.Value) {
+	t.touch(address)
+	t.TransactionContext.SetBalance(address, value)
+}
+
+func (t *PrestateTracer) GetNonce(address tosca.Address) uint64 {
+	t.touch(address)
+	return t.TransactionContext.GetNonce(address)
+}
+
+func (t *PrestateTracer) SetNonce(address tosca.Address, nonce uint64) {
+	t.touch(address)
+	t.TransactionContext.SetNonce(address, nonce)
+}
+
+func (t *PrestateTracer) GetCode(address tosca.Address) tosca.Code {
+	t.touch(address)
+	return t.TransactionContext.GetCode(address)
+}
+
+func (t *PrestateTracer) GetCodeHash(address tosca.Address) tosca.Hash {
+	t.touch(address)
+	return t.TransactionContext.GetCodeHash(address)
+}
+
+func (t *PrestateTracer) GetCodeSize(address tosca.Address) int {
+	t.touch(address)
+	return t.TransactionContext.GetCodeSize(address)
+}
+
+func (t *PrestateTracer) SetCode(address tosca.Address, code tosca.Code) {
+	t.touch(address)
+	t.TransactionContext.SetCode(address, code)
+}
+
+func (t *PrestateTracer) HasEmptyStorage(address tosca.Address) bool {
+	t.touch(address)
+	return t.TransactionContext.HasEmptyStorage(address)
+}
+
+func (t *PrestateTracer) GetStorage(address tosca.Address, key tosca.Key) tosca.Word {
+	t.touchSlot(address, key)
+	return t.TransactionContext.GetStorage(address, key)
+}
+
+func (t *PrestateTracer) SetStorage(address tosca.Address, key tosca.Key, value tosca.Word) tosca.StorageStatus {
+	t.touchSlot(address, key)
+	return t.TransactionContext.SetStorage(address, key, value)
+}
+
+func (t *PrestateTracer) GetCommittedStorage(address tosca.Address, key tosca.Key) tosca.Word {
+	t.touchSlot(address, key)
+	return t.TransactionContext.GetCommittedStorage(address, key)
+}
+
+func (t *PrestateTracer) SelfDestruct(address tosca.Address, beneficiary tosca.Address) bool {
+	t.touch(address)
+	t.touch(beneficiary)
+	return t.TransactionContext.SelfDestruct(address, beneficiary)
+}
+
+func (a *prestateAccount) clone() Account {
+	res := a.Account
+	res.Storage = maps.Clone(a.Storage)
+	return res
+}
+
+type accountJSON struct {
+	Balance string            `json:"balance,omitempty"`
+	Nonce   uint64            `json:"nonce,omitempty"`
+	Code    string            `json:"code,omitempty"`
+	Storage map[string]string `json:"storage,omitempty"`
+}
+
+func (a Account) MarshalJSON() ([]byte, error) {
+	res := accountJSON{Nonce: a.Nonce}
+	if a.Balance != nil {
+		res.Balance = encodeValue(*a.Balance)
+	}
+	if len(a.Code) > 0 {
+		res.Code = encodeData(a.Code)
+	}
+	if len(a.Storage) > 0 {
+		res.Storage = make(map[string]string, len(a.Storage))
+		for key, value := range a.Storage {
+			res.Storage[encodeData(key[:])] = encodeData(value[:])
+		}
+	}
+	return json.Marshal(res)
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tracing
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/0xsoniclabs/tosca/go/interpreter/lfvm"
+	"github.com/0xsoniclabs/tosca/go/processor/floria"
+	"github.com/0xsoniclabs/tosca/go/processor/simulation"
+	"github.com/0xsoniclabs/tosca/go/tosca"
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// prestateTracingProcessor is a processor recording the prestate of the most
+// recently executed transaction.
+type prestateTracingProcessor struct {
+	processor floria.Processor
+	tracer    *PrestateTracer
+}
+
+func (p *prestateTracingProcessor) Run(
+	blockParameters tosca.BlockParameters,
+	transaction tosca.Transaction,
+	context tosca.TransactionContext,
+) (tosca.Receipt, error) {
+	p.tracer = NewPrestateTracer(context, blockParameters.Revision)
+	return p.processor.Run(blockParameters, transaction, p.tracer)
+}
+
+// runWithPrestateTracer runs the given transaction on the given state and
+// returns the tracer used for recording the accessed state.
+func runWithPrestateTracer(t *testing.T, transaction tosca.Transaction, state simulation.StateOverride) *PrestateTracer {
+	t.Helper()
+	interpreter, err := lfvm.NewInterpreter(lfvm.Config{})
+	require.NoError(t, err)
+	processor := &prestateTracingProcessor{
+		processor: floria.Processor{
+			Interpreter: interpreter,
+			Config:      floria.Config{EthCompatible: true, OffChainSimulation: true},
+		},
+	}
+	blockParameters := tosca.BlockParameters{Revision: tosca.R13_Cancun, GasLimit: 10_000_000}
+	simulator := simulation.Simulator{Processor: processor}
+	receipt, err := simulator.Call(blockParameters, transaction, newEmptyState(t), state)
+	require.NoError(t, err)
+	require.True(t, receipt.Success)
+	return processor.tracer
+}
+
+func TestPrestateTracer_RecordsTouchedState(t *testing.T) {
+	balance := tosca.NewValue(1000)
+	code := tosca.Code{
+		byte(vm.PUSH1), 1,
+		byte(vm.SLOAD),
+		byte(vm.PUSH1), 2,
+		byte(vm.SSTORE),
+		byte(vm.PUSH1), second[19],
+		byte(vm.BALANCE),
+		byte(vm.STOP),
+	}
+	nonce := uint64(3)
+	tracer := runWithPrestateTracer(t,
+		tosca.Transaction{
+			Sender:    sender,
+			Recipient: &first,
+			Nonce:     nonce,
+			Value:     tosca.NewValue(10),
+			GasLimit:  100_000,
+		},
+		simulation.StateOverride{
+			sender: {Balance: &balance, Nonce: &nonce},
+			first:  {Code: &code, State: map[tosca.Key]tosca.Word{{31: 1}: {31: 7}}},
+		},
+	)
+
+	zero := tosca.Value{}
+	require.Equal(t, map[tosca.Address]Account{
+		sender: {Balance: &balance, Nonce: nonce, Storage: map[tosca.Key]tosca.Word{}},
+		first: {Balance: &zero, Code: code, Storage: map[tosca.Key]tosca.Word{
+			{31: 1}: {31: 7},
+			{31: 2}: {},
+		}},
+		second: {Balance: &zero, Storage: map[tosca.Key]tosca.Word{}},
+		// the coinbase is touched for paying the transaction fees
+		{}: {Balance: &zero, Storage: map[tosca.Key]tosca.Word{}},
+	}, tracer.Prestate())
+}
+
+func TestPrestateTracer_DiffListsModifiedStateOnly(t *testing.T) {
+	balance := tosca.NewValue(1000)
+	code := tosca.Code{
+		byte(vm.PUSH1), 1,
+		byte(vm.SLOAD),
+		byte(vm.PUSH1), 2,
+		byte(vm.SSTORE),
+		byte(vm.PUSH1), 0,
+		byte(vm.PUSH1), 1,
+		byte(vm.SSTORE),
+		byte(vm.PUSH1), second[19],
+		byte(vm.BALANCE),
+		byte(vm.STOP),
+	}
+	tracer := runWithPrestateTracer(t,
+		tosca.Transaction{
+			Sender:    sender,
+			Recipient: &first,
+			Value:     tosca.NewValue(10),
+			GasLimit:  100_000,
+		},
+		simulation.StateOverride{
+			sender: {Balance: &balance},
+			first: {Code: &code, State: map[tosca.Key]tosca.Word{
+				{31: 1}: {31: 7},
+				{31: 3}: {31: 9},
+			}},
+		},
+	)
+
+	zero := tosca.Value{}
+	senderBalance := tosca.NewValue(990)
+	recipientBalance := tosca.NewValue(10)
+	require.Equal(t, StateDiff{
+		Pre: map[tosca.Address]Account{
+			sender: {Balance: &balance, Storage: map[tosca.Key]tosca.Word{}},
+			first: {Balance: &zero, Code: code, Storage: map[tosca.Key]tosca.Word{
+				{31: 1}: {31: 7},
+			}},
+		},
+		Post: map[tosca.Address]Account{
+			sender: {Balance: &senderBalance, Nonce: 1, Storage: map[tosca.Key]tosca.Word{}},
+			first: {Balance: &recipientBalance, Storage: map[tosca.Key]tosca.Word{
+				{31: 2}: {31: 7},
+			}},
+		},
+	}, tracer.Diff())
+}
+
+func TestPrestateTracer_DiffOmitsPostStateOfDeletedAccounts(t *testing.T) {
+	tests := map[string]struct {
+		revision    tosca.Revision
+		newContract bool
+		deleted     bool
+	}{
+		"before Cancun":                {revision: tosca.R12_Shanghai, deleted: true},
+		"Cancun with existing account": {revision: tosca.R13_Cancun, deleted: false},
+		"Cancun with new contract":     {revision: tosca.R13_Cancun, newContract: true, deleted: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			context := tosca.NewMockTransactionContext(ctrl)
+			balance := tosca.NewValue(5)
+			context.EXPECT().GetBalance(first).Return(balance).AnyTimes()
+			context.EXPECT().GetNonce(first).Return(uint64(1)).AnyTimes()
+			context.EXPECT().GetCode(first).Return(tosca.Code{1}).AnyTimes()
+			context.EXPECT().AccountExists(first).Return(true)
+			context.EXPECT().HasSelfDestructed(first).Return(true)
+			context.EXPECT().IsNewContract(first).Return(test.newContract).AnyTimes()
+
+			tracer := NewPrestateTracer(context, test.revision)
+			tracer.GetBalance(first)
+			diff := tracer.Diff()
+
+			_, inPre := diff.Pre[first]
+			_, inPost := diff.Post[first]
+			require.Equal(t, test.deleted, inPre)
+			require.False(t, inPost)
+		})
+	}
+}
+
+func TestPrestateTracer_AccountJSONMatchesGethPrestateTracerFormat(t *testing.T) {
+	balance := tosca.NewValue(256)
+	data, err := json.Marshal(StateDiff{
+		Pre: map[tosca.Address]Account{
+			{19: 1}: {
+				Balance: &balance,
+				Nonce:   2,
+				Code:    tosca.Code{0x60, 0x00},
+				Storage: map[tosca.Key]tosca.Word{{31: 1}: {31: 2}},
+			},
+		},
+		Post: map[tosca.Address]Account{
+			{19: 1}: {Nonce: 3},
+		},
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"pre": {
+			"0x0000000000000000000000000000000000000001": {
+				"balance": "0x100",
+				"nonce": 2,
+				"code": "0x6000",
+				"storage": {
+					"0x0000000000000000000000000000000000000000000000000000000000000001": "0x0000000000000000000000000000000000000000000000000000000000000002"
+				}
+			}
+		},
+		"post": {
+			"0x0000000000000000000000000000000000000001": {
+				"nonce": 3
+			}
+		}
+	}`, string(data))
+}
@@ -0,0 +1,356 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+// Package eof implements the parsing and validation of containers of the EVM
+// Object Format (EOF) as specified by https://eips.ethereum.org/EIPS/eip-3540
+// and its follow-up proposals. EOF code is only executed in the
+// tosca.R98_Experimental revision.
+package eof
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/0xsoniclabs/tosca/go/tosca"
+)
+
+const (
+	// ErrInvalidHeader is reported for containers with a malformed header.
+	ErrInvalidHeader = tosca.ConstError("invalid EOF header")
+	// ErrInvalidBody is reported for containers whose body does not match
+	// the section sizes declared in the header.
+	ErrInvalidBody = tosca.ConstError("invalid EOF body")
+	// ErrInvalidCode is reported for containers with invalid code sections.
+	ErrInvalidCode = tosca.ConstError("invalid EOF code")
+	// ErrInvalidContainer is reported for containers with an invalid
+	// structure, such as unreachable sections or malformed sub-containers.
+	ErrInvalidContainer = tosca.ConstError("invalid EOF container")
+)
+
+const (
+	// Version is the only supported EOF version.
+	Version = 1
+
+	// NonReturning is the number of outputs of functions not returning to
+	// their caller.
+	NonReturning = 0x80
+
+	// MaxCodeSections is the maximum number of code sections of a container.
+	MaxCodeSections = 1024
+	// MaxContainerSections is the maximum number of sub-containers.
+	MaxContainerSections = 256
+	// MaxStackHeight is the maximum stack height of a function.
+	MaxStackHeight = 1023
+
+	maxFunctionInputs  = 127
+	maxFunctionOutputs = 127
+)
+
+// Section kinds of the container header.
+const (
+	kindTypes      = 0x01
+	kindCode       = 0x02
+	kindContainer  = 0x03
+	kindData       = 0xff
+	kindTerminator = 0x00
+)
+
+// magic is the prefix of all EOF containers. Since 0xEF is not a valid
+// instruction, no legacy contract can start with it (EIP-3541).
+var magic = [2]byte{0xEF, 0x00}
+
+// Container is a parsed EOF container.
+type Container struct {
+	Types      []FunctionType // < the type of each code section
+	Code       [][]byte       // < the code sections, at least one
+	Containers [][]byte       // < the encoded sub-containers
+	Data       []byte         // < the data section, may be shorter than DataSize
+	DataSize   uint16         // < the size of the data section declared in the header
+}
+
+// FunctionType describes the stack signature of a code section.
+type FunctionType struct {
+	Inputs           uint8  // < the number of stack items consumed
+	Outputs          uint8  // < the number of stack items produced, NonReturning if the function does not return
+	MaxStackIncrease uint16 // < the maximum stack growth above the inputs
+}
+
+// IsReturning reports whether a function of this type may return.
+func (t FunctionType) IsReturning() bool {
+	return t.Outputs != NonReturning
+}
+
+// HasMagic reports whether the given code starts with the EOF prefix. Codes
+// with this prefix are EOF containers or invalid code.
+func HasMagic(code []byte) bool {
+	return len(code) >= len(magic) && code[0] == magic[0] && code[1] == magic[1]
+}
+
+// Parse decodes an EOF container and checks the well-formedness of its header
+// and its type section. The code sections are not validated, see Validate.
+// The data section may be truncated, which is only permitted for containers
+// deployed by RETURNCONTRACT.
+func Parse(code []byte) (*Container, error) {
+	header, err := parseHeader(code)
+	if err != nil {
+		return nil, err
+	}
+
+	pos := header.bodyOffset
+	res := &Container{DataSize: header.dataSize}
+
+	res.Types = make([]FunctionType, len(header.codeSizes))
+	for i := range res.Types {
+		res.Types[i] = FunctionType{
+			Inputs:           code[pos],
+			Outputs:          code[pos+1],
+			MaxStackIncrease: binary.BigEndian.Uint16(code[pos+2:]),
+		}
+		pos += 4
+	}
+	if err := checkTypes(res.Types); err != nil {
+		return nil, err
+	}
+
+	res.Code = make([][]byte, len(header.codeSizes))
+	for i, size := range header.codeSizes {
+		res.Code[i] = code[pos : pos+size]
+		pos += size
+	}
+	if len(header.containerSizes) > 0 {
+		res.Containers = make([][]byte, len(header.containerSizes))
+	}
+	for i, size := range header.containerSizes {
+		res.Containers[i] = code[pos : pos+size]
+		pos += size
+	}
+	res.Data = code[pos:]
+	return res, nil
+}
+
+// header summarizes the section sizes declared in a container header.
+type header struct {
+	codeSizes      []int
+	containerSizes []int
+	dataSize       uint16
+	dataSizeOffset int // < position of the data size within the header
+	bodyOffset     int
+}
+
+func parseHeader(code []byte) (header, error) {
+	res := header{}
+	if !HasMagic(code) {
+		return res, fmt.Errorf("%w: missing magic", ErrInvalidHeader)
+	}
+	if len(code) < 3 || code[2] != Version {
+		return res, fmt.Errorf("%w: unsupported version", ErrInvalidHeader)
+	}
+	pos := 3
+
+	readByte := func() (byte, error) {
+		if pos >= len(code) {
+			return 0, fmt.Errorf("%w: truncated", ErrInvalidHeader)
+		}
+		pos++
+		return code[pos-1], nil
+	}
+	readUint16 := func() (int, error) {
+		if pos+2 > len(code) {
+			return 0, fmt.Errorf("%w: truncated", ErrInvalidHeader)
+		}
+		pos += 2
+		return int(binary.BigEndian.Uint16(code[pos-2:])), nil
+	}
+	readUint32 := func() (int, error) {
+		if pos+4 > len(code) {
+			return 0, fmt.Errorf("%w: truncated", ErrInvalidHeader)
+		}
+		pos += 4
+		return int(binary.BigEndian.Uint32(code[pos-4:])), nil
+	}
+	expectKind := func(kind byte) error {
+		got, err := readByte()
+		if err != nil {
+			return err
+		}
+		if got != kind {
+			return fmt.Errorf("%w: expected section kind 0x%02x, got 0x%02x", ErrInvalidHeader, kind, got)
+		}
+		return nil
+	}
+
+	// types section
+	if err := expectKind(kindTypes); err != nil {
+		return res, err
+	}
+	typesSize, err := readUint16()
+	if err != nil {
+		return res, err
+	}
+
+	// code sections
+	if err := expectKind(kindCode); err != nil {
+		return res, err
+	}
+	numCodeSections, err := readUint16()
+	if err != nil {
+		return res, err
+	}
+	if numCodeSections == 0 || numCodeSections > MaxCodeSections {
+		return res, fmt.Errorf("%w: invalid number of code sections: %d", ErrInvalidHeader, numCodeSections)
+	}
+	if typesSize != 4*numCodeSections {
+		return res, fmt.Errorf("%w: types section size %d does not match %d code sections", ErrInvalidHeader, typesSize, numCodeSections)
+	}
+	res.codeSizes = make([]int, numCodeSections)
+	for i := range res.codeSizes {
+		if res.codeSizes[i], err = readUint16(); err != nil {
+			return res, err
+		}
+		if res.codeSizes[i] == 0 {
+			return res, fmt.Errorf("%w: empty code section %d", ErrInvalidHeader, i)
+		}
+	}
+
+	// optional container sections
+	if pos < len(code) && code[pos] == kindContainer {
+		pos++
+		numContainers, err := readUint16()
+		if err != nil {
+			return res, err
+		}
+		if numContainers == 0 || numContainers > MaxContainerSections {
+			return res, fmt.Errorf("%w: invalid number of container sections: %d", ErrInvalidHeader, numContainers)
+		}
+		res.containerSizes = make([]int, numContainers)
+		for i := range res.containerSizes {
+			if res.containerSizes[i], err = readUint32(); err != nil {
+				return res, err
+			}
+			if res.containerSizes[i] == 0 {
+				return res, fmt.Errorf("%w: empty container section %d", ErrInvalidHeader, i)
+			}
+		}
+	}
+
+	// data section
+	if err := expectKind(kindData); err != nil {
+		return res, err
+	}
+	res.dataSizeOffset = pos
+	dataSize, err := readUint16()
+	if err != nil {
+		return res, err
+	}
+	res.dataSize = uint16(dataSize)
+	if err := expectKind(kindTerminator); err != nil {
+		return res, err
+	}
+	res.bodyOffset = pos
+
+	// The body has to cover all sections but the data section, which may be
+	// truncated. Trailing bytes are not permitted.
+	size := typesSize
+	for _, s := range res.codeSizes {
+		size += s
+	}
+	for _, s := range res.containerSizes {
+		size += s
+	}
+	if body := len(code) - pos; body < size || body > size+dataSize {
+		return res, fmt.Errorf("%w: body size %d does not match declared sections", ErrInvalidBody, body)
+	}
+	return res, nil
+}
+
+func checkTypes(types []FunctionType) error {
+	if types[0].Inputs != 0 || types[0].IsReturning() {
+		return fmt.Errorf("%w: first code section must have 0 inputs and be non-returning", ErrInvalidBody)
+	}
+	for i, t := range types {
+		if t.Inputs > maxFunctionInputs {
+			return fmt.Errorf("%w: too many inputs of section %d: %d", ErrInvalidBody, i, t.Inputs)
+		}
+		if t.Outputs > maxFunctionOutputs && t.IsReturning() {
+			return fmt.Errorf("%w: too many outputs of section %d: %d", ErrInvalidBody, i, t.Outputs)
+		}
+		if int(t.Inputs)+int(t.MaxStackIncrease) > MaxStackHeight {
+			return fmt.Errorf("%w: max stack height of section %d exceeds limit", ErrInvalidBody, i)
+		}
+	}
+	return nil
+}
+
+// Bytes encodes the container. The data size declared in the header is taken
+// from DataSize, which allows to encode containers with truncated data.
+func (c *Container) Bytes() []byte {
+	res := append([]byte{}, magic[:]...)
+	res = append(res, Version)
+	res = append(res, kindTypes)
+	res = binary.BigEndian.AppendUint16(res, uint16(4*len(c.Types)))
+	res = append(res, kindCode)
+	res = binary.BigEndian.AppendUint16(res, uint16(len(c.Code)))
+	for _, code := range c.Code {
+		res = binary.BigEndian.AppendUint16(res, uint16(len(code)))
+	}
+	if len(c.Containers) > 0 {
+		res = append(res, kindContainer)
+		res = binary.BigEndian.AppendUint16(res, uint16(len(c.Containers)))
+		for _, container := range c.Containers {
+			res = binary.BigEndian.AppendUint32(res, uint32(len(container)))
+		}
+	}
+	res = append(res, kindData)
+	res = binary.BigEndian.AppendUint16(res, c.DataSize)
+	res = append(res, kindTerminator)
+
+	for _, t := range c.Types {
+		res = append(res, t.Inputs, t.Outputs)
+		res = binary.BigEndian.AppendUint16(res, t.MaxStackIncrease)
+	}
+	for _, code := range c.Code {
+		res = append(res, code...)
+	}
+	for _, container := range c.Containers {
+		res = append(res, container...)
+	}
+	return append(res, c.Data...)
+}
+
+// AppendAuxData produces the container deployed by RETURNCONTRACT by appending
+// the given auxiliary data to the data section of the given container and
+// updating the data size in its header. The resulting data section must not
+// be truncated and must not exceed the maximum size of a data section.
+func AppendAuxData(container []byte, aux []byte) ([]byte, error) {
+	header, err := parseHeader(container)
+	if err != nil {
+		return nil, err
+	}
+	dataSize := len(container) - header.bodyOffset - 4*len(header.codeSizes) + len(aux)
+	for _, s := range header.codeSizes {
+		dataSize -= s
+	}
+	for _, s := range header.containerSizes {
+		dataSize -= s
+	}
+	if dataSize > math.MaxUint16 {
+		return nil, fmt.Errorf("%w: data section exceeds maximum size", ErrInvalidContainer)
+	}
+	if dataSize < int(header.dataSize) {
+		return nil, fmt.Errorf("%w: data section remains truncated", ErrInvalidContainer)
+	}
+
+	res := make([]byte, 0, len(container)+len(aux))
+	res = append(res, container...)
+	res = append(res, aux...)
+	binary.BigEndian.PutUint16(res[header.dataSizeOffset:], uint16(dataSize))
+	return res, nil
+}
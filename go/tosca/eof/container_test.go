@@ -0,0 +1,168 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package eof
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+)
+
+func TestHasMagic_DetectsEofPrefix(t *testing.T) {
+	tests := map[string]struct {
+		code []byte
+		want bool
+	}{
+		"empty":     {code: nil, want: false},
+		"short":     {code: []byte{0xEF}, want: false},
+		"legacy":    {code: []byte{byte(vm.PUSH1), 0}, want: false},
+		"eof":       {code: []byte{0xEF, 0x00}, want: true},
+		"eof-like":  {code: []byte{0xEF, 0x01}, want: false},
+		"container": {code: newTestContainer(byte(vm.STOP)).Bytes(), want: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := HasMagic(test.code); test.want != got {
+				t.Errorf("unexpected result, want %t, got %t", test.want, got)
+			}
+		})
+	}
+}
+
+func TestParse_EncodedContainersAreRestored(t *testing.T) {
+	tests := map[string]*Container{
+		"minimal": newTestContainer(byte(vm.STOP)),
+		"multiple sections": {
+			Types: []FunctionType{
+				{Inputs: 0, Outputs: NonReturning, MaxStackIncrease: 0},
+				{Inputs: 1, Outputs: 2, MaxStackIncrease: 3},
+			},
+			Code:     [][]byte{{byte(vm.CALLF), 0, 1, byte(vm.STOP)}, {byte(vm.DUP1), byte(vm.RETF)}},
+			Data:     []byte{1, 2, 3},
+			DataSize: 3,
+		},
+		"sub-containers": {
+			Types:      []FunctionType{{Outputs: NonReturning}},
+			Code:       [][]byte{{byte(vm.STOP)}},
+			Containers: [][]byte{{1, 2}, {3}},
+			Data:       []byte{},
+		},
+		"truncated data": {
+			Types:    []FunctionType{{Outputs: NonReturning}},
+			Code:     [][]byte{{byte(vm.STOP)}},
+			Data:     []byte{1},
+			DataSize: 10,
+		},
+	}
+
+	for name, container := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Parse(container.Bytes())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(container, got) {
+				t.Errorf("unexpected container, want %v, got %v", container, got)
+			}
+		})
+	}
+}
+
+func TestParse_InvalidContainersAreRejected(t *testing.T) {
+	valid := newTestContainer(byte(vm.STOP)).Bytes()
+	modify := func(f func([]byte) []byte) []byte {
+		return f(bytes.Clone(valid))
+	}
+
+	tests := map[string]struct {
+		code []byte
+		want error
+	}{
+		"no magic":             {code: []byte{byte(vm.STOP)}, want: ErrInvalidHeader},
+		"no version":           {code: []byte{0xEF, 0x00}, want: ErrInvalidHeader},
+		"wrong version":        {code: modify(func(c []byte) []byte { c[2] = 2; return c }), want: ErrInvalidHeader},
+		"missing types kind":   {code: modify(func(c []byte) []byte { c[3] = kindCode; return c }), want: ErrInvalidHeader},
+		"truncated header":     {code: valid[:8], want: ErrInvalidHeader},
+		"types size mismatch":  {code: modify(func(c []byte) []byte { c[5] = 8; return c }), want: ErrInvalidHeader},
+		"no code sections":     {code: modify(func(c []byte) []byte { c[8] = 0; return c }), want: ErrInvalidHeader},
+		"empty code section":   {code: modify(func(c []byte) []byte { c[10] = 0; return c }), want: ErrInvalidHeader},
+		"missing terminator":   {code: modify(func(c []byte) []byte { c[14] = 1; return c }), want: ErrInvalidHeader},
+		"truncated body":       {code: valid[:len(valid)-1], want: ErrInvalidBody},
+		"trailing bytes":       {code: append(bytes.Clone(valid), 0), want: ErrInvalidBody},
+		"first section inputs": {code: modify(func(c []byte) []byte { c[15] = 1; return c }), want: ErrInvalidBody},
+		"first section returns": {
+			code: modify(func(c []byte) []byte { c[16] = 0; return c }),
+			want: ErrInvalidBody,
+		},
+		"excessive stack height": {
+			code: modify(func(c []byte) []byte { c[17], c[18] = 0x04, 0x00; return c }),
+			want: ErrInvalidBody,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(test.code)
+			if !errors.Is(err, test.want) {
+				t.Errorf("unexpected error, want %v, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestAppendAuxData_ExtendsDataSection(t *testing.T) {
+	container := newTestContainer(byte(vm.STOP))
+	container.Data = []byte{1, 2}
+	container.DataSize = 4
+
+	got, err := AppendAuxData(container.Bytes(), []byte{3, 4, 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := newTestContainer(byte(vm.STOP))
+	want.Data = []byte{1, 2, 3, 4, 5}
+	want.DataSize = 5
+	if !bytes.Equal(want.Bytes(), got) {
+		t.Errorf("unexpected container, want %x, got %x", want.Bytes(), got)
+	}
+}
+
+func TestAppendAuxData_RejectsTruncatedResults(t *testing.T) {
+	container := newTestContainer(byte(vm.STOP))
+	container.DataSize = 4
+
+	_, err := AppendAuxData(container.Bytes(), []byte{1, 2, 3})
+	if !errors.Is(err, ErrInvalidContainer) {
+		t.Errorf("unexpected error, want %v, got %v", ErrInvalidContainer, err)
+	}
+}
+
+func TestAppendAuxData_RejectsOversizedDataSections(t *testing.T) {
+	container := newTestContainer(byte(vm.STOP))
+
+	_, err := AppendAuxData(container.Bytes(), make([]byte, 1<<16))
+	if !errors.Is(err, ErrInvalidContainer) {
+		t.Errorf("unexpected error, want %v, got %v", ErrInvalidContainer, err)
+	}
+}
+
+// newTestContainer creates a container with a single code section consisting
+// of the given code and a max stack increase of 0.
+func newTestContainer(code ...byte) *Container {
+	return &Container{
+		Types: []FunctionType{{Inputs: 0, Outputs: NonReturning}},
+		Code:  [][]byte{code},
+		Data:  []byte{},
+	}
+}
@@ -0,0 +1,446 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package eof
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+)
+
+// ContainerKind distinguishes the contexts a container may be used in.
+type ContainerKind int
+
+const (
+	// RuntimeContainer is the kind of containers deployed as contract code.
+	// Runtime containers must not use RETURNCONTRACT.
+	RuntimeContainer ContainerKind = iota
+	// InitcodeContainer is the kind of containers executed to create a
+	// contract. Initcode containers end with RETURNCONTRACT or REVERT and
+	// must not use RETURN or STOP.
+	InitcodeContainer
+)
+
+func (k ContainerKind) String() string {
+	switch k {
+	case RuntimeContainer:
+		return "runtime"
+	case InitcodeContainer:
+		return "initcode"
+	default:
+		return fmt.Sprintf("ContainerKind(%d)", int(k))
+	}
+}
+
+// stackLimit is the maximum number of elements on the EVM stack.
+const stackLimit = 1024
+
+// Validate checks that the container is valid for the given kind of usage.
+// This includes the validation of all code sections (EIP-3670, EIP-4200,
+// EIP-4750, EIP-5450) and, recursively, of all sub-containers (EIP-7620).
+// Since the container is considered to be a top-level container, its data
+// section must not be truncated.
+func (c *Container) Validate(kind ContainerKind) error {
+	if len(c.Data) < int(c.DataSize) {
+		return fmt.Errorf("%w: truncated data section", ErrInvalidContainer)
+	}
+	return c.validate(kind)
+}
+
+// ParseAndValidate parses the given code and validates it as a top-level
+// container of the given kind.
+func ParseAndValidate(code []byte, kind ContainerKind) (*Container, error) {
+	container, err := Parse(code)
+	if err != nil {
+		return nil, err
+	}
+	if err := container.Validate(kind); err != nil {
+		return nil, err
+	}
+	return container, nil
+}
+
+func (c *Container) validate(kind ContainerKind) error {
+	// Validate all code sections and collect their references.
+	infos := make([]sectionInfo, len(c.Code))
+	for i := range c.Code {
+		info, err := c.validateCode(i)
+		if err != nil {
+			return err
+		}
+		infos[i] = info
+	}
+
+	containerKinds := make([]*ContainerKind, len(c.Containers))
+	for i, info := range infos {
+		// A section is returning iff it contains a RETF or a JUMPF to a
+		// returning section.
+		returning := info.hasRetf
+		for _, target := range info.jumpfTargets {
+			returning = returning || c.Types[target].IsReturning()
+		}
+		if returning != c.Types[i].IsReturning() {
+			return fmt.Errorf("%w: returning property of section %d does not match its type", ErrInvalidCode, i)
+		}
+
+		if kind == RuntimeContainer && len(info.returnContractTargets) > 0 {
+			return fmt.Errorf("%w: RETURNCONTRACT in runtime container", ErrInvalidContainer)
+		}
+		if kind == InitcodeContainer && info.hasStopOrReturn {
+			return fmt.Errorf("%w: STOP or RETURN in initcode container", ErrInvalidContainer)
+		}
+
+		register := func(targets []int, targetKind ContainerKind) error {
+			for _, target := range targets {
+				if existing := containerKinds[target]; existing != nil && *existing != targetKind {
+					return fmt.Errorf("%w: container %d used as %v and %v", ErrInvalidContainer, target, *existing, targetKind)
+				}
+				containerKinds[target] = &targetKind
+			}
+			return nil
+		}
+		if err := register(info.eofCreateTargets, InitcodeContainer); err != nil {
+			return err
+		}
+		if err := register(info.returnContractTargets, RuntimeContainer); err != nil {
+			return err
+		}
+	}
+
+	// All code sections have to be reachable from the first section.
+	reachable := make([]bool, len(c.Code))
+	reachable[0] = true
+	worklist := []int{0}
+	for len(worklist) > 0 {
+		cur := worklist[len(worklist)-1]
+		worklist = worklist[:len(worklist)-1]
+		for _, target := range infos[cur].callTargets {
+			if !reachable[target] {
+				reachable[target] = true
+				worklist = append(worklist, target)
+			}
+		}
+	}
+	for i, r := range reachable {
+		if !r {
+			return fmt.Errorf("%w: unreachable code section %d", ErrInvalidContainer, i)
+		}
+	}
+
+	// All sub-containers have to be referenced and valid for their usage.
+	for i, kind := range containerKinds {
+		if kind == nil {
+			return fmt.Errorf("%w: unreferenced sub-container %d", ErrInvalidContainer, i)
+		}
+		sub, err := Parse(c.Containers[i])
+		if err != nil {
+			return fmt.Errorf("sub-container %d: %w", i, err)
+		}
+		// Only containers deployed by RETURNCONTRACT may have truncated data.
+		if *kind == InitcodeContainer && len(sub.Data) < int(sub.DataSize) {
+			return fmt.Errorf("%w: truncated data section of initcode sub-container %d", ErrInvalidContainer, i)
+		}
+		if err := sub.validate(*kind); err != nil {
+			return fmt.Errorf("sub-container %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// sectionInfo summarizes the references of a validated code section.
+type sectionInfo struct {
+	callTargets           []int // < sections targeted by CALLF and JUMPF
+	jumpfTargets          []int // < sections targeted by JUMPF
+	eofCreateTargets      []int // < containers targeted by EOFCREATE
+	returnContractTargets []int // < containers targeted by RETURNCONTRACT
+	hasRetf               bool
+	hasStopOrReturn       bool
+}
+
+// GetImmediateSize returns the number of immediate bytes following the given
+// instruction in EOF code. For RJUMPV, the size depends on the first
+// immediate byte, which needs to be provided by the caller.
+func GetImmediateSize(op vm.OpCode, maxIndex byte) int {
+	switch {
+	case vm.PUSH1 <= op && op <= vm.PUSH32:
+		return int(op-vm.PUSH1) + 1
+	}
+	switch op {
+	case vm.RJUMP, vm.RJUMPI, vm.CALLF, vm.JUMPF, vm.DATALOADN:
+		return 2
+	case vm.RJUMPV:
+		return 1 + 2*(int(maxIndex)+1)
+	case vm.EOFCREATE, vm.RETURNCONTRACT:
+		return 1
+	}
+	return 0
+}
+
+// GetRelativeJumpTargets returns the offsets of the targets of the relative
+// jump at the given position of the code relative to the position of the
+// succeeding instruction. The code is assumed to contain the complete
+// instruction.
+func GetRelativeJumpTargets(code []byte, pos int) []int {
+	switch vm.OpCode(code[pos]) {
+	case vm.RJUMP, vm.RJUMPI:
+		return []int{int(int16(binary.BigEndian.Uint16(code[pos+1:])))}
+	case vm.RJUMPV:
+		count := int(code[pos+1]) + 1
+		res := make([]int, count)
+		for i := range res {
+			res[i] = int(int16(binary.BigEndian.Uint16(code[pos+2+2*i:])))
+		}
+		return res
+	}
+	return nil
+}
+
+// validateCode checks the instructions, jump destinations, and the stack usage
+// of the given code section.
+func (c *Container) validateCode(section int) (sectionInfo, error) {
+	code := c.Code[section]
+	info := sectionInfo{}
+	fail := func(format string, args ...any) (sectionInfo, error) {
+		return sectionInfo{}, fmt.Errorf("%w: section %d: %s", ErrInvalidCode, section, fmt.Sprintf(format, args...))
+	}
+
+	isInstruction := make([]bool, len(code))
+	jumpTargets := []int{}
+	for pos := 0; pos < len(code); {
+		op := vm.OpCode(code[pos])
+		if !vm.IsValidInEof(op) {
+			return fail("invalid instruction %v at %d", op, pos)
+		}
+		isInstruction[pos] = true
+
+		maxIndex := byte(0)
+		if op == vm.RJUMPV && pos+1 < len(code) {
+			maxIndex = code[pos+1]
+		}
+		next := pos + 1 + GetImmediateSize(op, maxIndex)
+		if next > len(code) {
+			return fail("truncated immediate of %v at %d", op, pos)
+		}
+
+		switch op {
+		case vm.RJUMP, vm.RJUMPI, vm.RJUMPV:
+			for _, offset := range GetRelativeJumpTargets(code, pos) {
+				jumpTargets = append(jumpTargets, next+offset)
+			}
+		case vm.CALLF, vm.JUMPF:
+			target := int(binary.BigEndian.Uint16(code[pos+1:]))
+			if target >= len(c.Code) {
+				return fail("invalid target section %d of %v at %d", target, op, pos)
+			}
+			if op == vm.CALLF && !c.Types[target].IsReturning() {
+				return fail("CALLF to non-returning section %d at %d", target, pos)
+			}
+			info.callTargets = append(info.callTargets, target)
+			if op == vm.JUMPF {
+				info.jumpfTargets = append(info.jumpfTargets, target)
+			}
+		case vm.RETF:
+			info.hasRetf = true
+		case vm.STOP, vm.RETURN:
+			info.hasStopOrReturn = true
+		case vm.DATALOADN:
+			offset := int(binary.BigEndian.Uint16(code[pos+1:]))
+			if offset+32 > int(c.DataSize) {
+				return fail("DATALOADN offset %d out of data section bounds at %d", offset, pos)
+			}
+		case vm.EOFCREATE, vm.RETURNCONTRACT:
+			target := int(code[pos+1])
+			if target >= len(c.Containers) {
+				return fail("invalid container %d of %v at %d", target, op, pos)
+			}
+			if op == vm.EOFCREATE {
+				info.eofCreateTargets = append(info.eofCreateTargets, target)
+			} else {
+				info.returnContractTargets = append(info.returnContractTargets, target)
+			}
+		}
+		pos = next
+	}
+
+	for _, target := range jumpTargets {
+		if target < 0 || target >= len(code) || !isInstruction[target] {
+			return fail("invalid relative jump target %d", target)
+		}
+	}
+
+	if err := c.validateStack(section); err != nil {
+		return sectionInfo{}, err
+	}
+	return info, nil
+}
+
+// stackBounds is the range of stack heights possible at an instruction.
+type stackBounds struct {
+	min, max int
+	visited  bool
+}
+
+// validateStack verifies that no stack underflow or overflow can occur in the
+// given code section, that all instructions are reachable, and that the
+// declared maximum stack increase is accurate (EIP-5450). The instructions of
+// the section are expected to be valid.
+func (c *Container) validateStack(section int) error {
+	code := c.Code[section]
+	sectionType := c.Types[section]
+	fail := func(format string, args ...any) error {
+		return fmt.Errorf("%w: section %d: %s", ErrInvalidCode, section, fmt.Sprintf(format, args...))
+	}
+
+	heights := make([]stackBounds, len(code))
+	heights[0] = stackBounds{int(sectionType.Inputs), int(sectionType.Inputs), true}
+	maxHeight := int(sectionType.Inputs)
+
+	for pos := 0; pos < len(code); {
+		op := vm.OpCode(code[pos])
+		cur := heights[pos]
+		if !cur.visited {
+			return fail("unreachable instruction %v at %d", op, pos)
+		}
+
+		pops, pushes := GetStackEffect(op)
+		switch op {
+		case vm.CALLF, vm.JUMPF:
+			target := c.Types[binary.BigEndian.Uint16(code[pos+1:])]
+			pops, pushes = int(target.Inputs), int(target.Outputs)
+			if cur.max+int(target.MaxStackIncrease) > stackLimit {
+				return fail("potential stack overflow by %v at %d", op, pos)
+			}
+			if op == vm.JUMPF {
+				if target.IsReturning() {
+					want := int(sectionType.Outputs) + int(target.Inputs) - int(target.Outputs)
+					if cur.min != want || cur.max != want {
+						return fail("invalid stack height for JUMPF at %d", pos)
+					}
+				}
+				pushes = 0
+			}
+		case vm.RETF:
+			want := int(sectionType.Outputs)
+			if cur.min != want || cur.max != want {
+				return fail("invalid stack height for RETF at %d", pos)
+			}
+			pops = want
+		}
+		if cur.min < pops {
+			return fail("potential stack underflow by %v at %d", op, pos)
+		}
+		next := stackBounds{cur.min - pops + pushes, cur.max - pops + pushes, true}
+		maxHeight = max(maxHeight, next.max)
+
+		maxIndex := byte(0)
+		if op == vm.RJUMPV {
+			maxIndex = code[pos+1]
+		}
+		following := pos + 1 + GetImmediateSize(op, maxIndex)
+
+		successors := []int{}
+		if !IsTerminal(op) {
+			if following >= len(code) {
+				return fail("execution may run past the end of the section")
+			}
+			successors = append(successors, following)
+		}
+		for _, offset := range GetRelativeJumpTargets(code, pos) {
+			successors = append(successors, following+offset)
+		}
+
+		for _, successor := range successors {
+			target := &heights[successor]
+			if successor > pos {
+				if !target.visited {
+					*target = next
+				} else {
+					target.min = min(target.min, next.min)
+					target.max = max(target.max, next.max)
+				}
+			} else if !target.visited || target.min != next.min || target.max != next.max {
+				return fail("inconsistent stack height at backward jump target %d", successor)
+			}
+		}
+		pos = following
+	}
+
+	if maxHeight > MaxStackHeight {
+		return fail("max stack height exceeds limit")
+	}
+	if maxHeight-int(sectionType.Inputs) != int(sectionType.MaxStackIncrease) {
+		return fail("declared max stack increase %d does not match actual %d",
+			sectionType.MaxStackIncrease, maxHeight-int(sectionType.Inputs))
+	}
+	return nil
+}
+
+// IsTerminal reports whether the given instruction never continues with its
+// succeeding instruction.
+func IsTerminal(op vm.OpCode) bool {
+	switch op {
+	case vm.STOP, vm.RETURN, vm.REVERT, vm.INVALID, vm.RETF, vm.JUMPF,
+		vm.RJUMP, vm.RETURNCONTRACT:
+		return true
+	}
+	return false
+}
+
+// GetStackEffect returns the number of elements consumed and produced by the
+// given instruction. The effects of CALLF, RETF, and JUMPF depend on the
+// types of the involved sections and are not covered.
+func GetStackEffect(op vm.OpCode) (pops, pushes int) {
+	switch {
+	case vm.PUSH0 <= op && op <= vm.PUSH32:
+		return 0, 1
+	case vm.DUP1 <= op && op <= vm.DUP16:
+		n := int(op-vm.DUP1) + 1
+		return n, n + 1
+	case vm.SWAP1 <= op && op <= vm.SWAP16:
+		n := int(op-vm.SWAP1) + 2
+		return n, n
+	case vm.LOG0 <= op && op <= vm.LOG4:
+		return int(op-vm.LOG0) + 2, 0
+	}
+
+	switch op {
+	case vm.STOP, vm.INVALID, vm.JUMPDEST, vm.RJUMP, vm.CALLF, vm.RETF, vm.JUMPF:
+		return 0, 0
+	case vm.ADDMOD, vm.MULMOD:
+		return 3, 1
+	case vm.ADD, vm.MUL, vm.SUB, vm.DIV, vm.SDIV, vm.MOD, vm.SMOD, vm.EXP,
+		vm.SIGNEXTEND, vm.LT, vm.GT, vm.SLT, vm.SGT, vm.EQ, vm.AND, vm.OR,
+		vm.XOR, vm.BYTE, vm.SHL, vm.SHR, vm.SAR, vm.SHA3:
+		return 2, 1
+	case vm.ISZERO, vm.NOT, vm.CLZ, vm.BALANCE, vm.CALLDATALOAD, vm.BLOCKHASH,
+		vm.BLOBHASH, vm.MLOAD, vm.SLOAD, vm.TLOAD, vm.DATALOAD,
+		vm.RETURNDATALOAD:
+		return 1, 1
+	case vm.ADDRESS, vm.ORIGIN, vm.CALLER, vm.CALLVALUE, vm.CALLDATASIZE,
+		vm.GASPRICE, vm.RETURNDATASIZE, vm.COINBASE, vm.TIMESTAMP, vm.NUMBER,
+		vm.PREVRANDAO, vm.GASLIMIT, vm.CHAINID, vm.SELFBALANCE, vm.BASEFEE,
+		vm.BLOBBASEFEE, vm.MSIZE, vm.DATALOADN, vm.DATASIZE:
+		return 0, 1
+	case vm.POP, vm.RJUMPI, vm.RJUMPV:
+		return 1, 0
+	case vm.MSTORE, vm.MSTORE8, vm.SSTORE, vm.TSTORE, vm.RETURN, vm.REVERT,
+		vm.RETURNCONTRACT:
+		return 2, 0
+	case vm.CALLDATACOPY, vm.RETURNDATACOPY, vm.MCOPY, vm.DATACOPY:
+		return 3, 0
+	case vm.EXTDELEGATECALL, vm.EXTSTATICCALL:
+		return 3, 1
+	case vm.EXTCALL, vm.EOFCREATE:
+		return 4, 1
+	}
+	return 0, 0
+}
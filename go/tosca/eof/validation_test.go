@@ -0,0 +1,279 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package eof
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+)
+
+func TestValidate_ValidContainersAreAccepted(t *testing.T) {
+	tests := map[string]struct {
+		container *Container
+		kind      ContainerKind
+	}{
+		"stop": {
+			container: newTestContainer(byte(vm.STOP)),
+		},
+		"push and return": {
+			container: withMaxStackIncrease(newTestContainer(
+				byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.RETURN),
+			), 2),
+		},
+		"forward jump": {
+			container: withMaxStackIncrease(newTestContainer(
+				byte(vm.PUSH0), byte(vm.RJUMPI), 0, 1, byte(vm.STOP), byte(vm.STOP),
+			), 1),
+		},
+		"loop": {
+			container: withMaxStackIncrease(newTestContainer(
+				byte(vm.PUSH0), byte(vm.RJUMPI), 0xFF, 0xFC, byte(vm.STOP),
+			), 1),
+		},
+		"jump table": {
+			container: withMaxStackIncrease(newTestContainer(
+				byte(vm.PUSH0), byte(vm.RJUMPV), 1, 0, 0, 0, 1, byte(vm.STOP), byte(vm.INVALID),
+			), 1),
+		},
+		"branches with different heights": {
+			container: withMaxStackIncrease(newTestContainer(
+				byte(vm.PUSH0), byte(vm.RJUMPI), 0, 1, byte(vm.PUSH0), byte(vm.STOP),
+			), 1),
+		},
+		"function call": {
+			container: &Container{
+				Types: []FunctionType{
+					{Outputs: NonReturning, MaxStackIncrease: 2},
+					{Inputs: 2, Outputs: 1, MaxStackIncrease: 0},
+				},
+				Code: [][]byte{
+					{byte(vm.PUSH0), byte(vm.PUSH0), byte(vm.CALLF), 0, 1, byte(vm.POP), byte(vm.STOP)},
+					{byte(vm.ADD), byte(vm.RETF)},
+				},
+			},
+		},
+		"jump to function": {
+			container: &Container{
+				Types: []FunctionType{
+					{Outputs: NonReturning},
+					{Outputs: NonReturning},
+				},
+				Code: [][]byte{{byte(vm.JUMPF), 0, 1}, {byte(vm.STOP)}},
+			},
+		},
+		"data load": {
+			container: func() *Container {
+				c := withMaxStackIncrease(newTestContainer(
+					byte(vm.DATALOADN), 0, 0, byte(vm.POP), byte(vm.STOP),
+				), 1)
+				c.Data, c.DataSize = make([]byte, 32), 32
+				return c
+			}(),
+		},
+		"initcode": {
+			container: withSubContainer(withMaxStackIncrease(newTestContainer(
+				byte(vm.PUSH0), byte(vm.PUSH0), byte(vm.RETURNCONTRACT), 0,
+			), 2), newTestContainer(byte(vm.STOP))),
+			kind: InitcodeContainer,
+		},
+		"initcode deploying truncated data": {
+			container: withSubContainer(withMaxStackIncrease(newTestContainer(
+				byte(vm.PUSH0), byte(vm.PUSH0), byte(vm.RETURNCONTRACT), 0,
+			), 2), func() *Container {
+				c := newTestContainer(byte(vm.STOP))
+				c.DataSize = 10
+				return c
+			}()),
+			kind: InitcodeContainer,
+		},
+		"factory": {
+			container: withSubContainer(withMaxStackIncrease(newTestContainer(
+				byte(vm.PUSH0), byte(vm.PUSH0), byte(vm.PUSH0), byte(vm.PUSH0),
+				byte(vm.EOFCREATE), 0, byte(vm.POP), byte(vm.STOP),
+			), 4), withSubContainer(withMaxStackIncrease(newTestContainer(
+				byte(vm.PUSH0), byte(vm.PUSH0), byte(vm.RETURNCONTRACT), 0,
+			), 2), newTestContainer(byte(vm.STOP)))),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			code := test.container.Bytes()
+			if _, err := ParseAndValidate(code, test.kind); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidate_InvalidContainersAreRejected(t *testing.T) {
+	tests := map[string]struct {
+		container *Container
+		kind      ContainerKind
+		want      error
+	}{
+		"legacy instruction": {
+			container: withMaxStackIncrease(newTestContainer(byte(vm.PC), byte(vm.STOP)), 1),
+			want:      ErrInvalidCode,
+		},
+		"undefined instruction": {
+			container: newTestContainer(0x0C),
+			want:      ErrInvalidCode,
+		},
+		"truncated push": {
+			container: withMaxStackIncrease(newTestContainer(byte(vm.PUSH2), 0), 1),
+			want:      ErrInvalidCode,
+		},
+		"jump into immediate": {
+			container: withMaxStackIncrease(newTestContainer(
+				byte(vm.RJUMP), 0, 1, byte(vm.PUSH1), byte(vm.STOP), byte(vm.STOP),
+			), 1),
+			want: ErrInvalidCode,
+		},
+		"jump out of section": {
+			container: newTestContainer(byte(vm.RJUMP), 0, 2),
+			want:      ErrInvalidCode,
+		},
+		"missing terminator": {
+			container: withMaxStackIncrease(newTestContainer(byte(vm.PUSH0)), 1),
+			want:      ErrInvalidCode,
+		},
+		"unreachable code": {
+			container: newTestContainer(byte(vm.STOP), byte(vm.STOP)),
+			want:      ErrInvalidCode,
+		},
+		"stack underflow": {
+			container: newTestContainer(byte(vm.POP), byte(vm.STOP)),
+			want:      ErrInvalidCode,
+		},
+		"wrong max stack increase": {
+			container: withMaxStackIncrease(newTestContainer(byte(vm.PUSH0), byte(vm.STOP)), 2),
+			want:      ErrInvalidCode,
+		},
+		"inconsistent loop": {
+			container: withMaxStackIncrease(newTestContainer(
+				byte(vm.PUSH0), byte(vm.RJUMP), 0xFF, 0xFC,
+			), 1),
+			want: ErrInvalidCode,
+		},
+		"call of non-returning function": {
+			container: &Container{
+				Types: []FunctionType{{Outputs: NonReturning}, {Outputs: NonReturning}},
+				Code:  [][]byte{{byte(vm.CALLF), 0, 1, byte(vm.STOP)}, {byte(vm.STOP)}},
+			},
+			want: ErrInvalidCode,
+		},
+		"call of missing function": {
+			container: newTestContainer(byte(vm.CALLF), 0, 1, byte(vm.STOP)),
+			want:      ErrInvalidCode,
+		},
+		"returning function without return": {
+			container: &Container{
+				Types: []FunctionType{{Outputs: NonReturning}, {Outputs: 0}},
+				Code:  [][]byte{{byte(vm.CALLF), 0, 1, byte(vm.STOP)}, {byte(vm.STOP)}},
+			},
+			want: ErrInvalidCode,
+		},
+		"return with wrong stack height": {
+			container: &Container{
+				Types: []FunctionType{{Outputs: NonReturning}, {Outputs: 0, MaxStackIncrease: 1}},
+				Code:  [][]byte{{byte(vm.CALLF), 0, 1, byte(vm.STOP)}, {byte(vm.PUSH0), byte(vm.RETF)}},
+			},
+			want: ErrInvalidCode,
+		},
+		"unreachable section": {
+			container: &Container{
+				Types: []FunctionType{{Outputs: NonReturning}, {Outputs: NonReturning}},
+				Code:  [][]byte{{byte(vm.STOP)}, {byte(vm.STOP)}},
+			},
+			want: ErrInvalidContainer,
+		},
+		"data load out of bounds": {
+			container: withMaxStackIncrease(newTestContainer(
+				byte(vm.DATALOADN), 0, 1, byte(vm.POP), byte(vm.STOP),
+			), 1),
+			want: ErrInvalidCode,
+		},
+		"truncated data": {
+			container: func() *Container {
+				c := newTestContainer(byte(vm.STOP))
+				c.DataSize = 1
+				return c
+			}(),
+			want: ErrInvalidContainer,
+		},
+		"unreferenced sub-container": {
+			container: withSubContainer(newTestContainer(byte(vm.STOP)), newTestContainer(byte(vm.STOP))),
+			want:      ErrInvalidContainer,
+		},
+		"return contract in runtime": {
+			container: withSubContainer(withMaxStackIncrease(newTestContainer(
+				byte(vm.PUSH0), byte(vm.PUSH0), byte(vm.RETURNCONTRACT), 0,
+			), 2), newTestContainer(byte(vm.STOP))),
+			kind: RuntimeContainer,
+			want: ErrInvalidContainer,
+		},
+		"stop in initcode": {
+			container: newTestContainer(byte(vm.STOP)),
+			kind:      InitcodeContainer,
+			want:      ErrInvalidContainer,
+		},
+		"invalid sub-container": {
+			container: withSubContainer(withMaxStackIncrease(newTestContainer(
+				byte(vm.PUSH0), byte(vm.PUSH0), byte(vm.RETURNCONTRACT), 0,
+			), 2), newTestContainer(byte(vm.POP), byte(vm.STOP))),
+			kind: InitcodeContainer,
+			want: ErrInvalidCode,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseAndValidate(test.container.Bytes(), test.kind)
+			if !errors.Is(err, test.want) {
+				t.Errorf("unexpected error, want %v, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestGetImmediateSize_CoversInstructionsWithImmediates(t *testing.T) {
+	tests := map[vm.OpCode]int{
+		vm.ADD:            0,
+		vm.PUSH1:          1,
+		vm.PUSH32:         32,
+		vm.RJUMP:          2,
+		vm.RJUMPI:         2,
+		vm.RJUMPV:         5,
+		vm.CALLF:          2,
+		vm.JUMPF:          2,
+		vm.DATALOADN:      2,
+		vm.EOFCREATE:      1,
+		vm.RETURNCONTRACT: 1,
+	}
+	for op, want := range tests {
+		if got := GetImmediateSize(op, 1); want != got {
+			t.Errorf("unexpected immediate size of %v, want %d, got %d", op, want, got)
+		}
+	}
+}
+
+func withMaxStackIncrease(c *Container, increase uint16) *Container {
+	c.Types[0].MaxStackIncrease = increase
+	return c
+}
+
+func withSubContainer(c *Container, sub *Container) *Container {
+	c.Containers = append(c.Containers, sub.Bytes())
+	return c
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+)
+
+// GasSchedule describes chain-specific deviations from the gas prices defined
+// by Ethereum. Each override replaces a price starting with a given revision
+// until it is replaced by an override for a later revision. Prices not covered
+// by any override retain their Ethereum values. A nil schedule, as well as an
+// empty one, describes the Ethereum gas prices.
+type GasSchedule struct {
+	// Static lists overrides of the static gas prices of individual op-codes.
+	Static []StaticGasOverride
+	// Dynamic lists overrides of dynamic gas prices.
+	Dynamic []DynamicGasOverride
+}
+
+// StaticGasOverride replaces the static gas price of an op-code.
+type StaticGasOverride struct {
+	OpCode   vm.OpCode
+	Revision Revision // < the first revision the price applies to
+	Price    Gas
+}
+
+// DynamicGasOverride replaces a dynamic gas price.
+type DynamicGasOverride struct {
+	Kind     DynamicGasKind
+	Revision Revision // < the first revision the price applies to
+	Price    Gas
+}
+
+// DynamicGasKind enumerates the dynamic gas prices that may be overridden by
+// a gas schedule. These are the prices of accessing warm and cold accounts and
+// storage slots introduced by EIP-2929 and of listing them in the access list
+// of a transaction introduced by EIP-2930. Since access lists and the
+// distinction between warm and cold accesses only exist since Berlin, none of
+// them is charged in earlier revisions.
+type DynamicGasKind byte
+
+const (
+	// WarmSloadGas is the price of an SLOAD of a warm storage slot.
+	WarmSloadGas DynamicGasKind = iota
+	// ColdSloadGas is the price of an SLOAD of a cold storage slot.
+	ColdSloadGas
+	// WarmAccountAccessGas is the price of accessing a warm account by
+	// BALANCE, EXTCODESIZE, EXTCODECOPY, EXTCODEHASH, and the CALL
+	// operations, including the access of the delegate of an account with a
+	// delegation designator (EIP-7702).
+	WarmAccountAccessGas
+	// ColdAccountAccessGas is the price of accessing a cold account by the
+	// operations listed for WarmAccountAccessGas and by SELFDESTRUCT, which
+	// does not charge for accessing warm accounts.
+	ColdAccountAccessGas
+	// WarmSstoreGas is the price of an SSTORE of a warm storage slot neither
+	// adding, modifying, nor deleting the original value of the slot, e.g.
+	// since the slot has already been written by the transaction.
+	WarmSstoreGas
+	// ColdSstoreSurchargeGas is charged in addition to the price of an
+	// SSTORE if the storage slot is cold.
+	ColdSstoreSurchargeGas
+	// AccessListAddressGas is the price of each account listed in the access
+	// list of a transaction.
+	AccessListAddressGas
+	// AccessListStorageKeyGas is the price of each storage key listed in the
+	// access list of a transaction.
+	AccessListStorageKeyGas
+	numDynamicGasKinds
+)
+
+// GetAllDynamicGasKinds returns all dynamic gas kinds.
+func GetAllDynamicGasKinds() []DynamicGasKind {
+	res := make([]DynamicGasKind, 0, numDynamicGasKinds)
+	for kind := range numDynamicGasKinds {
+		res = append(res, kind)
+	}
+	return res
+}
+
+func (k DynamicGasKind) String() string {
+	switch k {
+	case WarmSloadGas:
+		return "WarmSloadGas"
+	case ColdSloadGas:
+		return "ColdSloadGas"
+	case WarmAccountAccessGas:
+		return "WarmAccountAccessGas"
+	case ColdAccountAccessGas:
+		return "ColdAccountAccessGas"
+	case WarmSstoreGas:
+		return "WarmSstoreGas"
+	case ColdSstoreSurchargeGas:
+		return "ColdSstoreSurchargeGas"
+	case AccessListAddressGas:
+		return "AccessListAddressGas"
+	case AccessListStorageKeyGas:
+		return "AccessListStorageKeyGas"
+	default:
+		return fmt.Sprintf("DynamicGasKind(%d)", k)
+	}
+}
+
+// EthereumPrice returns the price of the dynamic gas kind defined by Ethereum.
+func (k DynamicGasKind) EthereumPrice() Gas {
+	if k >= numDynamicGasKinds {
+		return 0
+	}
+	return defaultDynamicGasPrices[k]
+}
+
+// defaultDynamicGasPrices are the Ethereum prices of the dynamic gas kinds.
+var defaultDynamicGasPrices = [numDynamicGasKinds]Gas{
+	WarmSloadGas:            100,
+	ColdSloadGas:            2100,
+	WarmAccountAccessGas:    100,
+	ColdAccountAccessGas:    2600,
+	WarmSstoreGas:           100,
+	ColdSstoreSurchargeGas:  2100,
+	AccessListAddressGas:    2400,
+	AccessListStorageKeyGas: 1900,
+}
+
+// GetStaticGas returns the static gas price of the given op-code in the given
+// revision if it is overridden by the schedule. If not, false is returned and
+// the Ethereum price applies.
+func (s *GasSchedule) GetStaticGas(op vm.OpCode, revision Revision) (Gas, bool) {
+	if s == nil {
+		return 0, false
+	}
+	price, found := Gas(0), false
+	latest := Revision(-1)
+	for _, override := range s.Static {
+		if override.OpCode == op && override.Revision <= revision && override.Revision > latest {
+			price, found, latest = override.Price, true, override.Revision
+		}
+	}
+	return price, found
+}
+
+// GetDynamicGas returns the price of the given dynamic gas kind in the given
+// revision, which is either overridden by the schedule or the Ethereum price.
+func (s *GasSchedule) GetDynamicGas(kind DynamicGasKind, revision Revision) Gas {
+	price := kind.EthereumPrice()
+	if s == nil {
+		return price
+	}
+	latest := Revision(-1)
+	for _, override := range s.Dynamic {
+		if override.Kind == kind && override.Revision <= revision && override.Revision > latest {
+			price, latest = override.Price, override.Revision
+		}
+	}
+	return price
+}
+
+// Validate checks that all overrides of the schedule refer to valid op-codes,
+// dynamic gas kinds, and revisions, that prices are not negative, and that no
+// price is overridden twice for the same revision.
+func (s *GasSchedule) Validate() error {
+	if s == nil {
+		return nil
+	}
+	var errs []error
+	checkCommon := func(name string, revision Revision, price Gas) {
+		if revision < R07_Istanbul || int(revision) >= numRevisions {
+			errs = append(errs, fmt.Errorf("invalid revision for %s: %v", name, revision))
+		}
+		if price < 0 {
+			errs = append(errs, fmt.Errorf("negative price for %s: %d", name, price))
+		}
+	}
+
+	type staticKey struct {
+		op       vm.OpCode
+		revision Revision
+	}
+	static := map[staticKey]struct{}{}
+	for _, override := range s.Static {
+		name := override.OpCode.String()
+		if !vm.IsValid(override.OpCode) {
+			errs = append(errs, fmt.Errorf("invalid op-code: %v", override.OpCode))
+		}
+		checkCommon(name, override.Revision, override.Price)
+		key := staticKey{override.OpCode, override.Revision}
+		if _, found := static[key]; found {
+			errs = append(errs, fmt.Errorf("duplicate price for %s in %v", name, override.Revision))
+		}
+		static[key] = struct{}{}
+	}
+
+	type dynamicKey struct {
+		kind     DynamicGasKind
+		revision Revision
+	}
+	dynamic := map[dynamicKey]struct{}{}
+	for _, override := range s.Dynamic {
+		name := override.Kind.String()
+		if override.Kind >= numDynamicGasKinds {
+			errs = append(errs, fmt.Errorf("invalid dynamic gas kind: %v", override.Kind))
+		}
+		checkCommon(name, override.Revision, override.Price)
+		key := dynamicKey{override.Kind, override.Revision}
+		if _, found := dynamic[key]; found {
+			errs = append(errs, fmt.Errorf("duplicate price for %s in %v", name, override.Revision))
+		}
+		dynamic[key] = struct{}{}
+	}
+	return errors.Join(errs...)
+}
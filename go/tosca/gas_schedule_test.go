@@ -0,0 +1,180 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/0xsoniclabs/tosca/go/tosca/vm"
+)
+
+func TestGasSchedule_GetStaticGas_ReturnsLatestApplicableOverride(t *testing.T) {
+	schedule := &GasSchedule{
+		Static: []StaticGasOverride{
+			{OpCode: vm.ADD, Revision: R13_Cancun, Price: 7},
+			{OpCode: vm.ADD, Revision: R09_Berlin, Price: 5},
+			{OpCode: vm.MUL, Revision: R07_Istanbul, Price: 1},
+		},
+	}
+
+	tests := map[string]struct {
+		schedule *GasSchedule
+		op       vm.OpCode
+		revision Revision
+		price    Gas
+		found    bool
+	}{
+		"nil schedule":          {schedule: nil, op: vm.ADD, revision: R13_Cancun},
+		"not overridden":        {schedule: schedule, op: vm.SUB, revision: R13_Cancun},
+		"before first override": {schedule: schedule, op: vm.ADD, revision: R07_Istanbul},
+		"first override":        {schedule: schedule, op: vm.ADD, revision: R10_London, price: 5, found: true},
+		"latest override":       {schedule: schedule, op: vm.ADD, revision: R14_Prague, price: 7, found: true},
+		"other op-code":         {schedule: schedule, op: vm.MUL, revision: R15_Osaka, price: 1, found: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			price, found := test.schedule.GetStaticGas(test.op, test.revision)
+			if test.price != price || test.found != found {
+				t.Errorf("unexpected result, want (%d, %t), got (%d, %t)", test.price, test.found, price, found)
+			}
+		})
+	}
+}
+
+func TestGasSchedule_GetDynamicGas_DefaultsToEthereumPrices(t *testing.T) {
+	schedule := &GasSchedule{
+		Dynamic: []DynamicGasOverride{
+			{Kind: ColdSloadGas, Revision: R10_London, Price: 500},
+		},
+	}
+
+	tests := map[string]struct {
+		schedule *GasSchedule
+		kind     DynamicGasKind
+		revision Revision
+		want     Gas
+	}{
+		"nil schedule warm":     {schedule: nil, kind: WarmSloadGas, revision: R13_Cancun, want: 100},
+		"nil schedule cold":     {schedule: nil, kind: ColdSloadGas, revision: R13_Cancun, want: 2100},
+		"not overridden":        {schedule: schedule, kind: WarmSloadGas, revision: R13_Cancun, want: 100},
+		"before first override": {schedule: schedule, kind: ColdSloadGas, revision: R09_Berlin, want: 2100},
+		"overridden":            {schedule: schedule, kind: ColdSloadGas, revision: R13_Cancun, want: 500},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if want, got := test.want, test.schedule.GetDynamicGas(test.kind, test.revision); want != got {
+				t.Errorf("unexpected price, want %d, got %d", want, got)
+			}
+		})
+	}
+}
+
+func TestGasSchedule_DynamicGasKinds_HaveEthereumPricesAndNames(t *testing.T) {
+	want := map[DynamicGasKind]Gas{
+		WarmSloadGas:            100,
+		ColdSloadGas:            2100,
+		WarmAccountAccessGas:    100,
+		ColdAccountAccessGas:    2600,
+		WarmSstoreGas:           100,
+		ColdSstoreSurchargeGas:  2100,
+		AccessListAddressGas:    2400,
+		AccessListStorageKeyGas: 1900,
+	}
+	kinds := GetAllDynamicGasKinds()
+	if len(kinds) != len(want) {
+		t.Fatalf("unexpected number of kinds, want %d, got %d", len(want), len(kinds))
+	}
+	for _, kind := range kinds {
+		if got := kind.EthereumPrice(); got != want[kind] {
+			t.Errorf("unexpected price of %v, want %d, got %d", kind, want[kind], got)
+		}
+		if strings.HasPrefix(kind.String(), "DynamicGasKind") {
+			t.Errorf("missing name of kind %d", kind)
+		}
+	}
+	if got := numDynamicGasKinds.EthereumPrice(); got != 0 {
+		t.Errorf("unexpected price of invalid kind, got %d", got)
+	}
+}
+
+func TestGasSchedule_Validate_AcceptsValidSchedules(t *testing.T) {
+	schedules := map[string]*GasSchedule{
+		"nil":   nil,
+		"empty": {},
+		"overrides": {
+			Static: []StaticGasOverride{
+				{OpCode: vm.SLOAD, Revision: R07_Istanbul, Price: 400},
+				{OpCode: vm.SLOAD, Revision: R09_Berlin, Price: 0},
+			},
+			Dynamic: []DynamicGasOverride{
+				{Kind: WarmSloadGas, Revision: R09_Berlin, Price: 50},
+				{Kind: ColdSloadGas, Revision: R09_Berlin, Price: 800},
+			},
+		},
+	}
+	for name, schedule := range schedules {
+		t.Run(name, func(t *testing.T) {
+			if err := schedule.Validate(); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestGasSchedule_Validate_DetectsInvalidOverrides(t *testing.T) {
+	tests := map[string]struct {
+		schedule GasSchedule
+		issue    string
+	}{
+		"invalid op-code": {
+			schedule: GasSchedule{Static: []StaticGasOverride{{OpCode: vm.OpCode(0x0c)}}},
+			issue:    "invalid op-code",
+		},
+		"unknown revision": {
+			schedule: GasSchedule{Static: []StaticGasOverride{{OpCode: vm.ADD, Revision: Revision(numRevisions)}}},
+			issue:    "invalid revision",
+		},
+		"negative price": {
+			schedule: GasSchedule{Dynamic: []DynamicGasOverride{{Kind: WarmSloadGas, Price: -1}}},
+			issue:    "negative price",
+		},
+		"invalid dynamic gas kind": {
+			schedule: GasSchedule{Dynamic: []DynamicGasOverride{{Kind: numDynamicGasKinds}}},
+			issue:    "invalid dynamic gas kind",
+		},
+		"duplicate static price": {
+			schedule: GasSchedule{Static: []StaticGasOverride{
+				{OpCode: vm.ADD, Revision: R09_Berlin, Price: 1},
+				{OpCode: vm.ADD, Revision: R09_Berlin, Price: 2},
+			}},
+			issue: "duplicate price",
+		},
+		"duplicate dynamic price": {
+			schedule: GasSchedule{Dynamic: []DynamicGasOverride{
+				{Kind: ColdSloadGas, Revision: R09_Berlin, Price: 1},
+				{Kind: ColdSloadGas, Revision: R09_Berlin, Price: 2},
+			}},
+			issue: "duplicate price",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.schedule.Validate()
+			if err == nil || !strings.Contains(err.Error(), test.issue) {
+				t.Errorf("expected error containing %q, got %v", test.issue, err)
+			}
+		})
+	}
+}
@@ -26,7 +26,9 @@ type Interpreter interface {
 	// a code-internal issue). The error is not nil if some problem within the
 	// interpreter caused the execution to fail to correctly process the provided
 	// program. In such a case the result is undefined. During a call with an
-	// unsupported Revision an ErrUnsupportedRevision Error is returned.
+	// unsupported Revision an ErrUnsupportedRevision Error is returned. If the
+	// run is canceled through the Done channel of the parameters, ErrCanceled
+	// is returned.
 	// Interpreters are required to be thread-safe. Thus, multiple runs may be
 	// conducted in parallel.
 	Run(Parameters) (Result, error)
@@ -67,6 +69,25 @@ type TransactionParameters struct {
 	Origin     Address
 	GasPrice   Value
 	BlobHashes []Hash
+
+	// Done is an optional channel signaling the cancellation of the execution
+	// when closed, for instance the Done channel of a context.Context. If nil,
+	// the execution can not be canceled.
+	Done <-chan struct{}
+}
+
+// IsCanceled returns true if the execution has been canceled through the Done
+// channel of the parameters.
+func (p *TransactionParameters) IsCanceled() bool {
+	if p.Done == nil {
+		return false
+	}
+	select {
+	case <-p.Done:
+		return true
+	default:
+		return false
+	}
 }
 
 // RunContext provides an interface to access and manipulate state and transaction
@@ -123,7 +144,8 @@ const (
 
 // Result summarizes the result of a EVM code computation.
 type Result struct {
-	Success   bool // false if the execution ended in a revert, true otherwise
+	Success   bool // false if the execution ended in a revert or failed, true otherwise
+	Reverted  bool // true if the execution ended in a revert, false otherwise
 	Output    Data
 	GasLeft   Gas
 	GasRefund Gas
@@ -158,6 +180,9 @@ const (
 	CallCode
 	Create
 	Create2
+	// EofCreate creates a contract from an initcode container of an EOF
+	// contract, which is only supported by the R98_Experimental revision.
+	EofCreate
 )
 
 type CallParameters struct {
@@ -166,8 +191,9 @@ type CallParameters struct {
 	Value       Value   // < ignored by static calls, considered to be 0
 	Input       Data
 	Gas         Gas
-	Salt        Hash // < only relevant for CREATE2 calls
+	Salt        Hash // < only relevant for CREATE2 and EOFCREATE calls
 	CodeAddress Address
+	InitCode    Code // < only relevant for EOFCREATE calls, for which Input is the call data
 }
 
 type CallResult struct {
@@ -175,7 +201,8 @@ type CallResult struct {
 	GasLeft        Gas
 	GasRefund      Gas
 	CreatedAddress Address // < only meaningful for CREATE and CREATE2
-	Success        bool    // false if the execution ended in a revert, true otherwise
+	Success        bool    // false if the execution ended in a revert or failed, true otherwise
+	Reverted       bool    // true if the execution ended in a revert, false otherwise
 }
 
 // Revision is an enumeration for EVM specification revisions (aka. Hard-Forks).
@@ -194,6 +221,18 @@ const (
 	numRevisions int = iota
 )
 
+// R98_Experimental is a revision enabling features which have not been
+// scheduled for any Ethereum hard-fork, such as the EVM Object Format (EOF).
+// It includes all features of the regular revisions and is ordered after any
+// of them. Since it is not a regular revision, it is not listed by
+// GetAllKnownRevisions and interpreters may not support it.
+const R98_Experimental Revision = 98
+
+// ErrCanceled is returned by interpreters and processors if an execution has
+// been aborted due to a cancellation signal. The results of canceled
+// executions are undefined.
+const ErrCanceled = ConstError("execution canceled")
+
 // ErrUnsupportedRevision is an error for runs with unsupported Revision
 type ErrUnsupportedRevision struct {
 	Revision Revision
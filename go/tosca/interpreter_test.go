@@ -0,0 +1,36 @@
+// Copyright (c) 2025 Sonic Operations Ltd
+//
+// Use of this software is governed by the Business Source License included
+// in the LICENSE file and at soniclabs.com/bsl11.
+//
+// Change Date: 2028-4-16
+//
+// On the date above, in accordance with the Business Source License, use of
+// this software will be governed by the GNU Lesser General Public License v3.
+
+package tosca
+
+import "testing"
+
+func TestTransactionParameters_IsCanceled(t *testing.T) {
+	closed := make(chan struct{})
+	close(closed)
+
+	tests := map[string]struct {
+		done chan struct{}
+		want bool
+	}{
+		"no channel":     {done: nil, want: false},
+		"open channel":   {done: make(chan struct{}), want: false},
+		"closed channel": {done: closed, want: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			params := TransactionParameters{Done: test.done}
+			if want, got := test.want, params.IsCanceled(); want != got {
+				t.Errorf("unexpected result, want %t, got %t", want, got)
+			}
+		})
+	}
+}
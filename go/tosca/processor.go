@@ -36,6 +36,12 @@ type Transaction struct {
 	BlobHashes        []Hash        // the hashes of the blobs for this transaction
 	AccessList        []AccessTuple // the list of accounts and storage slots expected to be accessed
 	AuthorizationList []SetCodeAuthorization
+
+	// Done is an optional channel aborting the execution of the transaction
+	// when closed, for instance the Done channel of a context.Context. If
+	// canceled, processors return ErrCanceled and the state of the
+	// transaction context is undefined.
+	Done <-chan struct{}
 }
 
 // SetCodeAuthorization contains the information required for EIP-7702 set code transactions to
@@ -170,6 +170,8 @@ func (k CallKind) String() string {
 		return "create"
 	case Create2:
 		return "create2"
+	case EofCreate:
+		return "eof_create"
 	default:
 		return "unknown"
 	}
@@ -178,7 +180,7 @@ func (k CallKind) String() string {
 func (k CallKind) MarshalJSON() ([]byte, error) {
 	var res string
 	switch k {
-	case Call, StaticCall, DelegateCall, CallCode, Create, Create2:
+	case Call, StaticCall, DelegateCall, CallCode, Create, Create2, EofCreate:
 		res = k.String()
 	default:
 		return nil, fmt.Errorf("invalid call kind: %v", k)
@@ -204,6 +206,8 @@ func (k *CallKind) UnmarshalJSON(data []byte) error {
 		*k = Create
 	case "create2":
 		*k = Create2
+	case "eof_create":
+		*k = EofCreate
 	default:
 		return fmt.Errorf("unknown call kind: %s", kind)
 	}
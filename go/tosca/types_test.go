@@ -399,6 +399,7 @@ func TestCallKind_JSON_Encoding(t *testing.T) {
 		{CallCode, "\"call_code\""},
 		{Create, "\"create\""},
 		{Create2, "\"create2\""},
+		{EofCreate, "\"eof_create\""},
 	}
 
 	for _, test := range tests {
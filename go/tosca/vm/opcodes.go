@@ -169,6 +169,27 @@ const (
 	SELFDESTRUCT   OpCode = 0xFF
 )
 
+// Instructions of the EVM Object Format (EOF), which are only valid in the code
+// sections of EOF containers, see IsValidInEof.
+const (
+	DATALOAD        OpCode = 0xD0
+	DATALOADN       OpCode = 0xD1
+	DATASIZE        OpCode = 0xD2
+	DATACOPY        OpCode = 0xD3
+	RJUMP           OpCode = 0xE0
+	RJUMPI          OpCode = 0xE1
+	RJUMPV          OpCode = 0xE2
+	CALLF           OpCode = 0xE3
+	RETF            OpCode = 0xE4
+	JUMPF           OpCode = 0xE5
+	EOFCREATE       OpCode = 0xEC
+	RETURNCONTRACT  OpCode = 0xEE
+	RETURNDATALOAD  OpCode = 0xF7
+	EXTCALL         OpCode = 0xF8
+	EXTDELEGATECALL OpCode = 0xF9
+	EXTSTATICCALL   OpCode = 0xFB
+)
+
 func (op OpCode) Width() int {
 	if PUSH1 <= op && op <= PUSH32 {
 		return int(op-PUSH1) + 2
@@ -474,6 +495,30 @@ func (op OpCode) String() string {
 		return "LOG3"
 	case LOG4:
 		return "LOG4"
+	case DATALOAD:
+		return "DATALOAD"
+	case DATALOADN:
+		return "DATALOADN"
+	case DATASIZE:
+		return "DATASIZE"
+	case DATACOPY:
+		return "DATACOPY"
+	case RJUMP:
+		return "RJUMP"
+	case RJUMPI:
+		return "RJUMPI"
+	case RJUMPV:
+		return "RJUMPV"
+	case CALLF:
+		return "CALLF"
+	case RETF:
+		return "RETF"
+	case JUMPF:
+		return "JUMPF"
+	case EOFCREATE:
+		return "EOFCREATE"
+	case RETURNCONTRACT:
+		return "RETURNCONTRACT"
 	case CREATE:
 		return "CREATE"
 	case CALL:
@@ -486,8 +531,16 @@ func (op OpCode) String() string {
 		return "DELEGATECALL"
 	case CREATE2:
 		return "CREATE2"
+	case RETURNDATALOAD:
+		return "RETURNDATALOAD"
+	case EXTCALL:
+		return "EXTCALL"
+	case EXTDELEGATECALL:
+		return "EXTDELEGATECALL"
 	case STATICCALL:
 		return "STATICCALL"
+	case EXTSTATICCALL:
+		return "EXTSTATICCALL"
 	case REVERT:
 		return "REVERT"
 	case INVALID:
@@ -499,8 +552,9 @@ func (op OpCode) String() string {
 	}
 }
 
-// IsValid determines whether the given OpCode is a valid operation
-// for any revision.
+// IsValid determines whether the given OpCode is a valid operation in legacy
+// code for any revision. Operations exclusive to EOF code are not included,
+// see IsValidInEof.
 func IsValid(op OpCode) bool {
 	return _validOpCodes[op]
 }
@@ -661,3 +715,36 @@ func initValidOpCodes() [256]bool {
 	res[SELFDESTRUCT] = true
 	return res
 }
+
+// IsValidInEof determines whether the given OpCode is a valid operation in the
+// code sections of EOF containers (see https://eips.ethereum.org/EIPS/eip-3670).
+// EOF code can only be executed in the tosca.R98_Experimental revision.
+func IsValidInEof(op OpCode) bool {
+	return _validEofOpCodes[op]
+}
+
+var _validEofOpCodes = initValidEofOpCodes()
+
+func initValidEofOpCodes() [256]bool {
+	res := initValidOpCodes()
+	// Operations observing code or gas, or relying on dynamic jumps, are
+	// not supported by EOF code.
+	for _, op := range []OpCode{
+		CALLCODE, SELFDESTRUCT, JUMP, JUMPI, PC, CREATE, CREATE2, CALL,
+		STATICCALL, DELEGATECALL, CODESIZE, CODECOPY, EXTCODESIZE,
+		EXTCODECOPY, EXTCODEHASH, GAS,
+	} {
+		res[op] = false
+	}
+	// The designated invalid instruction is a valid terminating instruction
+	// in EOF code, see https://eips.ethereum.org/EIPS/eip-3670.
+	res[INVALID] = true
+	for _, op := range []OpCode{
+		DATALOAD, DATALOADN, DATASIZE, DATACOPY, RJUMP, RJUMPI, RJUMPV,
+		CALLF, RETF, JUMPF, EOFCREATE, RETURNCONTRACT, RETURNDATALOAD,
+		EXTCALL, EXTDELEGATECALL, EXTSTATICCALL,
+	} {
+		res[op] = true
+	}
+	return res
+}
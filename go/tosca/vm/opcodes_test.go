@@ -16,13 +16,19 @@ import (
 	"testing"
 )
 
+var eofOnlyOpCodes = []OpCode{
+	DATALOAD, DATALOADN, DATASIZE, DATACOPY, RJUMP, RJUMPI, RJUMPV, CALLF, RETF,
+	JUMPF, EOFCREATE, RETURNCONTRACT, RETURNDATALOAD, EXTCALL, EXTDELEGATECALL,
+	EXTSTATICCALL,
+}
+
 func TestOpCode_ValidOpCodes(t *testing.T) {
 	noPrettyPrint := regexp.MustCompile(`^op\(0x[0-9A-F][0-9A-F]\)$`)
 	for i := range 256 {
 		op := OpCode(i)
 
 		want := !noPrettyPrint.MatchString(op.String())
-		if op == INVALID {
+		if op == INVALID || slices.Contains(eofOnlyOpCodes, op) {
 			want = false
 		}
 		got := IsValid(op)
@@ -40,7 +46,7 @@ func TestOpCode_ValidOpCodesNoPush(t *testing.T) {
 		op := OpCode(i)
 
 		shouldBePresent := !noPrettyPrint.MatchString(op.String())
-		if op == INVALID {
+		if op == INVALID || slices.Contains(eofOnlyOpCodes, op) {
 			shouldBePresent = false
 		} else if PUSH0 <= op && op <= PUSH32 {
 			shouldBePresent = false
@@ -92,6 +98,24 @@ func TestOpCode_NumberOfOpCodes(t *testing.T) {
 
 }
 
+func TestOpCode_ValidEofOpCodes(t *testing.T) {
+	invalidInEof := []OpCode{
+		CALLCODE, SELFDESTRUCT, JUMP, JUMPI, PC, CREATE, CREATE2, CALL,
+		STATICCALL, DELEGATECALL, CODESIZE, CODECOPY, EXTCODESIZE,
+		EXTCODECOPY, EXTCODEHASH, GAS,
+	}
+	for i := range 256 {
+		op := OpCode(i)
+		want := IsValid(op) && !slices.Contains(invalidInEof, op)
+		if slices.Contains(eofOnlyOpCodes, op) || op == INVALID {
+			want = true
+		}
+		if got := IsValidInEof(op); want != got {
+			t.Errorf("invalid EOF classification of instruction %v, wanted %t, got %t", op, want, got)
+		}
+	}
+}
+
 func BenchmarkOpcodesIsValid(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		IsValid(OpCode(i % 256))